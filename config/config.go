@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -18,10 +20,19 @@ var (
 	POSTGRES_PASSWORD          string
 	POSTGRES_DB                string
 	POSTGRES_PORT              string
-	JWT_SECRET                 string
+	JWT_SIGNING_KEYS           string
+	JWT_ACTIVE_KEY             string
 	JWT_ACCESS_TOKEN_DURATION  string
 	JWT_REFRESH_TOKEN_DURATION string
 
+	FIELD_ENCRYPTION_KEYS       string
+	FIELD_ENCRYPTION_ACTIVE_KEY string
+
+	// REQUIRE_EMAIL_VERIFICATION stops users who have not verified their email from ordering.
+	REQUIRE_EMAIL_VERIFICATION bool
+	// TWO_FACTOR_ISSUER is the name authenticator apps show accounts under.
+	TWO_FACTOR_ISSUER string
+
 	DB *gorm.DB
 )
 
@@ -36,9 +47,23 @@ func InitialENV() {
 	POSTGRES_PASSWORD = requiredEnv("POSTGRES_PASSWORD")
 	POSTGRES_DB = requiredEnv("POSTGRES_DB")
 	POSTGRES_PORT = requiredEnv("POSTGRES_PORT")
-	JWT_SECRET = requiredEnv("JWT_SECRET")
+	JWT_SIGNING_KEYS = requiredEnv("JWT_SIGNING_KEYS")
+	JWT_ACTIVE_KEY = requiredEnv("JWT_ACTIVE_KEY")
 	JWT_ACCESS_TOKEN_DURATION = requiredEnv("JWT_ACCESS_TOKEN_DURATION")
 	JWT_REFRESH_TOKEN_DURATION = requiredEnv("JWT_REFRESH_TOKEN_DURATION")
+	FIELD_ENCRYPTION_KEYS = requiredEnv("FIELD_ENCRYPTION_KEYS")
+	FIELD_ENCRYPTION_ACTIVE_KEY = requiredEnv("FIELD_ENCRYPTION_ACTIVE_KEY")
+	REQUIRE_EMAIL_VERIFICATION = boolEnv("REQUIRE_EMAIL_VERIFICATION", false)
+	TWO_FACTOR_ISSUER = envOrDefault("TWO_FACTOR_ISSUER", "E-commerce")
+}
+
+// AppURL is the storefront that links in emails open and login providers redirect back
+// to, set by APP_URL. It defaults to http://localhost:3000.
+func AppURL() string {
+	if appURL := os.Getenv("APP_URL"); appURL != "" {
+		return strings.TrimRight(appURL, "/")
+	}
+	return "http://localhost:3000"
 }
 
 func ConnectDatabase() {
@@ -60,3 +85,23 @@ func requiredEnv(key string) string {
 	}
 	return env
 }
+
+func envOrDefault(key, fallback string) string {
+	if env := os.Getenv(key); env != "" {
+		return env
+	}
+	return fallback
+}
+
+func boolEnv(key string, fallback bool) bool {
+	env := os.Getenv(key)
+	if env == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(env)
+	if err != nil {
+		log.Printf("Warning: Invalid %s '%s'. Using default %t.", key, env, fallback)
+		return fallback
+	}
+	return value
+}
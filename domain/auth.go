@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"ecommerce-go-api/entity"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,9 +11,62 @@ import (
 type AuthUsecase interface {
 	Register(ctx context.Context, req *entity.RegisterRequest) (*entity.RegisterResponse, error)
 	RegisterShop(ctx context.Context, req *entity.RegisterShopRequest) (*entity.RegisterShopResponse, error)
-	Login(ctx context.Context, req *entity.LoginRequest) (*entity.AuthResponse, error)
-	RefreshToken(ctx context.Context, req *entity.RefreshTokenRequest) (*entity.AuthResponse, error)
+	Login(ctx context.Context, req *entity.LoginRequest, client entity.ClientInfo) (*entity.AuthResponse, error)
+	RefreshToken(ctx context.Context, req *entity.RefreshTokenRequest, client entity.ClientInfo) (*entity.AuthResponse, error)
+	Logout(ctx context.Context, req *entity.LogoutRequest) error
+	LogoutAll(ctx context.Context, userID uuid.UUID) (*entity.LogoutAllResponse, error)
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*entity.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error
 	VerifyToken(ctx context.Context, token string) (*entity.User, error)
+	VerifyEmail(ctx context.Context, req *entity.VerifyEmailRequest) error
+	ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error
+	ForgotPassword(ctx context.Context, req *entity.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *entity.ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, req *entity.ChangePasswordRequest, client entity.ClientInfo) (*entity.AuthResponse, error)
+	ListFailedLogins(ctx context.Context, req entity.FailedLoginListRequest) (*entity.FailedLoginListResponse, error)
+	UnlockLogin(ctx context.Context, req *entity.UnlockLoginRequest) error
+	VerifyTwoFactorLogin(ctx context.Context, req *entity.TwoFactorLoginRequest, client entity.ClientInfo) (*entity.AuthResponse, error)
+	GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorStatusResponse, error)
+	SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.TwoFactorRecoveryCodesResponse, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, req *entity.DisableTwoFactorRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.TwoFactorRecoveryCodesResponse, error)
+	// ReverifyTwoFactor checks the code and returns a new access token of the session
+	// that allows sensitive actions.
+	ReverifyTwoFactor(ctx context.Context, userID, sessionID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.AuthResponse, error)
+	SetTwoFactorRequired(ctx context.Context, userID uuid.UUID, required bool) error
+	ResetTwoFactor(ctx context.Context, userID uuid.UUID) error
+	ListOAuthProviders(ctx context.Context) *entity.OAuthProvidersResponse
+	StartOAuthLogin(ctx context.Context, provider string) (*entity.OAuthAuthorizeResponse, error)
+	CompleteOAuthLogin(ctx context.Context, provider string, req *entity.OAuthCallbackRequest, client entity.ClientInfo) (*entity.AuthResponse, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error)
+	UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error
+}
+
+// OAuthProvider signs users in with an external OAuth2/OIDC identity provider using the
+// authorization code flow with PKCE.
+type OAuthProvider interface {
+	// AuthCodeURL is the provider page the user signs in at. It redirects back with a
+	// code and the state.
+	AuthCodeURL(state, nonce, codeChallenge string) string
+	// Exchange trades the code for the user's identity, checking the nonce of ID tokens.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.OAuthIdentity, error)
+}
+
+// LoginLimiter counts failed logins per key, e.g. an account or an IP address, for
+// every instance that shares it.
+type LoginLimiter interface {
+	// Get returns the key's current count, or a zero count once it has expired.
+	Get(ctx context.Context, key string) (entity.LoginThrottle, error)
+	// Reserve counts an attempt as failed before it is checked, in one step, so every
+	// concurrent attempt of the key sees the ones before it. The count expires, and
+	// starts again from zero, once window has passed without another failure.
+	Reserve(ctx context.Context, key string, window time.Duration) (entity.LoginReservation, error)
+	// Release takes back a reserved attempt that succeeded or was not made. The key's
+	// last failure goes back to the one before the attempt, unless another attempt was
+	// reserved since.
+	Release(ctx context.Context, reservation entity.LoginReservation, window time.Duration) error
+	Reset(ctx context.Context, key string) error
 }
 
 type AuthRepository interface {
@@ -22,4 +76,72 @@ type AuthRepository interface {
 	AssignUserRole(ctx context.Context, userRole *entity.UserRole) error
 	RegisterUser(ctx context.Context, user *entity.User) error
 	RegisterShop(ctx context.Context, user *entity.User, shop *entity.Shop) error
+	// CreateUserToken stores the token and deletes the user's unused tokens of the same
+	// purpose, so only the latest link works.
+	CreateUserToken(ctx context.Context, token *entity.UserToken) error
+	// GetLatestUserToken returns nil when the user has no token of the purpose.
+	GetLatestUserToken(ctx context.Context, userID uuid.UUID, purpose string) (*entity.UserToken, error)
+	// VerifyEmail uses up the verification token with the hash and marks the user's email
+	// as verified. It returns errmap.ErrInvalidUserToken for unknown, used or expired tokens.
+	VerifyEmail(ctx context.Context, tokenHash string) (*entity.User, error)
+	// ResetPassword uses up the reset token with the hash and sets the user's password.
+	// It returns errmap.ErrInvalidUserToken for unknown, used or expired tokens.
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (*entity.User, error)
+	// UpdatePassword sets the user's password, deletes their unused reset tokens and
+	// revokes all their sessions.
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	CreateSession(ctx context.Context, session *entity.UserSession, token *entity.RefreshToken) error
+	// GetRefreshToken returns the token with the hash and its session. It returns
+	// errmap.ErrInvalidRefreshToken for unknown tokens.
+	GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// RotateRefreshToken uses up the token and stores the next token of its session. It
+	// returns errmap.ErrRefreshTokenReused if the token was used in the meantime.
+	RotateRefreshToken(ctx context.Context, usedTokenID uuid.UUID, next *entity.RefreshToken, client entity.ClientInfo) error
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*entity.UserSession, error)
+	// RevokeSession returns errmap.ErrSessionNotFound unless the user has an unrevoked
+	// session with the ID.
+	RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, reason string) error
+	RevokeSessions(ctx context.Context, userID uuid.UUID, reason string) (int64, error)
+	CreateFailedLogin(ctx context.Context, attempt *entity.FailedLogin) error
+	ListFailedLogins(ctx context.Context, req entity.FailedLoginListRequest) ([]*entity.FailedLogin, int64, error)
+	// GetUserToken returns the unused, unexpired token with the hash without using it up.
+	// It returns errmap.ErrInvalidUserToken otherwise.
+	GetUserToken(ctx context.Context, purpose, tokenHash string) (*entity.UserToken, error)
+	// UseUserToken uses up the token with the hash. It returns errmap.ErrInvalidUserToken
+	// for unknown, used or expired tokens.
+	UseUserToken(ctx context.Context, purpose, tokenHash string) error
+	// SetTwoFactorSecret stores the encrypted secret of a 2FA setup that is not enabled
+	// yet. It returns errmap.ErrTwoFactorAlreadyEnabled once 2FA is enabled.
+	SetTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	// EnableTwoFactor turns on 2FA with the secret set up before, records step as the
+	// last used code and replaces the recovery codes.
+	EnableTwoFactor(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	// DisableTwoFactor deletes the user's secret and recovery codes.
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID) error
+	// UseTwoFactorStep records step as the last used code. It returns
+	// errmap.ErrInvalidTwoFactorCode if that step or a later one was already used.
+	UseTwoFactorStep(ctx context.Context, userID uuid.UUID, step int64) error
+	// UseRecoveryCode uses up the recovery code with the hash. It returns
+	// errmap.ErrInvalidTwoFactorCode for unknown or used codes.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error)
+	// SetTwoFactorRequired returns errmap.ErrUserNotFound for unknown users.
+	SetTwoFactorRequired(ctx context.Context, userID uuid.UUID, required bool) error
+	// CreateOAuthState stores the state and deletes expired ones.
+	CreateOAuthState(ctx context.Context, state *entity.OAuthState) error
+	// UseOAuthState deletes and returns the unexpired state of the provider with the hash.
+	// It returns errmap.ErrInvalidOAuthState otherwise.
+	UseOAuthState(ctx context.Context, provider, stateHash string) (*entity.OAuthState, error)
+	GetUserIdentity(ctx context.Context, provider, subject string) (*entity.UserIdentity, error)
+	TouchUserIdentity(ctx context.Context, identityID uuid.UUID) error
+	// LinkUserIdentity stores the identity of an existing user and marks their email as
+	// verified, since the provider has verified it. A user whose email was not verified
+	// yet loses their password and two-factor setup and is signed out everywhere.
+	LinkUserIdentity(ctx context.Context, identity *entity.UserIdentity) error
+	// RegisterOAuthUser creates the user with the USER role and links the identity to it.
+	RegisterOAuthUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error
+	ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error)
+	// DeleteUserIdentity returns errmap.ErrIdentityNotFound unless the user has the identity.
+	DeleteUserIdentity(ctx context.Context, userID, identityID uuid.UUID) error
 }
@@ -13,7 +13,7 @@ type CartUsecase interface {
 	GetCart(ctx context.Context, userID uuid.UUID) (*entity.Cart, []*entity.CartItem, *entity.CartSummary, error)
 	UpdateItem(ctx context.Context, userID uuid.UUID, itemID uint32, qty uint32) (*entity.CartItem, error)
 	DeleteItem(ctx context.Context, userID uuid.UUID, itemID uint32) error
-	EstimateShipping(ctx context.Context, userID uuid.UUID, cartItemIDs []uint32) (*entity.CartShippingEstimateResponse, error)
+	EstimateShipping(ctx context.Context, userID uuid.UUID, cartItemIDs []uint32, addressID *uint32) (*entity.CartShippingEstimateResponse, error)
 }
 
 type CartRepository interface {
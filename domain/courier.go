@@ -2,14 +2,49 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"ecommerce-go-api/entity"
 )
 
 type CourierUsecase interface {
 	ListCouriers(ctx context.Context) ([]entity.CourierListResponse, error)
+
+	ListTransitTimes(ctx context.Context, courierID uint32) ([]entity.CourierTransitTimeResponse, error)
+	UpdateTransitTimes(ctx context.Context, courierID uint32, req entity.UpdateCourierTransitTimesRequest) ([]entity.CourierTransitTimeResponse, error)
+	ListHolidays(ctx context.Context, req entity.HolidayListRequest) ([]entity.HolidayResponse, error)
+	CreateHoliday(ctx context.Context, req entity.CreateHolidayRequest) (*entity.HolidayResponse, error)
+	DeleteHoliday(ctx context.Context, date string) error
+}
+
+// CourierProvider integrates a courier's booking and tracking API.
+type CourierProvider interface {
+	BookPickup(ctx context.Context, req entity.CourierBookingRequest) (*entity.CourierBooking, error)
+	GetLabel(ctx context.Context, trackingNo string) (*entity.CourierLabel, error)
+	GetTracking(ctx context.Context, trackingNo string) ([]entity.CourierTrackingEvent, error)
+	Cancel(ctx context.Context, trackingNo string) error
+}
+
+// CourierProviderRegistry looks up a CourierProvider by Courier.ProviderCode.
+type CourierProviderRegistry interface {
+	Get(providerCode string) (CourierProvider, bool)
+}
+
+// DeliveryEstimator works out when a parcel should arrive from the shop's handling time,
+// the courier's transit time between provinces and the holiday calendar.
+type DeliveryEstimator interface {
+	Estimate(ctx context.Context, req entity.DeliveryEstimateRequest) (*entity.DeliveryWindow, error)
 }
 
 type CourierRepository interface {
 	ListAll(ctx context.Context) ([]*entity.Courier, error)
+	GetByID(ctx context.Context, id uint32) (*entity.Courier, error)
+
+	ListTransitTimes(ctx context.Context, courierID uint32) ([]*entity.CourierTransitTime, error)
+	ReplaceTransitTimes(ctx context.Context, courierID uint32, transitTimes []*entity.CourierTransitTime) error
+	FindTransitTime(ctx context.Context, courierID uint32, originProvinceID *uint32, destinationProvinceID *uint32) (*entity.CourierTransitTime, error)
+
+	ListHolidays(ctx context.Context, from time.Time, to time.Time) ([]*entity.Holiday, error)
+	CreateHoliday(ctx context.Context, holiday *entity.Holiday) error
+	DeleteHoliday(ctx context.Context, date time.Time) error
 }
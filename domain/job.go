@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+
+	"ecommerce-go-api/entity"
+
+	"github.com/google/uuid"
+)
+
+type JobUsecase interface {
+	ListJobs(ctx context.Context) ([]entity.JobResponse, error)
+	ListJobRuns(ctx context.Context, req entity.JobRunListRequest) (*entity.JobRunListResponse, error)
+	TriggerJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobRunResponse, error)
+	PauseJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobResponse, error)
+	ResumeJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobResponse, error)
+}
+
+type JobRepository interface {
+	CreateJobRun(ctx context.Context, run *entity.JobRun) error
+	FinishJobRun(ctx context.Context, run *entity.JobRun) error
+	ListJobRuns(ctx context.Context, req entity.JobRunListRequest) ([]*entity.JobRun, int64, error)
+	GetLatestJobRun(ctx context.Context, name string) (*entity.JobRun, error)
+
+	ListJobStates(ctx context.Context) ([]*entity.JobState, error)
+	// IsJobPaused reports the stored pause flag; jobs that were never paused have none.
+	IsJobPaused(ctx context.Context, name string) (bool, error)
+	SetJobPaused(ctx context.Context, name string, paused bool, updatedBy *uuid.UUID) error
+}
+
+// JobScheduler runs the background jobs and lets admins control them.
+type JobScheduler interface {
+	Jobs() []entity.JobInfo
+	Job(name string) (*entity.JobInfo, bool)
+	// Trigger starts a run of the job in the background and returns it once recorded.
+	Trigger(ctx context.Context, name string, triggeredBy *uuid.UUID) (*entity.JobRun, error)
+	// SetPaused updates this instance's cached pause flag. Scheduled runs check the
+	// stored flag, so the caller persists it first.
+	SetPaused(name string, paused bool)
+}
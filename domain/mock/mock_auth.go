@@ -0,0 +1,1175 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/auth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/auth.go -destination=domain/mock/mock_auth.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthUsecase is a mock of AuthUsecase interface.
+type MockAuthUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthUsecaseMockRecorder is the mock recorder for MockAuthUsecase.
+type MockAuthUsecaseMockRecorder struct {
+	mock *MockAuthUsecase
+}
+
+// NewMockAuthUsecase creates a new mock instance.
+func NewMockAuthUsecase(ctrl *gomock.Controller) *MockAuthUsecase {
+	mock := &MockAuthUsecase{ctrl: ctrl}
+	mock.recorder = &MockAuthUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthUsecase) EXPECT() *MockAuthUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockAuthUsecase) ChangePassword(ctx context.Context, userID uuid.UUID, req *entity.ChangePasswordRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, req, client)
+	ret0, _ := ret[0].(*entity.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthUsecaseMockRecorder) ChangePassword(ctx, userID, req, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthUsecase)(nil).ChangePassword), ctx, userID, req, client)
+}
+
+// CompleteOAuthLogin mocks base method.
+func (m *MockAuthUsecase) CompleteOAuthLogin(ctx context.Context, provider string, req *entity.OAuthCallbackRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteOAuthLogin", ctx, provider, req, client)
+	ret0, _ := ret[0].(*entity.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteOAuthLogin indicates an expected call of CompleteOAuthLogin.
+func (mr *MockAuthUsecaseMockRecorder) CompleteOAuthLogin(ctx, provider, req, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteOAuthLogin", reflect.TypeOf((*MockAuthUsecase)(nil).CompleteOAuthLogin), ctx, provider, req, client)
+}
+
+// DisableTwoFactor mocks base method.
+func (m *MockAuthUsecase) DisableTwoFactor(ctx context.Context, userID uuid.UUID, req *entity.DisableTwoFactorRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", ctx, userID, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) DisableTwoFactor(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).DisableTwoFactor), ctx, userID, req)
+}
+
+// EnableTwoFactor mocks base method.
+func (m *MockAuthUsecase) EnableTwoFactor(ctx context.Context, userID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.TwoFactorRecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTwoFactor", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.TwoFactorRecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTwoFactor indicates an expected call of EnableTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) EnableTwoFactor(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).EnableTwoFactor), ctx, userID, req)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthUsecase) ForgotPassword(ctx context.Context, req *entity.ForgotPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthUsecaseMockRecorder) ForgotPassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthUsecase)(nil).ForgotPassword), ctx, req)
+}
+
+// GetTwoFactorStatus mocks base method.
+func (m *MockAuthUsecase) GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoFactorStatus", ctx, userID)
+	ret0, _ := ret[0].(*entity.TwoFactorStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoFactorStatus indicates an expected call of GetTwoFactorStatus.
+func (mr *MockAuthUsecaseMockRecorder) GetTwoFactorStatus(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoFactorStatus", reflect.TypeOf((*MockAuthUsecase)(nil).GetTwoFactorStatus), ctx, userID)
+}
+
+// ListFailedLogins mocks base method.
+func (m *MockAuthUsecase) ListFailedLogins(ctx context.Context, req entity.FailedLoginListRequest) (*entity.FailedLoginListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailedLogins", ctx, req)
+	ret0, _ := ret[0].(*entity.FailedLoginListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFailedLogins indicates an expected call of ListFailedLogins.
+func (mr *MockAuthUsecaseMockRecorder) ListFailedLogins(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedLogins", reflect.TypeOf((*MockAuthUsecase)(nil).ListFailedLogins), ctx, req)
+}
+
+// ListIdentities mocks base method.
+func (m *MockAuthUsecase) ListIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIdentities", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIdentities indicates an expected call of ListIdentities.
+func (mr *MockAuthUsecaseMockRecorder) ListIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIdentities", reflect.TypeOf((*MockAuthUsecase)(nil).ListIdentities), ctx, userID)
+}
+
+// ListOAuthProviders mocks base method.
+func (m *MockAuthUsecase) ListOAuthProviders(ctx context.Context) *entity.OAuthProvidersResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOAuthProviders", ctx)
+	ret0, _ := ret[0].(*entity.OAuthProvidersResponse)
+	return ret0
+}
+
+// ListOAuthProviders indicates an expected call of ListOAuthProviders.
+func (mr *MockAuthUsecaseMockRecorder) ListOAuthProviders(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOAuthProviders", reflect.TypeOf((*MockAuthUsecase)(nil).ListOAuthProviders), ctx)
+}
+
+// ListSessions mocks base method.
+func (m *MockAuthUsecase) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*entity.SessionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSessions", ctx, userID, currentSessionID)
+	ret0, _ := ret[0].([]*entity.SessionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSessions indicates an expected call of ListSessions.
+func (mr *MockAuthUsecaseMockRecorder) ListSessions(ctx, userID, currentSessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSessions", reflect.TypeOf((*MockAuthUsecase)(nil).ListSessions), ctx, userID, currentSessionID)
+}
+
+// Login mocks base method.
+func (m *MockAuthUsecase) Login(ctx context.Context, req *entity.LoginRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, req, client)
+	ret0, _ := ret[0].(*entity.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockAuthUsecaseMockRecorder) Login(ctx, req, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthUsecase)(nil).Login), ctx, req, client)
+}
+
+// Logout mocks base method.
+func (m *MockAuthUsecase) Logout(ctx context.Context, req *entity.LogoutRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthUsecaseMockRecorder) Logout(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthUsecase)(nil).Logout), ctx, req)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthUsecase) LogoutAll(ctx context.Context, userID uuid.UUID) (*entity.LogoutAllResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx, userID)
+	ret0, _ := ret[0].(*entity.LogoutAllResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthUsecaseMockRecorder) LogoutAll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthUsecase)(nil).LogoutAll), ctx, userID)
+}
+
+// RefreshToken mocks base method.
+func (m *MockAuthUsecase) RefreshToken(ctx context.Context, req *entity.RefreshTokenRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, req, client)
+	ret0, _ := ret[0].(*entity.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockAuthUsecaseMockRecorder) RefreshToken(ctx, req, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockAuthUsecase)(nil).RefreshToken), ctx, req, client)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthUsecase) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.TwoFactorRecoveryCodesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.TwoFactorRecoveryCodesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthUsecaseMockRecorder) RegenerateRecoveryCodes(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthUsecase)(nil).RegenerateRecoveryCodes), ctx, userID, req)
+}
+
+// Register mocks base method.
+func (m *MockAuthUsecase) Register(ctx context.Context, req *entity.RegisterRequest) (*entity.RegisterResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, req)
+	ret0, _ := ret[0].(*entity.RegisterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockAuthUsecaseMockRecorder) Register(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockAuthUsecase)(nil).Register), ctx, req)
+}
+
+// RegisterShop mocks base method.
+func (m *MockAuthUsecase) RegisterShop(ctx context.Context, req *entity.RegisterShopRequest) (*entity.RegisterShopResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterShop", ctx, req)
+	ret0, _ := ret[0].(*entity.RegisterShopResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegisterShop indicates an expected call of RegisterShop.
+func (mr *MockAuthUsecaseMockRecorder) RegisterShop(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterShop", reflect.TypeOf((*MockAuthUsecase)(nil).RegisterShop), ctx, req)
+}
+
+// ResendVerificationEmail mocks base method.
+func (m *MockAuthUsecase) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerificationEmail", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerificationEmail indicates an expected call of ResendVerificationEmail.
+func (mr *MockAuthUsecaseMockRecorder) ResendVerificationEmail(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerificationEmail", reflect.TypeOf((*MockAuthUsecase)(nil).ResendVerificationEmail), ctx, userID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthUsecase) ResetPassword(ctx context.Context, req *entity.ResetPasswordRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthUsecaseMockRecorder) ResetPassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthUsecase)(nil).ResetPassword), ctx, req)
+}
+
+// ResetTwoFactor mocks base method.
+func (m *MockAuthUsecase) ResetTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetTwoFactor", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetTwoFactor indicates an expected call of ResetTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) ResetTwoFactor(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).ResetTwoFactor), ctx, userID)
+}
+
+// ReverifyTwoFactor mocks base method.
+func (m *MockAuthUsecase) ReverifyTwoFactor(ctx context.Context, userID, sessionID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverifyTwoFactor", ctx, userID, sessionID, req)
+	ret0, _ := ret[0].(*entity.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverifyTwoFactor indicates an expected call of ReverifyTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) ReverifyTwoFactor(ctx, userID, sessionID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverifyTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).ReverifyTwoFactor), ctx, userID, sessionID, req)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthUsecase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthUsecaseMockRecorder) RevokeSession(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthUsecase)(nil).RevokeSession), ctx, userID, sessionID)
+}
+
+// SetTwoFactorRequired mocks base method.
+func (m *MockAuthUsecase) SetTwoFactorRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTwoFactorRequired", ctx, userID, required)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTwoFactorRequired indicates an expected call of SetTwoFactorRequired.
+func (mr *MockAuthUsecaseMockRecorder) SetTwoFactorRequired(ctx, userID, required any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTwoFactorRequired", reflect.TypeOf((*MockAuthUsecase)(nil).SetTwoFactorRequired), ctx, userID, required)
+}
+
+// SetupTwoFactor mocks base method.
+func (m *MockAuthUsecase) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorSetupResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupTwoFactor", ctx, userID)
+	ret0, _ := ret[0].(*entity.TwoFactorSetupResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupTwoFactor indicates an expected call of SetupTwoFactor.
+func (mr *MockAuthUsecaseMockRecorder) SetupTwoFactor(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupTwoFactor", reflect.TypeOf((*MockAuthUsecase)(nil).SetupTwoFactor), ctx, userID)
+}
+
+// StartOAuthLogin mocks base method.
+func (m *MockAuthUsecase) StartOAuthLogin(ctx context.Context, provider string) (*entity.OAuthAuthorizeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartOAuthLogin", ctx, provider)
+	ret0, _ := ret[0].(*entity.OAuthAuthorizeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartOAuthLogin indicates an expected call of StartOAuthLogin.
+func (mr *MockAuthUsecaseMockRecorder) StartOAuthLogin(ctx, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartOAuthLogin", reflect.TypeOf((*MockAuthUsecase)(nil).StartOAuthLogin), ctx, provider)
+}
+
+// UnlinkIdentity mocks base method.
+func (m *MockAuthUsecase) UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlinkIdentity", ctx, userID, identityID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlinkIdentity indicates an expected call of UnlinkIdentity.
+func (mr *MockAuthUsecaseMockRecorder) UnlinkIdentity(ctx, userID, identityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlinkIdentity", reflect.TypeOf((*MockAuthUsecase)(nil).UnlinkIdentity), ctx, userID, identityID)
+}
+
+// UnlockLogin mocks base method.
+func (m *MockAuthUsecase) UnlockLogin(ctx context.Context, req *entity.UnlockLoginRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockLogin", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockLogin indicates an expected call of UnlockLogin.
+func (mr *MockAuthUsecaseMockRecorder) UnlockLogin(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockLogin", reflect.TypeOf((*MockAuthUsecase)(nil).UnlockLogin), ctx, req)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthUsecase) VerifyEmail(ctx context.Context, req *entity.VerifyEmailRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthUsecaseMockRecorder) VerifyEmail(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthUsecase)(nil).VerifyEmail), ctx, req)
+}
+
+// VerifyToken mocks base method.
+func (m *MockAuthUsecase) VerifyToken(ctx context.Context, token string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyToken", ctx, token)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyToken indicates an expected call of VerifyToken.
+func (mr *MockAuthUsecaseMockRecorder) VerifyToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyToken", reflect.TypeOf((*MockAuthUsecase)(nil).VerifyToken), ctx, token)
+}
+
+// VerifyTwoFactorLogin mocks base method.
+func (m *MockAuthUsecase) VerifyTwoFactorLogin(ctx context.Context, req *entity.TwoFactorLoginRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyTwoFactorLogin", ctx, req, client)
+	ret0, _ := ret[0].(*entity.AuthResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyTwoFactorLogin indicates an expected call of VerifyTwoFactorLogin.
+func (mr *MockAuthUsecaseMockRecorder) VerifyTwoFactorLogin(ctx, req, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyTwoFactorLogin", reflect.TypeOf((*MockAuthUsecase)(nil).VerifyTwoFactorLogin), ctx, req, client)
+}
+
+// MockOAuthProvider is a mock of OAuthProvider interface.
+type MockOAuthProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthProviderMockRecorder is the mock recorder for MockOAuthProvider.
+type MockOAuthProviderMockRecorder struct {
+	mock *MockOAuthProvider
+}
+
+// NewMockOAuthProvider creates a new mock instance.
+func NewMockOAuthProvider(ctrl *gomock.Controller) *MockOAuthProvider {
+	mock := &MockOAuthProvider{ctrl: ctrl}
+	mock.recorder = &MockOAuthProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthProvider) EXPECT() *MockOAuthProviderMockRecorder {
+	return m.recorder
+}
+
+// AuthCodeURL mocks base method.
+func (m *MockOAuthProvider) AuthCodeURL(state, nonce, codeChallenge string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthCodeURL", state, nonce, codeChallenge)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// AuthCodeURL indicates an expected call of AuthCodeURL.
+func (mr *MockOAuthProviderMockRecorder) AuthCodeURL(state, nonce, codeChallenge any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthCodeURL", reflect.TypeOf((*MockOAuthProvider)(nil).AuthCodeURL), state, nonce, codeChallenge)
+}
+
+// Exchange mocks base method.
+func (m *MockOAuthProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.OAuthIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, code, codeVerifier, nonce)
+	ret0, _ := ret[0].(*entity.OAuthIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockOAuthProviderMockRecorder) Exchange(ctx, code, codeVerifier, nonce any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockOAuthProvider)(nil).Exchange), ctx, code, codeVerifier, nonce)
+}
+
+// MockLoginLimiter is a mock of LoginLimiter interface.
+type MockLoginLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginLimiterMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginLimiterMockRecorder is the mock recorder for MockLoginLimiter.
+type MockLoginLimiterMockRecorder struct {
+	mock *MockLoginLimiter
+}
+
+// NewMockLoginLimiter creates a new mock instance.
+func NewMockLoginLimiter(ctrl *gomock.Controller) *MockLoginLimiter {
+	mock := &MockLoginLimiter{ctrl: ctrl}
+	mock.recorder = &MockLoginLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginLimiter) EXPECT() *MockLoginLimiterMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockLoginLimiter) Get(ctx context.Context, key string) (entity.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].(entity.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLoginLimiterMockRecorder) Get(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLoginLimiter)(nil).Get), ctx, key)
+}
+
+// Release mocks base method.
+func (m *MockLoginLimiter) Release(ctx context.Context, reservation entity.LoginReservation, window time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, reservation, window)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLoginLimiterMockRecorder) Release(ctx, reservation, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLoginLimiter)(nil).Release), ctx, reservation, window)
+}
+
+// Reserve mocks base method.
+func (m *MockLoginLimiter) Reserve(ctx context.Context, key string, window time.Duration) (entity.LoginReservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, key, window)
+	ret0, _ := ret[0].(entity.LoginReservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockLoginLimiterMockRecorder) Reserve(ctx, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockLoginLimiter)(nil).Reserve), ctx, key, window)
+}
+
+// Reset mocks base method.
+func (m *MockLoginLimiter) Reset(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginLimiterMockRecorder) Reset(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginLimiter)(nil).Reset), ctx, key)
+}
+
+// MockAuthRepository is a mock of AuthRepository interface.
+type MockAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthRepositoryMockRecorder is the mock recorder for MockAuthRepository.
+type MockAuthRepositoryMockRecorder struct {
+	mock *MockAuthRepository
+}
+
+// NewMockAuthRepository creates a new mock instance.
+func NewMockAuthRepository(ctrl *gomock.Controller) *MockAuthRepository {
+	mock := &MockAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthRepository) EXPECT() *MockAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AssignUserRole mocks base method.
+func (m *MockAuthRepository) AssignUserRole(ctx context.Context, userRole *entity.UserRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignUserRole", ctx, userRole)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignUserRole indicates an expected call of AssignUserRole.
+func (mr *MockAuthRepositoryMockRecorder) AssignUserRole(ctx, userRole any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignUserRole", reflect.TypeOf((*MockAuthRepository)(nil).AssignUserRole), ctx, userRole)
+}
+
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockAuthRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockAuthRepositoryMockRecorder) CountUnusedRecoveryCodes(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockAuthRepository)(nil).CountUnusedRecoveryCodes), ctx, userID)
+}
+
+// CreateFailedLogin mocks base method.
+func (m *MockAuthRepository) CreateFailedLogin(ctx context.Context, attempt *entity.FailedLogin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFailedLogin", ctx, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFailedLogin indicates an expected call of CreateFailedLogin.
+func (mr *MockAuthRepositoryMockRecorder) CreateFailedLogin(ctx, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFailedLogin", reflect.TypeOf((*MockAuthRepository)(nil).CreateFailedLogin), ctx, attempt)
+}
+
+// CreateOAuthState mocks base method.
+func (m *MockAuthRepository) CreateOAuthState(ctx context.Context, state *entity.OAuthState) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOAuthState", ctx, state)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOAuthState indicates an expected call of CreateOAuthState.
+func (mr *MockAuthRepositoryMockRecorder) CreateOAuthState(ctx, state any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOAuthState", reflect.TypeOf((*MockAuthRepository)(nil).CreateOAuthState), ctx, state)
+}
+
+// CreateSession mocks base method.
+func (m *MockAuthRepository) CreateSession(ctx context.Context, session *entity.UserSession, token *entity.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockAuthRepositoryMockRecorder) CreateSession(ctx, session, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockAuthRepository)(nil).CreateSession), ctx, session, token)
+}
+
+// CreateUserToken mocks base method.
+func (m *MockAuthRepository) CreateUserToken(ctx context.Context, token *entity.UserToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUserToken indicates an expected call of CreateUserToken.
+func (mr *MockAuthRepositoryMockRecorder) CreateUserToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockAuthRepository)(nil).CreateUserToken), ctx, token)
+}
+
+// DeleteUserIdentity mocks base method.
+func (m *MockAuthRepository) DeleteUserIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserIdentity", ctx, userID, identityID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserIdentity indicates an expected call of DeleteUserIdentity.
+func (mr *MockAuthRepositoryMockRecorder) DeleteUserIdentity(ctx, userID, identityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserIdentity", reflect.TypeOf((*MockAuthRepository)(nil).DeleteUserIdentity), ctx, userID, identityID)
+}
+
+// DisableTwoFactor mocks base method.
+func (m *MockAuthRepository) DisableTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactor", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactor indicates an expected call of DisableTwoFactor.
+func (mr *MockAuthRepositoryMockRecorder) DisableTwoFactor(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactor", reflect.TypeOf((*MockAuthRepository)(nil).DisableTwoFactor), ctx, userID)
+}
+
+// EnableTwoFactor mocks base method.
+func (m *MockAuthRepository) EnableTwoFactor(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTwoFactor", ctx, userID, step, recoveryCodeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTwoFactor indicates an expected call of EnableTwoFactor.
+func (mr *MockAuthRepositoryMockRecorder) EnableTwoFactor(ctx, userID, step, recoveryCodeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTwoFactor", reflect.TypeOf((*MockAuthRepository)(nil).EnableTwoFactor), ctx, userID, step, recoveryCodeHashes)
+}
+
+// GetLatestUserToken mocks base method.
+func (m *MockAuthRepository) GetLatestUserToken(ctx context.Context, userID uuid.UUID, purpose string) (*entity.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestUserToken", ctx, userID, purpose)
+	ret0, _ := ret[0].(*entity.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestUserToken indicates an expected call of GetLatestUserToken.
+func (mr *MockAuthRepositoryMockRecorder) GetLatestUserToken(ctx, userID, purpose any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestUserToken", reflect.TypeOf((*MockAuthRepository)(nil).GetLatestUserToken), ctx, userID, purpose)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockAuthRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, tokenHash)
+	ret0, _ := ret[0].(*entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockAuthRepositoryMockRecorder) GetRefreshToken(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockAuthRepository)(nil).GetRefreshToken), ctx, tokenHash)
+}
+
+// GetRolesByUserID mocks base method.
+func (m *MockAuthRepository) GetRolesByUserID(ctx context.Context, userID uuid.UUID) ([]*entity.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRolesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*entity.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRolesByUserID indicates an expected call of GetRolesByUserID.
+func (mr *MockAuthRepositoryMockRecorder) GetRolesByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRolesByUserID", reflect.TypeOf((*MockAuthRepository)(nil).GetRolesByUserID), ctx, userID)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockAuthRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", ctx, email)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockAuthRepositoryMockRecorder) GetUserByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockAuthRepository)(nil).GetUserByEmail), ctx, email)
+}
+
+// GetUserByID mocks base method.
+func (m *MockAuthRepository) GetUserByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", ctx, id)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockAuthRepositoryMockRecorder) GetUserByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockAuthRepository)(nil).GetUserByID), ctx, id)
+}
+
+// GetUserIdentity mocks base method.
+func (m *MockAuthRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(*entity.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockAuthRepositoryMockRecorder) GetUserIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockAuthRepository)(nil).GetUserIdentity), ctx, provider, subject)
+}
+
+// GetUserToken mocks base method.
+func (m *MockAuthRepository) GetUserToken(ctx context.Context, purpose, tokenHash string) (*entity.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserToken", ctx, purpose, tokenHash)
+	ret0, _ := ret[0].(*entity.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserToken indicates an expected call of GetUserToken.
+func (mr *MockAuthRepositoryMockRecorder) GetUserToken(ctx, purpose, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserToken", reflect.TypeOf((*MockAuthRepository)(nil).GetUserToken), ctx, purpose, tokenHash)
+}
+
+// LinkUserIdentity mocks base method.
+func (m *MockAuthRepository) LinkUserIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkUserIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LinkUserIdentity indicates an expected call of LinkUserIdentity.
+func (mr *MockAuthRepositoryMockRecorder) LinkUserIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkUserIdentity", reflect.TypeOf((*MockAuthRepository)(nil).LinkUserIdentity), ctx, identity)
+}
+
+// ListActiveSessions mocks base method.
+func (m *MockAuthRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*entity.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveSessions", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveSessions indicates an expected call of ListActiveSessions.
+func (mr *MockAuthRepositoryMockRecorder) ListActiveSessions(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveSessions", reflect.TypeOf((*MockAuthRepository)(nil).ListActiveSessions), ctx, userID)
+}
+
+// ListFailedLogins mocks base method.
+func (m *MockAuthRepository) ListFailedLogins(ctx context.Context, req entity.FailedLoginListRequest) ([]*entity.FailedLogin, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFailedLogins", ctx, req)
+	ret0, _ := ret[0].([]*entity.FailedLogin)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListFailedLogins indicates an expected call of ListFailedLogins.
+func (mr *MockAuthRepositoryMockRecorder) ListFailedLogins(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFailedLogins", reflect.TypeOf((*MockAuthRepository)(nil).ListFailedLogins), ctx, req)
+}
+
+// ListUserIdentities mocks base method.
+func (m *MockAuthRepository) ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserIdentities", ctx, userID)
+	ret0, _ := ret[0].([]*entity.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserIdentities indicates an expected call of ListUserIdentities.
+func (mr *MockAuthRepositoryMockRecorder) ListUserIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserIdentities", reflect.TypeOf((*MockAuthRepository)(nil).ListUserIdentities), ctx, userID)
+}
+
+// RegisterOAuthUser mocks base method.
+func (m *MockAuthRepository) RegisterOAuthUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterOAuthUser", ctx, user, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterOAuthUser indicates an expected call of RegisterOAuthUser.
+func (mr *MockAuthRepositoryMockRecorder) RegisterOAuthUser(ctx, user, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOAuthUser", reflect.TypeOf((*MockAuthRepository)(nil).RegisterOAuthUser), ctx, user, identity)
+}
+
+// RegisterShop mocks base method.
+func (m *MockAuthRepository) RegisterShop(ctx context.Context, user *entity.User, shop *entity.Shop) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterShop", ctx, user, shop)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterShop indicates an expected call of RegisterShop.
+func (mr *MockAuthRepositoryMockRecorder) RegisterShop(ctx, user, shop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterShop", reflect.TypeOf((*MockAuthRepository)(nil).RegisterShop), ctx, user, shop)
+}
+
+// RegisterUser mocks base method.
+func (m *MockAuthRepository) RegisterUser(ctx context.Context, user *entity.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterUser", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterUser indicates an expected call of RegisterUser.
+func (mr *MockAuthRepositoryMockRecorder) RegisterUser(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockAuthRepository)(nil).RegisterUser), ctx, user)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockAuthRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", ctx, userID, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockAuthRepositoryMockRecorder) ReplaceRecoveryCodes(ctx, userID, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockAuthRepository)(nil).ReplaceRecoveryCodes), ctx, userID, codeHashes)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, tokenHash, passwordHash)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthRepositoryMockRecorder) ResetPassword(ctx, tokenHash, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthRepository)(nil).ResetPassword), ctx, tokenHash, passwordHash)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthRepository) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, sessionID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthRepositoryMockRecorder) RevokeSession(ctx, userID, sessionID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthRepository)(nil).RevokeSession), ctx, userID, sessionID, reason)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockAuthRepository) RevokeSessions(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx, userID, reason)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockAuthRepositoryMockRecorder) RevokeSessions(ctx, userID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockAuthRepository)(nil).RevokeSessions), ctx, userID, reason)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockAuthRepository) RotateRefreshToken(ctx context.Context, usedTokenID uuid.UUID, next *entity.RefreshToken, client entity.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, usedTokenID, next, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockAuthRepositoryMockRecorder) RotateRefreshToken(ctx, usedTokenID, next, client any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockAuthRepository)(nil).RotateRefreshToken), ctx, usedTokenID, next, client)
+}
+
+// SetTwoFactorRequired mocks base method.
+func (m *MockAuthRepository) SetTwoFactorRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTwoFactorRequired", ctx, userID, required)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTwoFactorRequired indicates an expected call of SetTwoFactorRequired.
+func (mr *MockAuthRepositoryMockRecorder) SetTwoFactorRequired(ctx, userID, required any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTwoFactorRequired", reflect.TypeOf((*MockAuthRepository)(nil).SetTwoFactorRequired), ctx, userID, required)
+}
+
+// SetTwoFactorSecret mocks base method.
+func (m *MockAuthRepository) SetTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTwoFactorSecret", ctx, userID, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTwoFactorSecret indicates an expected call of SetTwoFactorSecret.
+func (mr *MockAuthRepositoryMockRecorder) SetTwoFactorSecret(ctx, userID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTwoFactorSecret", reflect.TypeOf((*MockAuthRepository)(nil).SetTwoFactorSecret), ctx, userID, secret)
+}
+
+// TouchUserIdentity mocks base method.
+func (m *MockAuthRepository) TouchUserIdentity(ctx context.Context, identityID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchUserIdentity", ctx, identityID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchUserIdentity indicates an expected call of TouchUserIdentity.
+func (mr *MockAuthRepositoryMockRecorder) TouchUserIdentity(ctx, identityID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchUserIdentity", reflect.TypeOf((*MockAuthRepository)(nil).TouchUserIdentity), ctx, identityID)
+}
+
+// UpdatePassword mocks base method.
+func (m *MockAuthRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePassword", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePassword indicates an expected call of UpdatePassword.
+func (mr *MockAuthRepositoryMockRecorder) UpdatePassword(ctx, userID, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePassword", reflect.TypeOf((*MockAuthRepository)(nil).UpdatePassword), ctx, userID, passwordHash)
+}
+
+// UseOAuthState mocks base method.
+func (m *MockAuthRepository) UseOAuthState(ctx context.Context, provider, stateHash string) (*entity.OAuthState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseOAuthState", ctx, provider, stateHash)
+	ret0, _ := ret[0].(*entity.OAuthState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseOAuthState indicates an expected call of UseOAuthState.
+func (mr *MockAuthRepositoryMockRecorder) UseOAuthState(ctx, provider, stateHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOAuthState", reflect.TypeOf((*MockAuthRepository)(nil).UseOAuthState), ctx, provider, stateHash)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockAuthRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockAuthRepositoryMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockAuthRepository)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseTwoFactorStep mocks base method.
+func (m *MockAuthRepository) UseTwoFactorStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTwoFactorStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTwoFactorStep indicates an expected call of UseTwoFactorStep.
+func (mr *MockAuthRepositoryMockRecorder) UseTwoFactorStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTwoFactorStep", reflect.TypeOf((*MockAuthRepository)(nil).UseTwoFactorStep), ctx, userID, step)
+}
+
+// UseUserToken mocks base method.
+func (m *MockAuthRepository) UseUserToken(ctx context.Context, purpose, tokenHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserToken", ctx, purpose, tokenHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseUserToken indicates an expected call of UseUserToken.
+func (mr *MockAuthRepositoryMockRecorder) UseUserToken(ctx, purpose, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserToken", reflect.TypeOf((*MockAuthRepository)(nil).UseUserToken), ctx, purpose, tokenHash)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthRepository) VerifyEmail(ctx context.Context, tokenHash string) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, tokenHash)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthRepositoryMockRecorder) VerifyEmail(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthRepository)(nil).VerifyEmail), ctx, tokenHash)
+}
@@ -73,18 +73,18 @@ func (mr *MockCartUsecaseMockRecorder) DeleteItem(ctx, userID, itemID any) *gomo
 }
 
 // EstimateShipping mocks base method.
-func (m *MockCartUsecase) EstimateShipping(ctx context.Context, userID uuid.UUID, cartItemIDs []uint32) (*entity.CartShippingEstimateResponse, error) {
+func (m *MockCartUsecase) EstimateShipping(ctx context.Context, userID uuid.UUID, cartItemIDs []uint32, addressID *uint32) (*entity.CartShippingEstimateResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "EstimateShipping", ctx, userID, cartItemIDs)
+	ret := m.ctrl.Call(m, "EstimateShipping", ctx, userID, cartItemIDs, addressID)
 	ret0, _ := ret[0].(*entity.CartShippingEstimateResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // EstimateShipping indicates an expected call of EstimateShipping.
-func (mr *MockCartUsecaseMockRecorder) EstimateShipping(ctx, userID, cartItemIDs any) *gomock.Call {
+func (mr *MockCartUsecaseMockRecorder) EstimateShipping(ctx, userID, cartItemIDs, addressID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateShipping", reflect.TypeOf((*MockCartUsecase)(nil).EstimateShipping), ctx, userID, cartItemIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateShipping", reflect.TypeOf((*MockCartUsecase)(nil).EstimateShipping), ctx, userID, cartItemIDs, addressID)
 }
 
 // GetCart mocks base method.
@@ -0,0 +1,435 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/courier.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/courier.go -destination=domain/mock/mock_courier.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "ecommerce-go-api/domain"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCourierUsecase is a mock of CourierUsecase interface.
+type MockCourierUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourierUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockCourierUsecaseMockRecorder is the mock recorder for MockCourierUsecase.
+type MockCourierUsecaseMockRecorder struct {
+	mock *MockCourierUsecase
+}
+
+// NewMockCourierUsecase creates a new mock instance.
+func NewMockCourierUsecase(ctrl *gomock.Controller) *MockCourierUsecase {
+	mock := &MockCourierUsecase{ctrl: ctrl}
+	mock.recorder = &MockCourierUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourierUsecase) EXPECT() *MockCourierUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CreateHoliday mocks base method.
+func (m *MockCourierUsecase) CreateHoliday(ctx context.Context, req entity.CreateHolidayRequest) (*entity.HolidayResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHoliday", ctx, req)
+	ret0, _ := ret[0].(*entity.HolidayResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHoliday indicates an expected call of CreateHoliday.
+func (mr *MockCourierUsecaseMockRecorder) CreateHoliday(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHoliday", reflect.TypeOf((*MockCourierUsecase)(nil).CreateHoliday), ctx, req)
+}
+
+// DeleteHoliday mocks base method.
+func (m *MockCourierUsecase) DeleteHoliday(ctx context.Context, date string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHoliday", ctx, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHoliday indicates an expected call of DeleteHoliday.
+func (mr *MockCourierUsecaseMockRecorder) DeleteHoliday(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHoliday", reflect.TypeOf((*MockCourierUsecase)(nil).DeleteHoliday), ctx, date)
+}
+
+// ListCouriers mocks base method.
+func (m *MockCourierUsecase) ListCouriers(ctx context.Context) ([]entity.CourierListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCouriers", ctx)
+	ret0, _ := ret[0].([]entity.CourierListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCouriers indicates an expected call of ListCouriers.
+func (mr *MockCourierUsecaseMockRecorder) ListCouriers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCouriers", reflect.TypeOf((*MockCourierUsecase)(nil).ListCouriers), ctx)
+}
+
+// ListHolidays mocks base method.
+func (m *MockCourierUsecase) ListHolidays(ctx context.Context, req entity.HolidayListRequest) ([]entity.HolidayResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolidays", ctx, req)
+	ret0, _ := ret[0].([]entity.HolidayResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolidays indicates an expected call of ListHolidays.
+func (mr *MockCourierUsecaseMockRecorder) ListHolidays(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolidays", reflect.TypeOf((*MockCourierUsecase)(nil).ListHolidays), ctx, req)
+}
+
+// ListTransitTimes mocks base method.
+func (m *MockCourierUsecase) ListTransitTimes(ctx context.Context, courierID uint32) ([]entity.CourierTransitTimeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransitTimes", ctx, courierID)
+	ret0, _ := ret[0].([]entity.CourierTransitTimeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransitTimes indicates an expected call of ListTransitTimes.
+func (mr *MockCourierUsecaseMockRecorder) ListTransitTimes(ctx, courierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransitTimes", reflect.TypeOf((*MockCourierUsecase)(nil).ListTransitTimes), ctx, courierID)
+}
+
+// UpdateTransitTimes mocks base method.
+func (m *MockCourierUsecase) UpdateTransitTimes(ctx context.Context, courierID uint32, req entity.UpdateCourierTransitTimesRequest) ([]entity.CourierTransitTimeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransitTimes", ctx, courierID, req)
+	ret0, _ := ret[0].([]entity.CourierTransitTimeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransitTimes indicates an expected call of UpdateTransitTimes.
+func (mr *MockCourierUsecaseMockRecorder) UpdateTransitTimes(ctx, courierID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransitTimes", reflect.TypeOf((*MockCourierUsecase)(nil).UpdateTransitTimes), ctx, courierID, req)
+}
+
+// MockCourierProvider is a mock of CourierProvider interface.
+type MockCourierProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourierProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockCourierProviderMockRecorder is the mock recorder for MockCourierProvider.
+type MockCourierProviderMockRecorder struct {
+	mock *MockCourierProvider
+}
+
+// NewMockCourierProvider creates a new mock instance.
+func NewMockCourierProvider(ctrl *gomock.Controller) *MockCourierProvider {
+	mock := &MockCourierProvider{ctrl: ctrl}
+	mock.recorder = &MockCourierProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourierProvider) EXPECT() *MockCourierProviderMockRecorder {
+	return m.recorder
+}
+
+// BookPickup mocks base method.
+func (m *MockCourierProvider) BookPickup(ctx context.Context, req entity.CourierBookingRequest) (*entity.CourierBooking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookPickup", ctx, req)
+	ret0, _ := ret[0].(*entity.CourierBooking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookPickup indicates an expected call of BookPickup.
+func (mr *MockCourierProviderMockRecorder) BookPickup(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookPickup", reflect.TypeOf((*MockCourierProvider)(nil).BookPickup), ctx, req)
+}
+
+// Cancel mocks base method.
+func (m *MockCourierProvider) Cancel(ctx context.Context, trackingNo string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, trackingNo)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockCourierProviderMockRecorder) Cancel(ctx, trackingNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCourierProvider)(nil).Cancel), ctx, trackingNo)
+}
+
+// GetLabel mocks base method.
+func (m *MockCourierProvider) GetLabel(ctx context.Context, trackingNo string) (*entity.CourierLabel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLabel", ctx, trackingNo)
+	ret0, _ := ret[0].(*entity.CourierLabel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLabel indicates an expected call of GetLabel.
+func (mr *MockCourierProviderMockRecorder) GetLabel(ctx, trackingNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLabel", reflect.TypeOf((*MockCourierProvider)(nil).GetLabel), ctx, trackingNo)
+}
+
+// GetTracking mocks base method.
+func (m *MockCourierProvider) GetTracking(ctx context.Context, trackingNo string) ([]entity.CourierTrackingEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTracking", ctx, trackingNo)
+	ret0, _ := ret[0].([]entity.CourierTrackingEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTracking indicates an expected call of GetTracking.
+func (mr *MockCourierProviderMockRecorder) GetTracking(ctx, trackingNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTracking", reflect.TypeOf((*MockCourierProvider)(nil).GetTracking), ctx, trackingNo)
+}
+
+// MockCourierProviderRegistry is a mock of CourierProviderRegistry interface.
+type MockCourierProviderRegistry struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourierProviderRegistryMockRecorder
+	isgomock struct{}
+}
+
+// MockCourierProviderRegistryMockRecorder is the mock recorder for MockCourierProviderRegistry.
+type MockCourierProviderRegistryMockRecorder struct {
+	mock *MockCourierProviderRegistry
+}
+
+// NewMockCourierProviderRegistry creates a new mock instance.
+func NewMockCourierProviderRegistry(ctrl *gomock.Controller) *MockCourierProviderRegistry {
+	mock := &MockCourierProviderRegistry{ctrl: ctrl}
+	mock.recorder = &MockCourierProviderRegistryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourierProviderRegistry) EXPECT() *MockCourierProviderRegistryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockCourierProviderRegistry) Get(providerCode string) (domain.CourierProvider, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", providerCode)
+	ret0, _ := ret[0].(domain.CourierProvider)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockCourierProviderRegistryMockRecorder) Get(providerCode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCourierProviderRegistry)(nil).Get), providerCode)
+}
+
+// MockDeliveryEstimator is a mock of DeliveryEstimator interface.
+type MockDeliveryEstimator struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryEstimatorMockRecorder
+	isgomock struct{}
+}
+
+// MockDeliveryEstimatorMockRecorder is the mock recorder for MockDeliveryEstimator.
+type MockDeliveryEstimatorMockRecorder struct {
+	mock *MockDeliveryEstimator
+}
+
+// NewMockDeliveryEstimator creates a new mock instance.
+func NewMockDeliveryEstimator(ctrl *gomock.Controller) *MockDeliveryEstimator {
+	mock := &MockDeliveryEstimator{ctrl: ctrl}
+	mock.recorder = &MockDeliveryEstimatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryEstimator) EXPECT() *MockDeliveryEstimatorMockRecorder {
+	return m.recorder
+}
+
+// Estimate mocks base method.
+func (m *MockDeliveryEstimator) Estimate(ctx context.Context, req entity.DeliveryEstimateRequest) (*entity.DeliveryWindow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Estimate", ctx, req)
+	ret0, _ := ret[0].(*entity.DeliveryWindow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Estimate indicates an expected call of Estimate.
+func (mr *MockDeliveryEstimatorMockRecorder) Estimate(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Estimate", reflect.TypeOf((*MockDeliveryEstimator)(nil).Estimate), ctx, req)
+}
+
+// MockCourierRepository is a mock of CourierRepository interface.
+type MockCourierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCourierRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCourierRepositoryMockRecorder is the mock recorder for MockCourierRepository.
+type MockCourierRepositoryMockRecorder struct {
+	mock *MockCourierRepository
+}
+
+// NewMockCourierRepository creates a new mock instance.
+func NewMockCourierRepository(ctrl *gomock.Controller) *MockCourierRepository {
+	mock := &MockCourierRepository{ctrl: ctrl}
+	mock.recorder = &MockCourierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCourierRepository) EXPECT() *MockCourierRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateHoliday mocks base method.
+func (m *MockCourierRepository) CreateHoliday(ctx context.Context, holiday *entity.Holiday) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHoliday", ctx, holiday)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateHoliday indicates an expected call of CreateHoliday.
+func (mr *MockCourierRepositoryMockRecorder) CreateHoliday(ctx, holiday any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHoliday", reflect.TypeOf((*MockCourierRepository)(nil).CreateHoliday), ctx, holiday)
+}
+
+// DeleteHoliday mocks base method.
+func (m *MockCourierRepository) DeleteHoliday(ctx context.Context, date time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHoliday", ctx, date)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHoliday indicates an expected call of DeleteHoliday.
+func (mr *MockCourierRepositoryMockRecorder) DeleteHoliday(ctx, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHoliday", reflect.TypeOf((*MockCourierRepository)(nil).DeleteHoliday), ctx, date)
+}
+
+// FindTransitTime mocks base method.
+func (m *MockCourierRepository) FindTransitTime(ctx context.Context, courierID uint32, originProvinceID, destinationProvinceID *uint32) (*entity.CourierTransitTime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTransitTime", ctx, courierID, originProvinceID, destinationProvinceID)
+	ret0, _ := ret[0].(*entity.CourierTransitTime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTransitTime indicates an expected call of FindTransitTime.
+func (mr *MockCourierRepositoryMockRecorder) FindTransitTime(ctx, courierID, originProvinceID, destinationProvinceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTransitTime", reflect.TypeOf((*MockCourierRepository)(nil).FindTransitTime), ctx, courierID, originProvinceID, destinationProvinceID)
+}
+
+// GetByID mocks base method.
+func (m *MockCourierRepository) GetByID(ctx context.Context, id uint32) (*entity.Courier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Courier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCourierRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCourierRepository)(nil).GetByID), ctx, id)
+}
+
+// ListAll mocks base method.
+func (m *MockCourierRepository) ListAll(ctx context.Context) ([]*entity.Courier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", ctx)
+	ret0, _ := ret[0].([]*entity.Courier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockCourierRepositoryMockRecorder) ListAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockCourierRepository)(nil).ListAll), ctx)
+}
+
+// ListHolidays mocks base method.
+func (m *MockCourierRepository) ListHolidays(ctx context.Context, from, to time.Time) ([]*entity.Holiday, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHolidays", ctx, from, to)
+	ret0, _ := ret[0].([]*entity.Holiday)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHolidays indicates an expected call of ListHolidays.
+func (mr *MockCourierRepositoryMockRecorder) ListHolidays(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHolidays", reflect.TypeOf((*MockCourierRepository)(nil).ListHolidays), ctx, from, to)
+}
+
+// ListTransitTimes mocks base method.
+func (m *MockCourierRepository) ListTransitTimes(ctx context.Context, courierID uint32) ([]*entity.CourierTransitTime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransitTimes", ctx, courierID)
+	ret0, _ := ret[0].([]*entity.CourierTransitTime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransitTimes indicates an expected call of ListTransitTimes.
+func (mr *MockCourierRepositoryMockRecorder) ListTransitTimes(ctx, courierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransitTimes", reflect.TypeOf((*MockCourierRepository)(nil).ListTransitTimes), ctx, courierID)
+}
+
+// ReplaceTransitTimes mocks base method.
+func (m *MockCourierRepository) ReplaceTransitTimes(ctx context.Context, courierID uint32, transitTimes []*entity.CourierTransitTime) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTransitTimes", ctx, courierID, transitTimes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTransitTimes indicates an expected call of ReplaceTransitTimes.
+func (mr *MockCourierRepositoryMockRecorder) ReplaceTransitTimes(ctx, courierID, transitTimes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTransitTimes", reflect.TypeOf((*MockCourierRepository)(nil).ReplaceTransitTimes), ctx, courierID, transitTimes)
+}
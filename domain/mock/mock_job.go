@@ -0,0 +1,325 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/job.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/job.go -destination=domain/mock/mock_job.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobUsecase is a mock of JobUsecase interface.
+type MockJobUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockJobUsecaseMockRecorder is the mock recorder for MockJobUsecase.
+type MockJobUsecaseMockRecorder struct {
+	mock *MockJobUsecase
+}
+
+// NewMockJobUsecase creates a new mock instance.
+func NewMockJobUsecase(ctrl *gomock.Controller) *MockJobUsecase {
+	mock := &MockJobUsecase{ctrl: ctrl}
+	mock.recorder = &MockJobUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobUsecase) EXPECT() *MockJobUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ListJobRuns mocks base method.
+func (m *MockJobUsecase) ListJobRuns(ctx context.Context, req entity.JobRunListRequest) (*entity.JobRunListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobRuns", ctx, req)
+	ret0, _ := ret[0].(*entity.JobRunListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobRuns indicates an expected call of ListJobRuns.
+func (mr *MockJobUsecaseMockRecorder) ListJobRuns(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobRuns", reflect.TypeOf((*MockJobUsecase)(nil).ListJobRuns), ctx, req)
+}
+
+// ListJobs mocks base method.
+func (m *MockJobUsecase) ListJobs(ctx context.Context) ([]entity.JobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx)
+	ret0, _ := ret[0].([]entity.JobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockJobUsecaseMockRecorder) ListJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockJobUsecase)(nil).ListJobs), ctx)
+}
+
+// PauseJob mocks base method.
+func (m *MockJobUsecase) PauseJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseJob", ctx, adminID, name)
+	ret0, _ := ret[0].(*entity.JobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseJob indicates an expected call of PauseJob.
+func (mr *MockJobUsecaseMockRecorder) PauseJob(ctx, adminID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseJob", reflect.TypeOf((*MockJobUsecase)(nil).PauseJob), ctx, adminID, name)
+}
+
+// ResumeJob mocks base method.
+func (m *MockJobUsecase) ResumeJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeJob", ctx, adminID, name)
+	ret0, _ := ret[0].(*entity.JobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeJob indicates an expected call of ResumeJob.
+func (mr *MockJobUsecaseMockRecorder) ResumeJob(ctx, adminID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeJob", reflect.TypeOf((*MockJobUsecase)(nil).ResumeJob), ctx, adminID, name)
+}
+
+// TriggerJob mocks base method.
+func (m *MockJobUsecase) TriggerJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobRunResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TriggerJob", ctx, adminID, name)
+	ret0, _ := ret[0].(*entity.JobRunResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TriggerJob indicates an expected call of TriggerJob.
+func (mr *MockJobUsecaseMockRecorder) TriggerJob(ctx, adminID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TriggerJob", reflect.TypeOf((*MockJobUsecase)(nil).TriggerJob), ctx, adminID, name)
+}
+
+// MockJobRepository is a mock of JobRepository interface.
+type MockJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockJobRepositoryMockRecorder is the mock recorder for MockJobRepository.
+type MockJobRepositoryMockRecorder struct {
+	mock *MockJobRepository
+}
+
+// NewMockJobRepository creates a new mock instance.
+func NewMockJobRepository(ctrl *gomock.Controller) *MockJobRepository {
+	mock := &MockJobRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepository) EXPECT() *MockJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateJobRun mocks base method.
+func (m *MockJobRepository) CreateJobRun(ctx context.Context, run *entity.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJobRun indicates an expected call of CreateJobRun.
+func (mr *MockJobRepositoryMockRecorder) CreateJobRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobRun", reflect.TypeOf((*MockJobRepository)(nil).CreateJobRun), ctx, run)
+}
+
+// FinishJobRun mocks base method.
+func (m *MockJobRepository) FinishJobRun(ctx context.Context, run *entity.JobRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishJobRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishJobRun indicates an expected call of FinishJobRun.
+func (mr *MockJobRepositoryMockRecorder) FinishJobRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishJobRun", reflect.TypeOf((*MockJobRepository)(nil).FinishJobRun), ctx, run)
+}
+
+// GetLatestJobRun mocks base method.
+func (m *MockJobRepository) GetLatestJobRun(ctx context.Context, name string) (*entity.JobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestJobRun", ctx, name)
+	ret0, _ := ret[0].(*entity.JobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestJobRun indicates an expected call of GetLatestJobRun.
+func (mr *MockJobRepositoryMockRecorder) GetLatestJobRun(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestJobRun", reflect.TypeOf((*MockJobRepository)(nil).GetLatestJobRun), ctx, name)
+}
+
+// IsJobPaused mocks base method.
+func (m *MockJobRepository) IsJobPaused(ctx context.Context, name string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsJobPaused", ctx, name)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsJobPaused indicates an expected call of IsJobPaused.
+func (mr *MockJobRepositoryMockRecorder) IsJobPaused(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsJobPaused", reflect.TypeOf((*MockJobRepository)(nil).IsJobPaused), ctx, name)
+}
+
+// ListJobRuns mocks base method.
+func (m *MockJobRepository) ListJobRuns(ctx context.Context, req entity.JobRunListRequest) ([]*entity.JobRun, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobRuns", ctx, req)
+	ret0, _ := ret[0].([]*entity.JobRun)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListJobRuns indicates an expected call of ListJobRuns.
+func (mr *MockJobRepositoryMockRecorder) ListJobRuns(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobRuns", reflect.TypeOf((*MockJobRepository)(nil).ListJobRuns), ctx, req)
+}
+
+// ListJobStates mocks base method.
+func (m *MockJobRepository) ListJobStates(ctx context.Context) ([]*entity.JobState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobStates", ctx)
+	ret0, _ := ret[0].([]*entity.JobState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobStates indicates an expected call of ListJobStates.
+func (mr *MockJobRepositoryMockRecorder) ListJobStates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobStates", reflect.TypeOf((*MockJobRepository)(nil).ListJobStates), ctx)
+}
+
+// SetJobPaused mocks base method.
+func (m *MockJobRepository) SetJobPaused(ctx context.Context, name string, paused bool, updatedBy *uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetJobPaused", ctx, name, paused, updatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobPaused indicates an expected call of SetJobPaused.
+func (mr *MockJobRepositoryMockRecorder) SetJobPaused(ctx, name, paused, updatedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobPaused", reflect.TypeOf((*MockJobRepository)(nil).SetJobPaused), ctx, name, paused, updatedBy)
+}
+
+// MockJobScheduler is a mock of JobScheduler interface.
+type MockJobScheduler struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobSchedulerMockRecorder
+	isgomock struct{}
+}
+
+// MockJobSchedulerMockRecorder is the mock recorder for MockJobScheduler.
+type MockJobSchedulerMockRecorder struct {
+	mock *MockJobScheduler
+}
+
+// NewMockJobScheduler creates a new mock instance.
+func NewMockJobScheduler(ctrl *gomock.Controller) *MockJobScheduler {
+	mock := &MockJobScheduler{ctrl: ctrl}
+	mock.recorder = &MockJobSchedulerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobScheduler) EXPECT() *MockJobSchedulerMockRecorder {
+	return m.recorder
+}
+
+// Job mocks base method.
+func (m *MockJobScheduler) Job(name string) (*entity.JobInfo, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Job", name)
+	ret0, _ := ret[0].(*entity.JobInfo)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Job indicates an expected call of Job.
+func (mr *MockJobSchedulerMockRecorder) Job(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Job", reflect.TypeOf((*MockJobScheduler)(nil).Job), name)
+}
+
+// Jobs mocks base method.
+func (m *MockJobScheduler) Jobs() []entity.JobInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Jobs")
+	ret0, _ := ret[0].([]entity.JobInfo)
+	return ret0
+}
+
+// Jobs indicates an expected call of Jobs.
+func (mr *MockJobSchedulerMockRecorder) Jobs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Jobs", reflect.TypeOf((*MockJobScheduler)(nil).Jobs))
+}
+
+// SetPaused mocks base method.
+func (m *MockJobScheduler) SetPaused(name string, paused bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetPaused", name, paused)
+}
+
+// SetPaused indicates an expected call of SetPaused.
+func (mr *MockJobSchedulerMockRecorder) SetPaused(name, paused any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPaused", reflect.TypeOf((*MockJobScheduler)(nil).SetPaused), name, paused)
+}
+
+// Trigger mocks base method.
+func (m *MockJobScheduler) Trigger(ctx context.Context, name string, triggeredBy *uuid.UUID) (*entity.JobRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Trigger", ctx, name, triggeredBy)
+	ret0, _ := ret[0].(*entity.JobRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Trigger indicates an expected call of Trigger.
+func (mr *MockJobSchedulerMockRecorder) Trigger(ctx, name, triggeredBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Trigger", reflect.TypeOf((*MockJobScheduler)(nil).Trigger), ctx, name, triggeredBy)
+}
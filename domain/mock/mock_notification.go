@@ -0,0 +1,362 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/notification.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/notification.go -destination=domain/mock/mock_notification.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Send mocks base method.
+func (m *MockNotifier) Send(ctx context.Context, email entity.Email) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockNotifierMockRecorder) Send(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockNotifier)(nil).Send), ctx, email)
+}
+
+// MockNotificationUsecase is a mock of NotificationUsecase interface.
+type MockNotificationUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationUsecaseMockRecorder is the mock recorder for MockNotificationUsecase.
+type MockNotificationUsecaseMockRecorder struct {
+	mock *MockNotificationUsecase
+}
+
+// NewMockNotificationUsecase creates a new mock instance.
+func NewMockNotificationUsecase(ctrl *gomock.Controller) *MockNotificationUsecase {
+	mock := &MockNotificationUsecase{ctrl: ctrl}
+	mock.recorder = &MockNotificationUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationUsecase) EXPECT() *MockNotificationUsecaseMockRecorder {
+	return m.recorder
+}
+
+// GetPreferences mocks base method.
+func (m *MockNotificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationPreferenceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userID)
+	ret0, _ := ret[0].([]*entity.NotificationPreferenceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockNotificationUsecaseMockRecorder) GetPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockNotificationUsecase)(nil).GetPreferences), ctx, userID)
+}
+
+// GetUnreadCount mocks base method.
+func (m *MockNotificationUsecase) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*entity.NotificationUnreadCountResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreadCount", ctx, userID)
+	ret0, _ := ret[0].(*entity.NotificationUnreadCountResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnreadCount indicates an expected call of GetUnreadCount.
+func (mr *MockNotificationUsecaseMockRecorder) GetUnreadCount(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreadCount", reflect.TypeOf((*MockNotificationUsecase)(nil).GetUnreadCount), ctx, userID)
+}
+
+// ListNotifications mocks base method.
+func (m *MockNotificationUsecase) ListNotifications(ctx context.Context, userID uuid.UUID, req entity.NotificationListRequest) (*entity.NotificationListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.NotificationListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockNotificationUsecaseMockRecorder) ListNotifications(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockNotificationUsecase)(nil).ListNotifications), ctx, userID, req)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockNotificationUsecase) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (*entity.NotificationMarkAllReadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", ctx, userID)
+	ret0, _ := ret[0].(*entity.NotificationMarkAllReadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockNotificationUsecaseMockRecorder) MarkAllNotificationsRead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockNotificationUsecase)(nil).MarkAllNotificationsRead), ctx, userID)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockNotificationUsecase) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) (*entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", ctx, userID, notificationID)
+	ret0, _ := ret[0].(*entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockNotificationUsecaseMockRecorder) MarkNotificationRead(ctx, userID, notificationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockNotificationUsecase)(nil).MarkNotificationRead), ctx, userID, notificationID)
+}
+
+// UpdatePreferences mocks base method.
+func (m *MockNotificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, req entity.UpdateNotificationPreferencesRequest) ([]*entity.NotificationPreferenceResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePreferences", ctx, userID, req)
+	ret0, _ := ret[0].([]*entity.NotificationPreferenceResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePreferences indicates an expected call of UpdatePreferences.
+func (mr *MockNotificationUsecaseMockRecorder) UpdatePreferences(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePreferences", reflect.TypeOf((*MockNotificationUsecase)(nil).UpdatePreferences), ctx, userID, req)
+}
+
+// MockNotificationRepository is a mock of NotificationRepository interface.
+type MockNotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockNotificationRepositoryMockRecorder is the mock recorder for MockNotificationRepository.
+type MockNotificationRepositoryMockRecorder struct {
+	mock *MockNotificationRepository
+}
+
+// NewMockNotificationRepository creates a new mock instance.
+func NewMockNotificationRepository(ctrl *gomock.Controller) *MockNotificationRepository {
+	mock := &MockNotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationRepository) EXPECT() *MockNotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueEmails mocks base method.
+func (m *MockNotificationRepository) ClaimDueEmails(ctx context.Context, claim entity.JobClaim) ([]*entity.EmailMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueEmails", ctx, claim)
+	ret0, _ := ret[0].([]*entity.EmailMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueEmails indicates an expected call of ClaimDueEmails.
+func (mr *MockNotificationRepositoryMockRecorder) ClaimDueEmails(ctx, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueEmails", reflect.TypeOf((*MockNotificationRepository)(nil).ClaimDueEmails), ctx, claim)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockNotificationRepository) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) CountUnreadNotifications(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).CountUnreadNotifications), ctx, userID)
+}
+
+// CreateNotifications mocks base method.
+func (m *MockNotificationRepository) CreateNotifications(ctx context.Context, notifications []*entity.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotifications", ctx, notifications)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotifications indicates an expected call of CreateNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) CreateNotifications(ctx, notifications any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).CreateNotifications), ctx, notifications)
+}
+
+// FinishEmail mocks base method.
+func (m *MockNotificationRepository) FinishEmail(ctx context.Context, email *entity.EmailMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishEmail", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishEmail indicates an expected call of FinishEmail.
+func (mr *MockNotificationRepositoryMockRecorder) FinishEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishEmail", reflect.TypeOf((*MockNotificationRepository)(nil).FinishEmail), ctx, email)
+}
+
+// GetNotificationByID mocks base method.
+func (m *MockNotificationRepository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationByID indicates an expected call of GetNotificationByID.
+func (mr *MockNotificationRepositoryMockRecorder) GetNotificationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationByID", reflect.TypeOf((*MockNotificationRepository)(nil).GetNotificationByID), ctx, id)
+}
+
+// GetPreference mocks base method.
+func (m *MockNotificationRepository) GetPreference(ctx context.Context, userID uuid.UUID, eventType string) (*entity.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreference", ctx, userID, eventType)
+	ret0, _ := ret[0].(*entity.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreference indicates an expected call of GetPreference.
+func (mr *MockNotificationRepositoryMockRecorder) GetPreference(ctx, userID, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreference", reflect.TypeOf((*MockNotificationRepository)(nil).GetPreference), ctx, userID, eventType)
+}
+
+// GetPreferences mocks base method.
+func (m *MockNotificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPreferences", ctx, userID)
+	ret0, _ := ret[0].([]*entity.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPreferences indicates an expected call of GetPreferences.
+func (mr *MockNotificationRepositoryMockRecorder) GetPreferences(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPreferences", reflect.TypeOf((*MockNotificationRepository)(nil).GetPreferences), ctx, userID)
+}
+
+// ListNotifications mocks base method.
+func (m *MockNotificationRepository) ListNotifications(ctx context.Context, userID uuid.UUID, req entity.NotificationListRequest) ([]*entity.Notification, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", ctx, userID, req)
+	ret0, _ := ret[0].([]*entity.Notification)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockNotificationRepositoryMockRecorder) ListNotifications(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockNotificationRepository)(nil).ListNotifications), ctx, userID, req)
+}
+
+// MarkNotificationsRead mocks base method.
+func (m *MockNotificationRepository) MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids ...uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, userID}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "MarkNotificationsRead", varargs...)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationsRead indicates an expected call of MarkNotificationsRead.
+func (mr *MockNotificationRepositoryMockRecorder) MarkNotificationsRead(ctx, userID any, ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, userID}, ids...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationsRead", reflect.TypeOf((*MockNotificationRepository)(nil).MarkNotificationsRead), varargs...)
+}
+
+// QueueEmails mocks base method.
+func (m *MockNotificationRepository) QueueEmails(ctx context.Context, emails []*entity.EmailMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueEmails", ctx, emails)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueEmails indicates an expected call of QueueEmails.
+func (mr *MockNotificationRepositoryMockRecorder) QueueEmails(ctx, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueEmails", reflect.TypeOf((*MockNotificationRepository)(nil).QueueEmails), ctx, emails)
+}
+
+// UpsertPreferences mocks base method.
+func (m *MockNotificationRepository) UpsertPreferences(ctx context.Context, preferences []*entity.NotificationPreference) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertPreferences", ctx, preferences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertPreferences indicates an expected call of UpsertPreferences.
+func (mr *MockNotificationRepositoryMockRecorder) UpsertPreferences(ctx, preferences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertPreferences", reflect.TypeOf((*MockNotificationRepository)(nil).UpsertPreferences), ctx, preferences)
+}
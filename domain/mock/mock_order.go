@@ -162,10 +162,10 @@ func (mr *MockOrderUsecaseMockRecorder) GetOrderGroup(ctx, userID, orderID any)
 }
 
 // GetShipmentTracking mocks base method.
-func (m *MockOrderUsecase) GetShipmentTracking(ctx context.Context, userID, shopOrderID uuid.UUID) (*entity.ShipmentResponse, error) {
+func (m *MockOrderUsecase) GetShipmentTracking(ctx context.Context, userID, shopOrderID uuid.UUID) (*entity.ShopOrderTrackingResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetShipmentTracking", ctx, userID, shopOrderID)
-	ret0, _ := ret[0].(*entity.ShipmentResponse)
+	ret0, _ := ret[0].(*entity.ShopOrderTrackingResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -176,6 +176,36 @@ func (mr *MockOrderUsecaseMockRecorder) GetShipmentTracking(ctx, userID, shopOrd
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentTracking", reflect.TypeOf((*MockOrderUsecase)(nil).GetShipmentTracking), ctx, userID, shopOrderID)
 }
 
+// GetShippingLabel mocks base method.
+func (m *MockOrderUsecase) GetShippingLabel(ctx context.Context, userID, shopOrderID uuid.UUID) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShippingLabel", ctx, userID, shopOrderID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShippingLabel indicates an expected call of GetShippingLabel.
+func (mr *MockOrderUsecaseMockRecorder) GetShippingLabel(ctx, userID, shopOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShippingLabel", reflect.TypeOf((*MockOrderUsecase)(nil).GetShippingLabel), ctx, userID, shopOrderID)
+}
+
+// GetShippingLabels mocks base method.
+func (m *MockOrderUsecase) GetShippingLabels(ctx context.Context, userID uuid.UUID, shopOrderIDs []uuid.UUID) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShippingLabels", ctx, userID, shopOrderIDs)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShippingLabels indicates an expected call of GetShippingLabels.
+func (mr *MockOrderUsecaseMockRecorder) GetShippingLabels(ctx, userID, shopOrderIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShippingLabels", reflect.TypeOf((*MockOrderUsecase)(nil).GetShippingLabels), ctx, userID, shopOrderIDs)
+}
+
 // GetShopOrder mocks base method.
 func (m *MockOrderUsecase) GetShopOrder(ctx context.Context, userID, shopOrderID uuid.UUID) (*entity.ShopOrderResponse, error) {
 	m.ctrl.T.Helper()
@@ -192,10 +222,10 @@ func (mr *MockOrderUsecaseMockRecorder) GetShopOrder(ctx, userID, shopOrderID an
 }
 
 // GetShopShipmentTracking mocks base method.
-func (m *MockOrderUsecase) GetShopShipmentTracking(ctx context.Context, userID, shopOrderID uuid.UUID) (*entity.ShipmentResponse, error) {
+func (m *MockOrderUsecase) GetShopShipmentTracking(ctx context.Context, userID, shopOrderID uuid.UUID) (*entity.ShopOrderTrackingResponse, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetShopShipmentTracking", ctx, userID, shopOrderID)
-	ret0, _ := ret[0].(*entity.ShipmentResponse)
+	ret0, _ := ret[0].(*entity.ShopOrderTrackingResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -206,6 +236,20 @@ func (mr *MockOrderUsecaseMockRecorder) GetShopShipmentTracking(ctx, userID, sho
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopShipmentTracking", reflect.TypeOf((*MockOrderUsecase)(nil).GetShopShipmentTracking), ctx, userID, shopOrderID)
 }
 
+// HandleCourierWebhook mocks base method.
+func (m *MockOrderUsecase) HandleCourierWebhook(ctx context.Context, courierID uint32, signature string, payload []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandleCourierWebhook", ctx, courierID, signature, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HandleCourierWebhook indicates an expected call of HandleCourierWebhook.
+func (mr *MockOrderUsecaseMockRecorder) HandleCourierWebhook(ctx, courierID, signature, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandleCourierWebhook", reflect.TypeOf((*MockOrderUsecase)(nil).HandleCourierWebhook), ctx, courierID, signature, payload)
+}
+
 // ListOrderGroups mocks base method.
 func (m *MockOrderUsecase) ListOrderGroups(ctx context.Context, userID uuid.UUID, req entity.OrderListRequest) (*entity.OrderGroupListPaginationResponse, error) {
 	m.ctrl.T.Helper()
@@ -251,6 +295,36 @@ func (mr *MockOrderUsecaseMockRecorder) ListShopOrders(ctx, userID, req any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShopOrders", reflect.TypeOf((*MockOrderUsecase)(nil).ListShopOrders), ctx, userID, req)
 }
 
+// ScheduleDeliveryReattempt mocks base method.
+func (m *MockOrderUsecase) ScheduleDeliveryReattempt(ctx context.Context, userID, shopOrderID, shipmentID uuid.UUID, req entity.DeliveryReattemptRequest) (*entity.ShipmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleDeliveryReattempt", ctx, userID, shopOrderID, shipmentID, req)
+	ret0, _ := ret[0].(*entity.ShipmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleDeliveryReattempt indicates an expected call of ScheduleDeliveryReattempt.
+func (mr *MockOrderUsecaseMockRecorder) ScheduleDeliveryReattempt(ctx, userID, shopOrderID, shipmentID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleDeliveryReattempt", reflect.TypeOf((*MockOrderUsecase)(nil).ScheduleDeliveryReattempt), ctx, userID, shopOrderID, shipmentID, req)
+}
+
+// SyncShipmentTracking mocks base method.
+func (m *MockOrderUsecase) SyncShipmentTracking(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncShipmentTracking", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncShipmentTracking indicates an expected call of SyncShipmentTracking.
+func (mr *MockOrderUsecaseMockRecorder) SyncShipmentTracking(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncShipmentTracking", reflect.TypeOf((*MockOrderUsecase)(nil).SyncShipmentTracking), ctx)
+}
+
 // UpdateShopOrderStatus mocks base method.
 func (m *MockOrderUsecase) UpdateShopOrderStatus(ctx context.Context, userID, shopOrderID uuid.UUID, req entity.UpdateOrderStatusRequest) error {
 	m.ctrl.T.Helper()
@@ -331,6 +405,66 @@ func (mr *MockOrderRepositoryMockRecorder) CancelShopOrder(ctx, id, reason any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelShopOrder", reflect.TypeOf((*MockOrderRepository)(nil).CancelShopOrder), ctx, id, reason)
 }
 
+// CancelShopOrderWithRefund mocks base method.
+func (m *MockOrderRepository) CancelShopOrderWithRefund(ctx context.Context, id uuid.UUID, reason string, refund *entity.Refund) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelShopOrderWithRefund", ctx, id, reason, refund)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelShopOrderWithRefund indicates an expected call of CancelShopOrderWithRefund.
+func (mr *MockOrderRepositoryMockRecorder) CancelShopOrderWithRefund(ctx, id, reason, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelShopOrderWithRefund", reflect.TypeOf((*MockOrderRepository)(nil).CancelShopOrderWithRefund), ctx, id, reason, refund)
+}
+
+// ClaimDeliveredOrdersToAutoComplete mocks base method.
+func (m *MockOrderRepository) ClaimDeliveredOrdersToAutoComplete(ctx context.Context, defaultDays int, claim entity.JobClaim) ([]*entity.ShopOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveredOrdersToAutoComplete", ctx, defaultDays, claim)
+	ret0, _ := ret[0].([]*entity.ShopOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveredOrdersToAutoComplete indicates an expected call of ClaimDeliveredOrdersToAutoComplete.
+func (mr *MockOrderRepositoryMockRecorder) ClaimDeliveredOrdersToAutoComplete(ctx, defaultDays, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveredOrdersToAutoComplete", reflect.TypeOf((*MockOrderRepository)(nil).ClaimDeliveredOrdersToAutoComplete), ctx, defaultDays, claim)
+}
+
+// ClaimDeliveredOrdersToRemind mocks base method.
+func (m *MockOrderRepository) ClaimDeliveredOrdersToRemind(ctx context.Context, defaultDays int, claim entity.JobClaim) ([]*entity.ShopOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveredOrdersToRemind", ctx, defaultDays, claim)
+	ret0, _ := ret[0].([]*entity.ShopOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveredOrdersToRemind indicates an expected call of ClaimDeliveredOrdersToRemind.
+func (mr *MockOrderRepositoryMockRecorder) ClaimDeliveredOrdersToRemind(ctx, defaultDays, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveredOrdersToRemind", reflect.TypeOf((*MockOrderRepository)(nil).ClaimDeliveredOrdersToRemind), ctx, defaultDays, claim)
+}
+
+// ClaimExpiredPayments mocks base method.
+func (m *MockOrderRepository) ClaimExpiredPayments(ctx context.Context, claim entity.JobClaim) ([]*entity.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExpiredPayments", ctx, claim)
+	ret0, _ := ret[0].([]*entity.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExpiredPayments indicates an expected call of ClaimExpiredPayments.
+func (mr *MockOrderRepositoryMockRecorder) ClaimExpiredPayments(ctx, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExpiredPayments", reflect.TypeOf((*MockOrderRepository)(nil).ClaimExpiredPayments), ctx, claim)
+}
+
 // ClearCart mocks base method.
 func (m *MockOrderRepository) ClearCart(ctx context.Context, cartID uint32) error {
 	m.ctrl.T.Helper()
@@ -345,6 +479,20 @@ func (mr *MockOrderRepositoryMockRecorder) ClearCart(ctx, cartID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCart", reflect.TypeOf((*MockOrderRepository)(nil).ClearCart), ctx, cartID)
 }
 
+// CreateDeliveryReattempt mocks base method.
+func (m *MockOrderRepository) CreateDeliveryReattempt(ctx context.Context, reattempt *entity.DeliveryReattempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeliveryReattempt", ctx, reattempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeliveryReattempt indicates an expected call of CreateDeliveryReattempt.
+func (mr *MockOrderRepositoryMockRecorder) CreateDeliveryReattempt(ctx, reattempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveryReattempt", reflect.TypeOf((*MockOrderRepository)(nil).CreateDeliveryReattempt), ctx, reattempt)
+}
+
 // CreateFullOrder mocks base method.
 func (m *MockOrderRepository) CreateFullOrder(ctx context.Context, order *entity.Order, shopOrders []*entity.ShopOrder, orderItemsByShop map[string][]*entity.OrderItem, payment *entity.Payment, cartID uint32, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -429,6 +577,20 @@ func (mr *MockOrderRepositoryMockRecorder) CreateShopOrder(ctx, so any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShopOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateShopOrder), ctx, so)
 }
 
+// CreateShopOrderRefund mocks base method.
+func (m *MockOrderRepository) CreateShopOrderRefund(ctx context.Context, refund *entity.Refund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateShopOrderRefund", ctx, refund)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateShopOrderRefund indicates an expected call of CreateShopOrderRefund.
+func (mr *MockOrderRepositoryMockRecorder) CreateShopOrderRefund(ctx, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShopOrderRefund", reflect.TypeOf((*MockOrderRepository)(nil).CreateShopOrderRefund), ctx, refund)
+}
+
 // EnsureCartForUser mocks base method.
 func (m *MockOrderRepository) EnsureCartForUser(ctx context.Context, userID uuid.UUID) (*entity.Cart, error) {
 	m.ctrl.T.Helper()
@@ -474,6 +636,21 @@ func (mr *MockOrderRepositoryMockRecorder) GetCartItemByID(ctx, id any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartItemByID", reflect.TypeOf((*MockOrderRepository)(nil).GetCartItemByID), ctx, id)
 }
 
+// GetCourierByID mocks base method.
+func (m *MockOrderRepository) GetCourierByID(ctx context.Context, id uint32) (*entity.Courier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourierByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Courier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourierByID indicates an expected call of GetCourierByID.
+func (mr *MockOrderRepositoryMockRecorder) GetCourierByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourierByID", reflect.TypeOf((*MockOrderRepository)(nil).GetCourierByID), ctx, id)
+}
+
 // GetOrderByID mocks base method.
 func (m *MockOrderRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
 	m.ctrl.T.Helper()
@@ -549,19 +726,34 @@ func (mr *MockOrderRepositoryMockRecorder) GetPaymentByTransactionID(ctx, transa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentByTransactionID", reflect.TypeOf((*MockOrderRepository)(nil).GetPaymentByTransactionID), ctx, transactionID)
 }
 
-// GetShipmentByShopOrderID mocks base method.
-func (m *MockOrderRepository) GetShipmentByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) (*entity.Shipment, error) {
+// GetShipmentByTrackingNo mocks base method.
+func (m *MockOrderRepository) GetShipmentByTrackingNo(ctx context.Context, courierID uint32, trackingNo string) (*entity.Shipment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetShipmentByShopOrderID", ctx, shopOrderID)
+	ret := m.ctrl.Call(m, "GetShipmentByTrackingNo", ctx, courierID, trackingNo)
 	ret0, _ := ret[0].(*entity.Shipment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetShipmentByShopOrderID indicates an expected call of GetShipmentByShopOrderID.
-func (mr *MockOrderRepositoryMockRecorder) GetShipmentByShopOrderID(ctx, shopOrderID any) *gomock.Call {
+// GetShipmentByTrackingNo indicates an expected call of GetShipmentByTrackingNo.
+func (mr *MockOrderRepositoryMockRecorder) GetShipmentByTrackingNo(ctx, courierID, trackingNo any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentByTrackingNo", reflect.TypeOf((*MockOrderRepository)(nil).GetShipmentByTrackingNo), ctx, courierID, trackingNo)
+}
+
+// GetShipmentsByShopOrderID mocks base method.
+func (m *MockOrderRepository) GetShipmentsByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) ([]*entity.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShipmentsByShopOrderID", ctx, shopOrderID)
+	ret0, _ := ret[0].([]*entity.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShipmentsByShopOrderID indicates an expected call of GetShipmentsByShopOrderID.
+func (mr *MockOrderRepositoryMockRecorder) GetShipmentsByShopOrderID(ctx, shopOrderID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentByShopOrderID", reflect.TypeOf((*MockOrderRepository)(nil).GetShipmentByShopOrderID), ctx, shopOrderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShipmentsByShopOrderID", reflect.TypeOf((*MockOrderRepository)(nil).GetShipmentsByShopOrderID), ctx, shopOrderID)
 }
 
 // GetShopOrderByID mocks base method.
@@ -594,36 +786,6 @@ func (mr *MockOrderRepositoryMockRecorder) ListCartItems(ctx, cartID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCartItems", reflect.TypeOf((*MockOrderRepository)(nil).ListCartItems), ctx, cartID)
 }
 
-// ListDeliveredOrdersOlderThan mocks base method.
-func (m *MockOrderRepository) ListDeliveredOrdersOlderThan(ctx context.Context, days int) ([]*entity.ShopOrder, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListDeliveredOrdersOlderThan", ctx, days)
-	ret0, _ := ret[0].([]*entity.ShopOrder)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// ListDeliveredOrdersOlderThan indicates an expected call of ListDeliveredOrdersOlderThan.
-func (mr *MockOrderRepositoryMockRecorder) ListDeliveredOrdersOlderThan(ctx, days any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveredOrdersOlderThan", reflect.TypeOf((*MockOrderRepository)(nil).ListDeliveredOrdersOlderThan), ctx, days)
-}
-
-// ListExpiredPayments mocks base method.
-func (m *MockOrderRepository) ListExpiredPayments(ctx context.Context) ([]*entity.Payment, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListExpiredPayments", ctx)
-	ret0, _ := ret[0].([]*entity.Payment)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// ListExpiredPayments indicates an expected call of ListExpiredPayments.
-func (mr *MockOrderRepositoryMockRecorder) ListExpiredPayments(ctx any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredPayments", reflect.TypeOf((*MockOrderRepository)(nil).ListExpiredPayments), ctx)
-}
-
 // ListOrdersByUser mocks base method.
 func (m *MockOrderRepository) ListOrdersByUser(ctx context.Context, userID uuid.UUID, req entity.OrderListRequest) ([]*entity.Order, int64, error) {
 	m.ctrl.T.Helper()
@@ -640,6 +802,21 @@ func (mr *MockOrderRepositoryMockRecorder) ListOrdersByUser(ctx, userID, req any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrdersByUser", reflect.TypeOf((*MockOrderRepository)(nil).ListOrdersByUser), ctx, userID, req)
 }
 
+// ListShipmentsForTrackingSync mocks base method.
+func (m *MockOrderRepository) ListShipmentsForTrackingSync(ctx context.Context, limit int) ([]*entity.Shipment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShipmentsForTrackingSync", ctx, limit)
+	ret0, _ := ret[0].([]*entity.Shipment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShipmentsForTrackingSync indicates an expected call of ListShipmentsForTrackingSync.
+func (mr *MockOrderRepositoryMockRecorder) ListShipmentsForTrackingSync(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShipmentsForTrackingSync", reflect.TypeOf((*MockOrderRepository)(nil).ListShipmentsForTrackingSync), ctx, limit)
+}
+
 // ListShopOrdersByShopID mocks base method.
 func (m *MockOrderRepository) ListShopOrdersByShopID(ctx context.Context, shopID uuid.UUID, req entity.OrderListRequest) ([]*entity.ShopOrder, int64, error) {
 	m.ctrl.T.Helper()
@@ -672,6 +849,49 @@ func (mr *MockOrderRepositoryMockRecorder) ListShopOrdersByUserID(ctx, userID, r
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShopOrdersByUserID", reflect.TypeOf((*MockOrderRepository)(nil).ListShopOrdersByUserID), ctx, userID, req)
 }
 
+// MarkAutoCompleteReminded mocks base method.
+func (m *MockOrderRepository) MarkAutoCompleteReminded(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAutoCompleteReminded", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkAutoCompleteReminded indicates an expected call of MarkAutoCompleteReminded.
+func (mr *MockOrderRepositoryMockRecorder) MarkAutoCompleteReminded(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAutoCompleteReminded", reflect.TypeOf((*MockOrderRepository)(nil).MarkAutoCompleteReminded), ctx, id)
+}
+
+// RecordShipmentEvent mocks base method.
+func (m *MockOrderRepository) RecordShipmentEvent(ctx context.Context, event *entity.ShipmentEvent) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordShipmentEvent", ctx, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordShipmentEvent indicates an expected call of RecordShipmentEvent.
+func (mr *MockOrderRepositoryMockRecorder) RecordShipmentEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordShipmentEvent", reflect.TypeOf((*MockOrderRepository)(nil).RecordShipmentEvent), ctx, event)
+}
+
+// UpdatePaymentRefundStatus mocks base method.
+func (m *MockOrderRepository) UpdatePaymentRefundStatus(ctx context.Context, paymentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePaymentRefundStatus", ctx, paymentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePaymentRefundStatus indicates an expected call of UpdatePaymentRefundStatus.
+func (mr *MockOrderRepositoryMockRecorder) UpdatePaymentRefundStatus(ctx, paymentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePaymentRefundStatus", reflect.TypeOf((*MockOrderRepository)(nil).UpdatePaymentRefundStatus), ctx, paymentID)
+}
+
 // UpdatePaymentStatus mocks base method.
 func (m *MockOrderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatusID uint32, paidAt *time.Time) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,228 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/outbox.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/outbox.go -destination=domain/mock/mock_outbox.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactor is a mock of Transactor interface.
+type MockTransactor struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactorMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactorMockRecorder is the mock recorder for MockTransactor.
+type MockTransactorMockRecorder struct {
+	mock *MockTransactor
+}
+
+// NewMockTransactor creates a new mock instance.
+func NewMockTransactor(ctrl *gomock.Controller) *MockTransactor {
+	mock := &MockTransactor{ctrl: ctrl}
+	mock.recorder = &MockTransactorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactor) EXPECT() *MockTransactorMockRecorder {
+	return m.recorder
+}
+
+// WithinTransaction mocks base method.
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithinTransaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithinTransaction indicates an expected call of WithinTransaction.
+func (mr *MockTransactorMockRecorder) WithinTransaction(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithinTransaction", reflect.TypeOf((*MockTransactor)(nil).WithinTransaction), ctx, fn)
+}
+
+// MockOutboxUsecase is a mock of OutboxUsecase interface.
+type MockOutboxUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxUsecaseMockRecorder is the mock recorder for MockOutboxUsecase.
+type MockOutboxUsecaseMockRecorder struct {
+	mock *MockOutboxUsecase
+}
+
+// NewMockOutboxUsecase creates a new mock instance.
+func NewMockOutboxUsecase(ctrl *gomock.Controller) *MockOutboxUsecase {
+	mock := &MockOutboxUsecase{ctrl: ctrl}
+	mock.recorder = &MockOutboxUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxUsecase) EXPECT() *MockOutboxUsecaseMockRecorder {
+	return m.recorder
+}
+
+// ListEvents mocks base method.
+func (m *MockOutboxUsecase) ListEvents(ctx context.Context, req entity.OutboxEventListRequest) (*entity.OutboxEventListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, req)
+	ret0, _ := ret[0].(*entity.OutboxEventListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockOutboxUsecaseMockRecorder) ListEvents(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockOutboxUsecase)(nil).ListEvents), ctx, req)
+}
+
+// RetryEvent mocks base method.
+func (m *MockOutboxUsecase) RetryEvent(ctx context.Context, id uuid.UUID) (*entity.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryEvent", ctx, id)
+	ret0, _ := ret[0].(*entity.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryEvent indicates an expected call of RetryEvent.
+func (mr *MockOutboxUsecaseMockRecorder) RetryEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryEvent", reflect.TypeOf((*MockOutboxUsecase)(nil).RetryEvent), ctx, id)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockOutboxRepository) Add(ctx context.Context, events ...*entity.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Add", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockOutboxRepositoryMockRecorder) Add(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOutboxRepository)(nil).Add), varargs...)
+}
+
+// ClaimPendingEvents mocks base method.
+func (m *MockOutboxRepository) ClaimPendingEvents(ctx context.Context, claim entity.JobClaim) ([]*entity.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimPendingEvents", ctx, claim)
+	ret0, _ := ret[0].([]*entity.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimPendingEvents indicates an expected call of ClaimPendingEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimPendingEvents(ctx, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimPendingEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimPendingEvents), ctx, claim)
+}
+
+// FinishDelivery mocks base method.
+func (m *MockOutboxRepository) FinishDelivery(ctx context.Context, event *entity.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishDelivery", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishDelivery indicates an expected call of FinishDelivery.
+func (mr *MockOutboxRepositoryMockRecorder) FinishDelivery(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishDelivery", reflect.TypeOf((*MockOutboxRepository)(nil).FinishDelivery), ctx, event)
+}
+
+// GetEventByID mocks base method.
+func (m *MockOutboxRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*entity.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, id)
+	ret0, _ := ret[0].(*entity.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventByID indicates an expected call of GetEventByID.
+func (mr *MockOutboxRepositoryMockRecorder) GetEventByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockOutboxRepository)(nil).GetEventByID), ctx, id)
+}
+
+// ListEvents mocks base method.
+func (m *MockOutboxRepository) ListEvents(ctx context.Context, req entity.OutboxEventListRequest) ([]*entity.OutboxEvent, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, req)
+	ret0, _ := ret[0].([]*entity.OutboxEvent)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockOutboxRepositoryMockRecorder) ListEvents(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockOutboxRepository)(nil).ListEvents), ctx, req)
+}
+
+// RequeueDeadEvent mocks base method.
+func (m *MockOutboxRepository) RequeueDeadEvent(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDeadEvent", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueDeadEvent indicates an expected call of RequeueDeadEvent.
+func (mr *MockOutboxRepositoryMockRecorder) RequeueDeadEvent(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDeadEvent", reflect.TypeOf((*MockOutboxRepository)(nil).RequeueDeadEvent), ctx, id)
+}
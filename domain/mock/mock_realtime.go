@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/realtime.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/realtime.go -destination=domain/mock/mock_realtime.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	domain "ecommerce-go-api/domain"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRealtimeSubscription is a mock of RealtimeSubscription interface.
+type MockRealtimeSubscription struct {
+	ctrl     *gomock.Controller
+	recorder *MockRealtimeSubscriptionMockRecorder
+	isgomock struct{}
+}
+
+// MockRealtimeSubscriptionMockRecorder is the mock recorder for MockRealtimeSubscription.
+type MockRealtimeSubscriptionMockRecorder struct {
+	mock *MockRealtimeSubscription
+}
+
+// NewMockRealtimeSubscription creates a new mock instance.
+func NewMockRealtimeSubscription(ctrl *gomock.Controller) *MockRealtimeSubscription {
+	mock := &MockRealtimeSubscription{ctrl: ctrl}
+	mock.recorder = &MockRealtimeSubscriptionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRealtimeSubscription) EXPECT() *MockRealtimeSubscriptionMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockRealtimeSubscription) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockRealtimeSubscriptionMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockRealtimeSubscription)(nil).Close))
+}
+
+// Events mocks base method.
+func (m *MockRealtimeSubscription) Events() <-chan *entity.RealtimeEvent {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Events")
+	ret0, _ := ret[0].(<-chan *entity.RealtimeEvent)
+	return ret0
+}
+
+// Events indicates an expected call of Events.
+func (mr *MockRealtimeSubscriptionMockRecorder) Events() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockRealtimeSubscription)(nil).Events))
+}
+
+// MockRealtimeBroker is a mock of RealtimeBroker interface.
+type MockRealtimeBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockRealtimeBrokerMockRecorder
+	isgomock struct{}
+}
+
+// MockRealtimeBrokerMockRecorder is the mock recorder for MockRealtimeBroker.
+type MockRealtimeBrokerMockRecorder struct {
+	mock *MockRealtimeBroker
+}
+
+// NewMockRealtimeBroker creates a new mock instance.
+func NewMockRealtimeBroker(ctrl *gomock.Controller) *MockRealtimeBroker {
+	mock := &MockRealtimeBroker{ctrl: ctrl}
+	mock.recorder = &MockRealtimeBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRealtimeBroker) EXPECT() *MockRealtimeBrokerMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockRealtimeBroker) Publish(ctx context.Context, topic string, event *entity.RealtimeEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, topic, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockRealtimeBrokerMockRecorder) Publish(ctx, topic, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockRealtimeBroker)(nil).Publish), ctx, topic, event)
+}
+
+// Subscribe mocks base method.
+func (m *MockRealtimeBroker) Subscribe(topic string) domain.RealtimeSubscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", topic)
+	ret0, _ := ret[0].(domain.RealtimeSubscription)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockRealtimeBrokerMockRecorder) Subscribe(topic any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockRealtimeBroker)(nil).Subscribe), topic)
+}
+
+// MockRealtimeUsecase is a mock of RealtimeUsecase interface.
+type MockRealtimeUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRealtimeUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockRealtimeUsecaseMockRecorder is the mock recorder for MockRealtimeUsecase.
+type MockRealtimeUsecaseMockRecorder struct {
+	mock *MockRealtimeUsecase
+}
+
+// NewMockRealtimeUsecase creates a new mock instance.
+func NewMockRealtimeUsecase(ctrl *gomock.Controller) *MockRealtimeUsecase {
+	mock := &MockRealtimeUsecase{ctrl: ctrl}
+	mock.recorder = &MockRealtimeUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRealtimeUsecase) EXPECT() *MockRealtimeUsecaseMockRecorder {
+	return m.recorder
+}
+
+// SubscribeBuyer mocks base method.
+func (m *MockRealtimeUsecase) SubscribeBuyer(ctx context.Context, userID uuid.UUID) (domain.RealtimeSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeBuyer", ctx, userID)
+	ret0, _ := ret[0].(domain.RealtimeSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeBuyer indicates an expected call of SubscribeBuyer.
+func (mr *MockRealtimeUsecaseMockRecorder) SubscribeBuyer(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBuyer", reflect.TypeOf((*MockRealtimeUsecase)(nil).SubscribeBuyer), ctx, userID)
+}
+
+// SubscribeShop mocks base method.
+func (m *MockRealtimeUsecase) SubscribeShop(ctx context.Context, userID uuid.UUID) (domain.RealtimeSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeShop", ctx, userID)
+	ret0, _ := ret[0].(domain.RealtimeSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeShop indicates an expected call of SubscribeShop.
+func (mr *MockRealtimeUsecaseMockRecorder) SubscribeShop(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeShop", reflect.TypeOf((*MockRealtimeUsecase)(nil).SubscribeShop), ctx, userID)
+}
@@ -0,0 +1,556 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/refund.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/refund.go -destination=domain/mock/mock_refund.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRefundUsecase is a mock of RefundUsecase interface.
+type MockRefundUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockRefundUsecaseMockRecorder is the mock recorder for MockRefundUsecase.
+type MockRefundUsecaseMockRecorder struct {
+	mock *MockRefundUsecase
+}
+
+// NewMockRefundUsecase creates a new mock instance.
+func NewMockRefundUsecase(ctrl *gomock.Controller) *MockRefundUsecase {
+	mock := &MockRefundUsecase{ctrl: ctrl}
+	mock.recorder = &MockRefundUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundUsecase) EXPECT() *MockRefundUsecaseMockRecorder {
+	return m.recorder
+}
+
+// AcceptCounterOffer mocks base method.
+func (m *MockRefundUsecase) AcceptCounterOffer(ctx context.Context, userID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptCounterOffer", ctx, userID, refundID)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptCounterOffer indicates an expected call of AcceptCounterOffer.
+func (mr *MockRefundUsecaseMockRecorder) AcceptCounterOffer(ctx, userID, refundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptCounterOffer", reflect.TypeOf((*MockRefundUsecase)(nil).AcceptCounterOffer), ctx, userID, refundID)
+}
+
+// AddRefundEvidence mocks base method.
+func (m *MockRefundUsecase) AddRefundEvidence(ctx context.Context, userID, refundID uuid.UUID, req entity.AddRefundEvidenceRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRefundEvidence", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddRefundEvidence indicates an expected call of AddRefundEvidence.
+func (mr *MockRefundUsecaseMockRecorder) AddRefundEvidence(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRefundEvidence", reflect.TypeOf((*MockRefundUsecase)(nil).AddRefundEvidence), ctx, userID, refundID, req)
+}
+
+// ApproveRefund mocks base method.
+func (m *MockRefundUsecase) ApproveRefund(ctx context.Context, userID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveRefund", ctx, userID, refundID)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveRefund indicates an expected call of ApproveRefund.
+func (mr *MockRefundUsecaseMockRecorder) ApproveRefund(ctx, userID, refundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveRefund", reflect.TypeOf((*MockRefundUsecase)(nil).ApproveRefund), ctx, userID, refundID)
+}
+
+// CompleteRefund mocks base method.
+func (m *MockRefundUsecase) CompleteRefund(ctx context.Context, userID, refundID uuid.UUID, req entity.CompleteRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRefund", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteRefund indicates an expected call of CompleteRefund.
+func (mr *MockRefundUsecaseMockRecorder) CompleteRefund(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRefund", reflect.TypeOf((*MockRefundUsecase)(nil).CompleteRefund), ctx, userID, refundID, req)
+}
+
+// CounterOfferRefund mocks base method.
+func (m *MockRefundUsecase) CounterOfferRefund(ctx context.Context, userID, refundID uuid.UUID, req entity.CounterOfferRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CounterOfferRefund", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CounterOfferRefund indicates an expected call of CounterOfferRefund.
+func (mr *MockRefundUsecaseMockRecorder) CounterOfferRefund(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CounterOfferRefund", reflect.TypeOf((*MockRefundUsecase)(nil).CounterOfferRefund), ctx, userID, refundID, req)
+}
+
+// CreateRefund mocks base method.
+func (m *MockRefundUsecase) CreateRefund(ctx context.Context, userID uuid.UUID, req entity.CreateRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockRefundUsecaseMockRecorder) CreateRefund(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockRefundUsecase)(nil).CreateRefund), ctx, userID, req)
+}
+
+// EscalateShopRefund mocks base method.
+func (m *MockRefundUsecase) EscalateShopRefund(ctx context.Context, userID, refundID uuid.UUID, req entity.EscalateRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateShopRefund", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateShopRefund indicates an expected call of EscalateShopRefund.
+func (mr *MockRefundUsecaseMockRecorder) EscalateShopRefund(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateShopRefund", reflect.TypeOf((*MockRefundUsecase)(nil).EscalateShopRefund), ctx, userID, refundID, req)
+}
+
+// EscalateUserRefund mocks base method.
+func (m *MockRefundUsecase) EscalateUserRefund(ctx context.Context, userID, refundID uuid.UUID, req entity.EscalateRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateUserRefund", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateUserRefund indicates an expected call of EscalateUserRefund.
+func (mr *MockRefundUsecaseMockRecorder) EscalateUserRefund(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateUserRefund", reflect.TypeOf((*MockRefundUsecase)(nil).EscalateUserRefund), ctx, userID, refundID, req)
+}
+
+// GetShopRefund mocks base method.
+func (m *MockRefundUsecase) GetShopRefund(ctx context.Context, userID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopRefund", ctx, userID, refundID)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopRefund indicates an expected call of GetShopRefund.
+func (mr *MockRefundUsecaseMockRecorder) GetShopRefund(ctx, userID, refundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopRefund", reflect.TypeOf((*MockRefundUsecase)(nil).GetShopRefund), ctx, userID, refundID)
+}
+
+// GetUserRefund mocks base method.
+func (m *MockRefundUsecase) GetUserRefund(ctx context.Context, userID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRefund", ctx, userID, refundID)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRefund indicates an expected call of GetUserRefund.
+func (mr *MockRefundUsecaseMockRecorder) GetUserRefund(ctx, userID, refundID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRefund", reflect.TypeOf((*MockRefundUsecase)(nil).GetUserRefund), ctx, userID, refundID)
+}
+
+// ListAdminRefunds mocks base method.
+func (m *MockRefundUsecase) ListAdminRefunds(ctx context.Context, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAdminRefunds", ctx, req)
+	ret0, _ := ret[0].(*entity.RefundListPaginationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAdminRefunds indicates an expected call of ListAdminRefunds.
+func (mr *MockRefundUsecaseMockRecorder) ListAdminRefunds(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAdminRefunds", reflect.TypeOf((*MockRefundUsecase)(nil).ListAdminRefunds), ctx, req)
+}
+
+// ListShopRefunds mocks base method.
+func (m *MockRefundUsecase) ListShopRefunds(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListShopRefunds", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.RefundListPaginationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListShopRefunds indicates an expected call of ListShopRefunds.
+func (mr *MockRefundUsecaseMockRecorder) ListShopRefunds(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListShopRefunds", reflect.TypeOf((*MockRefundUsecase)(nil).ListShopRefunds), ctx, userID, req)
+}
+
+// ListUserRefunds mocks base method.
+func (m *MockRefundUsecase) ListUserRefunds(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRefunds", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.RefundListPaginationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserRefunds indicates an expected call of ListUserRefunds.
+func (mr *MockRefundUsecaseMockRecorder) ListUserRefunds(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRefunds", reflect.TypeOf((*MockRefundUsecase)(nil).ListUserRefunds), ctx, userID, req)
+}
+
+// RejectRefund mocks base method.
+func (m *MockRefundUsecase) RejectRefund(ctx context.Context, userID, refundID uuid.UUID, req entity.RejectRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectRefund", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectRefund indicates an expected call of RejectRefund.
+func (mr *MockRefundUsecaseMockRecorder) RejectRefund(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectRefund", reflect.TypeOf((*MockRefundUsecase)(nil).RejectRefund), ctx, userID, refundID, req)
+}
+
+// RequestRefund mocks base method.
+func (m *MockRefundUsecase) RequestRefund(ctx context.Context, userID uuid.UUID, req entity.RequestRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestRefund", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestRefund indicates an expected call of RequestRefund.
+func (mr *MockRefundUsecaseMockRecorder) RequestRefund(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestRefund", reflect.TypeOf((*MockRefundUsecase)(nil).RequestRefund), ctx, userID, req)
+}
+
+// ResolveRefund mocks base method.
+func (m *MockRefundUsecase) ResolveRefund(ctx context.Context, adminID, refundID uuid.UUID, req entity.ResolveRefundRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRefund", ctx, adminID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveRefund indicates an expected call of ResolveRefund.
+func (mr *MockRefundUsecaseMockRecorder) ResolveRefund(ctx, adminID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRefund", reflect.TypeOf((*MockRefundUsecase)(nil).ResolveRefund), ctx, adminID, refundID, req)
+}
+
+// SubmitRefundBankAccount mocks base method.
+func (m *MockRefundUsecase) SubmitRefundBankAccount(ctx context.Context, userID, refundID uuid.UUID, req entity.SubmitRefundBankAccountRequest) (*entity.RefundResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitRefundBankAccount", ctx, userID, refundID, req)
+	ret0, _ := ret[0].(*entity.RefundResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitRefundBankAccount indicates an expected call of SubmitRefundBankAccount.
+func (mr *MockRefundUsecaseMockRecorder) SubmitRefundBankAccount(ctx, userID, refundID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitRefundBankAccount", reflect.TypeOf((*MockRefundUsecase)(nil).SubmitRefundBankAccount), ctx, userID, refundID, req)
+}
+
+// MockRefundRepository is a mock of RefundRepository interface.
+type MockRefundRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefundRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockRefundRepositoryMockRecorder is the mock recorder for MockRefundRepository.
+type MockRefundRepositoryMockRecorder struct {
+	mock *MockRefundRepository
+}
+
+// NewMockRefundRepository creates a new mock instance.
+func NewMockRefundRepository(ctrl *gomock.Controller) *MockRefundRepository {
+	mock := &MockRefundRepository{ctrl: ctrl}
+	mock.recorder = &MockRefundRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefundRepository) EXPECT() *MockRefundRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcceptCounterOffer mocks base method.
+func (m *MockRefundRepository) AcceptCounterOffer(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptCounterOffer", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptCounterOffer indicates an expected call of AcceptCounterOffer.
+func (mr *MockRefundRepositoryMockRecorder) AcceptCounterOffer(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptCounterOffer", reflect.TypeOf((*MockRefundRepository)(nil).AcceptCounterOffer), ctx, id)
+}
+
+// AddRefundEvidences mocks base method.
+func (m *MockRefundRepository) AddRefundEvidences(ctx context.Context, evidences []*entity.RefundEvidence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRefundEvidences", ctx, evidences)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRefundEvidences indicates an expected call of AddRefundEvidences.
+func (mr *MockRefundRepositoryMockRecorder) AddRefundEvidences(ctx, evidences any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRefundEvidences", reflect.TypeOf((*MockRefundRepository)(nil).AddRefundEvidences), ctx, evidences)
+}
+
+// CompleteRefund mocks base method.
+func (m *MockRefundRepository) CompleteRefund(ctx context.Context, id uuid.UUID, transactionID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRefund", ctx, id, transactionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRefund indicates an expected call of CompleteRefund.
+func (mr *MockRefundRepositoryMockRecorder) CompleteRefund(ctx, id, transactionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRefund", reflect.TypeOf((*MockRefundRepository)(nil).CompleteRefund), ctx, id, transactionID)
+}
+
+// CounterOfferRefund mocks base method.
+func (m *MockRefundRepository) CounterOfferRefund(ctx context.Context, id uuid.UUID, amount float64, note string, responseDueAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CounterOfferRefund", ctx, id, amount, note, responseDueAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CounterOfferRefund indicates an expected call of CounterOfferRefund.
+func (mr *MockRefundRepositoryMockRecorder) CounterOfferRefund(ctx, id, amount, note, responseDueAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CounterOfferRefund", reflect.TypeOf((*MockRefundRepository)(nil).CounterOfferRefund), ctx, id, amount, note, responseDueAt)
+}
+
+// CreateRefund mocks base method.
+func (m *MockRefundRepository) CreateRefund(ctx context.Context, refund *entity.Refund) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", ctx, refund)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockRefundRepositoryMockRecorder) CreateRefund(ctx, refund any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockRefundRepository)(nil).CreateRefund), ctx, refund)
+}
+
+// EscalateRefund mocks base method.
+func (m *MockRefundRepository) EscalateRefund(ctx context.Context, id uuid.UUID, fromStatusID uint32, escalatedBy uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateRefund", ctx, id, fromStatusID, escalatedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EscalateRefund indicates an expected call of EscalateRefund.
+func (mr *MockRefundRepositoryMockRecorder) EscalateRefund(ctx, id, fromStatusID, escalatedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateRefund", reflect.TypeOf((*MockRefundRepository)(nil).EscalateRefund), ctx, id, fromStatusID, escalatedBy)
+}
+
+// GetRefundByID mocks base method.
+func (m *MockRefundRepository) GetRefundByID(ctx context.Context, id uuid.UUID) (*entity.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundByID", ctx, id)
+	ret0, _ := ret[0].(*entity.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundByID indicates an expected call of GetRefundByID.
+func (mr *MockRefundRepositoryMockRecorder) GetRefundByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundByID", reflect.TypeOf((*MockRefundRepository)(nil).GetRefundByID), ctx, id)
+}
+
+// GetRefundedAmount mocks base method.
+func (m *MockRefundRepository) GetRefundedAmount(ctx context.Context, shopOrderID uuid.UUID) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundedAmount", ctx, shopOrderID)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundedAmount indicates an expected call of GetRefundedAmount.
+func (mr *MockRefundRepositoryMockRecorder) GetRefundedAmount(ctx, shopOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundedAmount", reflect.TypeOf((*MockRefundRepository)(nil).GetRefundedAmount), ctx, shopOrderID)
+}
+
+// HasOpenRefund mocks base method.
+func (m *MockRefundRepository) HasOpenRefund(ctx context.Context, shopOrderID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasOpenRefund", ctx, shopOrderID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasOpenRefund indicates an expected call of HasOpenRefund.
+func (mr *MockRefundRepositoryMockRecorder) HasOpenRefund(ctx, shopOrderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasOpenRefund", reflect.TypeOf((*MockRefundRepository)(nil).HasOpenRefund), ctx, shopOrderID)
+}
+
+// ListRefunds mocks base method.
+func (m *MockRefundRepository) ListRefunds(ctx context.Context, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRefunds", ctx, req)
+	ret0, _ := ret[0].([]*entity.Refund)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRefunds indicates an expected call of ListRefunds.
+func (mr *MockRefundRepositoryMockRecorder) ListRefunds(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRefunds", reflect.TypeOf((*MockRefundRepository)(nil).ListRefunds), ctx, req)
+}
+
+// ListRefundsByShopID mocks base method.
+func (m *MockRefundRepository) ListRefundsByShopID(ctx context.Context, shopID uuid.UUID, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRefundsByShopID", ctx, shopID, req)
+	ret0, _ := ret[0].([]*entity.Refund)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRefundsByShopID indicates an expected call of ListRefundsByShopID.
+func (mr *MockRefundRepositoryMockRecorder) ListRefundsByShopID(ctx, shopID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRefundsByShopID", reflect.TypeOf((*MockRefundRepository)(nil).ListRefundsByShopID), ctx, shopID, req)
+}
+
+// ListRefundsByUserID mocks base method.
+func (m *MockRefundRepository) ListRefundsByUserID(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRefundsByUserID", ctx, userID, req)
+	ret0, _ := ret[0].([]*entity.Refund)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListRefundsByUserID indicates an expected call of ListRefundsByUserID.
+func (mr *MockRefundRepositoryMockRecorder) ListRefundsByUserID(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRefundsByUserID", reflect.TypeOf((*MockRefundRepository)(nil).ListRefundsByUserID), ctx, userID, req)
+}
+
+// RejectRefund mocks base method.
+func (m *MockRefundRepository) RejectRefund(ctx context.Context, id uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectRefund", ctx, id, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectRefund indicates an expected call of RejectRefund.
+func (mr *MockRefundRepositoryMockRecorder) RejectRefund(ctx, id, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectRefund", reflect.TypeOf((*MockRefundRepository)(nil).RejectRefund), ctx, id, reason)
+}
+
+// ResolveRefund mocks base method.
+func (m *MockRefundRepository) ResolveRefund(ctx context.Context, id uuid.UUID, statusID uint32, amount float64, note string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRefund", ctx, id, statusID, amount, note)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveRefund indicates an expected call of ResolveRefund.
+func (mr *MockRefundRepositoryMockRecorder) ResolveRefund(ctx, id, statusID, amount, note any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRefund", reflect.TypeOf((*MockRefundRepository)(nil).ResolveRefund), ctx, id, statusID, amount, note)
+}
+
+// UpdateRefundBankAccount mocks base method.
+func (m *MockRefundRepository) UpdateRefundBankAccount(ctx context.Context, id uuid.UUID, bankAccount, bankName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRefundBankAccount", ctx, id, bankAccount, bankName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRefundBankAccount indicates an expected call of UpdateRefundBankAccount.
+func (mr *MockRefundRepositoryMockRecorder) UpdateRefundBankAccount(ctx, id, bankAccount, bankName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRefundBankAccount", reflect.TypeOf((*MockRefundRepository)(nil).UpdateRefundBankAccount), ctx, id, bankAccount, bankName)
+}
+
+// UpdateRefundStatus mocks base method.
+func (m *MockRefundRepository) UpdateRefundStatus(ctx context.Context, id uuid.UUID, fromStatusID, statusID uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRefundStatus", ctx, id, fromStatusID, statusID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRefundStatus indicates an expected call of UpdateRefundStatus.
+func (mr *MockRefundRepositoryMockRecorder) UpdateRefundStatus(ctx, id, fromStatusID, statusID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRefundStatus", reflect.TypeOf((*MockRefundRepository)(nil).UpdateRefundStatus), ctx, id, fromStatusID, statusID)
+}
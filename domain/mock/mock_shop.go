@@ -13,6 +13,7 @@ import (
 	context "context"
 	entity "ecommerce-go-api/entity"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -70,6 +71,21 @@ func (mr *MockShopUsecaseMockRecorder) DeactivateShop(ctx, shopID any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateShop", reflect.TypeOf((*MockShopUsecase)(nil).DeactivateShop), ctx, shopID)
 }
 
+// GetFinanceSummary mocks base method.
+func (m *MockShopUsecase) GetFinanceSummary(ctx context.Context, userID uuid.UUID, req *entity.ShopFinanceRequest) (*entity.ShopFinanceSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinanceSummary", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.ShopFinanceSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFinanceSummary indicates an expected call of GetFinanceSummary.
+func (mr *MockShopUsecaseMockRecorder) GetFinanceSummary(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinanceSummary", reflect.TypeOf((*MockShopUsecase)(nil).GetFinanceSummary), ctx, userID, req)
+}
+
 // GetShopByID mocks base method.
 func (m *MockShopUsecase) GetShopByID(ctx context.Context, shopID uuid.UUID) (*entity.ShopResponse, error) {
 	m.ctrl.T.Helper()
@@ -213,6 +229,21 @@ func (mr *MockShopRepositoryMockRecorder) GetActiveShopCourier(ctx, shopID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveShopCourier", reflect.TypeOf((*MockShopRepository)(nil).GetActiveShopCourier), ctx, shopID)
 }
 
+// GetFinanceSummary mocks base method.
+func (m *MockShopRepository) GetFinanceSummary(ctx context.Context, shopID uuid.UUID, from, to time.Time) (*entity.ShopFinanceSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFinanceSummary", ctx, shopID, from, to)
+	ret0, _ := ret[0].(*entity.ShopFinanceSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFinanceSummary indicates an expected call of GetFinanceSummary.
+func (mr *MockShopRepositoryMockRecorder) GetFinanceSummary(ctx, shopID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFinanceSummary", reflect.TypeOf((*MockShopRepository)(nil).GetFinanceSummary), ctx, shopID, from, to)
+}
+
 // GetShopByID mocks base method.
 func (m *MockShopRepository) GetShopByID(ctx context.Context, id uuid.UUID) (*entity.Shop, error) {
 	m.ctrl.T.Helper()
@@ -243,6 +274,36 @@ func (mr *MockShopRepositoryMockRecorder) GetShopByUserID(ctx, userID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopByUserID", reflect.TypeOf((*MockShopRepository)(nil).GetShopByUserID), ctx, userID)
 }
 
+// GetShopMember mocks base method.
+func (m *MockShopRepository) GetShopMember(ctx context.Context, shopID, userID uuid.UUID) (*entity.ShopMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopMember", ctx, shopID, userID)
+	ret0, _ := ret[0].(*entity.ShopMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopMember indicates an expected call of GetShopMember.
+func (mr *MockShopRepositoryMockRecorder) GetShopMember(ctx, shopID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopMember", reflect.TypeOf((*MockShopRepository)(nil).GetShopMember), ctx, shopID, userID)
+}
+
+// GetShopMemberByUserID mocks base method.
+func (m *MockShopRepository) GetShopMemberByUserID(ctx context.Context, userID uuid.UUID) (*entity.ShopMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetShopMemberByUserID", ctx, userID)
+	ret0, _ := ret[0].(*entity.ShopMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetShopMemberByUserID indicates an expected call of GetShopMemberByUserID.
+func (mr *MockShopRepositoryMockRecorder) GetShopMemberByUserID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetShopMemberByUserID", reflect.TypeOf((*MockShopRepository)(nil).GetShopMemberByUserID), ctx, userID)
+}
+
 // GetShopsByIDs mocks base method.
 func (m *MockShopRepository) GetShopsByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Shop, error) {
 	m.ctrl.T.Helper()
@@ -330,3 +391,314 @@ func (mr *MockShopRepositoryMockRecorder) UpdateStatus(ctx, id, isActive any) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockShopRepository)(nil).UpdateStatus), ctx, id, isActive)
 }
+
+// MockShopMemberUsecase is a mock of ShopMemberUsecase interface.
+type MockShopMemberUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopMemberUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockShopMemberUsecaseMockRecorder is the mock recorder for MockShopMemberUsecase.
+type MockShopMemberUsecaseMockRecorder struct {
+	mock *MockShopMemberUsecase
+}
+
+// NewMockShopMemberUsecase creates a new mock instance.
+func NewMockShopMemberUsecase(ctrl *gomock.Controller) *MockShopMemberUsecase {
+	mock := &MockShopMemberUsecase{ctrl: ctrl}
+	mock.recorder = &MockShopMemberUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopMemberUsecase) EXPECT() *MockShopMemberUsecaseMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockShopMemberUsecase) AcceptInvitation(ctx context.Context, userID uuid.UUID, req entity.AcceptShopInvitationRequest) (*entity.ShopMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.ShopMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockShopMemberUsecaseMockRecorder) AcceptInvitation(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockShopMemberUsecase)(nil).AcceptInvitation), ctx, userID, req)
+}
+
+// GetMyMembership mocks base method.
+func (m *MockShopMemberUsecase) GetMyMembership(ctx context.Context, userID uuid.UUID) (*entity.ShopMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMyMembership", ctx, userID)
+	ret0, _ := ret[0].(*entity.ShopMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMyMembership indicates an expected call of GetMyMembership.
+func (mr *MockShopMemberUsecaseMockRecorder) GetMyMembership(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMyMembership", reflect.TypeOf((*MockShopMemberUsecase)(nil).GetMyMembership), ctx, userID)
+}
+
+// InviteMember mocks base method.
+func (m *MockShopMemberUsecase) InviteMember(ctx context.Context, userID uuid.UUID, req entity.InviteShopMemberRequest) (*entity.ShopInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InviteMember", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.ShopInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InviteMember indicates an expected call of InviteMember.
+func (mr *MockShopMemberUsecaseMockRecorder) InviteMember(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InviteMember", reflect.TypeOf((*MockShopMemberUsecase)(nil).InviteMember), ctx, userID, req)
+}
+
+// ListInvitations mocks base method.
+func (m *MockShopMemberUsecase) ListInvitations(ctx context.Context, userID uuid.UUID) ([]*entity.ShopInvitationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInvitations", ctx, userID)
+	ret0, _ := ret[0].([]*entity.ShopInvitationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInvitations indicates an expected call of ListInvitations.
+func (mr *MockShopMemberUsecaseMockRecorder) ListInvitations(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInvitations", reflect.TypeOf((*MockShopMemberUsecase)(nil).ListInvitations), ctx, userID)
+}
+
+// ListMembers mocks base method.
+func (m *MockShopMemberUsecase) ListMembers(ctx context.Context, userID uuid.UUID) ([]*entity.ShopMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, userID)
+	ret0, _ := ret[0].([]*entity.ShopMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockShopMemberUsecaseMockRecorder) ListMembers(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockShopMemberUsecase)(nil).ListMembers), ctx, userID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockShopMemberUsecase) RemoveMember(ctx context.Context, userID, memberUserID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, userID, memberUserID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockShopMemberUsecaseMockRecorder) RemoveMember(ctx, userID, memberUserID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockShopMemberUsecase)(nil).RemoveMember), ctx, userID, memberUserID)
+}
+
+// RevokeInvitation mocks base method.
+func (m *MockShopMemberUsecase) RevokeInvitation(ctx context.Context, userID, invitationID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeInvitation", ctx, userID, invitationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeInvitation indicates an expected call of RevokeInvitation.
+func (mr *MockShopMemberUsecaseMockRecorder) RevokeInvitation(ctx, userID, invitationID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeInvitation", reflect.TypeOf((*MockShopMemberUsecase)(nil).RevokeInvitation), ctx, userID, invitationID)
+}
+
+// UpdateMember mocks base method.
+func (m *MockShopMemberUsecase) UpdateMember(ctx context.Context, userID, memberUserID uuid.UUID, req entity.UpdateShopMemberRequest) (*entity.ShopMemberResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMember", ctx, userID, memberUserID, req)
+	ret0, _ := ret[0].(*entity.ShopMemberResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMember indicates an expected call of UpdateMember.
+func (mr *MockShopMemberUsecaseMockRecorder) UpdateMember(ctx, userID, memberUserID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMember", reflect.TypeOf((*MockShopMemberUsecase)(nil).UpdateMember), ctx, userID, memberUserID, req)
+}
+
+// MockShopMemberRepository is a mock of ShopMemberRepository interface.
+type MockShopMemberRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopMemberRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockShopMemberRepositoryMockRecorder is the mock recorder for MockShopMemberRepository.
+type MockShopMemberRepositoryMockRecorder struct {
+	mock *MockShopMemberRepository
+}
+
+// NewMockShopMemberRepository creates a new mock instance.
+func NewMockShopMemberRepository(ctrl *gomock.Controller) *MockShopMemberRepository {
+	mock := &MockShopMemberRepository{ctrl: ctrl}
+	mock.recorder = &MockShopMemberRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopMemberRepository) EXPECT() *MockShopMemberRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AcceptInvitation mocks base method.
+func (m *MockShopMemberRepository) AcceptInvitation(ctx context.Context, invitation *entity.ShopInvitation, member *entity.ShopMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptInvitation", ctx, invitation, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AcceptInvitation indicates an expected call of AcceptInvitation.
+func (mr *MockShopMemberRepositoryMockRecorder) AcceptInvitation(ctx, invitation, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptInvitation", reflect.TypeOf((*MockShopMemberRepository)(nil).AcceptInvitation), ctx, invitation, member)
+}
+
+// CountSeats mocks base method.
+func (m *MockShopMemberRepository) CountSeats(ctx context.Context, shopID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSeats", ctx, shopID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSeats indicates an expected call of CountSeats.
+func (mr *MockShopMemberRepositoryMockRecorder) CountSeats(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSeats", reflect.TypeOf((*MockShopMemberRepository)(nil).CountSeats), ctx, shopID)
+}
+
+// CreateInvitation mocks base method.
+func (m *MockShopMemberRepository) CreateInvitation(ctx context.Context, invitation *entity.ShopInvitation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInvitation", ctx, invitation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInvitation indicates an expected call of CreateInvitation.
+func (mr *MockShopMemberRepositoryMockRecorder) CreateInvitation(ctx, invitation any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInvitation", reflect.TypeOf((*MockShopMemberRepository)(nil).CreateInvitation), ctx, invitation)
+}
+
+// DeleteInvitation mocks base method.
+func (m *MockShopMemberRepository) DeleteInvitation(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteInvitation", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteInvitation indicates an expected call of DeleteInvitation.
+func (mr *MockShopMemberRepositoryMockRecorder) DeleteInvitation(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteInvitation", reflect.TypeOf((*MockShopMemberRepository)(nil).DeleteInvitation), ctx, id)
+}
+
+// GetInvitationByID mocks base method.
+func (m *MockShopMemberRepository) GetInvitationByID(ctx context.Context, id uuid.UUID) (*entity.ShopInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationByID", ctx, id)
+	ret0, _ := ret[0].(*entity.ShopInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationByID indicates an expected call of GetInvitationByID.
+func (mr *MockShopMemberRepositoryMockRecorder) GetInvitationByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationByID", reflect.TypeOf((*MockShopMemberRepository)(nil).GetInvitationByID), ctx, id)
+}
+
+// GetInvitationByTokenHash mocks base method.
+func (m *MockShopMemberRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entity.ShopInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInvitationByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*entity.ShopInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInvitationByTokenHash indicates an expected call of GetInvitationByTokenHash.
+func (mr *MockShopMemberRepositoryMockRecorder) GetInvitationByTokenHash(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInvitationByTokenHash", reflect.TypeOf((*MockShopMemberRepository)(nil).GetInvitationByTokenHash), ctx, tokenHash)
+}
+
+// ListMembers mocks base method.
+func (m *MockShopMemberRepository) ListMembers(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMembers", ctx, shopID)
+	ret0, _ := ret[0].([]*entity.ShopMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMembers indicates an expected call of ListMembers.
+func (mr *MockShopMemberRepositoryMockRecorder) ListMembers(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMembers", reflect.TypeOf((*MockShopMemberRepository)(nil).ListMembers), ctx, shopID)
+}
+
+// ListPendingInvitations mocks base method.
+func (m *MockShopMemberRepository) ListPendingInvitations(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopInvitation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingInvitations", ctx, shopID)
+	ret0, _ := ret[0].([]*entity.ShopInvitation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingInvitations indicates an expected call of ListPendingInvitations.
+func (mr *MockShopMemberRepositoryMockRecorder) ListPendingInvitations(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingInvitations", reflect.TypeOf((*MockShopMemberRepository)(nil).ListPendingInvitations), ctx, shopID)
+}
+
+// RemoveMember mocks base method.
+func (m *MockShopMemberRepository) RemoveMember(ctx context.Context, member *entity.ShopMember) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockShopMemberRepositoryMockRecorder) RemoveMember(ctx, member any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockShopMemberRepository)(nil).RemoveMember), ctx, member)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockShopMemberRepository) UpdateMemberRole(ctx context.Context, id uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, id, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockShopMemberRepositoryMockRecorder) UpdateMemberRole(ctx, id, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockShopMemberRepository)(nil).UpdateMemberRole), ctx, id, role)
+}
@@ -0,0 +1,391 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: domain/webhook.go
+//
+// Generated by this command:
+//
+//	mockgen -source=domain/webhook.go -destination=domain/mock/mock_webhook.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	entity "ecommerce-go-api/entity"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockShopWebhookUsecase is a mock of ShopWebhookUsecase interface.
+type MockShopWebhookUsecase struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopWebhookUsecaseMockRecorder
+	isgomock struct{}
+}
+
+// MockShopWebhookUsecaseMockRecorder is the mock recorder for MockShopWebhookUsecase.
+type MockShopWebhookUsecaseMockRecorder struct {
+	mock *MockShopWebhookUsecase
+}
+
+// NewMockShopWebhookUsecase creates a new mock instance.
+func NewMockShopWebhookUsecase(ctrl *gomock.Controller) *MockShopWebhookUsecase {
+	mock := &MockShopWebhookUsecase{ctrl: ctrl}
+	mock.recorder = &MockShopWebhookUsecaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopWebhookUsecase) EXPECT() *MockShopWebhookUsecaseMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockShopWebhookUsecase) CreateWebhook(ctx context.Context, userID uuid.UUID, req entity.CreateShopWebhookRequest) (*entity.ShopWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, userID, req)
+	ret0, _ := ret[0].(*entity.ShopWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockShopWebhookUsecaseMockRecorder) CreateWebhook(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockShopWebhookUsecase)(nil).CreateWebhook), ctx, userID, req)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockShopWebhookUsecase) DeleteWebhook(ctx context.Context, userID, webhookID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userID, webhookID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockShopWebhookUsecaseMockRecorder) DeleteWebhook(ctx, userID, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockShopWebhookUsecase)(nil).DeleteWebhook), ctx, userID, webhookID)
+}
+
+// GetDelivery mocks base method.
+func (m *MockShopWebhookUsecase) GetDelivery(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelivery", ctx, userID, webhookID, deliveryID)
+	ret0, _ := ret[0].(*entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelivery indicates an expected call of GetDelivery.
+func (mr *MockShopWebhookUsecaseMockRecorder) GetDelivery(ctx, userID, webhookID, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelivery", reflect.TypeOf((*MockShopWebhookUsecase)(nil).GetDelivery), ctx, userID, webhookID, deliveryID)
+}
+
+// GetWebhook mocks base method.
+func (m *MockShopWebhookUsecase) GetWebhook(ctx context.Context, userID, webhookID uuid.UUID) (*entity.ShopWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, userID, webhookID)
+	ret0, _ := ret[0].(*entity.ShopWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockShopWebhookUsecaseMockRecorder) GetWebhook(ctx, userID, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockShopWebhookUsecase)(nil).GetWebhook), ctx, userID, webhookID)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockShopWebhookUsecase) ListDeliveries(ctx context.Context, userID, webhookID uuid.UUID, req entity.WebhookDeliveryListRequest) (*entity.WebhookDeliveryListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, userID, webhookID, req)
+	ret0, _ := ret[0].(*entity.WebhookDeliveryListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockShopWebhookUsecaseMockRecorder) ListDeliveries(ctx, userID, webhookID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockShopWebhookUsecase)(nil).ListDeliveries), ctx, userID, webhookID, req)
+}
+
+// ListWebhooks mocks base method.
+func (m *MockShopWebhookUsecase) ListWebhooks(ctx context.Context, userID uuid.UUID) ([]*entity.ShopWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]*entity.ShopWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockShopWebhookUsecaseMockRecorder) ListWebhooks(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockShopWebhookUsecase)(nil).ListWebhooks), ctx, userID)
+}
+
+// RedeliverDelivery mocks base method.
+func (m *MockShopWebhookUsecase) RedeliverDelivery(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, userID, webhookID, deliveryID)
+	ret0, _ := ret[0].(*entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockShopWebhookUsecaseMockRecorder) RedeliverDelivery(ctx, userID, webhookID, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockShopWebhookUsecase)(nil).RedeliverDelivery), ctx, userID, webhookID, deliveryID)
+}
+
+// RotateWebhookSecret mocks base method.
+func (m *MockShopWebhookUsecase) RotateWebhookSecret(ctx context.Context, userID, webhookID uuid.UUID) (*entity.ShopWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateWebhookSecret", ctx, userID, webhookID)
+	ret0, _ := ret[0].(*entity.ShopWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateWebhookSecret indicates an expected call of RotateWebhookSecret.
+func (mr *MockShopWebhookUsecaseMockRecorder) RotateWebhookSecret(ctx, userID, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateWebhookSecret", reflect.TypeOf((*MockShopWebhookUsecase)(nil).RotateWebhookSecret), ctx, userID, webhookID)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockShopWebhookUsecase) UpdateWebhook(ctx context.Context, userID, webhookID uuid.UUID, req entity.UpdateShopWebhookRequest) (*entity.ShopWebhookResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, userID, webhookID, req)
+	ret0, _ := ret[0].(*entity.ShopWebhookResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockShopWebhookUsecaseMockRecorder) UpdateWebhook(ctx, userID, webhookID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockShopWebhookUsecase)(nil).UpdateWebhook), ctx, userID, webhookID, req)
+}
+
+// MockShopWebhookRepository is a mock of ShopWebhookRepository interface.
+type MockShopWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockShopWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockShopWebhookRepositoryMockRecorder is the mock recorder for MockShopWebhookRepository.
+type MockShopWebhookRepositoryMockRecorder struct {
+	mock *MockShopWebhookRepository
+}
+
+// NewMockShopWebhookRepository creates a new mock instance.
+func NewMockShopWebhookRepository(ctrl *gomock.Controller) *MockShopWebhookRepository {
+	mock := &MockShopWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockShopWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockShopWebhookRepository) EXPECT() *MockShopWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueDeliveries mocks base method.
+func (m *MockShopWebhookRepository) ClaimDueDeliveries(ctx context.Context, claim entity.JobClaim) ([]*entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueDeliveries", ctx, claim)
+	ret0, _ := ret[0].([]*entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueDeliveries indicates an expected call of ClaimDueDeliveries.
+func (mr *MockShopWebhookRepositoryMockRecorder) ClaimDueDeliveries(ctx, claim any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueDeliveries", reflect.TypeOf((*MockShopWebhookRepository)(nil).ClaimDueDeliveries), ctx, claim)
+}
+
+// CountWebhooksByShopID mocks base method.
+func (m *MockShopWebhookRepository) CountWebhooksByShopID(ctx context.Context, shopID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhooksByShopID", ctx, shopID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhooksByShopID indicates an expected call of CountWebhooksByShopID.
+func (mr *MockShopWebhookRepositoryMockRecorder) CountWebhooksByShopID(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhooksByShopID", reflect.TypeOf((*MockShopWebhookRepository)(nil).CountWebhooksByShopID), ctx, shopID)
+}
+
+// CreateDeliveries mocks base method.
+func (m *MockShopWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeliveries", ctx, deliveries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeliveries indicates an expected call of CreateDeliveries.
+func (mr *MockShopWebhookRepositoryMockRecorder) CreateDeliveries(ctx, deliveries any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeliveries", reflect.TypeOf((*MockShopWebhookRepository)(nil).CreateDeliveries), ctx, deliveries)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockShopWebhookRepository) CreateWebhook(ctx context.Context, webhook *entity.ShopWebhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockShopWebhookRepositoryMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockShopWebhookRepository)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockShopWebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockShopWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockShopWebhookRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// FinishAttempt mocks base method.
+func (m *MockShopWebhookRepository) FinishAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishAttempt", ctx, delivery, attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishAttempt indicates an expected call of FinishAttempt.
+func (mr *MockShopWebhookRepositoryMockRecorder) FinishAttempt(ctx, delivery, attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishAttempt", reflect.TypeOf((*MockShopWebhookRepository)(nil).FinishAttempt), ctx, delivery, attempt)
+}
+
+// GetDeliveryByID mocks base method.
+func (m *MockShopWebhookRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryByID", ctx, id)
+	ret0, _ := ret[0].(*entity.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryByID indicates an expected call of GetDeliveryByID.
+func (mr *MockShopWebhookRepositoryMockRecorder) GetDeliveryByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryByID", reflect.TypeOf((*MockShopWebhookRepository)(nil).GetDeliveryByID), ctx, id)
+}
+
+// GetWebhookByID mocks base method.
+func (m *MockShopWebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (*entity.ShopWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", ctx, id)
+	ret0, _ := ret[0].(*entity.ShopWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockShopWebhookRepositoryMockRecorder) GetWebhookByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockShopWebhookRepository)(nil).GetWebhookByID), ctx, id)
+}
+
+// ListDeliveries mocks base method.
+func (m *MockShopWebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, req entity.WebhookDeliveryListRequest) ([]*entity.WebhookDelivery, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeliveries", ctx, webhookID, req)
+	ret0, _ := ret[0].([]*entity.WebhookDelivery)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeliveries indicates an expected call of ListDeliveries.
+func (mr *MockShopWebhookRepositoryMockRecorder) ListDeliveries(ctx, webhookID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeliveries", reflect.TypeOf((*MockShopWebhookRepository)(nil).ListDeliveries), ctx, webhookID, req)
+}
+
+// ListSubscribedWebhooks mocks base method.
+func (m *MockShopWebhookRepository) ListSubscribedWebhooks(ctx context.Context, shopID uuid.UUID, eventType string) ([]*entity.ShopWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscribedWebhooks", ctx, shopID, eventType)
+	ret0, _ := ret[0].([]*entity.ShopWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscribedWebhooks indicates an expected call of ListSubscribedWebhooks.
+func (mr *MockShopWebhookRepositoryMockRecorder) ListSubscribedWebhooks(ctx, shopID, eventType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscribedWebhooks", reflect.TypeOf((*MockShopWebhookRepository)(nil).ListSubscribedWebhooks), ctx, shopID, eventType)
+}
+
+// ListWebhooksByShopID mocks base method.
+func (m *MockShopWebhookRepository) ListWebhooksByShopID(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooksByShopID", ctx, shopID)
+	ret0, _ := ret[0].([]*entity.ShopWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooksByShopID indicates an expected call of ListWebhooksByShopID.
+func (mr *MockShopWebhookRepositoryMockRecorder) ListWebhooksByShopID(ctx, shopID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooksByShopID", reflect.TypeOf((*MockShopWebhookRepository)(nil).ListWebhooksByShopID), ctx, shopID)
+}
+
+// RequeueDelivery mocks base method.
+func (m *MockShopWebhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueDelivery", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueDelivery indicates an expected call of RequeueDelivery.
+func (mr *MockShopWebhookRepositoryMockRecorder) RequeueDelivery(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueDelivery", reflect.TypeOf((*MockShopWebhookRepository)(nil).RequeueDelivery), ctx, id)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockShopWebhookRepository) UpdateWebhook(ctx context.Context, id uuid.UUID, updates map[string]any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, id, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockShopWebhookRepositoryMockRecorder) UpdateWebhook(ctx, id, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockShopWebhookRepository)(nil).UpdateWebhook), ctx, id, updates)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/entity"
+)
+
+// Notifier delivers an email to its recipient.
+type Notifier interface {
+	Send(ctx context.Context, email entity.Email) error
+}
+
+type NotificationUsecase interface {
+	ListNotifications(ctx context.Context, userID uuid.UUID, req entity.NotificationListRequest) (*entity.NotificationListResponse, error)
+	GetUnreadCount(ctx context.Context, userID uuid.UUID) (*entity.NotificationUnreadCountResponse, error)
+	MarkNotificationRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) (*entity.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (*entity.NotificationMarkAllReadResponse, error)
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationPreferenceResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req entity.UpdateNotificationPreferencesRequest) ([]*entity.NotificationPreferenceResponse, error)
+}
+
+type NotificationRepository interface {
+	// QueueEmails adds emails to the queue, skipping any already queued with the same
+	// dedupe key.
+	QueueEmails(ctx context.Context, emails []*entity.EmailMessage) error
+	ClaimDueEmails(ctx context.Context, claim entity.JobClaim) ([]*entity.EmailMessage, error)
+	// FinishEmail stores the outcome of a send attempt and releases the claim.
+	FinishEmail(ctx context.Context, email *entity.EmailMessage) error
+
+	// CreateNotifications adds notifications to inboxes, skipping any already created
+	// with the same dedupe key.
+	CreateNotifications(ctx context.Context, notifications []*entity.Notification) error
+	GetNotificationByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error)
+	ListNotifications(ctx context.Context, userID uuid.UUID, req entity.NotificationListRequest) ([]*entity.Notification, int64, error)
+	CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error)
+	// MarkNotificationsRead marks the user's unread notifications as read: all of them
+	// when no IDs are given. It returns how many were marked.
+	MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids ...uuid.UUID) (int64, error)
+
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationPreference, error)
+	// GetPreference returns nil when the user has no preference for the event.
+	GetPreference(ctx context.Context, userID uuid.UUID, eventType string) (*entity.NotificationPreference, error)
+	UpsertPreferences(ctx context.Context, preferences []*entity.NotificationPreference) error
+}
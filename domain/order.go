@@ -25,9 +25,15 @@ type OrderUsecase interface {
 	UpdateShopOrderStatus(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID, req entity.UpdateOrderStatusRequest) error
 	CancelShopOrder(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID, req entity.CancelOrderRequest) error
 	AddShipment(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID, req entity.AddShipmentRequest) (*entity.ShipmentResponse, error)
-	GetShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShipmentResponse, error)
-	GetShopShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShipmentResponse, error)
+	GetShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShopOrderTrackingResponse, error)
+	GetShopShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShopOrderTrackingResponse, error)
+	GetShippingLabel(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) ([]byte, error)
+	GetShippingLabels(ctx context.Context, userID uuid.UUID, shopOrderIDs []uuid.UUID) ([]byte, error)
 	ApproveOrder(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) error
+	ScheduleDeliveryReattempt(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID, shipmentID uuid.UUID, req entity.DeliveryReattemptRequest) (*entity.ShipmentResponse, error)
+
+	HandleCourierWebhook(ctx context.Context, courierID uint32, signature string, payload []byte) error
+	SyncShipmentTracking(ctx context.Context) (int, error)
 }
 
 type OrderRepository interface {
@@ -52,22 +58,32 @@ type OrderRepository interface {
 	GetShopOrderByID(ctx context.Context, id uuid.UUID) (*entity.ShopOrder, error)
 	UpdateShopOrderStatus(ctx context.Context, id uuid.UUID, OrderStatusID uint32) error
 	CancelShopOrder(ctx context.Context, id uuid.UUID, reason string) error
+	CancelShopOrderWithRefund(ctx context.Context, id uuid.UUID, reason string, refund *entity.Refund) (bool, error)
+	CreateShopOrderRefund(ctx context.Context, refund *entity.Refund) error
+	UpdatePaymentRefundStatus(ctx context.Context, paymentID uuid.UUID) error
 
 	AddShipment(ctx context.Context, s *entity.Shipment) error
-	GetShipmentByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) (*entity.Shipment, error)
+	GetShipmentsByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) ([]*entity.Shipment, error)
 	UpdateShipmentStatusByShopOrderID(ctx context.Context, shopOrderID uuid.UUID, shipmentStatusID uint32) error
+	GetShipmentByTrackingNo(ctx context.Context, courierID uint32, trackingNo string) (*entity.Shipment, error)
+	RecordShipmentEvent(ctx context.Context, event *entity.ShipmentEvent) (bool, error)
+	GetCourierByID(ctx context.Context, id uint32) (*entity.Courier, error)
+	ListShipmentsForTrackingSync(ctx context.Context, limit int) ([]*entity.Shipment, error)
+	CreateDeliveryReattempt(ctx context.Context, reattempt *entity.DeliveryReattempt) error
 
 	// Payment
 	CreatePayment(ctx context.Context, payment *entity.Payment) error
 	GetPaymentByOrderID(ctx context.Context, orderID uuid.UUID) (*entity.Payment, error)
 	GetPaymentByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error)
 	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, paymentStatusID uint32, paidAt *time.Time) error
-	ListExpiredPayments(ctx context.Context) ([]*entity.Payment, error)
+	ClaimExpiredPayments(ctx context.Context, claim entity.JobClaim) ([]*entity.Payment, error)
 
 	// OrderLog
 	CreateOrderLog(ctx context.Context, log *entity.OrderLog) error
 	GetOrderLogsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entity.OrderLog, error)
 	GetOrderLogsByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) ([]*entity.OrderLog, error)
 
-	ListDeliveredOrdersOlderThan(ctx context.Context, days int) ([]*entity.ShopOrder, error)
+	ClaimDeliveredOrdersToAutoComplete(ctx context.Context, defaultDays int, claim entity.JobClaim) ([]*entity.ShopOrder, error)
+	ClaimDeliveredOrdersToRemind(ctx context.Context, defaultDays int, claim entity.JobClaim) ([]*entity.ShopOrder, error)
+	MarkAutoCompleteReminded(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+
+	"ecommerce-go-api/entity"
+
+	"github.com/google/uuid"
+)
+
+// Transactor runs fn in a database transaction. Repositories called with the ctx passed
+// to fn join the transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type OutboxUsecase interface {
+	ListEvents(ctx context.Context, req entity.OutboxEventListRequest) (*entity.OutboxEventListResponse, error)
+	RetryEvent(ctx context.Context, id uuid.UUID) (*entity.OutboxEvent, error)
+}
+
+type OutboxRepository interface {
+	// Add writes events to the outbox, inside the transaction carried by ctx if any.
+	Add(ctx context.Context, events ...*entity.OutboxEvent) error
+	ClaimPendingEvents(ctx context.Context, claim entity.JobClaim) ([]*entity.OutboxEvent, error)
+	// FinishDelivery stores the outcome of a delivery attempt and releases the claim.
+	FinishDelivery(ctx context.Context, event *entity.OutboxEvent) error
+
+	ListEvents(ctx context.Context, req entity.OutboxEventListRequest) ([]*entity.OutboxEvent, int64, error)
+	GetEventByID(ctx context.Context, id uuid.UUID) (*entity.OutboxEvent, error)
+	RequeueDeadEvent(ctx context.Context, id uuid.UUID) error
+}
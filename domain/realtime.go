@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/entity"
+)
+
+// RealtimeSubscription receives the events published to one topic. Events is closed when
+// the subscription is dropped, e.g. because it fell behind or the server is stopping.
+type RealtimeSubscription interface {
+	Events() <-chan *entity.RealtimeEvent
+	Close()
+}
+
+// RealtimeBroker delivers events to the topic's subscribers on every instance.
+type RealtimeBroker interface {
+	Publish(ctx context.Context, topic string, event *entity.RealtimeEvent) error
+	Subscribe(topic string) RealtimeSubscription
+}
+
+type RealtimeUsecase interface {
+	// SubscribeBuyer streams status changes of the user's orders.
+	SubscribeBuyer(ctx context.Context, userID uuid.UUID) (RealtimeSubscription, error)
+	// SubscribeShop streams new orders, payments and cancellations of the shop owned by
+	// the user.
+	SubscribeShop(ctx context.Context, userID uuid.UUID) (RealtimeSubscription, error)
+}
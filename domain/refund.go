@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"ecommerce-go-api/entity"
 
@@ -11,12 +12,45 @@ import (
 type RefundUsecase interface {
 	CreateRefund(ctx context.Context, userID uuid.UUID, req entity.CreateRefundRequest) (*entity.RefundResponse, error)
 	ApproveRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error)
+	RejectRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.RejectRefundRequest) (*entity.RefundResponse, error)
+	CompleteRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.CompleteRefundRequest) (*entity.RefundResponse, error)
+	ListShopRefunds(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error)
+	GetShopRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error)
+	ListUserRefunds(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error)
+	GetUserRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error)
 	SubmitRefundBankAccount(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.SubmitRefundBankAccountRequest) (*entity.RefundResponse, error)
+
+	// Buyer-initiated requests
+	RequestRefund(ctx context.Context, userID uuid.UUID, req entity.RequestRefundRequest) (*entity.RefundResponse, error)
+	AddRefundEvidence(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.AddRefundEvidenceRequest) (*entity.RefundResponse, error)
+	CounterOfferRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.CounterOfferRefundRequest) (*entity.RefundResponse, error)
+	AcceptCounterOffer(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error)
+	EscalateUserRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.EscalateRefundRequest) (*entity.RefundResponse, error)
+	EscalateShopRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.EscalateRefundRequest) (*entity.RefundResponse, error)
+
+	// Admin mediation
+	ListAdminRefunds(ctx context.Context, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error)
+	ResolveRefund(ctx context.Context, adminID uuid.UUID, refundID uuid.UUID, req entity.ResolveRefundRequest) (*entity.RefundResponse, error)
 }
 
 type RefundRepository interface {
 	CreateRefund(ctx context.Context, refund *entity.Refund) error
 	GetRefundByID(ctx context.Context, id uuid.UUID) (*entity.Refund, error)
-	UpdateRefundStatus(ctx context.Context, id uuid.UUID, statusID uint32) error
+	ListRefundsByShopID(ctx context.Context, shopID uuid.UUID, req entity.RefundListRequest) ([]*entity.Refund, int64, error)
+	ListRefundsByUserID(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) ([]*entity.Refund, int64, error)
+	ListRefunds(ctx context.Context, req entity.RefundListRequest) ([]*entity.Refund, int64, error)
+	HasOpenRefund(ctx context.Context, shopOrderID uuid.UUID) (bool, error)
+	// GetRefundedAmount is the total of the shop order's approved and completed refunds.
+	GetRefundedAmount(ctx context.Context, shopOrderID uuid.UUID) (float64, error)
+	// The status changes return errmap.ErrRefundStatusChanged when the refund is no
+	// longer in the status the change starts from.
+	UpdateRefundStatus(ctx context.Context, id uuid.UUID, fromStatusID uint32, statusID uint32) error
+	RejectRefund(ctx context.Context, id uuid.UUID, reason string) error
+	CompleteRefund(ctx context.Context, id uuid.UUID, transactionID string) error
 	UpdateRefundBankAccount(ctx context.Context, id uuid.UUID, bankAccount, bankName string) error
+	AddRefundEvidences(ctx context.Context, evidences []*entity.RefundEvidence) error
+	CounterOfferRefund(ctx context.Context, id uuid.UUID, amount float64, note string, responseDueAt time.Time) error
+	AcceptCounterOffer(ctx context.Context, id uuid.UUID) error
+	EscalateRefund(ctx context.Context, id uuid.UUID, fromStatusID uint32, escalatedBy uuid.UUID) error
+	ResolveRefund(ctx context.Context, id uuid.UUID, statusID uint32, amount float64, note string) error
 }
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"time"
 
 	"ecommerce-go-api/entity"
 
@@ -17,11 +18,18 @@ type ShopUsecase interface {
 	DeactivateShop(ctx context.Context, shopID uuid.UUID) error
 	UpdateShopCouriers(ctx context.Context, userID uuid.UUID, req *entity.UpdateShopCouriersRequest) (*entity.ShopCourierResponse, error)
 	GetShopCouriers(ctx context.Context, userID uuid.UUID) (*entity.ShopCourierResponse, error)
+	GetFinanceSummary(ctx context.Context, userID uuid.UUID, req *entity.ShopFinanceRequest) (*entity.ShopFinanceSummary, error)
 }
 
 type ShopRepository interface {
 	GetShopByID(ctx context.Context, id uuid.UUID) (*entity.Shop, error)
+	// GetShopByUserID returns the shop the user owns or works at.
 	GetShopByUserID(ctx context.Context, userID uuid.UUID) (*entity.Shop, error)
+	// GetShopMemberByUserID returns the user's membership with its shop loaded.
+	GetShopMemberByUserID(ctx context.Context, userID uuid.UUID) (*entity.ShopMember, error)
+	// GetShopMember returns the user's membership of the shop with the shop loaded, or nil
+	// when the user is not a member of it.
+	GetShopMember(ctx context.Context, shopID uuid.UUID, userID uuid.UUID) (*entity.ShopMember, error)
 	GetShopsByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Shop, error)
 	UpdateShop(ctx context.Context, shop *entity.Shop) error
 	ListShops(ctx context.Context, req *entity.ShopListRequest) ([]*entity.Shop, int64, error)
@@ -30,4 +38,42 @@ type ShopRepository interface {
 	GetActiveShopCourier(ctx context.Context, shopID uuid.UUID) (*entity.ShopCourier, error)
 	SoftDeleteShopCouriers(ctx context.Context, shopID uuid.UUID) error
 	CreateShopCourier(ctx context.Context, courier *entity.ShopCourier) error
+	// GetFinanceSummary sums the paid shop orders placed in [from, to) and their completed
+	// refunds.
+	GetFinanceSummary(ctx context.Context, shopID uuid.UUID, from, to time.Time) (*entity.ShopFinanceSummary, error)
+}
+
+type ShopMemberUsecase interface {
+	GetMyMembership(ctx context.Context, userID uuid.UUID) (*entity.ShopMemberResponse, error)
+	ListMembers(ctx context.Context, userID uuid.UUID) ([]*entity.ShopMemberResponse, error)
+	UpdateMember(ctx context.Context, userID uuid.UUID, memberUserID uuid.UUID, req entity.UpdateShopMemberRequest) (*entity.ShopMemberResponse, error)
+	RemoveMember(ctx context.Context, userID uuid.UUID, memberUserID uuid.UUID) error
+
+	InviteMember(ctx context.Context, userID uuid.UUID, req entity.InviteShopMemberRequest) (*entity.ShopInvitationResponse, error)
+	ListInvitations(ctx context.Context, userID uuid.UUID) ([]*entity.ShopInvitationResponse, error)
+	RevokeInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) error
+	AcceptInvitation(ctx context.Context, userID uuid.UUID, req entity.AcceptShopInvitationRequest) (*entity.ShopMemberResponse, error)
+}
+
+type ShopMemberRepository interface {
+	// ListMembers returns the shop's members with their users, the owner first.
+	ListMembers(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopMember, error)
+	UpdateMemberRole(ctx context.Context, id uuid.UUID, role string) error
+	// RemoveMember deletes the membership and takes the SHOP role back from its user.
+	RemoveMember(ctx context.Context, member *entity.ShopMember) error
+
+	// CreateInvitation stores the invitation, replacing any pending one of the shop for
+	// the same email.
+	CreateInvitation(ctx context.Context, invitation *entity.ShopInvitation) error
+	// CountSeats counts the shop's members and its pending invitations.
+	CountSeats(ctx context.Context, shopID uuid.UUID) (int64, error)
+	ListPendingInvitations(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopInvitation, error)
+	GetInvitationByID(ctx context.Context, id uuid.UUID) (*entity.ShopInvitation, error)
+	// GetInvitationByTokenHash returns the pending invitation with its shop loaded.
+	GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entity.ShopInvitation, error)
+	DeleteInvitation(ctx context.Context, id uuid.UUID) error
+	// AcceptInvitation marks the invitation accepted, creates the membership and gives
+	// its user the SHOP role. It fails with ErrShopInvitationInvalid when the invitation
+	// was accepted or revoked meanwhile.
+	AcceptInvitation(ctx context.Context, invitation *entity.ShopInvitation, member *entity.ShopMember) error
 }
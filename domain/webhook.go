@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+
+	"ecommerce-go-api/entity"
+
+	"github.com/google/uuid"
+)
+
+type ShopWebhookUsecase interface {
+	ListWebhooks(ctx context.Context, userID uuid.UUID) ([]*entity.ShopWebhookResponse, error)
+	CreateWebhook(ctx context.Context, userID uuid.UUID, req entity.CreateShopWebhookRequest) (*entity.ShopWebhookResponse, error)
+	GetWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) (*entity.ShopWebhookResponse, error)
+	UpdateWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, req entity.UpdateShopWebhookRequest) (*entity.ShopWebhookResponse, error)
+	DeleteWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) error
+	RotateWebhookSecret(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) (*entity.ShopWebhookResponse, error)
+
+	ListDeliveries(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, req entity.WebhookDeliveryListRequest) (*entity.WebhookDeliveryListResponse, error)
+	GetDelivery(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+	RedeliverDelivery(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error)
+}
+
+type ShopWebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook *entity.ShopWebhook) error
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (*entity.ShopWebhook, error)
+	ListWebhooksByShopID(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopWebhook, error)
+	CountWebhooksByShopID(ctx context.Context, shopID uuid.UUID) (int64, error)
+	// ListSubscribedWebhooks returns the shop's active webhooks subscribed to eventType.
+	ListSubscribedWebhooks(ctx context.Context, shopID uuid.UUID, eventType string) ([]*entity.ShopWebhook, error)
+	UpdateWebhook(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+
+	// CreateDeliveries queues deliveries, skipping any already queued for the same
+	// webhook and event.
+	CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error
+	// ClaimDueDeliveries leases a batch of due deliveries, with their webhooks loaded.
+	ClaimDueDeliveries(ctx context.Context, claim entity.JobClaim) ([]*entity.WebhookDelivery, error)
+	// FinishAttempt logs an attempt, stores the delivery's new state and releases the claim.
+	FinishAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, req entity.WebhookDeliveryListRequest) ([]*entity.WebhookDelivery, int64, error)
+	// GetDeliveryByID returns the delivery with its attempts, newest first.
+	GetDeliveryByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error)
+	RequeueDelivery(ctx context.Context, id uuid.UUID) error
+}
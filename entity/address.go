@@ -10,14 +10,14 @@ import (
 type Address struct {
 	ID            uint32         `gorm:"primaryKey;autoIncrement" json:"id"`
 	UserID        uuid.UUID      `gorm:"type:uuid;not null;index:idx_addresses_user_id" json:"userId"`
-	Name          string         `gorm:"type:text" json:"name"`
-	Line1         string         `gorm:"type:text" json:"line1"`
-	Line2         string         `gorm:"type:text" json:"line2"`
+	Name          string         `gorm:"type:text;serializer:fieldcrypt" json:"name"`
+	Line1         string         `gorm:"type:text;serializer:fieldcrypt" json:"line1"`
+	Line2         string         `gorm:"type:text;serializer:fieldcrypt" json:"line2"`
 	SubDistrictID uint32         `gorm:"not null" json:"subDistrictId"`
 	DistrictID    uint32         `gorm:"not null" json:"districtId"`
 	ProvinceID    uint32         `gorm:"not null" json:"provinceId"`
 	Zipcode       uint32         `json:"zipcode"`
-	PhoneNumber   string         `gorm:"size:15" json:"phoneNumber"`
+	PhoneNumber   string         `gorm:"type:text;serializer:fieldcrypt" json:"phoneNumber"`
 	IsDefault     bool           `gorm:"default:false;uniqueIndex:uq_addresses_user_default,where:is_default = true" json:"isDefault"`
 	CreatedAt     time.Time      `gorm:"not null;default:now()" json:"createdAt"`
 	UpdatedAt     time.Time      `gorm:"not null;default:now()" json:"updatedAt"`
@@ -59,6 +59,8 @@ type CartShopEstimate struct {
 	Items    []CartItemShop `json:"items"`
 	Subtotal float64        `json:"subtotal"`
 	Courier  CourierOption  `json:"courier"`
+	// EstimatedDelivery is nil when no delivery address is known.
+	EstimatedDelivery *DeliveryWindow `json:"estimatedDelivery,omitempty"`
 }
 
 type CartShippingEstimateResponse struct {
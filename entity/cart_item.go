@@ -30,4 +30,7 @@ type UpdateCartItemRequest struct {
 
 type EstimateShippingRequest struct {
 	CartItemIDs []uint32 `json:"cartItemIds" validate:"required,min=1,dive,gt=0"`
+	// AddressID is the delivery address used for the delivery estimate. When omitted,
+	// the buyer's default address is used.
+	AddressID *uint32 `json:"addressId" validate:"omitempty,gt=0"`
 }
@@ -7,17 +7,24 @@ import (
 )
 
 type Courier struct {
-	ID        uint32         `gorm:"primaryKey;autoIncrement" json:"id"`
-	Name      string         `gorm:"size:255;not null" json:"name"`
-	ImageURL  string         `gorm:"type:text" json:"imageUrl"`
-	Rate      float64        `gorm:"type:decimal(10,2);not null" json:"rate"`
-	CreatedAt time.Time      `gorm:"not null;default:now()" json:"createdAt"`
-	UpdatedAt time.Time      `gorm:"not null;default:now()" json:"updatedAt"`
-	DeletedAt gorm.DeletedAt `gorm:"default:null" json:"deletedAt"`
+	ID       uint32  `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name     string  `gorm:"size:255;not null" json:"name"`
+	ImageURL string  `gorm:"type:text" json:"imageUrl"`
+	Rate     float64 `gorm:"type:decimal(10,2);not null" json:"rate"`
+	// WebhookSecret signs tracking webhooks sent by the courier (HMAC-SHA256 of the body).
+	WebhookSecret string `gorm:"type:text" json:"-"`
+	// ProviderCode selects the CourierProvider integration; empty means tracking numbers are entered by hand.
+	ProviderCode string         `gorm:"size:50" json:"providerCode,omitempty"`
+	CreatedAt    time.Time      `gorm:"not null;default:now()" json:"createdAt"`
+	UpdatedAt    time.Time      `gorm:"not null;default:now()" json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"default:null" json:"deletedAt"`
 }
 type CourierListResponse struct {
 	ID       uint32  `json:"id"`
 	Name     string  `json:"name"`
 	ImageURL string  `json:"imageUrl"`
 	Rate     float64 `json:"rate"`
+	// SupportsBooking is true when shipments can be booked through the courier's API
+	// instead of entering a tracking number.
+	SupportsBooking bool `json:"supportsBooking"`
 }
@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+const (
+	CourierProviderSimulator = "SIMULATOR"
+)
+
+// CourierParty is a sender or recipient on a courier booking.
+type CourierParty struct {
+	Name     string
+	Phone    string
+	Address  string
+	Province string
+	Zipcode  string
+}
+
+type CourierBookingRequest struct {
+	Reference   string
+	OrderNumber string
+	Sender      CourierParty
+	Recipient   CourierParty
+	ItemCount   uint32
+}
+
+type CourierBooking struct {
+	TrackingNo string
+	PickupAt   *time.Time
+}
+
+type CourierLabel struct {
+	TrackingNo string
+	// Barcode is the value to encode in the label barcode (usually the tracking number).
+	Barcode string
+	// SortCode is the courier's routing code printed on the label for hub sorting.
+	SortCode string
+}
+
+// CourierTrackingEvent is a tracking update as reported by a courier provider. Status uses
+// the same codes as courier webhooks (see ShipmentStatusByCode).
+type CourierTrackingEvent struct {
+	EventID     string
+	Status      string
+	Description string
+	Location    string
+	OccurredAt  time.Time
+}
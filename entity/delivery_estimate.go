@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"time"
+)
+
+// CourierTransitTime is how many business days a courier takes to deliver between two
+// provinces. A nil province matches any province, and the most specific rule wins.
+type CourierTransitTime struct {
+	ID                    uint32    `gorm:"primaryKey;autoIncrement" json:"id"`
+	CourierID             uint32    `gorm:"not null;index:idx_courier_transit_times_courier_id" json:"courierId"`
+	OriginProvinceID      *uint32   `json:"originProvinceId"`
+	DestinationProvinceID *uint32   `json:"destinationProvinceId"`
+	MinDays               uint32    `gorm:"not null" json:"minDays"`
+	MaxDays               uint32    `gorm:"not null" json:"maxDays"`
+	CreatedAt             time.Time `gorm:"not null;default:now()" json:"createdAt"`
+	UpdatedAt             time.Time `gorm:"not null;default:now()" json:"updatedAt"`
+}
+
+// Holiday is a day shops and couriers do not work, such as a Thai public holiday.
+type Holiday struct {
+	Date      time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"createdAt"`
+}
+
+type DeliveryEstimateRequest struct {
+	CourierID             uint32
+	OriginProvinceID      *uint32
+	DestinationProvinceID *uint32
+	// HandlingDays is how many business days the shop takes to hand the parcel over.
+	HandlingDays uint32
+	// From is when the clock starts: the order time, or the shipping time once shipped.
+	From time.Time
+}
+
+// DeliveryWindow is the earliest and latest expected delivery date, as YYYY-MM-DD.
+type DeliveryWindow struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type CourierTransitTimeRequest struct {
+	OriginProvinceID      *uint32 `json:"originProvinceId" validate:"omitempty,gt=0"`
+	DestinationProvinceID *uint32 `json:"destinationProvinceId" validate:"omitempty,gt=0"`
+	MinDays               uint32  `json:"minDays" validate:"lte=30"`
+	MaxDays               uint32  `json:"maxDays" validate:"required,gtefield=MinDays,lte=30"`
+}
+
+type UpdateCourierTransitTimesRequest struct {
+	TransitTimes []CourierTransitTimeRequest `json:"transitTimes" validate:"required,dive"`
+}
+
+type CourierTransitTimeResponse struct {
+	ID                    uint32  `json:"id"`
+	CourierID             uint32  `json:"courierId"`
+	OriginProvinceID      *uint32 `json:"originProvinceId"`
+	DestinationProvinceID *uint32 `json:"destinationProvinceId"`
+	MinDays               uint32  `json:"minDays"`
+	MaxDays               uint32  `json:"maxDays"`
+}
+
+type CreateHolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type HolidayListRequest struct {
+	Year int `query:"year"`
+}
+
+type HolidayResponse struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
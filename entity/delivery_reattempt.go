@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryReattempt is a redelivery the buyer scheduled after the courier failed to
+// deliver a parcel. When the buyer corrected the address, the new address is
+// snapshotted here and used for labels and later bookings of the parcel.
+type DeliveryReattempt struct {
+	ID                  uint32    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShipmentID          uuid.UUID `gorm:"type:uuid;not null;index:idx_delivery_reattempts_shipment_id" json:"shipmentId"`
+	RequestedBy         uuid.UUID `gorm:"type:uuid;not null" json:"requestedBy"`
+	ScheduledDate       time.Time `gorm:"type:date;not null" json:"scheduledDate"`
+	AddressID           *uint32   `json:"addressId,omitempty"`
+	ShippingName        string    `gorm:"type:text;serializer:fieldcrypt" json:"shippingName,omitempty"`
+	ShippingPhone       string    `gorm:"type:text;serializer:fieldcrypt" json:"shippingPhone,omitempty"`
+	ShippingLine1       string    `gorm:"type:text;serializer:fieldcrypt" json:"shippingLine1,omitempty"`
+	ShippingLine2       string    `gorm:"type:text;serializer:fieldcrypt" json:"shippingLine2,omitempty"`
+	ShippingSubDistrict string    `gorm:"type:text" json:"shippingSubDistrict,omitempty"`
+	ShippingDistrict    string    `gorm:"type:text" json:"shippingDistrict,omitempty"`
+	ShippingProvince    string    `gorm:"type:text" json:"shippingProvince,omitempty"`
+	ShippingZipcode     string    `gorm:"size:10" json:"shippingZipcode,omitempty"`
+	Note                string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt           time.Time `gorm:"not null;default:now()" json:"createdAt"`
+}
+
+type DeliveryReattemptRequest struct {
+	// ScheduledDate is the day the buyer wants the courier to try again, YYYY-MM-DD.
+	ScheduledDate string `json:"scheduledDate" validate:"required,datetime=2006-01-02"`
+	// AddressID optionally corrects the delivery address with one of the buyer's addresses.
+	AddressID *uint32 `json:"addressId" validate:"omitempty,gt=0"`
+	Note      string  `json:"note" validate:"max=500"`
+}
+
+type DeliveryReattemptResponse struct {
+	ID             uint32    `json:"id"`
+	ScheduledDate  string    `json:"scheduledDate"`
+	AddressChanged bool      `json:"addressChanged"`
+	Note           string    `json:"note,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
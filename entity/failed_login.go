@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LoginFailureUnknownEmail  = "UNKNOWN_EMAIL"
+	LoginFailureWrongPassword = "WRONG_PASSWORD"
+	// LoginFailureWrongTwoFactorCode is a login whose password was right but whose 2FA
+	// code or recovery code was not.
+	LoginFailureWrongTwoFactorCode = "WRONG_TWO_FACTOR_CODE"
+	// LoginFailureThrottled is an attempt rejected because the account or IP address
+	// had to wait after its previous failures. The password was not checked.
+	LoginFailureThrottled = "THROTTLED"
+	// LoginFailureLocked is an attempt rejected because the account or IP address is
+	// locked. The password was not checked.
+	LoginFailureLocked = "LOCKED"
+)
+
+// FailedLogin is one entry of the failed login audit trail. Email is stored lowercased;
+// UserID is nil when no account has the email.
+type FailedLogin struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Email     string     `gorm:"size:255;not null" json:"email"`
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"userId"`
+	IPAddress string     `gorm:"size:45;not null" json:"ipAddress"`
+	UserAgent string     `gorm:"size:500;not null" json:"userAgent"`
+	Reason    string     `gorm:"size:20;not null" json:"reason"`
+	CreatedAt time.Time  `gorm:"not null" json:"createdAt"`
+}
+
+// LoginThrottle is the count of recent failed logins of one account or IP address.
+type LoginThrottle struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// LoginReservation is a login attempt counted as failed before its credentials are
+// checked. Previous is the count before the attempt, which decides whether it may be
+// made.
+type LoginReservation struct {
+	Key        string
+	Previous   LoginThrottle
+	ReservedAt time.Time
+}
+
+type FailedLoginListRequest struct {
+	Page      uint64 `query:"page" validate:"omitempty,min=1" example:"1"`
+	PerPage   uint64 `query:"perPage" validate:"omitempty,min=1,max=100" example:"20"`
+	Email     string `query:"email" validate:"omitempty,max=255" example:"kiattisak.c@example.com"`
+	IPAddress string `query:"ipAddress" validate:"omitempty,max=45" example:"203.0.113.7"`
+	Reason    string `query:"reason" validate:"omitempty,oneof=UNKNOWN_EMAIL WRONG_PASSWORD WRONG_TWO_FACTOR_CODE THROTTLED LOCKED" example:"WRONG_PASSWORD"`
+}
+
+type FailedLoginListResponse struct {
+	Items []*FailedLogin `json:"items"`
+	Total int64          `json:"total"`
+}
+
+// UnlockLoginRequest clears the failed login count of an account, an IP address or both.
+type UnlockLoginRequest struct {
+	Email     string `json:"email" validate:"required_without=IPAddress,omitempty,email,max=255" example:"kiattisak.c@example.com"`
+	IPAddress string `json:"ipAddress" validate:"required_without=Email,omitempty,ip" example:"203.0.113.7"`
+}
@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	JobPaymentExpiry        = "payment_expiry"
+	JobOrderAutoComplete    = "order_auto_complete"
+	JobShipmentTrackingSync = "shipment_tracking_sync"
+)
+
+const (
+	JobTriggerScheduled = "SCHEDULED"
+	JobTriggerManual    = "MANUAL"
+)
+
+const (
+	JobRunStatusRunning   = "RUNNING"
+	JobRunStatusSucceeded = "SUCCEEDED"
+	// JobRunStatusPartial means the run finished but some items failed.
+	JobRunStatusPartial = "PARTIAL"
+	JobRunStatusFailed  = "FAILED"
+)
+
+// JobRun records one execution of a scheduled job.
+type JobRun struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	JobName     string     `gorm:"size:100;not null" json:"jobName"`
+	Trigger     string     `gorm:"size:20;not null" json:"trigger"`
+	TriggeredBy *uuid.UUID `gorm:"type:uuid" json:"triggeredBy,omitempty"`
+	// InstanceID is the replica that ran the job.
+	InstanceID string     `gorm:"size:100;not null" json:"instanceId"`
+	Status     string     `gorm:"size:20;not null" json:"status"`
+	StartedAt  time.Time  `gorm:"not null" json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+	Processed  int        `gorm:"not null;default:0" json:"processed"`
+	Succeeded  int        `gorm:"not null;default:0" json:"succeeded"`
+	Failed     int        `gorm:"not null;default:0" json:"failed"`
+	// ErrorSamples keeps the first few item errors of the run.
+	ErrorSamples []string `gorm:"type:jsonb;serializer:json" json:"errorSamples"`
+	Error        string   `gorm:"type:text" json:"error,omitempty"`
+}
+
+// JobClaim leases rows to one scheduler instance. Claimed rows are skipped by other
+// instances until the lease runs out.
+type JobClaim struct {
+	Claimant string
+	Limit    int
+	Lease    time.Duration
+}
+
+// JobState is the persisted pause flag of a job, so pausing survives restarts.
+type JobState struct {
+	JobName   string     `gorm:"primaryKey;size:100" json:"jobName"`
+	Paused    bool       `gorm:"not null;default:false" json:"paused"`
+	UpdatedBy *uuid.UUID `gorm:"type:uuid" json:"updatedBy,omitempty"`
+	UpdatedAt time.Time  `gorm:"not null;default:now()" json:"updatedAt"`
+}
+
+// JobInfo is what the scheduler knows about a registered job.
+type JobInfo struct {
+	Name      string
+	Schedule  string
+	Paused    bool
+	Running   bool
+	NextRunAt *time.Time
+}
+
+type JobRunListRequest struct {
+	Page    uint64 `query:"page" validate:"omitempty,min=1" example:"1"`
+	PerPage uint64 `query:"perPage" validate:"omitempty,min=1,max=100" example:"20"`
+	JobName string `query:"jobName" validate:"omitempty,max=100"`
+	Status  string `query:"status" validate:"omitempty,oneof=RUNNING SUCCEEDED PARTIAL FAILED"`
+}
+
+type JobResponse struct {
+	Name      string          `json:"name"`
+	Schedule  string          `json:"schedule"`
+	Paused    bool            `json:"paused"`
+	Running   bool            `json:"running"`
+	NextRunAt *time.Time      `json:"nextRunAt"`
+	LastRun   *JobRunResponse `json:"lastRun"`
+}
+
+type JobRunResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	JobName      string     `json:"jobName"`
+	Trigger      string     `json:"trigger"`
+	TriggeredBy  *uuid.UUID `json:"triggeredBy,omitempty"`
+	InstanceID   string     `json:"instanceId"`
+	Status       string     `json:"status"`
+	StartedAt    time.Time  `json:"startedAt"`
+	FinishedAt   *time.Time `json:"finishedAt"`
+	Processed    int        `json:"processed"`
+	Succeeded    int        `json:"succeeded"`
+	Failed       int        `json:"failed"`
+	ErrorSamples []string   `json:"errorSamples"`
+	Error        string     `json:"error,omitempty"`
+}
+
+type JobRunListResponse struct {
+	Items []*JobRunResponse `json:"items"`
+	Total int64             `json:"total"`
+}
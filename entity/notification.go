@@ -0,0 +1,195 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	LanguageThai    = "th"
+	LanguageEnglish = "en"
+)
+
+const (
+	EmailTemplateOrderPlaced                   = "order_placed"
+	EmailTemplatePaymentExpired                = "payment_expired"
+	EmailTemplateShopOrderShipped              = "shop_order_shipped"
+	EmailTemplateShopOrderAutoCompleteReminder = "shop_order_auto_complete_reminder"
+	EmailTemplateRefundRequested               = "refund_requested"
+	EmailTemplateRefundApproved                = "refund_approved"
+	EmailTemplateRefundCompleted               = "refund_completed"
+	EmailTemplateVerifyEmail                   = "verify_email"
+	EmailTemplatePasswordReset                 = "password_reset"
+	EmailTemplatePasswordChanged               = "password_changed"
+	EmailTemplateShopInvitation                = "shop_invitation"
+)
+
+const (
+	EmailStatusPending = "PENDING"
+	EmailStatusSent    = "SENT"
+	// EmailStatusFailed means the email ran out of attempts.
+	EmailStatusFailed = "FAILED"
+)
+
+// Email is a message for one recipient, ready to send.
+type Email struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// EmailMessage is a queued email. It is rendered when queued, so retries send exactly
+// the same message.
+type EmailMessage struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    *uuid.UUID `gorm:"type:uuid" json:"userId"`
+	Recipient string     `gorm:"size:255;not null" json:"recipient"`
+	Template  string     `gorm:"size:100;not null" json:"template"`
+	Language  string     `gorm:"size:5;not null" json:"language"`
+	Subject   string     `gorm:"size:255;not null" json:"subject"`
+	HTMLBody  string     `gorm:"type:text;not null" json:"-"`
+	TextBody  string     `gorm:"type:text;not null" json:"-"`
+	// DedupeKey identifies the email within the event that caused it, so a retried
+	// event does not queue it twice.
+	DedupeKey     string     `gorm:"size:255;not null" json:"-"`
+	Status        string     `gorm:"size:20;not null;default:PENDING" json:"status"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"nextAttemptAt"`
+	LastError     string     `gorm:"type:text" json:"lastError,omitempty"`
+	ClaimedBy     *string    `gorm:"size:100" json:"-"`
+	ClaimedUntil  *time.Time `json:"-"`
+	CreatedAt     time.Time  `gorm:"not null" json:"createdAt"`
+	SentAt        *time.Time `json:"sentAt"`
+}
+
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelInApp = "in_app"
+)
+
+// NotificationEvent is an event users are notified of and the channels it is sent on.
+type NotificationEvent struct {
+	EventType string
+	Channels  []string
+}
+
+// NotificationEvents are the events users can choose to be notified of. Shop owners
+// are notified of new orders, payments, completed orders and refund requests; buyers of
+// the rest.
+var NotificationEvents = []NotificationEvent{
+	{EventType: EventOrderPlaced, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+	{EventType: EventPaymentSubmitted, Channels: []string{NotificationChannelInApp}},
+	{EventType: EventPaymentExpired, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+	{EventType: EventShopOrderShipped, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+	{EventType: EventShopOrderDelivered, Channels: []string{NotificationChannelInApp}},
+	{EventType: EventShopOrderAutoCompleteReminder, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+	{EventType: EventShopOrderCompleted, Channels: []string{NotificationChannelInApp}},
+	{EventType: EventShopOrderCancelled, Channels: []string{NotificationChannelInApp}},
+	{EventType: EventRefundRequested, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+	{EventType: EventRefundApproved, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+	{EventType: EventRefundCompleted, Channels: []string{NotificationChannelEmail, NotificationChannelInApp}},
+}
+
+const (
+	InboxTemplateOrderPlaced        = "order_placed"
+	InboxTemplateNewOrder           = "new_order"
+	InboxTemplatePaymentReceived    = "payment_received"
+	InboxTemplatePaymentExpired     = "payment_expired"
+	InboxTemplateShopOrderShipped   = "shop_order_shipped"
+	InboxTemplateShopOrderDelivered = "shop_order_delivered"
+	InboxTemplateShopOrderCompleted = "shop_order_completed"
+	InboxTemplateShopOrderCancelled = "shop_order_cancelled"
+	InboxTemplateRefundRequested    = "refund_requested"
+	InboxTemplateRefundApproved     = "refund_approved"
+	InboxTemplateRefundCompleted    = "refund_completed"
+
+	InboxTemplateShopOrderAutoCompleteReminder = "shop_order_auto_complete_reminder"
+)
+
+// Notification is an entry in a user's in-app inbox. Title and Body are rendered in the
+// user's language when the notification is created.
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null" json:"userId"`
+	EventType string           `gorm:"size:100;not null" json:"eventType"`
+	Title     string           `gorm:"size:255;not null" json:"title"`
+	Body      string           `gorm:"type:text;not null" json:"body"`
+	Data      NotificationData `gorm:"type:jsonb;serializer:json;not null" json:"data"`
+	DedupeKey string           `gorm:"size:255;not null" json:"-"`
+	ReadAt    *time.Time       `json:"readAt"`
+	CreatedAt time.Time        `gorm:"not null" json:"createdAt"`
+}
+
+// NotificationData points the app at what the notification is about.
+type NotificationData struct {
+	OrderID     *uuid.UUID `json:"orderId,omitempty"`
+	ShopOrderID *uuid.UUID `json:"shopOrderId,omitempty"`
+	ShopID      *uuid.UUID `json:"shopId,omitempty"`
+	RefundID    *uuid.UUID `json:"refundId,omitempty"`
+	OrderNumber string     `json:"orderNumber,omitempty"`
+}
+
+// NotificationPreference is a user's choice of channels for one event. Users without a
+// preference for an event get it on every channel.
+type NotificationPreference struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"userId"`
+	EventType string    `gorm:"size:100;primaryKey" json:"eventType"`
+	Email     bool      `gorm:"not null" json:"email"`
+	InApp     bool      `gorm:"not null" json:"inApp"`
+	UpdatedAt time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// Allows reports whether the preference lets the event through on channel.
+func (p *NotificationPreference) Allows(channel string) bool {
+	if p == nil {
+		return true
+	}
+	switch channel {
+	case NotificationChannelEmail:
+		return p.Email
+	case NotificationChannelInApp:
+		return p.InApp
+	}
+	return false
+}
+
+type NotificationListRequest struct {
+	Page    uint64 `query:"page" validate:"omitempty,min=1" example:"1"`
+	PerPage uint64 `query:"perPage" validate:"omitempty,min=1,max=100" example:"20"`
+	Unread  bool   `query:"unread" example:"true"`
+}
+
+type NotificationListResponse struct {
+	Items       []*Notification `json:"items"`
+	Total       int64           `json:"total"`
+	UnreadCount int64           `json:"unreadCount"`
+}
+
+type NotificationUnreadCountResponse struct {
+	UnreadCount int64 `json:"unreadCount"`
+}
+
+type NotificationMarkAllReadResponse struct {
+	Updated int64 `json:"updated"`
+}
+
+type NotificationPreferenceResponse struct {
+	EventType string   `json:"eventType"`
+	Channels  []string `json:"channels"`
+	Email     bool     `json:"email"`
+	InApp     bool     `json:"inApp"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"preferences" validate:"required,min=1,dive"`
+}
+
+// NotificationPreferenceRequest changes the channels of one event. Channels left out
+// keep their current setting.
+type NotificationPreferenceRequest struct {
+	EventType string `json:"eventType" validate:"required,max=100" example:"shop_order.shipped"`
+	Email     *bool  `json:"email" example:"false"`
+	InApp     *bool  `json:"inApp" example:"true"`
+}
@@ -11,10 +11,10 @@ type Order struct {
 	UserID              uuid.UUID `gorm:"type:uuid;not null;index:idx_orders_user_id" json:"userId"`
 	AddressID           uint32    `json:"addressId"`
 	GrandTotal          float64   `gorm:"type:decimal(10,2);not null" json:"grandTotal"`
-	ShippingName        string    `gorm:"size:255;not null" json:"shippingName"`
-	ShippingPhone       string    `gorm:"size:15;not null" json:"shippingPhone"`
-	ShippingLine1       string    `gorm:"type:text;not null" json:"shippingLine1"`
-	ShippingLine2       string    `gorm:"type:text" json:"shippingLine2"`
+	ShippingName        string    `gorm:"type:text;not null;serializer:fieldcrypt" json:"shippingName"`
+	ShippingPhone       string    `gorm:"type:text;not null;serializer:fieldcrypt" json:"shippingPhone"`
+	ShippingLine1       string    `gorm:"type:text;not null;serializer:fieldcrypt" json:"shippingLine1"`
+	ShippingLine2       string    `gorm:"type:text;serializer:fieldcrypt" json:"shippingLine2"`
 	ShippingSubDistrict string    `gorm:"size:100;not null" json:"shippingSubDistrict"`
 	ShippingDistrict    string    `gorm:"size:100;not null" json:"shippingDistrict"`
 	ShippingProvince    string    `gorm:"size:100;not null" json:"shippingProvince"`
@@ -71,18 +71,19 @@ type OrderShopResponse struct {
 }
 
 type ShopOrderResponse struct {
-	ID            uuid.UUID           `json:"id"`
-	OrderID       uuid.UUID           `json:"orderId"`
-	OrderNumber   string              `json:"orderNumber"`
-	OrderStatusID uint32              `json:"orderStatusId"`
-	Subtotal      float64             `json:"subtotal"`
-	Shipping      float64             `json:"shipping"`
-	GrandTotal    float64             `json:"grandTotal"`
-	CreatedAt     time.Time           `json:"createdAt"`
-	UpdatedAt     time.Time           `json:"updatedAt"`
-	Shop          OrderShopResponse   `json:"shop"`
-	OrderItems    []OrderItemResponse `json:"orderItems"`
-	Timeline      []OrderTimelineItem `json:"timeline,omitempty"`
+	ID                uuid.UUID           `json:"id"`
+	OrderID           uuid.UUID           `json:"orderId"`
+	OrderNumber       string              `json:"orderNumber"`
+	OrderStatusID     uint32              `json:"orderStatusId"`
+	Subtotal          float64             `json:"subtotal"`
+	Shipping          float64             `json:"shipping"`
+	GrandTotal        float64             `json:"grandTotal"`
+	EstimatedDelivery *DeliveryWindow     `json:"estimatedDelivery,omitempty"`
+	CreatedAt         time.Time           `json:"createdAt"`
+	UpdatedAt         time.Time           `json:"updatedAt"`
+	Shop              OrderShopResponse   `json:"shop"`
+	OrderItems        []OrderItemResponse `json:"orderItems"`
+	Timeline          []OrderTimelineItem `json:"timeline,omitempty"`
 }
 
 type OrderResponse struct {
@@ -117,6 +118,7 @@ type OrderListResponse struct {
 	ShippingProvince    string              `json:"shippingProvince"`
 	ShippingZipcode     string              `json:"shippingZipcode"`
 	PaymentMethodID     uint32              `json:"paymentMethodId"`
+	EstimatedDelivery   *DeliveryWindow     `json:"estimatedDelivery,omitempty"`
 	CreatedAt           time.Time           `json:"createdAt"`
 	UpdatedAt           time.Time           `json:"updatedAt"`
 	Shop                OrderShopResponse   `json:"shop"`
@@ -140,6 +142,7 @@ type ShopOrderListResponse struct {
 	ShippingProvince    string              `json:"shippingProvince"`
 	ShippingZipcode     string              `json:"shippingZipcode"`
 	PaymentMethodID     uint32              `json:"paymentMethodId"`
+	EstimatedDelivery   *DeliveryWindow     `json:"estimatedDelivery,omitempty"`
 	CreatedAt           time.Time           `json:"createdAt"`
 	UpdatedAt           time.Time           `json:"updatedAt"`
 	OrderItems          []OrderItemResponse `json:"orderItems"`
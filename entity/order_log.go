@@ -7,13 +7,15 @@ import (
 )
 
 type OrderLog struct {
-	ID            uint32     `gorm:"primaryKey;autoIncrement" json:"id"`
-	OrderID       uuid.UUID  `gorm:"type:uuid;not null;index:idx_order_logs_order_id" json:"orderId"`
-	ShopOrderID   *uuid.UUID `gorm:"type:uuid" json:"shopOrderId"`
-	OrderStatusID uint32     `json:"orderStatusId,omitempty"`
-	Note          string     `gorm:"type:text" json:"note"`
-	CreatedBy     *uuid.UUID `gorm:"type:uuid" json:"createdBy"`
-	CreatedAt     *time.Time `gorm:"default:now();index:idx_order_logs_created_at" json:"createdAt"`
+	ID             uint32     `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID        uuid.UUID  `gorm:"type:uuid;not null;index:idx_order_logs_order_id" json:"orderId"`
+	ShopOrderID    *uuid.UUID `gorm:"type:uuid" json:"shopOrderId"`
+	OrderStatusID  uint32     `json:"orderStatusId,omitempty"`
+	RefundID       *uuid.UUID `gorm:"type:uuid" json:"refundId,omitempty"`
+	RefundStatusID *uint32    `json:"refundStatusId,omitempty"`
+	Note           string     `gorm:"type:text" json:"note"`
+	CreatedBy      *uuid.UUID `gorm:"type:uuid" json:"createdBy"`
+	CreatedAt      *time.Time `gorm:"default:now();index:idx_order_logs_created_at" json:"createdAt"`
 
 	Order       Order        `gorm:"foreignKey:OrderID;references:ID" json:"order,omitempty"`
 	ShopOrder   *ShopOrder   `gorm:"foreignKey:ShopOrderID;references:ID" json:"shopOrder,omitempty"`
@@ -23,8 +25,10 @@ type OrderLog struct {
 
 // OrderTimelineItem represents a single timeline event in order history
 type OrderTimelineItem struct {
-	StatusID  uint32     `json:"statusId"`
-	Note      string     `json:"note,omitempty"`
-	CreatedAt *time.Time `json:"createdAt"`
-	CreatedBy *uuid.UUID `json:"createdBy,omitempty"`
+	StatusID       uint32     `json:"statusId"`
+	RefundID       *uuid.UUID `json:"refundId,omitempty"`
+	RefundStatusID *uint32    `json:"refundStatusId,omitempty"`
+	Note           string     `json:"note,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt"`
+	CreatedBy      *uuid.UUID `json:"createdBy,omitempty"`
 }
@@ -0,0 +1,174 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventOrderPlaced        = "order.placed"
+	EventPaymentSubmitted   = "payment.submitted"
+	EventPaymentExpired     = "payment.expired"
+	EventShopOrderShipped   = "shop_order.shipped"
+	EventShopOrderDelivered = "shop_order.delivered"
+	EventShopOrderCompleted = "shop_order.completed"
+	EventShopOrderCancelled = "shop_order.cancelled"
+	EventParcelReturned     = "shipment.returned"
+	EventRefundRequested    = "refund.requested"
+	EventRefundApproved     = "refund.approved"
+	EventRefundCompleted    = "refund.completed"
+	// The negotiation of buyer-initiated refunds.
+	EventRefundCounterOffered = "refund.counter_offered"
+	EventRefundEscalated      = "refund.escalated"
+	EventRefundEvidenceAdded  = "refund.evidence_added"
+	// EventShopOrderAutoCompleteReminder is sent to the buyer a day before a delivered
+	// shop order is completed automatically.
+	EventShopOrderAutoCompleteReminder = "shop_order.auto_complete_reminder"
+)
+
+const (
+	AggregateOrder     = "order"
+	AggregateShopOrder = "shop_order"
+	AggregateShipment  = "shipment"
+	AggregateRefund    = "refund"
+)
+
+const (
+	OutboxStatusPending   = "PENDING"
+	OutboxStatusProcessed = "PROCESSED"
+	// OutboxStatusDead means the event ran out of delivery attempts. It stays in the
+	// outbox until an admin retries it.
+	OutboxStatusDead = "DEAD"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change it
+// describes, and delivered to its handlers afterwards.
+type OutboxEvent struct {
+	ID            uuid.UUID       `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	EventType     string          `gorm:"size:100;not null" json:"eventType"`
+	AggregateType string          `gorm:"size:50;not null" json:"aggregateType"`
+	AggregateID   uuid.UUID       `gorm:"type:uuid;not null" json:"aggregateId"`
+	Payload       json.RawMessage `gorm:"type:jsonb;serializer:json;not null" json:"payload"`
+	Status        string          `gorm:"size:20;not null;default:PENDING" json:"status"`
+	Attempts      int             `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time       `gorm:"not null" json:"nextAttemptAt"`
+	LastError     string          `gorm:"type:text" json:"lastError,omitempty"`
+	// CompletedHandlers lists the handlers that already succeeded, so a retry only runs
+	// the ones that failed.
+	CompletedHandlers []string   `gorm:"type:jsonb;serializer:json" json:"completedHandlers"`
+	ClaimedBy         *string    `gorm:"size:100" json:"-"`
+	ClaimedUntil      *time.Time `json:"-"`
+	CreatedAt         time.Time  `gorm:"not null" json:"createdAt"`
+	ProcessedAt       *time.Time `json:"processedAt"`
+}
+
+// StockItem is a quantity of a product going back into stock.
+type StockItem struct {
+	ProductID uint32 `json:"productId"`
+	Qty       uint32 `json:"qty"`
+}
+
+// RefundSummary is the refund created along with a change, if any.
+type RefundSummary struct {
+	ID             uuid.UUID `json:"id"`
+	RefundStatusID uint32    `json:"refundStatusId"`
+	Amount         float64   `json:"amount"`
+}
+
+type OrderPlacedPayload struct {
+	OrderID      uuid.UUID   `json:"orderId"`
+	UserID       uuid.UUID   `json:"userId"`
+	ShopOrderIDs []uuid.UUID `json:"shopOrderIds"`
+	GrandTotal   float64     `json:"grandTotal"`
+}
+
+type PaymentSubmittedPayload struct {
+	PaymentID     uuid.UUID `json:"paymentId"`
+	OrderID       uuid.UUID `json:"orderId"`
+	UserID        uuid.UUID `json:"userId"`
+	TransactionID string    `json:"transactionId"`
+	Amount        float64   `json:"amount"`
+}
+
+type PaymentExpiredPayload struct {
+	PaymentID     uuid.UUID  `json:"paymentId"`
+	OrderID       uuid.UUID  `json:"orderId"`
+	UserID        uuid.UUID  `json:"userId"`
+	TransactionID string     `json:"transactionId"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+}
+
+// ShopOrderPayload identifies the shop order an event is about.
+type ShopOrderPayload struct {
+	ShopOrderID uuid.UUID `json:"shopOrderId"`
+	OrderID     uuid.UUID `json:"orderId"`
+	ShopID      uuid.UUID `json:"shopId"`
+	UserID      uuid.UUID `json:"userId"`
+	OrderNumber string    `json:"orderNumber"`
+}
+
+type ShopOrderShippedPayload struct {
+	ShopOrderPayload
+	Parcels int `json:"parcels"`
+}
+
+type ShopOrderDeliveredPayload struct {
+	ShopOrderPayload
+}
+
+type ShopOrderCompletedPayload struct {
+	ShopOrderPayload
+	// Automatic is true when the order was completed by the auto-complete job rather
+	// than by the buyer.
+	Automatic bool `json:"automatic"`
+}
+
+type ShopOrderAutoCompleteReminderPayload struct {
+	ShopOrderPayload
+	CompleteAt time.Time `json:"completeAt"`
+}
+
+type ShopOrderCancelledPayload struct {
+	ShopOrderPayload
+	Reason      string         `json:"reason"`
+	CancelledBy *uuid.UUID     `json:"cancelledBy,omitempty"`
+	Restock     []StockItem    `json:"restock"`
+	Refund      *RefundSummary `json:"refund,omitempty"`
+}
+
+type ParcelReturnedPayload struct {
+	ShopOrderPayload
+	ShipmentID uuid.UUID      `json:"shipmentId"`
+	TrackingNo string         `json:"trackingNo"`
+	Restock    []StockItem    `json:"restock"`
+	Refund     *RefundSummary `json:"refund,omitempty"`
+}
+
+type RefundPayload struct {
+	RefundID    uuid.UUID `json:"refundId"`
+	ShopOrderID uuid.UUID `json:"shopOrderId"`
+	OrderID     uuid.UUID `json:"orderId"`
+	ShopID      uuid.UUID `json:"shopId"`
+	UserID      uuid.UUID `json:"userId"`
+	Amount      float64   `json:"amount"`
+}
+
+type RefundRequestedPayload struct {
+	RefundPayload
+	ReasonCode string `json:"reasonCode"`
+	Reason     string `json:"reason"`
+}
+
+type OutboxEventListRequest struct {
+	Page      uint64 `query:"page" validate:"omitempty,min=1" example:"1"`
+	PerPage   uint64 `query:"perPage" validate:"omitempty,min=1,max=100" example:"20"`
+	Status    string `query:"status" validate:"omitempty,oneof=PENDING PROCESSED DEAD" example:"DEAD"`
+	EventType string `query:"eventType" validate:"omitempty,max=100"`
+}
+
+type OutboxEventListResponse struct {
+	Items []*OutboxEvent `json:"items"`
+	Total int64          `json:"total"`
+}
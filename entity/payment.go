@@ -17,6 +17,9 @@ type Payment struct {
 	ExpiresAt       *time.Time `json:"expiresAt"`
 	CreatedAt       time.Time  `gorm:"not null;default:now()" json:"createdAt"`
 	UpdatedAt       time.Time  `gorm:"not null;default:now()" json:"updatedAt"`
+	// ClaimedBy and ClaimedUntil lease the payment to the scheduler instance expiring it.
+	ClaimedBy    *string    `gorm:"size:100" json:"-"`
+	ClaimedUntil *time.Time `json:"-"`
 
 	Order Order `gorm:"foreignKey:OrderID;references:ID" json:"order,omitempty"`
 }
@@ -8,6 +8,8 @@ const (
 	PaymentStatusCancelled  uint32 = 5
 	PaymentStatusRefunded   uint32 = 6
 	PaymentStatusExpired    uint32 = 7
+
+	PaymentStatusPartiallyRefunded uint32 = 8
 )
 
 type PaymentStatus struct {
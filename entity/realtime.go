@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RealtimeEvent is a live update streamed to the open connections of a buyer or a shop.
+// ID is the ID of the domain event it comes from, so clients can drop duplicates.
+type RealtimeEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
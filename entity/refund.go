@@ -6,6 +6,19 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	RefundInitiatorShop  = "SHOP"
+	RefundInitiatorBuyer = "BUYER"
+	// RefundInitiatorSystem marks refunds created automatically, e.g. when a paid shop order is cancelled.
+	RefundInitiatorSystem = "SYSTEM"
+)
+
+const (
+	RefundReasonNotDelivered = "NOT_DELIVERED"
+	RefundReasonDamaged      = "DAMAGED"
+	RefundReasonWrongItem    = "WRONG_ITEM"
+)
+
 type Refund struct {
 	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
 	ShopOrderID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_refunds_shop_order_id" json:"shopOrderId"`
@@ -14,9 +27,21 @@ type Refund struct {
 	RefundMethodID *uint32    `json:"refundMethodId,omitempty"`
 	RefundStatusID uint32     `gorm:"not null;default:1" json:"refundStatusId"`
 	Reason         string     `gorm:"type:text" json:"reason,omitempty"`
-	BankAccount    string     `gorm:"size:100" json:"bankAccount,omitempty"`
+	Initiator      string     `gorm:"size:20;not null;default:SHOP" json:"initiator"`
+	ReasonCode     string     `gorm:"size:50" json:"reasonCode,omitempty"`
+	RequestedBy    *uuid.UUID `gorm:"type:uuid" json:"requestedBy,omitempty"`
+	CounterAmount  *float64   `gorm:"type:decimal(10,2)" json:"counterAmount,omitempty"`
+	CounterNote    string     `gorm:"type:text" json:"counterNote,omitempty"`
+	ResponseDueAt  *time.Time `json:"responseDueAt,omitempty"`
+	EscalatedAt    *time.Time `json:"escalatedAt,omitempty"`
+	EscalatedBy    *uuid.UUID `gorm:"type:uuid" json:"escalatedBy,omitempty"`
+	ResolutionNote string     `gorm:"type:text" json:"resolutionNote,omitempty"`
+	BankAccount    string     `gorm:"type:text" json:"bankAccount,omitempty"`
 	BankName       string     `gorm:"size:100" json:"bankName,omitempty"`
 	TransactionID  string     `gorm:"type:text" json:"transactionId,omitempty"`
+	RejectReason   string     `gorm:"type:text" json:"rejectReason,omitempty"`
+	ApprovedAt     *time.Time `json:"approvedAt,omitempty"`
+	RejectedAt     *time.Time `json:"rejectedAt,omitempty"`
 	RefundedAt     *time.Time `json:"refundedAt,omitempty"`
 	CreatedAt      time.Time  `gorm:"not null;default:now()" json:"createdAt"`
 	UpdatedAt      time.Time  `gorm:"not null;default:now()" json:"updatedAt"`
@@ -25,6 +50,49 @@ type Refund struct {
 	RefundStatus *RefundStatus `gorm:"foreignKey:RefundStatusID;references:ID" json:"refundStatus,omitempty"`
 	Payment      *Payment      `gorm:"foreignKey:PaymentID;references:ID" json:"payment,omitempty"`
 	ShopOrder    *ShopOrder    `gorm:"foreignKey:ShopOrderID;references:ID" json:"shopOrder,omitempty"`
+
+	Evidences []RefundEvidence `gorm:"foreignKey:RefundID" json:"evidences,omitempty"`
+}
+
+type RefundEvidence struct {
+	ID         uint32     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RefundID   uuid.UUID  `gorm:"type:uuid;not null;index:idx_refund_evidences_refund_id" json:"refundId"`
+	URL        string     `gorm:"type:text;not null" json:"url"`
+	Note       string     `gorm:"type:text" json:"note,omitempty"`
+	UploadedBy *uuid.UUID `gorm:"type:uuid" json:"uploadedBy,omitempty"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()" json:"createdAt"`
+}
+
+type RefundEvidenceRequest struct {
+	URL  string `json:"url" validate:"required,url" example:"https://example.com/damaged-box.jpg"`
+	Note string `json:"note" validate:"omitempty,max=500"`
+}
+
+type RequestRefundRequest struct {
+	ShopOrderID uuid.UUID               `json:"shopOrderId" validate:"required"`
+	ReasonCode  string                  `json:"reasonCode" validate:"required,oneof=NOT_DELIVERED DAMAGED WRONG_ITEM" example:"DAMAGED"`
+	Reason      string                  `json:"reason" validate:"required,min=3,max=500"`
+	Amount      *float64                `json:"amount" validate:"omitempty,gt=0"`
+	Evidences   []RefundEvidenceRequest `json:"evidences" validate:"omitempty,max=10,dive"`
+}
+
+type AddRefundEvidenceRequest struct {
+	Evidences []RefundEvidenceRequest `json:"evidences" validate:"required,min=1,max=10,dive"`
+}
+
+type CounterOfferRefundRequest struct {
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	Note   string  `json:"note" validate:"required,min=3,max=500"`
+}
+
+type EscalateRefundRequest struct {
+	Note string `json:"note" validate:"required,min=3,max=500"`
+}
+
+type ResolveRefundRequest struct {
+	Approve bool     `json:"approve"`
+	Amount  *float64 `json:"amount" validate:"omitempty,gt=0"`
+	Note    string   `json:"note" validate:"required,min=3,max=500"`
 }
 
 type CreateRefundRequest struct {
@@ -36,6 +104,21 @@ type ApproveRefundRequest struct {
 	RefundID uuid.UUID `json:"refundId" validate:"required"`
 }
 
+type RejectRefundRequest struct {
+	Reason string `json:"reason" validate:"required,min=3,max=500"`
+}
+
+type CompleteRefundRequest struct {
+	TransactionID string `json:"transactionId" validate:"required,min=3,max=255"`
+}
+
+type RefundListRequest struct {
+	Page           uint64  `query:"page" validate:"omitempty,min=1" example:"1"`
+	PerPage        uint64  `query:"perPage" validate:"omitempty,min=1,max=100" example:"10"`
+	RefundStatusID *uint32 `query:"refundStatusId" validate:"omitempty,oneof=1 2 3 4 5 6"`
+	ShopOrderID    *string `query:"shopOrderId" validate:"omitempty,uuid"`
+}
+
 type SubmitRefundBankAccountRequest struct {
 	BankAccount string `json:"bankAccount" validate:"required,min=10,max=20"`
 	BankName    string `json:"bankName" validate:"required,min=2,max=100"`
@@ -49,10 +132,34 @@ type RefundResponse struct {
 	RefundMethodID *uint32    `json:"refundMethodId,omitempty"`
 	RefundStatusID uint32     `json:"refundStatusId"`
 	Reason         string     `json:"reason,omitempty"`
+	Initiator      string     `json:"initiator"`
+	ReasonCode     string     `json:"reasonCode,omitempty"`
+	CounterAmount  *float64   `json:"counterAmount,omitempty"`
+	CounterNote    string     `json:"counterNote,omitempty"`
+	ResponseDueAt  *time.Time `json:"responseDueAt,omitempty"`
+	EscalatedAt    *time.Time `json:"escalatedAt,omitempty"`
+	ResolutionNote string     `json:"resolutionNote,omitempty"`
 	BankAccount    string     `json:"bankAccount,omitempty"`
 	BankName       string     `json:"bankName,omitempty"`
 	TransactionID  string     `json:"transactionId,omitempty"`
+	RejectReason   string     `json:"rejectReason,omitempty"`
+	ApprovedAt     *time.Time `json:"approvedAt,omitempty"`
+	RejectedAt     *time.Time `json:"rejectedAt,omitempty"`
 	RefundedAt     *time.Time `json:"refundedAt,omitempty"`
 	CreatedAt      time.Time  `json:"createdAt"`
 	UpdatedAt      time.Time  `json:"updatedAt"`
+
+	Evidences []RefundEvidenceResponse `json:"evidences,omitempty"`
+}
+
+type RefundEvidenceResponse struct {
+	ID        uint32    `json:"id"`
+	URL       string    `json:"url"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type RefundListPaginationResponse struct {
+	Items []*RefundResponse `json:"items"`
+	Total int64             `json:"total"`
 }
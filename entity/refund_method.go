@@ -3,8 +3,22 @@ package entity
 const (
 	RefundMethodBankTransfer uint32 = 1
 	RefundMethodCreditCard   uint32 = 2
+	RefundMethodPromptPay    uint32 = 3
 )
 
+// RefundMethodForPayment returns the refund method that sends money back the
+// same way the buyer paid. COD and bank transfer payments are refunded by bank transfer.
+func RefundMethodForPayment(paymentMethodID uint32) uint32 {
+	switch paymentMethodID {
+	case PaymentMethodCreditCard:
+		return RefundMethodCreditCard
+	case PaymentMethodPromptPay:
+		return RefundMethodPromptPay
+	default:
+		return RefundMethodBankTransfer
+	}
+}
+
 type RefundMethod struct {
 	ID   uint32 `gorm:"primaryKey" json:"id"`
 	Code string `gorm:"size:50;not null;uniqueIndex" json:"code"`
@@ -5,6 +5,9 @@ const (
 	RefundStatusApproved  uint32 = 2
 	RefundStatusCompleted uint32 = 3
 	RefundStatusRejected  uint32 = 4
+	// Buyer-initiated requests only
+	RefundStatusCounterOffered uint32 = 5
+	RefundStatusEscalated      uint32 = 6
 )
 
 type RefundStatus struct {
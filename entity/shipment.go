@@ -7,34 +7,88 @@ import (
 )
 
 type Shipment struct {
-	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	ShopOrderID      uuid.UUID  `gorm:"type:uuid;not null" json:"shopOrderId"`
-	CourierID        uint32     `gorm:"not null" json:"courierId"`
-	TrackingNo       string     `gorm:"size:100;not null" json:"trackingNo"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ShopOrderID uuid.UUID `gorm:"type:uuid;not null" json:"shopOrderId"`
+	CourierID   uint32    `gorm:"not null" json:"courierId"`
+	TrackingNo  string    `gorm:"size:100;not null" json:"trackingNo"`
+	// ProviderCode is set when the shipment was booked through a CourierProvider.
+	ProviderCode     *string    `gorm:"size:50" json:"providerCode,omitempty"`
 	CreatedAt        time.Time  `gorm:"not null;default:now()" json:"createdAt"`
 	ShippedAt        *time.Time `json:"shippedAt"`
 	DeliveredAt      *time.Time `json:"deliveredAt"`
 	ShipmentStatusID uint32     `gorm:"not null;default:1" json:"shipmentStatusId"`
+	FailedAttempts   uint32     `gorm:"not null;default:0" json:"failedAttempts"`
 	UpdatedAt        time.Time  `gorm:"not null;default:now()" json:"updatedAt"`
 
 	ShopOrder      ShopOrder       `gorm:"foreignKey:ShopOrderID;references:ID" json:"shopOrder,omitempty"`
 	Courier        Courier         `gorm:"foreignKey:CourierID;references:ID" json:"courier,omitempty"`
 	ShipmentStatus *ShipmentStatus `gorm:"foreignKey:ShipmentStatusID;references:ID" json:"shipmentStatus,omitempty"`
+	Events         []ShipmentEvent `gorm:"foreignKey:ShipmentID" json:"events,omitempty"`
+	Items          []ShipmentItem  `gorm:"foreignKey:ShipmentID" json:"items,omitempty"`
+	// Reattempts are the redeliveries the buyer scheduled after failed attempts, oldest first.
+	Reattempts []DeliveryReattempt `gorm:"foreignKey:ShipmentID" json:"reattempts,omitempty"`
+}
+
+// ShipmentItem is the quantity of an order item packed in one parcel. A shop order is
+// fully shipped once its shipments cover every order item's quantity.
+type ShipmentItem struct {
+	ID          uint32    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShipmentID  uuid.UUID `gorm:"type:uuid;not null" json:"shipmentId"`
+	OrderItemID uint32    `gorm:"not null" json:"orderItemId"`
+	Qty         uint32    `gorm:"not null" json:"qty"`
+
+	OrderItem OrderItem `gorm:"foreignKey:OrderItemID;references:ID" json:"orderItem,omitempty"`
 }
 
 type AddShipmentRequest struct {
-	CourierID  uint32 `json:"courierId" validate:"required,gt=0"`
-	TrackingNo string `json:"trackingNo" validate:"required,min=3,max=100"`
+	CourierID uint32 `json:"courierId" validate:"required,gt=0"`
+	// TrackingNo is required for couriers without booking support. When omitted for a
+	// courier that supports booking, the pickup is booked and the courier assigns it.
+	TrackingNo string `json:"trackingNo" validate:"omitempty,min=3,max=100"`
+	// Items lists what goes in this parcel. When omitted, the parcel carries every item
+	// not yet covered by an earlier shipment.
+	Items []AddShipmentItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+type AddShipmentItemRequest struct {
+	OrderItemID uint32 `json:"orderItemId" validate:"required,gt=0"`
+	Qty         uint32 `json:"qty" validate:"required,gt=0"`
 }
 
 type ShipmentResponse struct {
-	ID               uuid.UUID               `json:"id"`
-	ShopOrderID      uuid.UUID               `json:"shopOrderId"`
-	CourierID        uint32                  `json:"courierId"`
-	Courier          *CourierListResponse    `json:"courier,omitempty"`
-	TrackingNo       string                  `json:"trackingNo"`
-	ShipmentStatusID uint32                  `json:"shipmentStatusId"`
-	ShipmentStatus   *ShipmentStatusResponse `json:"shipmentStatus,omitempty"`
-	CreatedAt        time.Time               `json:"createdAt"`
-	ShippedAt        *time.Time              `json:"shippedAt"`
+	ID               uuid.UUID                   `json:"id"`
+	ShopOrderID      uuid.UUID                   `json:"shopOrderId"`
+	CourierID        uint32                      `json:"courierId"`
+	Courier          *CourierListResponse        `json:"courier,omitempty"`
+	TrackingNo       string                      `json:"trackingNo"`
+	Booked           bool                        `json:"booked"`
+	ShipmentStatusID uint32                      `json:"shipmentStatusId"`
+	ShipmentStatus   *ShipmentStatusResponse     `json:"shipmentStatus,omitempty"`
+	FailedAttempts   uint32                      `json:"failedAttempts"`
+	CreatedAt        time.Time                   `json:"createdAt"`
+	ShippedAt        *time.Time                  `json:"shippedAt"`
+	DeliveredAt      *time.Time                  `json:"deliveredAt"`
+	Events           []ShipmentEventResponse     `json:"events"`
+	Items            []ShipmentItemResponse      `json:"items"`
+	Reattempts       []DeliveryReattemptResponse `json:"reattempts"`
+	// EstimatedDelivery is set for parcels still on their way.
+	EstimatedDelivery *DeliveryWindow `json:"estimatedDelivery,omitempty"`
+}
+
+type ShipmentItemResponse struct {
+	OrderItemID uint32 `json:"orderItemId"`
+	ProductID   uint32 `json:"productId"`
+	ProductName string `json:"productName"`
+	Qty         uint32 `json:"qty"`
+}
+
+// ShopOrderTrackingResponse shows every parcel of a shop order and what is still waiting
+// to be shipped.
+type ShopOrderTrackingResponse struct {
+	ShopOrderID  uuid.UUID `json:"shopOrderId"`
+	FullyShipped bool      `json:"fullyShipped"`
+	// EstimatedDelivery is the window promised at checkout.
+	EstimatedDelivery *DeliveryWindow        `json:"estimatedDelivery,omitempty"`
+	Shipments         []ShipmentResponse     `json:"shipments"`
+	UnshippedItems    []ShipmentItemResponse `json:"unshippedItems"`
 }
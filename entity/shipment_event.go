@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ShipmentEventSourceCourier = "COURIER"
+	ShipmentEventSourceShop    = "SHOP"
+	ShipmentEventSourceSystem  = "SYSTEM"
+	ShipmentEventSourceBuyer   = "BUYER"
+)
+
+type ShipmentEvent struct {
+	ID               uint32    `gorm:"primaryKey;autoIncrement" json:"id"`
+	ShipmentID       uuid.UUID `gorm:"type:uuid;not null;index:idx_shipment_events_shipment_id" json:"shipmentId"`
+	ShipmentStatusID uint32    `gorm:"not null" json:"shipmentStatusId"`
+	Source           string    `gorm:"size:20;not null" json:"source"`
+	ExternalEventID  *string   `gorm:"size:100" json:"externalEventId,omitempty"`
+	Description      string    `gorm:"type:text" json:"description,omitempty"`
+	Location         string    `gorm:"size:255" json:"location,omitempty"`
+	OccurredAt       time.Time `gorm:"not null" json:"occurredAt"`
+	CreatedAt        time.Time `gorm:"not null;default:now()" json:"createdAt"`
+
+	ShipmentStatus *ShipmentStatus `gorm:"foreignKey:ShipmentStatusID;references:ID" json:"shipmentStatus,omitempty"`
+}
+
+// CourierWebhookRequest is the tracking update payload couriers POST to
+// /api/webhooks/couriers/:courierId, signed with the courier's webhook secret.
+type CourierWebhookRequest struct {
+	EventID     string    `json:"eventId" example:"evt_01HZX3"`
+	TrackingNo  string    `json:"trackingNo" example:"TH0123456789"`
+	Status      string    `json:"status" example:"OUT_FOR_DELIVERY"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+type ShipmentEventResponse struct {
+	ID               uint32                  `json:"id"`
+	ShipmentStatusID uint32                  `json:"shipmentStatusId"`
+	ShipmentStatus   *ShipmentStatusResponse `json:"shipmentStatus,omitempty"`
+	Source           string                  `json:"source"`
+	Description      string                  `json:"description,omitempty"`
+	Location         string                  `json:"location,omitempty"`
+	OccurredAt       time.Time               `json:"occurredAt"`
+}
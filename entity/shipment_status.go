@@ -1,11 +1,24 @@
 package entity
 
 const (
-	ShipmentStatusInTransit      uint32 = 1
-	ShipmentStatusDelivered      uint32 = 2
-	ShipmentStatusFailedDelivery uint32 = 3
+	ShipmentStatusInTransit        uint32 = 1
+	ShipmentStatusDelivered        uint32 = 2
+	ShipmentStatusFailedDelivery   uint32 = 3
+	ShipmentStatusPickedUp         uint32 = 4
+	ShipmentStatusOutForDelivery   uint32 = 5
+	ShipmentStatusReturnedToSender uint32 = 6
 )
 
+// ShipmentStatusByCode maps the status codes couriers send in webhooks to shipment status IDs.
+var ShipmentStatusByCode = map[string]uint32{
+	"PICKED_UP":          ShipmentStatusPickedUp,
+	"IN_TRANSIT":         ShipmentStatusInTransit,
+	"OUT_FOR_DELIVERY":   ShipmentStatusOutForDelivery,
+	"DELIVERED":          ShipmentStatusDelivered,
+	"FAILED_DELIVERY":    ShipmentStatusFailedDelivery,
+	"RETURNED_TO_SENDER": ShipmentStatusReturnedToSender,
+}
+
 type ShipmentStatus struct {
 	ID   uint32 `gorm:"primaryKey" json:"id"`
 	Code string `gorm:"size:50;not null" json:"code"`
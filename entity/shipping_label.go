@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShippingLabel is everything printed for one parcel: the courier label and the packing
+// slip that goes inside it.
+type ShippingLabel struct {
+	OrderNumber string
+	// ParcelNo numbers the parcel when a shop order ships in several; zero for a single parcel.
+	ParcelNo    int
+	CourierName string
+	TrackingNo  string
+	SortCode    string
+	Sender      CourierParty
+	Recipient   CourierParty
+	Items       []ShippingLabelItem
+	Subtotal    float64
+	Shipping    float64
+	GrandTotal  float64
+	OrderedAt   time.Time
+}
+
+type ShippingLabelItem struct {
+	Name      string
+	Qty       uint32
+	UnitPrice float64
+	Subtotal  float64
+}
+
+type BulkShippingLabelRequest struct {
+	ShopOrderIDs []uuid.UUID `json:"shopOrderIds" validate:"required,min=1,max=50"`
+}
@@ -8,16 +8,23 @@ import (
 )
 
 type Shop struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID      uuid.UUID      `gorm:"type:uuid;not null;index:idx_shops_user_id" json:"userId"`
-	Name        string         `gorm:"size:255;not null" json:"name"`
-	Description string         `gorm:"type:text" json:"description"`
-	ImageURL    string         `gorm:"type:text" json:"imageUrl"`
-	Address     string         `gorm:"type:text" json:"address"`
-	IsActive    bool           `gorm:"default:true;index:idx_shops_is_active" json:"isActive"`
-	CreatedAt   time.Time      `gorm:"not null;default:now()" json:"createdAt"`
-	UpdatedAt   time.Time      `gorm:"not null;default:now()" json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"default:null" json:"deletedAt"`
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null;index:idx_shops_user_id" json:"userId"`
+	Name        string    `gorm:"size:255;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	ImageURL    string    `gorm:"type:text" json:"imageUrl"`
+	Address     string    `gorm:"type:text" json:"address"`
+	// ProvinceID is where parcels ship from, used for delivery estimates.
+	ProvinceID *uint32 `json:"provinceId"`
+	// HandlingDays is how many business days the shop takes to hand an order to the courier.
+	HandlingDays uint32 `gorm:"not null;default:1" json:"handlingDays"`
+	// AutoCompleteDays overrides how long delivered orders wait for the buyer before they
+	// are completed automatically. Nil uses the platform default.
+	AutoCompleteDays *uint32        `json:"autoCompleteDays"`
+	IsActive         bool           `gorm:"default:true;index:idx_shops_is_active" json:"isActive"`
+	CreatedAt        time.Time      `gorm:"not null;default:now()" json:"createdAt"`
+	UpdatedAt        time.Time      `gorm:"not null;default:now()" json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt `gorm:"default:null" json:"deletedAt"`
 
 	User *User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
 }
@@ -30,10 +37,14 @@ type CreateShopRequest struct {
 }
 
 type UpdateShopRequest struct {
-	Name        *string `json:"name" validate:"required,max=255"`
-	Description *string `json:"description"`
-	ImageURL    *string `json:"imageUrl"`
-	Address     *string `json:"address"`
+	Name         *string `json:"name" validate:"required,max=255"`
+	Description  *string `json:"description"`
+	ImageURL     *string `json:"imageUrl"`
+	Address      *string `json:"address"`
+	ProvinceID   *uint32 `json:"provinceId" validate:"omitempty,gt=0"`
+	HandlingDays *uint32 `json:"handlingDays" validate:"omitempty,lte=14"`
+	// AutoCompleteDays of 0 goes back to the platform default.
+	AutoCompleteDays *uint32 `json:"autoCompleteDays" validate:"omitempty,min=2,max=30"`
 }
 
 type ShopListRequest struct {
@@ -48,13 +59,16 @@ type ShopListResponse struct {
 }
 
 type ShopResponse struct {
-	ID          uuid.UUID `json:"id"`
-	UserID      uuid.UUID `json:"userId"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"imageUrl"`
-	Address     string    `json:"address"`
-	IsActive    bool      `json:"isActive"`
+	ID               uuid.UUID `json:"id"`
+	UserID           uuid.UUID `json:"userId"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	ImageURL         string    `json:"imageUrl"`
+	Address          string    `json:"address"`
+	ProvinceID       *uint32   `json:"provinceId"`
+	HandlingDays     uint32    `json:"handlingDays"`
+	AutoCompleteDays *uint32   `json:"autoCompleteDays"`
+	IsActive         bool      `json:"isActive"`
 }
 
 type UpdateShopCouriersRequest struct {
@@ -69,3 +83,22 @@ type ShopCourierResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+// ShopFinanceRequest is a range of days, in Thai time, by when the orders were placed.
+// It defaults to the last 30 days.
+type ShopFinanceRequest struct {
+	From string `query:"from" validate:"omitempty,datetime=2006-01-02" example:"2026-01-01"`
+	To   string `query:"to" validate:"omitempty,datetime=2006-01-02" example:"2026-01-31"`
+}
+
+// ShopFinanceSummary sums a shop's paid orders. CompletedSales is the part of GrossSales
+// whose orders are completed; NetSales is GrossSales less the refunds paid out.
+type ShopFinanceSummary struct {
+	From           string  `json:"from"`
+	To             string  `json:"to"`
+	PaidOrders     int64   `json:"paidOrders"`
+	GrossSales     float64 `json:"grossSales"`
+	CompletedSales float64 `json:"completedSales"`
+	Refunded       float64 `json:"refunded"`
+	NetSales       float64 `json:"netSales"`
+}
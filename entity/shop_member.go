@@ -0,0 +1,118 @@
+package entity
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Shop member roles. Every shop has one OWNER, the user who registered it; the others
+// are staff the owner invited.
+const (
+	ShopRoleOwner   = "OWNER"
+	ShopRoleManager = "MANAGER"
+	ShopRolePacker  = "PACKER"
+	ShopRoleSupport = "SUPPORT"
+)
+
+// Shop permissions. Every member can view the shop, its products, orders and refunds;
+// changing them takes a permission.
+const (
+	ShopPermissionManageProducts = "MANAGE_PRODUCTS"
+	ShopPermissionFulfilOrders   = "FULFIL_ORDERS"
+	ShopPermissionIssueRefunds   = "ISSUE_REFUNDS"
+	ShopPermissionViewFinance    = "VIEW_FINANCE"
+	// ShopPermissionManageShop covers the shop's profile, couriers and webhooks.
+	ShopPermissionManageShop    = "MANAGE_SHOP"
+	ShopPermissionManageMembers = "MANAGE_MEMBERS"
+)
+
+// ShopRolePermissions are the permissions of each member role.
+var ShopRolePermissions = map[string][]string{
+	ShopRoleOwner: {
+		ShopPermissionManageProducts,
+		ShopPermissionFulfilOrders,
+		ShopPermissionIssueRefunds,
+		ShopPermissionViewFinance,
+		ShopPermissionManageShop,
+		ShopPermissionManageMembers,
+	},
+	ShopRoleManager: {
+		ShopPermissionManageProducts,
+		ShopPermissionFulfilOrders,
+		ShopPermissionIssueRefunds,
+		ShopPermissionViewFinance,
+	},
+	ShopRolePacker:  {ShopPermissionFulfilOrders},
+	ShopRoleSupport: {ShopPermissionIssueRefunds},
+}
+
+// ShopMember gives a user access to a shop. A user is a member of at most one shop.
+type ShopMember struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ShopID    uuid.UUID `gorm:"type:uuid;not null" json:"shopId"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"userId"`
+	Role      string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"not null;default:now()" json:"updatedAt"`
+
+	Shop Shop  `gorm:"foreignKey:ShopID;references:ID" json:"-"`
+	User *User `gorm:"foreignKey:UserID;references:ID" json:"-"`
+}
+
+// Can reports whether the member's role has the permission. It is false for a nil
+// member, so the result of a membership lookup can be checked directly.
+func (m *ShopMember) Can(permission string) bool {
+	return m != nil && slices.Contains(ShopRolePermissions[m.Role], permission)
+}
+
+// ShopInvitation invites the owner of an email address to join a shop. Only the hash of
+// its token is stored; the token itself is only in the invitation email.
+type ShopInvitation struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ShopID     uuid.UUID  `gorm:"type:uuid;not null" json:"shopId"`
+	Email      string     `gorm:"size:255;not null" json:"email"`
+	Role       string     `gorm:"size:20;not null" json:"role"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	InvitedBy  uuid.UUID  `gorm:"type:uuid;not null" json:"invitedBy"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expiresAt"`
+	AcceptedAt *time.Time `json:"acceptedAt"`
+	CreatedAt  time.Time  `gorm:"not null;default:now()" json:"createdAt"`
+
+	Shop Shop `gorm:"foreignKey:ShopID;references:ID" json:"-"`
+}
+
+type ShopMemberResponse struct {
+	UserID      uuid.UUID `json:"userId"`
+	ShopID      uuid.UUID `json:"shopId"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	Role        string    `json:"role"`
+	Permissions []string  `json:"permissions"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ShopInvitationResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// InviteShopMemberRequest invites staff. The owner role cannot be given, since a shop
+// has one owner.
+type InviteShopMemberRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	Role  string `json:"role" validate:"required,oneof=MANAGER PACKER SUPPORT"`
+}
+
+type UpdateShopMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=MANAGER PACKER SUPPORT"`
+}
+
+type AcceptShopInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
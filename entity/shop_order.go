@@ -7,18 +7,28 @@ import (
 )
 
 type ShopOrder struct {
-	ID            uuid.UUID    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	OrderID       uuid.UUID    `gorm:"type:uuid;not null;index:idx_shop_orders_order_id;uniqueIndex:uq_shop_orders_order_shop" json:"orderId"`
-	ShopID        uuid.UUID    `gorm:"type:uuid;not null;index:idx_shop_orders_shop_id;index:idx_shop_orders_shop_status;index:idx_shop_orders_shop_created;uniqueIndex:uq_shop_orders_order_shop" json:"shopId"`
-	OrderNumber   string       `gorm:"size:20;not null;uniqueIndex" json:"orderNumber"`
-	OrderStatusID uint32       `gorm:"not null" json:"orderStatusId"`
-	Subtotal      float64      `gorm:"type:decimal(10,2);not null" json:"subtotal"`
-	Shipping      float64      `gorm:"type:decimal(10,2);not null" json:"shipping"`
-	GrandTotal    float64      `gorm:"type:decimal(10,2);not null" json:"grandTotal"`
-	CreatedAt     time.Time    `gorm:"not null;default:now();index:idx_shop_orders_shop_created" json:"createdAt"`
-	UpdatedAt     time.Time    `gorm:"not null;default:now()" json:"updatedAt"`
-	Order         Order        `gorm:"foreignKey:OrderID;references:ID" json:"order,omitempty"`
-	Shop          Shop         `gorm:"foreignKey:ShopID;references:ID" json:"shop,omitempty"`
-	OrderItems    []OrderItem  `gorm:"foreignKey:ShopOrderID" json:"orderItems,omitempty"`
-	OrderStatus   *OrderStatus `gorm:"foreignKey:OrderStatusID;references:ID" json:"orderStatus,omitempty"`
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	OrderID       uuid.UUID `gorm:"type:uuid;not null;index:idx_shop_orders_order_id;uniqueIndex:uq_shop_orders_order_shop" json:"orderId"`
+	ShopID        uuid.UUID `gorm:"type:uuid;not null;index:idx_shop_orders_shop_id;index:idx_shop_orders_shop_status;index:idx_shop_orders_shop_created;uniqueIndex:uq_shop_orders_order_shop" json:"shopId"`
+	OrderNumber   string    `gorm:"size:20;not null;uniqueIndex" json:"orderNumber"`
+	OrderStatusID uint32    `gorm:"not null" json:"orderStatusId"`
+	Subtotal      float64   `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	Shipping      float64   `gorm:"type:decimal(10,2);not null" json:"shipping"`
+	GrandTotal    float64   `gorm:"type:decimal(10,2);not null" json:"grandTotal"`
+	// EstimatedDeliveryFrom and EstimatedDeliveryTo are the delivery window promised at checkout.
+	EstimatedDeliveryFrom *time.Time `gorm:"type:date" json:"estimatedDeliveryFrom,omitempty"`
+	EstimatedDeliveryTo   *time.Time `gorm:"type:date" json:"estimatedDeliveryTo,omitempty"`
+	// DeliveredAt starts the auto-complete window; AutoCompleteRemindedAt is when the buyer
+	// was told the order is about to be completed.
+	DeliveredAt            *time.Time `json:"deliveredAt,omitempty"`
+	AutoCompleteRemindedAt *time.Time `json:"autoCompleteRemindedAt,omitempty"`
+	// ClaimedBy and ClaimedUntil lease the order to the scheduler instance processing it.
+	ClaimedBy    *string      `gorm:"size:100" json:"-"`
+	ClaimedUntil *time.Time   `json:"-"`
+	CreatedAt    time.Time    `gorm:"not null;default:now();index:idx_shop_orders_shop_created" json:"createdAt"`
+	UpdatedAt    time.Time    `gorm:"not null;default:now()" json:"updatedAt"`
+	Order        Order        `gorm:"foreignKey:OrderID;references:ID" json:"order,omitempty"`
+	Shop         Shop         `gorm:"foreignKey:ShopID;references:ID" json:"shop,omitempty"`
+	OrderItems   []OrderItem  `gorm:"foreignKey:ShopOrderID" json:"orderItems,omitempty"`
+	OrderStatus  *OrderStatus `gorm:"foreignKey:OrderStatusID;references:ID" json:"orderStatus,omitempty"`
 }
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventTypes are the events a shop can subscribe its webhooks to. order.placed and
+// payment.submitted are sent once per shop order, with the shop's part of the order.
+var WebhookEventTypes = []string{
+	EventOrderPlaced,
+	EventPaymentSubmitted,
+	EventShopOrderShipped,
+	EventShopOrderDelivered,
+	EventShopOrderCompleted,
+	EventShopOrderCancelled,
+	EventParcelReturned,
+	EventRefundRequested,
+	EventRefundApproved,
+	EventRefundCompleted,
+}
+
+const (
+	WebhookDeliveryStatusPending   = "PENDING"
+	WebhookDeliveryStatusSucceeded = "SUCCEEDED"
+	// WebhookDeliveryStatusFailed means the delivery ran out of attempts. The shop can
+	// still redeliver it.
+	WebhookDeliveryStatusFailed = "FAILED"
+)
+
+// ShopWebhook is an endpoint a shop receives events on. Secret signs every delivery and
+// is stored encrypted; it is only returned when the webhook is created or its secret is
+// rotated.
+type ShopWebhook struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ShopID      uuid.UUID `gorm:"type:uuid;not null" json:"shopId"`
+	URL         string    `gorm:"size:500;not null" json:"url"`
+	Description string    `gorm:"size:255" json:"description"`
+	Secret      string    `gorm:"type:text;not null" json:"-"`
+	EventTypes  []string  `gorm:"type:jsonb;serializer:json;not null" json:"eventTypes"`
+	IsActive    bool      `gorm:"not null;default:true" json:"isActive"`
+	CreatedAt   time.Time `gorm:"not null" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"not null" json:"updatedAt"`
+}
+
+// WebhookDelivery is one event sent to one webhook. Payload is the exact body posted,
+// so a redelivery sends the same body again.
+type WebhookDelivery struct {
+	ID                 uuid.UUID       `gorm:"type:uuid;primaryKey" json:"id"`
+	WebhookID          uuid.UUID       `gorm:"type:uuid;not null" json:"webhookId"`
+	EventID            uuid.UUID       `gorm:"type:uuid;not null" json:"eventId"`
+	EventType          string          `gorm:"size:100;not null" json:"eventType"`
+	Payload            json.RawMessage `gorm:"type:jsonb;serializer:json;not null" json:"payload"`
+	Status             string          `gorm:"size:20;not null;default:PENDING" json:"status"`
+	Attempts           int             `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt      time.Time       `gorm:"not null" json:"nextAttemptAt"`
+	LastResponseStatus *int            `json:"lastResponseStatus"`
+	LastError          string          `gorm:"type:text" json:"lastError,omitempty"`
+	ClaimedBy          *string         `gorm:"size:100" json:"-"`
+	ClaimedUntil       *time.Time      `json:"-"`
+	CreatedAt          time.Time       `gorm:"not null" json:"createdAt"`
+	DeliveredAt        *time.Time      `json:"deliveredAt"`
+
+	Webhook          *ShopWebhook             `gorm:"foreignKey:WebhookID" json:"-"`
+	DeliveryAttempts []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID" json:"deliveryAttempts,omitempty"`
+}
+
+// WebhookDeliveryAttempt is the log of one POST of a delivery.
+type WebhookDeliveryAttempt struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	DeliveryID     uuid.UUID `gorm:"type:uuid;not null" json:"deliveryId"`
+	ResponseStatus *int      `json:"responseStatus"`
+	ResponseBody   string    `gorm:"type:text" json:"responseBody,omitempty"`
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	DurationMs     int64     `gorm:"not null" json:"durationMs"`
+	CreatedAt      time.Time `gorm:"not null" json:"createdAt"`
+}
+
+// WebhookEnvelope is the body posted to a shop's webhook. ID stays the same across
+// retries and redeliveries, so receivers can use it to drop duplicates.
+type WebhookEnvelope struct {
+	ID        uuid.UUID       `json:"id"`
+	EventID   uuid.UUID       `json:"eventId"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ShopOrderPlacedPayload is the data of order.placed and payment.submitted webhooks: the
+// part of the order placed with the receiving shop.
+type ShopOrderPlacedPayload struct {
+	ShopOrderPayload
+	GrandTotal float64 `json:"grandTotal"`
+}
+
+type CreateShopWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=500" example:"https://erp.example.com/hooks/marketplace"`
+	Description string   `json:"description" validate:"omitempty,max=255" example:"ERP order sync"`
+	EventTypes  []string `json:"eventTypes" validate:"required,min=1,dive,required,max=100" example:"order.placed,shop_order.cancelled"`
+}
+
+type UpdateShopWebhookRequest struct {
+	URL         *string  `json:"url" validate:"omitempty,url,max=500"`
+	Description *string  `json:"description" validate:"omitempty,max=255"`
+	EventTypes  []string `json:"eventTypes" validate:"omitempty,min=1,dive,required,max=100"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+type ShopWebhookResponse struct {
+	ID          uuid.UUID `json:"id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description"`
+	EventTypes  []string  `json:"eventTypes"`
+	IsActive    bool      `json:"isActive"`
+	// Secret is only returned when the webhook is created or its secret is rotated.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type WebhookDeliveryListRequest struct {
+	Page      uint64 `query:"page" validate:"omitempty,min=1" example:"1"`
+	PerPage   uint64 `query:"perPage" validate:"omitempty,min=1,max=100" example:"20"`
+	Status    string `query:"status" validate:"omitempty,oneof=PENDING SUCCEEDED FAILED" example:"FAILED"`
+	EventType string `query:"eventType" validate:"omitempty,max=100"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Items []*WebhookDelivery `json:"items"`
+	Total int64              `json:"total"`
+}
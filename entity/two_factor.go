@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorRecoveryCode is a single-use code that replaces a TOTP code when the user's
+// phone is lost. Only its SHA-256 hash is stored.
+type TwoFactorRecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"userId"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"`
+	UsedAt    *time.Time `json:"usedAt"`
+	CreatedAt time.Time  `gorm:"not null" json:"createdAt"`
+}
+
+// TwoFactorLoginRequest is the second step of a login. Code is the 6-digit code from the
+// authenticator app or one of the recovery codes.
+type TwoFactorLoginRequest struct {
+	TwoFactorToken string `json:"twoFactorToken" validate:"required" example:"token_from_the_login_response"`
+	Code           string `json:"code" validate:"required,max=20" example:"123456"`
+}
+
+// TwoFactorCodeRequest confirms an action with the 6-digit code from the authenticator
+// app or, unless noted otherwise, a recovery code.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=20" example:"123456"`
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" validate:"required" example:"yourpassword"`
+	Code     string `json:"code" validate:"required,max=20" example:"123456"`
+}
+
+type SetTwoFactorRequiredRequest struct {
+	Required *bool `json:"required" validate:"required" example:"true"`
+}
+
+// TwoFactorSetupResponse is shown once while the user adds the account to their
+// authenticator app, usually as a QR code of ProvisioningURI.
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// TwoFactorRecoveryCodesResponse lists new recovery codes. They are shown only once.
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabledAt"`
+	Required               bool       `json:"required"`
+	RecoveryCodesRemaining int64      `json:"recoveryCodesRemaining"`
+}
@@ -8,16 +8,23 @@ import (
 )
 
 type User struct {
-	ID          uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	FirstName   string         `gorm:"type:varchar(255);not null" json:"firstName"`
-	LastName    string         `gorm:"type:varchar(255);not null" json:"lastName"`
-	Email       string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
-	Password    string         `gorm:"type:text;not null" json:"-"`
-	PhoneNumber string         `gorm:"type:varchar(15);not null;index" json:"phoneNumber"`
-	ImageURL    *string        `gorm:"type:text" json:"imageUrl,omitempty"`
-	CreatedAt   time.Time      `gorm:"default:now()" json:"createdAt"`
-	UpdatedAt   time.Time      `gorm:"default:now()" json:"updatedAt"`
-	DeletedAt   gorm.DeletedAt `gorm:"default:null" json:"deletedAt"`
+	ID                 uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	FirstName          string         `gorm:"type:varchar(255);not null" json:"firstName"`
+	LastName           string         `gorm:"type:varchar(255);not null" json:"lastName"`
+	Email              string         `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
+	Password           string         `gorm:"type:text;not null" json:"-"`
+	PhoneNumber        string         `gorm:"type:text;not null;serializer:fieldcrypt" json:"phoneNumber"`
+	ImageURL           *string        `gorm:"type:text" json:"imageUrl,omitempty"`
+	Language           string         `gorm:"size:5;not null;default:th" json:"language"`
+	EmailVerifiedAt    *time.Time     `json:"emailVerifiedAt"`
+	PasswordChangedAt  *time.Time     `json:"-"`
+	TwoFactorSecret    string         `gorm:"type:text;not null" json:"-"`
+	TwoFactorEnabledAt *time.Time     `json:"twoFactorEnabledAt"`
+	TwoFactorLastStep  int64          `gorm:"not null;default:0" json:"-"`
+	TwoFactorRequired  bool           `gorm:"not null;default:false" json:"twoFactorRequired"`
+	CreatedAt          time.Time      `gorm:"default:now()" json:"createdAt"`
+	UpdatedAt          time.Time      `gorm:"default:now()" json:"updatedAt"`
+	DeletedAt          gorm.DeletedAt `gorm:"default:null" json:"deletedAt"`
 }
 
 type RegisterRequest struct {
@@ -70,24 +77,52 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required" example:"yourpassword"`
 }
 
+// AuthResponse holds the tokens of a new session. When the account has two-factor
+// authentication, Login returns only TwoFactorRequired and TwoFactorToken, and the tokens
+// come from POST /auth/2fa/login once the code is checked.
 type AuthResponse struct {
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
+	AccessToken       string `json:"accessToken,omitempty"`
+	RefreshToken      string `json:"refreshToken,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	TwoFactorToken    string `json:"twoFactorToken,omitempty"`
+	// TwoFactorSetupRequired is set when an admin requires two-factor authentication the
+	// user has not set up yet. Sensitive actions are refused until they do.
+	TwoFactorSetupRequired bool `json:"twoFactorSetupRequired,omitempty"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required" example:"your_refresh_token"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required" example:"token_from_the_email_link"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email" example:"kiattisak.c@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required" example:"token_from_the_email_link"`
+	NewPassword string `json:"newPassword" validate:"required,min=8" example:"mynewpassword"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required" example:"yourpassword"`
+	NewPassword     string `json:"newPassword" validate:"required,min=8,nefield=CurrentPassword" example:"mynewpassword"`
+}
+
 type UserResponse struct {
-	ID          uuid.UUID `json:"id"`
-	FirstName   string    `json:"firstName"`
-	LastName    string    `json:"lastName"`
-	Email       string    `json:"email"`
-	PhoneNumber string    `json:"phoneNumber"`
-	ImageURL    *string   `json:"imageUrl,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID              uuid.UUID  `json:"id"`
+	FirstName       string     `json:"firstName"`
+	LastName        string     `json:"lastName"`
+	Email           string     `json:"email"`
+	PhoneNumber     string     `json:"phoneNumber"`
+	ImageURL        *string    `json:"imageUrl,omitempty"`
+	Language        string     `json:"language"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
 }
 
 type UpdateProfileRequest struct {
@@ -95,4 +130,5 @@ type UpdateProfileRequest struct {
 	LastName    *string `json:"lastName" validate:"omitempty,min=1" example:"Chantharamaneechote"`
 	PhoneNumber *string `json:"phoneNumber" validate:"omitempty,min=10" example:"0900000000"`
 	ImageURL    *string `json:"imageUrl" example:"https://example.com/image.jpg"`
+	Language    *string `json:"language" validate:"omitempty,oneof=th en" example:"en"`
 }
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links an account at an external identity provider, e.g. Google, to a
+// user. Subject is the provider's stable ID of the account; Email is the one it had when
+// it was linked.
+type UserIdentity struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null" json:"-"`
+	Provider   string    `gorm:"size:30;not null" json:"provider"`
+	Subject    string    `gorm:"size:255;not null" json:"-"`
+	Email      string    `gorm:"size:255;not null" json:"email"`
+	CreatedAt  time.Time `gorm:"not null" json:"createdAt"`
+	LastUsedAt time.Time `gorm:"not null" json:"lastUsedAt"`
+}
+
+// OAuthState is a login in progress at a provider. Only the hash of the state sent to
+// the provider is stored; the nonce and PKCE code verifier are checked when the user
+// comes back with the code.
+type OAuthState struct {
+	StateHash    string    `gorm:"size:64;primaryKey"`
+	Provider     string    `gorm:"size:30;not null"`
+	Nonce        string    `gorm:"size:64;not null"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"not null"`
+}
+
+// OAuthIdentity is the account a provider signed the user in with.
+type OAuthIdentity struct {
+	Subject string
+	Email   string
+	// EmailVerified is true when the provider has confirmed the user owns Email.
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+	ImageURL      string
+}
+
+type OAuthProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// OAuthAuthorizeResponse is where the client sends the user to sign in. State comes
+// back with the code and is only valid for one login.
+type OAuthAuthorizeResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+// OAuthCallbackRequest carries the code and state the provider redirected the user
+// back with.
+type OAuthCallbackRequest struct {
+	Code  string `json:"code" validate:"required,max=2048" example:"4/0AbCD..."`
+	State string `json:"state" validate:"required,max=128" example:"state_from_the_authorize_response"`
+}
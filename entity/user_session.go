@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SessionRevokedLogout         = "LOGOUT"
+	SessionRevokedLogoutAll      = "LOGOUT_ALL"
+	SessionRevokedTokenReuse     = "TOKEN_REUSE"
+	SessionRevokedPasswordChange = "PASSWORD_CHANGE"
+	SessionRevokedTwoFactorReset = "TWO_FACTOR_RESET"
+	// SessionRevokedEmailClaimed revokes the sessions of an unverified account when the
+	// owner of its email signs in with a provider that verified it.
+	SessionRevokedEmailClaimed = "EMAIL_CLAIMED"
+)
+
+// UserSession is a device the user signed in on. Each refresh of the session uses up its
+// refresh token and issues the next one; ExpiresAt moves with the latest token.
+type UserSession struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null" json:"userId"`
+	UserAgent     string     `gorm:"size:500;not null" json:"userAgent"`
+	IPAddress     string     `gorm:"size:45;not null" json:"ipAddress"`
+	CreatedAt     time.Time  `gorm:"not null" json:"createdAt"`
+	LastUsedAt    time.Time  `gorm:"not null" json:"lastUsedAt"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt     *time.Time `json:"revokedAt"`
+	RevokedReason *string    `gorm:"size:30" json:"revokedReason"`
+}
+
+// RefreshToken is one refresh token of a session. Only its SHA-256 hash is stored. A
+// token is used once; presenting a used token again means it leaked, and the whole
+// session is revoked.
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	SessionID uuid.UUID  `gorm:"type:uuid;not null" json:"sessionId"`
+	TokenHash string     `gorm:"size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+	CreatedAt time.Time  `gorm:"not null" json:"createdAt"`
+
+	Session *UserSession `gorm:"foreignKey:SessionID" json:"-"`
+}
+
+// ClientInfo describes the device a request came from.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required" example:"your_refresh_token"`
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	// Current is set on the session of the access token the sessions were listed with.
+	Current bool `json:"current"`
+}
+
+type LogoutAllResponse struct {
+	Revoked int64 `json:"revoked"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UserTokenEmailVerification = "EMAIL_VERIFICATION"
+	UserTokenPasswordReset     = "PASSWORD_RESET"
+	// UserTokenTwoFactorLogin carries a login from its password step to its 2FA step. It
+	// is returned by Login rather than emailed.
+	UserTokenTwoFactorLogin = "TWO_FACTOR_LOGIN"
+)
+
+// UserToken is a single-use token emailed to a user to verify their email or reset their
+// password. Only the SHA-256 hash of the token is stored, so the tokens cannot be read
+// back from the database.
+type UserToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"userId"`
+	Purpose   string     `gorm:"size:30;not null" json:"purpose"`
+	TokenHash string     `gorm:"size:64;not null" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expiresAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+	CreatedAt time.Time  `gorm:"not null" json:"createdAt"`
+}
@@ -2,16 +2,25 @@ package delivery
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
+	"ecommerce-go-api/config"
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
 	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/jwt"
+	"ecommerce-go-api/internal/loginguard"
+	"ecommerce-go-api/internal/oidc"
 	"ecommerce-go-api/internal/response"
 
 	authRepo "ecommerce-go-api/feature/auth/repository"
 	authUsecase "ecommerce-go-api/feature/auth/usecase"
+	notificationRepo "ecommerce-go-api/feature/notification/repository"
+	"ecommerce-go-api/middleware"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
@@ -89,13 +98,15 @@ func (h *AuthHandler) RegisterShop(c echo.Context) error {
 // Login godoc
 //
 //	@Summary		Login
-//	@Description	Authenticate user and return access & refresh tokens
+//	@Description	Authenticate user and return access & refresh tokens. Accounts with two-factor authentication get twoFactorRequired and a twoFactorToken instead, to finish the login at /api/auth/2fa/login. Repeated failures of an account or IP address delay, then lock, further attempts; the Retry-After header tells how long to wait.
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
 //	@Param			body	body		entity.LoginRequest	true	"Login payload"
 //	@Success		200		{object}	entity.AuthResponse
+//	@Failure		400		{object}	response.ResponseError
 //	@Failure		401		{object}	response.ResponseError
+//	@Failure		429		{object}	response.ResponseError
 //	@Failure		500		{object}	response.ResponseError
 //	@Router			/api/auth/login [post]
 func (h *AuthHandler) Login(c echo.Context) error {
@@ -104,13 +115,23 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err.Error())
 	}
 
-	loginResponse, err := h.authUsecase.Login(c.Request().Context(), &req)
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	loginResponse, err := h.authUsecase.Login(c.Request().Context(), &req, clientInfo(c))
 	if err != nil {
-		statusCode := http.StatusInternalServerError
-		if errors.Is(err, errmap.ErrInvalidCredentials) {
-			statusCode = http.StatusUnauthorized
+		var blocked *errmap.LoginBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+			return response.Error(c, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, errmap.ErrInvalidCredentials):
+			return response.Error(c, http.StatusUnauthorized, err.Error())
+		default:
+			c.Logger().Error("Login error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
 		}
-		return response.Error(c, statusCode, errmap.ErrInvalidCredentials.Error())
 	}
 
 	return response.Success(c, http.StatusOK, "Login successful", loginResponse)
@@ -119,7 +140,7 @@ func (h *AuthHandler) Login(c echo.Context) error {
 // RefreshToken godoc
 //
 //	@Summary		Refresh access token
-//	@Description	Refresh access token using refresh token
+//	@Description	Exchange a refresh token for a new access and refresh token pair. Each refresh token can be used once; using one again signs its session out.
 //	@Tags			Auth
 //	@Accept			json
 //	@Produce		json
@@ -139,7 +160,7 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err.Error())
 	}
 
-	loginResponse, err := h.authUsecase.RefreshToken(c.Request().Context(), &req)
+	loginResponse, err := h.authUsecase.RefreshToken(c.Request().Context(), &req, clientInfo(c))
 	if err != nil {
 		switch {
 		case errors.Is(err, errmap.ErrInvalidRefreshToken):
@@ -153,9 +174,918 @@ func (h *AuthHandler) RefreshToken(c echo.Context) error {
 	return response.Success(c, http.StatusOK, "Token refreshed successfully", loginResponse)
 }
 
+// VerifyEmail godoc
+//
+//	@Summary		Verify email
+//	@Description	Mark the user's email as verified with the token from their verification email. Each token can be used once.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.VerifyEmailRequest	true	"Verify email payload"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c echo.Context) error {
+	var req entity.VerifyEmailRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.VerifyEmail(c.Request().Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidUserToken):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			c.Logger().Error("VerifyEmail error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+// ResendVerificationEmail godoc
+//
+//	@Summary		Resend verification email
+//	@Description	Send a new verification link to the signed-in user. Earlier links stop working.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		202	{object}	response.ResponseSuccess
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		409	{object}	response.ResponseError
+//	@Failure		429	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerificationEmail(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	if err := h.authUsecase.ResendVerificationEmail(c.Request().Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrEmailAlreadyVerified):
+			return response.Error(c, http.StatusConflict, err.Error())
+		case errors.Is(err, errmap.ErrVerificationEmailRecent):
+			return response.Error(c, http.StatusTooManyRequests, err.Error())
+		default:
+			c.Logger().Error("ResendVerificationEmail error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusAccepted, "Verification email sent", nil)
+}
+
+// ForgotPassword godoc
+//
+//	@Summary		Forgot password
+//	@Description	Email a single-use password reset link if an account uses the email. The response is the same whether or not it does.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.ForgotPasswordRequest	true	"Forgot password payload"
+//	@Success		202		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c echo.Context) error {
+	var req entity.ForgotPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.ForgotPassword(c.Request().Context(), &req); err != nil {
+		c.Logger().Error("ForgotPassword error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusAccepted, "If an account uses this email, a password reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+//
+//	@Summary		Reset password
+//	@Description	Set a new password with the token from a password reset email. Each token can be used once, and every session of the user is signed out.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.ResetPasswordRequest	true	"Reset password payload"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c echo.Context) error {
+	var req entity.ResetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.ResetPassword(c.Request().Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidUserToken):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			c.Logger().Error("ResetPassword error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// ChangePassword godoc
+//
+//	@Summary		Change password
+//	@Description	Change the signed-in user's password. Every session of the user is signed out, and a new token pair is returned for the current device.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.ChangePasswordRequest	true	"Change password payload"
+//	@Success		200		{object}	entity.AuthResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	tokens, err := h.authUsecase.ChangePassword(c.Request().Context(), userID, &req, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrIncorrectPassword):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			c.Logger().Error("ChangePassword error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Password changed successfully", tokens)
+}
+
+// Logout godoc
+//
+//	@Summary		Log out
+//	@Description	Sign out the session of the refresh token. Access tokens already issued stay valid until they expire.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.LogoutRequest	true	"Logout payload"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/logout [post]
+func (h *AuthHandler) Logout(c echo.Context) error {
+	var req entity.LogoutRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.Logout(c.Request().Context(), &req); err != nil {
+		c.Logger().Error("Logout error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// LogoutAll godoc
+//
+//	@Summary		Log out of all devices
+//	@Description	Sign out every session of the user, including the current one
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	entity.LogoutAllResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	result, err := h.authUsecase.LogoutAll(c.Request().Context(), userID)
+	if err != nil {
+		c.Logger().Error("LogoutAll error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "Logged out of all devices", result)
+}
+
+// ListSessions godoc
+//
+//	@Summary		List active sessions
+//	@Description	List the devices the user is signed in on, most recently used first
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.SessionResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	sessions, err := h.authUsecase.ListSessions(c.Request().Context(), userID, middleware.GetSessionID(c))
+	if err != nil {
+		c.Logger().Error("ListSessions error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", sessions)
+}
+
+// RevokeSession godoc
+//
+//	@Summary		Revoke a session
+//	@Description	Sign out one of the user's sessions
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			sessionId	path		string	true	"Session ID"
+//	@Success		200			{object}	response.ResponseSuccess
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/auth/sessions/{sessionId} [delete]
+func (h *AuthHandler) RevokeSession(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	sessionID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := h.authUsecase.RevokeSession(c.Request().Context(), userID, sessionID); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrSessionNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			c.Logger().Error("RevokeSession error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Session revoked", nil)
+}
+
+// ListOAuthProviders godoc
+//
+//	@Summary		List social login providers
+//	@Description	Names of the providers users can sign in with, e.g. google, line or facebook
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	entity.OAuthProvidersResponse
+//	@Router			/api/auth/oauth/providers [get]
+func (h *AuthHandler) ListOAuthProviders(c echo.Context) error {
+	return response.Success(c, http.StatusOK, "ok", h.authUsecase.ListOAuthProviders(c.Request().Context()))
+}
+
+// StartOAuthLogin godoc
+//
+//	@Summary		Start social login
+//	@Description	Returns the provider page to send the user to. The provider redirects back to the storefront with a code and the state, which it posts to /api/auth/oauth/{provider}/callback within 10 minutes.
+//	@Tags			Auth
+//	@Produce		json
+//	@Param			provider	path		string	true	"Provider name"
+//	@Success		200			{object}	entity.OAuthAuthorizeResponse
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/auth/oauth/{provider}/authorize [post]
+func (h *AuthHandler) StartOAuthLogin(c echo.Context) error {
+	authorize, err := h.authUsecase.StartOAuthLogin(c.Request().Context(), c.Param("provider"))
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrOAuthProviderNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			c.Logger().Error("StartOAuthLogin error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "ok", authorize)
+}
+
+// CompleteOAuthLogin godoc
+//
+//	@Summary		Finish social login
+//	@Description	Sign in with the code the provider redirected back with. The provider account is linked to the user with its email, or a new user is created, if the provider has verified the email. Accounts with two-factor authentication get twoFactorRequired and a twoFactorToken instead, to finish the login at /api/auth/2fa/login.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			provider	path		string						true	"Provider name"
+//	@Param			body		body		entity.OAuthCallbackRequest	true	"Callback payload"
+//	@Success		200			{object}	entity.AuthResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/auth/oauth/{provider}/callback [post]
+func (h *AuthHandler) CompleteOAuthLogin(c echo.Context) error {
+	var req entity.OAuthCallbackRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	loginResponse, err := h.authUsecase.CompleteOAuthLogin(c.Request().Context(), c.Param("provider"), &req, clientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrOAuthProviderNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, errmap.ErrInvalidOAuthState), errors.Is(err, errmap.ErrOAuthEmailRequired):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrOAuthLoginFailed):
+			return response.Error(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, errmap.ErrOAuthEmailNotVerified):
+			return response.Error(c, http.StatusForbidden, err.Error())
+		default:
+			c.Logger().Error("CompleteOAuthLogin error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Login successful", loginResponse)
+}
+
+// ListIdentities godoc
+//
+//	@Summary		List linked accounts
+//	@Description	The social login accounts linked to the signed-in user
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.UserIdentity
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/auth/identities [get]
+func (h *AuthHandler) ListIdentities(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	identities, err := h.authUsecase.ListIdentities(c.Request().Context(), userID)
+	if err != nil {
+		c.Logger().Error("ListIdentities error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", identities)
+}
+
+// UnlinkIdentity godoc
+//
+//	@Summary		Unlink an account
+//	@Description	Remove a linked social login account. The last one cannot be removed from a user without a password.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			identityId	path		string	true	"Identity ID"
+//	@Success		200			{object}	response.ResponseSuccess
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/auth/identities/{identityId} [delete]
+func (h *AuthHandler) UnlinkIdentity(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	identityID, err := uuid.Parse(c.Param("identityId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := h.authUsecase.UnlinkIdentity(c.Request().Context(), userID, identityID); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrIdentityNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, errmap.ErrLastSignInMethod):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			c.Logger().Error("UnlinkIdentity error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Account unlinked", nil)
+}
+
+// VerifyTwoFactorLogin godoc
+//
+//	@Summary		Finish login with a two-factor code
+//	@Description	Second step of logging in to an account with two-factor authentication. Code is the 6-digit code from the authenticator app or a recovery code. The two-factor token from login expires after 5 minutes.
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.TwoFactorLoginRequest	true	"Two-factor login payload"
+//	@Success		200		{object}	entity.AuthResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		429		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/2fa/login [post]
+func (h *AuthHandler) VerifyTwoFactorLogin(c echo.Context) error {
+	var req entity.TwoFactorLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	loginResponse, err := h.authUsecase.VerifyTwoFactorLogin(c.Request().Context(), &req, clientInfo(c))
+	if err != nil {
+		var blocked *errmap.LoginBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+			return response.Error(c, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, errmap.ErrInvalidTwoFactorToken), errors.Is(err, errmap.ErrInvalidTwoFactorCode):
+			return response.Error(c, http.StatusUnauthorized, err.Error())
+		default:
+			c.Logger().Error("VerifyTwoFactorLogin error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Login successful", loginResponse)
+}
+
+// GetTwoFactorStatus godoc
+//
+//	@Summary		Get two-factor status
+//	@Description	Whether two-factor authentication is enabled or required for the signed-in user, and how many recovery codes are left
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	entity.TwoFactorStatusResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/auth/2fa [get]
+func (h *AuthHandler) GetTwoFactorStatus(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	status, err := h.authUsecase.GetTwoFactorStatus(c.Request().Context(), userID)
+	if err != nil {
+		c.Logger().Error("GetTwoFactorStatus error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", status)
+}
+
+// SetupTwoFactor godoc
+//
+//	@Summary		Set up two-factor authentication
+//	@Description	Create a TOTP secret for the signed-in user. Show provisioningUri as a QR code for the authenticator app, then confirm a code at /api/auth/2fa/enable. Setting up again before enabling replaces the secret.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	entity.TwoFactorSetupResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		409	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	setup, err := h.authUsecase.SetupTwoFactor(c.Request().Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrTwoFactorAlreadyEnabled):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			c.Logger().Error("SetupTwoFactor error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "ok", setup)
+}
+
+// EnableTwoFactor godoc
+//
+//	@Summary		Enable two-factor authentication
+//	@Description	Confirm a 6-digit code from the authenticator app to turn on two-factor authentication. Returns the recovery codes, which are shown only once.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.TwoFactorCodeRequest	true	"Code payload"
+//	@Success		200		{object}	entity.TwoFactorRecoveryCodesResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.TwoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	codes, err := h.authUsecase.EnableTwoFactor(c.Request().Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidTwoFactorCode):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrTwoFactorAlreadyEnabled), errors.Is(err, errmap.ErrTwoFactorNotSetUp):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			c.Logger().Error("EnableTwoFactor error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Two-factor authentication enabled", codes)
+}
+
+// DisableTwoFactor godoc
+//
+//	@Summary		Disable two-factor authentication
+//	@Description	Turn off two-factor authentication with the password and a code from the app or a recovery code. Not allowed when an admin requires it.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.DisableTwoFactorRequest	true	"Disable payload"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.DisableTwoFactorRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.DisableTwoFactor(c.Request().Context(), userID, &req); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrIncorrectPassword), errors.Is(err, errmap.ErrInvalidTwoFactorCode):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrTwoFactorEnforced):
+			return response.Error(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, errmap.ErrTwoFactorNotEnabled):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			c.Logger().Error("DisableTwoFactor error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+// RegenerateRecoveryCodes godoc
+//
+//	@Summary		Regenerate recovery codes
+//	@Description	Replace all recovery codes after confirming a code from the app or a recovery code. The new codes are shown only once.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.TwoFactorCodeRequest	true	"Code payload"
+//	@Success		200		{object}	entity.TwoFactorRecoveryCodesResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.TwoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	codes, err := h.authUsecase.RegenerateRecoveryCodes(c.Request().Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidTwoFactorCode):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrTwoFactorNotEnabled):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			c.Logger().Error("RegenerateRecoveryCodes error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Recovery codes regenerated", codes)
+}
+
+// ReverifyTwoFactor godoc
+//
+//	@Summary		Re-verify two-factor code
+//	@Description	Confirm a 6-digit code from the authenticator app to get a new access token that allows sensitive actions, such as approving refunds, for the next 10 minutes. Recovery codes are not accepted.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.TwoFactorCodeRequest	true	"Code payload"
+//	@Success		200		{object}	entity.AuthResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		429		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/auth/2fa/reverify [post]
+func (h *AuthHandler) ReverifyTwoFactor(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.TwoFactorCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	tokens, err := h.authUsecase.ReverifyTwoFactor(c.Request().Context(), userID, middleware.GetSessionID(c), &req)
+	if err != nil {
+		var blocked *errmap.LoginBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			c.Response().Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(blocked.RetryAfter.Seconds()))))
+			return response.Error(c, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, errmap.ErrInvalidTwoFactorCode):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrTwoFactorNotEnabled):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			c.Logger().Error("ReverifyTwoFactor error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Two-factor code verified", tokens)
+}
+
+// SetTwoFactorRequired godoc
+//
+//	@Summary		Require two-factor authentication (Admin)
+//	@Description	Make two-factor authentication mandatory for a user, or optional again. A user who has not set it up is refused sensitive actions until they do.
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userId	path		string							true	"User ID"
+//	@Param			body	body		entity.SetTwoFactorRequiredRequest	true	"Requirement payload"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/users/{userId}/two-factor [put]
+func (h *AuthHandler) SetTwoFactorRequired(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidUserID.Error())
+	}
+
+	var req entity.SetTwoFactorRequiredRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.SetTwoFactorRequired(c.Request().Context(), userID, *req.Required); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrUserNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			c.Logger().Error("SetTwoFactorRequired error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Two-factor requirement updated", nil)
+}
+
+// ResetTwoFactor godoc
+//
+//	@Summary		Reset two-factor authentication (Admin)
+//	@Description	Turn off two-factor authentication for a user who lost their phone and recovery codes, and sign them out of every session
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			userId	path		string	true	"User ID"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/users/{userId}/two-factor [delete]
+func (h *AuthHandler) ResetTwoFactor(c echo.Context) error {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidUserID.Error())
+	}
+
+	if err := h.authUsecase.ResetTwoFactor(c.Request().Context(), userID); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrUserNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			c.Logger().Error("ResetTwoFactor error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "Two-factor authentication reset", nil)
+}
+
+// ListFailedLogins godoc
+//
+//	@Summary		List failed logins (Admin)
+//	@Description	Audit trail of failed login attempts, newest first, including attempts rejected while throttled or locked
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page		query		int		false	"Page"
+//	@Param			perPage		query		int		false	"Items per page"
+//	@Param			email		query		string	false	"Filter by email"
+//	@Param			ipAddress	query		string	false	"Filter by IP address"
+//	@Param			reason		query		string	false	"Filter by reason"	Enums(UNKNOWN_EMAIL, WRONG_PASSWORD, WRONG_TWO_FACTOR_CODE, THROTTLED, LOCKED)
+//	@Success		200			{object}	entity.FailedLoginListResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/admin/logins/failures [get]
+func (h *AuthHandler) ListFailedLogins(c echo.Context) error {
+	var req entity.FailedLoginListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	attempts, err := h.authUsecase.ListFailedLogins(c.Request().Context(), req)
+	if err != nil {
+		c.Logger().Error("ListFailedLogins error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", attempts)
+}
+
+// UnlockLogin godoc
+//
+//	@Summary		Unlock login (Admin)
+//	@Description	Clear the failed login count of an account, an IP address or both, lifting any delay or lockout
+//	@Tags			Auth
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.UnlockLoginRequest	true	"Unlock payload"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/logins/unlock [post]
+func (h *AuthHandler) UnlockLogin(c echo.Context) error {
+	var req entity.UnlockLoginRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authUsecase.UnlockLogin(c.Request().Context(), &req); err != nil {
+		c.Logger().Error("UnlockLogin error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "Login unlocked", nil)
+}
+
+// JWKS godoc
+//
+//	@Summary		JSON Web Key Set
+//	@Description	Public keys access tokens are signed with, for services that verify tokens themselves. Tokens name their key in the "kid" header. The body is a plain JWK set (RFC 7517), not wrapped in the usual response envelope.
+//	@Tags			Auth
+//	@Produce		json
+//	@Success		200	{object}	jwt.JWKS
+//	@Router			/.well-known/jwks.json [get]
+func JWKS(c echo.Context) error {
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, jwt.Default().JWKS())
+}
+
+// clientInfo is the device info stored on the sessions a request starts or refreshes.
+func clientInfo(c echo.Context) entity.ClientInfo {
+	return entity.ClientInfo{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+	}
+}
+
 func RegisterAuthHandler(group *echo.Group, db *gorm.DB) {
 	authRepository := authRepo.NewAuthRepository(db)
-	authUsecaseInstance := authUsecase.NewAuthUsecase(authRepository)
+	authUsecaseInstance := authUsecase.NewAuthUsecase(authRepository, notificationRepo.NewNotificationRepository(db), loginguard.NewLimiterFromEnv(db), oidc.ProvidersFromEnv(), config.TWO_FACTOR_ISSUER)
 	authHandler := NewAuthHandler(authUsecaseInstance)
 	authHandler.RegisterRoutes(group)
 }
@@ -2,6 +2,8 @@ package delivery
 
 import (
 	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/middleware"
 )
 
 func (h *AuthHandler) RegisterRoutes(r *echo.Group) {
@@ -11,5 +13,55 @@ func (h *AuthHandler) RegisterRoutes(r *echo.Group) {
 		auth.POST("/register-shop", h.RegisterShop)
 		auth.POST("/login", h.Login)
 		auth.POST("/refresh", h.RefreshToken)
+		auth.POST("/verify-email", h.VerifyEmail)
+		auth.POST("/verify-email/resend", h.ResendVerificationEmail, middleware.JWTAuth())
+		auth.POST("/forgot-password", h.ForgotPassword)
+		auth.POST("/reset-password", h.ResetPassword)
+		auth.POST("/change-password", h.ChangePassword, middleware.JWTAuth())
+		auth.POST("/logout", h.Logout)
+		auth.POST("/logout-all", h.LogoutAll, middleware.JWTAuth())
+		auth.GET("/sessions", h.ListSessions, middleware.JWTAuth())
+		auth.DELETE("/sessions/:sessionId", h.RevokeSession, middleware.JWTAuth())
+	}
+
+	oauth := r.Group("/auth/oauth")
+	{
+		oauth.GET("/providers", h.ListOAuthProviders)
+		oauth.POST("/:provider/authorize", h.StartOAuthLogin)
+		oauth.POST("/:provider/callback", h.CompleteOAuthLogin)
+	}
+
+	identities := r.Group("/auth/identities", middleware.JWTAuth())
+	{
+		identities.GET("", h.ListIdentities)
+		identities.DELETE("/:identityId", h.UnlinkIdentity)
+	}
+
+	twoFactor := r.Group("/auth/2fa")
+	{
+		twoFactor.POST("/login", h.VerifyTwoFactorLogin)
+		twoFactor.GET("", h.GetTwoFactorStatus, middleware.JWTAuth())
+		twoFactor.POST("/setup", h.SetupTwoFactor, middleware.JWTAuth())
+		twoFactor.POST("/enable", h.EnableTwoFactor, middleware.JWTAuth())
+		twoFactor.POST("/disable", h.DisableTwoFactor, middleware.JWTAuth())
+		twoFactor.POST("/recovery-codes", h.RegenerateRecoveryCodes, middleware.JWTAuth())
+		twoFactor.POST("/reverify", h.ReverifyTwoFactor, middleware.JWTAuth())
 	}
+
+	adminTwoFactor := r.Group("/admin/users/:userId/two-factor", middleware.JWTAuth(), middleware.AdminOnly(), middleware.RequireTwoFactor())
+	{
+		adminTwoFactor.PUT("", h.SetTwoFactorRequired)
+		adminTwoFactor.DELETE("", h.ResetTwoFactor)
+	}
+
+	adminLogins := r.Group("/admin/logins", middleware.JWTAuth(), middleware.AdminOnly())
+	{
+		adminLogins.GET("/failures", h.ListFailedLogins)
+		adminLogins.POST("/unlock", h.UnlockLogin)
+	}
+}
+
+// RegisterWellKnownRoutes serves the JWKS at the root, where JWT libraries look for it.
+func RegisterWellKnownRoutes(e *echo.Echo) {
+	e.GET("/.well-known/jwks.json", JWKS)
 }
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -95,6 +100,518 @@ func (r *AuthRepository) RegisterShop(ctx context.Context, user *entity.User, sh
 			return err
 		}
 
+		owner := &entity.ShopMember{
+			ShopID: shop.ID,
+			UserID: user.ID,
+			Role:   entity.ShopRoleOwner,
+		}
+		if err := tx.Create(owner).Error; err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
+
+func (r *AuthRepository) CreateUserToken(ctx context.Context, token *entity.UserToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Delete(&entity.UserToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *AuthRepository) GetLatestUserToken(ctx context.Context, userID uuid.UUID, purpose string) (*entity.UserToken, error) {
+	var token entity.UserToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("created_at DESC").
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *AuthRepository) VerifyEmail(ctx context.Context, tokenHash string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := timeth.Now()
+		token, err := useUserToken(tx, entity.UserTokenEmailVerification, tokenHash, now)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.User{}).
+			Where("id = ? AND email_verified_at IS NULL", token.UserID).
+			Updates(map[string]interface{}{"email_verified_at": now, "updated_at": now}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id = ? AND deleted_at IS NULL", token.UserID).First(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *AuthRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := timeth.Now()
+		token, err := useUserToken(tx, entity.UserTokenPasswordReset, tokenHash, now)
+		if err != nil {
+			return err
+		}
+
+		if err := updatePassword(tx, token.UserID, passwordHash, now); err != nil {
+			return err
+		}
+
+		return tx.Where("id = ? AND deleted_at IS NULL", token.UserID).First(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *AuthRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updatePassword(tx, userID, passwordHash, timeth.Now())
+	})
+}
+
+// useUserToken marks the unused, unexpired token with the hash as used. The update is
+// conditional, so two requests racing with the same token cannot both use it.
+func useUserToken(tx *gorm.DB, purpose, tokenHash string, now time.Time) (*entity.UserToken, error) {
+	var tokens []*entity.UserToken
+	err := tx.Raw(`
+		UPDATE user_tokens SET used_at = ?
+		WHERE token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?
+		RETURNING *`, now, tokenHash, purpose, now).
+		Scan(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errmap.ErrInvalidUserToken
+	}
+	return tokens[0], nil
+}
+
+// updatePassword sets the password and password_changed_at, deletes any reset links
+// still waiting to be used and signs the user out of every session.
+func updatePassword(tx *gorm.DB, userID uuid.UUID, passwordHash string, now time.Time) error {
+	res := tx.Model(&entity.User{}).
+		Where("id = ? AND deleted_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"password":            passwordHash,
+			"password_changed_at": now,
+			"updated_at":          now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrNotFound
+	}
+
+	if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, entity.UserTokenPasswordReset).
+		Delete(&entity.UserToken{}).Error; err != nil {
+		return err
+	}
+
+	_, err := revokeSessions(tx, userID, entity.SessionRevokedPasswordChange, now)
+	return err
+}
+
+func (r *AuthRepository) CreateSession(ctx context.Context, session *entity.UserSession, token *entity.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+func (r *AuthRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.db.WithContext(ctx).
+		Preload("Session").
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errmap.ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *AuthRepository) RotateRefreshToken(ctx context.Context, usedTokenID uuid.UUID, next *entity.RefreshToken, client entity.ClientInfo) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := timeth.Now()
+
+		// Two refreshes racing with the same token: only the first one gets to use it.
+		res := tx.Model(&entity.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", usedTokenID).
+			Update("used_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errmap.ErrRefreshTokenReused
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.UserSession{}).
+			Where("id = ?", next.SessionID).
+			Updates(map[string]interface{}{
+				"user_agent":   client.UserAgent,
+				"ip_address":   client.IPAddress,
+				"last_used_at": now,
+				"expires_at":   next.ExpiresAt,
+			}).Error
+	})
+}
+
+func (r *AuthRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]*entity.UserSession, error) {
+	var sessions []*entity.UserSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, timeth.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *AuthRepository) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID, reason string) error {
+	res := r.db.WithContext(ctx).Model(&entity.UserSession{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Updates(map[string]interface{}{"revoked_at": timeth.Now(), "revoked_reason": reason})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepository) RevokeSessions(ctx context.Context, userID uuid.UUID, reason string) (int64, error) {
+	return revokeSessions(r.db.WithContext(ctx), userID, reason, timeth.Now())
+}
+
+func revokeSessions(tx *gorm.DB, userID uuid.UUID, reason string, now time.Time) (int64, error) {
+	res := tx.Model(&entity.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": now, "revoked_reason": reason})
+	return res.RowsAffected, res.Error
+}
+
+func (r *AuthRepository) CreateFailedLogin(ctx context.Context, attempt *entity.FailedLogin) error {
+	return r.db.WithContext(ctx).Create(attempt).Error
+}
+
+func (r *AuthRepository) ListFailedLogins(ctx context.Context, req entity.FailedLoginListRequest) ([]*entity.FailedLogin, int64, error) {
+	var attempts []*entity.FailedLogin
+	var total int64
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	query := r.db.WithContext(ctx).Model(&entity.FailedLogin{})
+	if req.Email != "" {
+		query = query.Where("email = ?", strings.ToLower(strings.TrimSpace(req.Email)))
+	}
+	if req.IPAddress != "" {
+		query = query.Where("ip_address = ?", req.IPAddress)
+	}
+	if req.Reason != "" {
+		query = query.Where("reason = ?", req.Reason)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(int(perPage)).
+		Offset(int(offset)).
+		Find(&attempts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return attempts, total, nil
+}
+
+func (r *AuthRepository) GetUserToken(ctx context.Context, purpose, tokenHash string) (*entity.UserToken, error) {
+	var token entity.UserToken
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, timeth.Now()).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrInvalidUserToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *AuthRepository) UseUserToken(ctx context.Context, purpose, tokenHash string) error {
+	_, err := useUserToken(r.db.WithContext(ctx), purpose, tokenHash, timeth.Now())
+	return err
+}
+
+func (r *AuthRepository) SetTwoFactorSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	res := r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND two_factor_enabled_at IS NULL", userID).
+		Updates(map[string]interface{}{"two_factor_secret": secret, "updated_at": timeth.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrTwoFactorAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *AuthRepository) EnableTwoFactor(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := timeth.Now()
+		res := tx.Model(&entity.User{}).
+			Where("id = ? AND two_factor_enabled_at IS NULL AND two_factor_secret <> ''", userID).
+			Updates(map[string]interface{}{"two_factor_enabled_at": now, "two_factor_last_step": step, "updated_at": now})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errmap.ErrTwoFactorAlreadyEnabled
+		}
+
+		return replaceRecoveryCodes(tx, userID, recoveryCodeHashes, now)
+	})
+}
+
+func (r *AuthRepository) DisableTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"two_factor_secret":     "",
+				"two_factor_enabled_at": nil,
+				"two_factor_last_step":  0,
+				"updated_at":            timeth.Now(),
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&entity.TwoFactorRecoveryCode{}).Error
+	})
+}
+
+func (r *AuthRepository) UseTwoFactorStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	res := r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (r *AuthRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	res := r.db.WithContext(ctx).Model(&entity.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", timeth.Now())
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+func (r *AuthRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codeHashes, timeth.Now())
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uuid.UUID, codeHashes []string, now time.Time) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&entity.TwoFactorRecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]*entity.TwoFactorRecoveryCode, 0, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		codes = append(codes, &entity.TwoFactorRecoveryCode{UserID: userID, CodeHash: codeHash, CreatedAt: now})
+	}
+	return tx.Create(&codes).Error
+}
+
+func (r *AuthRepository) CountUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.TwoFactorRecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *AuthRepository) SetTwoFactorRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	res := r.db.WithContext(ctx).Model(&entity.User{}).
+		Where("id = ? AND deleted_at IS NULL", userID).
+		Updates(map[string]interface{}{"two_factor_required": required, "updated_at": timeth.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *AuthRepository) CreateOAuthState(ctx context.Context, state *entity.OAuthState) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM oauth_states WHERE expires_at <= ?", timeth.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Table("oauth_states").Create(state).Error
+	})
+}
+
+// UseOAuthState deletes the state in the same statement that reads it, so two callbacks
+// racing with the same state cannot both use it.
+func (r *AuthRepository) UseOAuthState(ctx context.Context, provider, stateHash string) (*entity.OAuthState, error) {
+	var states []*entity.OAuthState
+	err := r.db.WithContext(ctx).Raw(`
+		DELETE FROM oauth_states
+		WHERE state_hash = ? AND provider = ? AND expires_at > ?
+		RETURNING *`, stateHash, provider, timeth.Now()).
+		Scan(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, errmap.ErrInvalidOAuthState
+	}
+	return states[0], nil
+}
+
+func (r *AuthRepository) GetUserIdentity(ctx context.Context, provider, subject string) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	if err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *AuthRepository) TouchUserIdentity(ctx context.Context, identityID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&entity.UserIdentity{}).
+		Where("id = ?", identityID).
+		Update("last_used_at", timeth.Now()).Error
+}
+
+func (r *AuthRepository) LinkUserIdentity(ctx context.Context, identity *entity.UserIdentity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(identity).Error; err != nil {
+			return err
+		}
+
+		// Whoever registered an unverified account may not own the email, so the
+		// password, two-factor setup and sessions they could have left behind go.
+		now := timeth.Now()
+		res := tx.Model(&entity.User{}).
+			Where("id = ? AND email_verified_at IS NULL", identity.UserID).
+			Updates(map[string]interface{}{
+				"email_verified_at":     now,
+				"password":              "",
+				"password_changed_at":   now,
+				"two_factor_secret":     "",
+				"two_factor_enabled_at": nil,
+				"two_factor_last_step":  0,
+				"updated_at":            now,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+
+		if err := tx.Where("user_id = ?", identity.UserID).Delete(&entity.TwoFactorRecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", identity.UserID, entity.UserTokenPasswordReset).
+			Delete(&entity.UserToken{}).Error; err != nil {
+			return err
+		}
+		_, err := revokeSessions(tx, identity.UserID, entity.SessionRevokedEmailClaimed, now)
+		return err
+	})
+}
+
+func (r *AuthRepository) RegisterOAuthUser(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		userRole := &entity.UserRole{
+			UserID: user.ID,
+			RoleID: entity.RoleUser,
+		}
+		if err := tx.Create(userRole).Error; err != nil {
+			return err
+		}
+
+		identity.UserID = user.ID
+		return tx.Create(identity).Error
+	})
+}
+
+func (r *AuthRepository) ListUserIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error) {
+	var identities []*entity.UserIdentity
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&identities).Error
+	return identities, err
+}
+
+func (r *AuthRepository) DeleteUserIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", identityID, userID).
+		Delete(&entity.UserIdentity{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrIdentityNotFound
+	}
+	return nil
+}
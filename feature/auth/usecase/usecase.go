@@ -2,27 +2,78 @@ package usecase
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"ecommerce-go-api/config"
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
 	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/fieldcrypt"
 	"ecommerce-go-api/internal/hash"
 	"ecommerce-go-api/internal/jwt"
+	"ecommerce-go-api/internal/loginguard"
+	"ecommerce-go-api/internal/notify"
+	"ecommerce-go-api/internal/securetoken"
+	"ecommerce-go-api/internal/timeth"
+	"ecommerce-go-api/internal/totp"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	verificationTokenTTL  = 24 * time.Hour
+	passwordResetTokenTTL = time.Hour
+	// tokenEmailCooldown is how long a user waits before another verification or reset
+	// email is sent.
+	tokenEmailCooldown = time.Minute
+	// twoFactorLoginTTL is how long the user has to enter their two-factor code after
+	// their password.
+	twoFactorLoginTTL = 5 * time.Minute
+	totpCodeLength    = 6
+	recoveryCodeCount = 10
+	// oauthStateTTL is how long the user has to sign in at the provider.
+	oauthStateTTL = 10 * time.Minute
+)
+
 type authUsecase struct {
-	authRepo domain.AuthRepository
+	authRepo         domain.AuthRepository
+	notificationRepo domain.NotificationRepository
+	loginLimiter     domain.LoginLimiter
+	accountPolicy    loginguard.Policy
+	ipPolicy         loginguard.Policy
+	appURL           string
+	twoFactorIssuer  string
+	oauthProviders   map[string]domain.OAuthProvider
 }
 
-func NewAuthUsecase(authRepo domain.AuthRepository) domain.AuthUsecase {
+func NewAuthUsecase(authRepo domain.AuthRepository, notificationRepo domain.NotificationRepository, loginLimiter domain.LoginLimiter, oauthProviders map[string]domain.OAuthProvider, twoFactorIssuer string) domain.AuthUsecase {
 	return &authUsecase{
-		authRepo: authRepo,
+		authRepo:         authRepo,
+		notificationRepo: notificationRepo,
+		loginLimiter:     loginLimiter,
+		oauthProviders:   oauthProviders,
+		accountPolicy:    loginguard.AccountPolicyFromEnv(),
+		ipPolicy:         loginguard.IPPolicyFromEnv(),
+		appURL:           config.AppURL(),
+		twoFactorIssuer:  twoFactorIssuer,
 	}
 }
 
+// loginLimit is one key a login attempt is counted under.
+type loginLimit struct {
+	key    string
+	policy loginguard.Policy
+}
+
 func (u *authUsecase) Register(ctx context.Context, req *entity.RegisterRequest) (*entity.RegisterResponse, error) {
 	existingUser, err := u.authRepo.GetUserByEmail(ctx, req.Email)
 	if err == nil && existingUser != nil {
@@ -53,6 +104,11 @@ func (u *authUsecase) Register(ctx context.Context, req *entity.RegisterRequest)
 		return nil, err
 	}
 
+	// The account exists either way; the user can ask for another email.
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("[EMAIL] Failed to queue verification email for user %s: %v", user.ID, err)
+	}
+
 	return &entity.RegisterResponse{
 		ID:          user.ID,
 		FirstName:   user.FirstName,
@@ -103,6 +159,10 @@ func (u *authUsecase) RegisterShop(ctx context.Context, req *entity.RegisterShop
 		return nil, err
 	}
 
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		log.Printf("[EMAIL] Failed to queue verification email for user %s: %v", user.ID, err)
+	}
+
 	user.Password = ""
 	shop.User = user
 
@@ -127,90 +187,813 @@ func (u *authUsecase) RegisterShop(ctx context.Context, req *entity.RegisterShop
 	}, nil
 }
 
-func (u *authUsecase) Login(ctx context.Context, req *entity.LoginRequest) (*entity.AuthResponse, error) {
+// Login is throttled per account and per IP address: failures past a free allowance
+// have to wait a growing delay before the next attempt, and enough of them lock the key.
+// The attempt is counted as failed before the password is checked and taken back if it
+// succeeds, so parallel guesses cannot all pass the same count. Attempts that have to
+// wait return *errmap.LoginBlockedError without checking the password. Every failed
+// attempt is added to the audit trail. Accounts with two-factor authentication get a
+// two-factor token instead of a session; see VerifyTwoFactorLogin.
+func (u *authUsecase) Login(ctx context.Context, req *entity.LoginRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	client = clientInfo(client)
+	limits := u.loginLimits(req.Email, client.IPAddress)
+
+	reservations, err := u.reserveLoginAttempt(ctx, limits)
+	if err != nil {
+		var blocked *errmap.LoginBlockedError
+		if errors.As(err, &blocked) {
+			reason := entity.LoginFailureThrottled
+			if blocked.Locked {
+				reason = entity.LoginFailureLocked
+			}
+			u.auditFailedLogin(ctx, req.Email, nil, client, reason)
+		}
+		return nil, err
+	}
 
 	user, err := u.authRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		if errors.Is(err, errmap.ErrNotFound) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			u.recordFailedLogin(ctx, limits, reservations, req.Email, nil, client, entity.LoginFailureUnknownEmail)
 			return nil, errmap.ErrInvalidCredentials
 		}
+		u.releaseLoginAttempt(ctx, limits, reservations)
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
 	if !hash.CheckPassword(req.Password, user.Password) {
+		u.recordFailedLogin(ctx, limits, reservations, req.Email, &user.ID, client, entity.LoginFailureWrongPassword)
 		return nil, errmap.ErrInvalidCredentials
 	}
 
-	roles, err := u.authRepo.GetRolesByUserID(ctx, user.ID)
+	// Only the account is reset: an IP address trying many accounts keeps its count even
+	// if some of its guesses are right.
+	u.releaseLoginAttempt(ctx, limits, reservations)
+	if err := u.loginLimiter.Reset(ctx, limits[0].key); err != nil {
+		log.Printf("[AUTH] Warning: Failed to reset failed logins of %s: %v", limits[0].key, err)
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		return u.startTwoFactorLogin(ctx, user)
+	}
+
+	return u.startSession(ctx, user, client, nil)
+}
+
+// loginLimits returns the keys of the attempt, the account first.
+func (u *authUsecase) loginLimits(email, ip string) []loginLimit {
+	limits := []loginLimit{{key: loginguard.AccountKey(email), policy: u.accountPolicy}}
+	if ip != "" {
+		limits = append(limits, loginLimit{key: loginguard.IPKey(ip), policy: u.ipPolicy})
+	}
+	return limits
+}
+
+// reserveLoginAttempt counts the attempt as failed under every key before its
+// credentials are checked. When any key has to wait, the attempt is taken back and
+// *errmap.LoginBlockedError is returned with the longest wait of the keys.
+func (u *authUsecase) reserveLoginAttempt(ctx context.Context, limits []loginLimit) ([]entity.LoginReservation, error) {
+	now := timeth.Now()
+
+	reservations := make([]entity.LoginReservation, 0, len(limits))
+	var blocked *errmap.LoginBlockedError
+	for _, limit := range limits {
+		reservation, err := u.loginLimiter.Reserve(ctx, limit.key, limit.policy.LockDuration)
+		if err != nil {
+			u.releaseLoginAttempt(ctx, limits, reservations)
+			return nil, fmt.Errorf("failed to count login attempt: %w", err)
+		}
+		reservations = append(reservations, reservation)
+
+		wait, locked := limit.policy.Wait(reservation.Previous, now)
+		if wait > 0 && (blocked == nil || wait > blocked.RetryAfter) {
+			blocked = &errmap.LoginBlockedError{RetryAfter: wait, Locked: locked}
+		}
+	}
+
+	if blocked != nil {
+		u.releaseLoginAttempt(ctx, limits, reservations)
+		return nil, blocked
+	}
+	return reservations, nil
+}
+
+// releaseLoginAttempt takes back an attempt that succeeded or was not made. Errors are
+// only logged; the attempt then stays counted as a failure.
+func (u *authUsecase) releaseLoginAttempt(ctx context.Context, limits []loginLimit, reservations []entity.LoginReservation) {
+	for i, reservation := range reservations {
+		if err := u.loginLimiter.Release(ctx, reservation, limits[i].policy.LockDuration); err != nil {
+			log.Printf("[AUTH] Warning: Failed to release login attempt of %s: %v", reservation.Key, err)
+		}
+	}
+}
+
+// recordFailedLogin keeps the reserved attempt counted as a failure and audits it.
+func (u *authUsecase) recordFailedLogin(ctx context.Context, limits []loginLimit, reservations []entity.LoginReservation, email string, userID *uuid.UUID, client entity.ClientInfo, reason string) {
+	for i, reservation := range reservations {
+		if failures := reservation.Previous.Failures + 1; failures == limits[i].policy.LockAfter {
+			log.Printf("[AUTH] Logins of %s are locked for %s after %d failures", reservation.Key, limits[i].policy.LockDuration, failures)
+		}
+	}
+
+	u.auditFailedLogin(ctx, email, userID, client, reason)
+}
+
+func (u *authUsecase) auditFailedLogin(ctx context.Context, email string, userID *uuid.UUID, client entity.ClientInfo, reason string) {
+	attempt := &entity.FailedLogin{
+		Email:     truncate(loginguard.NormalizeEmail(email), 255),
+		UserID:    userID,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Reason:    reason,
+	}
+	if err := u.authRepo.CreateFailedLogin(ctx, attempt); err != nil {
+		log.Printf("[AUTH] Warning: Failed to audit failed login of %s: %v", attempt.Email, err)
+	}
+}
+
+func (u *authUsecase) ListFailedLogins(ctx context.Context, req entity.FailedLoginListRequest) (*entity.FailedLoginListResponse, error) {
+	attempts, total, err := u.authRepo.ListFailedLogins(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 
-	role := entity.RoleNameUser
-	for _, r := range roles {
-		if r.ID == entity.RoleShop {
-			role = entity.RoleNameShop
-			break
+	return &entity.FailedLoginListResponse{
+		Items: attempts,
+		Total: total,
+	}, nil
+}
+
+// UnlockLogin clears the failed login count of the account, the IP address or both,
+// lifting any delay or lockout at once.
+func (u *authUsecase) UnlockLogin(ctx context.Context, req *entity.UnlockLoginRequest) error {
+	if req.Email != "" {
+		if err := u.loginLimiter.Reset(ctx, loginguard.AccountKey(req.Email)); err != nil {
+			return err
 		}
-		if r.ID == entity.RoleAdmin {
-			role = entity.RoleNameAdmin
-			break
+
+		// Wrong two-factor codes are counted apart from passwords, by user.
+		user, err := u.authRepo.GetUserByEmail(ctx, req.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if user != nil {
+			if err := u.loginLimiter.Reset(ctx, loginguard.TwoFactorKey(user.ID)); err != nil {
+				return err
+			}
 		}
 	}
+	if req.IPAddress != "" {
+		if err := u.loginLimiter.Reset(ctx, loginguard.IPKey(req.IPAddress)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	accessToken, err := jwt.GenerateAccessToken(user.ID, role)
+// startTwoFactorLogin returns the token that lets the user finish the login with their
+// two-factor code. Starting another login replaces it.
+func (u *authUsecase) startTwoFactorLogin(ctx context.Context, user *entity.User) (*entity.AuthResponse, error) {
+	token, userToken, err := newUserToken(user.ID, entity.UserTokenTwoFactorLogin, twoFactorLoginTTL)
 	if err != nil {
 		return nil, err
 	}
+	if err := u.authRepo.CreateUserToken(ctx, userToken); err != nil {
+		return nil, fmt.Errorf("failed to create two-factor token: %w", err)
+	}
 
-	refreshToken, err := jwt.GenerateRefreshToken(user.ID)
+	return &entity.AuthResponse{
+		TwoFactorRequired: true,
+		TwoFactorToken:    token,
+	}, nil
+}
+
+// VerifyTwoFactorLogin finishes a login with the code from the user's authenticator app
+// or a recovery code. Wrong codes are throttled and audited like wrong passwords; the
+// two-factor token stays usable until it expires or the code is right.
+func (u *authUsecase) VerifyTwoFactorLogin(ctx context.Context, req *entity.TwoFactorLoginRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	client = clientInfo(client)
+	tokenHash := securetoken.Hash(req.TwoFactorToken)
+
+	userToken, err := u.authRepo.GetUserToken(ctx, entity.UserTokenTwoFactorLogin, tokenHash)
 	if err != nil {
+		if errors.Is(err, errmap.ErrInvalidUserToken) {
+			return nil, errmap.ErrInvalidTwoFactorToken
+		}
 		return nil, err
 	}
 
-	user.Password = ""
+	user, err := u.authRepo.GetUserByID(ctx, userToken.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrInvalidTwoFactorToken
+		}
+		return nil, err
+	}
 
-	return &entity.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	limits := []loginLimit{{key: loginguard.TwoFactorKey(user.ID), policy: u.accountPolicy}}
+	if client.IPAddress != "" {
+		limits = append(limits, loginLimit{key: loginguard.IPKey(client.IPAddress), policy: u.ipPolicy})
+	}
+	reservations, err := u.reserveLoginAttempt(ctx, limits)
+	if err != nil {
+		var blocked *errmap.LoginBlockedError
+		if errors.As(err, &blocked) {
+			reason := entity.LoginFailureThrottled
+			if blocked.Locked {
+				reason = entity.LoginFailureLocked
+			}
+			u.auditFailedLogin(ctx, user.Email, &user.ID, client, reason)
+		}
+		return nil, err
+	}
+
+	if err := u.checkTwoFactorCode(ctx, user, req.Code, true); err != nil {
+		if errors.Is(err, errmap.ErrInvalidTwoFactorCode) {
+			u.recordFailedLogin(ctx, limits, reservations, user.Email, &user.ID, client, entity.LoginFailureWrongTwoFactorCode)
+		} else {
+			u.releaseLoginAttempt(ctx, limits, reservations)
+		}
+		return nil, err
+	}
+	u.releaseLoginAttempt(ctx, limits, reservations)
+
+	if err := u.authRepo.UseUserToken(ctx, entity.UserTokenTwoFactorLogin, tokenHash); err != nil {
+		if errors.Is(err, errmap.ErrInvalidUserToken) {
+			return nil, errmap.ErrInvalidTwoFactorToken
+		}
+		return nil, err
+	}
+
+	if err := u.loginLimiter.Reset(ctx, limits[0].key); err != nil {
+		log.Printf("[AUTH] Warning: Failed to reset failed logins of %s: %v", limits[0].key, err)
+	}
+
+	now := timeth.Now()
+	return u.startSession(ctx, user, client, &now)
+}
+
+func (u *authUsecase) GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorStatusResponse, error) {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entity.TwoFactorStatusResponse{
+		Enabled:   user.TwoFactorEnabledAt != nil,
+		EnabledAt: user.TwoFactorEnabledAt,
+		Required:  user.TwoFactorRequired,
+	}
+	if status.Enabled {
+		status.RecoveryCodesRemaining, err = u.authRepo.CountUnusedRecoveryCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// SetupTwoFactor creates a new secret for the user to add to their authenticator app.
+// Two-factor authentication is only enabled once EnableTwoFactor confirms a code of it;
+// setting up again before that replaces the secret.
+func (u *authUsecase) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*entity.TwoFactorSetupResponse, error) {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, errmap.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := fieldcrypt.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.authRepo.SetTwoFactorSecret(ctx, user.ID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &entity.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(u.twoFactorIssuer, user.Email, secret),
 	}, nil
 }
 
-func (u *authUsecase) RefreshToken(ctx context.Context, req *entity.RefreshTokenRequest) (*entity.AuthResponse, error) {
+// EnableTwoFactor turns on two-factor authentication once the user proves their app
+// generates the right codes, and returns their recovery codes. Only app codes are
+// accepted here.
+func (u *authUsecase) EnableTwoFactor(ctx context.Context, userID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.TwoFactorRecoveryCodesResponse, error) {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt != nil {
+		return nil, errmap.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, errmap.ErrTwoFactorNotSetUp
+	}
 
-	claims, err := jwt.ValidateToken(req.RefreshToken)
+	secret, err := fieldcrypt.Decrypt(user.TwoFactorSecret)
 	if err != nil {
-		return nil, errmap.ErrInvalidRefreshToken
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, req.Code, timeth.Now())
+	if !ok {
+		return nil, errmap.ErrInvalidTwoFactorCode
 	}
 
-	user, err := u.authRepo.GetUserByID(ctx, claims.UserID)
+	codes, hashes, err := newRecoveryCodes()
 	if err != nil {
 		return nil, err
 	}
+	if err := u.authRepo.EnableTwoFactor(ctx, user.ID, step, hashes); err != nil {
+		return nil, err
+	}
 
-	roles, err := u.authRepo.GetRolesByUserID(ctx, user.ID)
+	log.Printf("[AUTH] Two-factor authentication enabled for user %s", user.ID)
+
+	return &entity.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor turns off two-factor authentication after checking the password and a
+// code. Users an admin requires it of cannot turn it off.
+func (u *authUsecase) DisableTwoFactor(ctx context.Context, userID uuid.UUID, req *entity.DisableTwoFactorRequest) error {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get roles for refresh: %w", err)
+		return err
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return errmap.ErrTwoFactorNotEnabled
+	}
+	if user.TwoFactorRequired {
+		return errmap.ErrTwoFactorEnforced
+	}
+	if !hash.CheckPassword(req.Password, user.Password) {
+		return errmap.ErrIncorrectPassword
+	}
+	if err := u.checkTwoFactorCode(ctx, user, req.Code, true); err != nil {
+		return err
 	}
 
-	role := entity.RoleNameUser
-	for _, r := range roles {
-		if r.ID == entity.RoleShop {
-			role = entity.RoleNameShop
-			break
+	if err := u.authRepo.DisableTwoFactor(ctx, user.ID); err != nil {
+		return err
+	}
+
+	log.Printf("[AUTH] Two-factor authentication disabled for user %s", user.ID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all the user's recovery codes, used or not.
+func (u *authUsecase) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.TwoFactorRecoveryCodesResponse, error) {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return nil, errmap.ErrTwoFactorNotEnabled
+	}
+	if err := u.checkTwoFactorCode(ctx, user, req.Code, true); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.authRepo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &entity.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// ReverifyTwoFactor returns a new access token for the session once the code is checked,
+// so sensitive actions are allowed for the next few minutes. Recovery codes are not
+// accepted: they are kept for signing in without the phone.
+func (u *authUsecase) ReverifyTwoFactor(ctx context.Context, userID, sessionID uuid.UUID, req *entity.TwoFactorCodeRequest) (*entity.AuthResponse, error) {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabledAt == nil {
+		return nil, errmap.ErrTwoFactorNotEnabled
+	}
+
+	limits := []loginLimit{{key: loginguard.TwoFactorKey(user.ID), policy: u.accountPolicy}}
+	reservations, err := u.reserveLoginAttempt(ctx, limits)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkTwoFactorCode(ctx, user, req.Code, false); err != nil {
+		if !errors.Is(err, errmap.ErrInvalidTwoFactorCode) {
+			u.releaseLoginAttempt(ctx, limits, reservations)
+		}
+		return nil, err
+	}
+	u.releaseLoginAttempt(ctx, limits, reservations)
+
+	now := timeth.Now()
+	return u.issueTokens(ctx, user, sessionID, "", &now)
+}
+
+// SetTwoFactorRequired makes two-factor authentication mandatory for the user, or
+// optional again. Users who have not set it up are refused sensitive actions until they
+// do; the change applies to access tokens issued from now on.
+func (u *authUsecase) SetTwoFactorRequired(ctx context.Context, userID uuid.UUID, required bool) error {
+	return u.authRepo.SetTwoFactorRequired(ctx, userID, required)
+}
+
+// ResetTwoFactor turns off two-factor authentication for a user who lost their phone and
+// recovery codes, and signs them out everywhere. If it is required they must set it up
+// again.
+func (u *authUsecase) ResetTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	if _, err := u.authRepo.GetUserByID(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := u.authRepo.DisableTwoFactor(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := u.authRepo.RevokeSessions(ctx, userID, entity.SessionRevokedTwoFactorReset); err != nil {
+		return err
+	}
+	if err := u.loginLimiter.Reset(ctx, loginguard.TwoFactorKey(userID)); err != nil {
+		log.Printf("[AUTH] Warning: Failed to reset failed two-factor codes of user %s: %v", userID, err)
+	}
+
+	log.Printf("[AUTH] Two-factor authentication reset by an admin for user %s", userID)
+	return nil
+}
+
+// checkTwoFactorCode accepts a current code from the user's app that was not used before
+// or, if allowRecovery, an unused recovery code, and uses it up.
+func (u *authUsecase) checkTwoFactorCode(ctx context.Context, user *entity.User, code string, allowRecovery bool) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpCodeLength {
+		secret, err := fieldcrypt.Decrypt(user.TwoFactorSecret)
+		if err != nil {
+			return err
+		}
+		step, ok := totp.Validate(secret, code, timeth.Now())
+		if !ok || step <= user.TwoFactorLastStep {
+			return errmap.ErrInvalidTwoFactorCode
 		}
-		if r.ID == entity.RoleAdmin {
-			role = entity.RoleNameAdmin
+		return u.authRepo.UseTwoFactorStep(ctx, user.ID, step)
+	}
+
+	if !allowRecovery {
+		return errmap.ErrInvalidTwoFactorCode
+	}
+	return u.authRepo.UseRecoveryCode(ctx, user.ID, securetoken.Hash(normalizeRecoveryCode(code)))
+}
+
+func (u *authUsecase) ListOAuthProviders(ctx context.Context) *entity.OAuthProvidersResponse {
+	providers := make([]string, 0, len(u.oauthProviders))
+	for name := range u.oauthProviders {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	return &entity.OAuthProvidersResponse{Providers: providers}
+}
+
+// StartOAuthLogin returns the provider page the user signs in at. The state, nonce and
+// PKCE code verifier are kept until the user comes back, so the code can only be used by
+// the login that asked for it.
+func (u *authUsecase) StartOAuthLogin(ctx context.Context, provider string) (*entity.OAuthAuthorizeResponse, error) {
+	p, ok := u.oauthProviders[provider]
+	if !ok {
+		return nil, errmap.ErrOAuthProviderNotFound
+	}
+
+	state, err := securetoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := securetoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+	codeVerifier, err := securetoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+
+	now := timeth.Now()
+	if err := u.authRepo.CreateOAuthState(ctx, &entity.OAuthState{
+		StateHash:    securetoken.Hash(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(oauthStateTTL),
+		CreatedAt:    now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to create oauth state: %w", err)
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	return &entity.OAuthAuthorizeResponse{
+		AuthorizationURL: p.AuthCodeURL(state, nonce, base64.RawURLEncoding.EncodeToString(challenge[:])),
+		State:            state,
+	}, nil
+}
+
+// CompleteOAuthLogin signs the user in with the code the provider redirected them back
+// with. Users with two-factor authentication still have to enter their code.
+func (u *authUsecase) CompleteOAuthLogin(ctx context.Context, provider string, req *entity.OAuthCallbackRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	p, ok := u.oauthProviders[provider]
+	if !ok {
+		return nil, errmap.ErrOAuthProviderNotFound
+	}
+
+	state, err := u.authRepo.UseOAuthState(ctx, provider, securetoken.Hash(req.State))
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := p.Exchange(ctx, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("[AUTH] Warning: %s login failed: %v", provider, err)
+		return nil, errmap.ErrOAuthLoginFailed
+	}
+
+	user, err := u.oauthUser(ctx, provider, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabledAt != nil {
+		return u.startTwoFactorLogin(ctx, user)
+	}
+
+	return u.startSession(ctx, user, client, nil)
+}
+
+// oauthUser returns the user the identity is linked to. An identity signing in for the
+// first time is linked to the user with its email, or a new user is created for it, as
+// long as the provider has verified the email. Anyone can register an account with an
+// email they do not own, so linking to an unverified account takes it over: its
+// password, two-factor setup and sessions are dropped.
+func (u *authUsecase) oauthUser(ctx context.Context, provider string, identity *entity.OAuthIdentity) (*entity.User, error) {
+	linked, err := u.authRepo.GetUserIdentity(ctx, provider, identity.Subject)
+	if err == nil {
+		user, err := u.authRepo.GetUserByID(ctx, linked.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmap.ErrOAuthLoginFailed
+			}
+			return nil, err
+		}
+		if err := u.authRepo.TouchUserIdentity(ctx, linked.ID); err != nil {
+			log.Printf("[AUTH] Warning: Failed to update last use of identity %s: %v", linked.ID, err)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" {
+		return nil, errmap.ErrOAuthEmailRequired
+	}
+	if !identity.EmailVerified {
+		return nil, errmap.ErrOAuthEmailNotVerified
+	}
+
+	now := timeth.Now()
+	userIdentity := &entity.UserIdentity{
+		Provider:   provider,
+		Subject:    identity.Subject,
+		Email:      identity.Email,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+
+	user, err := u.authRepo.GetUserByEmail(ctx, identity.Email)
+	if err == nil {
+		userIdentity.UserID = user.ID
+		if err := u.authRepo.LinkUserIdentity(ctx, userIdentity); err != nil {
+			return nil, fmt.Errorf("failed to link identity: %w", err)
+		}
+		if user.EmailVerifiedAt == nil {
+			// The link dropped the credentials of the unverified account, so they must not
+			// decide how this login continues either.
+			user.EmailVerifiedAt = &now
+			user.Password = ""
+			user.TwoFactorSecret = ""
+			user.TwoFactorEnabledAt = nil
+			log.Printf("[AUTH] Claimed unverified user %s with %s; their password and sessions were dropped", user.ID, provider)
+		}
+		log.Printf("[AUTH] Linked %s account to user %s", provider, user.ID)
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user = &entity.User{
+		Email:           identity.Email,
+		FirstName:       identity.FirstName,
+		LastName:        identity.LastName,
+		EmailVerifiedAt: &now,
+	}
+	if identity.ImageURL != "" {
+		user.ImageURL = &identity.ImageURL
+	}
+	if err := u.authRepo.RegisterOAuthUser(ctx, user, userIdentity); err != nil {
+		return nil, fmt.Errorf("failed to register user: %w", err)
+	}
+	log.Printf("[AUTH] Registered user %s with %s", user.ID, provider)
+	return user, nil
+}
+
+func (u *authUsecase) ListIdentities(ctx context.Context, userID uuid.UUID) ([]*entity.UserIdentity, error) {
+	return u.authRepo.ListUserIdentities(ctx, userID)
+}
+
+// UnlinkIdentity removes a linked account, unless the user could not sign in without it.
+func (u *authUsecase) UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	identities, err := u.authRepo.ListUserIdentities(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, identity := range identities {
+		if identity.ID == identityID {
+			found = true
 			break
 		}
 	}
+	if !found {
+		return errmap.ErrIdentityNotFound
+	}
+	// Users who signed up with a provider have no password until they reset it.
+	if user.Password == "" && len(identities) == 1 {
+		return errmap.ErrLastSignInMethod
+	}
+
+	return u.authRepo.DeleteUserIdentity(ctx, userID, identityID)
+}
 
-	accessToken, err := jwt.GenerateAccessToken(user.ID, role)
+// RefreshToken uses up the refresh token and returns the session's next token pair. A
+// token that was already used has leaked or been stolen, so the whole session is revoked
+// and both holders have to sign in again.
+func (u *authUsecase) RefreshToken(ctx context.Context, req *entity.RefreshTokenRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	current, err := u.authRepo.GetRefreshToken(ctx, securetoken.Hash(req.RefreshToken))
 	if err != nil {
 		return nil, err
 	}
 
-	newRefreshToken, err := jwt.GenerateRefreshToken(user.ID)
+	session := current.Session
+	if session.RevokedAt != nil || !timeth.Now().Before(current.ExpiresAt) {
+		return nil, errmap.ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, u.revokeReusedSession(ctx, session)
+	}
+
+	user, err := u.authRepo.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	refreshToken, next, err := newRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.authRepo.RotateRefreshToken(ctx, current.ID, next, clientInfo(client)); err != nil {
+		if errors.Is(err, errmap.ErrRefreshTokenReused) {
+			return nil, u.revokeReusedSession(ctx, session)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return u.issueTokens(ctx, user, session.ID, refreshToken, nil)
+}
+
+// Logout revokes the session of the refresh token. Unknown and already revoked tokens
+// are ignored, so logging out twice succeeds.
+func (u *authUsecase) Logout(ctx context.Context, req *entity.LogoutRequest) error {
+	token, err := u.authRepo.GetRefreshToken(ctx, securetoken.Hash(req.RefreshToken))
+	if errors.Is(err, errmap.ErrInvalidRefreshToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = u.authRepo.RevokeSession(ctx, token.Session.UserID, token.SessionID, entity.SessionRevokedLogout)
+	if err != nil && !errors.Is(err, errmap.ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (u *authUsecase) LogoutAll(ctx context.Context, userID uuid.UUID) (*entity.LogoutAllResponse, error) {
+	revoked, err := u.authRepo.RevokeSessions(ctx, userID, entity.SessionRevokedLogoutAll)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.LogoutAllResponse{Revoked: revoked}, nil
+}
+
+func (u *authUsecase) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]*entity.SessionResponse, error) {
+	sessions, err := u.authRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entity.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, &entity.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return resp, nil
+}
+
+func (u *authUsecase) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	return u.authRepo.RevokeSession(ctx, userID, sessionID, entity.SessionRevokedLogout)
+}
+
+func (u *authUsecase) revokeReusedSession(ctx context.Context, session *entity.UserSession) error {
+	log.Printf("[AUTH] Refresh token of session %s (user %s) was used twice; revoking the session", session.ID, session.UserID)
+
+	err := u.authRepo.RevokeSession(ctx, session.UserID, session.ID, entity.SessionRevokedTokenReuse)
+	if err != nil && !errors.Is(err, errmap.ErrSessionNotFound) {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return errmap.ErrInvalidRefreshToken
+}
+
+// startSession signs the user in on a new session.
+// twoFactorAt is when the user entered a two-factor code to start it, if they did.
+func (u *authUsecase) startSession(ctx context.Context, user *entity.User, client entity.ClientInfo, twoFactorAt *time.Time) (*entity.AuthResponse, error) {
+	client = clientInfo(client)
+	now := timeth.Now()
+	session := &entity.UserSession{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IPAddress,
+		CreatedAt:  now,
+		LastUsedAt: now,
+	}
+
+	refreshToken, token, err := newRefreshToken(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	session.ExpiresAt = token.ExpiresAt
+
+	if err := u.authRepo.CreateSession(ctx, session, token); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return u.issueTokens(ctx, user, session.ID, refreshToken, twoFactorAt)
+}
+
+// issueTokens signs an access token for the session, carrying the user's highest role
+// and two-factor state, and pairs it with the session's refresh token.
+func (u *authUsecase) issueTokens(ctx context.Context, user *entity.User, sessionID uuid.UUID, refreshToken string, twoFactorAt *time.Time) (*entity.AuthResponse, error) {
+	roles, err := u.authRepo.GetRolesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	roleNames := tokenRoles(roles)
+
+	twoFactor := ""
+	switch {
+	case user.TwoFactorEnabledAt != nil:
+		twoFactor = jwt.TwoFactorEnabled
+	case user.TwoFactorRequired:
+		twoFactor = jwt.TwoFactorSetupRequired
+	}
+
+	accessToken, err := jwt.GenerateAccessToken(user.ID, roleNames, sessionID, twoFactor, twoFactorAt)
 	if err != nil {
 		return nil, err
 	}
@@ -218,11 +1001,40 @@ func (u *authUsecase) RefreshToken(ctx context.Context, req *entity.RefreshToken
 	user.Password = ""
 
 	return &entity.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
+		AccessToken:            accessToken,
+		RefreshToken:           refreshToken,
+		TwoFactorSetupRequired: twoFactor == jwt.TwoFactorSetupRequired,
 	}, nil
 }
 
+// tokenRoles returns the names of the roles for an access token, the main one first:
+// ADMIN, then SHOP, then USER. Shop staff keep the USER role they buy with. Accounts
+// without roles are users.
+func tokenRoles(roles []*entity.Role) []string {
+	has := make(map[uint32]bool, len(roles))
+	for _, r := range roles {
+		has[r.ID] = true
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, r := range []struct {
+		id   uint32
+		name string
+	}{
+		{entity.RoleAdmin, entity.RoleNameAdmin},
+		{entity.RoleShop, entity.RoleNameShop},
+		{entity.RoleUser, entity.RoleNameUser},
+	} {
+		if has[r.id] {
+			names = append(names, r.name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, entity.RoleNameUser)
+	}
+	return names
+}
+
 func (u *authUsecase) VerifyToken(ctx context.Context, token string) (*entity.User, error) {
 
 	claims, err := jwt.ValidateToken(token)
@@ -239,3 +1051,252 @@ func (u *authUsecase) VerifyToken(ctx context.Context, token string) (*entity.Us
 
 	return user, nil
 }
+
+func (u *authUsecase) VerifyEmail(ctx context.Context, req *entity.VerifyEmailRequest) error {
+	_, err := u.authRepo.VerifyEmail(ctx, securetoken.Hash(req.Token))
+	return err
+}
+
+func (u *authUsecase) ResendVerificationEmail(ctx context.Context, userID uuid.UUID) error {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.EmailVerifiedAt != nil {
+		return errmap.ErrEmailAlreadyVerified
+	}
+
+	recent, err := u.sentRecently(ctx, user.ID, entity.UserTokenEmailVerification)
+	if err != nil {
+		return err
+	}
+	if recent {
+		return errmap.ErrVerificationEmailRecent
+	}
+
+	return u.sendVerificationEmail(ctx, user)
+}
+
+// ForgotPassword emails a reset link if an account uses the email. It succeeds either
+// way, so it cannot be used to find out which emails have accounts.
+func (u *authUsecase) ForgotPassword(ctx context.Context, req *entity.ForgotPasswordRequest) error {
+	user, err := u.authRepo.GetUserByEmail(ctx, req.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	recent, err := u.sentRecently(ctx, user.ID, entity.UserTokenPasswordReset)
+	if err != nil || recent {
+		return err
+	}
+
+	token, userToken, err := newUserToken(user.ID, entity.UserTokenPasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.CreateUserToken(ctx, userToken); err != nil {
+		return fmt.Errorf("failed to create reset token: %w", err)
+	}
+
+	return u.queueEmail(ctx, user, entity.EmailTemplatePasswordReset, "user_token:"+userToken.ID.String(), notify.AccountData{
+		Name:           user.FirstName,
+		URL:            u.link("/reset-password", token),
+		ExpiresInHours: int(passwordResetTokenTTL / time.Hour),
+	})
+}
+
+func (u *authUsecase) ResetPassword(ctx context.Context, req *entity.ResetPasswordRequest) error {
+	hashedPassword, err := hash.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	user, err := u.authRepo.ResetPassword(ctx, securetoken.Hash(req.Token), hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	u.sendPasswordChangedEmail(ctx, user)
+	return nil
+}
+
+// ChangePassword sets a new password for a signed-in user. All the user's sessions are
+// revoked, so the current device is signed in again on a new session.
+func (u *authUsecase) ChangePassword(ctx context.Context, userID uuid.UUID, req *entity.ChangePasswordRequest, client entity.ClientInfo) (*entity.AuthResponse, error) {
+	user, err := u.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hash.CheckPassword(req.CurrentPassword, user.Password) {
+		return nil, errmap.ErrIncorrectPassword
+	}
+
+	hashedPassword, err := hash.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.authRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return nil, err
+	}
+
+	u.sendPasswordChangedEmail(ctx, user)
+
+	return u.startSession(ctx, user, client, nil)
+}
+
+func (u *authUsecase) sendVerificationEmail(ctx context.Context, user *entity.User) error {
+	token, userToken, err := newUserToken(user.ID, entity.UserTokenEmailVerification, verificationTokenTTL)
+	if err != nil {
+		return err
+	}
+	if err := u.authRepo.CreateUserToken(ctx, userToken); err != nil {
+		return fmt.Errorf("failed to create verification token: %w", err)
+	}
+
+	return u.queueEmail(ctx, user, entity.EmailTemplateVerifyEmail, "user_token:"+userToken.ID.String(), notify.AccountData{
+		Name:           user.FirstName,
+		URL:            u.link("/verify-email", token),
+		ExpiresInHours: int(verificationTokenTTL / time.Hour),
+	})
+}
+
+// sendPasswordChangedEmail lets the user know their password changed, in case it was not
+// them. The password is already changed, so failures are only logged.
+func (u *authUsecase) sendPasswordChangedEmail(ctx context.Context, user *entity.User) {
+	now := timeth.Now()
+	err := u.queueEmail(ctx, user, entity.EmailTemplatePasswordChanged, fmt.Sprintf("password_changed:%s:%d", user.ID, now.UnixNano()), notify.AccountData{
+		Name:      user.FirstName,
+		ChangedAt: now,
+	})
+	if err != nil {
+		log.Printf("[EMAIL] Failed to queue password changed email for user %s: %v", user.ID, err)
+	}
+}
+
+// sentRecently reports whether a token of the purpose was sent to the user within the
+// cooldown.
+func (u *authUsecase) sentRecently(ctx context.Context, userID uuid.UUID, purpose string) (bool, error) {
+	latest, err := u.authRepo.GetLatestUserToken(ctx, userID, purpose)
+	if err != nil {
+		return false, err
+	}
+	return latest != nil && timeth.Now().Sub(latest.CreatedAt) < tokenEmailCooldown, nil
+}
+
+// queueEmail queues an account email. Account emails are not notifications, so they are
+// sent whatever the user's notification preferences are.
+func (u *authUsecase) queueEmail(ctx context.Context, user *entity.User, template, dedupeKey string, data notify.AccountData) error {
+	email, err := notify.Render(template, user.Language, user.Email, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", template, err)
+	}
+
+	userID := user.ID
+	language := user.Language
+	if language == "" {
+		language = entity.LanguageThai
+	}
+	now := timeth.Now()
+
+	return u.notificationRepo.QueueEmails(ctx, []*entity.EmailMessage{{
+		UserID:        &userID,
+		Recipient:     email.To,
+		Template:      template,
+		Language:      language,
+		Subject:       email.Subject,
+		HTMLBody:      email.HTML,
+		TextBody:      email.Text,
+		DedupeKey:     dedupeKey,
+		Status:        entity.EmailStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}})
+}
+
+func (u *authUsecase) link(path, token string) string {
+	return u.appURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// newUserToken returns a random token to email to the user, and the UserToken to store
+// for it.
+func newUserToken(userID uuid.UUID, purpose string, ttl time.Duration) (string, *entity.UserToken, error) {
+	token, err := securetoken.Generate()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := timeth.Now()
+	return token, &entity.UserToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: securetoken.Hash(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, nil
+}
+
+// newRefreshToken returns a random refresh token for the session, and the RefreshToken
+// to store for it.
+func newRefreshToken(sessionID uuid.UUID) (string, *entity.RefreshToken, error) {
+	token, err := securetoken.Generate()
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := timeth.Now()
+	return token, &entity.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: sessionID,
+		TokenHash: securetoken.Hash(token),
+		ExpiresAt: now.Add(jwt.RefreshTokenDuration()),
+		CreatedAt: now,
+	}, nil
+}
+
+// newRecoveryCodes returns recoveryCodeCount codes formatted for display, such as
+// "k7mxq-2vt9p", and their hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	// 32 characters without i, l, o and 1, so a random byte maps to one without bias.
+	const alphabet = "abcdefghjkmnpqrstuvwxyz023456789"
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		for j := range b {
+			b[j] = alphabet[b[j]&31]
+		}
+		codes[i] = string(b[:5]) + "-" + string(b[5:])
+		hashes[i] = securetoken.Hash(normalizeRecoveryCode(codes[i]))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode lets users type recovery codes without the dash or in capitals.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// clientInfo trims the client info to the sizes sessions store.
+func clientInfo(client entity.ClientInfo) entity.ClientInfo {
+	return entity.ClientInfo{
+		UserAgent: truncate(client.UserAgent, 500),
+		IPAddress: truncate(client.IPAddress, 45),
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
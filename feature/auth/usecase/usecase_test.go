@@ -0,0 +1,832 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/config"
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/fieldcrypt"
+	"ecommerce-go-api/internal/hash"
+	"ecommerce-go-api/internal/jwt"
+	"ecommerce-go-api/internal/loginguard"
+	"ecommerce-go-api/internal/securetoken"
+	"ecommerce-go-api/internal/timeth"
+	"ecommerce-go-api/internal/totp"
+)
+
+func newTestUsecase(ctrl *gomock.Controller) (*authUsecase, *mock.MockAuthRepository, *mock.MockNotificationRepository) {
+	authRepo := mock.NewMockAuthRepository(ctrl)
+	notificationRepo := mock.NewMockNotificationRepository(ctrl)
+	return &authUsecase{
+		authRepo:         authRepo,
+		notificationRepo: notificationRepo,
+		loginLimiter:     loginguard.NewMemoryLimiter(),
+		accountPolicy:    loginguard.Policy{FreeFailures: 3, BaseDelay: time.Second, MaxDelay: 30 * time.Second, LockAfter: 10, LockDuration: 15 * time.Minute},
+		ipPolicy:         loginguard.Policy{FreeFailures: 20, BaseDelay: time.Second, MaxDelay: 30 * time.Second, LockAfter: 100, LockDuration: 15 * time.Minute},
+		appURL:           "https://shop.example.com",
+	}, authRepo, notificationRepo
+}
+
+// setTestSigningKey initialises jwt.Default with a fresh Ed25519 key.
+func setTestSigningKey(t *testing.T) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, jwt.Init("test:"+base64.StdEncoding.EncodeToString(pemKey), "test"))
+}
+
+func TestForgotPassword_EmailsSingleUseLinkAndStoresOnlyItsHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, notificationRepo := newTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New(), FirstName: "Somchai", Email: "somchai@example.com", Language: entity.LanguageEnglish}
+
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(user, nil)
+	authRepo.EXPECT().GetLatestUserToken(gomock.Any(), user.ID, entity.UserTokenPasswordReset).Return(nil, nil)
+
+	var stored *entity.UserToken
+	authRepo.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, token *entity.UserToken) error {
+		stored = token
+		return nil
+	})
+	notificationRepo.EXPECT().QueueEmails(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, emails []*entity.EmailMessage) error {
+		require.Len(t, emails, 1)
+		email := emails[0]
+		assert.Equal(t, entity.EmailTemplatePasswordReset, email.Template)
+		assert.Equal(t, user.Email, email.Recipient)
+
+		_, link, found := strings.Cut(email.TextBody, "https://shop.example.com/reset-password?token=")
+		require.True(t, found)
+		token, _, _ := strings.Cut(link, "\n")
+		assert.NotEqual(t, token, stored.TokenHash)
+		assert.Equal(t, securetoken.Hash(token), stored.TokenHash)
+		return nil
+	})
+
+	err := uc.ForgotPassword(context.Background(), &entity.ForgotPasswordRequest{Email: user.Email})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.UserTokenPasswordReset, stored.Purpose)
+	assert.WithinDuration(t, timeth.Now().Add(passwordResetTokenTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestForgotPassword_UnknownEmailSucceedsWithoutSending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	err := uc.ForgotPassword(context.Background(), &entity.ForgotPasswordRequest{Email: "nobody@example.com"})
+
+	assert.NoError(t, err)
+}
+
+func TestForgotPassword_RecentLinkIsNotResent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New(), Email: "somchai@example.com"}
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(user, nil)
+	authRepo.EXPECT().GetLatestUserToken(gomock.Any(), user.ID, entity.UserTokenPasswordReset).
+		Return(&entity.UserToken{CreatedAt: timeth.Now().Add(-10 * time.Second)}, nil)
+
+	err := uc.ForgotPassword(context.Background(), &entity.ForgotPasswordRequest{Email: user.Email})
+
+	assert.NoError(t, err)
+}
+
+func TestResetPassword_InvalidTokenIsRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	authRepo.EXPECT().ResetPassword(gomock.Any(), securetoken.Hash("used-token"), gomock.Any()).Return(nil, errmap.ErrInvalidUserToken)
+
+	err := uc.ResetPassword(context.Background(), &entity.ResetPasswordRequest{Token: "used-token", NewPassword: "newpassword"})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidUserToken)
+}
+
+func TestResendVerificationEmail_AlreadyVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	verifiedAt := timeth.Now()
+	user := &entity.User{ID: uuid.New(), EmailVerifiedAt: &verifiedAt}
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+
+	err := uc.ResendVerificationEmail(context.Background(), user.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrEmailAlreadyVerified)
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	hashed, err := hash.HashPassword("currentpassword")
+	require.NoError(t, err)
+	user := &entity.User{ID: uuid.New(), Password: hashed}
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+
+	_, err = uc.ChangePassword(context.Background(), user.ID, &entity.ChangePasswordRequest{CurrentPassword: "wrongpassword", NewPassword: "newpassword"}, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrIncorrectPassword)
+}
+
+func TestRefreshToken_RotatesTokenWithinSession(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New()}
+	session := &entity.UserSession{ID: uuid.New(), UserID: user.ID}
+	current := &entity.RefreshToken{ID: uuid.New(), SessionID: session.ID, ExpiresAt: timeth.Now().Add(time.Hour), Session: session}
+	client := entity.ClientInfo{UserAgent: "Mozilla/5.0", IPAddress: "203.0.113.7"}
+
+	authRepo.EXPECT().GetRefreshToken(gomock.Any(), securetoken.Hash("current-token")).Return(current, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	var next *entity.RefreshToken
+	authRepo.EXPECT().RotateRefreshToken(gomock.Any(), current.ID, gomock.Any(), client).DoAndReturn(func(ctx context.Context, usedTokenID uuid.UUID, token *entity.RefreshToken, client entity.ClientInfo) error {
+		next = token
+		return nil
+	})
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return([]*entity.Role{{ID: entity.RoleUser}}, nil)
+
+	resp, err := uc.RefreshToken(context.Background(), &entity.RefreshTokenRequest{RefreshToken: "current-token"}, client)
+
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, next.SessionID)
+	assert.Equal(t, securetoken.Hash(resp.RefreshToken), next.TokenHash)
+	assert.NotEqual(t, "current-token", resp.RefreshToken)
+
+	claims, err := jwt.ValidateToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, claims.SessionID)
+}
+
+func TestRefreshToken_ShopStaffKeepTheirBuyerRole(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New()}
+	session := &entity.UserSession{ID: uuid.New(), UserID: user.ID}
+	current := &entity.RefreshToken{ID: uuid.New(), SessionID: session.ID, ExpiresAt: timeth.Now().Add(time.Hour), Session: session}
+
+	authRepo.EXPECT().GetRefreshToken(gomock.Any(), securetoken.Hash("current-token")).Return(current, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().RotateRefreshToken(gomock.Any(), current.ID, gomock.Any(), gomock.Any()).Return(nil)
+	// A buyer who accepted a shop invitation.
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return([]*entity.Role{{ID: entity.RoleUser}, {ID: entity.RoleShop}}, nil)
+
+	resp, err := uc.RefreshToken(context.Background(), &entity.RefreshTokenRequest{RefreshToken: "current-token"}, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	claims, err := jwt.ValidateToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, entity.RoleNameShop, claims.Role)
+	assert.Equal(t, []string{entity.RoleNameShop, entity.RoleNameUser}, claims.Roles)
+}
+
+func TestRefreshToken_ReusedTokenRevokesSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	usedAt := timeth.Now().Add(-time.Minute)
+	session := &entity.UserSession{ID: uuid.New(), UserID: uuid.New()}
+	stolen := &entity.RefreshToken{ID: uuid.New(), SessionID: session.ID, ExpiresAt: timeth.Now().Add(time.Hour), UsedAt: &usedAt, Session: session}
+
+	authRepo.EXPECT().GetRefreshToken(gomock.Any(), securetoken.Hash("stolen-token")).Return(stolen, nil)
+	authRepo.EXPECT().RevokeSession(gomock.Any(), session.UserID, session.ID, entity.SessionRevokedTokenReuse).Return(nil)
+
+	_, err := uc.RefreshToken(context.Background(), &entity.RefreshTokenRequest{RefreshToken: "stolen-token"}, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidRefreshToken)
+}
+
+func TestRefreshToken_LosingRotationRaceRevokesSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New()}
+	session := &entity.UserSession{ID: uuid.New(), UserID: user.ID}
+	current := &entity.RefreshToken{ID: uuid.New(), SessionID: session.ID, ExpiresAt: timeth.Now().Add(time.Hour), Session: session}
+
+	authRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(current, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().RotateRefreshToken(gomock.Any(), current.ID, gomock.Any(), gomock.Any()).Return(errmap.ErrRefreshTokenReused)
+	authRepo.EXPECT().RevokeSession(gomock.Any(), user.ID, session.ID, entity.SessionRevokedTokenReuse).Return(nil)
+
+	_, err := uc.RefreshToken(context.Background(), &entity.RefreshTokenRequest{RefreshToken: "current-token"}, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidRefreshToken)
+}
+
+func TestRefreshToken_RevokedSessionIsRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	revokedAt := timeth.Now()
+	session := &entity.UserSession{ID: uuid.New(), UserID: uuid.New(), RevokedAt: &revokedAt}
+	current := &entity.RefreshToken{ID: uuid.New(), SessionID: session.ID, ExpiresAt: timeth.Now().Add(time.Hour), Session: session}
+	authRepo.EXPECT().GetRefreshToken(gomock.Any(), gomock.Any()).Return(current, nil)
+
+	_, err := uc.RefreshToken(context.Background(), &entity.RefreshTokenRequest{RefreshToken: "current-token"}, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidRefreshToken)
+}
+
+func TestLogout_UnknownTokenSucceeds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	authRepo.EXPECT().GetRefreshToken(gomock.Any(), securetoken.Hash("unknown-token")).Return(nil, errmap.ErrInvalidRefreshToken)
+
+	err := uc.Logout(context.Background(), &entity.LogoutRequest{RefreshToken: "unknown-token"})
+
+	assert.NoError(t, err)
+}
+
+func expectFailedLogin(authRepo *mock.MockAuthRepository, reason string) *gomock.Call {
+	return authRepo.EXPECT().CreateFailedLogin(gomock.Any(), gomock.Cond(func(attempt *entity.FailedLogin) bool {
+		return attempt.Reason == reason
+	})).Return(nil)
+}
+
+func TestLogin_LocksAccountAfterRepeatedFailuresUntilUnlocked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	uc.accountPolicy = loginguard.Policy{FreeFailures: 3, LockAfter: 3, LockDuration: 15 * time.Minute}
+	hashed, err := hash.HashPassword("correctpassword")
+	require.NoError(t, err)
+	user := &entity.User{ID: uuid.New(), Email: "somchai@example.com", Password: hashed}
+	client := entity.ClientInfo{IPAddress: "203.0.113.7"}
+	wrong := &entity.LoginRequest{Email: user.Email, Password: "wrongpassword"}
+
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(user, nil).Times(5)
+	expectFailedLogin(authRepo, entity.LoginFailureWrongPassword).Times(4)
+	expectFailedLogin(authRepo, entity.LoginFailureLocked)
+
+	for range 3 {
+		_, err := uc.Login(context.Background(), wrong, client)
+		require.ErrorIs(t, err, errmap.ErrInvalidCredentials)
+	}
+
+	// Locked: the password is not checked, even a correct one.
+	_, err = uc.Login(context.Background(), &entity.LoginRequest{Email: "SOMCHAI@example.com", Password: "correctpassword"}, client)
+	var blocked *errmap.LoginBlockedError
+	require.ErrorAs(t, err, &blocked)
+	assert.True(t, blocked.Locked)
+	assert.InDelta(t, (15 * time.Minute).Seconds(), blocked.RetryAfter.Seconds(), 5)
+	assert.ErrorIs(t, err, errmap.ErrTooManyLoginAttempts)
+
+	require.NoError(t, uc.UnlockLogin(context.Background(), &entity.UnlockLoginRequest{Email: user.Email}))
+
+	_, err = uc.Login(context.Background(), wrong, client)
+	assert.ErrorIs(t, err, errmap.ErrInvalidCredentials)
+}
+
+func TestLogin_ParallelGuessesCannotPassTheLockout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	uc.accountPolicy = loginguard.Policy{FreeFailures: 3, LockAfter: 3, LockDuration: 15 * time.Minute}
+	hashed, err := hash.HashPassword("correctpassword")
+	require.NoError(t, err)
+	user := &entity.User{ID: uuid.New(), Email: "somchai@example.com", Password: hashed}
+	wrong := &entity.LoginRequest{Email: user.Email, Password: "wrongpassword"}
+
+	var checked atomic.Int32
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).DoAndReturn(func(ctx context.Context, email string) (*entity.User, error) {
+		checked.Add(1)
+		return user, nil
+	}).AnyTimes()
+	authRepo.EXPECT().CreateFailedLogin(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			_, err := uc.Login(context.Background(), wrong, entity.ClientInfo{IPAddress: "203.0.113.7"})
+			assert.Error(t, err)
+		})
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, 3, checked.Load(), "only the attempts before the lockout check the password")
+
+	// The lockout holds for the right password too.
+	_, err = uc.Login(context.Background(), &entity.LoginRequest{Email: user.Email, Password: "correctpassword"}, entity.ClientInfo{})
+	assert.ErrorIs(t, err, errmap.ErrTooManyLoginAttempts)
+}
+
+func TestLogin_UnknownEmailIsDelayedLikeAnAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	uc.accountPolicy = loginguard.Policy{FreeFailures: 0, BaseDelay: time.Minute, MaxDelay: time.Hour, LockAfter: 10, LockDuration: 15 * time.Minute}
+	req := &entity.LoginRequest{Email: "nobody@example.com", Password: "guess"}
+
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), req.Email).Return(nil, gorm.ErrRecordNotFound)
+	expectFailedLogin(authRepo, entity.LoginFailureUnknownEmail)
+	expectFailedLogin(authRepo, entity.LoginFailureThrottled)
+
+	_, err := uc.Login(context.Background(), req, entity.ClientInfo{})
+	require.ErrorIs(t, err, errmap.ErrInvalidCredentials)
+
+	_, err = uc.Login(context.Background(), req, entity.ClientInfo{})
+	var blocked *errmap.LoginBlockedError
+	require.ErrorAs(t, err, &blocked)
+	assert.False(t, blocked.Locked)
+	assert.InDelta(t, time.Minute.Seconds(), blocked.RetryAfter.Seconds(), 5)
+}
+
+func TestLogin_IPAddressIsLockedAcrossAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	uc.ipPolicy = loginguard.Policy{FreeFailures: 2, LockAfter: 2, LockDuration: 15 * time.Minute}
+	client := entity.ClientInfo{IPAddress: "203.0.113.7"}
+
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).Times(2)
+	expectFailedLogin(authRepo, entity.LoginFailureUnknownEmail).Times(2)
+	expectFailedLogin(authRepo, entity.LoginFailureLocked)
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		_, err := uc.Login(context.Background(), &entity.LoginRequest{Email: email, Password: "guess"}, client)
+		require.ErrorIs(t, err, errmap.ErrInvalidCredentials)
+	}
+
+	_, err := uc.Login(context.Background(), &entity.LoginRequest{Email: "c@example.com", Password: "guess"}, client)
+	assert.ErrorIs(t, err, errmap.ErrTooManyLoginAttempts)
+
+	// Another address is not affected.
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), "c@example.com").Return(nil, gorm.ErrRecordNotFound)
+	expectFailedLogin(authRepo, entity.LoginFailureUnknownEmail)
+	_, err = uc.Login(context.Background(), &entity.LoginRequest{Email: "c@example.com", Password: "guess"}, entity.ClientInfo{IPAddress: "198.51.100.1"})
+	assert.ErrorIs(t, err, errmap.ErrInvalidCredentials)
+}
+
+// setTestFieldKey configures fieldcrypt.Default, which two-factor secrets are encrypted
+// with. Like the signing key, it is loaded once per process.
+func setTestFieldKey(t *testing.T) {
+	t.Helper()
+	config.FIELD_ENCRYPTION_KEYS = "k1:" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+	config.FIELD_ENCRYPTION_ACTIVE_KEY = "k1"
+}
+
+// newTwoFactorUser returns a user with two-factor authentication enabled and the plain
+// secret of their authenticator app.
+func newTwoFactorUser(t *testing.T) (*entity.User, string) {
+	t.Helper()
+	setTestFieldKey(t)
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	encrypted, err := fieldcrypt.Encrypt(secret)
+	require.NoError(t, err)
+	enabledAt := timeth.Now().Add(-24 * time.Hour)
+	return &entity.User{ID: uuid.New(), Email: "somchai@example.com", TwoFactorSecret: encrypted, TwoFactorEnabledAt: &enabledAt}, secret
+}
+
+func TestLogin_TwoFactorAccountGetsTokenInsteadOfSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+	hashed, err := hash.HashPassword("correctpassword")
+	require.NoError(t, err)
+	user.Password = hashed
+
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(user, nil)
+	var stored *entity.UserToken
+	authRepo.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, token *entity.UserToken) error {
+		stored = token
+		return nil
+	})
+
+	resp, err := uc.Login(context.Background(), &entity.LoginRequest{Email: user.Email, Password: "correctpassword"}, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.True(t, resp.TwoFactorRequired)
+	assert.Empty(t, resp.AccessToken)
+	assert.Empty(t, resp.RefreshToken)
+	assert.Equal(t, entity.UserTokenTwoFactorLogin, stored.Purpose)
+	assert.Equal(t, securetoken.Hash(resp.TwoFactorToken), stored.TokenHash)
+	assert.WithinDuration(t, timeth.Now().Add(twoFactorLoginTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestVerifyTwoFactorLogin_AppCodeStartsVerifiedSession(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, secret := newTwoFactorUser(t)
+	step := totp.Step(timeth.Now())
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+	tokenHash := securetoken.Hash("two-factor-token")
+
+	authRepo.EXPECT().GetUserToken(gomock.Any(), entity.UserTokenTwoFactorLogin, tokenHash).Return(&entity.UserToken{UserID: user.ID}, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().UseTwoFactorStep(gomock.Any(), user.ID, step).Return(nil)
+	authRepo.EXPECT().UseUserToken(gomock.Any(), entity.UserTokenTwoFactorLogin, tokenHash).Return(nil)
+	authRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return([]*entity.Role{{ID: entity.RoleUser}}, nil)
+
+	resp, err := uc.VerifyTwoFactorLogin(context.Background(), &entity.TwoFactorLoginRequest{TwoFactorToken: "two-factor-token", Code: code}, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.RefreshToken)
+	claims, err := jwt.ValidateToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.TwoFactorEnabled, claims.TwoFactor)
+	require.NotNil(t, claims.TwoFactorAt)
+	assert.WithinDuration(t, timeth.Now(), claims.TwoFactorAt.Time, time.Minute)
+}
+
+func TestVerifyTwoFactorLogin_ReplayedCodeIsRejectedAndAudited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, secret := newTwoFactorUser(t)
+	step := totp.Step(timeth.Now())
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+	user.TwoFactorLastStep = step
+
+	authRepo.EXPECT().GetUserToken(gomock.Any(), entity.UserTokenTwoFactorLogin, gomock.Any()).Return(&entity.UserToken{UserID: user.ID}, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	expectFailedLogin(authRepo, entity.LoginFailureWrongTwoFactorCode)
+
+	_, err = uc.VerifyTwoFactorLogin(context.Background(), &entity.TwoFactorLoginRequest{TwoFactorToken: "two-factor-token", Code: code}, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidTwoFactorCode)
+}
+
+func TestVerifyTwoFactorLogin_RecoveryCodeIsNormalized(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+
+	authRepo.EXPECT().GetUserToken(gomock.Any(), entity.UserTokenTwoFactorLogin, gomock.Any()).Return(&entity.UserToken{UserID: user.ID}, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().UseRecoveryCode(gomock.Any(), user.ID, securetoken.Hash("abcde23456")).Return(nil)
+	authRepo.EXPECT().UseUserToken(gomock.Any(), entity.UserTokenTwoFactorLogin, gomock.Any()).Return(nil)
+	authRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return(nil, nil)
+
+	_, err := uc.VerifyTwoFactorLogin(context.Background(), &entity.TwoFactorLoginRequest{TwoFactorToken: "two-factor-token", Code: " ABCDE-23456 "}, entity.ClientInfo{})
+
+	assert.NoError(t, err)
+}
+
+func TestEnableTwoFactor_StoresOnlyRecoveryCodeHashes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, secret := newTwoFactorUser(t)
+	user.TwoFactorEnabledAt = nil
+	step := totp.Step(timeth.Now())
+	code, err := totp.Code(secret, step)
+	require.NoError(t, err)
+
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	var hashes []string
+	authRepo.EXPECT().EnableTwoFactor(gomock.Any(), user.ID, step, gomock.Any()).DoAndReturn(func(ctx context.Context, userID uuid.UUID, step int64, codeHashes []string) error {
+		hashes = codeHashes
+		return nil
+	})
+
+	resp, err := uc.EnableTwoFactor(context.Background(), user.ID, &entity.TwoFactorCodeRequest{Code: code})
+
+	require.NoError(t, err)
+	require.Len(t, resp.RecoveryCodes, recoveryCodeCount)
+	require.Len(t, hashes, recoveryCodeCount)
+	for i, recoveryCode := range resp.RecoveryCodes {
+		assert.Regexp(t, `^[a-z0-9]{5}-[a-z0-9]{5}$`, recoveryCode)
+		assert.Equal(t, securetoken.Hash(normalizeRecoveryCode(recoveryCode)), hashes[i])
+	}
+}
+
+func TestEnableTwoFactor_WrongCodeIsRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, secret := newTwoFactorUser(t)
+	user.TwoFactorEnabledAt = nil
+	code, err := totp.Code(secret, totp.Step(timeth.Now())+5)
+	require.NoError(t, err)
+
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+
+	_, err = uc.EnableTwoFactor(context.Background(), user.ID, &entity.TwoFactorCodeRequest{Code: code})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidTwoFactorCode)
+}
+
+func TestDisableTwoFactor_RequiredByAdminIsRefused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+	user.TwoFactorRequired = true
+
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+
+	err := uc.DisableTwoFactor(context.Background(), user.ID, &entity.DisableTwoFactorRequest{Password: "correctpassword", Code: "123456"})
+
+	assert.ErrorIs(t, err, errmap.ErrTwoFactorEnforced)
+}
+
+func TestReverifyTwoFactor_RecoveryCodeIsNotAccepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+
+	_, err := uc.ReverifyTwoFactor(context.Background(), user.ID, uuid.New(), &entity.TwoFactorCodeRequest{Code: "abcde-23456"})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidTwoFactorCode)
+}
+
+func TestResetTwoFactor_RevokesAllSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().DisableTwoFactor(gomock.Any(), user.ID).Return(nil)
+	authRepo.EXPECT().RevokeSessions(gomock.Any(), user.ID, entity.SessionRevokedTwoFactorReset).Return(int64(2), nil)
+
+	assert.NoError(t, uc.ResetTwoFactor(context.Background(), user.ID))
+}
+
+func newOAuthTestUsecase(ctrl *gomock.Controller) (*authUsecase, *mock.MockAuthRepository, *mock.MockOAuthProvider) {
+	uc, authRepo, _ := newTestUsecase(ctrl)
+	provider := mock.NewMockOAuthProvider(ctrl)
+	uc.oauthProviders = map[string]domain.OAuthProvider{"google": provider}
+	return uc, authRepo, provider
+}
+
+// expectOAuthCallback expects the state of the callback to be used and the code to be
+// exchanged for the identity.
+func expectOAuthCallback(authRepo *mock.MockAuthRepository, provider *mock.MockOAuthProvider, identity *entity.OAuthIdentity) {
+	state := &entity.OAuthState{Provider: "google", Nonce: "nonce-1", CodeVerifier: "verifier-1"}
+	authRepo.EXPECT().UseOAuthState(gomock.Any(), "google", securetoken.Hash("state-1")).Return(state, nil)
+	provider.EXPECT().Exchange(gomock.Any(), "code-1", "verifier-1", "nonce-1").Return(identity, nil)
+}
+
+var oauthCallback = &entity.OAuthCallbackRequest{Code: "code-1", State: "state-1"}
+
+func TestStartOAuthLogin_StoresStateWithPKCEVerifier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	var stored *entity.OAuthState
+	authRepo.EXPECT().CreateOAuthState(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, state *entity.OAuthState) error {
+		stored = state
+		return nil
+	})
+	var challenge string
+	provider.EXPECT().AuthCodeURL(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(state, nonce, codeChallenge string) string {
+		challenge = codeChallenge
+		return "https://accounts.example.com/authorize?state=" + state
+	})
+
+	resp, err := uc.StartOAuthLogin(context.Background(), "google")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://accounts.example.com/authorize?state="+resp.State, resp.AuthorizationURL)
+	assert.Equal(t, securetoken.Hash(resp.State), stored.StateHash)
+	assert.Equal(t, "google", stored.Provider)
+	sum := sha256.Sum256([]byte(stored.CodeVerifier))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), challenge)
+	assert.WithinDuration(t, timeth.Now().Add(oauthStateTTL), stored.ExpiresAt, time.Minute)
+}
+
+func TestStartOAuthLogin_UnknownProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, _, _ := newOAuthTestUsecase(ctrl)
+
+	_, err := uc.StartOAuthLogin(context.Background(), "myspace")
+
+	assert.ErrorIs(t, err, errmap.ErrOAuthProviderNotFound)
+}
+
+func TestCompleteOAuthLogin_LinkedIdentitySignsIn(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New(), Email: "somchai@example.com"}
+	linked := &entity.UserIdentity{ID: uuid.New(), UserID: user.ID, Provider: "google", Subject: "google-1"}
+	expectOAuthCallback(authRepo, provider, &entity.OAuthIdentity{Subject: "google-1", Email: "another@example.com"})
+
+	authRepo.EXPECT().GetUserIdentity(gomock.Any(), "google", "google-1").Return(linked, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().TouchUserIdentity(gomock.Any(), linked.ID).Return(nil)
+	authRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return([]*entity.Role{{ID: entity.RoleUser}}, nil)
+
+	resp, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	claims, err := jwt.ValidateToken(resp.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+}
+
+func TestCompleteOAuthLogin_LinksExistingUserByVerifiedEmail(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	verifiedAt := timeth.Now().Add(-24 * time.Hour)
+	user := &entity.User{ID: uuid.New(), Email: "somchai@example.com", EmailVerifiedAt: &verifiedAt}
+	expectOAuthCallback(authRepo, provider, &entity.OAuthIdentity{Subject: "google-1", Email: user.Email, EmailVerified: true})
+
+	authRepo.EXPECT().GetUserIdentity(gomock.Any(), "google", "google-1").Return(nil, gorm.ErrRecordNotFound)
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(user, nil)
+	authRepo.EXPECT().LinkUserIdentity(gomock.Any(), gomock.Cond(func(identity *entity.UserIdentity) bool {
+		return identity.UserID == user.ID && identity.Provider == "google" && identity.Subject == "google-1"
+	})).Return(nil)
+	authRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return(nil, nil)
+
+	_, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	assert.NoError(t, err)
+}
+
+func TestCompleteOAuthLogin_LinkingUnverifiedAccountDropsItsCredentials(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Someone registered the account with the email, and set up two-factor, before its
+	// owner signed in with Google.
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+	expectOAuthCallback(authRepo, provider, &entity.OAuthIdentity{Subject: "google-1", Email: user.Email, EmailVerified: true})
+
+	authRepo.EXPECT().GetUserIdentity(gomock.Any(), "google", "google-1").Return(nil, gorm.ErrRecordNotFound)
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), user.Email).Return(user, nil)
+	authRepo.EXPECT().LinkUserIdentity(gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), user.ID).Return(nil, nil)
+
+	resp, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.False(t, resp.TwoFactorRequired, "the two-factor setup of the unverified account is not used")
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.NotNil(t, user.EmailVerifiedAt)
+}
+
+func TestCompleteOAuthLogin_UnverifiedEmailIsNotLinked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	expectOAuthCallback(authRepo, provider, &entity.OAuthIdentity{Subject: "google-1", Email: "somchai@example.com"})
+	authRepo.EXPECT().GetUserIdentity(gomock.Any(), "google", "google-1").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrOAuthEmailNotVerified)
+}
+
+func TestCompleteOAuthLogin_CreatesUserWithVerifiedEmail(t *testing.T) {
+	setTestSigningKey(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	expectOAuthCallback(authRepo, provider, &entity.OAuthIdentity{Subject: "google-1", Email: "somchai@example.com", EmailVerified: true, FirstName: "Somchai", LastName: "Jaidee"})
+
+	authRepo.EXPECT().GetUserIdentity(gomock.Any(), "google", "google-1").Return(nil, gorm.ErrRecordNotFound)
+	authRepo.EXPECT().GetUserByEmail(gomock.Any(), "somchai@example.com").Return(nil, gorm.ErrRecordNotFound)
+	var created *entity.User
+	authRepo.EXPECT().RegisterOAuthUser(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user *entity.User, identity *entity.UserIdentity) error {
+		user.ID = uuid.New()
+		created = user
+		assert.Equal(t, "google-1", identity.Subject)
+		return nil
+	})
+	authRepo.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	authRepo.EXPECT().GetRolesByUserID(gomock.Any(), gomock.Any()).Return([]*entity.Role{{ID: entity.RoleUser}}, nil)
+
+	resp, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Equal(t, "Somchai", created.FirstName)
+	assert.Empty(t, created.Password)
+	assert.NotNil(t, created.EmailVerifiedAt)
+}
+
+func TestCompleteOAuthLogin_TwoFactorUserGetsTwoFactorToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, provider := newOAuthTestUsecase(ctrl)
+	user, _ := newTwoFactorUser(t)
+	linked := &entity.UserIdentity{ID: uuid.New(), UserID: user.ID}
+	expectOAuthCallback(authRepo, provider, &entity.OAuthIdentity{Subject: "google-1"})
+
+	authRepo.EXPECT().GetUserIdentity(gomock.Any(), "google", "google-1").Return(linked, nil)
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().TouchUserIdentity(gomock.Any(), linked.ID).Return(nil)
+	authRepo.EXPECT().CreateUserToken(gomock.Any(), gomock.Any()).Return(nil)
+
+	resp, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	require.NoError(t, err)
+	assert.True(t, resp.TwoFactorRequired)
+	assert.Empty(t, resp.AccessToken)
+}
+
+func TestCompleteOAuthLogin_UsedStateIsRejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newOAuthTestUsecase(ctrl)
+	authRepo.EXPECT().UseOAuthState(gomock.Any(), "google", securetoken.Hash("state-1")).Return(nil, errmap.ErrInvalidOAuthState)
+
+	_, err := uc.CompleteOAuthLogin(context.Background(), "google", oauthCallback, entity.ClientInfo{})
+
+	assert.ErrorIs(t, err, errmap.ErrInvalidOAuthState)
+}
+
+func TestUnlinkIdentity_LastSignInMethodIsKept(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, authRepo, _ := newOAuthTestUsecase(ctrl)
+	user := &entity.User{ID: uuid.New()}
+	identity := &entity.UserIdentity{ID: uuid.New(), UserID: user.ID}
+	authRepo.EXPECT().GetUserByID(gomock.Any(), user.ID).Return(user, nil)
+	authRepo.EXPECT().ListUserIdentities(gomock.Any(), user.ID).Return([]*entity.UserIdentity{identity}, nil)
+
+	err := uc.UnlinkIdentity(context.Background(), user.ID, identity.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrLastSignInMethod)
+}
@@ -8,16 +8,22 @@ import (
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 
+	"ecommerce-go-api/config"
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
 
 	cartRepo "ecommerce-go-api/feature/cart/repository"
 	cartUsecase "ecommerce-go-api/feature/cart/usecase"
+	"ecommerce-go-api/feature/courier/estimate"
+	"ecommerce-go-api/feature/courier/provider"
+	courierRepo "ecommerce-go-api/feature/courier/repository"
 	orderRepo "ecommerce-go-api/feature/order/repository"
 	orderUsecase "ecommerce-go-api/feature/order/usecase"
+	outboxRepo "ecommerce-go-api/feature/outbox/repository"
 	productRepo "ecommerce-go-api/feature/product/repository"
 	shopRepo "ecommerce-go-api/feature/shop/repository"
 	userRepo "ecommerce-go-api/feature/user/repository"
+	"ecommerce-go-api/internal/dbtx"
 	"ecommerce-go-api/internal/errmap"
 	"ecommerce-go-api/internal/response"
 	"ecommerce-go-api/middleware"
@@ -272,7 +278,7 @@ func (h *CartHandler) DeleteItem(c echo.Context) error {
 // Estimate godoc
 //
 //	@Summary		Estimate shipping per shop for given cart items
-//	@Description	Calculate shipping costs grouped by shop for selected cart items
+//	@Description	Calculate shipping costs and the estimated delivery window grouped by shop for selected cart items
 //	@Tags			Cart
 //	@Security		BearerAuth
 //	@Accept			json
@@ -281,6 +287,7 @@ func (h *CartHandler) DeleteItem(c echo.Context) error {
 //	@Success		200		{object}	entity.CartShippingEstimateResponse
 //	@Failure		400		{object}	response.ResponseError
 //	@Failure		401		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
 //	@Failure		500		{object}	response.ResponseError
 //	@Router			/api/cart/estimate [post]
 func (h *CartHandler) Estimate(c echo.Context) error {
@@ -298,9 +305,16 @@ func (h *CartHandler) Estimate(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, err.Error())
 	}
 
-	resp, err := h.cartUsecase.EstimateShipping(c.Request().Context(), userID, req.CartItemIDs)
+	resp, err := h.cartUsecase.EstimateShipping(c.Request().Context(), userID, req.CartItemIDs, req.AddressID)
 	if err != nil {
-		return response.Error(c, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, errmap.ErrAddressNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, errmap.ErrNoShippingOptions):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
 	}
 	return response.Success(c, http.StatusOK, "estimate", resp)
 }
@@ -311,8 +325,9 @@ func RegisterCartHandler(group *echo.Group, db *gorm.DB) {
 	productRepository := productRepo.NewProductRepository(db)
 	orderRepository := orderRepo.NewOrderRepository(db)
 	userRepository := userRepo.NewUserRepository(db)
-	orderUsecase := orderUsecase.NewOrderUsecase(orderRepository, shopRepository, productRepository, userRepository)
-	cartUsecase := cartUsecase.NewCartUsecase(repo, productRepository, shopRepository)
+	estimator := estimate.NewEstimator(courierRepo.NewCourierRepository(db))
+	orderUsecase := orderUsecase.NewOrderUsecase(orderRepository, shopRepository, productRepository, userRepository, provider.Default(), estimator, outboxRepo.NewOutboxRepository(db), dbtx.NewTransactor(db), config.REQUIRE_EMAIL_VERIFICATION)
+	cartUsecase := cartUsecase.NewCartUsecase(repo, productRepository, shopRepository, userRepository, estimator)
 	cartHandler := NewCartHandler(repo, cartUsecase, orderUsecase)
 	cartHandler.RegisterRoutes(group)
 }
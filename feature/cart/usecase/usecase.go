@@ -20,24 +20,63 @@ type cartUsecase struct {
 	cartRepo    domain.CartRepository
 	productRepo domain.ProductRepository
 	shopRepo    domain.ShopRepository
+	userRepo    domain.UserRepository
+	estimator   domain.DeliveryEstimator
 	validate    *validator.Validate
 }
 
-func NewCartUsecase(cartRepo domain.CartRepository, productRepo domain.ProductRepository, shopRepo domain.ShopRepository) domain.CartUsecase {
+func NewCartUsecase(cartRepo domain.CartRepository, productRepo domain.ProductRepository, shopRepo domain.ShopRepository, userRepo domain.UserRepository, estimator domain.DeliveryEstimator) domain.CartUsecase {
 	return &cartUsecase{
 		cartRepo:    cartRepo,
 		productRepo: productRepo,
 		shopRepo:    shopRepo,
+		userRepo:    userRepo,
+		estimator:   estimator,
 		validate:    validator.New(),
 	}
 }
 
-func (u *cartUsecase) EstimateShipping(ctx context.Context, userID uuid.UUID, cartItemIDs []uint32) (*entity.CartShippingEstimateResponse, error) {
+// destinationProvinceID returns the province of the address a cart would ship to: the
+// given address, or the buyer's default address when none is given.
+func (u *cartUsecase) destinationProvinceID(ctx context.Context, userID uuid.UUID, addressID *uint32) (*uint32, error) {
+	if addressID != nil {
+		addr, err := u.userRepo.GetAddressByID(ctx, *addressID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmap.ErrAddressNotFound
+			}
+			return nil, err
+		}
+		if addr.UserID != userID {
+			return nil, errmap.ErrAddressNotFound
+		}
+		return &addr.ProvinceID, nil
+	}
+
+	addresses, err := u.userRepo.GetAddressesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addresses {
+		if addr.IsDefault {
+			return &addr.ProvinceID, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (u *cartUsecase) EstimateShipping(ctx context.Context, userID uuid.UUID, cartItemIDs []uint32, addressID *uint32) (*entity.CartShippingEstimateResponse, error) {
 	cartItems, err := u.cartRepo.GetCartItemsByIDs(ctx, cartItemIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get cart items: %w", err)
 	}
 
+	destination, err := u.destinationProvinceID(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
 	shopMap := make(map[string][]entity.CartItemShop)
 	shopSubtotals := make(map[string]float64)
 	shopUUIDs := make([]uuid.UUID, 0)
@@ -116,6 +155,20 @@ func (u *cartUsecase) EstimateShipping(ctx context.Context, userID uuid.UUID, ca
 			Subtotal: subtotal,
 			Courier:  courierOpt,
 		}
+
+		if shop, ok := shopDetailsMap[shopID]; ok && destination != nil {
+			window, err := u.estimator.Estimate(ctx, entity.DeliveryEstimateRequest{
+				CourierID:             courierOpt.CourierID,
+				OriginProvinceID:      shop.ProvinceID,
+				DestinationProvinceID: destination,
+				HandlingDays:          shop.HandlingDays,
+				From:                  timeth.Now(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to estimate delivery: %w", err)
+			}
+			shopEstimate.EstimatedDelivery = window
+		}
 		resp.Shop = append(resp.Shop, shopEstimate)
 		grandTotal += subtotal + courierOpt.Price
 	}
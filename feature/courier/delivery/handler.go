@@ -1,11 +1,14 @@
 package delivery
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/labstack/echo/v4"
 
 	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
 	"ecommerce-go-api/internal/errmap"
 	"ecommerce-go-api/internal/response"
 )
@@ -39,3 +42,180 @@ func (h *CourierHandler) ListCouriers(c echo.Context) error {
 
 	return response.Success(c, http.StatusOK, "ok", couriers)
 }
+
+// ListTransitTimes godoc
+//
+//	@Summary		List courier transit times (admin)
+//	@Description	Get the transit time rules used for delivery estimates. A null province matches any province
+//	@Tags			Courier
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			courierId	path		int	true	"Courier ID"
+//	@Success		200			{array}		entity.CourierTransitTimeResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/admin/couriers/{courierId}/transit-times [get]
+func (h *CourierHandler) ListTransitTimes(c echo.Context) error {
+	courierID, err := strconv.ParseUint(c.Param("courierId"), 10, 32)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidCourierID.Error())
+	}
+
+	transitTimes, err := h.usecase.ListTransitTimes(c.Request().Context(), uint32(courierID))
+	if err != nil {
+		if errors.Is(err, errmap.ErrCourierNotFound) {
+			return response.Error(c, http.StatusNotFound, err.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", transitTimes)
+}
+
+// UpdateTransitTimes godoc
+//
+//	@Summary		Replace courier transit times (admin)
+//	@Description	Replace every transit time rule of a courier. Each rule gives the business days between an origin and destination province; leave a province out to match any province. The most specific rule wins
+//	@Tags			Courier
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			courierId	path		int										true	"Courier ID"
+//	@Param			body		body		entity.UpdateCourierTransitTimesRequest	true	"Transit time rules"
+//	@Success		200			{array}		entity.CourierTransitTimeResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/admin/couriers/{courierId}/transit-times [put]
+func (h *CourierHandler) UpdateTransitTimes(c echo.Context) error {
+	courierID, err := strconv.ParseUint(c.Param("courierId"), 10, 32)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidCourierID.Error())
+	}
+
+	var req entity.UpdateCourierTransitTimesRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	transitTimes, err := h.usecase.UpdateTransitTimes(c.Request().Context(), uint32(courierID), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrCourierNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, errmap.ErrDuplicateTransitTime):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "transit times updated", transitTimes)
+}
+
+// ListHolidays godoc
+//
+//	@Summary		List holidays (admin)
+//	@Description	Get the holidays skipped by delivery estimates for a year
+//	@Tags			Courier
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			year	query		int	false	"Year (default: current year)"
+//	@Success		200		{array}		entity.HolidayResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/holidays [get]
+func (h *CourierHandler) ListHolidays(c echo.Context) error {
+	var req entity.HolidayListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	holidays, err := h.usecase.ListHolidays(c.Request().Context(), req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", holidays)
+}
+
+// CreateHoliday godoc
+//
+//	@Summary		Add a holiday (admin)
+//	@Description	Add a day on which shops and couriers do not work
+//	@Tags			Courier
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.CreateHolidayRequest	true	"Holiday"
+//	@Success		201		{object}	entity.HolidayResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/holidays [post]
+func (h *CourierHandler) CreateHoliday(c echo.Context) error {
+	var req entity.CreateHolidayRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	holiday, err := h.usecase.CreateHoliday(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidHolidayDate):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrHolidayExists):
+			return response.Error(c, http.StatusConflict, err.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusCreated, "created", holiday)
+}
+
+// DeleteHoliday godoc
+//
+//	@Summary		Remove a holiday (admin)
+//	@Description	Remove a holiday from the delivery estimate calendar
+//	@Tags			Courier
+//	@Security		BearerAuth
+//	@Param			date	path		string	true	"Holiday date (YYYY-MM-DD)"
+//	@Success		204		{object}	object
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/holidays/{date} [delete]
+func (h *CourierHandler) DeleteHoliday(c echo.Context) error {
+	if err := h.usecase.DeleteHoliday(c.Request().Context(), c.Param("date")); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidHolidayDate):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrHolidayNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.NoContent(c)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 
+	"ecommerce-go-api/feature/courier/provider"
 	"ecommerce-go-api/feature/courier/repository"
 	"ecommerce-go-api/feature/courier/usecase"
 	"ecommerce-go-api/middleware"
@@ -11,11 +12,20 @@ import (
 
 func RegisterCourierHandler(group *echo.Group, db *gorm.DB) {
 	repo := repository.NewCourierRepository(db)
-	uc := usecase.NewCourierUsecase(repo)
+	uc := usecase.NewCourierUsecase(repo, provider.Default())
 	handler := NewCourierHandler(uc)
 
 	couriers := group.Group("/couriers")
 	couriers.Use(middleware.JWTAuth(), middleware.ShopOwnerOnly())
 
 	couriers.GET("", handler.ListCouriers)
+
+	adminCouriers := group.Group("/admin/couriers", middleware.JWTAuth(), middleware.AdminOnly())
+	adminCouriers.GET("/:courierId/transit-times", handler.ListTransitTimes)
+	adminCouriers.PUT("/:courierId/transit-times", handler.UpdateTransitTimes)
+
+	holidays := group.Group("/admin/holidays", middleware.JWTAuth(), middleware.AdminOnly())
+	holidays.GET("", handler.ListHolidays)
+	holidays.POST("", handler.CreateHoliday)
+	holidays.DELETE("/:date", handler.DeleteHoliday)
 }
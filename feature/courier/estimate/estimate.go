@@ -0,0 +1,98 @@
+package estimate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+// Transit times used when the courier has no rule for a route.
+const (
+	defaultMinDays             = 2
+	defaultMaxDays             = 4
+	defaultSameProvinceMinDays = 1
+	defaultSameProvinceMaxDays = 2
+)
+
+// holidayLookahead bounds the holidays loaded for one estimate. It covers the longest
+// handling and transit times the API accepts, plus the weekends in between.
+const holidayLookahead = 90 * 24 * time.Hour
+
+type estimator struct {
+	repo domain.CourierRepository
+}
+
+func NewEstimator(repo domain.CourierRepository) domain.DeliveryEstimator {
+	return &estimator{repo: repo}
+}
+
+func (e *estimator) Estimate(ctx context.Context, req entity.DeliveryEstimateRequest) (*entity.DeliveryWindow, error) {
+	minDays, maxDays := uint32(defaultMinDays), uint32(defaultMaxDays)
+	if req.OriginProvinceID != nil && req.DestinationProvinceID != nil && *req.OriginProvinceID == *req.DestinationProvinceID {
+		minDays, maxDays = defaultSameProvinceMinDays, defaultSameProvinceMaxDays
+	}
+
+	transitTime, err := e.repo.FindTransitTime(ctx, req.CourierID, req.OriginProvinceID, req.DestinationProvinceID)
+	switch {
+	case err == nil:
+		minDays, maxDays = transitTime.MinDays, transitTime.MaxDays
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to get transit time: %w", err)
+	}
+
+	from := req.From.In(timeth.LoadLocation())
+	holidays, err := e.repo.ListHolidays(ctx, from, from.Add(holidayLookahead))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+
+	closed := make(map[string]bool, len(holidays))
+	for _, h := range holidays {
+		closed[h.Date.Format("2006-01-02")] = true
+	}
+
+	earliest, latest := Window(from, req.HandlingDays, minDays, maxDays, closed)
+	return &entity.DeliveryWindow{
+		From: earliest.Format("2006-01-02"),
+		To:   latest.Format("2006-01-02"),
+	}, nil
+}
+
+// Window returns the earliest and latest delivery dates for a parcel ordered at from.
+// The shop hands the parcel over handlingDays business days later and the courier
+// delivers minDays to maxDays business days after that. Weekends and the dates in
+// holidays (keyed YYYY-MM-DD) are not business days.
+func Window(from time.Time, handlingDays, minDays, maxDays uint32, holidays map[string]bool) (time.Time, time.Time) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	shipDay := addBusinessDays(day, handlingDays, holidays)
+	return addBusinessDays(shipDay, minDays, holidays), addBusinessDays(shipDay, maxDays, holidays)
+}
+
+// addBusinessDays moves day to the nth business day after it, first rolling forward to
+// a business day when day itself is not one.
+func addBusinessDays(day time.Time, n uint32, holidays map[string]bool) time.Time {
+	for !isBusinessDay(day, holidays) {
+		day = day.AddDate(0, 0, 1)
+	}
+	for n > 0 {
+		day = day.AddDate(0, 0, 1)
+		if isBusinessDay(day, holidays) {
+			n--
+		}
+	}
+	return day
+}
+
+func isBusinessDay(day time.Time, holidays map[string]bool) bool {
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	return !holidays[day.Format("2006-01-02")]
+}
@@ -0,0 +1,35 @@
+package estimate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow_SkipsWeekendsAndHolidays(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	// Friday 10 April 2026, right before the Songkran holidays.
+	orderedAt := time.Date(2026, time.April, 10, 15, 30, 0, 0, bangkok)
+	holidays := map[string]bool{
+		"2026-04-13": true,
+		"2026-04-14": true,
+		"2026-04-15": true,
+	}
+
+	earliest, latest := Window(orderedAt, 1, 2, 4, holidays)
+
+	// Handed over Thursday 16 April, delivered 2 to 4 business days later.
+	assert.Equal(t, "2026-04-20", earliest.Format("2006-01-02"))
+	assert.Equal(t, "2026-04-22", latest.Format("2006-01-02"))
+}
+
+func TestWindow_OrderOnWeekendStartsMonday(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	orderedAt := time.Date(2026, time.January, 3, 9, 0, 0, 0, bangkok)
+
+	earliest, latest := Window(orderedAt, 0, 1, 2, nil)
+
+	assert.Equal(t, "2026-01-06", earliest.Format("2006-01-02"))
+	assert.Equal(t, "2026-01-07", latest.Format("2006-01-02"))
+}
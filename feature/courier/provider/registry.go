@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"sync"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+)
+
+type registry struct {
+	providers map[string]domain.CourierProvider
+}
+
+// NewRegistry returns the courier integrations available to the API, keyed by provider code.
+func NewRegistry() domain.CourierProviderRegistry {
+	return &registry{
+		providers: map[string]domain.CourierProvider{
+			entity.CourierProviderSimulator: NewSimulator(),
+		},
+	}
+}
+
+var (
+	defaultRegistry     domain.CourierProviderRegistry
+	defaultRegistryOnce sync.Once
+)
+
+// Default returns the process-wide registry, so every usecase shares provider state
+// such as the simulator's cancelled bookings.
+func Default() domain.CourierProviderRegistry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+	})
+	return defaultRegistry
+}
+
+func (r *registry) Get(providerCode string) (domain.CourierProvider, bool) {
+	if providerCode == "" {
+		return nil, false
+	}
+	p, ok := r.providers[providerCode]
+	return p, ok
+}
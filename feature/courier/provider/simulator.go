@@ -0,0 +1,159 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
+)
+
+// simulatedStep is one point on the simulator's fixed delivery timeline.
+type simulatedStep struct {
+	after       time.Duration
+	status      string
+	description string
+	location    string
+}
+
+var simulatedTimeline = []simulatedStep{
+	{after: 2 * time.Hour, status: "PICKED_UP", description: "Parcel picked up from sender", location: "Origin branch"},
+	{after: 8 * time.Hour, status: "IN_TRANSIT", description: "Parcel arrived at sorting hub", location: "Bangkok hub"},
+	{after: 30 * time.Hour, status: "OUT_FOR_DELIVERY", description: "Out for delivery", location: "Destination branch"},
+	{after: 34 * time.Hour, status: "DELIVERED", description: "Delivered to recipient", location: "Recipient address"},
+}
+
+// simulator is a local courier that needs no external API. Tracking numbers encode the
+// booking time, and tracking states advance along simulatedTimeline as time passes, so
+// tracking survives restarts. COURIER_SIMULATOR_TIME_SCALE speeds the timeline up
+// (e.g. 60 makes one simulated hour pass every real minute).
+type simulator struct {
+	timeScale float64
+
+	mu        sync.Mutex
+	cancelled map[string]bool
+}
+
+func NewSimulator() domain.CourierProvider {
+	return &simulator{
+		timeScale: getSimulatorTimeScale(),
+		cancelled: make(map[string]bool),
+	}
+}
+
+func (s *simulator) BookPickup(ctx context.Context, req entity.CourierBookingRequest) (*entity.CourierBooking, error) {
+	now := timeth.Now()
+	trackingNo := fmt.Sprintf("SIM%010d%04d", now.Unix(), rand.IntN(10000))
+	pickupAt := now.Add(s.realDuration(simulatedTimeline[0].after))
+
+	return &entity.CourierBooking{
+		TrackingNo: trackingNo,
+		PickupAt:   &pickupAt,
+	}, nil
+}
+
+func (s *simulator) GetLabel(ctx context.Context, trackingNo string) (*entity.CourierLabel, error) {
+	if _, err := parseSimulatedBookedAt(trackingNo); err != nil {
+		return nil, err
+	}
+
+	return &entity.CourierLabel{
+		TrackingNo: trackingNo,
+		Barcode:    trackingNo,
+		SortCode:   "SIM-" + trackingNo[len(trackingNo)-2:],
+	}, nil
+}
+
+func (s *simulator) GetTracking(ctx context.Context, trackingNo string) ([]entity.CourierTrackingEvent, error) {
+	bookedAt, err := parseSimulatedBookedAt(trackingNo)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.isCancelled(trackingNo) {
+		return []entity.CourierTrackingEvent{}, nil
+	}
+
+	elapsed := timeth.Now().Sub(bookedAt)
+	events := make([]entity.CourierTrackingEvent, 0, len(simulatedTimeline))
+	for _, step := range simulatedTimeline {
+		at := s.realDuration(step.after)
+		if elapsed < at {
+			break
+		}
+		events = append(events, entity.CourierTrackingEvent{
+			EventID:     trackingNo + "-" + step.status,
+			Status:      step.status,
+			Description: step.description,
+			Location:    step.location,
+			OccurredAt:  bookedAt.Add(at),
+		})
+	}
+
+	return events, nil
+}
+
+// Cancel succeeds only before the parcel has been picked up.
+func (s *simulator) Cancel(ctx context.Context, trackingNo string) error {
+	bookedAt, err := parseSimulatedBookedAt(trackingNo)
+	if err != nil {
+		return err
+	}
+
+	if timeth.Now().Sub(bookedAt) >= s.realDuration(simulatedTimeline[0].after) {
+		return errmap.ErrCourierCannotCancel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelled[trackingNo] = true
+	return nil
+}
+
+func (s *simulator) isCancelled(trackingNo string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cancelled[trackingNo]
+}
+
+func (s *simulator) realDuration(simulated time.Duration) time.Duration {
+	return time.Duration(float64(simulated) / s.timeScale)
+}
+
+func parseSimulatedBookedAt(trackingNo string) (time.Time, error) {
+	if len(trackingNo) != 17 || trackingNo[:3] != "SIM" {
+		return time.Time{}, errmap.ErrCourierTrackingNotFound
+	}
+
+	unix, err := strconv.ParseInt(trackingNo[3:13], 10, 64)
+	if err != nil {
+		return time.Time{}, errmap.ErrCourierTrackingNotFound
+	}
+
+	return time.Unix(unix, 0).In(timeth.Now().Location()), nil
+}
+
+func getSimulatorTimeScale() float64 {
+	const defaultScale = 1.0
+
+	scaleStr := os.Getenv("COURIER_SIMULATOR_TIME_SCALE")
+	if scaleStr == "" {
+		return defaultScale
+	}
+
+	scale, err := strconv.ParseFloat(scaleStr, 64)
+	if err != nil || scale <= 0 {
+		log.Printf("Warning: Invalid COURIER_SIMULATOR_TIME_SCALE '%s'. Using default 1.", scaleStr)
+		return defaultScale
+	}
+
+	return scale
+}
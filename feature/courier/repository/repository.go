@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -30,3 +31,93 @@ func (r *courierRepository) ListAll(ctx context.Context) ([]*entity.Courier, err
 
 	return couriers, nil
 }
+
+func (r *courierRepository) GetByID(ctx context.Context, id uint32) (*entity.Courier, error) {
+	var courier entity.Courier
+	if err := r.db.WithContext(ctx).First(&courier, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &courier, nil
+}
+
+func (r *courierRepository) ListTransitTimes(ctx context.Context, courierID uint32) ([]*entity.CourierTransitTime, error) {
+	var transitTimes []*entity.CourierTransitTime
+
+	err := r.db.WithContext(ctx).
+		Where("courier_id = ?", courierID).
+		Order("origin_province_id ASC NULLS FIRST, destination_province_id ASC NULLS FIRST").
+		Find(&transitTimes).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return transitTimes, nil
+}
+
+func (r *courierRepository) ReplaceTransitTimes(ctx context.Context, courierID uint32, transitTimes []*entity.CourierTransitTime) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("courier_id = ?", courierID).Delete(&entity.CourierTransitTime{}).Error; err != nil {
+			return err
+		}
+		if len(transitTimes) == 0 {
+			return nil
+		}
+		return tx.Create(transitTimes).Error
+	})
+}
+
+// FindTransitTime returns the most specific transit time rule matching the route. A rule
+// for the destination province beats one for the origin province, which beats the
+// courier's catch-all rule.
+func (r *courierRepository) FindTransitTime(ctx context.Context, courierID uint32, originProvinceID *uint32, destinationProvinceID *uint32) (*entity.CourierTransitTime, error) {
+	q := r.db.WithContext(ctx).Where("courier_id = ?", courierID)
+
+	if originProvinceID != nil {
+		q = q.Where("(origin_province_id IS NULL OR origin_province_id = ?)", *originProvinceID)
+	} else {
+		q = q.Where("origin_province_id IS NULL")
+	}
+	if destinationProvinceID != nil {
+		q = q.Where("(destination_province_id IS NULL OR destination_province_id = ?)", *destinationProvinceID)
+	} else {
+		q = q.Where("destination_province_id IS NULL")
+	}
+
+	var transitTime entity.CourierTransitTime
+	err := q.Order("destination_province_id IS NULL, origin_province_id IS NULL").
+		First(&transitTime).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &transitTime, nil
+}
+
+func (r *courierRepository) ListHolidays(ctx context.Context, from time.Time, to time.Time) ([]*entity.Holiday, error) {
+	var holidays []*entity.Holiday
+
+	err := r.db.WithContext(ctx).
+		Where("date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC").
+		Find(&holidays).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return holidays, nil
+}
+
+func (r *courierRepository) CreateHoliday(ctx context.Context, holiday *entity.Holiday) error {
+	return r.db.WithContext(ctx).Create(holiday).Error
+}
+
+func (r *courierRepository) DeleteHoliday(ctx context.Context, date time.Time) error {
+	res := r.db.WithContext(ctx).Delete(&entity.Holiday{}, "date = ?", date.Format("2006-01-02"))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -2,17 +2,25 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
 )
 
 type courierUsecase struct {
-	repo domain.CourierRepository
+	repo      domain.CourierRepository
+	providers domain.CourierProviderRegistry
 }
 
-func NewCourierUsecase(repo domain.CourierRepository) domain.CourierUsecase {
-	return &courierUsecase{repo: repo}
+func NewCourierUsecase(repo domain.CourierRepository, providers domain.CourierProviderRegistry) domain.CourierUsecase {
+	return &courierUsecase{repo: repo, providers: providers}
 }
 
 func (u *courierUsecase) ListCouriers(ctx context.Context) ([]entity.CourierListResponse, error) {
@@ -23,13 +31,154 @@ func (u *courierUsecase) ListCouriers(ctx context.Context) ([]entity.CourierList
 
 	var response []entity.CourierListResponse
 	for _, c := range couriers {
+		_, supportsBooking := u.providers.Get(c.ProviderCode)
 		response = append(response, entity.CourierListResponse{
-			ID:       c.ID,
-			Name:     c.Name,
-			ImageURL: c.ImageURL,
-			Rate:     c.Rate,
+			ID:              c.ID,
+			Name:            c.Name,
+			ImageURL:        c.ImageURL,
+			Rate:            c.Rate,
+			SupportsBooking: supportsBooking,
 		})
 	}
 
 	return response, nil
 }
+
+func mapToTransitTimeResponse(t *entity.CourierTransitTime) entity.CourierTransitTimeResponse {
+	return entity.CourierTransitTimeResponse{
+		ID:                    t.ID,
+		CourierID:             t.CourierID,
+		OriginProvinceID:      t.OriginProvinceID,
+		DestinationProvinceID: t.DestinationProvinceID,
+		MinDays:               t.MinDays,
+		MaxDays:               t.MaxDays,
+	}
+}
+
+func (u *courierUsecase) ListTransitTimes(ctx context.Context, courierID uint32) ([]entity.CourierTransitTimeResponse, error) {
+	if _, err := u.repo.GetByID(ctx, courierID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrCourierNotFound
+		}
+		return nil, err
+	}
+
+	transitTimes, err := u.repo.ListTransitTimes(ctx, courierID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]entity.CourierTransitTimeResponse, 0, len(transitTimes))
+	for _, t := range transitTimes {
+		resp = append(resp, mapToTransitTimeResponse(t))
+	}
+
+	return resp, nil
+}
+
+// UpdateTransitTimes replaces every transit time rule of a courier. Routes without a
+// rule fall back to the estimator's defaults.
+func (u *courierUsecase) UpdateTransitTimes(ctx context.Context, courierID uint32, req entity.UpdateCourierTransitTimesRequest) ([]entity.CourierTransitTimeResponse, error) {
+	if _, err := u.repo.GetByID(ctx, courierID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrCourierNotFound
+		}
+		return nil, err
+	}
+
+	seen := make(map[[2]uint32]bool, len(req.TransitTimes))
+	transitTimes := make([]*entity.CourierTransitTime, 0, len(req.TransitTimes))
+	for _, t := range req.TransitTimes {
+		var route [2]uint32
+		if t.OriginProvinceID != nil {
+			route[0] = *t.OriginProvinceID
+		}
+		if t.DestinationProvinceID != nil {
+			route[1] = *t.DestinationProvinceID
+		}
+		if seen[route] {
+			return nil, errmap.ErrDuplicateTransitTime
+		}
+		seen[route] = true
+
+		transitTimes = append(transitTimes, &entity.CourierTransitTime{
+			CourierID:             courierID,
+			OriginProvinceID:      t.OriginProvinceID,
+			DestinationProvinceID: t.DestinationProvinceID,
+			MinDays:               t.MinDays,
+			MaxDays:               t.MaxDays,
+		})
+	}
+
+	if err := u.repo.ReplaceTransitTimes(ctx, courierID, transitTimes); err != nil {
+		return nil, fmt.Errorf("failed to update transit times: %w", err)
+	}
+
+	return u.ListTransitTimes(ctx, courierID)
+}
+
+// ListHolidays returns the holidays of a year, the current year by default.
+func (u *courierUsecase) ListHolidays(ctx context.Context, req entity.HolidayListRequest) ([]entity.HolidayResponse, error) {
+	year := req.Year
+	if year == 0 {
+		year = timeth.Now().Year()
+	}
+
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, timeth.LoadLocation())
+	holidays, err := u.repo.ListHolidays(ctx, from, from.AddDate(1, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]entity.HolidayResponse, 0, len(holidays))
+	for _, h := range holidays {
+		resp = append(resp, entity.HolidayResponse{
+			Date: h.Date.Format("2006-01-02"),
+			Name: h.Name,
+		})
+	}
+
+	return resp, nil
+}
+
+func (u *courierUsecase) CreateHoliday(ctx context.Context, req entity.CreateHolidayRequest) (*entity.HolidayResponse, error) {
+	date, err := time.ParseInLocation("2006-01-02", req.Date, timeth.LoadLocation())
+	if err != nil {
+		return nil, errmap.ErrInvalidHolidayDate
+	}
+
+	existing, err := u.repo.ListHolidays(ctx, date, date)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, errmap.ErrHolidayExists
+	}
+
+	holiday := &entity.Holiday{
+		Date:      date,
+		Name:      req.Name,
+		CreatedAt: timeth.Now(),
+	}
+	if err := u.repo.CreateHoliday(ctx, holiday); err != nil {
+		return nil, fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	return &entity.HolidayResponse{Date: req.Date, Name: holiday.Name}, nil
+}
+
+func (u *courierUsecase) DeleteHoliday(ctx context.Context, date string) error {
+	day, err := time.ParseInLocation("2006-01-02", date, timeth.LoadLocation())
+	if err != nil {
+		return errmap.ErrInvalidHolidayDate
+	}
+
+	if err := u.repo.DeleteHoliday(ctx, day); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrHolidayNotFound
+		}
+		return err
+	}
+
+	return nil
+}
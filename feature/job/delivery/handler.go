@@ -0,0 +1,176 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/response"
+	"ecommerce-go-api/middleware"
+)
+
+type JobHandler struct {
+	usecase domain.JobUsecase
+}
+
+func NewJobHandler(usecase domain.JobUsecase) *JobHandler {
+	return &JobHandler{usecase: usecase}
+}
+
+// ListJobs godoc
+//
+//	@Summary		List scheduled jobs (admin)
+//	@Description	Get every background job with its schedule, pause state, next run and last run
+//	@Tags			Job
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.JobResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/admin/jobs [get]
+func (h *JobHandler) ListJobs(c echo.Context) error {
+	jobs, err := h.usecase.ListJobs(c.Request().Context())
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", jobs)
+}
+
+// ListJobRuns godoc
+//
+//	@Summary		List job runs (admin)
+//	@Description	Get the run history of the background jobs, newest first
+//	@Tags			Job
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page	query		int		false	"Page number (default: 1)"
+//	@Param			perPage	query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			jobName	query		string	false	"Filter by job name"
+//	@Param			status	query		string	false	"Filter by status (RUNNING, SUCCEEDED, PARTIAL, FAILED)"
+//	@Success		200		{object}	entity.JobRunListResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/jobs/runs [get]
+func (h *JobHandler) ListJobRuns(c echo.Context) error {
+	var req entity.JobRunListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	runs, err := h.usecase.ListJobRuns(c.Request().Context(), req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", runs)
+}
+
+// TriggerJob godoc
+//
+//	@Summary		Run a job now (admin)
+//	@Description	Start a run of the job in the background, even while it is paused. Poll the run history for the result.
+//	@Tags			Job
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			name	path		string	true	"Job name"
+//	@Success		202		{object}	entity.JobRunResponse
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/jobs/{name}/run [post]
+func (h *JobHandler) TriggerJob(c echo.Context) error {
+	adminID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	run, err := h.usecase.TriggerJob(c.Request().Context(), adminID, c.Param("name"))
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrJobNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrJobNotFound.Error())
+		case errors.Is(err, errmap.ErrJobAlreadyRunning):
+			return response.Error(c, http.StatusConflict, errmap.ErrJobAlreadyRunning.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusAccepted, "job started", run)
+}
+
+// PauseJob godoc
+//
+//	@Summary		Pause a job (admin)
+//	@Description	Skip the job's scheduled runs until it is resumed. The pause survives restarts.
+//	@Tags			Job
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			name	path		string	true	"Job name"
+//	@Success		200		{object}	entity.JobResponse
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/jobs/{name}/pause [put]
+func (h *JobHandler) PauseJob(c echo.Context) error {
+	adminID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	job, err := h.usecase.PauseJob(c.Request().Context(), adminID, c.Param("name"))
+	if err != nil {
+		if errors.Is(err, errmap.ErrJobNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrJobNotFound.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "job paused", job)
+}
+
+// ResumeJob godoc
+//
+//	@Summary		Resume a job (admin)
+//	@Description	Run the job on its schedule again
+//	@Tags			Job
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			name	path		string	true	"Job name"
+//	@Success		200		{object}	entity.JobResponse
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/admin/jobs/{name}/resume [put]
+func (h *JobHandler) ResumeJob(c echo.Context) error {
+	adminID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	job, err := h.usecase.ResumeJob(c.Request().Context(), adminID, c.Param("name"))
+	if err != nil {
+		if errors.Is(err, errmap.ErrJobNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrJobNotFound.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "job resumed", job)
+}
@@ -0,0 +1,24 @@
+package delivery
+
+import (
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/feature/job/repository"
+	"ecommerce-go-api/feature/job/usecase"
+	"ecommerce-go-api/middleware"
+)
+
+func RegisterJobHandler(group *echo.Group, db *gorm.DB, scheduler domain.JobScheduler) {
+	repo := repository.NewJobRepository(db)
+	uc := usecase.NewJobUsecase(repo, scheduler)
+	handler := NewJobHandler(uc)
+
+	jobs := group.Group("/admin/jobs", middleware.JWTAuth(), middleware.AdminOnly())
+	jobs.GET("", handler.ListJobs)
+	jobs.GET("/runs", handler.ListJobRuns)
+	jobs.POST("/:name/run", handler.TriggerJob)
+	jobs.PUT("/:name/pause", handler.PauseJob)
+	jobs.PUT("/:name/resume", handler.ResumeJob)
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) domain.JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) CreateJobRun(ctx context.Context, run *entity.JobRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+// FinishJobRun stores the outcome of a run created by CreateJobRun.
+func (r *jobRepository) FinishJobRun(ctx context.Context, run *entity.JobRun) error {
+	return r.db.WithContext(ctx).Model(run).
+		Select("status", "finished_at", "processed", "succeeded", "failed", "error_samples", "error").
+		Updates(run).Error
+}
+
+func (r *jobRepository) ListJobRuns(ctx context.Context, req entity.JobRunListRequest) ([]*entity.JobRun, int64, error) {
+	var runs []*entity.JobRun
+	var total int64
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	query := r.db.WithContext(ctx).Model(&entity.JobRun{})
+	if req.JobName != "" {
+		query = query.Where("job_name = ?", req.JobName)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("started_at DESC").
+		Limit(int(perPage)).
+		Offset(int(offset)).
+		Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return runs, total, nil
+}
+
+func (r *jobRepository) GetLatestJobRun(ctx context.Context, name string) (*entity.JobRun, error) {
+	var run entity.JobRun
+	err := r.db.WithContext(ctx).
+		Where("job_name = ?", name).
+		Order("started_at DESC").
+		First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *jobRepository) ListJobStates(ctx context.Context) ([]*entity.JobState, error) {
+	var states []*entity.JobState
+	if err := r.db.WithContext(ctx).Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *jobRepository) IsJobPaused(ctx context.Context, name string) (bool, error) {
+	var states []*entity.JobState
+	if err := r.db.WithContext(ctx).Where("job_name = ?", name).Limit(1).Find(&states).Error; err != nil {
+		return false, err
+	}
+	return len(states) > 0 && states[0].Paused, nil
+}
+
+func (r *jobRepository) SetJobPaused(ctx context.Context, name string, paused bool, updatedBy *uuid.UUID) error {
+	state := entity.JobState{
+		JobName:   name,
+		Paused:    paused,
+		UpdatedBy: updatedBy,
+		UpdatedAt: timeth.Now(),
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "job_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"paused", "updated_by", "updated_at"}),
+	}).Create(&state).Error
+}
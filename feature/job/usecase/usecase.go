@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+)
+
+type jobUsecase struct {
+	repo      domain.JobRepository
+	scheduler domain.JobScheduler
+}
+
+func NewJobUsecase(repo domain.JobRepository, scheduler domain.JobScheduler) domain.JobUsecase {
+	return &jobUsecase{repo: repo, scheduler: scheduler}
+}
+
+func (u *jobUsecase) ListJobs(ctx context.Context) ([]entity.JobResponse, error) {
+	jobs := u.scheduler.Jobs()
+
+	response := make([]entity.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobResponse, err := u.jobResponse(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, *jobResponse)
+	}
+	return response, nil
+}
+
+func (u *jobUsecase) ListJobRuns(ctx context.Context, req entity.JobRunListRequest) (*entity.JobRunListResponse, error) {
+	runs, total, err := u.repo.ListJobRuns(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+
+	items := make([]*entity.JobRunResponse, 0, len(runs))
+	for _, run := range runs {
+		items = append(items, mapToJobRunResponse(run))
+	}
+	return &entity.JobRunListResponse{Items: items, Total: total}, nil
+}
+
+func (u *jobUsecase) TriggerJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobRunResponse, error) {
+	run, err := u.scheduler.Trigger(ctx, name, &adminID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToJobRunResponse(run), nil
+}
+
+func (u *jobUsecase) PauseJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobResponse, error) {
+	return u.setPaused(ctx, adminID, name, true)
+}
+
+func (u *jobUsecase) ResumeJob(ctx context.Context, adminID uuid.UUID, name string) (*entity.JobResponse, error) {
+	return u.setPaused(ctx, adminID, name, false)
+}
+
+func (u *jobUsecase) setPaused(ctx context.Context, adminID uuid.UUID, name string, paused bool) (*entity.JobResponse, error) {
+	if _, ok := u.scheduler.Job(name); !ok {
+		return nil, errmap.ErrJobNotFound
+	}
+
+	if err := u.repo.SetJobPaused(ctx, name, paused, &adminID); err != nil {
+		return nil, fmt.Errorf("failed to save job state: %w", err)
+	}
+	u.scheduler.SetPaused(name, paused)
+
+	job, _ := u.scheduler.Job(name)
+	return u.jobResponse(ctx, *job)
+}
+
+func (u *jobUsecase) jobResponse(ctx context.Context, job entity.JobInfo) (*entity.JobResponse, error) {
+	response := &entity.JobResponse{
+		Name:      job.Name,
+		Schedule:  job.Schedule,
+		Paused:    job.Paused,
+		Running:   job.Running,
+		NextRunAt: job.NextRunAt,
+	}
+
+	lastRun, err := u.repo.GetLatestJobRun(ctx, job.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get last run of %s: %w", job.Name, err)
+	}
+	if lastRun != nil {
+		response.LastRun = mapToJobRunResponse(lastRun)
+	}
+	return response, nil
+}
+
+func mapToJobRunResponse(run *entity.JobRun) *entity.JobRunResponse {
+	errorSamples := run.ErrorSamples
+	if errorSamples == nil {
+		errorSamples = []string{}
+	}
+	return &entity.JobRunResponse{
+		ID:           run.ID,
+		JobName:      run.JobName,
+		Trigger:      run.Trigger,
+		TriggeredBy:  run.TriggeredBy,
+		InstanceID:   run.InstanceID,
+		Status:       run.Status,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		Processed:    run.Processed,
+		Succeeded:    run.Succeeded,
+		Failed:       run.Failed,
+		ErrorSamples: errorSamples,
+		Error:        run.Error,
+	}
+}
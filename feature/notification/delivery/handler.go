@@ -0,0 +1,212 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/response"
+	"ecommerce-go-api/middleware"
+)
+
+type NotificationHandler struct {
+	usecase domain.NotificationUsecase
+}
+
+func NewNotificationHandler(usecase domain.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{usecase: usecase}
+}
+
+// ListNotifications godoc
+//
+//	@Summary		List notifications
+//	@Description	Get the user's in-app notifications, newest first, with the number still unread
+//	@Tags			Notification
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page	query		int		false	"Page number (default: 1)"
+//	@Param			perPage	query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			unread	query		bool	false	"Only unread notifications"
+//	@Success		200		{object}	entity.NotificationListResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/notifications [get]
+func (h *NotificationHandler) ListNotifications(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.NotificationListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	notifications, err := h.usecase.ListNotifications(c.Request().Context(), userID, req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", notifications)
+}
+
+// GetUnreadCount godoc
+//
+//	@Summary		Count unread notifications
+//	@Description	Get the number of the user's unread notifications, e.g. for a badge
+//	@Tags			Notification
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	entity.NotificationUnreadCountResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	count, err := h.usecase.GetUnreadCount(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", count)
+}
+
+// MarkNotificationRead godoc
+//
+//	@Summary		Mark a notification as read
+//	@Description	Mark one of the user's notifications as read. Notifications already read keep the time they were first read.
+//	@Tags			Notification
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			notificationId	path		string	true	"Notification ID"
+//	@Success		200				{object}	entity.Notification
+//	@Failure		400				{object}	response.ResponseError
+//	@Failure		401				{object}	response.ResponseError
+//	@Failure		404				{object}	response.ResponseError
+//	@Failure		500				{object}	response.ResponseError
+//	@Router			/api/notifications/{notificationId}/read [put]
+func (h *NotificationHandler) MarkNotificationRead(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	notificationID, err := uuid.Parse(c.Param("notificationId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	notification, err := h.usecase.MarkNotificationRead(c.Request().Context(), userID, notificationID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrNotificationNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrNotificationNotFound.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "notification marked as read", notification)
+}
+
+// MarkAllNotificationsRead godoc
+//
+//	@Summary		Mark all notifications as read
+//	@Description	Mark every unread notification of the user as read
+//	@Tags			Notification
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	entity.NotificationMarkAllReadResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/notifications/read-all [put]
+func (h *NotificationHandler) MarkAllNotificationsRead(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	result, err := h.usecase.MarkAllNotificationsRead(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "notifications marked as read", result)
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get notification preferences
+//	@Description	Get, for every event users are notified of, the channels it is available on and whether the user receives it by email and in the app
+//	@Tags			Notification
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.NotificationPreferenceResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	preferences, err := h.usecase.GetPreferences(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", preferences)
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Update notification preferences
+//	@Description	Turn the email or in-app channel of events on or off. Channels left out keep their current setting.
+//	@Tags			Notification
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		entity.UpdateNotificationPreferencesRequest	true	"Preferences to change"
+//	@Success		200		{array}		entity.NotificationPreferenceResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.UpdateNotificationPreferencesRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	preferences, err := h.usecase.UpdatePreferences(c.Request().Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrNotificationEventTypeUnsupported) {
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "notification preferences updated", preferences)
+}
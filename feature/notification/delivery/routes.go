@@ -0,0 +1,24 @@
+package delivery
+
+import (
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/feature/notification/repository"
+	"ecommerce-go-api/feature/notification/usecase"
+	"ecommerce-go-api/middleware"
+)
+
+func RegisterNotificationHandler(group *echo.Group, db *gorm.DB) {
+	repo := repository.NewNotificationRepository(db)
+	uc := usecase.NewNotificationUsecase(repo)
+	handler := NewNotificationHandler(uc)
+
+	notifications := group.Group("/notifications", middleware.JWTAuth())
+	notifications.GET("", handler.ListNotifications)
+	notifications.GET("/unread-count", handler.GetUnreadCount)
+	notifications.PUT("/read-all", handler.MarkAllNotificationsRead)
+	notifications.PUT("/:notificationId/read", handler.MarkNotificationRead)
+	notifications.GET("/preferences", handler.GetPreferences)
+	notifications.PUT("/preferences", handler.UpdatePreferences)
+}
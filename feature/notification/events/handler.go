@@ -0,0 +1,283 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/notify"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/timeth"
+)
+
+// The names the notification handlers are recorded under on events.
+const (
+	emailHandler = "notification.email"
+	inboxHandler = "notification.inbox"
+)
+
+type notificationHandler struct {
+	notificationRepo domain.NotificationRepository
+	orderRepo        domain.OrderRepository
+	userRepo         domain.UserRepository
+}
+
+// RegisterHandlers queues the emails sent for order, shipment and refund events, which
+// the sender in internal/notify sends, and adds the in-app notifications to inboxes.
+// Both skip users who turned the event's channel off.
+func RegisterHandlers(dispatcher *outbox.Dispatcher, notificationRepo domain.NotificationRepository, orderRepo domain.OrderRepository, userRepo domain.UserRepository) {
+	h := &notificationHandler{
+		notificationRepo: notificationRepo,
+		orderRepo:        orderRepo,
+		userRepo:         userRepo,
+	}
+	dispatcher.Subscribe(entity.EventOrderPlaced, emailHandler, h.orderPlaced)
+	dispatcher.Subscribe(entity.EventPaymentExpired, emailHandler, h.paymentExpired)
+	dispatcher.Subscribe(entity.EventShopOrderShipped, emailHandler, h.shopOrderShipped)
+	dispatcher.Subscribe(entity.EventShopOrderAutoCompleteReminder, emailHandler, h.autoCompleteReminder)
+	dispatcher.Subscribe(entity.EventRefundRequested, emailHandler, h.refundRequested)
+	dispatcher.Subscribe(entity.EventRefundApproved, emailHandler, h.refundChanged(entity.EmailTemplateRefundApproved))
+	dispatcher.Subscribe(entity.EventRefundCompleted, emailHandler, h.refundChanged(entity.EmailTemplateRefundCompleted))
+
+	dispatcher.Subscribe(entity.EventOrderPlaced, inboxHandler, h.inboxOrderPlaced)
+	dispatcher.Subscribe(entity.EventPaymentSubmitted, inboxHandler, h.inboxPaymentSubmitted)
+	dispatcher.Subscribe(entity.EventPaymentExpired, inboxHandler, h.inboxPaymentExpired)
+	dispatcher.Subscribe(entity.EventShopOrderShipped, inboxHandler, h.inboxBuyerShopOrder(entity.InboxTemplateShopOrderShipped))
+	dispatcher.Subscribe(entity.EventShopOrderDelivered, inboxHandler, h.inboxBuyerShopOrder(entity.InboxTemplateShopOrderDelivered))
+	dispatcher.Subscribe(entity.EventShopOrderAutoCompleteReminder, inboxHandler, h.inboxAutoCompleteReminder)
+	dispatcher.Subscribe(entity.EventShopOrderCompleted, inboxHandler, h.inboxShopOrderCompleted)
+	dispatcher.Subscribe(entity.EventShopOrderCancelled, inboxHandler, h.inboxShopOrderCancelled)
+	dispatcher.Subscribe(entity.EventRefundRequested, inboxHandler, h.inboxRefundRequested)
+	dispatcher.Subscribe(entity.EventRefundApproved, inboxHandler, h.inboxRefundChanged(entity.InboxTemplateRefundApproved))
+	dispatcher.Subscribe(entity.EventRefundCompleted, inboxHandler, h.inboxRefundChanged(entity.InboxTemplateRefundCompleted))
+}
+
+// shopOwner returns nil when the shop's owner no longer exists.
+func (h *notificationHandler) shopOwner(ctx context.Context, shop *entity.Shop) (*entity.User, error) {
+	owner, err := h.userRepo.GetByID(ctx, shop.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[OUTBOX] Skipping notification for shop %s: owner not found", shop.ID)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get shop owner: %w", err)
+	}
+	return owner, nil
+}
+
+// allows reports whether the user gets the event on channel.
+func (h *notificationHandler) allows(ctx context.Context, userID uuid.UUID, eventType, channel string) (bool, error) {
+	preference, err := h.notificationRepo.GetPreference(ctx, userID, eventType)
+	if err != nil {
+		return false, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	return preference.Allows(channel), nil
+}
+
+func displayName(u *entity.User) string {
+	return u.FirstName
+}
+
+func (h *notificationHandler) orderPlaced(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.OrderPlacedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := h.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	data := notify.OrderPlacedData{
+		Name:       displayName(&order.User),
+		GrandTotal: order.GrandTotal,
+	}
+	for _, shopOrder := range order.ShopOrders {
+		summary := notify.ShopOrderSummary{
+			OrderNumber: shopOrder.OrderNumber,
+			ShopName:    shopOrder.Shop.Name,
+			Shipping:    shopOrder.Shipping,
+			GrandTotal:  shopOrder.GrandTotal,
+		}
+		for _, item := range shopOrder.OrderItems {
+			summary.Items = append(summary.Items, notify.OrderItemSummary{
+				Name:     item.Product.Name,
+				Qty:      item.Qty,
+				Subtotal: item.Subtotal,
+			})
+		}
+		data.Orders = append(data.Orders, summary)
+	}
+
+	return h.queue(ctx, event, &order.User, entity.EmailTemplateOrderPlaced, data)
+}
+
+func (h *notificationHandler) paymentExpired(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.PaymentExpiredPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := h.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	data := notify.PaymentExpiredData{
+		Name:          displayName(&order.User),
+		TransactionID: payload.TransactionID,
+	}
+	for _, shopOrder := range order.ShopOrders {
+		data.OrderNumbers = append(data.OrderNumbers, shopOrder.OrderNumber)
+	}
+
+	return h.queue(ctx, event, &order.User, entity.EmailTemplatePaymentExpired, data)
+}
+
+func (h *notificationHandler) shopOrderShipped(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ShopOrderShippedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+	shipments, err := h.orderRepo.GetShipmentsByShopOrderID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shipments: %w", err)
+	}
+
+	data := notify.ShopOrderShippedData{
+		Name:                displayName(&shopOrder.Order.User),
+		OrderNumber:         shopOrder.OrderNumber,
+		ShopName:            shopOrder.Shop.Name,
+		EstimatedDeliveryTo: shopOrder.EstimatedDeliveryTo,
+	}
+	for _, shipment := range shipments {
+		data.Parcels = append(data.Parcels, notify.ParcelSummary{
+			Courier:    shipment.Courier.Name,
+			TrackingNo: shipment.TrackingNo,
+		})
+	}
+
+	return h.queue(ctx, event, &shopOrder.Order.User, entity.EmailTemplateShopOrderShipped, data)
+}
+
+// autoCompleteReminder tells the buyer their delivered order is about to be completed
+// for them.
+func (h *notificationHandler) autoCompleteReminder(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ShopOrderAutoCompleteReminderPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+
+	return h.queue(ctx, event, &shopOrder.Order.User, entity.EmailTemplateShopOrderAutoCompleteReminder, notify.AutoCompleteReminderData{
+		Name:        displayName(&shopOrder.Order.User),
+		OrderNumber: shopOrder.OrderNumber,
+		ShopName:    shopOrder.Shop.Name,
+		CompleteAt:  payload.CompleteAt,
+	})
+}
+
+// refundRequested tells the shop owner a buyer wants a refund.
+func (h *notificationHandler) refundRequested(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.RefundRequestedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+	owner, err := h.shopOwner(ctx, &shopOrder.Shop)
+	if err != nil || owner == nil {
+		return err
+	}
+
+	return h.queue(ctx, event, owner, entity.EmailTemplateRefundRequested, notify.RefundData{
+		Name:        displayName(owner),
+		OrderNumber: shopOrder.OrderNumber,
+		ShopName:    shopOrder.Shop.Name,
+		Amount:      payload.Amount,
+		ReasonCode:  payload.ReasonCode,
+		Reason:      payload.Reason,
+	})
+}
+
+// refundChanged tells the buyer their refund moved on.
+func (h *notificationHandler) refundChanged(template string) outbox.Handler {
+	return func(ctx context.Context, event *entity.OutboxEvent) error {
+		var payload entity.RefundPayload
+		if err := outbox.Decode(event, &payload); err != nil {
+			return err
+		}
+
+		shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get shop order: %w", err)
+		}
+
+		return h.queue(ctx, event, &shopOrder.Order.User, template, notify.RefundData{
+			Name:        displayName(&shopOrder.Order.User),
+			OrderNumber: shopOrder.OrderNumber,
+			ShopName:    shopOrder.Shop.Name,
+			Amount:      payload.Amount,
+		})
+	}
+}
+
+// queue renders the email in the recipient's language and queues it. An email already
+// queued for the same event is kept, so a retried event does not send it twice.
+func (h *notificationHandler) queue(ctx context.Context, event *entity.OutboxEvent, recipient *entity.User, template string, data any) error {
+	if recipient.Email == "" {
+		// Deleted accounts are not preloaded.
+		log.Printf("[OUTBOX] Skipping %s email for event %s: recipient has no email", template, event.ID)
+		return nil
+	}
+
+	allowed, err := h.allows(ctx, recipient.ID, event.EventType, entity.NotificationChannelEmail)
+	if err != nil || !allowed {
+		return err
+	}
+
+	email, err := notify.Render(template, recipient.Language, recipient.Email, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", template, err)
+	}
+
+	userID := recipient.ID
+	language := recipient.Language
+	if language == "" {
+		language = entity.LanguageThai
+	}
+	now := timeth.Now()
+
+	return h.notificationRepo.QueueEmails(ctx, []*entity.EmailMessage{{
+		UserID:        &userID,
+		Recipient:     email.To,
+		Template:      template,
+		Language:      language,
+		Subject:       email.Subject,
+		HTMLBody:      email.HTML,
+		TextBody:      email.Text,
+		DedupeKey:     fmt.Sprintf("%s:%s:%s", event.ID, template, recipient.ID),
+		Status:        entity.EmailStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}})
+}
@@ -0,0 +1,274 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/notify"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/timeth"
+)
+
+func shopOrderTarget(shopOrder *entity.ShopOrder) entity.NotificationData {
+	return entity.NotificationData{
+		OrderID:     &shopOrder.OrderID,
+		ShopOrderID: &shopOrder.ID,
+		ShopID:      &shopOrder.ShopID,
+		OrderNumber: shopOrder.OrderNumber,
+	}
+}
+
+// inboxOrderPlaced tells the buyer the order was placed and each shop owner their part
+// of it.
+func (h *notificationHandler) inboxOrderPlaced(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.OrderPlacedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := h.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	data := notify.InboxData{Amount: order.GrandTotal}
+	for _, shopOrder := range order.ShopOrders {
+		data.OrderNumbers = append(data.OrderNumbers, shopOrder.OrderNumber)
+	}
+	if err := h.notify(ctx, event, &order.User, entity.InboxTemplateOrderPlaced, data, entity.NotificationData{OrderID: &order.ID}); err != nil {
+		return err
+	}
+
+	for i := range order.ShopOrders {
+		shopOrder := &order.ShopOrders[i]
+		owner, err := h.shopOwner(ctx, &shopOrder.Shop)
+		if err != nil {
+			return err
+		}
+		if owner == nil {
+			continue
+		}
+
+		err = h.notify(ctx, event, owner, entity.InboxTemplateNewOrder, notify.InboxData{
+			OrderNumber: shopOrder.OrderNumber,
+			ShopName:    shopOrder.Shop.Name,
+			Amount:      shopOrder.GrandTotal,
+		}, shopOrderTarget(shopOrder))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inboxPaymentSubmitted tells each shop owner in the order that it is ready to ship.
+func (h *notificationHandler) inboxPaymentSubmitted(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.PaymentSubmittedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := h.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	for i := range order.ShopOrders {
+		shopOrder := &order.ShopOrders[i]
+		owner, err := h.shopOwner(ctx, &shopOrder.Shop)
+		if err != nil {
+			return err
+		}
+		if owner == nil {
+			continue
+		}
+
+		err = h.notify(ctx, event, owner, entity.InboxTemplatePaymentReceived, notify.InboxData{
+			OrderNumber: shopOrder.OrderNumber,
+			ShopName:    shopOrder.Shop.Name,
+			Amount:      shopOrder.GrandTotal,
+		}, shopOrderTarget(shopOrder))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *notificationHandler) inboxPaymentExpired(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.PaymentExpiredPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := h.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	data := notify.InboxData{Amount: order.GrandTotal}
+	for _, shopOrder := range order.ShopOrders {
+		data.OrderNumbers = append(data.OrderNumbers, shopOrder.OrderNumber)
+	}
+	return h.notify(ctx, event, &order.User, entity.InboxTemplatePaymentExpired, data, entity.NotificationData{OrderID: &order.ID})
+}
+
+// inboxBuyerShopOrder tells the buyer their shop order moved on.
+func (h *notificationHandler) inboxBuyerShopOrder(template string) outbox.Handler {
+	return func(ctx context.Context, event *entity.OutboxEvent) error {
+		var payload entity.ShopOrderPayload
+		if err := outbox.Decode(event, &payload); err != nil {
+			return err
+		}
+
+		shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get shop order: %w", err)
+		}
+
+		return h.notify(ctx, event, &shopOrder.Order.User, template, notify.InboxData{
+			OrderNumber: shopOrder.OrderNumber,
+			ShopName:    shopOrder.Shop.Name,
+		}, shopOrderTarget(shopOrder))
+	}
+}
+
+func (h *notificationHandler) inboxShopOrderCancelled(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ShopOrderCancelledPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+
+	return h.notify(ctx, event, &shopOrder.Order.User, entity.InboxTemplateShopOrderCancelled, notify.InboxData{
+		OrderNumber: shopOrder.OrderNumber,
+		ShopName:    shopOrder.Shop.Name,
+		Reason:      payload.Reason,
+	}, shopOrderTarget(shopOrder))
+}
+
+func (h *notificationHandler) inboxAutoCompleteReminder(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ShopOrderAutoCompleteReminderPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+
+	return h.notify(ctx, event, &shopOrder.Order.User, entity.InboxTemplateShopOrderAutoCompleteReminder, notify.InboxData{
+		OrderNumber: shopOrder.OrderNumber,
+		ShopName:    shopOrder.Shop.Name,
+		DueAt:       payload.CompleteAt,
+	}, shopOrderTarget(shopOrder))
+}
+
+// inboxShopOrderCompleted tells the shop owner the buyer received the order.
+func (h *notificationHandler) inboxShopOrderCompleted(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ShopOrderCompletedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+	owner, err := h.shopOwner(ctx, &shopOrder.Shop)
+	if err != nil || owner == nil {
+		return err
+	}
+
+	return h.notify(ctx, event, owner, entity.InboxTemplateShopOrderCompleted, notify.InboxData{
+		OrderNumber: shopOrder.OrderNumber,
+		ShopName:    shopOrder.Shop.Name,
+	}, shopOrderTarget(shopOrder))
+}
+
+func (h *notificationHandler) inboxRefundRequested(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.RefundRequestedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop order: %w", err)
+	}
+	owner, err := h.shopOwner(ctx, &shopOrder.Shop)
+	if err != nil || owner == nil {
+		return err
+	}
+
+	target := shopOrderTarget(shopOrder)
+	target.RefundID = &payload.RefundID
+	return h.notify(ctx, event, owner, entity.InboxTemplateRefundRequested, notify.InboxData{
+		OrderNumber: shopOrder.OrderNumber,
+		ShopName:    shopOrder.Shop.Name,
+		Amount:      payload.Amount,
+		Reason:      payload.Reason,
+	}, target)
+}
+
+func (h *notificationHandler) inboxRefundChanged(template string) outbox.Handler {
+	return func(ctx context.Context, event *entity.OutboxEvent) error {
+		var payload entity.RefundPayload
+		if err := outbox.Decode(event, &payload); err != nil {
+			return err
+		}
+
+		shopOrder, err := h.orderRepo.GetShopOrderByID(ctx, payload.ShopOrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get shop order: %w", err)
+		}
+
+		target := shopOrderTarget(shopOrder)
+		target.RefundID = &payload.RefundID
+		return h.notify(ctx, event, &shopOrder.Order.User, template, notify.InboxData{
+			OrderNumber: shopOrder.OrderNumber,
+			ShopName:    shopOrder.Shop.Name,
+			Amount:      payload.Amount,
+		}, target)
+	}
+}
+
+// notify adds a notification to the recipient's inbox, rendered in their language. A
+// notification already created for the same event is kept, so a retried event does not
+// add it twice.
+func (h *notificationHandler) notify(ctx context.Context, event *entity.OutboxEvent, recipient *entity.User, template string, data notify.InboxData, target entity.NotificationData) error {
+	if recipient.ID == uuid.Nil {
+		// Deleted accounts are not preloaded.
+		log.Printf("[OUTBOX] Skipping %s notification for event %s: recipient not found", template, event.ID)
+		return nil
+	}
+
+	allowed, err := h.allows(ctx, recipient.ID, event.EventType, entity.NotificationChannelInApp)
+	if err != nil || !allowed {
+		return err
+	}
+
+	title, body, err := notify.RenderInbox(template, recipient.Language, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s notification: %w", template, err)
+	}
+
+	return h.notificationRepo.CreateNotifications(ctx, []*entity.Notification{{
+		UserID:    recipient.ID,
+		EventType: event.EventType,
+		Title:     title,
+		Body:      body,
+		Data:      target,
+		DedupeKey: fmt.Sprintf("%s:%s:%s", event.ID, template, recipient.ID),
+		CreatedAt: timeth.Now(),
+	}})
+}
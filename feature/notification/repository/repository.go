@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) domain.NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) QueueEmails(ctx context.Context, emails []*entity.EmailMessage) error {
+	if len(emails) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "dedupe_key"}},
+			DoNothing: true,
+		}).
+		Create(emails).Error
+}
+
+// ClaimDueEmails leases a batch of due emails to the claimant, oldest first. Emails
+// locked or leased by another instance are skipped.
+func (r *notificationRepository) ClaimDueEmails(ctx context.Context, claim entity.JobClaim) ([]*entity.EmailMessage, error) {
+	now := timeth.Now()
+	due := r.db.WithContext(ctx).Model(&entity.EmailMessage{}).
+		Select("id").
+		Where("status = ?", entity.EmailStatusPending).
+		Where("next_attempt_at <= ?", now).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Order("created_at").
+		Limit(claim.Limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE email_messages SET claimed_by = ?, claimed_until = ? WHERE id IN (?) RETURNING id",
+			claim.Claimant, now.Add(claim.Lease), due).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var emails []*entity.EmailMessage
+	err = r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Order("created_at").
+		Find(&emails).Error
+	return emails, err
+}
+
+func (r *notificationRepository) FinishEmail(ctx context.Context, email *entity.EmailMessage) error {
+	email.ClaimedBy = nil
+	email.ClaimedUntil = nil
+	return r.db.WithContext(ctx).Model(email).
+		Select("status", "attempts", "next_attempt_at", "last_error", "sent_at", "claimed_by", "claimed_until").
+		Updates(email).Error
+}
+
+func (r *notificationRepository) CreateNotifications(ctx context.Context, notifications []*entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "dedupe_key"}},
+			DoNothing: true,
+		}).
+		Create(notifications).Error
+}
+
+func (r *notificationRepository) GetNotificationByID(ctx context.Context, id uuid.UUID) (*entity.Notification, error) {
+	var notification entity.Notification
+	if err := r.db.WithContext(ctx).First(&notification, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) ListNotifications(ctx context.Context, userID uuid.UUID, req entity.NotificationListRequest) ([]*entity.Notification, int64, error) {
+	var notifications []*entity.Notification
+	var total int64
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	query := r.db.WithContext(ctx).Model(&entity.Notification{}).Where("user_id = ?", userID)
+	if req.Unread {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(int(perPage)).
+		Offset(int(offset)).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnreadNotifications(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkNotificationsRead(ctx context.Context, userID uuid.UUID, ids ...uuid.UUID) (int64, error) {
+	query := r.db.WithContext(ctx).Model(&entity.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+
+	result := query.Update("read_at", timeth.Now())
+	return result.RowsAffected, result.Error
+}
+
+func (r *notificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationPreference, error) {
+	var preferences []*entity.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&preferences).Error
+	return preferences, err
+}
+
+func (r *notificationRepository) GetPreference(ctx context.Context, userID uuid.UUID, eventType string) (*entity.NotificationPreference, error) {
+	var preferences []*entity.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND event_type = ?", userID, eventType).
+		Limit(1).
+		Find(&preferences).Error
+	if err != nil || len(preferences) == 0 {
+		return nil, err
+	}
+	return preferences[0], nil
+}
+
+func (r *notificationRepository) UpsertPreferences(ctx context.Context, preferences []*entity.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"email", "in_app", "updated_at"}),
+		}).
+		Create(preferences).Error
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type notificationUsecase struct {
+	notificationRepo domain.NotificationRepository
+}
+
+func NewNotificationUsecase(notificationRepo domain.NotificationRepository) domain.NotificationUsecase {
+	return &notificationUsecase{notificationRepo: notificationRepo}
+}
+
+func (u *notificationUsecase) ListNotifications(ctx context.Context, userID uuid.UUID, req entity.NotificationListRequest) (*entity.NotificationListResponse, error) {
+	notifications, total, err := u.notificationRepo.ListNotifications(ctx, userID, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	unread, err := u.notificationRepo.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return &entity.NotificationListResponse{
+		Items:       notifications,
+		Total:       total,
+		UnreadCount: unread,
+	}, nil
+}
+
+func (u *notificationUsecase) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*entity.NotificationUnreadCountResponse, error) {
+	unread, err := u.notificationRepo.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return &entity.NotificationUnreadCountResponse{UnreadCount: unread}, nil
+}
+
+func (u *notificationUsecase) MarkNotificationRead(ctx context.Context, userID uuid.UUID, notificationID uuid.UUID) (*entity.Notification, error) {
+	notification, err := u.notificationRepo.GetNotificationByID(ctx, notificationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if notification.UserID != userID {
+		return nil, errmap.ErrNotificationNotFound
+	}
+
+	// Reading a notification again keeps the time it was first read.
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	if _, err := u.notificationRepo.MarkNotificationsRead(ctx, userID, notificationID); err != nil {
+		return nil, fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	now := timeth.Now()
+	notification.ReadAt = &now
+
+	return notification, nil
+}
+
+func (u *notificationUsecase) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (*entity.NotificationMarkAllReadResponse, error) {
+	updated, err := u.notificationRepo.MarkNotificationsRead(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return &entity.NotificationMarkAllReadResponse{Updated: updated}, nil
+}
+
+// GetPreferences returns the user's channels for every notification event, filling in
+// the defaults for events they have not set.
+func (u *notificationUsecase) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*entity.NotificationPreferenceResponse, error) {
+	preferences, err := u.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return mapToPreferenceResponses(preferences), nil
+}
+
+func (u *notificationUsecase) UpdatePreferences(ctx context.Context, userID uuid.UUID, req entity.UpdateNotificationPreferencesRequest) ([]*entity.NotificationPreferenceResponse, error) {
+	for _, p := range req.Preferences {
+		if !isNotificationEvent(p.EventType) {
+			return nil, fmt.Errorf("%w: %s", errmap.ErrNotificationEventTypeUnsupported, p.EventType)
+		}
+	}
+
+	current, err := u.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+
+	now := timeth.Now()
+	byEventType := make(map[string]*entity.NotificationPreference, len(req.Preferences))
+	for _, p := range req.Preferences {
+		preference, ok := byEventType[p.EventType]
+		if !ok {
+			preference = findPreference(current, p.EventType)
+			if preference == nil {
+				preference = &entity.NotificationPreference{UserID: userID, EventType: p.EventType, Email: true, InApp: true}
+			}
+			byEventType[p.EventType] = preference
+		}
+
+		if p.Email != nil {
+			preference.Email = *p.Email
+		}
+		if p.InApp != nil {
+			preference.InApp = *p.InApp
+		}
+		preference.UpdatedAt = now
+	}
+
+	changed := make([]*entity.NotificationPreference, 0, len(byEventType))
+	for _, preference := range byEventType {
+		changed = append(changed, preference)
+	}
+	if err := u.notificationRepo.UpsertPreferences(ctx, changed); err != nil {
+		return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+
+	for _, preference := range changed {
+		if findPreference(current, preference.EventType) == nil {
+			current = append(current, preference)
+		}
+	}
+	return mapToPreferenceResponses(current), nil
+}
+
+func isNotificationEvent(eventType string) bool {
+	return slices.ContainsFunc(entity.NotificationEvents, func(e entity.NotificationEvent) bool {
+		return e.EventType == eventType
+	})
+}
+
+func findPreference(preferences []*entity.NotificationPreference, eventType string) *entity.NotificationPreference {
+	for _, p := range preferences {
+		if p.EventType == eventType {
+			return p
+		}
+	}
+	return nil
+}
+
+func mapToPreferenceResponses(preferences []*entity.NotificationPreference) []*entity.NotificationPreferenceResponse {
+	responses := make([]*entity.NotificationPreferenceResponse, 0, len(entity.NotificationEvents))
+	for _, event := range entity.NotificationEvents {
+		preference := findPreference(preferences, event.EventType)
+		responses = append(responses, &entity.NotificationPreferenceResponse{
+			EventType: event.EventType,
+			Channels:  event.Channels,
+			Email:     preference.Allows(entity.NotificationChannelEmail),
+			InApp:     preference.Allows(entity.NotificationChannelInApp),
+		})
+	}
+	return responses
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func findResponse(t *testing.T, responses []*entity.NotificationPreferenceResponse, eventType string) *entity.NotificationPreferenceResponse {
+	for _, r := range responses {
+		if r.EventType == eventType {
+			return r
+		}
+	}
+	t.Fatalf("no preference for %s", eventType)
+	return nil
+}
+
+func TestUpdatePreferences_MergesWithCurrentAndDefaults(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUsecase(repo)
+	userID := uuid.New()
+
+	repo.EXPECT().GetPreferences(gomock.Any(), userID).Return([]*entity.NotificationPreference{
+		{UserID: userID, EventType: entity.EventShopOrderShipped, Email: false, InApp: true},
+	}, nil)
+	repo.EXPECT().UpsertPreferences(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, preferences []*entity.NotificationPreference) error {
+		assert.Len(t, preferences, 2)
+		for _, p := range preferences {
+			switch p.EventType {
+			case entity.EventShopOrderShipped:
+				// Email was off already and is left out of the request.
+				assert.False(t, p.Email)
+				assert.False(t, p.InApp)
+			case entity.EventOrderPlaced:
+				assert.False(t, p.Email)
+				assert.True(t, p.InApp)
+			default:
+				t.Errorf("unexpected preference for %s", p.EventType)
+			}
+		}
+		return nil
+	})
+
+	responses, err := uc.UpdatePreferences(context.Background(), userID, entity.UpdateNotificationPreferencesRequest{
+		Preferences: []entity.NotificationPreferenceRequest{
+			{EventType: entity.EventShopOrderShipped, InApp: boolPtr(false)},
+			{EventType: entity.EventOrderPlaced, Email: boolPtr(false)},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, responses, len(entity.NotificationEvents))
+	shipped := findResponse(t, responses, entity.EventShopOrderShipped)
+	assert.False(t, shipped.Email)
+	assert.False(t, shipped.InApp)
+	placed := findResponse(t, responses, entity.EventOrderPlaced)
+	assert.False(t, placed.Email)
+	assert.True(t, placed.InApp)
+	refunded := findResponse(t, responses, entity.EventRefundCompleted)
+	assert.True(t, refunded.Email)
+	assert.True(t, refunded.InApp)
+}
+
+func TestUpdatePreferences_UnsupportedEventType(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUsecase(repo)
+
+	_, err := uc.UpdatePreferences(context.Background(), uuid.New(), entity.UpdateNotificationPreferencesRequest{
+		Preferences: []entity.NotificationPreferenceRequest{{EventType: entity.EventParcelReturned, Email: boolPtr(false)}},
+	})
+
+	assert.ErrorIs(t, err, errmap.ErrNotificationEventTypeUnsupported)
+}
+
+func TestMarkNotificationRead_OtherUsersNotificationIsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockNotificationRepository(ctrl)
+	uc := NewNotificationUsecase(repo)
+	notificationID := uuid.New()
+
+	repo.EXPECT().GetNotificationByID(gomock.Any(), notificationID).Return(&entity.Notification{ID: notificationID, UserID: uuid.New()}, nil)
+
+	_, err := uc.MarkNotificationRead(context.Background(), uuid.New(), notificationID)
+
+	assert.ErrorIs(t, err, errmap.ErrNotificationNotFound)
+}
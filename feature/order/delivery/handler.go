@@ -2,19 +2,28 @@ package delivery
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 
+	"ecommerce-go-api/config"
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/feature/courier/estimate"
+	"ecommerce-go-api/feature/courier/provider"
+	courierRepo "ecommerce-go-api/feature/courier/repository"
 	"ecommerce-go-api/feature/order/repository"
 	usecase "ecommerce-go-api/feature/order/usecase"
+	outboxRepo "ecommerce-go-api/feature/outbox/repository"
 	productRepo "ecommerce-go-api/feature/product/repository"
 	shopRepo "ecommerce-go-api/feature/shop/repository"
 	userRepo "ecommerce-go-api/feature/user/repository"
+	"ecommerce-go-api/internal/dbtx"
 	"ecommerce-go-api/internal/errmap"
 	"ecommerce-go-api/internal/response"
 	"ecommerce-go-api/middleware"
@@ -255,6 +264,7 @@ func (h *OrderHandler) GetOrder(c echo.Context) error {
 //	@Success		201		{object}	entity.OrderResponse
 //	@Failure		400		{object}	response.ResponseError
 //	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
 //	@Failure		500		{object}	response.ResponseError
 //	@Router			/api/orders [post]
 func (h *OrderHandler) CreateOrder(c echo.Context) error {
@@ -284,6 +294,8 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 			return response.Error(c, http.StatusBadRequest, errmap.ErrCartIsEmpty.Error())
 		case errmap.ErrAddressIDRequired:
 			return response.Error(c, http.StatusBadRequest, errmap.ErrAddressIDRequired.Error())
+		case errmap.ErrEmailNotVerified:
+			return response.Error(c, http.StatusForbidden, errmap.ErrEmailNotVerified.Error())
 		default:
 			return response.Error(c, http.StatusInternalServerError, err.Error())
 		}
@@ -410,7 +422,7 @@ func (h *OrderHandler) UpdateShopOrderStatus(c echo.Context) error {
 		switch {
 		case errors.Is(err, errmap.ErrInvalidRequest):
 			return response.Error(c, http.StatusBadRequest, err.Error())
-		case errors.Is(err, errmap.ErrCannotCancelOrder):
+		case errors.Is(err, errmap.ErrCannotCancelOrder), errors.Is(err, errmap.ErrOrderNotFullyShipped):
 			return response.Error(c, http.StatusBadRequest, err.Error())
 		case errors.Is(err, errmap.ErrForbidden):
 			return response.Error(c, http.StatusForbidden, err.Error())
@@ -479,7 +491,7 @@ func (h *OrderHandler) CancelShopOrder(c echo.Context) error {
 // AddShipment godoc
 //
 //	@Summary		Add shipment to order
-//	@Description	Add shipment details to a order for shop owner
+//	@Description	Ship a parcel with some or all unshipped items of a order for shop owner. Omit items to ship everything left, and omit trackingNo to book a pickup with a courier that supports booking.
 //	@Tags			Order
 //	@Security		BearerAuth
 //	@Accept			json
@@ -490,7 +502,9 @@ func (h *OrderHandler) CancelShopOrder(c echo.Context) error {
 //	@Failure		400			{object}	response.ResponseError
 //	@Failure		401			{object}	response.ResponseError
 //	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
 //	@Failure		500			{object}	response.ResponseError
+//	@Failure		502			{object}	response.ResponseError
 //	@Router			/api/shop/orders/{shopOrderId}/shipping [post]
 func (h *OrderHandler) AddShipment(c echo.Context) error {
 	userID, err := middleware.GetUserID(c)
@@ -517,6 +531,14 @@ func (h *OrderHandler) AddShipment(c echo.Context) error {
 		switch {
 		case errors.Is(err, errmap.ErrForbidden):
 			return response.Error(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, errmap.ErrAllItemsShipped),
+			errors.Is(err, errmap.ErrInvalidShipmentItems),
+			errors.Is(err, errmap.ErrTrackingNoRequired):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrCourierNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, errmap.ErrCourierBookingFailed):
+			return response.Error(c, http.StatusBadGateway, err.Error())
 		case errors.Is(err, gorm.ErrRecordNotFound):
 			return response.Error(c, http.StatusNotFound, errmap.ErrOrderNotFound.Error())
 		default:
@@ -530,12 +552,12 @@ func (h *OrderHandler) AddShipment(c echo.Context) error {
 // GetShipmentTracking godoc
 //
 //	@Summary		Get shipment tracking details
-//	@Description	Get tracking information for a specific order (user only)
+//	@Description	Get tracking information for every parcel of a specific order (user only)
 //	@Tags			Order
 //	@Security		BearerAuth
 //	@Produce		json
 //	@Param			shopOrderId	path		string	true	"Shop Order ID"
-//	@Success		200			{object}	entity.ShipmentResponse
+//	@Success		200			{object}	entity.ShopOrderTrackingResponse
 //	@Failure		400			{object}	response.ResponseError
 //	@Failure		401			{object}	response.ResponseError
 //	@Failure		403			{object}	response.ResponseError
@@ -553,30 +575,30 @@ func (h *OrderHandler) GetShipmentTracking(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidOrderID.Error())
 	}
 
-	shipment, err := h.usecase.GetShipmentTracking(c.Request().Context(), userID, orderID)
+	tracking, err := h.usecase.GetShipmentTracking(c.Request().Context(), userID, orderID)
 	if err != nil {
 		switch {
 		case errors.Is(err, errmap.ErrForbidden):
 			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
-		case errors.Is(err, gorm.ErrRecordNotFound):
-			return response.Error(c, http.StatusNotFound, "shipment not found")
+		case errors.Is(err, errmap.ErrShipmentNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrShipmentNotFound.Error())
 		default:
 			return response.Error(c, http.StatusInternalServerError, err.Error())
 		}
 	}
 
-	return response.Success(c, http.StatusOK, "ok", shipment)
+	return response.Success(c, http.StatusOK, "ok", tracking)
 }
 
 // GetShopShipmentTracking godoc
 //
 //	@Summary		Get shipment tracking details (shop)
-//	@Description	Get tracking information for a specific shop order (shop owner only)
+//	@Description	Get tracking information for every parcel of a specific shop order (shop owner only)
 //	@Tags			Order
 //	@Security		BearerAuth
 //	@Produce		json
 //	@Param			shopOrderId	path		string	true	"Shop Order ID"
-//	@Success		200			{object}	entity.ShipmentResponse
+//	@Success		200			{object}	entity.ShopOrderTrackingResponse
 //	@Failure		400			{object}	response.ResponseError
 //	@Failure		401			{object}	response.ResponseError
 //	@Failure		403			{object}	response.ResponseError
@@ -594,19 +616,19 @@ func (h *OrderHandler) GetShopShipmentTracking(c echo.Context) error {
 		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidOrderID.Error())
 	}
 
-	shipment, err := h.usecase.GetShopShipmentTracking(c.Request().Context(), userID, orderID)
+	tracking, err := h.usecase.GetShopShipmentTracking(c.Request().Context(), userID, orderID)
 	if err != nil {
 		switch {
 		case errors.Is(err, errmap.ErrForbidden):
 			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
-		case errors.Is(err, gorm.ErrRecordNotFound):
-			return response.Error(c, http.StatusNotFound, "shipment not found")
+		case errors.Is(err, errmap.ErrShipmentNotFound), errors.Is(err, gorm.ErrRecordNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrShipmentNotFound.Error())
 		default:
 			return response.Error(c, http.StatusInternalServerError, err.Error())
 		}
 	}
 
-	return response.Success(c, http.StatusOK, "ok", shipment)
+	return response.Success(c, http.StatusOK, "ok", tracking)
 }
 
 // ApproveOrder godoc
@@ -649,12 +671,211 @@ func (h *OrderHandler) ApproveOrder(c echo.Context) error {
 	return response.Success(c, http.StatusOK, "order approved successfully", nil)
 }
 
+// ScheduleDeliveryReattempt godoc
+//
+//	@Summary		Schedule a delivery reattempt
+//	@Description	After the courier fails to deliver a parcel, the buyer picks a redelivery date within the next 7 days and may correct the delivery address with one of their saved addresses (user only)
+//	@Tags			Order
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			shopOrderId	path		string							true	"Shop Order ID"
+//	@Param			shipmentId	path		string							true	"Shipment ID"
+//	@Param			body		body		entity.DeliveryReattemptRequest	true	"Reattempt payload"
+//	@Success		200			{object}	entity.ShipmentResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/orders/{shopOrderId}/shipments/{shipmentId}/reattempt [put]
+func (h *OrderHandler) ScheduleDeliveryReattempt(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	orderID, err := uuid.Parse(c.Param("shopOrderId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidOrderID.Error())
+	}
+
+	shipmentID, err := uuid.Parse(c.Param("shipmentId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidShipmentID.Error())
+	}
+
+	var req entity.DeliveryReattemptRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	shipment, err := h.usecase.ScheduleDeliveryReattempt(c.Request().Context(), userID, orderID, shipmentID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		case errors.Is(err, errmap.ErrReattemptNotAllowed),
+			errors.Is(err, errmap.ErrInvalidReattemptDate):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrAddressNotFound),
+			errors.Is(err, errmap.ErrShipmentNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrOrderNotFound.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "delivery reattempt scheduled", shipment)
+}
+
+// CourierWebhook godoc
+//
+//	@Summary		Courier tracking webhook
+//	@Description	Receives tracking status updates from a courier. The body must be signed with the courier's webhook secret: X-Courier-Signature = hex(HMAC-SHA256(secret, body)). Redelivered events with the same eventId are ignored.
+//	@Tags			Shipment
+//	@Accept			json
+//	@Produce		json
+//	@Param			courierId			path		int								true	"Courier ID"
+//	@Param			X-Courier-Signature	header		string							true	"Hex HMAC-SHA256 of the raw body"
+//	@Param			body				body		entity.CourierWebhookRequest	true	"Tracking event"
+//	@Success		200					{object}	response.ResponseSuccess
+//	@Failure		400					{object}	response.ResponseError
+//	@Failure		401					{object}	response.ResponseError
+//	@Failure		404					{object}	response.ResponseError
+//	@Failure		500					{object}	response.ResponseError
+//	@Router			/api/webhooks/couriers/{courierId} [post]
+func (h *OrderHandler) CourierWebhook(c echo.Context) error {
+	courierID, err := strconv.ParseUint(c.Param("courierId"), 10, 32)
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidCourierID.Error())
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, 1<<20))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	signature := c.Request().Header.Get("X-Courier-Signature")
+
+	if err := h.usecase.HandleCourierWebhook(c.Request().Context(), uint32(courierID), signature, payload); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidWebhookSignature):
+			return response.Error(c, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, errmap.ErrCourierNotFound), errors.Is(err, errmap.ErrShipmentNotFound):
+			return response.Error(c, http.StatusNotFound, err.Error())
+		case errors.Is(err, errmap.ErrInvalidRequest), errors.Is(err, errmap.ErrInvalidShipmentStatus):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "ok", nil)
+}
+
+// GetShippingLabel godoc
+//
+//	@Summary		Get shipping label
+//	@Description	Render the shipping label and packing slip of a shipped shop order as PDF
+//	@Tags			Order
+//	@Security		BearerAuth
+//	@Produce		application/pdf
+//	@Param			shopOrderId	path		string	true	"Shop Order ID"
+//	@Success		200			{file}		file
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/orders/{shopOrderId}/label [get]
+func (h *OrderHandler) GetShippingLabel(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	shopOrderID, err := uuid.Parse(c.Param("shopOrderId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidOrderID.Error())
+	}
+
+	pdf, err := h.usecase.GetShippingLabel(c.Request().Context(), userID, shopOrderID)
+	if err != nil {
+		return shippingLabelError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=\"label-%s.pdf\"", shopOrderID))
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+// GetShippingLabels godoc
+//
+//	@Summary		Get shipping labels in bulk
+//	@Description	Render shipping labels and packing slips of up to 50 shipped shop orders into one PDF
+//	@Tags			Order
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		application/pdf
+//	@Param			body	body		entity.BulkShippingLabelRequest	true	"Shop order IDs"
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop/orders/labels [post]
+func (h *OrderHandler) GetShippingLabels(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.BulkShippingLabelRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	pdf, err := h.usecase.GetShippingLabels(c.Request().Context(), userID, req.ShopOrderIDs)
+	if err != nil {
+		return shippingLabelError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, "inline; filename=\"labels.pdf\"")
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+func shippingLabelError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errmap.ErrForbidden):
+		return response.Error(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, errmap.ErrLabelUnavailable):
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, errmap.ErrShipmentNotFound):
+		return response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return response.Error(c, http.StatusNotFound, errmap.ErrOrderNotFound.Error())
+	default:
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+}
+
 func RegisterOrderHandler(group *echo.Group, db *gorm.DB) {
 	repo := repository.NewOrderRepository(db)
 	shopRepo := shopRepo.NewShopRepository(db)
 	productRepo := productRepo.NewProductRepository(db)
 	userRepo := userRepo.NewUserRepository(db)
-	orderUsecase := usecase.NewOrderUsecase(repo, shopRepo, productRepo, userRepo)
+	orderUsecase := usecase.NewOrderUsecase(repo, shopRepo, productRepo, userRepo, provider.Default(), estimate.NewEstimator(courierRepo.NewCourierRepository(db)), outboxRepo.NewOutboxRepository(db), dbtx.NewTransactor(db), config.REQUIRE_EMAIL_VERIFICATION)
 	handler := NewOrderHandler(orderUsecase)
 	RegisterRoutes(group, handler)
 }
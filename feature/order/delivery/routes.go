@@ -18,12 +18,17 @@ func RegisterRoutes(g *echo.Group, h *OrderHandler) {
 	order.POST("/:orderId/payment", h.CreateOrderPayment)
 	order.GET("/:shopOrderId/tracking", h.GetShipmentTracking)
 	order.PUT("/:shopOrderId/approved", h.ApproveOrder)
+	order.PUT("/:shopOrderId/shipments/:shipmentId/reattempt", h.ScheduleDeliveryReattempt)
 
 	shopOrder := g.Group("/shop/orders", middleware.JWTAuth(), middleware.ShopOwnerOnly())
 	shopOrder.GET("", h.ListShopOrders)
+	shopOrder.POST("/labels", h.GetShippingLabels)
 	shopOrder.GET("/:shopOrderId", h.GetShopOrder)
 	shopOrder.GET("/:shopOrderId/tracking", h.GetShopShipmentTracking)
+	shopOrder.GET("/:shopOrderId/label", h.GetShippingLabel)
 	shopOrder.PUT("/:shopOrderId/status", h.UpdateShopOrderStatus)
 	shopOrder.PUT("/:shopOrderId/cancel", h.CancelShopOrder)
 	shopOrder.POST("/:shopOrderId/shipping", h.AddShipment)
+
+	g.POST("/webhooks/couriers/:courierId", h.CourierWebhook)
 }
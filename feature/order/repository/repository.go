@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +13,7 @@ import (
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/dbtx"
 	"ecommerce-go-api/internal/errmap"
 	"ecommerce-go-api/internal/timeth"
 )
@@ -24,9 +26,14 @@ func NewOrderRepository(db *gorm.DB) domain.OrderRepository {
 	return &orderRepository{db: db}
 }
 
+// conn joins the transaction carried by ctx, if any.
+func (r *orderRepository) conn(ctx context.Context) *gorm.DB {
+	return dbtx.From(ctx, r.db)
+}
+
 func (r *orderRepository) GetCartByUserID(ctx context.Context, userID uuid.UUID) (*entity.Cart, error) {
 	var c entity.Cart
-	if err := r.db.WithContext(ctx).First(&c, "user_id = ?", userID).Error; err != nil {
+	if err := r.conn(ctx).First(&c, "user_id = ?", userID).Error; err != nil {
 		return nil, err
 	}
 	return &c, nil
@@ -39,7 +46,7 @@ func (r *orderRepository) EnsureCartForUser(ctx context.Context, userID uuid.UUI
 	}
 
 	cart := &entity.Cart{UserID: userID}
-	if err := r.db.WithContext(ctx).Create(cart).Error; err != nil {
+	if err := r.conn(ctx).Create(cart).Error; err != nil {
 		return nil, err
 	}
 	return cart, nil
@@ -47,7 +54,7 @@ func (r *orderRepository) EnsureCartForUser(ctx context.Context, userID uuid.UUI
 
 func (r *orderRepository) ListCartItems(ctx context.Context, cartID uint32) ([]*entity.CartItem, error) {
 	var items []*entity.CartItem
-	if err := r.db.WithContext(ctx).
+	if err := r.conn(ctx).
 		Preload("Product", "deleted_at IS NULL").
 		Where("cart_id = ? AND deleted_at IS NULL", cartID).
 		Find(&items).Error; err != nil {
@@ -57,13 +64,13 @@ func (r *orderRepository) ListCartItems(ctx context.Context, cartID uint32) ([]*
 }
 
 func (r *orderRepository) AddCartItem(ctx context.Context, item *entity.CartItem) error {
-	return r.db.WithContext(ctx).Create(item).Error
+	return r.conn(ctx).Create(item).Error
 }
 
 func (r *orderRepository) UpsertCartItem(ctx context.Context, item *entity.CartItem) (*entity.CartItem, error) {
 	var result *entity.CartItem
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
 		var product entity.Product
 		if err := tx.Where("id = ?", item.ProductID).First(&product).Error; err != nil {
 			return err
@@ -112,35 +119,35 @@ func (r *orderRepository) UpsertCartItem(ctx context.Context, item *entity.CartI
 
 func (r *orderRepository) GetCartItemByID(ctx context.Context, id uint32) (*entity.CartItem, error) {
 	var it entity.CartItem
-	if err := r.db.WithContext(ctx).First(&it, "id = ? AND deleted_at IS NULL", id).Error; err != nil {
+	if err := r.conn(ctx).First(&it, "id = ? AND deleted_at IS NULL", id).Error; err != nil {
 		return nil, err
 	}
 	return &it, nil
 }
 
 func (r *orderRepository) UpdateCartItem(ctx context.Context, item *entity.CartItem) error {
-	return r.db.WithContext(ctx).Save(item).Error
+	return r.conn(ctx).Save(item).Error
 }
 
 func (r *orderRepository) DeleteCartItem(ctx context.Context, id uint32) error {
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.CartItem{}).Error
+	return r.conn(ctx).Where("id = ?", id).Delete(&entity.CartItem{}).Error
 }
 
 func (r *orderRepository) ClearCart(ctx context.Context, cartID uint32) error {
-	return r.db.WithContext(ctx).Where("cart_id = ?", cartID).Delete(&entity.CartItem{}).Error
+	return r.conn(ctx).Where("cart_id = ?", cartID).Delete(&entity.CartItem{}).Error
 }
 
 func (r *orderRepository) CreateOrder(ctx context.Context, order *entity.Order) error {
-	return r.db.WithContext(ctx).Create(order).Error
+	return r.conn(ctx).Create(order).Error
 }
 
 func (r *orderRepository) CreateShopOrder(ctx context.Context, so *entity.ShopOrder) error {
-	return r.db.WithContext(ctx).Create(so).Error
+	return r.conn(ctx).Create(so).Error
 }
 
 func (r *orderRepository) CreateOrderItems(ctx context.Context, items []*entity.OrderItem) error {
 	for _, it := range items {
-		if err := r.db.WithContext(ctx).Create(it).Error; err != nil {
+		if err := r.conn(ctx).Create(it).Error; err != nil {
 			return err
 		}
 	}
@@ -148,7 +155,7 @@ func (r *orderRepository) CreateOrderItems(ctx context.Context, items []*entity.
 }
 
 func (r *orderRepository) CreateFullOrder(ctx context.Context, order *entity.Order, shopOrders []*entity.ShopOrder, orderItemsByShop map[string][]*entity.OrderItem, payment *entity.Payment, cartID uint32, userID uuid.UUID) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
 		now := timeth.Now()
 
 		order.CreatedAt = now
@@ -246,7 +253,7 @@ func (r *orderRepository) ListOrdersByUser(ctx context.Context, userID uuid.UUID
 	}
 	offset := (page - 1) * perPage
 
-	query := r.db.WithContext(ctx).Model(&entity.Order{}).Where("user_id = ?", userID)
+	query := r.conn(ctx).Model(&entity.Order{}).Where("user_id = ?", userID)
 
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -282,7 +289,7 @@ func (r *orderRepository) ListShopOrdersByUserID(ctx context.Context, userID uui
 	}
 	offset := (page - 1) * perPage
 
-	query := r.db.WithContext(ctx).Model(&entity.ShopOrder{}).
+	query := r.conn(ctx).Model(&entity.ShopOrder{}).
 		Joins("JOIN orders ON orders.id = shop_orders.order_id").
 		Where("orders.user_id = ?", userID)
 
@@ -316,7 +323,7 @@ func (r *orderRepository) ListShopOrdersByUserID(ctx context.Context, userID uui
 
 func (r *orderRepository) GetOrderByID(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
 	var order entity.Order
-	err := r.db.WithContext(ctx).
+	err := r.conn(ctx).
 		Preload("User").
 		Preload("Address").
 		Preload("ShopOrders").
@@ -344,7 +351,7 @@ func (r *orderRepository) ListShopOrdersByShopID(ctx context.Context, shopID uui
 	}
 	offset := (page - 1) * perPage
 
-	query := r.db.WithContext(ctx).Model(&entity.ShopOrder{}).Where("shop_id = ?", shopID)
+	query := r.conn(ctx).Model(&entity.ShopOrder{}).Where("shop_id = ?", shopID)
 
 	if req.SearchText != nil {
 		searchPattern := "%" + *req.SearchText + "%"
@@ -375,7 +382,7 @@ func (r *orderRepository) ListShopOrdersByShopID(ctx context.Context, shopID uui
 
 func (r *orderRepository) GetShopOrderByID(ctx context.Context, id uuid.UUID) (*entity.ShopOrder, error) {
 	var so entity.ShopOrder
-	err := r.db.WithContext(ctx).
+	err := r.conn(ctx).
 		Preload("Order").
 		Preload("Order.User").
 		Preload("Order.Address").
@@ -394,23 +401,207 @@ func (r *orderRepository) UpdateShopOrderStatus(ctx context.Context, id uuid.UUI
 		"order_status_id": OrderStatusID,
 		"updated_at":      timeth.Now(),
 	}
-	return r.db.WithContext(ctx).Model(&entity.ShopOrder{}).Where("id = ?", id).Updates(updates).Error
+	if OrderStatusID == entity.OrderStatusDelivered {
+		updates["delivered_at"] = updates["updated_at"]
+	}
+	return r.conn(ctx).Model(&entity.ShopOrder{}).Where("id = ?", id).Updates(updates).Error
 }
 
 func (r *orderRepository) CancelShopOrder(ctx context.Context, id uuid.UUID, reason string) error {
-	return r.db.WithContext(ctx).Model(&entity.ShopOrder{}).Where("id = ?", id).Updates(map[string]interface{}{"order_status_id": entity.OrderStatusCancelled}).Error
+	return r.conn(ctx).Model(&entity.ShopOrder{}).Where("id = ?", id).Updates(map[string]interface{}{"order_status_id": entity.OrderStatusCancelled}).Error
+}
+
+// CancelShopOrderWithRefund cancels a paid shop order and refunds what the buyer has
+// not been refunded yet, in one transaction. The refund supersedes the shop order's
+// open refund requests, which are rejected, and its amount is capped at the shop
+// order's total less its approved and completed refunds. It reports whether the refund
+// was created: nothing is left to refund when earlier refunds already cover the total.
+// The order's payment then moves to REFUNDED or PARTIALLY_REFUNDED.
+func (r *orderRepository) CancelShopOrderWithRefund(ctx context.Context, id uuid.UUID, reason string, refund *entity.Refund) (bool, error) {
+	created := false
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var so entity.ShopOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&so, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		now := timeth.Now()
+		if err := tx.Model(&entity.ShopOrder{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"order_status_id": entity.OrderStatusCancelled,
+			"updated_at":      now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&entity.Refund{}).
+			Where("shop_order_id = ?", id).
+			Where("refund_status_id IN ?", []uint32{
+				entity.RefundStatusPending,
+				entity.RefundStatusCounterOffered,
+				entity.RefundStatusEscalated,
+			}).
+			Updates(map[string]interface{}{
+				"refund_status_id": entity.RefundStatusRejected,
+				"reject_reason":    "Superseded by the refund of the cancelled order",
+				"rejected_at":      now,
+				"response_due_at":  nil,
+				"updated_at":       now,
+			}).Error; err != nil {
+			return err
+		}
+
+		var refunded float64
+		if err := tx.Model(&entity.Refund{}).
+			Select("COALESCE(SUM(amount), 0)").
+			Where("shop_order_id = ?", id).
+			Where("refund_status_id IN ?", []uint32{entity.RefundStatusApproved, entity.RefundStatusCompleted}).
+			Scan(&refunded).Error; err != nil {
+			return err
+		}
+
+		remaining := math.Round((so.GrandTotal-refunded)*100) / 100
+		if refund.Amount > remaining {
+			refund.Amount = remaining
+		}
+		if refund.Amount <= 0 {
+			return nil
+		}
+
+		if err := tx.Create(refund).Error; err != nil {
+			return err
+		}
+		created = true
+
+		return updatePaymentRefundStatus(tx, *refund.PaymentID)
+	})
+	return created, err
+}
+
+// CreateShopOrderRefund records a refund for part of a shop order that stays open, such
+// as a parcel returned to the shop, and updates the payment's refund status.
+func (r *orderRepository) CreateShopOrderRefund(ctx context.Context, refund *entity.Refund) error {
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(refund).Error; err != nil {
+			return err
+		}
+
+		return updatePaymentRefundStatus(tx, *refund.PaymentID)
+	})
+}
+
+// UpdatePaymentRefundStatus moves the payment to REFUNDED or PARTIALLY_REFUNDED after
+// one of its refunds has been paid out.
+func (r *orderRepository) UpdatePaymentRefundStatus(ctx context.Context, paymentID uuid.UUID) error {
+	return updatePaymentRefundStatus(r.conn(ctx), paymentID)
+}
+
+// updatePaymentRefundStatus marks the payment as refunded or partially refunded from the
+// total of the order's approved and completed refunds. Requests still being negotiated
+// do not count.
+func updatePaymentRefundStatus(tx *gorm.DB, paymentID uuid.UUID) error {
+	var payment entity.Payment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, "id = ?", paymentID).Error; err != nil {
+		return err
+	}
+
+	var refunded float64
+	if err := tx.Model(&entity.Refund{}).
+		Select("COALESCE(SUM(refunds.amount), 0)").
+		Joins("JOIN shop_orders ON shop_orders.id = refunds.shop_order_id").
+		Where("shop_orders.order_id = ?", payment.OrderID).
+		Where("refunds.refund_status_id IN ?", []uint32{entity.RefundStatusApproved, entity.RefundStatusCompleted}).
+		Scan(&refunded).Error; err != nil {
+		return err
+	}
+
+	paymentStatusID := entity.PaymentStatusPartiallyRefunded
+	if refunded >= payment.Amount {
+		paymentStatusID = entity.PaymentStatusRefunded
+	}
+
+	return tx.Model(&entity.Payment{}).Where("id = ?", payment.ID).Updates(map[string]interface{}{
+		"payment_status_id": paymentStatusID,
+		"updated_at":        timeth.Now(),
+	}).Error
+}
+
+func (r *orderRepository) CreateDeliveryReattempt(ctx context.Context, reattempt *entity.DeliveryReattempt) error {
+	return r.conn(ctx).Create(reattempt).Error
 }
 
+// AddShipment creates a parcel with its items. The shop order row is locked while the
+// quantities already shipped are checked, so concurrent parcels cannot over-ship an item.
 func (r *orderRepository) AddShipment(ctx context.Context, s *entity.Shipment) error {
-	return r.db.WithContext(ctx).Create(s).Error
+	return r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		var so entity.ShopOrder
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("OrderItems").
+			First(&so, "id = ?", s.ShopOrderID).Error; err != nil {
+			return err
+		}
+
+		type shippedQty struct {
+			OrderItemID uint32
+			Qty         uint32
+		}
+		var shipped []shippedQty
+		if err := tx.Model(&entity.ShipmentItem{}).
+			Select("shipment_items.order_item_id, SUM(shipment_items.qty) AS qty").
+			Joins("JOIN shipments ON shipments.id = shipment_items.shipment_id").
+			Where("shipments.shop_order_id = ?", s.ShopOrderID).
+			Group("shipment_items.order_item_id").
+			Scan(&shipped).Error; err != nil {
+			return err
+		}
+
+		remaining := make(map[uint32]uint32, len(so.OrderItems))
+		for _, oi := range so.OrderItems {
+			remaining[oi.ID] = oi.Qty
+		}
+		for _, sq := range shipped {
+			remaining[sq.OrderItemID] -= sq.Qty
+		}
+		for _, item := range s.Items {
+			if item.Qty > remaining[item.OrderItemID] {
+				return errmap.ErrInvalidShipmentItems
+			}
+			remaining[item.OrderItemID] -= item.Qty
+		}
+
+		return tx.Create(s).Error
+	})
 }
 
-func (r *orderRepository) GetShipmentByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) (*entity.Shipment, error) {
-	var shipment entity.Shipment
-	err := r.db.WithContext(ctx).
+func (r *orderRepository) GetShipmentsByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) ([]*entity.Shipment, error) {
+	var shipments []*entity.Shipment
+	err := r.conn(ctx).
 		Preload("Courier").
 		Preload("ShipmentStatus").
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("shipment_events.occurred_at ASC, shipment_events.id ASC")
+		}).
+		Preload("Events.ShipmentStatus").
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("shipment_items.id ASC")
+		}).
+		Preload("Items.OrderItem.Product").
+		Preload("Reattempts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("delivery_reattempts.id ASC")
+		}).
 		Where("shop_order_id = ?", shopOrderID).
+		Order("created_at ASC").
+		Find(&shipments).Error
+	if err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+func (r *orderRepository) GetShipmentByTrackingNo(ctx context.Context, courierID uint32, trackingNo string) (*entity.Shipment, error) {
+	var shipment entity.Shipment
+	err := r.conn(ctx).
+		Where("courier_id = ? AND tracking_no = ?", courierID, trackingNo).
+		Order("created_at DESC").
 		First(&shipment).Error
 	if err != nil {
 		return nil, err
@@ -418,8 +609,92 @@ func (r *orderRepository) GetShipmentByShopOrderID(ctx context.Context, shopOrde
 	return &shipment, nil
 }
 
+// ListShipmentsForTrackingSync returns booked shipments that have not reached a final
+// tracking state, oldest update first.
+func (r *orderRepository) ListShipmentsForTrackingSync(ctx context.Context, limit int) ([]*entity.Shipment, error) {
+	var shipments []*entity.Shipment
+	err := r.conn(ctx).
+		Preload("Courier").
+		Joins("JOIN shop_orders ON shop_orders.id = shipments.shop_order_id").
+		Where("shipments.provider_code IS NOT NULL").
+		Where("shipments.shipment_status_id NOT IN ?", []uint32{
+			entity.ShipmentStatusDelivered,
+			entity.ShipmentStatusReturnedToSender,
+		}).
+		Where("shop_orders.order_status_id <> ?", entity.OrderStatusCancelled).
+		Order("shipments.updated_at ASC").
+		Limit(limit).
+		Find(&shipments).Error
+	if err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+func (r *orderRepository) GetCourierByID(ctx context.Context, id uint32) (*entity.Courier, error) {
+	var courier entity.Courier
+	if err := r.conn(ctx).First(&courier, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &courier, nil
+}
+
+// RecordShipmentEvent stores a tracking event and, when it is the most recent event for
+// the shipment, makes its status the shipment's current status. Events redelivered with
+// the same ExternalEventID are ignored. Every new failed delivery counts as an attempt,
+// even when the previous event had also failed. It reports whether the shipment status
+// changed or a failed attempt was counted.
+func (r *orderRepository) RecordShipmentEvent(ctx context.Context, event *entity.ShipmentEvent) (bool, error) {
+	applied := false
+	err := r.conn(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+
+		var newer int64
+		if err := tx.Model(&entity.ShipmentEvent{}).
+			Where("shipment_id = ? AND occurred_at > ?", event.ShipmentID, event.OccurredAt).
+			Count(&newer).Error; err != nil {
+			return err
+		}
+		if newer > 0 {
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"shipment_status_id": event.ShipmentStatusID,
+			"updated_at":         timeth.Now(),
+		}
+		if event.ShipmentStatusID == entity.ShipmentStatusDelivered {
+			updates["delivered_at"] = event.OccurredAt
+		}
+
+		q := tx.Model(&entity.Shipment{}).Where("id = ?", event.ShipmentID)
+		if event.ShipmentStatusID == entity.ShipmentStatusFailedDelivery {
+			updates["failed_attempts"] = gorm.Expr("failed_attempts + 1")
+		} else {
+			q = q.Where("shipment_status_id <> ?", event.ShipmentStatusID)
+		}
+
+		res = q.Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		applied = res.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
 func (r *orderRepository) UpdateShipmentStatusByShopOrderID(ctx context.Context, shopOrderID uuid.UUID, shipmentStatusID uint32) error {
-	return r.db.WithContext(ctx).
+	return r.conn(ctx).
 		Model(&entity.Shipment{}).
 		Where("shop_order_id = ?", shopOrderID).
 		Update("shipment_status_id", shipmentStatusID).Error
@@ -427,7 +702,7 @@ func (r *orderRepository) UpdateShipmentStatusByShopOrderID(ctx context.Context,
 
 func (r *orderRepository) CreatePayment(ctx context.Context, payment *entity.Payment) error {
 	var existing entity.Payment
-	err := r.db.WithContext(ctx).
+	err := r.conn(ctx).
 		Where("order_id = ? AND payment_status_id != ?", payment.OrderID, entity.PaymentStatusExpired).
 		First(&existing).Error
 
@@ -439,12 +714,12 @@ func (r *orderRepository) CreatePayment(ctx context.Context, payment *entity.Pay
 		return err
 	}
 
-	return r.db.WithContext(ctx).Create(payment).Error
+	return r.conn(ctx).Create(payment).Error
 }
 
 func (r *orderRepository) GetPaymentByOrderID(ctx context.Context, orderID uuid.UUID) (*entity.Payment, error) {
 	var payment entity.Payment
-	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&payment).Error
+	err := r.conn(ctx).Where("order_id = ?", orderID).First(&payment).Error
 	if err != nil {
 		return nil, err
 	}
@@ -453,7 +728,7 @@ func (r *orderRepository) GetPaymentByOrderID(ctx context.Context, orderID uuid.
 
 func (r *orderRepository) GetPaymentByTransactionID(ctx context.Context, transactionID string) (*entity.Payment, error) {
 	var payment entity.Payment
-	err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).First(&payment).Error
+	err := r.conn(ctx).Where("transaction_id = ?", transactionID).First(&payment).Error
 	if err != nil {
 		return nil, err
 	}
@@ -468,42 +743,121 @@ func (r *orderRepository) UpdatePaymentStatus(ctx context.Context, id uuid.UUID,
 	if paidAt != nil {
 		updates["paid_at"] = paidAt
 	}
-	return r.db.WithContext(ctx).Model(&entity.Payment{}).Where("id = ?", id).Updates(updates).Error
+	return r.conn(ctx).Model(&entity.Payment{}).Where("id = ?", id).Updates(updates).Error
 }
 
-func (r *orderRepository) ListExpiredPayments(ctx context.Context) ([]*entity.Payment, error) {
-	var payments []*entity.Payment
-	err := r.db.WithContext(ctx).
+// ClaimExpiredPayments leases a batch of pending payments past their expiry to the
+// claimant. Rows locked or leased by another instance are skipped, so several instances
+// can share the work; a lease that runs out without the payment being expired makes it
+// claimable again.
+func (r *orderRepository) ClaimExpiredPayments(ctx context.Context, claim entity.JobClaim) ([]*entity.Payment, error) {
+	now := timeth.Now()
+	due := r.conn(ctx).Model(&entity.Payment{}).
+		Select("id").
 		Where("payment_status_id = ?", entity.PaymentStatusPending).
-		Where("expires_at < ?", timeth.Now()).
-		Find(&payments).Error
-	return payments, err
+		Where("expires_at < ?", now).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Order("expires_at").
+		Limit(claim.Limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var payments []*entity.Payment
+	err := r.conn(ctx).
+		Raw("UPDATE payments SET claimed_by = ?, claimed_until = ? WHERE id IN (?) RETURNING *",
+			claim.Claimant, now.Add(claim.Lease), due).
+		Scan(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
 }
 
-func (r *orderRepository) ListDeliveredOrdersOlderThan(ctx context.Context, days int) ([]*entity.ShopOrder, error) {
-	var shopOrders []*entity.ShopOrder
-	cutoffTime := timeth.Now().AddDate(0, 0, -days)
+// ClaimDeliveredOrdersToAutoComplete leases a batch of delivered shop orders whose
+// auto-complete window (the shop's own or defaultDays) has passed. Orders with an open
+// refund are left alone until it is settled.
+func (r *orderRepository) ClaimDeliveredOrdersToAutoComplete(ctx context.Context, defaultDays int, claim entity.JobClaim) ([]*entity.ShopOrder, error) {
+	return r.claimDeliveredOrders(ctx, r.deliveredOrdersDueWithin(ctx, defaultDays, 0), claim)
+}
 
-	err := r.db.WithContext(ctx).
-		Preload("Order").
-		Where("order_status_id = ?", entity.OrderStatusDelivered).
-		Where("updated_at < ?", cutoffTime).
-		Find(&shopOrders).Error
+// ClaimDeliveredOrdersToRemind leases a batch of delivered shop orders that will be
+// auto-completed within a day and whose buyer has not been reminded yet.
+func (r *orderRepository) ClaimDeliveredOrdersToRemind(ctx context.Context, defaultDays int, claim entity.JobClaim) ([]*entity.ShopOrder, error) {
+	due := r.deliveredOrdersDueWithin(ctx, defaultDays, 1).
+		Where("shop_orders.auto_complete_reminded_at IS NULL")
+	return r.claimDeliveredOrders(ctx, due, claim)
+}
+
+func (r *orderRepository) claimDeliveredOrders(ctx context.Context, due *gorm.DB, claim entity.JobClaim) ([]*entity.ShopOrder, error) {
+	now := timeth.Now()
+	due = due.
+		Select("shop_orders.id").
+		Where("shop_orders.claimed_until IS NULL OR shop_orders.claimed_until < ?", now).
+		Order("shop_orders.delivered_at").
+		Limit(claim.Limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "shop_orders"}, Options: "SKIP LOCKED"})
 
+	var claimed []entity.ShopOrder
+	err := r.conn(ctx).
+		Raw("UPDATE shop_orders SET claimed_by = ?, claimed_until = ? WHERE id IN (?) RETURNING id",
+			claim.Claimant, now.Add(claim.Lease), due).
+		Scan(&claimed).Error
 	if err != nil {
 		return nil, err
 	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(claimed))
+	for _, so := range claimed {
+		ids = append(ids, so.ID)
+	}
 
+	var shopOrders []*entity.ShopOrder
+	err = r.conn(ctx).
+		Preload("Order").
+		Preload("Shop", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("id IN ?", ids).
+		Find(&shopOrders).Error
+	if err != nil {
+		return nil, err
+	}
 	return shopOrders, nil
 }
 
+func (r *orderRepository) deliveredOrdersDueWithin(ctx context.Context, defaultDays, daysBefore int) *gorm.DB {
+	return r.conn(ctx).
+		Model(&entity.ShopOrder{}).
+		Joins("JOIN shops ON shops.id = shop_orders.shop_id").
+		Where("shop_orders.order_status_id = ?", entity.OrderStatusDelivered).
+		Where("COALESCE(shop_orders.delivered_at, shop_orders.updated_at) + make_interval(days => COALESCE(shops.auto_complete_days, ?) - ?) < ?",
+			defaultDays, daysBefore, timeth.Now()).
+		Where("NOT EXISTS (SELECT 1 FROM refunds WHERE refunds.shop_order_id = shop_orders.id AND refunds.refund_status_id IN ?)", []uint32{
+			entity.RefundStatusPending,
+			entity.RefundStatusApproved,
+			entity.RefundStatusCounterOffered,
+			entity.RefundStatusEscalated,
+		})
+}
+
+// MarkAutoCompleteReminded records the reminder and releases the order's claim so the
+// completion pass can pick it up when it is due.
+func (r *orderRepository) MarkAutoCompleteReminded(ctx context.Context, id uuid.UUID) error {
+	return r.conn(ctx).Model(&entity.ShopOrder{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"auto_complete_reminded_at": timeth.Now(),
+			"claimed_by":                nil,
+			"claimed_until":             nil,
+		}).Error
+}
+
 func (r *orderRepository) CreateOrderLog(ctx context.Context, log *entity.OrderLog) error {
-	return r.db.WithContext(ctx).Create(log).Error
+	return r.conn(ctx).Create(log).Error
 }
 
 func (r *orderRepository) GetOrderLogsByOrderID(ctx context.Context, orderID uuid.UUID) ([]*entity.OrderLog, error) {
 	var logs []*entity.OrderLog
-	err := r.db.WithContext(ctx).
+	err := r.conn(ctx).
 		Where("order_id = ?", orderID).
 		Order("created_at ASC").
 		Find(&logs).Error
@@ -515,7 +869,7 @@ func (r *orderRepository) GetOrderLogsByOrderID(ctx context.Context, orderID uui
 
 func (r *orderRepository) GetOrderLogsByShopOrderID(ctx context.Context, shopOrderID uuid.UUID) ([]*entity.OrderLog, error) {
 	var logs []*entity.OrderLog
-	err := r.db.WithContext(ctx).
+	err := r.conn(ctx).
 		Where("shop_order_id = ?", shopOrderID).
 		Order("created_at ASC").
 		Find(&logs).Error
@@ -1,9 +1,16 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,18 +20,40 @@ import (
 	"ecommerce-go-api/entity"
 	"ecommerce-go-api/internal/constant"
 	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/shiplabel"
 	"ecommerce-go-api/internal/timeth"
 )
 
+// maxDeliveryAttempts is how many times a courier may fail to deliver a parcel before it
+// is returned to the shop.
+const maxDeliveryAttempts = 3
+
 type orderUsecase struct {
 	repo        domain.OrderRepository
 	shopRepo    domain.ShopRepository
 	productRepo domain.ProductRepository
 	userRepo    domain.UserRepository
+	couriers    domain.CourierProviderRegistry
+	estimator   domain.DeliveryEstimator
+	outboxRepo  domain.OutboxRepository
+	tx          domain.Transactor
+	// requireVerifiedEmail stops users who have not verified their email from ordering.
+	requireVerifiedEmail bool
+}
+
+func NewOrderUsecase(r domain.OrderRepository, s domain.ShopRepository, p domain.ProductRepository, u domain.UserRepository, c domain.CourierProviderRegistry, e domain.DeliveryEstimator, o domain.OutboxRepository, t domain.Transactor, requireVerifiedEmail bool) domain.OrderUsecase {
+	return &orderUsecase{repo: r, shopRepo: s, productRepo: p, userRepo: u, couriers: c, estimator: e, outboxRepo: o, tx: t, requireVerifiedEmail: requireVerifiedEmail}
 }
 
-func NewOrderUsecase(r domain.OrderRepository, s domain.ShopRepository, p domain.ProductRepository, u domain.UserRepository) domain.OrderUsecase {
-	return &orderUsecase{repo: r, shopRepo: s, productRepo: p, userRepo: u}
+// publish adds an event to the outbox. Call it inside the transaction that makes the
+// change the event describes.
+func (u *orderUsecase) publish(ctx context.Context, eventType, aggregateType string, aggregateID uuid.UUID, payload any) error {
+	event, err := outbox.NewEvent(eventType, aggregateType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return u.outboxRepo.Add(ctx, event)
 }
 
 func mapToCartItemResponse(item *entity.CartItem) *entity.CartItemResponse {
@@ -62,17 +91,57 @@ func mapToShipmentResponse(s *entity.Shipment) *entity.ShipmentResponse {
 		ShopOrderID:      s.ShopOrderID,
 		CourierID:        s.CourierID,
 		TrackingNo:       s.TrackingNo,
+		Booked:           s.ProviderCode != nil,
 		ShipmentStatusID: s.ShipmentStatusID,
+		FailedAttempts:   s.FailedAttempts,
 		CreatedAt:        s.CreatedAt,
 		ShippedAt:        s.ShippedAt,
+		DeliveredAt:      s.DeliveredAt,
+		Events:           make([]entity.ShipmentEventResponse, 0, len(s.Events)),
+		Items:            make([]entity.ShipmentItemResponse, 0, len(s.Items)),
+		Reattempts:       make([]entity.DeliveryReattemptResponse, 0, len(s.Reattempts)),
+	}
+
+	for _, si := range s.Items {
+		resp.Items = append(resp.Items, mapToShipmentItemResponse(si))
+	}
+
+	for _, r := range s.Reattempts {
+		resp.Reattempts = append(resp.Reattempts, entity.DeliveryReattemptResponse{
+			ID:             r.ID,
+			ScheduledDate:  r.ScheduledDate.Format("2006-01-02"),
+			AddressChanged: r.AddressID != nil,
+			Note:           r.Note,
+			CreatedAt:      r.CreatedAt,
+		})
+	}
+
+	for _, e := range s.Events {
+		event := entity.ShipmentEventResponse{
+			ID:               e.ID,
+			ShipmentStatusID: e.ShipmentStatusID,
+			Source:           e.Source,
+			Description:      e.Description,
+			Location:         e.Location,
+			OccurredAt:       e.OccurredAt,
+		}
+		if e.ShipmentStatus != nil {
+			event.ShipmentStatus = &entity.ShipmentStatusResponse{
+				ID:   e.ShipmentStatus.ID,
+				Code: e.ShipmentStatus.Code,
+				Name: e.ShipmentStatus.Name,
+			}
+		}
+		resp.Events = append(resp.Events, event)
 	}
 
 	if s.Courier.ID != 0 {
 		resp.Courier = &entity.CourierListResponse{
-			ID:       s.Courier.ID,
-			Name:     s.Courier.Name,
-			ImageURL: s.Courier.ImageURL,
-			Rate:     s.Courier.Rate,
+			ID:              s.Courier.ID,
+			Name:            s.Courier.Name,
+			ImageURL:        s.Courier.ImageURL,
+			Rate:            s.Courier.Rate,
+			SupportsBooking: s.Courier.ProviderCode != "",
 		}
 	}
 
@@ -87,6 +156,124 @@ func mapToShipmentResponse(s *entity.Shipment) *entity.ShipmentResponse {
 	return resp
 }
 
+func mapToShipmentItemResponse(si entity.ShipmentItem) entity.ShipmentItemResponse {
+	return entity.ShipmentItemResponse{
+		OrderItemID: si.OrderItemID,
+		ProductID:   si.OrderItem.ProductID,
+		ProductName: si.OrderItem.Product.Name,
+		Qty:         si.Qty,
+	}
+}
+
+// unshippedItems lists the order items, with quantities, that no parcel carries yet.
+func unshippedItems(so *entity.ShopOrder, shipments []*entity.Shipment) []entity.ShipmentItem {
+	shipped := make(map[uint32]uint32)
+	for _, s := range shipments {
+		for _, si := range s.Items {
+			shipped[si.OrderItemID] += si.Qty
+		}
+	}
+
+	var items []entity.ShipmentItem
+	for _, oi := range so.OrderItems {
+		if oi.Qty > shipped[oi.ID] {
+			items = append(items, entity.ShipmentItem{
+				OrderItemID: oi.ID,
+				Qty:         oi.Qty - shipped[oi.ID],
+				OrderItem:   oi,
+			})
+		}
+	}
+	return items
+}
+
+// parcelItems resolves the items requested for a new parcel against what is still
+// unshipped. No items means everything that is left.
+func parcelItems(unshipped []entity.ShipmentItem, req []entity.AddShipmentItemRequest) ([]entity.ShipmentItem, error) {
+	if len(req) == 0 {
+		return unshipped, nil
+	}
+
+	available := make(map[uint32]entity.ShipmentItem, len(unshipped))
+	for _, item := range unshipped {
+		available[item.OrderItemID] = item
+	}
+
+	items := make([]entity.ShipmentItem, 0, len(req))
+	for _, r := range req {
+		item, ok := available[r.OrderItemID]
+		if !ok || r.Qty > item.Qty {
+			return nil, errmap.ErrInvalidShipmentItems
+		}
+		delete(available, r.OrderItemID)
+
+		item.Qty = r.Qty
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// itemsValue is what the buyer paid for the given items.
+func itemsValue(items []entity.ShipmentItem) float64 {
+	var value float64
+	for _, item := range items {
+		value += float64(item.Qty) * item.OrderItem.UnitPrice
+	}
+	return value
+}
+
+func totalQty(items []entity.ShipmentItem) uint32 {
+	var qty uint32
+	for _, item := range items {
+		qty += item.Qty
+	}
+	return qty
+}
+
+// shopOrderDeliveryWindow is the delivery window promised when the order was placed.
+func shopOrderDeliveryWindow(so *entity.ShopOrder) *entity.DeliveryWindow {
+	if so.EstimatedDeliveryFrom == nil || so.EstimatedDeliveryTo == nil {
+		return nil
+	}
+	return &entity.DeliveryWindow{
+		From: so.EstimatedDeliveryFrom.Format("2006-01-02"),
+		To:   so.EstimatedDeliveryTo.Format("2006-01-02"),
+	}
+}
+
+func deliveryWindowDates(window *entity.DeliveryWindow) (*time.Time, *time.Time) {
+	from, err := time.ParseInLocation("2006-01-02", window.From, timeth.LoadLocation())
+	if err != nil {
+		return nil, nil
+	}
+	to, err := time.ParseInLocation("2006-01-02", window.To, timeth.LoadLocation())
+	if err != nil {
+		return nil, nil
+	}
+	return &from, &to
+}
+
+// destinationProvinceID is the province of the address the order ships to, when the
+// address still exists.
+func destinationProvinceID(order *entity.Order) *uint32 {
+	if order.Address == nil || order.Address.ProvinceID == 0 {
+		return nil
+	}
+	id := order.Address.ProvinceID
+	return &id
+}
+
+// estimateDelivery returns nil when the estimate cannot be made. A missing estimate
+// never blocks checkout or tracking.
+func (u *orderUsecase) estimateDelivery(ctx context.Context, req entity.DeliveryEstimateRequest) *entity.DeliveryWindow {
+	window, err := u.estimator.Estimate(ctx, req)
+	if err != nil {
+		log.Printf("[ERROR] Failed to estimate delivery for courier_id=%d: %v", req.CourierID, err)
+		return nil
+	}
+	return window
+}
+
 func createTimeline(logs []*entity.OrderLog) []entity.OrderTimelineItem {
 	if len(logs) == 0 {
 		return []entity.OrderTimelineItem{}
@@ -95,10 +282,12 @@ func createTimeline(logs []*entity.OrderLog) []entity.OrderTimelineItem {
 	timeline := make([]entity.OrderTimelineItem, 0, len(logs))
 	for _, log := range logs {
 		timeline = append(timeline, entity.OrderTimelineItem{
-			StatusID:  log.OrderStatusID,
-			Note:      log.Note,
-			CreatedAt: log.CreatedAt,
-			CreatedBy: log.CreatedBy,
+			StatusID:       log.OrderStatusID,
+			RefundID:       log.RefundID,
+			RefundStatusID: log.RefundStatusID,
+			Note:           log.Note,
+			CreatedAt:      log.CreatedAt,
+			CreatedBy:      log.CreatedBy,
 		})
 	}
 
@@ -136,16 +325,17 @@ func (u *orderUsecase) toOrderResponseWithTimeline(ctx context.Context, order *e
 
 func (u *orderUsecase) toShopOrderResponseWithTimeline(ctx context.Context, shopOrder *entity.ShopOrder) *entity.ShopOrderResponse {
 	resp := &entity.ShopOrderResponse{
-		ID:            shopOrder.ID,
-		OrderID:       shopOrder.OrderID,
-		OrderNumber:   shopOrder.OrderNumber,
-		OrderStatusID: shopOrder.OrderStatusID,
-		Subtotal:      shopOrder.Subtotal,
-		Shipping:      shopOrder.Shipping,
-		GrandTotal:    shopOrder.GrandTotal,
-		CreatedAt:     shopOrder.CreatedAt,
-		UpdatedAt:     shopOrder.UpdatedAt,
-		OrderItems:    make([]entity.OrderItemResponse, 0),
+		ID:                shopOrder.ID,
+		OrderID:           shopOrder.OrderID,
+		OrderNumber:       shopOrder.OrderNumber,
+		OrderStatusID:     shopOrder.OrderStatusID,
+		Subtotal:          shopOrder.Subtotal,
+		Shipping:          shopOrder.Shipping,
+		GrandTotal:        shopOrder.GrandTotal,
+		EstimatedDelivery: shopOrderDeliveryWindow(shopOrder),
+		CreatedAt:         shopOrder.CreatedAt,
+		UpdatedAt:         shopOrder.UpdatedAt,
+		OrderItems:        make([]entity.OrderItemResponse, 0),
 	}
 
 	if shopOrder.Shop.ID != uuid.Nil {
@@ -214,6 +404,16 @@ func (u *orderUsecase) AddItemToCart(ctx context.Context, userID uuid.UUID, req
 }
 
 func (u *orderUsecase) CreateOrderFromCart(ctx context.Context, userID uuid.UUID, req entity.CreateOrderRequest) (*entity.OrderResponse, error) {
+	if u.requireVerifiedEmail {
+		user, err := u.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user.EmailVerifiedAt == nil {
+			return nil, errmap.ErrEmailNotVerified
+		}
+	}
+
 	cart, err := u.repo.GetCartByUserID(ctx, userID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -241,6 +441,7 @@ func (u *orderUsecase) CreateOrderFromCart(ctx context.Context, userID uuid.UUID
 		PaymentMethodID: req.PaymentMethodID,
 	}
 
+	var destination *uint32
 	if addr, err := u.userRepo.GetAddressByID(ctx, addressID); err == nil && addr != nil {
 		order.ShippingName = addr.Name
 		order.ShippingPhone = addr.PhoneNumber
@@ -257,6 +458,9 @@ func (u *orderUsecase) CreateOrderFromCart(ctx context.Context, userID uuid.UUID
 			order.ShippingProvince = addr.Province.NameTH
 		}
 		order.ShippingZipcode = fmt.Sprintf("%d", addr.Zipcode)
+		if addr.ProvinceID != 0 {
+			destination = &addr.ProvinceID
+		}
 	}
 
 	shopItems := make(map[string][]*entity.CartItem)
@@ -322,6 +526,33 @@ func (u *orderUsecase) CreateOrderFromCart(ctx context.Context, userID uuid.UUID
 
 	order.GrandTotal = grandTotal
 
+	// Delivery estimates are best effort, so an order is still placed without them.
+	shops, err := u.shopRepo.GetShopsByIDs(ctx, shopIDs)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load shops for delivery estimates: %v", err)
+	}
+	shopsMap := make(map[uuid.UUID]*entity.Shop, len(shops))
+	for _, shop := range shops {
+		shopsMap[shop.ID] = shop
+	}
+
+	for _, so := range shopOrders {
+		shop, ok := shopsMap[so.ShopID]
+		if !ok {
+			continue
+		}
+		window := u.estimateDelivery(ctx, entity.DeliveryEstimateRequest{
+			CourierID:             shopCouriersMap[so.ShopID][0].CourierID,
+			OriginProvinceID:      shop.ProvinceID,
+			DestinationProvinceID: destination,
+			HandlingDays:          shop.HandlingDays,
+			From:                  timeth.Now(),
+		})
+		if window != nil {
+			so.EstimatedDeliveryFrom, so.EstimatedDeliveryTo = deliveryWindowDates(window)
+		}
+	}
+
 	transactionID := fmt.Sprintf("TXN-%d-%s", timeth.Now().Unix(), uuid.New().String()[:8])
 
 	expiresAt := timeth.Now().Add(24 * time.Hour)
@@ -333,7 +564,23 @@ func (u *orderUsecase) CreateOrderFromCart(ctx context.Context, userID uuid.UUID
 		ExpiresAt:       &expiresAt,
 	}
 
-	if err := u.repo.CreateFullOrder(ctx, order, shopOrders, orderItemsByShop, payment, cart.ID, userID); err != nil {
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.CreateFullOrder(ctx, order, shopOrders, orderItemsByShop, payment, cart.ID, userID); err != nil {
+			return err
+		}
+
+		shopOrderIDs := make([]uuid.UUID, 0, len(shopOrders))
+		for _, so := range shopOrders {
+			shopOrderIDs = append(shopOrderIDs, so.ID)
+		}
+		return u.publish(ctx, entity.EventOrderPlaced, entity.AggregateOrder, order.ID, entity.OrderPlacedPayload{
+			OrderID:      order.ID,
+			UserID:       userID,
+			ShopOrderIDs: shopOrderIDs,
+			GrandTotal:   order.GrandTotal,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -369,6 +616,7 @@ func (u *orderUsecase) ListOrders(ctx context.Context, userID uuid.UUID, req ent
 			ShippingProvince:    so.Order.ShippingProvince,
 			ShippingZipcode:     so.Order.ShippingZipcode,
 			PaymentMethodID:     so.Order.PaymentMethodID,
+			EstimatedDelivery:   shopOrderDeliveryWindow(so),
 			CreatedAt:           so.CreatedAt,
 			UpdatedAt:           so.UpdatedAt,
 			OrderItems:          make([]entity.OrderItemResponse, 0),
@@ -434,6 +682,7 @@ func (u *orderUsecase) GetOrder(ctx context.Context, userID uuid.UUID, shopOrder
 		ShippingProvince:    so.Order.ShippingProvince,
 		ShippingZipcode:     so.Order.ShippingZipcode,
 		PaymentMethodID:     so.Order.PaymentMethodID,
+		EstimatedDelivery:   shopOrderDeliveryWindow(so),
 		CreatedAt:           so.CreatedAt,
 		UpdatedAt:           so.UpdatedAt,
 		OrderItems:          make([]entity.OrderItemResponse, 0),
@@ -531,8 +780,20 @@ func (u *orderUsecase) CreateOrderPayment(ctx context.Context, userID uuid.UUID,
 	}
 
 	now := timeth.Now()
-	if err := u.repo.UpdatePaymentStatus(ctx, existingPayment.ID, entity.PaymentStatusProcessing, &now); err != nil {
-		return nil, fmt.Errorf("failed to update payment status: %w", err)
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdatePaymentStatus(ctx, existingPayment.ID, entity.PaymentStatusProcessing, &now); err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
+		return u.publish(ctx, entity.EventPaymentSubmitted, entity.AggregateOrder, orderID, entity.PaymentSubmittedPayload{
+			PaymentID:     existingPayment.ID,
+			OrderID:       orderID,
+			UserID:        userID,
+			TransactionID: existingPayment.TransactionID,
+			Amount:        existingPayment.Amount,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	updatedPayment, err := u.repo.GetPaymentByOrderID(ctx, orderID)
@@ -585,6 +846,7 @@ func (u *orderUsecase) ListShopOrders(ctx context.Context, userID uuid.UUID, req
 			ShippingProvince:    so.Order.ShippingProvince,
 			ShippingZipcode:     so.Order.ShippingZipcode,
 			PaymentMethodID:     so.Order.PaymentMethodID,
+			EstimatedDelivery:   shopOrderDeliveryWindow(so),
 			CreatedAt:           so.CreatedAt,
 			UpdatedAt:           so.UpdatedAt,
 			OrderItems:          make([]entity.OrderItemResponse, 0),
@@ -624,11 +886,11 @@ func (u *orderUsecase) GetShopOrder(ctx context.Context, userID uuid.UUID, shopO
 		return nil, errmap.ErrNotFound
 	}
 
-	shop, err := u.shopRepo.GetShopByID(ctx, so.ShopID)
+	member, err := u.shopRepo.GetShopMember(ctx, so.ShopID, userID)
 	if err != nil {
 		return nil, err
 	}
-	if shop.UserID != userID {
+	if member == nil {
 		return nil, errmap.ErrForbidden
 	}
 
@@ -657,34 +919,59 @@ func (u *orderUsecase) UpdateShopOrderStatus(ctx context.Context, userID uuid.UU
 	if err != nil {
 		return err
 	}
-	shop, err := u.shopRepo.GetShopByID(ctx, so.ShopID)
+	member, err := u.shopRepo.GetShopMember(ctx, so.ShopID, userID)
 	if err != nil {
 		return err
 	}
-	if shop.UserID != userID {
+	if !member.Can(entity.ShopPermissionFulfilOrders) {
 		return errmap.ErrForbidden
 	}
 
-	if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, *req.OrderStatusID); err != nil {
-		return err
-	}
-
 	if statusID == entity.OrderStatusDelivered {
-		if err := u.repo.UpdateShipmentStatusByShopOrderID(ctx, shopOrderID, entity.ShipmentStatusDelivered); err != nil {
-			log.Printf("[ERROR] Failed to update shipment status for shop_order_id=%s: %v", shopOrderID, err)
+		shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+		if err != nil {
+			return err
+		}
+		if len(unshippedItems(so, shipments)) > 0 {
+			return errmap.ErrOrderNotFullyShipped
 		}
 	}
 
-	now := timeth.Now()
-	orderLog := &entity.OrderLog{
-		OrderID:       so.OrderID,
-		ShopOrderID:   &shopOrderID,
-		OrderStatusID: statusID,
-		CreatedBy:     &userID,
-		CreatedAt:     &now,
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, statusID); err != nil {
+			return err
+		}
+
+		now := timeth.Now()
+		orderLog := &entity.OrderLog{
+			OrderID:       so.OrderID,
+			ShopOrderID:   &shopOrderID,
+			OrderStatusID: statusID,
+			CreatedBy:     &userID,
+			CreatedAt:     &now,
+		}
+		if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		switch statusID {
+		case entity.OrderStatusDelivered:
+			return u.publish(ctx, entity.EventShopOrderDelivered, entity.AggregateShopOrder, shopOrderID, entity.ShopOrderDeliveredPayload{
+				ShopOrderPayload: outbox.ShopOrderPayload(so),
+			})
+		case entity.OrderStatusCompleted:
+			return u.publish(ctx, entity.EventShopOrderCompleted, entity.AggregateShopOrder, shopOrderID, entity.ShopOrderCompletedPayload{
+				ShopOrderPayload: outbox.ShopOrderPayload(so),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
-		log.Printf("[ERROR] Failed to create order log for shop_order_id=%s, order_id=%s: %v", shopOrderID, so.OrderID, err)
+
+	if statusID == entity.OrderStatusDelivered {
+		u.recordShopDeliveredEvent(ctx, shopOrderID)
 	}
 
 	return nil
@@ -696,57 +983,213 @@ func (u *orderUsecase) CancelShopOrder(ctx context.Context, userID uuid.UUID, sh
 		return err
 	}
 
-	shop, err := u.shopRepo.GetShopByID(ctx, so.ShopID)
+	member, err := u.shopRepo.GetShopMember(ctx, so.ShopID, userID)
 	if err != nil {
 		return err
 	}
-	if shop.UserID != userID {
+	if !member.Can(entity.ShopPermissionFulfilOrders) {
 		return errmap.ErrForbidden
 	}
 
-	for _, oi := range so.OrderItems {
-		if err := u.productRepo.RestoreProductStock(ctx, oi.ProductID, oi.Qty); err != nil {
-			log.Printf("[ERROR] Failed to restore stock for product_id=%d, quantity=%d, shop_order_id=%s: %v", oi.ProductID, oi.Qty, shopOrderID, err)
-		}
+	if so.OrderStatusID == entity.OrderStatusCancelled {
+		return errmap.ErrCannotCancelOrder
 	}
 
-	if err := u.repo.CancelShopOrder(ctx, shopOrderID, req.Reason); err != nil {
+	payment, err := u.repo.GetPaymentByOrderID(ctx, so.OrderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return err
 	}
 
-	now := timeth.Now()
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var refund *entity.Refund
+		var err error
+		if isPrepaid(payment) {
+			refund, err = u.cancelPaidShopOrder(ctx, so, payment, req.Reason)
+		} else {
+			err = u.repo.CancelShopOrder(ctx, shopOrderID, req.Reason)
+		}
+		if err != nil {
+			return err
+		}
 
-	orderLog := &entity.OrderLog{
-		OrderID:       so.OrderID,
-		ShopOrderID:   &shopOrderID,
-		OrderStatusID: entity.OrderStatusCancelled,
-		Note:          req.Reason,
-		CreatedBy:     &userID,
-		CreatedAt:     &now,
-	}
-	if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
-		log.Printf("[ERROR] Failed to create order log for cancelled order, shop_order_id=%s, order_id=%s: %v", shopOrderID, so.OrderID, err)
+		now := timeth.Now()
+
+		orderLog := &entity.OrderLog{
+			OrderID:       so.OrderID,
+			ShopOrderID:   &shopOrderID,
+			OrderStatusID: entity.OrderStatusCancelled,
+			Note:          req.Reason,
+			CreatedBy:     &userID,
+			CreatedAt:     &now,
+		}
+		if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		if refund != nil {
+			refundLog := &entity.OrderLog{
+				OrderID:        so.OrderID,
+				ShopOrderID:    &shopOrderID,
+				OrderStatusID:  entity.OrderStatusCancelled,
+				RefundID:       &refund.ID,
+				RefundStatusID: &refund.RefundStatusID,
+				Note:           fmt.Sprintf("Automatic refund of %.2f created for cancelled order", refund.Amount),
+				CreatedBy:      &userID,
+				CreatedAt:      &now,
+			}
+			if err := u.repo.CreateOrderLog(ctx, refundLog); err != nil {
+				return fmt.Errorf("failed to create refund log: %w", err)
+			}
+		}
+
+		return u.publish(ctx, entity.EventShopOrderCancelled, entity.AggregateShopOrder, shopOrderID, entity.ShopOrderCancelledPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(so),
+			Reason:           req.Reason,
+			CancelledBy:      &userID,
+			Restock:          orderItemsStock(so.OrderItems),
+			Refund:           refundSummary(refund),
+		})
+	})
+	if err != nil {
+		return err
 	}
 
+	u.cancelCourierBooking(ctx, shopOrderID)
+
 	return nil
 }
 
+// orderItemsStock is the stock to put back for the items of a cancelled shop order.
+func orderItemsStock(items []entity.OrderItem) []entity.StockItem {
+	stock := make([]entity.StockItem, 0, len(items))
+	for _, oi := range items {
+		stock = append(stock, entity.StockItem{ProductID: oi.ProductID, Qty: oi.Qty})
+	}
+	return stock
+}
+
+// shipmentItemsStock is the stock to put back for returned or never-shipped items.
+func shipmentItemsStock(items []entity.ShipmentItem) []entity.StockItem {
+	stock := make([]entity.StockItem, 0, len(items))
+	for _, item := range items {
+		stock = append(stock, entity.StockItem{ProductID: item.OrderItem.ProductID, Qty: item.Qty})
+	}
+	return stock
+}
+
+func refundSummary(refund *entity.Refund) *entity.RefundSummary {
+	if refund == nil {
+		return nil
+	}
+	return &entity.RefundSummary{ID: refund.ID, RefundStatusID: refund.RefundStatusID, Amount: refund.Amount}
+}
+
+// cancelCourierBooking cancels the courier pickups booked for a cancelled shop order.
+// Parcels already picked up cannot be cancelled at the courier, so failures are only logged.
+func (u *orderUsecase) cancelCourierBooking(ctx context.Context, shopOrderID uuid.UUID) {
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shipments for cancelled shop_order_id=%s: %v", shopOrderID, err)
+		return
+	}
+
+	for _, shipment := range shipments {
+		if shipment.ProviderCode == nil ||
+			shipment.ShipmentStatusID == entity.ShipmentStatusDelivered ||
+			shipment.ShipmentStatusID == entity.ShipmentStatusReturnedToSender {
+			continue
+		}
+
+		provider, ok := u.couriers.Get(*shipment.ProviderCode)
+		if !ok {
+			continue
+		}
+
+		if err := provider.Cancel(ctx, shipment.TrackingNo); err != nil {
+			log.Printf("[ERROR] Failed to cancel courier booking for shop_order_id=%s, tracking_no=%s: %v", shopOrderID, shipment.TrackingNo, err)
+		}
+	}
+}
+
+// cancelPaidShopOrder cancels a shop order whose payment has already been captured and
+// creates an approved refund for the part of the shop order's share not refunded yet,
+// routed back through the refund method that matches how the buyer paid. It returns nil
+// when earlier refunds already cover the shop order.
+func (u *orderUsecase) cancelPaidShopOrder(ctx context.Context, so *entity.ShopOrder, payment *entity.Payment, reason string) (*entity.Refund, error) {
+	refund := newSystemRefund(so, payment, so.GrandTotal, fmt.Sprintf("Order cancelled: %s", reason))
+	created, err := u.repo.CancelShopOrderWithRefund(ctx, so.ID, reason, refund)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel paid shop order: %w", err)
+	}
+	if !created {
+		return nil, nil
+	}
+
+	return refund, nil
+}
+
+// newSystemRefund builds an approved refund routed back through the refund method that
+// matches how the buyer paid.
+func newSystemRefund(so *entity.ShopOrder, payment *entity.Payment, amount float64, reason string) *entity.Refund {
+	now := timeth.Now()
+	refundMethodID := entity.RefundMethodForPayment(payment.PaymentMethodID)
+	return &entity.Refund{
+		ShopOrderID:    so.ID,
+		PaymentID:      &payment.ID,
+		Amount:         amount,
+		RefundMethodID: &refundMethodID,
+		RefundStatusID: entity.RefundStatusApproved,
+		Reason:         reason,
+		Initiator:      entity.RefundInitiatorSystem,
+		ApprovedAt:     &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// isPrepaid reports whether the buyer's payment has been captured, so returned goods
+// must be refunded rather than simply not collected.
+func isPrepaid(payment *entity.Payment) bool {
+	return payment != nil && (payment.PaymentStatusID == entity.PaymentStatusCompleted || payment.PaymentStatusID == entity.PaymentStatusPartiallyRefunded)
+}
+
+// AddShipment sends a parcel with some or all of the unshipped items. The shop order
+// moves to SHIPPED once its parcels cover every item.
 func (u *orderUsecase) AddShipment(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID, req entity.AddShipmentRequest) (*entity.ShipmentResponse, error) {
 	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
 	if err != nil {
 		return nil, err
 	}
-	shop, err := u.shopRepo.GetShopByID(ctx, so.ShopID)
+	member, err := u.shopRepo.GetShopMember(ctx, so.ShopID, userID)
 	if err != nil {
 		return nil, err
 	}
-	if shop.UserID != userID {
+	if !member.Can(entity.ShopPermissionFulfilOrders) {
 		return nil, errmap.ErrForbidden
 	}
+	shop := &member.Shop
 
-	existingShipment, err := u.repo.GetShipmentByShopOrderID(ctx, shopOrderID)
-	if err == nil && existingShipment != nil {
-		return nil, errmap.ErrShipmentAlreadyExists
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	unshipped := unshippedItems(so, shipments)
+	if len(unshipped) == 0 {
+		return nil, errmap.ErrAllItemsShipped
+	}
+
+	items, err := parcelItems(unshipped, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	courier, err := u.repo.GetCourierByID(ctx, req.CourierID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrCourierNotFound
+		}
+		return nil, err
 	}
 
 	now := timeth.Now()
@@ -758,31 +1201,137 @@ func (u *orderUsecase) AddShipment(ctx context.Context, userID uuid.UUID, shopOr
 		ShippedAt:        &now,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		Events: []entity.ShipmentEvent{
+			{
+				ShipmentStatusID: entity.ShipmentStatusInTransit,
+				Source:           entity.ShipmentEventSourceShop,
+				Description:      "Shipment created",
+				OccurredAt:       now,
+				CreatedAt:        now,
+			},
+		},
+		Items: make([]entity.ShipmentItem, 0, len(items)),
+	}
+	for _, item := range items {
+		s.Items = append(s.Items, entity.ShipmentItem{OrderItemID: item.OrderItemID, Qty: item.Qty})
 	}
 
-	if err := u.repo.AddShipment(ctx, s); err != nil {
+	if req.TrackingNo == "" {
+		provider, ok := u.couriers.Get(courier.ProviderCode)
+		if !ok {
+			return nil, errmap.ErrTrackingNoRequired
+		}
+
+		booking, err := provider.BookPickup(ctx, newCourierBookingRequest(so, shop, items))
+		if err != nil {
+			log.Printf("[ERROR] Failed to book courier pickup for shop_order_id=%s, courier_id=%d: %v", shopOrderID, courier.ID, err)
+			return nil, errmap.ErrCourierBookingFailed
+		}
+
+		s.TrackingNo = booking.TrackingNo
+		s.ProviderCode = &courier.ProviderCode
+		s.Events[0].Description = fmt.Sprintf("Pickup booked with %s", courier.Name)
+	}
+
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.AddShipment(ctx, s); err != nil {
+			return err
+		}
+
+		orderLog := &entity.OrderLog{
+			OrderID:       so.OrderID,
+			ShopOrderID:   &shopOrderID,
+			OrderStatusID: entity.OrderStatusShipped,
+			CreatedBy:     &userID,
+			CreatedAt:     &now,
+		}
+
+		fullyShipped := totalQty(items) == totalQty(unshipped)
+		if fullyShipped {
+			if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusShipped); err != nil {
+				return fmt.Errorf("failed to update shop order status to shipped: %w", err)
+			}
+			if len(shipments) > 0 {
+				orderLog.Note = fmt.Sprintf("All items shipped in %d parcels", len(shipments)+1)
+			}
+		} else {
+			orderLog.OrderStatusID = so.OrderStatusID
+			orderLog.Note = fmt.Sprintf("Parcel %s shipped with %s, other items still to ship", s.TrackingNo, courier.Name)
+		}
+
+		if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		if !fullyShipped {
+			return nil
+		}
+		return u.publish(ctx, entity.EventShopOrderShipped, entity.AggregateShopOrder, shopOrderID, entity.ShopOrderShippedPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(so),
+			Parcels:          len(shipments) + 1,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusShipped); err != nil {
-		log.Printf("[WARN] Failed to update shop order status to shipped (status=3) for shop_order_id=%s: %v", shopOrderID, err)
+	s.Courier = *courier
+	for i := range s.Items {
+		s.Items[i].OrderItem = items[i].OrderItem
 	}
+	return mapToShipmentResponse(s), nil
+}
 
-	orderLog := &entity.OrderLog{
-		OrderID:       so.OrderID,
-		ShopOrderID:   &shopOrderID,
-		OrderStatusID: entity.OrderStatusShipped,
-		CreatedBy:     &userID,
-		CreatedAt:     &now,
+// newCourierBookingRequest builds a pickup booking for a parcel from the shop's address
+// and the shipping address snapshotted on the order.
+func newCourierBookingRequest(so *entity.ShopOrder, shop *entity.Shop, items []entity.ShipmentItem) entity.CourierBookingRequest {
+	order := so.Order
+
+	return entity.CourierBookingRequest{
+		Reference:   so.ID.String(),
+		OrderNumber: so.OrderNumber,
+		Sender: entity.CourierParty{
+			Name:    shop.Name,
+			Address: shop.Address,
+		},
+		Recipient: courierRecipient(order.ShippingName, order.ShippingPhone, order.ShippingLine1, order.ShippingLine2,
+			order.ShippingSubDistrict, order.ShippingDistrict, order.ShippingProvince, order.ShippingZipcode),
+		ItemCount: totalQty(items),
 	}
-	if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
-		log.Printf("[ERROR] Failed to create order log for shipment, shop_order_id=%s, order_id=%s, tracking_no=%s: %v", shopOrderID, so.OrderID, req.TrackingNo, err)
+}
+
+func courierRecipient(name, phone, line1, line2, subDistrict, district, province, zipcode string) entity.CourierParty {
+	address := line1
+	if line2 != "" {
+		address += " " + line2
 	}
 
-	return mapToShipmentResponse(s), nil
+	return entity.CourierParty{
+		Name:     name,
+		Phone:    phone,
+		Address:  fmt.Sprintf("%s %s %s", address, subDistrict, district),
+		Province: province,
+		Zipcode:  zipcode,
+	}
+}
+
+// parcelRecipient is where a parcel is delivered: the latest address the buyer gave when
+// scheduling a reattempt, or the order's shipping address.
+func parcelRecipient(so *entity.ShopOrder, shipment *entity.Shipment) entity.CourierParty {
+	for i := len(shipment.Reattempts) - 1; i >= 0; i-- {
+		r := shipment.Reattempts[i]
+		if r.AddressID != nil {
+			return courierRecipient(r.ShippingName, r.ShippingPhone, r.ShippingLine1, r.ShippingLine2,
+				r.ShippingSubDistrict, r.ShippingDistrict, r.ShippingProvince, r.ShippingZipcode)
+		}
+	}
+
+	order := so.Order
+	return courierRecipient(order.ShippingName, order.ShippingPhone, order.ShippingLine1, order.ShippingLine2,
+		order.ShippingSubDistrict, order.ShippingDistrict, order.ShippingProvince, order.ShippingZipcode)
 }
 
-func (u *orderUsecase) GetShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShipmentResponse, error) {
+func (u *orderUsecase) GetShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShopOrderTrackingResponse, error) {
 	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
 	if err != nil {
 		return nil, err
@@ -792,35 +1341,182 @@ func (u *orderUsecase) GetShipmentTracking(ctx context.Context, userID uuid.UUID
 		return nil, errmap.ErrForbidden
 	}
 
-	shipment, err := u.repo.GetShipmentByShopOrderID(ctx, shopOrderID)
+	return u.shopOrderTracking(ctx, so)
+}
+
+func (u *orderUsecase) GetShopShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShopOrderTrackingResponse, error) {
+	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
 	if err != nil {
 		return nil, err
 	}
 
-	return mapToShipmentResponse(shipment), nil
+	member, err := u.shopRepo.GetShopMember(ctx, so.ShopID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member == nil {
+		return nil, errmap.ErrForbidden
+	}
+
+	return u.shopOrderTracking(ctx, so)
 }
 
-func (u *orderUsecase) GetShopShipmentTracking(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) (*entity.ShipmentResponse, error) {
-	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
+// shopOrderTracking syncs booked parcels with their courier and lists every parcel of
+// the shop order along with the items still waiting to ship.
+func (u *orderUsecase) shopOrderTracking(ctx context.Context, so *entity.ShopOrder) (*entity.ShopOrderTrackingResponse, error) {
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, so.ID)
 	if err != nil {
 		return nil, err
 	}
+	if len(shipments) == 0 {
+		return nil, errmap.ErrShipmentNotFound
+	}
+
+	changed := false
+	for _, shipment := range shipments {
+		if u.syncShipment(ctx, shipment) {
+			changed = true
+		}
+	}
+	if changed {
+		if refreshed, err := u.repo.GetShipmentsByShopOrderID(ctx, so.ID); err == nil {
+			shipments = refreshed
+		}
+	}
 
-	shop, err := u.shopRepo.GetShopByID(ctx, so.ShopID)
+	unshipped := unshippedItems(so, shipments)
+	resp := &entity.ShopOrderTrackingResponse{
+		ShopOrderID:       so.ID,
+		FullyShipped:      len(unshipped) == 0,
+		EstimatedDelivery: shopOrderDeliveryWindow(so),
+		Shipments:         make([]entity.ShipmentResponse, 0, len(shipments)),
+		UnshippedItems:    make([]entity.ShipmentItemResponse, 0, len(unshipped)),
+	}
+	for _, shipment := range shipments {
+		shipmentResp := mapToShipmentResponse(shipment)
+		if shipment.ShipmentStatusID != entity.ShipmentStatusDelivered && shipment.ShipmentStatusID != entity.ShipmentStatusReturnedToSender {
+			shippedAt := shipment.CreatedAt
+			if shipment.ShippedAt != nil {
+				shippedAt = *shipment.ShippedAt
+			}
+			shipmentResp.EstimatedDelivery = u.estimateDelivery(ctx, entity.DeliveryEstimateRequest{
+				CourierID:             shipment.CourierID,
+				OriginProvinceID:      so.Shop.ProvinceID,
+				DestinationProvinceID: destinationProvinceID(&so.Order),
+				From:                  shippedAt,
+			})
+		}
+		resp.Shipments = append(resp.Shipments, *shipmentResp)
+	}
+	for _, item := range unshipped {
+		resp.UnshippedItems = append(resp.UnshippedItems, mapToShipmentItemResponse(item))
+	}
+
+	return resp, nil
+}
+
+func (u *orderUsecase) GetShippingLabel(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) ([]byte, error) {
+	return u.GetShippingLabels(ctx, userID, []uuid.UUID{shopOrderID})
+}
+
+// GetShippingLabels renders one PDF with a label and packing slip per parcel, for each
+// shop order in the order requested. Every shop order must belong to the caller's shop
+// and have at least one shipment.
+func (u *orderUsecase) GetShippingLabels(ctx context.Context, userID uuid.UUID, shopOrderIDs []uuid.UUID) ([]byte, error) {
+	labels := make([]entity.ShippingLabel, 0, len(shopOrderIDs))
+	seen := make(map[uuid.UUID]bool, len(shopOrderIDs))
+	for _, id := range shopOrderIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		orderLabels, err := u.buildShippingLabels(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, orderLabels...)
+	}
+
+	var buf bytes.Buffer
+	if err := shiplabel.Render(&buf, labels); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (u *orderUsecase) buildShippingLabels(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) ([]entity.ShippingLabel, error) {
+	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
 	if err != nil {
 		return nil, err
 	}
 
-	if shop.UserID != userID {
+	member, err := u.shopRepo.GetShopMember(ctx, so.ShopID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Can(entity.ShopPermissionFulfilOrders) {
 		return nil, errmap.ErrForbidden
 	}
+	shop := &member.Shop
+
+	if so.OrderStatusID == entity.OrderStatusCancelled {
+		return nil, errmap.ErrLabelUnavailable
+	}
 
-	shipment, err := u.repo.GetShipmentByShopOrderID(ctx, shopOrderID)
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
 	if err != nil {
 		return nil, err
 	}
+	if len(shipments) == 0 {
+		return nil, errmap.ErrShipmentNotFound
+	}
+
+	numbered := len(shipments) > 1 || len(unshippedItems(so, shipments)) > 0
+	labels := make([]entity.ShippingLabel, 0, len(shipments))
+	for i, shipment := range shipments {
+		booking := newCourierBookingRequest(so, shop, shipment.Items)
+		label := entity.ShippingLabel{
+			OrderNumber: so.OrderNumber,
+			CourierName: shipment.Courier.Name,
+			TrackingNo:  shipment.TrackingNo,
+			Sender:      booking.Sender,
+			Recipient:   parcelRecipient(so, shipment),
+			Items:       make([]entity.ShippingLabelItem, 0, len(shipment.Items)),
+			Subtotal:    so.Subtotal,
+			Shipping:    so.Shipping,
+			GrandTotal:  so.GrandTotal,
+			OrderedAt:   so.CreatedAt,
+		}
+		if numbered {
+			label.ParcelNo = i + 1
+		}
+
+		for _, si := range shipment.Items {
+			label.Items = append(label.Items, entity.ShippingLabelItem{
+				Name:      si.OrderItem.Product.Name,
+				Qty:       si.Qty,
+				UnitPrice: si.OrderItem.UnitPrice,
+				Subtotal:  float64(si.Qty) * si.OrderItem.UnitPrice,
+			})
+		}
 
-	return mapToShipmentResponse(shipment), nil
+		if shipment.ProviderCode != nil {
+			if provider, ok := u.couriers.Get(*shipment.ProviderCode); ok {
+				courierLabel, err := provider.GetLabel(ctx, shipment.TrackingNo)
+				if err != nil {
+					log.Printf("[ERROR] Failed to get courier label for shop_order_id=%s, tracking_no=%s: %v", shopOrderID, shipment.TrackingNo, err)
+				} else {
+					label.SortCode = courierLabel.SortCode
+				}
+			}
+		}
+
+		labels = append(labels, label)
+	}
+
+	return labels, nil
 }
 
 func (u *orderUsecase) ApproveOrder(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID) error {
@@ -845,22 +1541,578 @@ func (u *orderUsecase) ApproveOrder(ctx context.Context, userID uuid.UUID, shopO
 		return fmt.Errorf("can only approve orders with DELIVERED status, current status: %d", shopOrder.OrderStatusID)
 	}
 
-	if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusCompleted); err != nil {
-		return err
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusCompleted); err != nil {
+			return err
+		}
+
+		now := timeth.Now()
+		orderLog := &entity.OrderLog{
+			OrderID:       shopOrder.OrderID,
+			ShopOrderID:   &shopOrderID,
+			OrderStatusID: entity.OrderStatusCompleted,
+			Note:          "Customer confirmed receipt of goods",
+			CreatedBy:     &userID,
+			CreatedAt:     &now,
+		}
+		if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		return u.publish(ctx, entity.EventShopOrderCompleted, entity.AggregateShopOrder, shopOrderID, entity.ShopOrderCompletedPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(shopOrder),
+		})
+	})
+}
+
+// ScheduleDeliveryReattempt lets the buyer ask for another delivery of a parcel the
+// courier failed to deliver, optionally to a corrected address. The parcel goes back
+// in transit until the courier reports the next attempt.
+func (u *orderUsecase) ScheduleDeliveryReattempt(ctx context.Context, userID uuid.UUID, shopOrderID uuid.UUID, shipmentID uuid.UUID, req entity.DeliveryReattemptRequest) (*entity.ShipmentResponse, error) {
+	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if so.Order.UserID != userID {
+		return nil, errmap.ErrForbidden
+	}
+
+	if so.OrderStatusID == entity.OrderStatusCancelled {
+		return nil, errmap.ErrReattemptNotAllowed
 	}
 
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var shipment *entity.Shipment
+	for _, s := range shipments {
+		if s.ID == shipmentID {
+			shipment = s
+			break
+		}
+	}
+	if shipment == nil {
+		return nil, errmap.ErrShipmentNotFound
+	}
+
+	if shipment.ShipmentStatusID != entity.ShipmentStatusFailedDelivery {
+		return nil, errmap.ErrReattemptNotAllowed
+	}
+
+	scheduledDate, err := time.ParseInLocation("2006-01-02", req.ScheduledDate, timeth.LoadLocation())
+	if err != nil {
+		return nil, errmap.ErrInvalidReattemptDate
+	}
 	now := timeth.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !scheduledDate.After(today) || scheduledDate.After(today.AddDate(0, 0, 7)) {
+		return nil, errmap.ErrInvalidReattemptDate
+	}
+
+	reattempt := &entity.DeliveryReattempt{
+		ShipmentID:    shipment.ID,
+		RequestedBy:   userID,
+		ScheduledDate: scheduledDate,
+		Note:          req.Note,
+		CreatedAt:     now,
+	}
+
+	if req.AddressID != nil {
+		addr, err := u.userRepo.GetAddressByID(ctx, *req.AddressID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmap.ErrAddressNotFound
+			}
+			return nil, err
+		}
+		if addr.UserID != userID {
+			return nil, errmap.ErrForbidden
+		}
+
+		reattempt.AddressID = &addr.ID
+		reattempt.ShippingName = addr.Name
+		reattempt.ShippingPhone = addr.PhoneNumber
+		reattempt.ShippingLine1 = addr.Line1
+		reattempt.ShippingLine2 = addr.Line2
+		reattempt.ShippingSubDistrict = addr.SubDistrict.NameTH
+		reattempt.ShippingDistrict = addr.District.NameTH
+		reattempt.ShippingProvince = addr.Province.NameTH
+		reattempt.ShippingZipcode = fmt.Sprintf("%d", addr.Zipcode)
+	}
+
+	if err := u.repo.CreateDeliveryReattempt(ctx, reattempt); err != nil {
+		return nil, err
+	}
+
+	description := fmt.Sprintf("Redelivery scheduled by buyer for %s", req.ScheduledDate)
+	if reattempt.AddressID != nil {
+		description += " to a new address"
+	}
+
+	event := &entity.ShipmentEvent{
+		ShipmentID:       shipment.ID,
+		ShipmentStatusID: entity.ShipmentStatusInTransit,
+		Source:           entity.ShipmentEventSourceBuyer,
+		Description:      description,
+		OccurredAt:       now,
+		CreatedAt:        now,
+	}
+	if _, err := u.repo.RecordShipmentEvent(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to record shipment event: %w", err)
+	}
+
 	orderLog := &entity.OrderLog{
-		OrderID:       shopOrder.OrderID,
+		OrderID:       so.OrderID,
 		ShopOrderID:   &shopOrderID,
-		OrderStatusID: entity.OrderStatusCompleted,
-		Note:          "Customer confirmed receipt of goods",
+		OrderStatusID: so.OrderStatusID,
+		Note:          fmt.Sprintf("%s (tracking no. %s)", description, shipment.TrackingNo),
 		CreatedBy:     &userID,
 		CreatedAt:     &now,
 	}
 	if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
-		log.Printf("[ERROR] Failed to create order log for approved order, shop_order_id=%s, order_id=%s: %v", shopOrderID, shopOrder.OrderID, err)
+		log.Printf("[ERROR] Failed to create order log for delivery reattempt, shop_order_id=%s, order_id=%s: %v", shopOrderID, so.OrderID, err)
 	}
 
-	return nil
+	shipments, err = u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range shipments {
+		if s.ID == shipmentID {
+			return mapToShipmentResponse(s), nil
+		}
+	}
+	return nil, errmap.ErrShipmentNotFound
+}
+
+// recordShopDeliveredEvent adds a DELIVERED event to every parcel still on its way when
+// the shop marks an order as delivered by hand.
+func (u *orderUsecase) recordShopDeliveredEvent(ctx context.Context, shopOrderID uuid.UUID) {
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shipments for shop_order_id=%s: %v", shopOrderID, err)
+		return
+	}
+
+	now := timeth.Now()
+	for _, shipment := range shipments {
+		if shipment.ShipmentStatusID == entity.ShipmentStatusDelivered || shipment.ShipmentStatusID == entity.ShipmentStatusReturnedToSender {
+			continue
+		}
+
+		event := &entity.ShipmentEvent{
+			ShipmentID:       shipment.ID,
+			ShipmentStatusID: entity.ShipmentStatusDelivered,
+			Source:           entity.ShipmentEventSourceShop,
+			Description:      "Marked as delivered by shop",
+			OccurredAt:       now,
+			CreatedAt:        now,
+		}
+		if _, err := u.repo.RecordShipmentEvent(ctx, event); err != nil {
+			log.Printf("[ERROR] Failed to update shipment status for shop_order_id=%s, shipment_id=%s: %v", shopOrderID, shipment.ID, err)
+		}
+	}
+}
+
+func (u *orderUsecase) HandleCourierWebhook(ctx context.Context, courierID uint32, signature string, payload []byte) error {
+	courier, err := u.repo.GetCourierByID(ctx, courierID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrCourierNotFound
+		}
+		return err
+	}
+
+	if !validCourierSignature(courier.WebhookSecret, signature, payload) {
+		return errmap.ErrInvalidWebhookSignature
+	}
+
+	var req entity.CourierWebhookRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return errmap.ErrInvalidRequest
+	}
+	if req.EventID == "" || req.TrackingNo == "" {
+		return errmap.ErrInvalidRequest
+	}
+
+	statusID, ok := entity.ShipmentStatusByCode[req.Status]
+	if !ok {
+		return errmap.ErrInvalidShipmentStatus
+	}
+
+	shipment, err := u.repo.GetShipmentByTrackingNo(ctx, courierID, req.TrackingNo)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrShipmentNotFound
+		}
+		return err
+	}
+
+	_, err = u.applyCourierEvent(ctx, shipment, courier.Name, entity.CourierTrackingEvent{
+		EventID:     req.EventID,
+		Status:      req.Status,
+		Description: req.Description,
+		Location:    req.Location,
+		OccurredAt:  req.OccurredAt,
+	}, statusID)
+	return err
+}
+
+// applyCourierEvent records a tracking event reported by the courier, either through a
+// webhook or by polling its provider, and moves the shop order along when the courier
+// reports delivery, a failed attempt or a return. It reports whether the shipment
+// status changed.
+func (u *orderUsecase) applyCourierEvent(ctx context.Context, shipment *entity.Shipment, courierName string, ce entity.CourierTrackingEvent, statusID uint32) (bool, error) {
+	now := timeth.Now()
+	occurredAt := ce.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+
+	eventID := ce.EventID
+	event := &entity.ShipmentEvent{
+		ShipmentID:       shipment.ID,
+		ShipmentStatusID: statusID,
+		Source:           entity.ShipmentEventSourceCourier,
+		ExternalEventID:  &eventID,
+		Description:      ce.Description,
+		Location:         ce.Location,
+		OccurredAt:       occurredAt,
+		CreatedAt:        now,
+	}
+
+	applied, err := u.repo.RecordShipmentEvent(ctx, event)
+	if err != nil {
+		return false, fmt.Errorf("failed to record shipment event: %w", err)
+	}
+	if !applied {
+		return false, nil
+	}
+
+	switch statusID {
+	case entity.ShipmentStatusDelivered:
+		u.markShopOrderDelivered(ctx, shipment.ShopOrderID, courierName)
+	case entity.ShipmentStatusFailedDelivery:
+		u.handleFailedDelivery(ctx, shipment, ce.Description)
+	case entity.ShipmentStatusReturnedToSender:
+		u.handleReturnedParcel(ctx, shipment, courierName)
+	}
+
+	return true, nil
+}
+
+// handleFailedDelivery tells the buyer through the order timeline that the courier could
+// not deliver a parcel, so they can schedule a reattempt or correct the address. Once
+// maxDeliveryAttempts have failed, the parcel is returned to the shop.
+func (u *orderUsecase) handleFailedDelivery(ctx context.Context, shipment *entity.Shipment, reason string) {
+	shipment.FailedAttempts++
+
+	so, err := u.repo.GetShopOrderByID(ctx, shipment.ShopOrderID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shop order for failed delivery, shop_order_id=%s: %v", shipment.ShopOrderID, err)
+		return
+	}
+
+	if reason == "" {
+		reason = "recipient not available"
+	}
+
+	now := timeth.Now()
+	note := fmt.Sprintf("Delivery attempt %d of %d failed for tracking no. %s (%s). Schedule a reattempt or update the delivery address.",
+		shipment.FailedAttempts, maxDeliveryAttempts, shipment.TrackingNo, reason)
+	if shipment.FailedAttempts >= maxDeliveryAttempts {
+		note = fmt.Sprintf("Delivery failed %d times for tracking no. %s (%s). The parcel is being returned to the shop.",
+			shipment.FailedAttempts, shipment.TrackingNo, reason)
+	}
+
+	orderLog := &entity.OrderLog{
+		OrderID:       so.OrderID,
+		ShopOrderID:   &so.ID,
+		OrderStatusID: so.OrderStatusID,
+		Note:          note,
+		CreatedAt:     &now,
+	}
+	if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+		log.Printf("[ERROR] Failed to create order log for failed delivery, shop_order_id=%s, order_id=%s: %v", so.ID, so.OrderID, err)
+	}
+
+	if shipment.FailedAttempts < maxDeliveryAttempts {
+		return
+	}
+
+	event := &entity.ShipmentEvent{
+		ShipmentID:       shipment.ID,
+		ShipmentStatusID: entity.ShipmentStatusReturnedToSender,
+		Source:           entity.ShipmentEventSourceSystem,
+		Description:      fmt.Sprintf("Returning to shop after %d failed delivery attempts", shipment.FailedAttempts),
+		OccurredAt:       now,
+		CreatedAt:        now,
+	}
+	applied, err := u.repo.RecordShipmentEvent(ctx, event)
+	if err != nil {
+		log.Printf("[ERROR] Failed to return shipment to sender for shop_order_id=%s, shipment_id=%s: %v", so.ID, shipment.ID, err)
+		return
+	}
+	if applied {
+		u.handleReturnedParcel(ctx, shipment, shipment.Courier.Name)
+	}
+}
+
+// handleReturnedParcel puts the items of a parcel returned to the shop back in stock and
+// refunds them when the order was prepaid. Once every parcel has come back, the shop
+// order is cancelled and the rest of what the buyer paid, including shipping, is
+// refunded. Unpaid cash on delivery orders are simply cancelled.
+func (u *orderUsecase) handleReturnedParcel(ctx context.Context, shipment *entity.Shipment, courierName string) {
+	so, err := u.repo.GetShopOrderByID(ctx, shipment.ShopOrderID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shop order for returned shipment, shop_order_id=%s: %v", shipment.ShopOrderID, err)
+		return
+	}
+	if so.OrderStatusID == entity.OrderStatusCancelled {
+		return
+	}
+
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, so.ID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shipments for returned shipment, shop_order_id=%s: %v", so.ID, err)
+		return
+	}
+
+	var parcel *entity.Shipment
+	allReturned := true
+	for _, s := range shipments {
+		if s.ID == shipment.ID {
+			parcel = s
+		}
+		if s.ShipmentStatusID != entity.ShipmentStatusReturnedToSender {
+			allReturned = false
+		}
+	}
+	if parcel == nil {
+		log.Printf("[ERROR] Returned shipment_id=%s not found on shop_order_id=%s", shipment.ID, so.ID)
+		return
+	}
+
+	payment, err := u.repo.GetPaymentByOrderID(ctx, so.OrderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("[ERROR] Failed to get payment for returned shipment, shop_order_id=%s: %v", so.ID, err)
+		return
+	}
+
+	restock := parcel.Items
+	refundAmount := itemsValue(parcel.Items)
+	if allReturned {
+		unshipped := unshippedItems(so, shipments)
+		restock = append(append([]entity.ShipmentItem{}, restock...), unshipped...)
+		refundAmount += itemsValue(unshipped) + so.Shipping
+	}
+
+	reason := fmt.Sprintf("Parcel %s returned to sender", parcel.TrackingNo)
+	var refund *entity.Refund
+	if isPrepaid(payment) {
+		refund = newSystemRefund(so, payment, refundAmount, reason)
+	}
+
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		orderStatusID := so.OrderStatusID
+		switch {
+		case allReturned && refund != nil:
+			var created bool
+			created, err = u.repo.CancelShopOrderWithRefund(ctx, so.ID, reason, refund)
+			if !created {
+				refund = nil
+			}
+		case allReturned:
+			err = u.repo.CancelShopOrder(ctx, so.ID, reason)
+		case refund != nil:
+			err = u.repo.CreateShopOrderRefund(ctx, refund)
+		}
+		if err != nil {
+			return err
+		}
+		if allReturned {
+			orderStatusID = entity.OrderStatusCancelled
+		}
+
+		now := timeth.Now()
+		note := fmt.Sprintf("Parcel %s was returned to the shop by %s", parcel.TrackingNo, courierName)
+		if allReturned {
+			note = fmt.Sprintf("Order cancelled: parcel %s was returned to the shop by %s", parcel.TrackingNo, courierName)
+		}
+		orderLog := &entity.OrderLog{
+			OrderID:       so.OrderID,
+			ShopOrderID:   &so.ID,
+			OrderStatusID: orderStatusID,
+			Note:          note,
+			CreatedAt:     &now,
+		}
+		if refund != nil {
+			orderLog.RefundID = &refund.ID
+			orderLog.RefundStatusID = &refund.RefundStatusID
+			orderLog.Note += fmt.Sprintf(", refund of %.2f created", refund.Amount)
+		}
+		if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		err = u.publish(ctx, entity.EventParcelReturned, entity.AggregateShipment, parcel.ID, entity.ParcelReturnedPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(so),
+			ShipmentID:       parcel.ID,
+			TrackingNo:       parcel.TrackingNo,
+			Restock:          shipmentItemsStock(restock),
+			Refund:           refundSummary(refund),
+		})
+		if err != nil || !allReturned {
+			return err
+		}
+
+		// The returned parcel's event already puts its stock back.
+		return u.publish(ctx, entity.EventShopOrderCancelled, entity.AggregateShopOrder, so.ID, entity.ShopOrderCancelledPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(so),
+			Reason:           reason,
+			Restock:          []entity.StockItem{},
+			Refund:           refundSummary(refund),
+		})
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to settle returned shipment_id=%s for shop_order_id=%s: %v", parcel.ID, so.ID, err)
+		return
+	}
+
+	if !allReturned {
+		u.markShopOrderDelivered(ctx, so.ID, courierName)
+	}
+}
+
+// syncShipment pulls tracking events from the provider a shipment was booked with.
+// Shipments with hand-entered tracking numbers only change through webhooks and the
+// shop, so they are left alone. It reports whether the shipment status changed.
+func (u *orderUsecase) syncShipment(ctx context.Context, shipment *entity.Shipment) bool {
+	if shipment.ProviderCode == nil {
+		return false
+	}
+
+	provider, ok := u.couriers.Get(*shipment.ProviderCode)
+	if !ok {
+		return false
+	}
+
+	events, err := provider.GetTracking(ctx, shipment.TrackingNo)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get courier tracking for shipment_id=%s, tracking_no=%s: %v", shipment.ID, shipment.TrackingNo, err)
+		return false
+	}
+
+	changed := false
+	for _, ce := range events {
+		statusID, ok := entity.ShipmentStatusByCode[ce.Status]
+		if !ok {
+			log.Printf("[ERROR] Unknown courier tracking status %q for shipment_id=%s", ce.Status, shipment.ID)
+			continue
+		}
+
+		applied, err := u.applyCourierEvent(ctx, shipment, shipment.Courier.Name, ce, statusID)
+		if err != nil {
+			log.Printf("[ERROR] Failed to apply courier tracking event %s for shipment_id=%s: %v", ce.EventID, shipment.ID, err)
+			continue
+		}
+		changed = changed || applied
+	}
+
+	return changed
+}
+
+// SyncShipmentTracking polls courier providers for every booked shipment still in
+// transit and returns how many shipments changed status.
+func (u *orderUsecase) SyncShipmentTracking(ctx context.Context) (int, error) {
+	const batchSize = 200
+	shipments, err := u.repo.ListShipmentsForTrackingSync(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, shipment := range shipments {
+		if u.syncShipment(ctx, shipment) {
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// markShopOrderDelivered moves a shipped shop order to DELIVERED once the courier has
+// delivered every parcel that was not returned to the shop.
+func (u *orderUsecase) markShopOrderDelivered(ctx context.Context, shopOrderID uuid.UUID, courierName string) {
+	so, err := u.repo.GetShopOrderByID(ctx, shopOrderID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shop order for delivered shipment, shop_order_id=%s: %v", shopOrderID, err)
+		return
+	}
+
+	if so.OrderStatusID != entity.OrderStatusShipped {
+		return
+	}
+
+	shipments, err := u.repo.GetShipmentsByShopOrderID(ctx, shopOrderID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to get shipments for delivered shipment, shop_order_id=%s: %v", shopOrderID, err)
+		return
+	}
+	delivered := false
+	for _, shipment := range shipments {
+		switch shipment.ShipmentStatusID {
+		case entity.ShipmentStatusDelivered:
+			delivered = true
+		case entity.ShipmentStatusReturnedToSender:
+		default:
+			return
+		}
+	}
+	if !delivered {
+		return
+	}
+
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusDelivered); err != nil {
+			return fmt.Errorf("failed to update shop order status to delivered: %w", err)
+		}
+
+		now := timeth.Now()
+		orderLog := &entity.OrderLog{
+			OrderID:       so.OrderID,
+			ShopOrderID:   &shopOrderID,
+			OrderStatusID: entity.OrderStatusDelivered,
+			Note:          fmt.Sprintf("Delivered (reported by %s)", courierName),
+			CreatedAt:     &now,
+		}
+		if err := u.repo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		return u.publish(ctx, entity.EventShopOrderDelivered, entity.AggregateShopOrder, shopOrderID, entity.ShopOrderDeliveredPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(so),
+		})
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to mark shop_order_id=%s as delivered: %v", shopOrderID, err)
+	}
+}
+
+// validCourierSignature checks the hex HMAC-SHA256 of the payload against the
+// courier's webhook secret. Couriers without a secret cannot send webhooks.
+func validCourierSignature(secret string, signature string, payload []byte) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256=")))
 }
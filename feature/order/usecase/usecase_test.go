@@ -2,6 +2,10 @@ package usecase
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -12,9 +16,19 @@ import (
 
 	"ecommerce-go-api/domain/mock"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/feature/courier/provider"
 	"ecommerce-go-api/internal/errmap"
 )
 
+// passThroughTx runs transactional work directly, as these tests have no database.
+func passThroughTx(ctrl *gomock.Controller) *mock.MockTransactor {
+	tx := mock.NewMockTransactor(ctrl)
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+	return tx
+}
+
 func TestCreateOrderFromCart_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -24,8 +38,11 @@ func TestCreateOrderFromCart_Success(t *testing.T) {
 	mockShopRepo := mock.NewMockShopRepository(ctrl)
 	mockProductRepo := mock.NewMockProductRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
 
-	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo)
+	mockEstimator := mock.NewMockDeliveryEstimator(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mockEstimator, mockOutboxRepo, passThroughTx(ctrl), false)
 
 	// Test data
 	ctx := context.Background()
@@ -104,9 +121,20 @@ func TestCreateOrderFromCart_Success(t *testing.T) {
 		Return(shopCouriers, nil).
 		Times(1)
 
+	mockShopRepo.EXPECT().
+		GetShopsByIDs(ctx, []uuid.UUID{shopID}).
+		Return([]*entity.Shop{{ID: shopID, HandlingDays: 1}}, nil).
+		Times(1)
+
+	mockEstimator.EXPECT().
+		Estimate(ctx, gomock.Any()).
+		Return(&entity.DeliveryWindow{From: "2026-01-07", To: "2026-01-09"}, nil).
+		Times(1)
+
 	mockOrderRepo.EXPECT().
 		CreateFullOrder(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), cart.ID, userID).
-		DoAndReturn(func(ctx context.Context, order *entity.Order, shopOrders []*entity.ShopOrder, orderItemsByShop map[string][]*entity.OrderItem, payment *entity.Payment, cartID int, uid uuid.UUID) error {
+		DoAndReturn(func(ctx context.Context, order *entity.Order, shopOrders []*entity.ShopOrder, orderItemsByShop map[string][]*entity.OrderItem, payment *entity.Payment, cartID uint32, uid uuid.UUID) error {
+			assert.Equal(t, "2026-01-07", shopOrders[0].EstimatedDeliveryFrom.Format("2006-01-02"))
 			order.ID = uuid.New()
 			for _, so := range shopOrders {
 				so.ID = uuid.New()
@@ -116,6 +144,11 @@ func TestCreateOrderFromCart_Success(t *testing.T) {
 		}).
 		Times(1)
 
+	mockOutboxRepo.EXPECT().
+		Add(ctx, gomock.Any()).
+		Return(nil).
+		Times(1)
+
 	mockOrderRepo.EXPECT().
 		GetOrderLogsByOrderID(ctx, gomock.Any()).
 		Return([]*entity.OrderLog{}, nil).
@@ -160,6 +193,56 @@ func TestCreateOrderFromCart_Success(t *testing.T) {
 	assert.Equal(t, "ปทุมวัน", result.ShippingSubDistrict)
 }
 
+func TestCreateOrderFromCart_ShopLookupFailureSkipsEstimate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	// The estimator has no expectations: without the shop there is nothing to estimate.
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mock.NewMockProductRepository(ctrl), mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	addressID := uint32(123)
+	shopID := uuid.New()
+	cart := &entity.Cart{ID: 1, UserID: userID}
+
+	mockOrderRepo.EXPECT().GetCartByUserID(ctx, userID).Return(cart, nil)
+	mockOrderRepo.EXPECT().ListCartItems(ctx, cart.ID).Return([]*entity.CartItem{{
+		ID:        1,
+		CartID:    cart.ID,
+		ProductID: 1,
+		Qty:       1,
+		Product:   entity.Product{ID: 1, Price: 100.0, ShopID: shopID, IsActive: true},
+	}}, nil)
+	mockUserRepo.EXPECT().GetAddressByID(ctx, addressID).Return(&entity.Address{ID: addressID, UserID: userID}, nil)
+	mockShopRepo.EXPECT().ListShopCouriersByShopIDs(ctx, gomock.Any()).
+		Return([]*entity.ShopCourier{{ID: 1, ShopID: shopID, CourierID: 1, Rate: 50.0}}, nil)
+	mockShopRepo.EXPECT().GetShopsByIDs(ctx, []uuid.UUID{shopID}).Return(nil, errors.New("database error"))
+
+	orderID := uuid.New()
+	mockOrderRepo.EXPECT().
+		CreateFullOrder(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), cart.ID, userID).
+		DoAndReturn(func(_ context.Context, order *entity.Order, shopOrders []*entity.ShopOrder, _ map[string][]*entity.OrderItem, _ *entity.Payment, _ uint32, _ uuid.UUID) error {
+			assert.Nil(t, shopOrders[0].EstimatedDeliveryFrom)
+			assert.Nil(t, shopOrders[0].EstimatedDeliveryTo)
+			order.ID = orderID
+			return nil
+		})
+	mockOutboxRepo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+	mockOrderRepo.EXPECT().GetOrderByID(ctx, orderID).Return(&entity.Order{ID: orderID, UserID: userID, GrandTotal: 150.0}, nil)
+	mockOrderRepo.EXPECT().GetOrderLogsByOrderID(ctx, gomock.Any()).Return([]*entity.OrderLog{}, nil).AnyTimes()
+
+	result, err := uc.CreateOrderFromCart(ctx, userID, entity.CreateOrderRequest{AddressID: addressID, PaymentMethodID: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 150.0, result.GrandTotal)
+}
+
 func TestCreateOrderFromCart_CartNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -168,8 +251,9 @@ func TestCreateOrderFromCart_CartNotFound(t *testing.T) {
 	mockShopRepo := mock.NewMockShopRepository(ctrl)
 	mockProductRepo := mock.NewMockProductRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
 
-	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo)
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -194,6 +278,30 @@ func TestCreateOrderFromCart_CartNotFound(t *testing.T) {
 	assert.Equal(t, errmap.ErrCartIsEmpty, err)
 }
 
+func TestCreateOrderFromCart_UnverifiedEmailWhenVerificationRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mock.NewMockShopRepository(ctrl), mock.NewMockProductRepository(ctrl), mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mock.NewMockOutboxRepository(ctrl), passThroughTx(ctrl), true)
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// The cart is never read
+	mockUserRepo.EXPECT().
+		GetByID(ctx, userID).
+		Return(&entity.User{ID: userID}, nil).
+		Times(1)
+
+	result, err := uc.CreateOrderFromCart(ctx, userID, entity.CreateOrderRequest{AddressID: 123, PaymentMethodID: 1})
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, errmap.ErrEmailNotVerified)
+}
+
 func TestCreateOrderFromCart_EmptyCart(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -202,8 +310,9 @@ func TestCreateOrderFromCart_EmptyCart(t *testing.T) {
 	mockShopRepo := mock.NewMockShopRepository(ctrl)
 	mockProductRepo := mock.NewMockProductRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
 
-	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo)
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -246,8 +355,9 @@ func TestCreateOrderFromCart_AddressIDRequired(t *testing.T) {
 	mockShopRepo := mock.NewMockShopRepository(ctrl)
 	mockProductRepo := mock.NewMockProductRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
 
-	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo)
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -307,8 +417,9 @@ func TestCreateOrderFromCart_NoShippingOptions(t *testing.T) {
 	mockShopRepo := mock.NewMockShopRepository(ctrl)
 	mockProductRepo := mock.NewMockProductRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
 
-	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo)
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -388,7 +499,10 @@ func TestCreateOrderFromCart_CreateFullOrderError(t *testing.T) {
 	mockProductRepo := mock.NewMockProductRepository(ctrl)
 	mockUserRepo := mock.NewMockUserRepository(ctrl)
 
-	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo)
+	mockEstimator := mock.NewMockDeliveryEstimator(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mockEstimator, mockOutboxRepo, passThroughTx(ctrl), false)
 
 	ctx := context.Background()
 	userID := uuid.New()
@@ -417,12 +531,15 @@ func TestCreateOrderFromCart_CreateFullOrderError(t *testing.T) {
 	}
 
 	address := &entity.Address{
-		ID:      addressID,
-		UserID:  userID,
-		Name:    "John Doe",
-		Zipcode: uint32(10110),
+		ID:         addressID,
+		UserID:     userID,
+		Name:       "John Doe",
+		ProvinceID: 2,
+		Zipcode:    uint32(10110),
 	}
 
+	originProvinceID := uint32(1)
+
 	shopCouriers := []*entity.ShopCourier{
 		{
 			ID:        1,
@@ -460,9 +577,29 @@ func TestCreateOrderFromCart_CreateFullOrderError(t *testing.T) {
 		Return(shopCouriers, nil).
 		Times(1)
 
+	mockShopRepo.EXPECT().
+		GetShopsByIDs(ctx, []uuid.UUID{shopID}).
+		Return([]*entity.Shop{{ID: shopID, ProvinceID: &originProvinceID, HandlingDays: 2}}, nil).
+		Times(1)
+
+	mockEstimator.EXPECT().
+		Estimate(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, req entity.DeliveryEstimateRequest) (*entity.DeliveryWindow, error) {
+			assert.Equal(t, uint32(1), req.CourierID)
+			assert.Equal(t, originProvinceID, *req.OriginProvinceID)
+			assert.Equal(t, uint32(2), *req.DestinationProvinceID)
+			assert.Equal(t, uint32(2), req.HandlingDays)
+			return &entity.DeliveryWindow{From: "2026-01-07", To: "2026-01-09"}, nil
+		}).
+		Times(1)
+
 	mockOrderRepo.EXPECT().
 		CreateFullOrder(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), cart.ID, userID).
-		Return(dbError).
+		DoAndReturn(func(_ context.Context, _ *entity.Order, shopOrders []*entity.ShopOrder, _ map[string][]*entity.OrderItem, _ *entity.Payment, _ uint32, _ uuid.UUID) error {
+			assert.Equal(t, "2026-01-07", shopOrders[0].EstimatedDeliveryFrom.Format("2006-01-02"))
+			assert.Equal(t, "2026-01-09", shopOrders[0].EstimatedDeliveryTo.Format("2006-01-02"))
+			return dbError
+		}).
 		Times(1)
 
 	// Execute
@@ -473,3 +610,509 @@ func TestCreateOrderFromCart_CreateFullOrderError(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Equal(t, dbError, err)
 }
+
+func TestCancelShopOrder_PaidOrderCreatesRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopID := uuid.New()
+	orderID := uuid.New()
+	shopOrderID := uuid.New()
+	paymentID := uuid.New()
+
+	shopOrder := &entity.ShopOrder{
+		ID:            shopOrderID,
+		OrderID:       orderID,
+		ShopID:        shopID,
+		OrderStatusID: entity.OrderStatusProcessing,
+		GrandTotal:    450,
+		OrderItems: []entity.OrderItem{
+			{ProductID: 7, Qty: 2},
+		},
+	}
+	payment := &entity.Payment{
+		ID:              paymentID,
+		OrderID:         orderID,
+		PaymentMethodID: entity.PaymentMethodPromptPay,
+		PaymentStatusID: entity.PaymentStatusCompleted,
+		Amount:          900,
+	}
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(shopOrder, nil)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, userID).Return(&entity.ShopMember{ShopID: shopID, UserID: userID, Role: entity.ShopRoleOwner, Shop: entity.Shop{ID: shopID, UserID: userID}}, nil)
+	mockOrderRepo.EXPECT().GetPaymentByOrderID(ctx, orderID).Return(payment, nil)
+	mockOrderRepo.EXPECT().
+		CancelShopOrderWithRefund(ctx, shopOrderID, "out of stock", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, refund *entity.Refund) (bool, error) {
+			assert.Equal(t, 450.0, refund.Amount)
+			assert.Equal(t, paymentID, *refund.PaymentID)
+			assert.Equal(t, entity.RefundMethodPromptPay, *refund.RefundMethodID)
+			assert.Equal(t, entity.RefundStatusApproved, refund.RefundStatusID)
+			assert.Equal(t, entity.RefundInitiatorSystem, refund.Initiator)
+			refund.ID = uuid.New()
+			return true, nil
+		})
+	mockOrderRepo.EXPECT().CancelShopOrder(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{}, nil)
+	mockOrderRepo.EXPECT().CreateOrderLog(ctx, gomock.Any()).Return(nil).Times(2)
+	mockProductRepo.EXPECT().RestoreProductStock(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	var event *entity.OutboxEvent
+	mockOutboxRepo.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, events ...*entity.OutboxEvent) error {
+		event = events[0]
+		return nil
+	})
+
+	err := uc.CancelShopOrder(ctx, userID, shopOrderID, entity.CancelOrderRequest{Reason: "out of stock"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.EventShopOrderCancelled, event.EventType)
+	var payload entity.ShopOrderCancelledPayload
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, []entity.StockItem{{ProductID: 7, Qty: 2}}, payload.Restock)
+	assert.Equal(t, 450.0, payload.Refund.Amount)
+}
+
+func TestCancelShopOrder_AlreadyRefundedOrderCreatesNoRefund(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mock.NewMockProductRepository(ctrl), mock.NewMockUserRepository(ctrl), provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopID := uuid.New()
+	orderID := uuid.New()
+	shopOrderID := uuid.New()
+
+	shopOrder := &entity.ShopOrder{ID: shopOrderID, OrderID: orderID, ShopID: shopID, OrderStatusID: entity.OrderStatusShipped, GrandTotal: 450}
+	payment := &entity.Payment{ID: uuid.New(), OrderID: orderID, PaymentMethodID: entity.PaymentMethodPromptPay, PaymentStatusID: entity.PaymentStatusPartiallyRefunded, Amount: 450}
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(shopOrder, nil)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, userID).Return(&entity.ShopMember{ShopID: shopID, UserID: userID, Role: entity.ShopRoleOwner}, nil)
+	mockOrderRepo.EXPECT().GetPaymentByOrderID(ctx, orderID).Return(payment, nil)
+	mockOrderRepo.EXPECT().CancelShopOrderWithRefund(ctx, shopOrderID, "buyer asked", gomock.Any()).Return(false, nil)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{}, nil)
+	mockOrderRepo.EXPECT().CreateOrderLog(ctx, gomock.Any()).Return(nil).Times(1)
+
+	var event *entity.OutboxEvent
+	mockOutboxRepo.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, events ...*entity.OutboxEvent) error {
+		event = events[0]
+		return nil
+	})
+
+	err := uc.CancelShopOrder(ctx, userID, shopOrderID, entity.CancelOrderRequest{Reason: "buyer asked"})
+
+	assert.NoError(t, err)
+	var payload entity.ShopOrderCancelledPayload
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Nil(t, payload.Refund)
+}
+
+func TestCancelShopOrder_LogFailureRollsBackCancellation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopID := uuid.New()
+	shopOrderID := uuid.New()
+	orderID := uuid.New()
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(&entity.ShopOrder{
+		ID:            shopOrderID,
+		OrderID:       orderID,
+		ShopID:        shopID,
+		OrderStatusID: entity.OrderStatusPending,
+		OrderItems:    []entity.OrderItem{{ProductID: 7, Qty: 2}},
+	}, nil)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, userID).Return(&entity.ShopMember{ShopID: shopID, UserID: userID, Role: entity.ShopRoleOwner, Shop: entity.Shop{ID: shopID, UserID: userID}}, nil)
+	mockOrderRepo.EXPECT().GetPaymentByOrderID(ctx, orderID).Return(nil, gorm.ErrRecordNotFound)
+	mockOrderRepo.EXPECT().CancelShopOrder(ctx, shopOrderID, "out of stock").Return(nil)
+	mockOrderRepo.EXPECT().CreateOrderLog(ctx, gomock.Any()).Return(errors.New("db down"))
+	mockOutboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Times(0)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(gomock.Any(), gomock.Any()).Times(0)
+
+	err := uc.CancelShopOrder(ctx, userID, shopOrderID, entity.CancelOrderRequest{Reason: "out of stock"})
+
+	assert.Error(t, err)
+}
+
+func TestHandleCourierWebhook_DeliveredMovesOrderToDelivered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	shipmentID := uuid.New()
+	shopOrderID := uuid.New()
+	payload := []byte(`{"eventId":"evt-1","trackingNo":"TH123","status":"DELIVERED","occurredAt":"2026-01-02T10:00:00+07:00"}`)
+
+	mac := hmac.New(sha256.New, []byte("courier-secret"))
+	mac.Write(payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	mockOrderRepo.EXPECT().GetCourierByID(ctx, uint32(1)).Return(&entity.Courier{ID: 1, Name: "Flash", WebhookSecret: "courier-secret"}, nil).Times(2)
+	mockOrderRepo.EXPECT().GetShipmentByTrackingNo(ctx, uint32(1), "TH123").Return(&entity.Shipment{ID: shipmentID, ShopOrderID: shopOrderID}, nil)
+	mockOrderRepo.EXPECT().
+		RecordShipmentEvent(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *entity.ShipmentEvent) (bool, error) {
+			assert.Equal(t, shipmentID, event.ShipmentID)
+			assert.Equal(t, entity.ShipmentStatusDelivered, event.ShipmentStatusID)
+			assert.Equal(t, "evt-1", *event.ExternalEventID)
+			return true, nil
+		})
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(&entity.ShopOrder{ID: shopOrderID, OrderStatusID: entity.OrderStatusShipped}, nil)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{
+		{ID: shipmentID, ShopOrderID: shopOrderID, ShipmentStatusID: entity.ShipmentStatusDelivered},
+	}, nil)
+	mockOrderRepo.EXPECT().UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusDelivered).Return(nil)
+	mockOrderRepo.EXPECT().CreateOrderLog(ctx, gomock.Any()).Return(nil)
+	mockOutboxRepo.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, events ...*entity.OutboxEvent) error {
+		assert.Equal(t, entity.EventShopOrderDelivered, events[0].EventType)
+		return nil
+	})
+
+	err := uc.HandleCourierWebhook(ctx, 1, signature, payload)
+	assert.NoError(t, err)
+
+	err = uc.HandleCourierWebhook(ctx, 1, "bad-signature", payload)
+	assert.ErrorIs(t, err, errmap.ErrInvalidWebhookSignature)
+}
+
+func TestAddShipment_BooksPickupWithCourierProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopID := uuid.New()
+	shopOrderID := uuid.New()
+	orderID := uuid.New()
+
+	shopOrder := &entity.ShopOrder{
+		ID:            shopOrderID,
+		OrderID:       orderID,
+		ShopID:        shopID,
+		OrderNumber:   "ORD-0001",
+		OrderStatusID: entity.OrderStatusProcessing,
+		Order: entity.Order{
+			ShippingName:     "สมชาย ใจดี",
+			ShippingProvince: "กรุงเทพมหานคร",
+			ShippingZipcode:  "10110",
+		},
+		OrderItems: []entity.OrderItem{{ID: 11, ShopOrderID: shopOrderID, ProductID: 7, Qty: 2}},
+	}
+	courier := &entity.Courier{ID: 1, Name: "Kerry Express", ProviderCode: entity.CourierProviderSimulator}
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(shopOrder, nil)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, userID).Return(&entity.ShopMember{ShopID: shopID, UserID: userID, Role: entity.ShopRoleOwner, Shop: entity.Shop{ID: shopID, UserID: userID}}, nil)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{}, nil)
+	mockOrderRepo.EXPECT().GetCourierByID(ctx, uint32(1)).Return(courier, nil)
+	mockOrderRepo.EXPECT().
+		AddShipment(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, s *entity.Shipment) error {
+			assert.NotEmpty(t, s.TrackingNo)
+			assert.Equal(t, []entity.ShipmentItem{{OrderItemID: 11, Qty: 2}}, s.Items)
+			if assert.NotNil(t, s.ProviderCode) {
+				assert.Equal(t, entity.CourierProviderSimulator, *s.ProviderCode)
+			}
+			return nil
+		})
+	mockOrderRepo.EXPECT().UpdateShopOrderStatus(ctx, shopOrderID, entity.OrderStatusShipped).Return(nil)
+	mockOrderRepo.EXPECT().CreateOrderLog(ctx, gomock.Any()).Return(nil)
+	mockOutboxRepo.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, events ...*entity.OutboxEvent) error {
+		assert.Equal(t, entity.EventShopOrderShipped, events[0].EventType)
+		return nil
+	})
+
+	resp, err := uc.AddShipment(ctx, userID, shopOrderID, entity.AddShipmentRequest{CourierID: 1})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Booked)
+	assert.NotEmpty(t, resp.TrackingNo)
+}
+
+func TestAddShipment_ManualCourierRequiresTrackingNo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopID := uuid.New()
+	shopOrderID := uuid.New()
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(&entity.ShopOrder{
+		ID:         shopOrderID,
+		ShopID:     shopID,
+		OrderItems: []entity.OrderItem{{ID: 11, ShopOrderID: shopOrderID, Qty: 1}},
+	}, nil)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, userID).Return(&entity.ShopMember{ShopID: shopID, UserID: userID, Role: entity.ShopRoleOwner, Shop: entity.Shop{ID: shopID, UserID: userID}}, nil)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{}, nil)
+	mockOrderRepo.EXPECT().GetCourierByID(ctx, uint32(2)).Return(&entity.Courier{ID: 2, Name: "Flash Express"}, nil)
+	mockOrderRepo.EXPECT().AddShipment(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AddShipment(ctx, userID, shopOrderID, entity.AddShipmentRequest{CourierID: 2})
+
+	assert.ErrorIs(t, err, errmap.ErrTrackingNoRequired)
+}
+
+func TestAddShipment_PartialParcelKeepsOrderUnshipped(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopID := uuid.New()
+	shopOrderID := uuid.New()
+
+	shopOrder := &entity.ShopOrder{
+		ID:            shopOrderID,
+		ShopID:        shopID,
+		OrderStatusID: entity.OrderStatusProcessing,
+		OrderItems: []entity.OrderItem{
+			{ID: 11, ShopOrderID: shopOrderID, Qty: 3},
+			{ID: 12, ShopOrderID: shopOrderID, Qty: 1},
+		},
+	}
+	firstParcel := &entity.Shipment{
+		ID:          uuid.New(),
+		ShopOrderID: shopOrderID,
+		Items:       []entity.ShipmentItem{{OrderItemID: 11, Qty: 1}},
+	}
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(shopOrder, nil).Times(2)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, userID).Return(&entity.ShopMember{ShopID: shopID, UserID: userID, Role: entity.ShopRoleOwner, Shop: entity.Shop{ID: shopID, UserID: userID}}, nil).Times(2)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{firstParcel}, nil).Times(2)
+	mockOrderRepo.EXPECT().GetCourierByID(ctx, uint32(2)).Return(&entity.Courier{ID: 2, Name: "Flash Express"}, nil)
+	mockOrderRepo.EXPECT().AddShipment(ctx, gomock.Any()).Return(nil)
+	mockOrderRepo.EXPECT().UpdateShopOrderStatus(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockOrderRepo.EXPECT().
+		CreateOrderLog(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, l *entity.OrderLog) error {
+			assert.Equal(t, entity.OrderStatusProcessing, l.OrderStatusID)
+			return nil
+		})
+
+	resp, err := uc.AddShipment(ctx, userID, shopOrderID, entity.AddShipmentRequest{
+		CourierID:  2,
+		TrackingNo: "TH0002",
+		Items:      []entity.AddShipmentItemRequest{{OrderItemID: 11, Qty: 2}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), resp.Items[0].Qty)
+
+	_, err = uc.AddShipment(ctx, userID, shopOrderID, entity.AddShipmentRequest{
+		CourierID:  2,
+		TrackingNo: "TH0003",
+		Items:      []entity.AddShipmentItemRequest{{OrderItemID: 11, Qty: 3}},
+	})
+	assert.ErrorIs(t, err, errmap.ErrInvalidShipmentItems)
+}
+
+func TestHandleCourierWebhook_LastFailedDeliveryReturnsParcelAndRefunds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	shipmentID := uuid.New()
+	shopOrderID := uuid.New()
+	orderID := uuid.New()
+	paymentID := uuid.New()
+	payload := []byte(`{"eventId":"evt-3","trackingNo":"TH123","status":"FAILED_DELIVERY","description":"recipient not home"}`)
+
+	mac := hmac.New(sha256.New, []byte("courier-secret"))
+	mac.Write(payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	shopOrder := &entity.ShopOrder{
+		ID:            shopOrderID,
+		OrderID:       orderID,
+		OrderStatusID: entity.OrderStatusShipped,
+		Shipping:      50,
+		GrandTotal:    250,
+		OrderItems:    []entity.OrderItem{{ID: 11, ShopOrderID: shopOrderID, ProductID: 7, Qty: 2, UnitPrice: 100}},
+	}
+
+	mockOrderRepo.EXPECT().GetCourierByID(ctx, uint32(1)).Return(&entity.Courier{ID: 1, Name: "Flash", WebhookSecret: "courier-secret"}, nil)
+	mockOrderRepo.EXPECT().GetShipmentByTrackingNo(ctx, uint32(1), "TH123").Return(&entity.Shipment{
+		ID: shipmentID, ShopOrderID: shopOrderID, TrackingNo: "TH123", FailedAttempts: 2,
+	}, nil)
+
+	var statuses []uint32
+	mockOrderRepo.EXPECT().
+		RecordShipmentEvent(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, event *entity.ShipmentEvent) (bool, error) {
+			statuses = append(statuses, event.ShipmentStatusID)
+			return true, nil
+		}).
+		Times(2)
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(shopOrder, nil).Times(2)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{
+		{
+			ID:               shipmentID,
+			ShopOrderID:      shopOrderID,
+			TrackingNo:       "TH123",
+			ShipmentStatusID: entity.ShipmentStatusReturnedToSender,
+			Items: []entity.ShipmentItem{
+				{ShipmentID: shipmentID, OrderItemID: 11, Qty: 2, OrderItem: shopOrder.OrderItems[0]},
+			},
+		},
+	}, nil)
+	mockOrderRepo.EXPECT().GetPaymentByOrderID(ctx, orderID).Return(&entity.Payment{
+		ID:              paymentID,
+		OrderID:         orderID,
+		PaymentMethodID: entity.PaymentMethodPromptPay,
+		PaymentStatusID: entity.PaymentStatusCompleted,
+		Amount:          250,
+	}, nil)
+	mockOrderRepo.EXPECT().
+		CancelShopOrderWithRefund(ctx, shopOrderID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, refund *entity.Refund) (bool, error) {
+			assert.Equal(t, 250.0, refund.Amount)
+			assert.Equal(t, paymentID, *refund.PaymentID)
+			assert.Equal(t, entity.RefundInitiatorSystem, refund.Initiator)
+			refund.ID = uuid.New()
+			return true, nil
+		})
+	mockOrderRepo.EXPECT().CreateOrderLog(ctx, gomock.Any()).Return(nil).Times(2)
+
+	var events []*entity.OutboxEvent
+	mockOutboxRepo.EXPECT().Add(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, added ...*entity.OutboxEvent) error {
+		events = append(events, added...)
+		return nil
+	}).Times(2)
+
+	err := uc.HandleCourierWebhook(ctx, 1, signature, payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{entity.ShipmentStatusFailedDelivery, entity.ShipmentStatusReturnedToSender}, statuses)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, entity.EventParcelReturned, events[0].EventType)
+		var returned entity.ParcelReturnedPayload
+		assert.NoError(t, json.Unmarshal(events[0].Payload, &returned))
+		assert.Equal(t, []entity.StockItem{{ProductID: 7, Qty: 2}}, returned.Restock)
+		assert.Equal(t, entity.EventShopOrderCancelled, events[1].EventType)
+	}
+}
+
+func TestScheduleDeliveryReattempt_RequiresFailedDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockProductRepo := mock.NewMockProductRepository(ctrl)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mockProductRepo, mockUserRepo, provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	shopOrderID := uuid.New()
+	shipmentID := uuid.New()
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(&entity.ShopOrder{
+		ID:            shopOrderID,
+		OrderStatusID: entity.OrderStatusShipped,
+		Order:         entity.Order{UserID: userID},
+	}, nil)
+	mockOrderRepo.EXPECT().GetShipmentsByShopOrderID(ctx, shopOrderID).Return([]*entity.Shipment{
+		{ID: shipmentID, ShopOrderID: shopOrderID, ShipmentStatusID: entity.ShipmentStatusOutForDelivery},
+	}, nil)
+	mockOrderRepo.EXPECT().CreateDeliveryReattempt(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.ScheduleDeliveryReattempt(ctx, userID, shopOrderID, shipmentID, entity.DeliveryReattemptRequest{ScheduledDate: "2030-01-01"})
+
+	assert.ErrorIs(t, err, errmap.ErrReattemptNotAllowed)
+}
+
+func TestAddShipment_MembersWithoutFulfilPermissionAreForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderRepo := mock.NewMockOrderRepository(ctrl)
+	mockShopRepo := mock.NewMockShopRepository(ctrl)
+	mockOutboxRepo := mock.NewMockOutboxRepository(ctrl)
+
+	uc := NewOrderUsecase(mockOrderRepo, mockShopRepo, mock.NewMockProductRepository(ctrl), mock.NewMockUserRepository(ctrl), provider.NewRegistry(), mock.NewMockDeliveryEstimator(ctrl), mockOutboxRepo, passThroughTx(ctrl), false)
+
+	ctx := context.Background()
+	shopID := uuid.New()
+	shopOrderID := uuid.New()
+	support := uuid.New()
+	stranger := uuid.New()
+
+	mockOrderRepo.EXPECT().GetShopOrderByID(ctx, shopOrderID).Return(&entity.ShopOrder{ID: shopOrderID, ShopID: shopID}, nil).Times(2)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, support).Return(&entity.ShopMember{ShopID: shopID, UserID: support, Role: entity.ShopRoleSupport}, nil)
+	mockShopRepo.EXPECT().GetShopMember(ctx, shopID, stranger).Return(nil, nil)
+	mockOrderRepo.EXPECT().AddShipment(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AddShipment(ctx, support, shopOrderID, entity.AddShipmentRequest{CourierID: 1})
+	assert.ErrorIs(t, err, errmap.ErrForbidden)
+
+	_, err = uc.AddShipment(ctx, stranger, shopOrderID, entity.AddShipmentRequest{CourierID: 1})
+	assert.ErrorIs(t, err, errmap.ErrForbidden)
+}
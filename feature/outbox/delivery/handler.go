@@ -0,0 +1,94 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/response"
+)
+
+type OutboxHandler struct {
+	usecase domain.OutboxUsecase
+}
+
+func NewOutboxHandler(usecase domain.OutboxUsecase) *OutboxHandler {
+	return &OutboxHandler{usecase: usecase}
+}
+
+// ListEvents godoc
+//
+//	@Summary		List outbox events (admin)
+//	@Description	Get domain events waiting for delivery, delivered or dead-lettered, newest first
+//	@Tags			Outbox
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page		query		int		false	"Page number (default: 1)"
+//	@Param			perPage		query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			status		query		string	false	"Filter by status (PENDING, PROCESSED, DEAD)"
+//	@Param			eventType	query		string	false	"Filter by event type, e.g. shop_order.cancelled"
+//	@Success		200			{object}	entity.OutboxEventListResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/admin/outbox/events [get]
+func (h *OutboxHandler) ListEvents(c echo.Context) error {
+	var req entity.OutboxEventListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	events, err := h.usecase.ListEvents(c.Request().Context(), req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", events)
+}
+
+// RetryEvent godoc
+//
+//	@Summary		Retry a dead event (admin)
+//	@Description	Give a dead-lettered event a fresh set of delivery attempts. Handlers that already succeeded are not run again.
+//	@Tags			Outbox
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			id	path		string	true	"Event ID"
+//	@Success		200	{object}	entity.OutboxEvent
+//	@Failure		400	{object}	response.ResponseError
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Failure		404	{object}	response.ResponseError
+//	@Failure		409	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/admin/outbox/events/{id}/retry [post]
+func (h *OutboxHandler) RetryEvent(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	event, err := h.usecase.RetryEvent(c.Request().Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrOutboxEventNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrOutboxEventNotFound.Error())
+		case errors.Is(err, errmap.ErrOutboxEventNotDead):
+			return response.Error(c, http.StatusConflict, errmap.ErrOutboxEventNotDead.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "event requeued", event)
+}
@@ -0,0 +1,20 @@
+package delivery
+
+import (
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/feature/outbox/repository"
+	"ecommerce-go-api/feature/outbox/usecase"
+	"ecommerce-go-api/middleware"
+)
+
+func RegisterOutboxHandler(group *echo.Group, db *gorm.DB) {
+	repo := repository.NewOutboxRepository(db)
+	uc := usecase.NewOutboxUsecase(repo)
+	handler := NewOutboxHandler(uc)
+
+	events := group.Group("/admin/outbox/events", middleware.JWTAuth(), middleware.AdminOnly())
+	events.GET("", handler.ListEvents)
+	events.POST("/:id/retry", handler.RetryEvent)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/dbtx"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Add(ctx context.Context, events ...*entity.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return dbtx.From(ctx, r.db).Create(events).Error
+}
+
+// ClaimPendingEvents leases a batch of due events to the claimant, oldest first. Events
+// locked or leased by another instance are skipped.
+func (r *outboxRepository) ClaimPendingEvents(ctx context.Context, claim entity.JobClaim) ([]*entity.OutboxEvent, error) {
+	now := timeth.Now()
+	due := r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).
+		Select("id").
+		Where("status = ?", entity.OutboxStatusPending).
+		Where("next_attempt_at <= ?", now).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Order("created_at").
+		Limit(claim.Limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var events []*entity.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE outbox_events SET claimed_by = ?, claimed_until = ? WHERE id IN (?) RETURNING *",
+			claim.Claimant, now.Add(claim.Lease), due).
+		Scan(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING gives no order guarantee.
+	slices.SortFunc(events, func(a, b *entity.OutboxEvent) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return events, nil
+}
+
+func (r *outboxRepository) FinishDelivery(ctx context.Context, event *entity.OutboxEvent) error {
+	event.ClaimedBy = nil
+	event.ClaimedUntil = nil
+	return r.db.WithContext(ctx).Model(event).
+		Select("status", "attempts", "next_attempt_at", "last_error", "completed_handlers", "processed_at", "claimed_by", "claimed_until").
+		Updates(event).Error
+}
+
+func (r *outboxRepository) ListEvents(ctx context.Context, req entity.OutboxEventListRequest) ([]*entity.OutboxEvent, int64, error) {
+	var events []*entity.OutboxEvent
+	var total int64
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	query := r.db.WithContext(ctx).Model(&entity.OutboxEvent{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.EventType != "" {
+		query = query.Where("event_type = ?", req.EventType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(int(perPage)).
+		Offset(int(offset)).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+func (r *outboxRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*entity.OutboxEvent, error) {
+	var event entity.OutboxEvent
+	if err := r.db.WithContext(ctx).First(&event, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// RequeueDeadEvent gives a dead event a fresh set of attempts. Handlers that already
+// succeeded are not run again.
+func (r *outboxRepository) RequeueDeadEvent(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entity.OutboxEvent{}).
+		Where("id = ? AND status = ?", id, entity.OutboxStatusDead).
+		Updates(map[string]interface{}{
+			"status":          entity.OutboxStatusPending,
+			"attempts":        0,
+			"next_attempt_at": timeth.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
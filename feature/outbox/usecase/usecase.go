@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+)
+
+type outboxUsecase struct {
+	repo domain.OutboxRepository
+}
+
+func NewOutboxUsecase(repo domain.OutboxRepository) domain.OutboxUsecase {
+	return &outboxUsecase{repo: repo}
+}
+
+func (u *outboxUsecase) ListEvents(ctx context.Context, req entity.OutboxEventListRequest) (*entity.OutboxEventListResponse, error) {
+	events, total, err := u.repo.ListEvents(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	return &entity.OutboxEventListResponse{Items: events, Total: total}, nil
+}
+
+func (u *outboxUsecase) RetryEvent(ctx context.Context, id uuid.UUID) (*entity.OutboxEvent, error) {
+	event, err := u.repo.GetEventByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrOutboxEventNotFound
+		}
+		return nil, err
+	}
+	if event.Status != entity.OutboxStatusDead {
+		return nil, errmap.ErrOutboxEventNotDead
+	}
+
+	if err := u.repo.RequeueDeadEvent(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Retried by someone else in the meantime.
+			return nil, errmap.ErrOutboxEventNotDead
+		}
+		return nil, fmt.Errorf("failed to requeue outbox event: %w", err)
+	}
+
+	return u.repo.GetEventByID(ctx, id)
+}
@@ -150,6 +150,9 @@ func (h *ProductHandler) CreateShopProduct(c echo.Context) error {
 	}
 	product, err := h.usecase.CreateProduct(c.Request().Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
 		return response.Error(c, http.StatusInternalServerError, err.Error())
 	}
 	return response.Success(c, http.StatusCreated, "created", product)
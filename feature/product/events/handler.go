@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/outbox"
+)
+
+// restockHandler is the name the restock handler is recorded under on events.
+const restockHandler = "product.restock"
+
+type stockHandler struct {
+	productRepo domain.ProductRepository
+	tx          domain.Transactor
+}
+
+// RegisterHandlers puts the stock of cancelled shop orders and returned parcels back.
+func RegisterHandlers(dispatcher *outbox.Dispatcher, productRepo domain.ProductRepository, tx domain.Transactor) {
+	h := &stockHandler{productRepo: productRepo, tx: tx}
+	dispatcher.Subscribe(entity.EventShopOrderCancelled, restockHandler, h.restockCancelledOrder)
+	dispatcher.Subscribe(entity.EventParcelReturned, restockHandler, h.restockReturnedParcel)
+}
+
+func (h *stockHandler) restockCancelledOrder(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ShopOrderCancelledPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+	return h.restock(ctx, payload.Restock)
+}
+
+func (h *stockHandler) restockReturnedParcel(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.ParcelReturnedPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+	return h.restock(ctx, payload.Restock)
+}
+
+// restock puts all items back in one transaction, so a retry never restocks an item
+// twice.
+func (h *stockHandler) restock(ctx context.Context, items []entity.StockItem) error {
+	return h.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range items {
+			err := h.productRepo.RestoreProductStock(ctx, item.ProductID, item.Qty)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// Deleted products have no stock to go back to.
+				log.Printf("[OUTBOX] Skipping restock of deleted product_id=%d, quantity=%d", item.ProductID, item.Qty)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("failed to restore stock for product_id=%d: %w", item.ProductID, err)
+			}
+		}
+		return nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/dbtx"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -18,11 +19,16 @@ func NewProductRepository(db *gorm.DB) domain.ProductRepository {
 	return &productRepository{db: db}
 }
 
+// conn joins the transaction carried by ctx, if any.
+func (r *productRepository) conn(ctx context.Context) *gorm.DB {
+	return dbtx.From(ctx, r.db)
+}
+
 func (r *productRepository) ListProducts(ctx context.Context, q *entity.ProductListRequest) ([]*entity.Product, int64, error) {
 	var products []*entity.Product
 	var total int64
 
-	base := r.db.WithContext(ctx).Model(&entity.Product{}).Where("is_active = true AND deleted_at IS NULL")
+	base := r.conn(ctx).Model(&entity.Product{}).Where("is_active = true AND deleted_at IS NULL")
 
 	if q != nil && q.SearchText != "" {
 		like := "%" + q.SearchText + "%"
@@ -52,7 +58,7 @@ func (r *productRepository) ListProducts(ctx context.Context, q *entity.ProductL
 
 func (r *productRepository) GetProductByID(ctx context.Context, id uint32) (*entity.Product, error) {
 	var p entity.Product
-	if err := r.db.WithContext(ctx).Preload("Shop").First(&p, "id = ? AND is_active = true AND deleted_at IS NULL", id).Error; err != nil {
+	if err := r.conn(ctx).Preload("Shop").First(&p, "id = ? AND is_active = true AND deleted_at IS NULL", id).Error; err != nil {
 		return nil, err
 	}
 	return &p, nil
@@ -62,7 +68,7 @@ func (r *productRepository) ListByShopID(ctx context.Context, shopID uuid.UUID,
 	var products []*entity.Product
 	var total int64
 
-	productQuery := r.db.WithContext(ctx).Model(&entity.Product{}).Where("deleted_at IS NULL AND shop_id = ?", shopID)
+	productQuery := r.conn(ctx).Model(&entity.Product{}).Where("deleted_at IS NULL AND shop_id = ?", shopID)
 
 	if q != nil && q.SearchText != "" {
 		like := "%" + q.SearchText + "%"
@@ -91,7 +97,7 @@ func (r *productRepository) ListByShopID(ctx context.Context, shopID uuid.UUID,
 }
 
 func (r *productRepository) CreateProduct(ctx context.Context, product *entity.Product) error {
-	return r.db.WithContext(ctx).Create(product).Error
+	return r.conn(ctx).Create(product).Error
 }
 
 func (r *productRepository) UpdateProduct(ctx context.Context, product *entity.Product) error {
@@ -104,7 +110,7 @@ func (r *productRepository) UpdateProduct(ctx context.Context, product *entity.P
 		"is_active":   product.IsActive,
 		"updated_at":  product.UpdatedAt,
 	}
-	res := r.db.WithContext(ctx).
+	res := r.conn(ctx).
 		Model(&entity.Product{}).
 		Where("id = ? AND deleted_at IS NULL", product.ID).
 		Updates(updates)
@@ -118,14 +124,14 @@ func (r *productRepository) UpdateProduct(ctx context.Context, product *entity.P
 }
 
 func (r *productRepository) DeleteProduct(ctx context.Context, productID uint32) error {
-	res := r.db.WithContext(ctx).
+	res := r.conn(ctx).
 		Where("id = ?", productID).
 		Delete(&entity.Product{})
 	return res.Error
 }
 
 func (r *productRepository) RestoreProductStock(ctx context.Context, productID uint32, qty uint32) error {
-	res := r.db.WithContext(ctx).
+	res := r.conn(ctx).
 		Model(&entity.Product{}).
 		Where("id = ? AND deleted_at IS NULL", productID).
 		Update("stock_qty", gorm.Expr("stock_qty + ?", qty))
@@ -9,6 +9,7 @@ import (
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
 )
 
 type productUsecase struct {
@@ -80,10 +81,14 @@ func (u *productUsecase) CreateProduct(ctx context.Context, userID uuid.UUID, re
 		return nil, fmt.Errorf("invalid input: %w", err)
 	}
 
-	shop, err := u.shopRepo.GetShopByUserID(ctx, userID)
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("shop not found for user: %w", err)
 	}
+	if !member.Can(entity.ShopPermissionManageProducts) {
+		return nil, errmap.ErrForbidden
+	}
+	shop := &member.Shop
 
 	p := &entity.Product{
 		Name:        req.Name,
@@ -114,12 +119,12 @@ func (u *productUsecase) UpdateProduct(ctx context.Context, userID uuid.UUID, pr
 		return nil, fmt.Errorf("product not found: %w", err)
 	}
 
-	shop, err := u.shopRepo.GetShopByUserID(ctx, userID)
+	member, err := u.shopRepo.GetShopMember(ctx, prod.ShopID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("shop not found for user: %w", err)
+		return nil, fmt.Errorf("failed to check shop member: %w", err)
 	}
-	if prod.ShopID != shop.ID {
-		return nil, fmt.Errorf("forbidden: user does not own this product")
+	if !member.Can(entity.ShopPermissionManageProducts) {
+		return nil, errmap.ErrForbidden
 	}
 
 	prod.Name = req.Name
@@ -141,12 +146,12 @@ func (u *productUsecase) DeleteProduct(ctx context.Context, userID uuid.UUID, pr
 		return err
 	}
 
-	shop, err := u.shopRepo.GetShopByUserID(ctx, userID)
+	member, err := u.shopRepo.GetShopMember(ctx, prod.ShopID, userID)
 	if err != nil {
-		return fmt.Errorf("shop not found for user: %w", err)
+		return fmt.Errorf("failed to check shop member: %w", err)
 	}
-	if prod.ShopID != shop.ID {
-		return fmt.Errorf("forbidden: user does not own this product")
+	if !member.Can(entity.ShopPermissionManageProducts) {
+		return errmap.ErrForbidden
 	}
 
 	if err := u.repo.DeleteProduct(ctx, productID); err != nil {
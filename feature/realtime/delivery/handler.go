@@ -0,0 +1,128 @@
+package delivery
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/response"
+	"ecommerce-go-api/middleware"
+)
+
+// heartbeatInterval keeps idle streams from being closed by proxies.
+const heartbeatInterval = 25 * time.Second
+
+// retryMillis is how long clients wait before reconnecting a dropped stream.
+const retryMillis = 3000
+
+type RealtimeHandler struct {
+	usecase domain.RealtimeUsecase
+}
+
+func NewRealtimeHandler(usecase domain.RealtimeUsecase) *RealtimeHandler {
+	return &RealtimeHandler{usecase: usecase}
+}
+
+// StreamBuyerOrders godoc
+//
+//	@Summary		Stream order updates
+//	@Description	Server-Sent Events stream of status changes of the user's orders: payment.expired, shop_order.shipped, shop_order.delivered, shop_order.completed, shop_order.cancelled and shipment.returned. Each event's id is the domain event ID; reload the orders after reconnecting, as events sent while disconnected are not replayed.
+//	@Tags			Order
+//	@Security		BearerAuth
+//	@Produce		text/event-stream
+//	@Success		200	{object}	entity.RealtimeEvent
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Router			/api/orders/stream [get]
+func (h *RealtimeHandler) StreamBuyerOrders(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	sub, err := h.usecase.SubscribeBuyer(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	defer sub.Close()
+
+	return stream(c, sub)
+}
+
+// StreamShopOrders godoc
+//
+//	@Summary		Stream shop order updates
+//	@Description	Server-Sent Events stream of the shop's new orders (order.placed), payments (payment.submitted) and cancellations (shop_order.cancelled). Each event's id is the domain event ID; reload the orders after reconnecting, as events sent while disconnected are not replayed.
+//	@Tags			Order
+//	@Security		BearerAuth
+//	@Produce		text/event-stream
+//	@Success		200	{object}	entity.RealtimeEvent
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/shop/orders/stream [get]
+func (h *RealtimeHandler) StreamShopOrders(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	sub, err := h.usecase.SubscribeShop(c.Request().Context(), userID)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+	defer sub.Close()
+
+	return stream(c, sub)
+}
+
+// stream writes the subscription's events until the client goes away or the
+// subscription is dropped.
+func stream(c echo.Context, sub domain.RealtimeSubscription) error {
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	// Stop nginx from buffering the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", retryMillis); err != nil {
+		return nil
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return nil
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("[REALTIME] Skipping event %s: %v", event.ID, err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+	}
+}
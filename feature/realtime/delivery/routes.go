@@ -0,0 +1,19 @@
+package delivery
+
+import (
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/feature/realtime/usecase"
+	shopRepo "ecommerce-go-api/feature/shop/repository"
+	"ecommerce-go-api/middleware"
+)
+
+func RegisterRealtimeHandler(group *echo.Group, db *gorm.DB, broker domain.RealtimeBroker) {
+	uc := usecase.NewRealtimeUsecase(broker, shopRepo.NewShopRepository(db))
+	handler := NewRealtimeHandler(uc)
+
+	group.GET("/orders/stream", handler.StreamBuyerOrders, middleware.JWTAuth(), middleware.UserOnly())
+	group.GET("/shop/orders/stream", handler.StreamShopOrders, middleware.JWTAuth(), middleware.ShopOwnerOnly())
+}
@@ -0,0 +1,127 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/realtime"
+)
+
+// publishHandler is the name the realtime handler is recorded under on events.
+const publishHandler = "realtime.publish"
+
+type publisher struct {
+	broker    domain.RealtimeBroker
+	orderRepo domain.OrderRepository
+}
+
+// shopOrderUpdate is the data of shop order events on the streams. It leaves out the
+// restock lists and refunds of the domain events to stay within Postgres' notification
+// size limit.
+type shopOrderUpdate struct {
+	entity.ShopOrderPayload
+	Reason string `json:"reason,omitempty"`
+}
+
+// RegisterHandlers streams new orders, payments and cancellations to the shop's
+// dashboard, and status changes of their orders to buyers.
+func RegisterHandlers(dispatcher *outbox.Dispatcher, broker domain.RealtimeBroker, orderRepo domain.OrderRepository) {
+	p := &publisher{broker: broker, orderRepo: orderRepo}
+
+	dispatcher.Subscribe(entity.EventOrderPlaced, publishHandler, p.publishOrderEvent)
+	dispatcher.Subscribe(entity.EventPaymentSubmitted, publishHandler, p.publishOrderEvent)
+	dispatcher.Subscribe(entity.EventPaymentExpired, publishHandler, p.publishPaymentExpired)
+	dispatcher.Subscribe(entity.EventShopOrderCancelled, publishHandler, p.publishShopOrderEvent(true))
+	for _, eventType := range []string{
+		entity.EventShopOrderShipped,
+		entity.EventShopOrderDelivered,
+		entity.EventShopOrderCompleted,
+		entity.EventParcelReturned,
+	} {
+		dispatcher.Subscribe(eventType, publishHandler, p.publishShopOrderEvent(false))
+	}
+}
+
+func (p *publisher) publish(ctx context.Context, event *entity.OutboxEvent, topic string, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	err = p.broker.Publish(ctx, topic, &entity.RealtimeEvent{
+		ID:        event.ID,
+		Type:      event.EventType,
+		Data:      raw,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", topic, err)
+	}
+	return nil
+}
+
+// publishOrderEvent sends an order-level event to each shop in the order, with that
+// shop's part of the order as data.
+func (p *publisher) publishOrderEvent(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload struct {
+		OrderID uuid.UUID `json:"orderId"`
+	}
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := p.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	for _, shopOrder := range order.ShopOrders {
+		err := p.publish(ctx, event, realtime.ShopTopic(shopOrder.ShopID), entity.ShopOrderPlacedPayload{
+			ShopOrderPayload: entity.ShopOrderPayload{
+				ShopOrderID: shopOrder.ID,
+				OrderID:     order.ID,
+				ShopID:      shopOrder.ShopID,
+				UserID:      order.UserID,
+				OrderNumber: shopOrder.OrderNumber,
+			},
+			GrandTotal: shopOrder.GrandTotal,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *publisher) publishPaymentExpired(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload entity.PaymentExpiredPayload
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+	return p.publish(ctx, event, realtime.UserTopic(payload.UserID), payload)
+}
+
+// publishShopOrderEvent sends a shop order's status change to the buyer, and to the shop
+// as well when toShop is set.
+func (p *publisher) publishShopOrderEvent(toShop bool) outbox.Handler {
+	return func(ctx context.Context, event *entity.OutboxEvent) error {
+		var update shopOrderUpdate
+		if err := outbox.Decode(event, &update); err != nil {
+			return err
+		}
+
+		if err := p.publish(ctx, event, realtime.UserTopic(update.UserID), update); err != nil {
+			return err
+		}
+		if toShop {
+			return p.publish(ctx, event, realtime.ShopTopic(update.ShopID), update)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/internal/realtime"
+)
+
+type realtimeUsecase struct {
+	broker   domain.RealtimeBroker
+	shopRepo domain.ShopRepository
+}
+
+func NewRealtimeUsecase(broker domain.RealtimeBroker, shopRepo domain.ShopRepository) domain.RealtimeUsecase {
+	return &realtimeUsecase{
+		broker:   broker,
+		shopRepo: shopRepo,
+	}
+}
+
+func (u *realtimeUsecase) SubscribeBuyer(ctx context.Context, userID uuid.UUID) (domain.RealtimeSubscription, error) {
+	return u.broker.Subscribe(realtime.UserTopic(userID)), nil
+}
+
+func (u *realtimeUsecase) SubscribeShop(ctx context.Context, userID uuid.UUID) (domain.RealtimeSubscription, error) {
+	shop, err := u.shopRepo.GetShopByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shop for user: %w", err)
+	}
+	return u.broker.Subscribe(realtime.ShopTopic(shop.ID)), nil
+}
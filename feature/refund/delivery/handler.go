@@ -1,6 +1,7 @@
 package delivery
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -67,7 +68,7 @@ func (h *RefundHandler) CreateRefund(c echo.Context) error {
 // ApproveRefund godoc
 //
 //	@Summary		Approve refund
-//	@Description	Approve a refund request for shop owner
+//	@Description	Approve a pending refund request; payout is recorded separately via the complete endpoint
 //	@Tags			Refund
 //	@Security		BearerAuth
 //	@Accept			json
@@ -78,6 +79,7 @@ func (h *RefundHandler) CreateRefund(c echo.Context) error {
 //	@Failure		401			{object}	response.ResponseError
 //	@Failure		403			{object}	response.ResponseError
 //	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
 //	@Failure		500			{object}	response.ResponseError
 //	@Router			/api/shop/refunds/{refundId}/approve [put]
 func (h *RefundHandler) ApproveRefund(c echo.Context) error {
@@ -96,12 +98,298 @@ func (h *RefundHandler) ApproveRefund(c echo.Context) error {
 		if errors.Is(err, errmap.ErrForbidden) {
 			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
 		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotPending) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotPending.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
 		return response.Error(c, http.StatusInternalServerError, err.Error())
 	}
 
 	return response.Success(c, http.StatusOK, "refund approved successfully", refund)
 }
 
+// RejectRefund godoc
+//
+//	@Summary		Reject refund
+//	@Description	Reject a pending refund request with a reason
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string						true	"Refund ID"
+//	@Param			body		body		entity.RejectRefundRequest	true	"Rejection payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/refunds/{refundId}/reject [put]
+func (h *RefundHandler) RejectRefund(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	var req entity.RejectRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := h.usecase.RejectRefund(c.Request().Context(), userID, refundID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotPending) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotPending.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "refund rejected successfully", refund)
+}
+
+// CompleteRefund godoc
+//
+//	@Summary		Complete refund payout
+//	@Description	Mark an approved refund as paid out with the payout transaction ID
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string							true	"Refund ID"
+//	@Param			body		body		entity.CompleteRefundRequest	true	"Payout payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/refunds/{refundId}/complete [put]
+func (h *RefundHandler) CompleteRefund(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	var req entity.CompleteRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := h.usecase.CompleteRefund(c.Request().Context(), userID, refundID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotApproved) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotApproved.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundBankAccountRequired) {
+			return response.Error(c, http.StatusUnprocessableEntity, errmap.ErrRefundBankAccountRequired.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "refund completed successfully", refund)
+}
+
+// ListShopRefunds godoc
+//
+//	@Summary		List shop refunds
+//	@Description	Get list of refunds for the shop owner's orders
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page			query		int		false	"Page number (default: 1)"
+//	@Param			perPage			query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			refundStatusId	query		int		false	"Filter by refund status"
+//	@Param			shopOrderId		query		string	false	"Filter by shop order ID"
+//	@Success		200				{object}	entity.RefundListPaginationResponse
+//	@Failure		400				{object}	response.ResponseError
+//	@Failure		401				{object}	response.ResponseError
+//	@Failure		500				{object}	response.ResponseError
+//	@Router			/api/shop/refunds [get]
+func (h *RefundHandler) ListShopRefunds(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.RefundListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refunds, err := h.usecase.ListShopRefunds(c.Request().Context(), userID, req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrFailedToListRefunds.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", refunds)
+}
+
+// GetShopRefund godoc
+//
+//	@Summary		Get shop refund
+//	@Description	Get details of a refund belonging to the shop owner's order
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			refundId	path		string	true	"Refund ID"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/refunds/{refundId} [get]
+func (h *RefundHandler) GetShopRefund(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	refund, err := h.usecase.GetShopRefund(c.Request().Context(), userID, refundID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", refund)
+}
+
+// ListUserRefunds godoc
+//
+//	@Summary		List user refunds
+//	@Description	Get list of refunds for the customer's orders
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page			query		int		false	"Page number (default: 1)"
+//	@Param			perPage			query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			refundStatusId	query		int		false	"Filter by refund status"
+//	@Param			shopOrderId		query		string	false	"Filter by shop order ID"
+//	@Success		200				{object}	entity.RefundListPaginationResponse
+//	@Failure		400				{object}	response.ResponseError
+//	@Failure		401				{object}	response.ResponseError
+//	@Failure		500				{object}	response.ResponseError
+//	@Router			/api/refunds [get]
+func (h *RefundHandler) ListUserRefunds(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.RefundListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refunds, err := h.usecase.ListUserRefunds(c.Request().Context(), userID, req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrFailedToListRefunds.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", refunds)
+}
+
+// GetUserRefund godoc
+//
+//	@Summary		Get user refund
+//	@Description	Get details of a refund for one of the customer's orders
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			refundId	path		string	true	"Refund ID"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/refunds/{refundId} [get]
+func (h *RefundHandler) GetUserRefund(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	refund, err := h.usecase.GetUserRefund(c.Request().Context(), userID, refundID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", refund)
+}
+
 // SubmitRefundBankAccount godoc
 //
 //	@Summary		Submit bank account for refund
@@ -144,8 +432,415 @@ func (h *RefundHandler) SubmitRefundBankAccount(c echo.Context) error {
 		if errors.Is(err, errmap.ErrForbidden) {
 			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
 		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
 		return response.Error(c, http.StatusInternalServerError, err.Error())
 	}
 
 	return response.Success(c, http.StatusOK, "bank account submitted successfully", refund)
 }
+
+// RequestRefund godoc
+//
+//	@Summary		Request refund
+//	@Description	Buyer opens a refund request on a shipped, delivered or completed shop order with optional evidence
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.RequestRefundRequest	true	"Refund request payload"
+//	@Success		201		{object}	entity.RefundResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		422		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/refunds [post]
+func (h *RefundHandler) RequestRefund(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.RequestRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := h.usecase.RequestRefund(c.Request().Context(), userID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrOrderNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrOrderNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundAlreadyOpen) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundAlreadyOpen.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotEligible) {
+			return response.Error(c, http.StatusUnprocessableEntity, errmap.ErrRefundNotEligible.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundAmountExceedsTotal) {
+			return response.Error(c, http.StatusUnprocessableEntity, errmap.ErrRefundAmountExceedsTotal.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusCreated, "refund requested successfully", refund)
+}
+
+// AddRefundEvidence godoc
+//
+//	@Summary		Add refund evidence
+//	@Description	Buyer attaches additional evidence to an open refund request
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string							true	"Refund ID"
+//	@Param			body		body		entity.AddRefundEvidenceRequest	true	"Evidence payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/refunds/{refundId}/evidences [post]
+func (h *RefundHandler) AddRefundEvidence(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	var req entity.AddRefundEvidenceRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := h.usecase.AddRefundEvidence(c.Request().Context(), userID, refundID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotPending) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotPending.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "evidence added successfully", refund)
+}
+
+// CounterOfferRefund godoc
+//
+//	@Summary		Counter-offer refund
+//	@Description	Shop offers a partial amount on a pending buyer-initiated refund request
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string								true	"Refund ID"
+//	@Param			body		body		entity.CounterOfferRefundRequest	true	"Counter-offer payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		422			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/refunds/{refundId}/counter-offer [put]
+func (h *RefundHandler) CounterOfferRefund(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	var req entity.CounterOfferRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := h.usecase.CounterOfferRefund(c.Request().Context(), userID, refundID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotPending) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotPending.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotBuyerInitiated) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotBuyerInitiated.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		if errors.Is(err, errmap.ErrCounterOfferNotLower) {
+			return response.Error(c, http.StatusUnprocessableEntity, errmap.ErrCounterOfferNotLower.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "counter-offer sent successfully", refund)
+}
+
+// AcceptCounterOffer godoc
+//
+//	@Summary		Accept refund counter-offer
+//	@Description	Buyer accepts the shop's counter-offer; the refund is approved at the offered amount
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			refundId	path		string	true	"Refund ID"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/refunds/{refundId}/accept-offer [put]
+func (h *RefundHandler) AcceptCounterOffer(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	refund, err := h.usecase.AcceptCounterOffer(c.Request().Context(), userID, refundID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotCounterOffered) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotCounterOffered.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "counter-offer accepted successfully", refund)
+}
+
+// EscalateUserRefund godoc
+//
+//	@Summary		Escalate refund (buyer)
+//	@Description	Buyer escalates a counter-offered or rejected refund, or one the shop has not answered in time, to admin mediation
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string							true	"Refund ID"
+//	@Param			body		body		entity.EscalateRefundRequest	true	"Escalation payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/refunds/{refundId}/escalate [put]
+func (h *RefundHandler) EscalateUserRefund(c echo.Context) error {
+	return h.escalateRefund(c, h.usecase.EscalateUserRefund)
+}
+
+// EscalateShopRefund godoc
+//
+//	@Summary		Escalate refund (shop)
+//	@Description	Shop escalates a buyer-initiated refund to admin mediation once the buyer's response deadline has passed
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string							true	"Refund ID"
+//	@Param			body		body		entity.EscalateRefundRequest	true	"Escalation payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/refunds/{refundId}/escalate [put]
+func (h *RefundHandler) EscalateShopRefund(c echo.Context) error {
+	return h.escalateRefund(c, h.usecase.EscalateShopRefund)
+}
+
+func (h *RefundHandler) escalateRefund(c echo.Context, escalate func(context.Context, uuid.UUID, uuid.UUID, entity.EscalateRefundRequest) (*entity.RefundResponse, error)) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	var req entity.EscalateRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := escalate(c.Request().Context(), userID, refundID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundCannotEscalate) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundCannotEscalate.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "refund escalated successfully", refund)
+}
+
+// ListAdminRefunds godoc
+//
+//	@Summary		List all refunds (admin)
+//	@Description	Get list of refunds across all shops; filter by refundStatusId=6 for the mediation queue
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			page			query		int		false	"Page number (default: 1)"
+//	@Param			perPage			query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			refundStatusId	query		int		false	"Filter by refund status"
+//	@Param			shopOrderId		query		string	false	"Filter by shop order ID"
+//	@Success		200				{object}	entity.RefundListPaginationResponse
+//	@Failure		400				{object}	response.ResponseError
+//	@Failure		401				{object}	response.ResponseError
+//	@Failure		403				{object}	response.ResponseError
+//	@Failure		500				{object}	response.ResponseError
+//	@Router			/api/admin/refunds [get]
+func (h *RefundHandler) ListAdminRefunds(c echo.Context) error {
+	var req entity.RefundListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refunds, err := h.usecase.ListAdminRefunds(c.Request().Context(), req)
+	if err != nil {
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrFailedToListRefunds.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", refunds)
+}
+
+// ResolveRefund godoc
+//
+//	@Summary		Resolve escalated refund (admin)
+//	@Description	Admin mediator approves (optionally with an adjusted amount) or rejects an escalated refund
+//	@Tags			Refund
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			refundId	path		string						true	"Refund ID"
+//	@Param			body		body		entity.ResolveRefundRequest	true	"Resolution payload"
+//	@Success		200			{object}	entity.RefundResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		422			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/admin/refunds/{refundId}/resolve [put]
+func (h *RefundHandler) ResolveRefund(c echo.Context) error {
+	adminID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	refundID, err := uuid.Parse(c.Param("refundId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRefundID.Error())
+	}
+
+	var req entity.ResolveRefundRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	refund, err := h.usecase.ResolveRefund(c.Request().Context(), adminID, refundID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrRefundNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrRefundNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundNotEscalated) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundNotEscalated.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundStatusChanged) {
+			return response.Error(c, http.StatusConflict, errmap.ErrRefundStatusChanged.Error())
+		}
+		if errors.Is(err, errmap.ErrRefundAmountExceedsTotal) {
+			return response.Error(c, http.StatusUnprocessableEntity, errmap.ErrRefundAmountExceedsTotal.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "refund resolved successfully", refund)
+}
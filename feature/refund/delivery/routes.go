@@ -5,26 +5,44 @@ import (
 	"gorm.io/gorm"
 
 	"ecommerce-go-api/feature/order/repository"
+	outboxRepo "ecommerce-go-api/feature/outbox/repository"
 	refundRepo "ecommerce-go-api/feature/refund/repository"
 	"ecommerce-go-api/feature/refund/usecase"
 	shopRepo "ecommerce-go-api/feature/shop/repository"
+	"ecommerce-go-api/internal/dbtx"
 	"ecommerce-go-api/middleware"
 )
 
 func RegisterRoutes(group *echo.Group, handler *RefundHandler) {
 	shopRefunds := group.Group("/shop/refunds", middleware.JWTAuth(), middleware.ShopOwnerOnly())
+	shopRefunds.GET("", handler.ListShopRefunds)
 	shopRefunds.POST("", handler.CreateRefund)
-	shopRefunds.PUT("/:refundId/approve", handler.ApproveRefund)
+	shopRefunds.GET("/:refundId", handler.GetShopRefund)
+	shopRefunds.PUT("/:refundId/approve", handler.ApproveRefund, middleware.RequireTwoFactor())
+	shopRefunds.PUT("/:refundId/reject", handler.RejectRefund)
+	shopRefunds.PUT("/:refundId/complete", handler.CompleteRefund, middleware.RequireTwoFactor())
+	shopRefunds.PUT("/:refundId/counter-offer", handler.CounterOfferRefund)
+	shopRefunds.PUT("/:refundId/escalate", handler.EscalateShopRefund)
 
 	userRefunds := group.Group("/refunds", middleware.JWTAuth(), middleware.UserOnly())
-	userRefunds.PUT("/:refundId/bank-account", handler.SubmitRefundBankAccount)
+	userRefunds.GET("", handler.ListUserRefunds)
+	userRefunds.POST("", handler.RequestRefund)
+	userRefunds.GET("/:refundId", handler.GetUserRefund)
+	userRefunds.PUT("/:refundId/bank-account", handler.SubmitRefundBankAccount, middleware.RequireTwoFactor())
+	userRefunds.POST("/:refundId/evidences", handler.AddRefundEvidence)
+	userRefunds.PUT("/:refundId/accept-offer", handler.AcceptCounterOffer)
+	userRefunds.PUT("/:refundId/escalate", handler.EscalateUserRefund)
+
+	adminRefunds := group.Group("/admin/refunds", middleware.JWTAuth(), middleware.AdminOnly())
+	adminRefunds.GET("", handler.ListAdminRefunds)
+	adminRefunds.PUT("/:refundId/resolve", handler.ResolveRefund, middleware.RequireTwoFactor())
 }
 
 func RegisterRefundHandler(group *echo.Group, db *gorm.DB) {
 	refundRepository := refundRepo.NewRefundRepository(db)
 	orderRepository := repository.NewOrderRepository(db)
 	shopRepository := shopRepo.NewShopRepository(db)
-	refundUsecase := usecase.NewRefundUsecase(refundRepository, orderRepository, shopRepository)
+	refundUsecase := usecase.NewRefundUsecase(refundRepository, orderRepository, shopRepository, outboxRepo.NewOutboxRepository(db), dbtx.NewTransactor(db))
 	handler := NewRefundHandler(refundUsecase)
 	RegisterRoutes(group, handler)
 }
@@ -2,12 +2,17 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/dbtx"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/fieldcrypt"
 	"ecommerce-go-api/internal/timeth"
 )
 
@@ -19,43 +24,276 @@ func NewRefundRepository(db *gorm.DB) domain.RefundRepository {
 	return &refundRepository{db: db}
 }
 
+// conn joins the transaction carried by ctx, if any.
+func (r *refundRepository) conn(ctx context.Context) *gorm.DB {
+	return dbtx.From(ctx, r.db)
+}
+
 func (r *refundRepository) CreateRefund(ctx context.Context, refund *entity.Refund) error {
-	return r.db.WithContext(ctx).Create(refund).Error
+	bankAccount := refund.BankAccount
+	if bankAccount != "" {
+		encrypted, err := fieldcrypt.Encrypt(bankAccount)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt bank account: %w", err)
+		}
+		refund.BankAccount = encrypted
+	}
+
+	err := r.conn(ctx).Create(refund).Error
+	refund.BankAccount = bankAccount
+	return err
+}
+
+// decryptRefund replaces the stored bank account ciphertext with its plaintext.
+func decryptRefund(refund *entity.Refund) error {
+	bankAccount, err := fieldcrypt.Decrypt(refund.BankAccount)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt bank account for refund_id=%s: %w", refund.ID, err)
+	}
+	refund.BankAccount = bankAccount
+	return nil
 }
 
 func (r *refundRepository) GetRefundByID(ctx context.Context, id uuid.UUID) (*entity.Refund, error) {
 	var refund entity.Refund
-	err := r.db.WithContext(ctx).
+	err := r.conn(ctx).
 		Preload("ShopOrder").
 		Preload("Payment").
+		Preload("Evidences", func(db *gorm.DB) *gorm.DB {
+			return db.Order("refund_evidences.created_at ASC")
+		}).
 		First(&refund, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
+	if err := decryptRefund(&refund); err != nil {
+		return nil, err
+	}
 	return &refund, nil
 }
 
-func (r *refundRepository) UpdateRefundStatus(ctx context.Context, id uuid.UUID, statusID uint32) error {
+func (r *refundRepository) ListRefundsByShopID(ctx context.Context, shopID uuid.UUID, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	query := r.conn(ctx).Model(&entity.Refund{}).
+		Joins("JOIN shop_orders ON shop_orders.id = refunds.shop_order_id").
+		Where("shop_orders.shop_id = ?", shopID)
+
+	return r.listRefunds(query, req)
+}
+
+func (r *refundRepository) ListRefundsByUserID(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	query := r.conn(ctx).Model(&entity.Refund{}).
+		Joins("JOIN shop_orders ON shop_orders.id = refunds.shop_order_id").
+		Joins("JOIN orders ON orders.id = shop_orders.order_id").
+		Where("orders.user_id = ?", userID)
+
+	return r.listRefunds(query, req)
+}
+
+func (r *refundRepository) ListRefunds(ctx context.Context, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	query := r.conn(ctx).Model(&entity.Refund{})
+
+	return r.listRefunds(query, req)
+}
+
+func (r *refundRepository) HasOpenRefund(ctx context.Context, shopOrderID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.conn(ctx).
+		Model(&entity.Refund{}).
+		Where("shop_order_id = ?", shopOrderID).
+		Where("refund_status_id IN ?", []uint32{
+			entity.RefundStatusPending,
+			entity.RefundStatusApproved,
+			entity.RefundStatusCounterOffered,
+			entity.RefundStatusEscalated,
+		}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *refundRepository) GetRefundedAmount(ctx context.Context, shopOrderID uuid.UUID) (float64, error) {
+	var refunded float64
+	err := r.conn(ctx).
+		Model(&entity.Refund{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("shop_order_id = ?", shopOrderID).
+		Where("refund_status_id IN ?", []uint32{entity.RefundStatusApproved, entity.RefundStatusCompleted}).
+		Scan(&refunded).Error
+	return refunded, err
+}
+
+func (r *refundRepository) listRefunds(query *gorm.DB, req entity.RefundListRequest) ([]*entity.Refund, int64, error) {
+	var refunds []*entity.Refund
+	var total int64
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	if req.RefundStatusID != nil {
+		query = query.Where("refunds.refund_status_id = ?", *req.RefundStatusID)
+	}
+
+	if req.ShopOrderID != nil {
+		query = query.Where("refunds.shop_order_id = ?", *req.ShopOrderID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Preload("RefundStatus").
+		Preload("RefundMethod").
+		Preload("Evidences").
+		Order("refunds.created_at DESC").
+		Limit(int(perPage)).
+		Offset(int(offset)).
+		Find(&refunds).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for _, refund := range refunds {
+		if err := decryptRefund(refund); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return refunds, total, nil
+}
+
+// changeStatus applies updates to the refund only while it is still in fromStatusID,
+// so two requests acting on the same refund cannot both change it.
+func (r *refundRepository) changeStatus(ctx context.Context, id uuid.UUID, fromStatusID uint32, updates map[string]interface{}) error {
+	res := r.conn(ctx).
+		Model(&entity.Refund{}).
+		Where("id = ? AND refund_status_id = ?", id, fromStatusID).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return errmap.ErrRefundStatusChanged
+	}
+	return nil
+}
+
+func (r *refundRepository) UpdateRefundStatus(ctx context.Context, id uuid.UUID, fromStatusID uint32, statusID uint32) error {
+	now := timeth.Now()
 	updates := map[string]interface{}{
 		"refund_status_id": statusID,
-		"updated_at":       timeth.Now(),
+		"updated_at":       now,
 	}
 
-	if statusID == entity.RefundStatusCompleted {
-		now := timeth.Now()
+	switch statusID {
+	case entity.RefundStatusApproved:
+		updates["approved_at"] = now
+	case entity.RefundStatusCompleted:
 		updates["refunded_at"] = now
 	}
 
-	return r.db.WithContext(ctx).Model(&entity.Refund{}).Where("id = ?", id).Updates(updates).Error
+	return r.changeStatus(ctx, id, fromStatusID, updates)
+}
+
+func (r *refundRepository) RejectRefund(ctx context.Context, id uuid.UUID, reason string) error {
+	now := timeth.Now()
+	return r.changeStatus(ctx, id, entity.RefundStatusPending, map[string]interface{}{
+		"refund_status_id": entity.RefundStatusRejected,
+		"reject_reason":    reason,
+		"rejected_at":      now,
+		"updated_at":       now,
+	})
+}
+
+func (r *refundRepository) CompleteRefund(ctx context.Context, id uuid.UUID, transactionID string) error {
+	now := timeth.Now()
+	return r.changeStatus(ctx, id, entity.RefundStatusApproved, map[string]interface{}{
+		"refund_status_id": entity.RefundStatusCompleted,
+		"transaction_id":   transactionID,
+		"refunded_at":      now,
+		"updated_at":       now,
+	})
 }
 
 func (r *refundRepository) UpdateRefundBankAccount(ctx context.Context, id uuid.UUID, bankAccount, bankName string) error {
-	return r.db.WithContext(ctx).
+	encrypted, err := fieldcrypt.Encrypt(bankAccount)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt bank account: %w", err)
+	}
+
+	return r.conn(ctx).
 		Model(&entity.Refund{}).
 		Where("id = ?", id).
 		Updates(map[string]interface{}{
-			"bank_account": bankAccount,
+			"bank_account": encrypted,
 			"bank_name":    bankName,
 			"updated_at":   timeth.Now(),
 		}).Error
 }
+
+func (r *refundRepository) AddRefundEvidences(ctx context.Context, evidences []*entity.RefundEvidence) error {
+	if len(evidences) == 0 {
+		return nil
+	}
+	return r.conn(ctx).Create(&evidences).Error
+}
+
+func (r *refundRepository) CounterOfferRefund(ctx context.Context, id uuid.UUID, amount float64, note string, responseDueAt time.Time) error {
+	return r.changeStatus(ctx, id, entity.RefundStatusPending, map[string]interface{}{
+		"refund_status_id": entity.RefundStatusCounterOffered,
+		"counter_amount":   amount,
+		"counter_note":     note,
+		"response_due_at":  responseDueAt,
+		"updated_at":       timeth.Now(),
+	})
+}
+
+func (r *refundRepository) AcceptCounterOffer(ctx context.Context, id uuid.UUID) error {
+	now := timeth.Now()
+	return r.changeStatus(ctx, id, entity.RefundStatusCounterOffered, map[string]interface{}{
+		"refund_status_id": entity.RefundStatusApproved,
+		"amount":           gorm.Expr("counter_amount"),
+		"approved_at":      now,
+		"response_due_at":  nil,
+		"updated_at":       now,
+	})
+}
+
+func (r *refundRepository) EscalateRefund(ctx context.Context, id uuid.UUID, fromStatusID uint32, escalatedBy uuid.UUID) error {
+	now := timeth.Now()
+	return r.changeStatus(ctx, id, fromStatusID, map[string]interface{}{
+		"refund_status_id": entity.RefundStatusEscalated,
+		"escalated_at":     now,
+		"escalated_by":     escalatedBy,
+		"response_due_at":  nil,
+		"updated_at":       now,
+	})
+}
+
+func (r *refundRepository) ResolveRefund(ctx context.Context, id uuid.UUID, statusID uint32, amount float64, note string) error {
+	now := timeth.Now()
+	updates := map[string]interface{}{
+		"refund_status_id": statusID,
+		"amount":           amount,
+		"resolution_note":  note,
+		"updated_at":       now,
+	}
+
+	switch statusID {
+	case entity.RefundStatusApproved:
+		updates["approved_at"] = now
+	case entity.RefundStatusRejected:
+		updates["rejected_at"] = now
+		updates["reject_reason"] = note
+	}
+
+	return r.changeStatus(ctx, id, entity.RefundStatusEscalated, updates)
+}
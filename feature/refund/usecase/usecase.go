@@ -2,13 +2,20 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/constant"
 	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/fieldcrypt"
+	"ecommerce-go-api/internal/outbox"
 	"ecommerce-go-api/internal/timeth"
 )
 
@@ -16,27 +23,222 @@ type refundUsecase struct {
 	refundRepo domain.RefundRepository
 	orderRepo  domain.OrderRepository
 	shopRepo   domain.ShopRepository
+	outboxRepo domain.OutboxRepository
+	tx         domain.Transactor
 }
 
-func NewRefundUsecase(refundRepo domain.RefundRepository, orderRepo domain.OrderRepository, shopRepo domain.ShopRepository) domain.RefundUsecase {
+func NewRefundUsecase(refundRepo domain.RefundRepository, orderRepo domain.OrderRepository, shopRepo domain.ShopRepository, outboxRepo domain.OutboxRepository, tx domain.Transactor) domain.RefundUsecase {
 	return &refundUsecase{
 		refundRepo: refundRepo,
 		orderRepo:  orderRepo,
 		shopRepo:   shopRepo,
+		outboxRepo: outboxRepo,
+		tx:         tx,
 	}
 }
 
+// mapToRefundResponse builds the API view of a refund. The bank account number is
+// only returned in full to shop members who pay refunds out or view the shop's
+// finances, and to admins; buyers and other members see it masked.
+func mapToRefundResponse(r *entity.Refund, revealBankAccount bool) *entity.RefundResponse {
+	if r == nil {
+		return nil
+	}
+
+	bankAccount := r.BankAccount
+	if !revealBankAccount && bankAccount != "" {
+		bankAccount = fieldcrypt.Mask(bankAccount, 4)
+	}
+
+	evidences := make([]entity.RefundEvidenceResponse, 0, len(r.Evidences))
+	for _, e := range r.Evidences {
+		evidences = append(evidences, entity.RefundEvidenceResponse{
+			ID:        e.ID,
+			URL:       e.URL,
+			Note:      e.Note,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return &entity.RefundResponse{
+		ID:             r.ID,
+		ShopOrderID:    r.ShopOrderID,
+		PaymentID:      r.PaymentID,
+		Amount:         r.Amount,
+		RefundMethodID: r.RefundMethodID,
+		RefundStatusID: r.RefundStatusID,
+		Reason:         r.Reason,
+		Initiator:      r.Initiator,
+		ReasonCode:     r.ReasonCode,
+		CounterAmount:  r.CounterAmount,
+		CounterNote:    r.CounterNote,
+		ResponseDueAt:  r.ResponseDueAt,
+		EscalatedAt:    r.EscalatedAt,
+		ResolutionNote: r.ResolutionNote,
+		BankAccount:    bankAccount,
+		BankName:       r.BankName,
+		TransactionID:  r.TransactionID,
+		RejectReason:   r.RejectReason,
+		ApprovedAt:     r.ApprovedAt,
+		RejectedAt:     r.RejectedAt,
+		RefundedAt:     r.RefundedAt,
+		CreatedAt:      r.CreatedAt,
+		UpdatedAt:      r.UpdatedAt,
+		Evidences:      evidences,
+	}
+}
+
+func mapToRefundListResponse(refunds []*entity.Refund, total int64, revealBankAccount bool) *entity.RefundListPaginationResponse {
+	items := make([]*entity.RefundResponse, 0, len(refunds))
+	for _, r := range refunds {
+		items = append(items, mapToRefundResponse(r, revealBankAccount))
+	}
+
+	return &entity.RefundListPaginationResponse{
+		Items: items,
+		Total: total,
+	}
+}
+
+func (u *refundUsecase) getRefund(ctx context.Context, refundID uuid.UUID) (*entity.Refund, error) {
+	refund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrRefundNotFound
+		}
+		return nil, err
+	}
+	return refund, nil
+}
+
+// getShopRefund loads a refund and checks that userID is a member of its shop with the
+// permission. An empty permission only requires membership.
+func (u *refundUsecase) getShopRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, permission string) (*entity.Refund, *entity.ShopOrder, *entity.ShopMember, error) {
+	refund, err := u.getRefund(ctx, refundID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, refund.ShopOrderID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	member, err := u.shopRepo.GetShopMember(ctx, shopOrder.ShopID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if member == nil || (permission != "" && !member.Can(permission)) {
+		return nil, nil, nil, errmap.ErrForbidden
+	}
+
+	return refund, shopOrder, member, nil
+}
+
+// canSeeBankAccounts reports whether the shop member is shown buyers' bank account
+// numbers in full: those who pay refunds out or look after the shop's finances.
+func canSeeBankAccounts(member *entity.ShopMember) bool {
+	return member.Can(entity.ShopPermissionIssueRefunds) || member.Can(entity.ShopPermissionViewFinance)
+}
+
+// getUserRefund loads a refund and checks that it belongs to an order placed by userID.
+func (u *refundUsecase) getUserRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.Refund, *entity.ShopOrder, error) {
+	refund, err := u.getRefund(ctx, refundID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, refund.ShopOrderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if shopOrder.Order.UserID != userID {
+		return nil, nil, errmap.ErrForbidden
+	}
+
+	return refund, shopOrder, nil
+}
+
+// refundableAmount is what is left of the shop order's grand total after its approved
+// and completed refunds: the most a new refund, or a mediated approval, may pay back.
+func (u *refundUsecase) refundableAmount(ctx context.Context, shopOrder *entity.ShopOrder) (float64, error) {
+	refunded, err := u.refundRepo.GetRefundedAmount(ctx, shopOrder.ID)
+	if err != nil {
+		return 0, err
+	}
+	return math.Round((shopOrder.GrandTotal-refunded)*100) / 100, nil
+}
+
+func newRefundLog(shopOrder *entity.ShopOrder, refundID uuid.UUID, refundStatusID uint32, note string, userID *uuid.UUID) *entity.OrderLog {
+	now := timeth.Now()
+	return &entity.OrderLog{
+		OrderID:        shopOrder.OrderID,
+		ShopOrderID:    &shopOrder.ID,
+		OrderStatusID:  shopOrder.OrderStatusID,
+		RefundID:       &refundID,
+		RefundStatusID: &refundStatusID,
+		Note:           note,
+		CreatedBy:      userID,
+		CreatedAt:      &now,
+	}
+}
+
+func (u *refundUsecase) createRefundLog(ctx context.Context, shopOrder *entity.ShopOrder, refundID uuid.UUID, refundStatusID uint32, note string, userID *uuid.UUID) {
+	if err := u.orderRepo.CreateOrderLog(ctx, newRefundLog(shopOrder, refundID, refundStatusID, note, userID)); err != nil {
+		log.Printf("[ERROR] Failed to create refund log for refund_id=%s, shop_order_id=%s: %v", refundID, shopOrder.ID, err)
+	}
+}
+
+// changeRefundStatus runs change, the refund log for the new status and the outbox event
+// of eventType, if any, in one transaction. A paid-out refund also moves its payment to
+// REFUNDED or PARTIALLY_REFUNDED.
+func (u *refundUsecase) changeRefundStatus(ctx context.Context, shopOrder *entity.ShopOrder, refund *entity.Refund, refundStatusID uint32, eventType string, amount float64, note string, userID *uuid.UUID, change func(ctx context.Context) error) error {
+	refundID := refund.ID
+	return u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+
+		if err := u.orderRepo.CreateOrderLog(ctx, newRefundLog(shopOrder, refundID, refundStatusID, note, userID)); err != nil {
+			return fmt.Errorf("failed to create refund log: %w", err)
+		}
+
+		if refundStatusID == entity.RefundStatusCompleted && refund.PaymentID != nil {
+			if err := u.orderRepo.UpdatePaymentRefundStatus(ctx, *refund.PaymentID); err != nil {
+				return fmt.Errorf("failed to update payment refund status: %w", err)
+			}
+		}
+
+		if eventType == "" {
+			return nil
+		}
+		event, err := outbox.NewEvent(eventType, entity.AggregateRefund, refundID, entity.RefundPayload{
+			RefundID:    refundID,
+			ShopOrderID: shopOrder.ID,
+			OrderID:     shopOrder.OrderID,
+			ShopID:      shopOrder.ShopID,
+			UserID:      shopOrder.Order.UserID,
+			Amount:      amount,
+		})
+		if err != nil {
+			return err
+		}
+		return u.outboxRepo.Add(ctx, event)
+	})
+}
+
 func (u *refundUsecase) CreateRefund(ctx context.Context, userID uuid.UUID, req entity.CreateRefundRequest) (*entity.RefundResponse, error) {
 	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, req.ShopOrderID)
 	if err != nil {
 		return nil, fmt.Errorf("shop order not found: %w", err)
 	}
 
-	shop, err := u.shopRepo.GetShopByID(ctx, shopOrder.ShopID)
+	member, err := u.shopRepo.GetShopMember(ctx, shopOrder.ShopID, userID)
 	if err != nil {
 		return nil, err
 	}
-	if shop.UserID != userID {
+	if !member.Can(entity.ShopPermissionIssueRefunds) {
 		return nil, errmap.ErrForbidden
 	}
 
@@ -53,10 +255,7 @@ func (u *refundUsecase) CreateRefund(ctx context.Context, userID uuid.UUID, req
 		return nil, fmt.Errorf("refund can only be created when payment_status = 6")
 	}
 
-	var refundMethodID uint32 = entity.RefundMethodBankTransfer
-	if payment.PaymentMethodID == entity.PaymentMethodCreditCard {
-		refundMethodID = entity.RefundMethodCreditCard
-	}
+	refundMethodID := entity.RefundMethodForPayment(payment.PaymentMethodID)
 
 	now := timeth.Now()
 	refund := &entity.Refund{
@@ -74,44 +273,57 @@ func (u *refundUsecase) CreateRefund(ctx context.Context, userID uuid.UUID, req
 		return nil, fmt.Errorf("failed to create refund: %w", err)
 	}
 
-	return &entity.RefundResponse{
-		ID:             refund.ID,
-		ShopOrderID:    refund.ShopOrderID,
-		PaymentID:      refund.PaymentID,
-		Amount:         refund.Amount,
-		RefundMethodID: refund.RefundMethodID,
-		RefundStatusID: refund.RefundStatusID,
-		Reason:         refund.Reason,
-		CreatedAt:      refund.CreatedAt,
-		UpdatedAt:      refund.UpdatedAt,
-	}, nil
+	u.createRefundLog(ctx, shopOrder, refund.ID, entity.RefundStatusPending, fmt.Sprintf("Refund requested: %s", req.Reason), &userID)
+
+	return mapToRefundResponse(refund, true), nil
 }
 
 func (u *refundUsecase) ApproveRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error) {
-	refund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	refund, shopOrder, _, err := u.getShopRefund(ctx, userID, refundID, entity.ShopPermissionIssueRefunds)
 	if err != nil {
-		return nil, fmt.Errorf("refund not found: %w", err)
+		return nil, err
 	}
 
-	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, refund.ShopOrderID)
+	if refund.RefundStatusID != entity.RefundStatusPending {
+		return nil, errmap.ErrRefundNotPending
+	}
+
+	err = u.changeRefundStatus(ctx, shopOrder, refund, entity.RefundStatusApproved, entity.EventRefundApproved, refund.Amount, "Refund approved", &userID, func(ctx context.Context) error {
+		if err := u.refundRepo.UpdateRefundStatus(ctx, refundID, entity.RefundStatusPending, entity.RefundStatusApproved); err != nil {
+			return fmt.Errorf("failed to approve refund: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	shop, err := u.shopRepo.GetShopByID(ctx, shopOrder.ShopID)
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
 	if err != nil {
 		return nil, err
 	}
-	if shop.UserID != userID {
-		return nil, errmap.ErrForbidden
+
+	return mapToRefundResponse(updatedRefund, true), nil
+}
+
+func (u *refundUsecase) RejectRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.RejectRefundRequest) (*entity.RefundResponse, error) {
+	refund, shopOrder, _, err := u.getShopRefund(ctx, userID, refundID, entity.ShopPermissionIssueRefunds)
+	if err != nil {
+		return nil, err
 	}
 
 	if refund.RefundStatusID != entity.RefundStatusPending {
-		return nil, fmt.Errorf("refund is not in pending status")
+		return nil, errmap.ErrRefundNotPending
 	}
 
-	if err := u.refundRepo.UpdateRefundStatus(ctx, refundID, entity.RefundStatusCompleted); err != nil {
-		return nil, fmt.Errorf("failed to approve refund: %w", err)
+	err = u.changeRefundStatus(ctx, shopOrder, refund, entity.RefundStatusRejected, "", refund.Amount, fmt.Sprintf("Refund rejected: %s", req.Reason), &userID, func(ctx context.Context) error {
+		if err := u.refundRepo.RejectRefund(ctx, refundID, req.Reason); err != nil {
+			return fmt.Errorf("failed to reject refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
@@ -119,46 +331,299 @@ func (u *refundUsecase) ApproveRefund(ctx context.Context, userID uuid.UUID, ref
 		return nil, err
 	}
 
-	return &entity.RefundResponse{
-		ID:             updatedRefund.ID,
-		ShopOrderID:    updatedRefund.ShopOrderID,
-		PaymentID:      updatedRefund.PaymentID,
-		Amount:         updatedRefund.Amount,
-		RefundMethodID: updatedRefund.RefundMethodID,
-		RefundStatusID: updatedRefund.RefundStatusID,
-		Reason:         updatedRefund.Reason,
-		RefundedAt:     updatedRefund.RefundedAt,
-		CreatedAt:      updatedRefund.CreatedAt,
-		UpdatedAt:      updatedRefund.UpdatedAt,
-	}, nil
+	return mapToRefundResponse(updatedRefund, true), nil
+}
+
+func (u *refundUsecase) CompleteRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.CompleteRefundRequest) (*entity.RefundResponse, error) {
+	refund, shopOrder, _, err := u.getShopRefund(ctx, userID, refundID, entity.ShopPermissionIssueRefunds)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.RefundStatusID != entity.RefundStatusApproved {
+		return nil, errmap.ErrRefundNotApproved
+	}
+
+	if refund.RefundMethodID != nil && *refund.RefundMethodID == entity.RefundMethodBankTransfer && refund.BankAccount == "" {
+		return nil, errmap.ErrRefundBankAccountRequired
+	}
+
+	err = u.changeRefundStatus(ctx, shopOrder, refund, entity.RefundStatusCompleted, entity.EventRefundCompleted, refund.Amount, fmt.Sprintf("Refund paid out (Transaction: %s)", req.TransactionID), &userID, func(ctx context.Context) error {
+		if err := u.refundRepo.CompleteRefund(ctx, refundID, req.TransactionID); err != nil {
+			return fmt.Errorf("failed to complete refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(updatedRefund, true), nil
+}
+
+func (u *refundUsecase) ListShopRefunds(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error) {
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, total, err := u.refundRepo.ListRefundsByShopID(ctx, member.ShopID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundListResponse(refunds, total, canSeeBankAccounts(member)), nil
+}
+
+func (u *refundUsecase) GetShopRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	refund, _, member, err := u.getShopRefund(ctx, userID, refundID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(refund, canSeeBankAccounts(member)), nil
+}
+
+func (u *refundUsecase) ListUserRefunds(ctx context.Context, userID uuid.UUID, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error) {
+	refunds, total, err := u.refundRepo.ListRefundsByUserID(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundListResponse(refunds, total, false), nil
+}
+
+func (u *refundUsecase) GetUserRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	refund, _, err := u.getUserRefund(ctx, userID, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(refund, false), nil
 }
 
 func (u *refundUsecase) SubmitRefundBankAccount(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.SubmitRefundBankAccountRequest) (*entity.RefundResponse, error) {
-	refund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	refund, _, err := u.getUserRefund(ctx, userID, refundID)
 	if err != nil {
-		return nil, fmt.Errorf("refund not found: %w", err)
+		return nil, err
 	}
 
-	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, refund.ShopOrderID)
+	if refund.RefundStatusID != entity.RefundStatusPending && refund.RefundStatusID != entity.RefundStatusApproved {
+		return nil, fmt.Errorf("cannot submit bank account for refund in status %d", refund.RefundStatusID)
+	}
+
+	if err := u.refundRepo.UpdateRefundBankAccount(ctx, refundID, req.BankAccount, req.BankName); err != nil {
+		return nil, fmt.Errorf("failed to update bank account: %w", err)
+	}
+
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
 	if err != nil {
 		return nil, err
 	}
 
-	order, err := u.orderRepo.GetOrderByID(ctx, shopOrder.OrderID)
+	return mapToRefundResponse(updatedRefund, false), nil
+}
+
+func (u *refundUsecase) RequestRefund(ctx context.Context, userID uuid.UUID, req entity.RequestRefundRequest) (*entity.RefundResponse, error) {
+	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, req.ShopOrderID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrOrderNotFound
+		}
 		return nil, err
 	}
 
-	if order.UserID != userID {
+	if shopOrder.Order.UserID != userID {
 		return nil, errmap.ErrForbidden
 	}
 
+	switch shopOrder.OrderStatusID {
+	case entity.OrderStatusShipped, entity.OrderStatusDelivered, entity.OrderStatusCompleted:
+	default:
+		return nil, errmap.ErrRefundNotEligible
+	}
+
+	payment, err := u.orderRepo.GetPaymentByOrderID(ctx, shopOrder.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("payment not found for this order: %w", err)
+	}
+	// Cancelling another shop order of the same order partially refunds the payment; this
+	// shop order's share is still paid.
+	if payment.PaymentStatusID != entity.PaymentStatusCompleted && payment.PaymentStatusID != entity.PaymentStatusPartiallyRefunded {
+		return nil, errmap.ErrRefundNotEligible
+	}
+
+	hasOpen, err := u.refundRepo.HasOpenRefund(ctx, shopOrder.ID)
+	if err != nil {
+		return nil, err
+	}
+	if hasOpen {
+		return nil, errmap.ErrRefundAlreadyOpen
+	}
+
+	// Earlier refunds that were paid out, or are about to be, are not open any more, so
+	// the new request may only ask for what they left.
+	amount, err := u.refundableAmount(ctx, shopOrder)
+	if err != nil {
+		return nil, err
+	}
+	if amount <= 0 {
+		return nil, errmap.ErrRefundNotEligible
+	}
+	if req.Amount != nil {
+		if *req.Amount > amount {
+			return nil, errmap.ErrRefundAmountExceedsTotal
+		}
+		amount = *req.Amount
+	}
+
+	now := timeth.Now()
+	responseDueAt := now.Add(constant.RefundResponseWindow)
+	refundMethodID := entity.RefundMethodForPayment(payment.PaymentMethodID)
+
+	evidences := make([]entity.RefundEvidence, 0, len(req.Evidences))
+	for _, e := range req.Evidences {
+		evidences = append(evidences, entity.RefundEvidence{
+			URL:        e.URL,
+			Note:       e.Note,
+			UploadedBy: &userID,
+			CreatedAt:  now,
+		})
+	}
+
+	refund := &entity.Refund{
+		ShopOrderID:    shopOrder.ID,
+		PaymentID:      &payment.ID,
+		Amount:         amount,
+		RefundMethodID: &refundMethodID,
+		RefundStatusID: entity.RefundStatusPending,
+		Reason:         req.Reason,
+		Initiator:      entity.RefundInitiatorBuyer,
+		ReasonCode:     req.ReasonCode,
+		RequestedBy:    &userID,
+		ResponseDueAt:  &responseDueAt,
+		Evidences:      evidences,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.refundRepo.CreateRefund(ctx, refund); err != nil {
+			return fmt.Errorf("failed to create refund: %w", err)
+		}
+
+		note := fmt.Sprintf("Buyer requested refund (%s): %s", req.ReasonCode, req.Reason)
+		if err := u.orderRepo.CreateOrderLog(ctx, newRefundLog(shopOrder, refund.ID, entity.RefundStatusPending, note, &userID)); err != nil {
+			return fmt.Errorf("failed to create refund log: %w", err)
+		}
+
+		event, err := outbox.NewEvent(entity.EventRefundRequested, entity.AggregateRefund, refund.ID, entity.RefundRequestedPayload{
+			RefundPayload: entity.RefundPayload{
+				RefundID:    refund.ID,
+				ShopOrderID: shopOrder.ID,
+				OrderID:     shopOrder.OrderID,
+				ShopID:      shopOrder.ShopID,
+				UserID:      userID,
+				Amount:      amount,
+			},
+			ReasonCode: req.ReasonCode,
+			Reason:     req.Reason,
+		})
+		if err != nil {
+			return err
+		}
+		return u.outboxRepo.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(refund, false), nil
+}
+
+// AddRefundEvidence attaches more evidence to a refund that is still being negotiated or
+// mediated. The refund keeps its status; the evidence is logged under it.
+func (u *refundUsecase) AddRefundEvidence(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.AddRefundEvidenceRequest) (*entity.RefundResponse, error) {
+	refund, shopOrder, err := u.getUserRefund(ctx, userID, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch refund.RefundStatusID {
+	case entity.RefundStatusPending, entity.RefundStatusCounterOffered, entity.RefundStatusEscalated:
+	default:
+		return nil, errmap.ErrRefundNotPending
+	}
+
+	now := timeth.Now()
+	evidences := make([]*entity.RefundEvidence, 0, len(req.Evidences))
+	for _, e := range req.Evidences {
+		evidences = append(evidences, &entity.RefundEvidence{
+			RefundID:   refundID,
+			URL:        e.URL,
+			Note:       e.Note,
+			UploadedBy: &userID,
+			CreatedAt:  now,
+		})
+	}
+
+	note := fmt.Sprintf("Buyer attached %d more evidence item(s)", len(evidences))
+	err = u.changeRefundStatus(ctx, shopOrder, refund, refund.RefundStatusID, entity.EventRefundEvidenceAdded, refund.Amount, note, &userID, func(ctx context.Context) error {
+		// Moving the refund to the status it is in only succeeds if nobody has
+		// settled it in the meantime.
+		if err := u.refundRepo.UpdateRefundStatus(ctx, refundID, refund.RefundStatusID, refund.RefundStatusID); err != nil {
+			return err
+		}
+		if err := u.refundRepo.AddRefundEvidences(ctx, evidences); err != nil {
+			return fmt.Errorf("failed to add refund evidence: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(updatedRefund, false), nil
+}
+
+func (u *refundUsecase) CounterOfferRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.CounterOfferRefundRequest) (*entity.RefundResponse, error) {
+	refund, shopOrder, _, err := u.getShopRefund(ctx, userID, refundID, entity.ShopPermissionIssueRefunds)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.Initiator != entity.RefundInitiatorBuyer {
+		return nil, errmap.ErrRefundNotBuyerInitiated
+	}
+
 	if refund.RefundStatusID != entity.RefundStatusPending {
-		return nil, fmt.Errorf("cannot submit bank account for non-pending refund")
+		return nil, errmap.ErrRefundNotPending
 	}
 
-	if err := u.refundRepo.UpdateRefundBankAccount(ctx, refundID, req.BankAccount, req.BankName); err != nil {
-		return nil, fmt.Errorf("failed to update bank account: %w", err)
+	if req.Amount >= refund.Amount {
+		return nil, errmap.ErrCounterOfferNotLower
+	}
+
+	responseDueAt := timeth.Now().Add(constant.RefundResponseWindow)
+	note := fmt.Sprintf("Shop counter-offered %.2f: %s", req.Amount, req.Note)
+	err = u.changeRefundStatus(ctx, shopOrder, refund, entity.RefundStatusCounterOffered, entity.EventRefundCounterOffered, req.Amount, note, &userID, func(ctx context.Context) error {
+		if err := u.refundRepo.CounterOfferRefund(ctx, refundID, req.Amount, req.Note, responseDueAt); err != nil {
+			return fmt.Errorf("failed to counter-offer refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
@@ -166,17 +631,168 @@ func (u *refundUsecase) SubmitRefundBankAccount(ctx context.Context, userID uuid
 		return nil, err
 	}
 
-	return &entity.RefundResponse{
-		ID:             updatedRefund.ID,
-		ShopOrderID:    updatedRefund.ShopOrderID,
-		PaymentID:      updatedRefund.PaymentID,
-		Amount:         updatedRefund.Amount,
-		RefundMethodID: updatedRefund.RefundMethodID,
-		RefundStatusID: updatedRefund.RefundStatusID,
-		Reason:         updatedRefund.Reason,
-		BankAccount:    updatedRefund.BankAccount,
-		BankName:       updatedRefund.BankName,
-		CreatedAt:      updatedRefund.CreatedAt,
-		UpdatedAt:      updatedRefund.UpdatedAt,
-	}, nil
+	return mapToRefundResponse(updatedRefund, true), nil
+}
+
+func (u *refundUsecase) AcceptCounterOffer(ctx context.Context, userID uuid.UUID, refundID uuid.UUID) (*entity.RefundResponse, error) {
+	refund, shopOrder, err := u.getUserRefund(ctx, userID, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.RefundStatusID != entity.RefundStatusCounterOffered || refund.CounterAmount == nil {
+		return nil, errmap.ErrRefundNotCounterOffered
+	}
+
+	err = u.changeRefundStatus(ctx, shopOrder, refund, entity.RefundStatusApproved, entity.EventRefundApproved, *refund.CounterAmount, fmt.Sprintf("Buyer accepted counter-offer of %.2f", *refund.CounterAmount), &userID, func(ctx context.Context) error {
+		if err := u.refundRepo.AcceptCounterOffer(ctx, refundID); err != nil {
+			return fmt.Errorf("failed to accept counter-offer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(updatedRefund, false), nil
+}
+
+// canEscalate reports whether a buyer-initiated refund may be handed to admin
+// mediation. The buyer may escalate a counter-offer or rejection at any time;
+// either side may escalate once the other has let the response deadline pass.
+func canEscalate(refund *entity.Refund, byBuyer bool) bool {
+	if refund.Initiator != entity.RefundInitiatorBuyer {
+		return false
+	}
+
+	deadlinePassed := refund.ResponseDueAt != nil && timeth.Now().After(*refund.ResponseDueAt)
+
+	switch refund.RefundStatusID {
+	case entity.RefundStatusPending:
+		return deadlinePassed
+	case entity.RefundStatusCounterOffered:
+		return byBuyer || deadlinePassed
+	case entity.RefundStatusRejected:
+		return byBuyer
+	}
+	return false
+}
+
+func (u *refundUsecase) escalateRefund(ctx context.Context, userID uuid.UUID, refund *entity.Refund, shopOrder *entity.ShopOrder, byBuyer bool, note string) (*entity.RefundResponse, error) {
+	// Cancelling a paid shop order refunds what is left of it and rejects its open
+	// requests, so there is nothing left to mediate.
+	if shopOrder.OrderStatusID == entity.OrderStatusCancelled || !canEscalate(refund, byBuyer) {
+		return nil, errmap.ErrRefundCannotEscalate
+	}
+
+	party := "Shop"
+	if byBuyer {
+		party = "Buyer"
+	}
+	logNote := fmt.Sprintf("%s escalated refund to mediation: %s", party, note)
+	err := u.changeRefundStatus(ctx, shopOrder, refund, entity.RefundStatusEscalated, entity.EventRefundEscalated, refund.Amount, logNote, &userID, func(ctx context.Context) error {
+		if err := u.refundRepo.EscalateRefund(ctx, refund.ID, refund.RefundStatusID, userID); err != nil {
+			return fmt.Errorf("failed to escalate refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refund.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(updatedRefund, !byBuyer), nil
+}
+
+func (u *refundUsecase) EscalateUserRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.EscalateRefundRequest) (*entity.RefundResponse, error) {
+	refund, shopOrder, err := u.getUserRefund(ctx, userID, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.escalateRefund(ctx, userID, refund, shopOrder, true, req.Note)
+}
+
+func (u *refundUsecase) EscalateShopRefund(ctx context.Context, userID uuid.UUID, refundID uuid.UUID, req entity.EscalateRefundRequest) (*entity.RefundResponse, error) {
+	refund, shopOrder, _, err := u.getShopRefund(ctx, userID, refundID, entity.ShopPermissionIssueRefunds)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.escalateRefund(ctx, userID, refund, shopOrder, false, req.Note)
+}
+
+func (u *refundUsecase) ListAdminRefunds(ctx context.Context, req entity.RefundListRequest) (*entity.RefundListPaginationResponse, error) {
+	refunds, total, err := u.refundRepo.ListRefunds(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundListResponse(refunds, total, true), nil
+}
+
+func (u *refundUsecase) ResolveRefund(ctx context.Context, adminID uuid.UUID, refundID uuid.UUID, req entity.ResolveRefundRequest) (*entity.RefundResponse, error) {
+	refund, err := u.getRefund(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if refund.RefundStatusID != entity.RefundStatusEscalated {
+		return nil, errmap.ErrRefundNotEscalated
+	}
+
+	shopOrder, err := u.orderRepo.GetShopOrderByID(ctx, refund.ShopOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusID := entity.RefundStatusRejected
+	eventType := ""
+	amount := refund.Amount
+	note := fmt.Sprintf("Mediation rejected refund: %s", req.Note)
+	if req.Approve {
+		statusID = entity.RefundStatusApproved
+		eventType = entity.EventRefundApproved
+		remaining, err := u.refundableAmount(ctx, shopOrder)
+		if err != nil {
+			return nil, err
+		}
+		if remaining <= 0 {
+			return nil, errmap.ErrRefundNotEligible
+		}
+		if req.Amount != nil {
+			if *req.Amount > remaining {
+				return nil, errmap.ErrRefundAmountExceedsTotal
+			}
+			amount = *req.Amount
+		}
+		amount = min(amount, remaining)
+		note = fmt.Sprintf("Mediation approved refund of %.2f: %s", amount, req.Note)
+	}
+
+	err = u.changeRefundStatus(ctx, shopOrder, refund, statusID, eventType, amount, note, &adminID, func(ctx context.Context) error {
+		if err := u.refundRepo.ResolveRefund(ctx, refundID, statusID, amount, req.Note); err != nil {
+			return fmt.Errorf("failed to resolve refund: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedRefund, err := u.refundRepo.GetRefundByID(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapToRefundResponse(updatedRefund, true), nil
 }
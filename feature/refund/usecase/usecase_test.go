@@ -0,0 +1,627 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type testRepos struct {
+	refund *mock.MockRefundRepository
+	order  *mock.MockOrderRepository
+	shop   *mock.MockShopRepository
+	outbox *mock.MockOutboxRepository
+}
+
+func newTestUsecase(t *testing.T) (*refundUsecase, testRepos) {
+	ctrl := gomock.NewController(t)
+	repos := testRepos{
+		refund: mock.NewMockRefundRepository(ctrl),
+		order:  mock.NewMockOrderRepository(ctrl),
+		shop:   mock.NewMockShopRepository(ctrl),
+		outbox: mock.NewMockOutboxRepository(ctrl),
+	}
+	tx := mock.NewMockTransactor(ctrl)
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	uc := NewRefundUsecase(repos.refund, repos.order, repos.shop, repos.outbox, tx).(*refundUsecase)
+	return uc, repos
+}
+
+type refundFixture struct {
+	shopUserID uuid.UUID
+	buyerID    uuid.UUID
+	shopOrder  *entity.ShopOrder
+	refund     *entity.Refund
+}
+
+// newRefundFixture is a buyer-initiated refund of 500 on a shop order of 1000, in status.
+func newRefundFixture(status uint32) *refundFixture {
+	shopID := uuid.New()
+	shopUserID := uuid.New()
+	buyerID := uuid.New()
+	shopOrder := &entity.ShopOrder{
+		ID:            uuid.New(),
+		OrderID:       uuid.New(),
+		ShopID:        shopID,
+		OrderStatusID: entity.OrderStatusDelivered,
+		GrandTotal:    1000,
+		Order:         entity.Order{UserID: buyerID},
+	}
+	responseDueAt := timeth.Now().Add(time.Hour)
+	return &refundFixture{
+		shopUserID: shopUserID,
+		buyerID:    buyerID,
+		shopOrder:  shopOrder,
+		refund: &entity.Refund{
+			ID:             uuid.New(),
+			ShopOrderID:    shopOrder.ID,
+			Amount:         500,
+			RefundStatusID: status,
+			Initiator:      entity.RefundInitiatorBuyer,
+			ResponseDueAt:  &responseDueAt,
+		},
+	}
+}
+
+// expectShopAccess expects the refund, its shop order and the membership of userID to be
+// looked up.
+func (f *refundFixture) expectShopAccess(repos testRepos, userID uuid.UUID, role string) {
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+	repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+	repos.shop.EXPECT().GetShopMember(gomock.Any(), f.shopOrder.ShopID, userID).Return(&entity.ShopMember{ShopID: f.shopOrder.ShopID, UserID: userID, Role: role}, nil)
+}
+
+func (f *refundFixture) expectBuyerAccess(repos testRepos) {
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+	repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+}
+
+// expectEvent expects one outbox event of eventType and returns its decoded payload.
+func expectEvent(t *testing.T, repos testRepos, eventType string) *entity.RefundPayload {
+	payload := &entity.RefundPayload{}
+	repos.outbox.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...*entity.OutboxEvent) error {
+		require.Len(t, events, 1)
+		assert.Equal(t, eventType, events[0].EventType)
+		require.NoError(t, outbox.Decode(events[0], payload))
+		return nil
+	})
+	return payload
+}
+
+func TestApproveRefund_PublishesApprovedEvent(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().UpdateRefundStatus(gomock.Any(), f.refund.ID, entity.RefundStatusPending, entity.RefundStatusApproved).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	payload := expectEvent(t, repos, entity.EventRefundApproved)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, RefundStatusID: entity.RefundStatusApproved}, nil)
+
+	resp, err := uc.ApproveRefund(ctx, f.shopUserID, f.refund.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.RefundStatusApproved, resp.RefundStatusID)
+	assert.Equal(t, 500.0, payload.Amount)
+	assert.Equal(t, f.buyerID, payload.UserID)
+}
+
+func TestApproveRefund_RequiresPendingStatus(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusApproved)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().UpdateRefundStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.ApproveRefund(context.Background(), f.shopUserID, f.refund.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrRefundNotPending)
+}
+
+func TestApproveRefund_ConcurrentChangeIsNotPublished(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().UpdateRefundStatus(gomock.Any(), f.refund.ID, entity.RefundStatusPending, entity.RefundStatusApproved).Return(errmap.ErrRefundStatusChanged)
+	repos.outbox.EXPECT().Add(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.ApproveRefund(context.Background(), f.shopUserID, f.refund.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrRefundStatusChanged)
+}
+
+func TestApproveRefund_NeedsIssueRefundsPermission(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+	packerID := uuid.New()
+
+	f.expectShopAccess(repos, packerID, entity.ShopRolePacker)
+	repos.refund.EXPECT().UpdateRefundStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.ApproveRefund(context.Background(), packerID, f.refund.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrForbidden)
+}
+
+func TestRejectRefund_RecordsReasonWithoutEvent(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleSupport)
+	repos.refund.EXPECT().RejectRefund(gomock.Any(), f.refund.ID, "item was used").Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, orderLog *entity.OrderLog) error {
+		assert.Equal(t, entity.RefundStatusRejected, *orderLog.RefundStatusID)
+		assert.Equal(t, "Refund rejected: item was used", orderLog.Note)
+		return nil
+	})
+	repos.outbox.EXPECT().Add(gomock.Any(), gomock.Any()).Times(0)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, RefundStatusID: entity.RefundStatusRejected}, nil)
+
+	resp, err := uc.RejectRefund(context.Background(), f.shopUserID, f.refund.ID, entity.RejectRefundRequest{Reason: "item was used"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.RefundStatusRejected, resp.RefundStatusID)
+}
+
+func TestRejectRefund_RequiresPendingStatus(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusCompleted)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().RejectRefund(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.RejectRefund(context.Background(), f.shopUserID, f.refund.ID, entity.RejectRefundRequest{Reason: "too late"})
+
+	assert.ErrorIs(t, err, errmap.ErrRefundNotPending)
+}
+
+func TestCompleteRefund_PublishesCompletedEventAndUpdatesPayment(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusApproved)
+	method := entity.RefundMethodBankTransfer
+	f.refund.RefundMethodID = &method
+	f.refund.BankAccount = "1234567890"
+
+	paymentID := uuid.New()
+	f.refund.PaymentID = &paymentID
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().CompleteRefund(gomock.Any(), f.refund.ID, "TX-1").Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	repos.order.EXPECT().UpdatePaymentRefundStatus(gomock.Any(), paymentID).Return(nil)
+	expectEvent(t, repos, entity.EventRefundCompleted)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, RefundStatusID: entity.RefundStatusCompleted}, nil)
+
+	resp, err := uc.CompleteRefund(context.Background(), f.shopUserID, f.refund.ID, entity.CompleteRefundRequest{TransactionID: "TX-1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.RefundStatusCompleted, resp.RefundStatusID)
+}
+
+func TestCompleteRefund_InvalidStates(t *testing.T) {
+	method := entity.RefundMethodBankTransfer
+	tests := map[string]struct {
+		status      uint32
+		bankAccount string
+		want        error
+	}{
+		"not approved":         {status: entity.RefundStatusPending, bankAccount: "1234567890", want: errmap.ErrRefundNotApproved},
+		"already completed":    {status: entity.RefundStatusCompleted, bankAccount: "1234567890", want: errmap.ErrRefundNotApproved},
+		"missing bank account": {status: entity.RefundStatusApproved, want: errmap.ErrRefundBankAccountRequired},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			uc, repos := newTestUsecase(t)
+			f := newRefundFixture(tt.status)
+			f.refund.RefundMethodID = &method
+			f.refund.BankAccount = tt.bankAccount
+
+			f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+			repos.refund.EXPECT().CompleteRefund(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := uc.CompleteRefund(context.Background(), f.shopUserID, f.refund.ID, entity.CompleteRefundRequest{TransactionID: "TX-1"})
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestCounterOfferRefund_OffersLowerAmount(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleManager)
+	repos.refund.EXPECT().CounterOfferRefund(gomock.Any(), f.refund.ID, 300.0, "half the items", gomock.Any()).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	payload := expectEvent(t, repos, entity.EventRefundCounterOffered)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, RefundStatusID: entity.RefundStatusCounterOffered}, nil)
+
+	resp, err := uc.CounterOfferRefund(context.Background(), f.shopUserID, f.refund.ID, entity.CounterOfferRefundRequest{Amount: 300, Note: "half the items"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.RefundStatusCounterOffered, resp.RefundStatusID)
+	assert.Equal(t, 300.0, payload.Amount)
+}
+
+func TestCounterOfferRefund_FailsWithTheLog(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().CounterOfferRefund(gomock.Any(), f.refund.ID, 300.0, gomock.Any(), gomock.Any()).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+	repos.outbox.EXPECT().Add(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.CounterOfferRefund(context.Background(), f.shopUserID, f.refund.ID, entity.CounterOfferRefundRequest{Amount: 300, Note: "offer"})
+
+	assert.ErrorContains(t, err, "connection reset")
+}
+
+func TestCounterOfferRefund_InvalidStates(t *testing.T) {
+	tests := map[string]struct {
+		status    uint32
+		initiator string
+		amount    float64
+		want      error
+	}{
+		"not pending":             {status: entity.RefundStatusCounterOffered, initiator: entity.RefundInitiatorBuyer, amount: 300, want: errmap.ErrRefundNotPending},
+		"shop-initiated":          {status: entity.RefundStatusPending, initiator: entity.RefundInitiatorShop, amount: 300, want: errmap.ErrRefundNotBuyerInitiated},
+		"not a lower amount":      {status: entity.RefundStatusPending, initiator: entity.RefundInitiatorBuyer, amount: 500, want: errmap.ErrCounterOfferNotLower},
+		"changed in the meantime": {status: entity.RefundStatusPending, initiator: entity.RefundInitiatorBuyer, amount: 300, want: errmap.ErrRefundStatusChanged},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			uc, repos := newTestUsecase(t)
+			f := newRefundFixture(tt.status)
+			f.refund.Initiator = tt.initiator
+
+			f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+			if tt.want == errmap.ErrRefundStatusChanged {
+				repos.refund.EXPECT().CounterOfferRefund(gomock.Any(), f.refund.ID, tt.amount, gomock.Any(), gomock.Any()).Return(errmap.ErrRefundStatusChanged)
+			}
+
+			_, err := uc.CounterOfferRefund(context.Background(), f.shopUserID, f.refund.ID, entity.CounterOfferRefundRequest{Amount: tt.amount, Note: "offer"})
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestAddRefundEvidence_KeepsStatusAndPublishesEvent(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusEscalated)
+
+	f.expectBuyerAccess(repos)
+	repos.refund.EXPECT().UpdateRefundStatus(gomock.Any(), f.refund.ID, entity.RefundStatusEscalated, entity.RefundStatusEscalated).Return(nil)
+	repos.refund.EXPECT().AddRefundEvidences(gomock.Any(), gomock.Len(1)).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, orderLog *entity.OrderLog) error {
+		assert.Equal(t, entity.RefundStatusEscalated, *orderLog.RefundStatusID)
+		return nil
+	})
+	expectEvent(t, repos, entity.EventRefundEvidenceAdded)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+
+	_, err := uc.AddRefundEvidence(context.Background(), f.buyerID, f.refund.ID, entity.AddRefundEvidenceRequest{
+		Evidences: []entity.RefundEvidenceRequest{{URL: "https://cdn.example.com/box.jpg", Note: "crushed box"}},
+	})
+
+	require.NoError(t, err)
+}
+
+func TestAddRefundEvidence_RefundSettledInTheMeantime(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectBuyerAccess(repos)
+	repos.refund.EXPECT().UpdateRefundStatus(gomock.Any(), f.refund.ID, entity.RefundStatusPending, entity.RefundStatusPending).Return(errmap.ErrRefundStatusChanged)
+	repos.refund.EXPECT().AddRefundEvidences(gomock.Any(), gomock.Any()).Times(0)
+	repos.outbox.EXPECT().Add(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AddRefundEvidence(context.Background(), f.buyerID, f.refund.ID, entity.AddRefundEvidenceRequest{
+		Evidences: []entity.RefundEvidenceRequest{{URL: "https://cdn.example.com/box.jpg"}},
+	})
+
+	assert.ErrorIs(t, err, errmap.ErrRefundStatusChanged)
+}
+
+func TestAcceptCounterOffer_ApprovesCounterAmount(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusCounterOffered)
+	counterAmount := 300.0
+	f.refund.CounterAmount = &counterAmount
+
+	f.expectBuyerAccess(repos)
+	repos.refund.EXPECT().AcceptCounterOffer(gomock.Any(), f.refund.ID).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	payload := expectEvent(t, repos, entity.EventRefundApproved)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, Amount: counterAmount, RefundStatusID: entity.RefundStatusApproved}, nil)
+
+	resp, err := uc.AcceptCounterOffer(context.Background(), f.buyerID, f.refund.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 300.0, resp.Amount)
+	assert.Equal(t, 300.0, payload.Amount)
+}
+
+func TestAcceptCounterOffer_RequiresCounterOffer(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusPending)
+
+	f.expectBuyerAccess(repos)
+	repos.refund.EXPECT().AcceptCounterOffer(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AcceptCounterOffer(context.Background(), f.buyerID, f.refund.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrRefundNotCounterOffered)
+}
+
+func TestAcceptCounterOffer_OnlyByTheBuyer(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusCounterOffered)
+
+	f.expectBuyerAccess(repos)
+	repos.refund.EXPECT().AcceptCounterOffer(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AcceptCounterOffer(context.Background(), uuid.New(), f.refund.ID)
+
+	assert.ErrorIs(t, err, errmap.ErrForbidden)
+}
+
+func TestEscalateUserRefund_RejectedRefund(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusRejected)
+
+	f.expectBuyerAccess(repos)
+	repos.refund.EXPECT().EscalateRefund(gomock.Any(), f.refund.ID, entity.RefundStatusRejected, f.buyerID).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	expectEvent(t, repos, entity.EventRefundEscalated)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, RefundStatusID: entity.RefundStatusEscalated}, nil)
+
+	resp, err := uc.EscalateUserRefund(context.Background(), f.buyerID, f.refund.ID, entity.EscalateRefundRequest{Note: "the parcel was empty"})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.RefundStatusEscalated, resp.RefundStatusID)
+}
+
+func TestEscalateShopRefund_OnlyAfterBuyerMissesDeadline(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusCounterOffered)
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().EscalateRefund(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.EscalateShopRefund(context.Background(), f.shopUserID, f.refund.ID, entity.EscalateRefundRequest{Note: "no answer"})
+	assert.ErrorIs(t, err, errmap.ErrRefundCannotEscalate)
+
+	uc, repos = newTestUsecase(t)
+	overdue := timeth.Now().Add(-time.Minute)
+	f.refund.ResponseDueAt = &overdue
+
+	f.expectShopAccess(repos, f.shopUserID, entity.ShopRoleOwner)
+	repos.refund.EXPECT().EscalateRefund(gomock.Any(), f.refund.ID, entity.RefundStatusCounterOffered, f.shopUserID).Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	expectEvent(t, repos, entity.EventRefundEscalated)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, RefundStatusID: entity.RefundStatusEscalated}, nil)
+
+	_, err = uc.EscalateShopRefund(context.Background(), f.shopUserID, f.refund.ID, entity.EscalateRefundRequest{Note: "no answer"})
+	assert.NoError(t, err)
+}
+
+func TestEscalateUserRefund_InvalidStates(t *testing.T) {
+	tests := map[string]func(f *refundFixture){
+		"approved":                func(f *refundFixture) { f.refund.RefundStatusID = entity.RefundStatusApproved },
+		"already escalated":       func(f *refundFixture) { f.refund.RefundStatusID = entity.RefundStatusEscalated },
+		"pending within deadline": func(f *refundFixture) {},
+		"superseded by cancellation": func(f *refundFixture) {
+			f.refund.RefundStatusID = entity.RefundStatusRejected
+			f.shopOrder.OrderStatusID = entity.OrderStatusCancelled
+		},
+		"shop-initiated": func(f *refundFixture) {
+			f.refund.RefundStatusID = entity.RefundStatusRejected
+			f.refund.Initiator = entity.RefundInitiatorShop
+		},
+	}
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			uc, repos := newTestUsecase(t)
+			f := newRefundFixture(entity.RefundStatusPending)
+			setup(f)
+
+			f.expectBuyerAccess(repos)
+			repos.refund.EXPECT().EscalateRefund(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := uc.EscalateUserRefund(context.Background(), f.buyerID, f.refund.ID, entity.EscalateRefundRequest{Note: "please help"})
+
+			assert.ErrorIs(t, err, errmap.ErrRefundCannotEscalate)
+		})
+	}
+}
+
+func TestResolveRefund_InvalidStates(t *testing.T) {
+	adminID := uuid.New()
+	tooMuch := 1500.0
+
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusRejected)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+	repos.refund.EXPECT().ResolveRefund(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.ResolveRefund(context.Background(), adminID, f.refund.ID, entity.ResolveRefundRequest{Approve: true, Note: "ok"})
+	assert.ErrorIs(t, err, errmap.ErrRefundNotEscalated)
+
+	f.refund.RefundStatusID = entity.RefundStatusEscalated
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+	repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+	repos.refund.EXPECT().GetRefundedAmount(gomock.Any(), f.shopOrder.ID).Return(0.0, nil)
+
+	_, err = uc.ResolveRefund(context.Background(), adminID, f.refund.ID, entity.ResolveRefundRequest{Approve: true, Amount: &tooMuch, Note: "ok"})
+	assert.ErrorIs(t, err, errmap.ErrRefundAmountExceedsTotal)
+
+	// 700 of the 1000 were refunded while the dispute was open.
+	leftOver := 400.0
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+	repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+	repos.refund.EXPECT().GetRefundedAmount(gomock.Any(), f.shopOrder.ID).Return(700.0, nil)
+
+	_, err = uc.ResolveRefund(context.Background(), adminID, f.refund.ID, entity.ResolveRefundRequest{Approve: true, Amount: &leftOver, Note: "ok"})
+	assert.ErrorIs(t, err, errmap.ErrRefundAmountExceedsTotal)
+}
+
+func TestResolveRefund_ApprovesMediatedAmount(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	adminID := uuid.New()
+	f := newRefundFixture(entity.RefundStatusEscalated)
+	amount := 400.0
+
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(f.refund, nil)
+	repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+	repos.refund.EXPECT().GetRefundedAmount(gomock.Any(), f.shopOrder.ID).Return(0.0, nil)
+	repos.refund.EXPECT().ResolveRefund(gomock.Any(), f.refund.ID, entity.RefundStatusApproved, amount, "split the difference").Return(nil)
+	repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	payload := expectEvent(t, repos, entity.EventRefundApproved)
+	repos.refund.EXPECT().GetRefundByID(gomock.Any(), f.refund.ID).Return(&entity.Refund{ID: f.refund.ID, Amount: amount, RefundStatusID: entity.RefundStatusApproved}, nil)
+
+	_, err := uc.ResolveRefund(context.Background(), adminID, f.refund.ID, entity.ResolveRefundRequest{Approve: true, Amount: &amount, Note: "split the difference"})
+
+	require.NoError(t, err)
+	assert.Equal(t, amount, payload.Amount)
+}
+
+func TestRequestRefund_PaymentEligibility(t *testing.T) {
+	tests := map[string]struct {
+		paymentStatusID uint32
+		want            error
+	}{
+		"paid":               {paymentStatusID: entity.PaymentStatusCompleted},
+		"partially refunded": {paymentStatusID: entity.PaymentStatusPartiallyRefunded},
+		"fully refunded":     {paymentStatusID: entity.PaymentStatusRefunded, want: errmap.ErrRefundNotEligible},
+		"not yet paid":       {paymentStatusID: entity.PaymentStatusPending, want: errmap.ErrRefundNotEligible},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			uc, repos := newTestUsecase(t)
+			f := newRefundFixture(entity.RefundStatusPending)
+
+			repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+			repos.order.EXPECT().GetPaymentByOrderID(gomock.Any(), f.shopOrder.OrderID).Return(&entity.Payment{ID: uuid.New(), OrderID: f.shopOrder.OrderID, PaymentMethodID: entity.PaymentMethodPromptPay, PaymentStatusID: tt.paymentStatusID}, nil)
+			if tt.want == nil {
+				repos.refund.EXPECT().HasOpenRefund(gomock.Any(), f.shopOrder.ID).Return(false, nil)
+				repos.refund.EXPECT().GetRefundedAmount(gomock.Any(), f.shopOrder.ID).Return(0.0, nil)
+				repos.refund.EXPECT().CreateRefund(gomock.Any(), gomock.Any()).Return(nil)
+				repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+				expectEvent(t, repos, entity.EventRefundRequested)
+			}
+
+			_, err := uc.RequestRefund(context.Background(), f.buyerID, entity.RequestRefundRequest{ShopOrderID: f.shopOrder.ID, ReasonCode: "DAMAGED", Reason: "broken"})
+
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestRefund_AfterCompletedRefundOnlyWhatIsLeft(t *testing.T) {
+	partial := 500.0
+	tests := map[string]struct {
+		refunded float64
+		amount   *float64
+		want     error
+		wantPaid float64
+	}{
+		"defaults to the rest":       {refunded: 600, wantPaid: 400},
+		"more than the rest":         {refunded: 600, amount: &partial, want: errmap.ErrRefundAmountExceedsTotal},
+		"within the rest":            {refunded: 400, amount: &partial, wantPaid: 500},
+		"nothing left to refund":     {refunded: 1000, want: errmap.ErrRefundNotEligible},
+		"full total asked for again": {refunded: 1000, amount: &partial, want: errmap.ErrRefundNotEligible},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			uc, repos := newTestUsecase(t)
+			f := newRefundFixture(entity.RefundStatusCompleted)
+
+			repos.order.EXPECT().GetShopOrderByID(gomock.Any(), f.shopOrder.ID).Return(f.shopOrder, nil)
+			repos.order.EXPECT().GetPaymentByOrderID(gomock.Any(), f.shopOrder.OrderID).Return(&entity.Payment{ID: uuid.New(), OrderID: f.shopOrder.OrderID, PaymentMethodID: entity.PaymentMethodPromptPay, PaymentStatusID: entity.PaymentStatusPartiallyRefunded}, nil)
+			repos.refund.EXPECT().HasOpenRefund(gomock.Any(), f.shopOrder.ID).Return(false, nil)
+			repos.refund.EXPECT().GetRefundedAmount(gomock.Any(), f.shopOrder.ID).Return(tt.refunded, nil)
+			if tt.want == nil {
+				repos.refund.EXPECT().CreateRefund(gomock.Any(), gomock.Any()).Return(nil)
+				repos.order.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+				expectEvent(t, repos, entity.EventRefundRequested)
+			} else {
+				repos.refund.EXPECT().CreateRefund(gomock.Any(), gomock.Any()).Times(0)
+			}
+
+			resp, err := uc.RequestRefund(context.Background(), f.buyerID, entity.RequestRefundRequest{ShopOrderID: f.shopOrder.ID, ReasonCode: "DAMAGED", Reason: "broken again", Amount: tt.amount})
+
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPaid, resp.Amount)
+		})
+	}
+}
+
+func TestGetShopRefund_BankAccountIsMaskedWithoutFinanceAccess(t *testing.T) {
+	tests := []struct {
+		role string
+		want string
+	}{
+		{entity.ShopRoleOwner, "1234567890"},
+		{entity.ShopRoleManager, "1234567890"},
+		{entity.ShopRoleSupport, "1234567890"},
+		{entity.ShopRolePacker, "******7890"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			uc, repos := newTestUsecase(t)
+			f := newRefundFixture(entity.RefundStatusApproved)
+			f.refund.BankAccount = "1234567890"
+
+			f.expectShopAccess(repos, f.shopUserID, tt.role)
+
+			resp, err := uc.GetShopRefund(context.Background(), f.shopUserID, f.refund.ID)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, resp.BankAccount)
+		})
+	}
+}
+
+func TestListShopRefunds_BankAccountIsMaskedWithoutFinanceAccess(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	f := newRefundFixture(entity.RefundStatusApproved)
+	f.refund.BankAccount = "1234567890"
+	req := entity.RefundListRequest{}
+
+	repos.shop.EXPECT().GetShopMemberByUserID(gomock.Any(), f.shopUserID).
+		Return(&entity.ShopMember{ShopID: f.shopOrder.ShopID, UserID: f.shopUserID, Role: entity.ShopRolePacker}, nil)
+	repos.refund.EXPECT().ListRefundsByShopID(gomock.Any(), f.shopOrder.ShopID, req).Return([]*entity.Refund{f.refund}, int64(1), nil)
+
+	resp, err := uc.ListShopRefunds(context.Background(), f.shopUserID, req)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "******7890", resp.Items[0].BankAccount)
+}
@@ -172,6 +172,7 @@ func (h *ShopHandler) UpdateMyShop(c echo.Context) error {
 //	@Success		200		{object}	entity.ShopCourierResponse
 //	@Failure		400		{object}	response.ResponseError
 //	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
 //	@Failure		500		{object}	response.ResponseError
 //	@Router			/api/shop/couriers [put]
 func (h *ShopHandler) UpdateShopCouriers(c echo.Context) error {
@@ -191,6 +192,9 @@ func (h *ShopHandler) UpdateShopCouriers(c echo.Context) error {
 
 	resp, err := h.shopUsecase.UpdateShopCouriers(c.Request().Context(), userID, &req)
 	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
 		return response.Error(c, http.StatusInternalServerError, err.Error())
 	}
 
@@ -223,6 +227,52 @@ func (h *ShopHandler) GetShopCouriers(c echo.Context) error {
 	return response.Success(c, http.StatusOK, "ok", resp)
 }
 
+// GetFinanceSummary godoc
+//
+//	@Summary		Get shop finance summary
+//	@Description	Sum the shop's paid orders and the refunds paid out on them, for orders placed between two dates in Thai time. Defaults to the last 30 days; ranges can span up to 366 days. Needs the VIEW_FINANCE permission.
+//	@Tags			Shops
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			from	query		string	false	"First day, YYYY-MM-DD"
+//	@Param			to		query		string	false	"Last day, YYYY-MM-DD"
+//	@Success		200		{object}	entity.ShopFinanceSummary
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop/finance [get]
+func (h *ShopHandler) GetFinanceSummary(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.ShopFinanceRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	summary, err := h.shopUsecase.GetFinanceSummary(c.Request().Context(), userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidFinanceRange):
+			return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidFinanceRange.Error())
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		default:
+			c.Logger().Error("GetFinanceSummary error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "ok", summary)
+}
+
 func RegisterShopHandler(group *echo.Group, db *gorm.DB) {
 	shopRepository := shopRepo.NewShopRepository(db)
 	productRepository := productRepo.NewProductRepository(db)
@@ -16,4 +16,5 @@ func (h *ShopHandler) RegisterRoutes(group *echo.Group) {
 	shopGroup.PUT("/shop", h.UpdateMyShop)
 	shopGroup.GET("/shop/couriers", h.GetShopCouriers)
 	shopGroup.PUT("/shop/couriers", h.UpdateShopCouriers)
+	shopGroup.GET("/shop/finance", h.GetFinanceSummary)
 }
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
@@ -28,12 +30,46 @@ func (r *shopRepository) GetShopByID(ctx context.Context, id uuid.UUID) (*entity
 
 func (r *shopRepository) GetShopByUserID(ctx context.Context, userID uuid.UUID) (*entity.Shop, error) {
 	var s entity.Shop
-	if err := r.db.WithContext(ctx).Where("user_id = ? AND deleted_at IS NULL", userID).First(&s).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Select("shops.*").
+		Joins("JOIN shop_members ON shop_members.shop_id = shops.id").
+		Where("shop_members.user_id = ? AND shops.deleted_at IS NULL", userID).
+		First(&s).Error; err != nil {
 		return nil, err
 	}
 	return &s, nil
 }
 
+func (r *shopRepository) GetShopMemberByUserID(ctx context.Context, userID uuid.UUID) (*entity.ShopMember, error) {
+	var m entity.ShopMember
+	if err := r.db.WithContext(ctx).
+		Select("shop_members.*").
+		Preload("Shop").
+		Joins("JOIN shops ON shops.id = shop_members.shop_id").
+		Where("shop_members.user_id = ? AND shops.deleted_at IS NULL", userID).
+		First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *shopRepository) GetShopMember(ctx context.Context, shopID uuid.UUID, userID uuid.UUID) (*entity.ShopMember, error) {
+	var m entity.ShopMember
+	err := r.db.WithContext(ctx).
+		Select("shop_members.*").
+		Preload("Shop").
+		Joins("JOIN shops ON shops.id = shop_members.shop_id").
+		Where("shop_members.shop_id = ? AND shop_members.user_id = ? AND shops.deleted_at IS NULL", shopID, userID).
+		First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 func (r *shopRepository) GetShopsByIDs(ctx context.Context, ids []uuid.UUID) ([]*entity.Shop, error) {
 	var shops []*entity.Shop
 	if len(ids) == 0 {
@@ -125,3 +161,30 @@ func (r *shopRepository) SoftDeleteShopCouriers(ctx context.Context, shopID uuid
 func (r *shopRepository) CreateShopCourier(ctx context.Context, courier *entity.ShopCourier) error {
 	return r.db.WithContext(ctx).Create(courier).Error
 }
+
+func (r *shopRepository) GetFinanceSummary(ctx context.Context, shopID uuid.UUID, from, to time.Time) (*entity.ShopFinanceSummary, error) {
+	var summary entity.ShopFinanceSummary
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS paid_orders,
+			COALESCE(SUM(so.grand_total), 0) AS gross_sales,
+			COALESCE(SUM(so.grand_total) FILTER (WHERE so.order_status_id = ?), 0) AS completed_sales
+		FROM shop_orders so
+		JOIN payments p ON p.order_id = so.order_id AND p.paid_at IS NOT NULL
+		WHERE so.shop_id = ? AND so.created_at >= ? AND so.created_at < ?`,
+		entity.OrderStatusCompleted, shopID, from, to,
+	).Scan(&summary).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(rf.amount), 0)
+		FROM refunds rf
+		JOIN shop_orders so ON so.id = rf.shop_order_id
+		WHERE so.shop_id = ? AND rf.refund_status_id = ? AND so.created_at >= ? AND so.created_at < ?`,
+		shopID, entity.RefundStatusCompleted, from, to,
+	).Scan(&summary.Refunded).Error; err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
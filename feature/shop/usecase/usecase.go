@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
 	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
 )
 
 type shopUsecase struct {
@@ -28,13 +30,16 @@ func (u *shopUsecase) GetShopByID(ctx context.Context, shopID uuid.UUID) (*entit
 	}
 
 	return &entity.ShopResponse{
-		ID:          shop.ID,
-		UserID:      shop.UserID,
-		Name:        shop.Name,
-		Description: shop.Description,
-		ImageURL:    shop.ImageURL,
-		Address:     shop.Address,
-		IsActive:    shop.IsActive,
+		ID:               shop.ID,
+		UserID:           shop.UserID,
+		Name:             shop.Name,
+		Description:      shop.Description,
+		ImageURL:         shop.ImageURL,
+		Address:          shop.Address,
+		ProvinceID:       shop.ProvinceID,
+		HandlingDays:     shop.HandlingDays,
+		AutoCompleteDays: shop.AutoCompleteDays,
+		IsActive:         shop.IsActive,
 	}, nil
 }
 
@@ -45,13 +50,16 @@ func (u *shopUsecase) GetShopByUserID(ctx context.Context, userID uuid.UUID) (*e
 	}
 
 	return &entity.ShopResponse{
-		ID:          shop.ID,
-		UserID:      shop.UserID,
-		Name:        shop.Name,
-		Description: shop.Description,
-		ImageURL:    shop.ImageURL,
-		Address:     shop.Address,
-		IsActive:    shop.IsActive,
+		ID:               shop.ID,
+		UserID:           shop.UserID,
+		Name:             shop.Name,
+		Description:      shop.Description,
+		ImageURL:         shop.ImageURL,
+		Address:          shop.Address,
+		ProvinceID:       shop.ProvinceID,
+		HandlingDays:     shop.HandlingDays,
+		AutoCompleteDays: shop.AutoCompleteDays,
+		IsActive:         shop.IsActive,
 	}, nil
 }
 
@@ -77,7 +85,11 @@ func (u *shopUsecase) UpdateShop(ctx context.Context, shopID uuid.UUID, userID u
 
 		return nil, fmt.Errorf("failed to get shop: %w", err)
 	}
-	if shop.UserID != userID {
+	member, err := u.shopRepo.GetShopMember(ctx, shop.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check shop member: %w", err)
+	}
+	if !member.Can(entity.ShopPermissionManageShop) {
 		return nil, errmap.ErrForbidden
 	}
 	if req.Name != nil {
@@ -92,19 +104,35 @@ func (u *shopUsecase) UpdateShop(ctx context.Context, shopID uuid.UUID, userID u
 	if req.Address != nil {
 		shop.Address = *req.Address
 	}
+	if req.ProvinceID != nil {
+		shop.ProvinceID = req.ProvinceID
+	}
+	if req.HandlingDays != nil {
+		shop.HandlingDays = *req.HandlingDays
+	}
+	if req.AutoCompleteDays != nil {
+		if *req.AutoCompleteDays == 0 {
+			shop.AutoCompleteDays = nil
+		} else {
+			shop.AutoCompleteDays = req.AutoCompleteDays
+		}
+	}
 
 	if err := u.shopRepo.UpdateShop(ctx, shop); err != nil {
 		return nil, fmt.Errorf("failed to update shop: %w", err)
 	}
 
 	return &entity.ShopResponse{
-		ID:          shop.ID,
-		UserID:      shop.UserID,
-		Name:        shop.Name,
-		Description: shop.Description,
-		ImageURL:    shop.ImageURL,
-		Address:     shop.Address,
-		IsActive:    shop.IsActive,
+		ID:               shop.ID,
+		UserID:           shop.UserID,
+		Name:             shop.Name,
+		Description:      shop.Description,
+		ImageURL:         shop.ImageURL,
+		Address:          shop.Address,
+		ProvinceID:       shop.ProvinceID,
+		HandlingDays:     shop.HandlingDays,
+		AutoCompleteDays: shop.AutoCompleteDays,
+		IsActive:         shop.IsActive,
 	}, nil
 }
 
@@ -117,13 +145,16 @@ func (u *shopUsecase) ListShops(ctx context.Context, req *entity.ShopListRequest
 	items := make([]*entity.ShopResponse, 0, len(shops))
 	for _, shop := range shops {
 		items = append(items, &entity.ShopResponse{
-			ID:          shop.ID,
-			UserID:      shop.UserID,
-			Name:        shop.Name,
-			Description: shop.Description,
-			ImageURL:    shop.ImageURL,
-			Address:     shop.Address,
-			IsActive:    shop.IsActive,
+			ID:               shop.ID,
+			UserID:           shop.UserID,
+			Name:             shop.Name,
+			Description:      shop.Description,
+			ImageURL:         shop.ImageURL,
+			Address:          shop.Address,
+			ProvinceID:       shop.ProvinceID,
+			HandlingDays:     shop.HandlingDays,
+			AutoCompleteDays: shop.AutoCompleteDays,
+			IsActive:         shop.IsActive,
 		})
 	}
 
@@ -139,10 +170,14 @@ func (u *shopUsecase) DeactivateShop(ctx context.Context, shopID uuid.UUID) erro
 }
 
 func (u *shopUsecase) UpdateShopCouriers(ctx context.Context, userID uuid.UUID, req *entity.UpdateShopCouriersRequest) (*entity.ShopCourierResponse, error) {
-	shop, err := u.shopRepo.GetShopByUserID(ctx, userID)
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get shop: %w", err)
 	}
+	if !member.Can(entity.ShopPermissionManageShop) {
+		return nil, errmap.ErrForbidden
+	}
+	shop := &member.Shop
 
 	if err := u.shopRepo.SoftDeleteShopCouriers(ctx, shop.ID); err != nil {
 		return nil, fmt.Errorf("failed to delete existing couriers: %w", err)
@@ -186,3 +221,45 @@ func (u *shopUsecase) GetShopCouriers(ctx context.Context, userID uuid.UUID) (*e
 		UpdatedAt: courier.UpdatedAt,
 	}, nil
 }
+
+// financeMaxDays caps the range of a finance summary.
+const financeMaxDays = 366
+
+// GetFinanceSummary sums the shop's sales over whole days in Thai time, by when the
+// orders were placed.
+func (u *shopUsecase) GetFinanceSummary(ctx context.Context, userID uuid.UUID, req *entity.ShopFinanceRequest) (*entity.ShopFinanceSummary, error) {
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop: %w", err)
+	}
+	if !member.Can(entity.ShopPermissionViewFinance) {
+		return nil, errmap.ErrForbidden
+	}
+
+	loc := timeth.LoadLocation()
+	now := timeth.Now().In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if req.To != "" {
+		if to, err = time.ParseInLocation(time.DateOnly, req.To, loc); err != nil {
+			return nil, errmap.ErrInvalidFinanceRange
+		}
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.From != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, req.From, loc); err != nil {
+			return nil, errmap.ErrInvalidFinanceRange
+		}
+	}
+	if to.Before(from) || to.Sub(from) >= financeMaxDays*24*time.Hour {
+		return nil, errmap.ErrInvalidFinanceRange
+	}
+
+	summary, err := u.shopRepo.GetFinanceSummary(ctx, member.ShopID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum shop finances: %w", err)
+	}
+	summary.From = from.Format(time.DateOnly)
+	summary.To = to.Format(time.DateOnly)
+	summary.NetSales = summary.GrossSales - summary.Refunded
+	return summary, nil
+}
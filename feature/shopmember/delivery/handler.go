@@ -0,0 +1,350 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/response"
+	"ecommerce-go-api/middleware"
+)
+
+type ShopMemberHandler struct {
+	usecase domain.ShopMemberUsecase
+}
+
+func NewShopMemberHandler(usecase domain.ShopMemberUsecase) *ShopMemberHandler {
+	return &ShopMemberHandler{usecase: usecase}
+}
+
+// GetMyMembership godoc
+//
+//	@Summary		Get my shop membership
+//	@Description	Get the caller's role and permissions in their shop
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{object}	entity.ShopMemberResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		404	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/shop/members/me [get]
+func (h *ShopMemberHandler) GetMyMembership(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	member, err := h.usecase.GetMyMembership(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrShopMemberNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrShopMemberNotFound.Error())
+		}
+		c.Logger().Error("GetMyMembership error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", member)
+}
+
+// ListMembers godoc
+//
+//	@Summary		List shop members
+//	@Description	Get the owner and staff of the caller's shop with their roles and permissions
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.ShopMemberResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/shop/members [get]
+func (h *ShopMemberHandler) ListMembers(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	members, err := h.usecase.ListMembers(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		c.Logger().Error("ListMembers error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", members)
+}
+
+// UpdateMember godoc
+//
+//	@Summary		Change a shop member's role
+//	@Description	Give a staff member another role. Only the owner can, and the owner's own role cannot change.
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			userId	path		string							true	"Member's user ID"
+//	@Param			body	body		entity.UpdateShopMemberRequest	true	"New role"
+//	@Success		200		{object}	entity.ShopMemberResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop/members/{userId} [put]
+func (h *ShopMemberHandler) UpdateMember(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	memberUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidUserID.Error())
+	}
+
+	var req entity.UpdateShopMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	member, err := h.usecase.UpdateMember(c.Request().Context(), userID, memberUserID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		case errors.Is(err, errmap.ErrShopMemberNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrShopMemberNotFound.Error())
+		case errors.Is(err, errmap.ErrShopOwnerImmutable):
+			return response.Error(c, http.StatusConflict, errmap.ErrShopOwnerImmutable.Error())
+		default:
+			c.Logger().Error("UpdateMember error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "shop member updated", member)
+}
+
+// RemoveMember godoc
+//
+//	@Summary		Remove a shop member
+//	@Description	Take a staff member out of the shop. Only the owner can, and the owner cannot be removed. The account goes back to a buyer account.
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			userId	path		string	true	"Member's user ID"
+//	@Success		200		{object}	response.ResponseSuccess
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		404		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop/members/{userId} [delete]
+func (h *ShopMemberHandler) RemoveMember(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	memberUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidUserID.Error())
+	}
+
+	if err := h.usecase.RemoveMember(c.Request().Context(), userID, memberUserID); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		case errors.Is(err, errmap.ErrShopMemberNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrShopMemberNotFound.Error())
+		case errors.Is(err, errmap.ErrShopOwnerImmutable):
+			return response.Error(c, http.StatusConflict, errmap.ErrShopOwnerImmutable.Error())
+		default:
+			c.Logger().Error("RemoveMember error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "shop member removed", nil)
+}
+
+// InviteMember godoc
+//
+//	@Summary		Invite a shop member
+//	@Description	Email an invitation to join the shop with a staff role. It expires in 7 days; inviting the same email again replaces it. Only the owner can invite.
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.InviteShopMemberRequest	true	"Invitee and role"
+//	@Success		201		{object}	entity.ShopInvitationResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop/invitations [post]
+func (h *ShopMemberHandler) InviteMember(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.InviteShopMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	invitation, err := h.usecase.InviteMember(c.Request().Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		case errors.Is(err, errmap.ErrShopMemberLimitReached):
+			return response.Error(c, http.StatusConflict, errmap.ErrShopMemberLimitReached.Error())
+		default:
+			c.Logger().Error("InviteMember error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusCreated, "invitation sent", invitation)
+}
+
+// ListInvitations godoc
+//
+//	@Summary		List shop invitations
+//	@Description	Get the shop's invitations that are neither accepted nor expired. Only the owner can.
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.ShopInvitationResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/shop/invitations [get]
+func (h *ShopMemberHandler) ListInvitations(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	invitations, err := h.usecase.ListInvitations(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		c.Logger().Error("ListInvitations error: ", err)
+		return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", invitations)
+}
+
+// RevokeInvitation godoc
+//
+//	@Summary		Revoke a shop invitation
+//	@Description	Cancel a pending invitation so its link no longer works. Only the owner can.
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			invitationId	path		string	true	"Invitation ID"
+//	@Success		200				{object}	response.ResponseSuccess
+//	@Failure		400				{object}	response.ResponseError
+//	@Failure		401				{object}	response.ResponseError
+//	@Failure		403				{object}	response.ResponseError
+//	@Failure		404				{object}	response.ResponseError
+//	@Failure		500				{object}	response.ResponseError
+//	@Router			/api/shop/invitations/{invitationId} [delete]
+func (h *ShopMemberHandler) RevokeInvitation(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := h.usecase.RevokeInvitation(c.Request().Context(), userID, invitationID); err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		case errors.Is(err, errmap.ErrShopInvitationNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrShopInvitationNotFound.Error())
+		default:
+			c.Logger().Error("RevokeInvitation error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "invitation revoked", nil)
+}
+
+// AcceptInvitation godoc
+//
+//	@Summary		Accept a shop invitation
+//	@Description	Join the shop with the token from the invitation email. The caller must be signed in with the invited email and not be in a shop already. The account becomes a shop account; refresh the access token to use the shop endpoints.
+//	@Tags			Shop Members
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		entity.AcceptShopInvitationRequest	true	"Invitation token"
+//	@Success		200		{object}	entity.ShopMemberResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop-invitations/accept [post]
+func (h *ShopMemberHandler) AcceptInvitation(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.AcceptShopInvitationRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	member, err := h.usecase.AcceptInvitation(c.Request().Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrShopInvitationInvalid):
+			return response.Error(c, http.StatusBadRequest, errmap.ErrShopInvitationInvalid.Error())
+		case errors.Is(err, errmap.ErrShopInvitationEmailMismatch):
+			return response.Error(c, http.StatusForbidden, errmap.ErrShopInvitationEmailMismatch.Error())
+		case errors.Is(err, errmap.ErrAlreadyShopMember):
+			return response.Error(c, http.StatusConflict, errmap.ErrAlreadyShopMember.Error())
+		default:
+			c.Logger().Error("AcceptInvitation error: ", err)
+			return response.Error(c, http.StatusInternalServerError, errmap.ErrInternalServer.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "invitation accepted", member)
+}
@@ -0,0 +1,36 @@
+package delivery
+
+import (
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	notificationRepo "ecommerce-go-api/feature/notification/repository"
+	shopRepo "ecommerce-go-api/feature/shop/repository"
+	"ecommerce-go-api/feature/shopmember/repository"
+	"ecommerce-go-api/feature/shopmember/usecase"
+	userRepo "ecommerce-go-api/feature/user/repository"
+	"ecommerce-go-api/middleware"
+)
+
+func RegisterShopMemberHandler(group *echo.Group, db *gorm.DB) {
+	uc := usecase.NewShopMemberUsecase(
+		repository.NewShopMemberRepository(db),
+		shopRepo.NewShopRepository(db),
+		userRepo.NewUserRepository(db),
+		notificationRepo.NewNotificationRepository(db),
+	)
+	handler := NewShopMemberHandler(uc)
+
+	members := group.Group("/shop/members", middleware.JWTAuth(), middleware.ShopOwnerOnly())
+	members.GET("", handler.ListMembers)
+	members.GET("/me", handler.GetMyMembership)
+	members.PUT("/:userId", handler.UpdateMember, middleware.RequireTwoFactor())
+	members.DELETE("/:userId", handler.RemoveMember, middleware.RequireTwoFactor())
+
+	invitations := group.Group("/shop/invitations", middleware.JWTAuth(), middleware.ShopOwnerOnly())
+	invitations.GET("", handler.ListInvitations)
+	invitations.POST("", handler.InviteMember, middleware.RequireTwoFactor())
+	invitations.DELETE("/:invitationId", handler.RevokeInvitation)
+
+	group.POST("/shop-invitations/accept", handler.AcceptInvitation, middleware.JWTAuth())
+}
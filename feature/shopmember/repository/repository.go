@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type shopMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewShopMemberRepository(db *gorm.DB) domain.ShopMemberRepository {
+	return &shopMemberRepository{db: db}
+}
+
+func (r *shopMemberRepository) ListMembers(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopMember, error) {
+	var members []*entity.ShopMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("shop_id = ?", shopID).
+		Order("role = 'OWNER' DESC, created_at").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *shopMemberRepository) UpdateMemberRole(ctx context.Context, id uuid.UUID, role string) error {
+	return r.db.WithContext(ctx).Model(&entity.ShopMember{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"role": role, "updated_at": timeth.Now()}).Error
+}
+
+func (r *shopMemberRepository) RemoveMember(ctx context.Context, member *entity.ShopMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.ShopMember{}, "id = ?", member.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.UserRole{}, "user_id = ? AND role_id = ?", member.UserID, entity.RoleShop).Error
+	})
+}
+
+func (r *shopMemberRepository) CreateInvitation(ctx context.Context, invitation *entity.ShopInvitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shop_id = ? AND email = ? AND accepted_at IS NULL", invitation.ShopID, invitation.Email).
+			Delete(&entity.ShopInvitation{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(invitation).Error
+	})
+}
+
+func (r *shopMemberRepository) CountSeats(ctx context.Context, shopID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT (SELECT COUNT(*) FROM shop_members WHERE shop_id = ?)
+			+ (SELECT COUNT(*) FROM shop_invitations WHERE shop_id = ? AND accepted_at IS NULL AND expires_at > ?)`,
+		shopID, shopID, timeth.Now(),
+	).Scan(&count).Error
+	return count, err
+}
+
+func (r *shopMemberRepository) ListPendingInvitations(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopInvitation, error) {
+	var invitations []*entity.ShopInvitation
+	err := r.db.WithContext(ctx).
+		Where("shop_id = ? AND accepted_at IS NULL AND expires_at > ?", shopID, timeth.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *shopMemberRepository) GetInvitationByID(ctx context.Context, id uuid.UUID) (*entity.ShopInvitation, error) {
+	var invitation entity.ShopInvitation
+	if err := r.db.WithContext(ctx).First(&invitation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *shopMemberRepository) GetInvitationByTokenHash(ctx context.Context, tokenHash string) (*entity.ShopInvitation, error) {
+	var invitation entity.ShopInvitation
+	if err := r.db.WithContext(ctx).
+		Preload("Shop").
+		Where("token_hash = ? AND accepted_at IS NULL", tokenHash).
+		First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *shopMemberRepository) DeleteInvitation(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.ShopInvitation{}, "id = ?", id).Error
+}
+
+func (r *shopMemberRepository) AcceptInvitation(ctx context.Context, invitation *entity.ShopInvitation, member *entity.ShopMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&entity.ShopInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", timeth.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errmap.ErrShopInvitationInvalid
+		}
+
+		// A user joins one shop; the unique user_id catches a concurrent accept.
+		res = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(member)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errmap.ErrAlreadyShopMember
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.UserRole{UserID: member.UserID, RoleID: entity.RoleShop}).Error
+	})
+}
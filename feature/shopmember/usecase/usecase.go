@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/config"
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/notify"
+	"ecommerce-go-api/internal/securetoken"
+	"ecommerce-go-api/internal/timeth"
+)
+
+const (
+	shopInvitationTTL = 7 * 24 * time.Hour
+	// maxShopMembers caps a shop's members, counting the owner and pending invitations.
+	maxShopMembers = 50
+)
+
+type shopMemberUsecase struct {
+	memberRepo       domain.ShopMemberRepository
+	shopRepo         domain.ShopRepository
+	userRepo         domain.UserRepository
+	notificationRepo domain.NotificationRepository
+	appURL           string
+}
+
+func NewShopMemberUsecase(memberRepo domain.ShopMemberRepository, shopRepo domain.ShopRepository, userRepo domain.UserRepository, notificationRepo domain.NotificationRepository) domain.ShopMemberUsecase {
+	return &shopMemberUsecase{
+		memberRepo:       memberRepo,
+		shopRepo:         shopRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		appURL:           config.AppURL(),
+	}
+}
+
+func mapToMemberResponse(member *entity.ShopMember, user *entity.User) *entity.ShopMemberResponse {
+	resp := &entity.ShopMemberResponse{
+		UserID:      member.UserID,
+		ShopID:      member.ShopID,
+		Role:        member.Role,
+		Permissions: entity.ShopRolePermissions[member.Role],
+		CreatedAt:   member.CreatedAt,
+	}
+	if user != nil {
+		resp.Email = user.Email
+		resp.FirstName = user.FirstName
+		resp.LastName = user.LastName
+	}
+	return resp
+}
+
+func mapToInvitationResponse(invitation *entity.ShopInvitation) *entity.ShopInvitationResponse {
+	return &entity.ShopInvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
+
+// getMembership returns the membership of userID, or ErrForbidden when the user is not
+// a member of any shop.
+func (u *shopMemberUsecase) getMembership(ctx context.Context, userID uuid.UUID) (*entity.ShopMember, error) {
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrForbidden
+		}
+		return nil, fmt.Errorf("failed to find shop for user: %w", err)
+	}
+	return member, nil
+}
+
+// getManager returns the membership of userID if it can manage the shop's members.
+func (u *shopMemberUsecase) getManager(ctx context.Context, userID uuid.UUID) (*entity.ShopMember, error) {
+	member, err := u.getMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Can(entity.ShopPermissionManageMembers) {
+		return nil, errmap.ErrForbidden
+	}
+	return member, nil
+}
+
+// getStaffMember loads a member of the manager's shop other than its owner.
+func (u *shopMemberUsecase) getStaffMember(ctx context.Context, shopID uuid.UUID, memberUserID uuid.UUID) (*entity.ShopMember, error) {
+	member, err := u.shopRepo.GetShopMember(ctx, shopID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, errmap.ErrShopMemberNotFound
+	}
+	if member.Role == entity.ShopRoleOwner {
+		return nil, errmap.ErrShopOwnerImmutable
+	}
+	return member, nil
+}
+
+func (u *shopMemberUsecase) GetMyMembership(ctx context.Context, userID uuid.UUID) (*entity.ShopMemberResponse, error) {
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrShopMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to find shop for user: %w", err)
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToMemberResponse(member, user), nil
+}
+
+func (u *shopMemberUsecase) ListMembers(ctx context.Context, userID uuid.UUID) ([]*entity.ShopMemberResponse, error) {
+	member, err := u.getMembership(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := u.memberRepo.ListMembers(ctx, member.ShopID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*entity.ShopMemberResponse, 0, len(members))
+	for _, m := range members {
+		items = append(items, mapToMemberResponse(m, m.User))
+	}
+	return items, nil
+}
+
+func (u *shopMemberUsecase) UpdateMember(ctx context.Context, userID uuid.UUID, memberUserID uuid.UUID, req entity.UpdateShopMemberRequest) (*entity.ShopMemberResponse, error) {
+	manager, err := u.getManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := u.getStaffMember(ctx, manager.ShopID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.memberRepo.UpdateMemberRole(ctx, member.ID, req.Role); err != nil {
+		return nil, fmt.Errorf("failed to update shop member: %w", err)
+	}
+	member.Role = req.Role
+
+	user, err := u.userRepo.GetByID(ctx, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToMemberResponse(member, user), nil
+}
+
+// RemoveMember takes the member out of the shop. Their account goes back to a buyer
+// account the next time its access token is refreshed; until then the member checks
+// already refuse it.
+func (u *shopMemberUsecase) RemoveMember(ctx context.Context, userID uuid.UUID, memberUserID uuid.UUID) error {
+	manager, err := u.getManager(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	member, err := u.getStaffMember(ctx, manager.ShopID, memberUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.memberRepo.RemoveMember(ctx, member); err != nil {
+		return fmt.Errorf("failed to remove shop member: %w", err)
+	}
+	return nil
+}
+
+func (u *shopMemberUsecase) InviteMember(ctx context.Context, userID uuid.UUID, req entity.InviteShopMemberRequest) (*entity.ShopInvitationResponse, error) {
+	manager, err := u.getManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seats, err := u.memberRepo.CountSeats(ctx, manager.ShopID)
+	if err != nil {
+		return nil, err
+	}
+	if seats >= maxShopMembers {
+		return nil, errmap.ErrShopMemberLimitReached
+	}
+
+	inviter, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := securetoken.Generate()
+	if err != nil {
+		return nil, err
+	}
+	now := timeth.Now()
+	invitation := &entity.ShopInvitation{
+		ID:        uuid.New(),
+		ShopID:    manager.ShopID,
+		Email:     strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:      req.Role,
+		TokenHash: securetoken.Hash(token),
+		InvitedBy: userID,
+		ExpiresAt: now.Add(shopInvitationTTL),
+		CreatedAt: now,
+	}
+	if err := u.memberRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	if err := u.queueInvitationEmail(ctx, invitation, inviter, &manager.Shop, token); err != nil {
+		return nil, err
+	}
+
+	return mapToInvitationResponse(invitation), nil
+}
+
+// queueInvitationEmail emails the invitation link, in the inviter's language since the
+// invitee may not have an account yet.
+func (u *shopMemberUsecase) queueInvitationEmail(ctx context.Context, invitation *entity.ShopInvitation, inviter *entity.User, shop *entity.Shop, token string) error {
+	language := inviter.Language
+	if language == "" {
+		language = entity.LanguageThai
+	}
+
+	email, err := notify.Render(entity.EmailTemplateShopInvitation, language, invitation.Email, notify.ShopInvitationData{
+		InviterName:   strings.TrimSpace(inviter.FirstName + " " + inviter.LastName),
+		ShopName:      shop.Name,
+		Role:          invitation.Role,
+		URL:           u.appURL + "/shop-invitations/accept?token=" + url.QueryEscape(token),
+		ExpiresInDays: int(shopInvitationTTL / (24 * time.Hour)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", entity.EmailTemplateShopInvitation, err)
+	}
+
+	now := timeth.Now()
+	return u.notificationRepo.QueueEmails(ctx, []*entity.EmailMessage{{
+		Recipient:     email.To,
+		Template:      entity.EmailTemplateShopInvitation,
+		Language:      language,
+		Subject:       email.Subject,
+		HTMLBody:      email.HTML,
+		TextBody:      email.Text,
+		DedupeKey:     "shop_invitation:" + invitation.ID.String(),
+		Status:        entity.EmailStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}})
+}
+
+func (u *shopMemberUsecase) ListInvitations(ctx context.Context, userID uuid.UUID) ([]*entity.ShopInvitationResponse, error) {
+	manager, err := u.getManager(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := u.memberRepo.ListPendingInvitations(ctx, manager.ShopID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*entity.ShopInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		items = append(items, mapToInvitationResponse(invitation))
+	}
+	return items, nil
+}
+
+func (u *shopMemberUsecase) RevokeInvitation(ctx context.Context, userID uuid.UUID, invitationID uuid.UUID) error {
+	manager, err := u.getManager(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := u.memberRepo.GetInvitationByID(ctx, invitationID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmap.ErrShopInvitationNotFound
+		}
+		return err
+	}
+	if invitation.ShopID != manager.ShopID || invitation.AcceptedAt != nil {
+		return errmap.ErrShopInvitationNotFound
+	}
+
+	return u.memberRepo.DeleteInvitation(ctx, invitation.ID)
+}
+
+// AcceptInvitation makes userID a member of the inviting shop. The invitation must have
+// been sent to the user's email, and the user must not be in a shop already. The new
+// SHOP role is in the access tokens issued from the next refresh.
+func (u *shopMemberUsecase) AcceptInvitation(ctx context.Context, userID uuid.UUID, req entity.AcceptShopInvitationRequest) (*entity.ShopMemberResponse, error) {
+	invitation, err := u.memberRepo.GetInvitationByTokenHash(ctx, securetoken.Hash(req.Token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrShopInvitationInvalid
+		}
+		return nil, err
+	}
+	if !timeth.Now().Before(invitation.ExpiresAt) {
+		return nil, errmap.ErrShopInvitationInvalid
+	}
+
+	user, err := u.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(strings.TrimSpace(user.Email), invitation.Email) {
+		return nil, errmap.ErrShopInvitationEmailMismatch
+	}
+
+	_, err = u.shopRepo.GetShopMemberByUserID(ctx, userID)
+	if err == nil {
+		return nil, errmap.ErrAlreadyShopMember
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find shop for user: %w", err)
+	}
+
+	now := timeth.Now()
+	member := &entity.ShopMember{
+		ID:        uuid.New(),
+		ShopID:    invitation.ShopID,
+		UserID:    userID,
+		Role:      invitation.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := u.memberRepo.AcceptInvitation(ctx, invitation, member); err != nil {
+		if errors.Is(err, errmap.ErrShopInvitationInvalid) || errors.Is(err, errmap.ErrAlreadyShopMember) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to accept invitation: %w", err)
+	}
+
+	return mapToMemberResponse(member, user), nil
+}
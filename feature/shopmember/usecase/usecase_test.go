@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/securetoken"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type testRepos struct {
+	member       *mock.MockShopMemberRepository
+	shop         *mock.MockShopRepository
+	user         *mock.MockUserRepository
+	notification *mock.MockNotificationRepository
+}
+
+func newTestUsecase(t *testing.T) (*shopMemberUsecase, testRepos) {
+	ctrl := gomock.NewController(t)
+	repos := testRepos{
+		member:       mock.NewMockShopMemberRepository(ctrl),
+		shop:         mock.NewMockShopRepository(ctrl),
+		user:         mock.NewMockUserRepository(ctrl),
+		notification: mock.NewMockNotificationRepository(ctrl),
+	}
+	uc := NewShopMemberUsecase(repos.member, repos.shop, repos.user, repos.notification).(*shopMemberUsecase)
+	uc.appURL = "https://shop.example.com"
+	return uc, repos
+}
+
+func TestInviteMember_EmailsTheInvitationLink(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	shop := entity.Shop{ID: uuid.New(), UserID: ownerID, Name: "Baan Coffee"}
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, ownerID).Return(&entity.ShopMember{ShopID: shop.ID, UserID: ownerID, Role: entity.ShopRoleOwner, Shop: shop}, nil)
+	repos.member.EXPECT().CountSeats(ctx, shop.ID).Return(int64(3), nil)
+	repos.user.EXPECT().GetByID(ctx, ownerID).Return(&entity.User{ID: ownerID, FirstName: "Somchai", LastName: "Jaidee", Language: entity.LanguageThai}, nil)
+
+	var saved *entity.ShopInvitation
+	repos.member.EXPECT().CreateInvitation(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, invitation *entity.ShopInvitation) error {
+		saved = invitation
+		return nil
+	})
+	repos.notification.EXPECT().QueueEmails(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, messages []*entity.EmailMessage) error {
+		require.Len(t, messages, 1)
+		assert.Equal(t, "packer@example.com", messages[0].Recipient)
+		assert.Equal(t, entity.EmailTemplateShopInvitation, messages[0].Template)
+		assert.Equal(t, "shop_invitation:"+saved.ID.String(), messages[0].DedupeKey)
+		assert.Contains(t, messages[0].TextBody, "https://shop.example.com/shop-invitations/accept?token=")
+		return nil
+	})
+
+	resp, err := uc.InviteMember(ctx, ownerID, entity.InviteShopMemberRequest{Email: " Packer@Example.com ", Role: entity.ShopRolePacker})
+
+	require.NoError(t, err)
+	assert.Equal(t, "packer@example.com", resp.Email)
+	assert.Equal(t, entity.ShopRolePacker, resp.Role)
+	assert.Equal(t, shop.ID, saved.ShopID)
+	assert.Equal(t, ownerID, saved.InvitedBy)
+	assert.Len(t, saved.TokenHash, 64)
+}
+
+func TestInviteMember_RequiresManageMembers(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	managerID := uuid.New()
+	strangerID := uuid.New()
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, managerID).Return(&entity.ShopMember{ShopID: uuid.New(), UserID: managerID, Role: entity.ShopRoleManager}, nil)
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, strangerID).Return(nil, gorm.ErrRecordNotFound)
+	repos.member.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+
+	req := entity.InviteShopMemberRequest{Email: "support@example.com", Role: entity.ShopRoleSupport}
+	_, err := uc.InviteMember(ctx, managerID, req)
+	assert.ErrorIs(t, err, errmap.ErrForbidden)
+
+	_, err = uc.InviteMember(ctx, strangerID, req)
+	assert.ErrorIs(t, err, errmap.ErrForbidden)
+}
+
+func TestInviteMember_RejectsFullShop(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	shopID := uuid.New()
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, ownerID).Return(&entity.ShopMember{ShopID: shopID, UserID: ownerID, Role: entity.ShopRoleOwner}, nil)
+	repos.member.EXPECT().CountSeats(ctx, shopID).Return(int64(maxShopMembers), nil)
+	repos.member.EXPECT().CreateInvitation(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.InviteMember(ctx, ownerID, entity.InviteShopMemberRequest{Email: "support@example.com", Role: entity.ShopRoleSupport})
+
+	assert.ErrorIs(t, err, errmap.ErrShopMemberLimitReached)
+}
+
+func TestUpdateMember_OwnerIsImmutable(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	shopID := uuid.New()
+	owner := &entity.ShopMember{ShopID: shopID, UserID: ownerID, Role: entity.ShopRoleOwner}
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, ownerID).Return(owner, nil)
+	repos.shop.EXPECT().GetShopMember(ctx, shopID, ownerID).Return(owner, nil)
+	repos.member.EXPECT().UpdateMemberRole(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.UpdateMember(ctx, ownerID, ownerID, entity.UpdateShopMemberRequest{Role: entity.ShopRolePacker})
+
+	assert.ErrorIs(t, err, errmap.ErrShopOwnerImmutable)
+}
+
+func TestUpdateMember_ChangesRole(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	staffID := uuid.New()
+	shopID := uuid.New()
+	staff := &entity.ShopMember{ID: uuid.New(), ShopID: shopID, UserID: staffID, Role: entity.ShopRolePacker}
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, ownerID).Return(&entity.ShopMember{ShopID: shopID, UserID: ownerID, Role: entity.ShopRoleOwner}, nil)
+	repos.shop.EXPECT().GetShopMember(ctx, shopID, staffID).Return(staff, nil)
+	repos.member.EXPECT().UpdateMemberRole(ctx, staff.ID, entity.ShopRoleManager).Return(nil)
+	repos.user.EXPECT().GetByID(ctx, staffID).Return(&entity.User{ID: staffID, Email: "staff@example.com"}, nil)
+
+	resp, err := uc.UpdateMember(ctx, ownerID, staffID, entity.UpdateShopMemberRequest{Role: entity.ShopRoleManager})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.ShopRoleManager, resp.Role)
+	assert.Equal(t, entity.ShopRolePermissions[entity.ShopRoleManager], resp.Permissions)
+}
+
+func TestRemoveMember_OnlyMembersOfTheOwnShop(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	otherShopStaffID := uuid.New()
+	shopID := uuid.New()
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, ownerID).Return(&entity.ShopMember{ShopID: shopID, UserID: ownerID, Role: entity.ShopRoleOwner}, nil)
+	repos.shop.EXPECT().GetShopMember(ctx, shopID, otherShopStaffID).Return(nil, nil)
+	repos.member.EXPECT().RemoveMember(gomock.Any(), gomock.Any()).Times(0)
+
+	err := uc.RemoveMember(ctx, ownerID, otherShopStaffID)
+
+	assert.ErrorIs(t, err, errmap.ErrShopMemberNotFound)
+}
+
+func TestRevokeInvitation_OfAnotherShopIsNotFound(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	ownerID := uuid.New()
+	invitationID := uuid.New()
+
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, ownerID).Return(&entity.ShopMember{ShopID: uuid.New(), UserID: ownerID, Role: entity.ShopRoleOwner}, nil)
+	repos.member.EXPECT().GetInvitationByID(ctx, invitationID).Return(&entity.ShopInvitation{ID: invitationID, ShopID: uuid.New()}, nil)
+	repos.member.EXPECT().DeleteInvitation(gomock.Any(), gomock.Any()).Times(0)
+
+	err := uc.RevokeInvitation(ctx, ownerID, invitationID)
+
+	assert.ErrorIs(t, err, errmap.ErrShopInvitationNotFound)
+}
+
+func TestAcceptInvitation_JoinsShop(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	invitation := &entity.ShopInvitation{ID: uuid.New(), ShopID: uuid.New(), Email: "packer@example.com", Role: entity.ShopRolePacker, ExpiresAt: timeth.Now().Add(time.Hour)}
+
+	repos.member.EXPECT().GetInvitationByTokenHash(ctx, securetoken.Hash("invite-123")).Return(invitation, nil)
+	repos.user.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, Email: "Packer@Example.com"}, nil)
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, userID).Return(nil, gorm.ErrRecordNotFound)
+	repos.member.EXPECT().AcceptInvitation(ctx, invitation, gomock.Any()).DoAndReturn(func(ctx context.Context, inv *entity.ShopInvitation, member *entity.ShopMember) error {
+		assert.Equal(t, invitation.ShopID, member.ShopID)
+		assert.Equal(t, userID, member.UserID)
+		assert.Equal(t, entity.ShopRolePacker, member.Role)
+		return nil
+	})
+
+	resp, err := uc.AcceptInvitation(ctx, userID, entity.AcceptShopInvitationRequest{Token: "invite-123"})
+
+	require.NoError(t, err)
+	assert.Equal(t, invitation.ShopID, resp.ShopID)
+	assert.Equal(t, []string{entity.ShopPermissionFulfilOrders}, resp.Permissions)
+}
+
+func TestAcceptInvitation_RejectsExpiredOrUnknownTokens(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	repos.member.EXPECT().GetInvitationByTokenHash(ctx, securetoken.Hash("expired")).Return(&entity.ShopInvitation{Email: "packer@example.com", ExpiresAt: timeth.Now().Add(-time.Minute)}, nil)
+	repos.member.EXPECT().GetInvitationByTokenHash(ctx, securetoken.Hash("unknown")).Return(nil, gorm.ErrRecordNotFound)
+	repos.member.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	for _, token := range []string{"expired", "unknown"} {
+		_, err := uc.AcceptInvitation(ctx, userID, entity.AcceptShopInvitationRequest{Token: token})
+		assert.ErrorIs(t, err, errmap.ErrShopInvitationInvalid, token)
+	}
+}
+
+func TestAcceptInvitation_EmailMismatch(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	repos.member.EXPECT().GetInvitationByTokenHash(ctx, securetoken.Hash("invite-123")).Return(&entity.ShopInvitation{Email: "packer@example.com", ExpiresAt: timeth.Now().Add(time.Hour)}, nil)
+	repos.user.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, Email: "someone-else@example.com"}, nil)
+	repos.member.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AcceptInvitation(ctx, userID, entity.AcceptShopInvitationRequest{Token: "invite-123"})
+
+	assert.ErrorIs(t, err, errmap.ErrShopInvitationEmailMismatch)
+}
+
+func TestAcceptInvitation_AlreadyMember(t *testing.T) {
+	uc, repos := newTestUsecase(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	repos.member.EXPECT().GetInvitationByTokenHash(ctx, securetoken.Hash("invite-123")).Return(&entity.ShopInvitation{Email: "owner@example.com", ExpiresAt: timeth.Now().Add(time.Hour)}, nil)
+	repos.user.EXPECT().GetByID(ctx, userID).Return(&entity.User{ID: userID, Email: "owner@example.com"}, nil)
+	repos.shop.EXPECT().GetShopMemberByUserID(ctx, userID).Return(&entity.ShopMember{UserID: userID, Role: entity.ShopRoleOwner}, nil)
+	repos.member.EXPECT().AcceptInvitation(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := uc.AcceptInvitation(ctx, userID, entity.AcceptShopInvitationRequest{Token: "invite-123"})
+
+	assert.ErrorIs(t, err, errmap.ErrAlreadyShopMember)
+}
@@ -99,6 +99,9 @@ func (h *UserHandler) UpdateProfile(c echo.Context) error {
 	if req.ImageURL != nil {
 		currentUser.ImageURL = req.ImageURL
 	}
+	if req.Language != nil {
+		currentUser.Language = *req.Language
+	}
 
 	currentUser.UpdatedAt = timeth.Now()
 
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
 	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/fieldcrypt"
 	"ecommerce-go-api/internal/timeth"
 
 	"github.com/google/uuid"
@@ -81,17 +83,21 @@ func (r *userRepository) GetAddressByID(ctx context.Context, id uint32) (*entity
 
 func (r *userRepository) UpdateAddress(ctx context.Context, addr *entity.Address, userID uuid.UUID) error {
 	updates := map[string]interface{}{
-		"name":            addr.Name,
-		"line1":           addr.Line1,
-		"line2":           addr.Line2,
 		"sub_district_id": addr.SubDistrictID,
 		"district_id":     addr.DistrictID,
 		"province_id":     addr.ProvinceID,
 		"zipcode":         addr.Zipcode,
-		"phone_number":    addr.PhoneNumber,
 		"is_default":      addr.IsDefault,
 		"updated_at":      timeth.Now(),
 	}
+	if err := setEncrypted(updates, map[string]string{
+		"name":         addr.Name,
+		"line1":        addr.Line1,
+		"line2":        addr.Line2,
+		"phone_number": addr.PhoneNumber,
+	}); err != nil {
+		return err
+	}
 
 	res := r.db.WithContext(ctx).
 		Model(&entity.Address{}).
@@ -124,11 +130,14 @@ func (r *userRepository) DeleteAddress(ctx context.Context, id uint32, userID uu
 
 func (r *userRepository) UpdateProfile(ctx context.Context, user *entity.User) error {
 	updates := map[string]interface{}{
-		"first_name":   user.FirstName,
-		"last_name":    user.LastName,
-		"phone_number": user.PhoneNumber,
-		"image_url":    user.ImageURL,
-		"updated_at":   timeth.Now(),
+		"first_name": user.FirstName,
+		"last_name":  user.LastName,
+		"image_url":  user.ImageURL,
+		"language":   user.Language,
+		"updated_at": timeth.Now(),
+	}
+	if err := setEncrypted(updates, map[string]string{"phone_number": user.PhoneNumber}); err != nil {
+		return err
 	}
 
 	res := r.db.WithContext(ctx).
@@ -144,3 +153,16 @@ func (r *userRepository) UpdateProfile(ctx context.Context, user *entity.User) e
 	}
 	return nil
 }
+
+// setEncrypted adds encrypted values to a map update. Map updates bypass the
+// fieldcrypt serializer on the entity, so the columns it covers are encrypted here.
+func setEncrypted(updates map[string]interface{}, values map[string]string) error {
+	for column, value := range values {
+		encrypted, err := fieldcrypt.Encrypt(value)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", column, err)
+		}
+		updates[column] = encrypted
+	}
+	return nil
+}
@@ -81,14 +81,16 @@ func (u *userUsecase) GetProfile(ctx context.Context, userID uuid.UUID) (*entity
 	}
 
 	return &entity.UserResponse{
-		ID:          user.ID,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		PhoneNumber: user.PhoneNumber,
-		ImageURL:    user.ImageURL,
-		CreatedAt:   user.CreatedAt,
-		UpdatedAt:   user.UpdatedAt,
+		ID:              user.ID,
+		FirstName:       user.FirstName,
+		LastName:        user.LastName,
+		Email:           user.Email,
+		PhoneNumber:     user.PhoneNumber,
+		ImageURL:        user.ImageURL,
+		Language:        user.Language,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}, nil
 }
 
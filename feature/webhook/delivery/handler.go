@@ -0,0 +1,431 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/response"
+	"ecommerce-go-api/middleware"
+)
+
+type ShopWebhookHandler struct {
+	usecase domain.ShopWebhookUsecase
+}
+
+func NewShopWebhookHandler(usecase domain.ShopWebhookUsecase) *ShopWebhookHandler {
+	return &ShopWebhookHandler{usecase: usecase}
+}
+
+// ListWebhooks godoc
+//
+//	@Summary		List shop webhooks
+//	@Description	Get the webhook endpoints registered by the shop owner's shop
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Success		200	{array}		entity.ShopWebhookResponse
+//	@Failure		401	{object}	response.ResponseError
+//	@Failure		403	{object}	response.ResponseError
+//	@Failure		500	{object}	response.ResponseError
+//	@Router			/api/shop/webhooks [get]
+func (h *ShopWebhookHandler) ListWebhooks(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhooks, err := h.usecase.ListWebhooks(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", webhooks)
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Register a shop webhook
+//	@Description	Register an endpoint to receive the subscribed events. Every delivery is a POST signed with the returned secret: X-Webhook-Signature = "sha256=" + hex(HMAC-SHA256(secret, X-Webhook-Timestamp + "." + body)). The secret is only shown once.
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		entity.CreateShopWebhookRequest	true	"Webhook endpoint"
+//	@Success		201		{object}	entity.ShopWebhookResponse
+//	@Failure		400		{object}	response.ResponseError
+//	@Failure		401		{object}	response.ResponseError
+//	@Failure		403		{object}	response.ResponseError
+//	@Failure		409		{object}	response.ResponseError
+//	@Failure		500		{object}	response.ResponseError
+//	@Router			/api/shop/webhooks [post]
+func (h *ShopWebhookHandler) CreateWebhook(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	var req entity.CreateShopWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	webhook, err := h.usecase.CreateWebhook(c.Request().Context(), userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrInvalidRequest),
+			errors.Is(err, errmap.ErrWebhookURLInsecure),
+			errors.Is(err, errmap.ErrWebhookEventTypeUnsupported):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrWebhookLimitReached):
+			return response.Error(c, http.StatusConflict, errmap.ErrWebhookLimitReached.Error())
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusCreated, "webhook created", webhook)
+}
+
+// GetWebhook godoc
+//
+//	@Summary		Get a shop webhook
+//	@Description	Get a webhook endpoint of the shop owner's shop
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		200			{object}	entity.ShopWebhookResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId} [get]
+func (h *ShopWebhookHandler) GetWebhook(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	webhook, err := h.usecase.GetWebhook(c.Request().Context(), userID, webhookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrWebhookNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", webhook)
+}
+
+// UpdateWebhook godoc
+//
+//	@Summary		Update a shop webhook
+//	@Description	Change a webhook's URL, description or subscribed events, or turn it off and on. Deliveries queued while a webhook is off are marked failed and can be redelivered.
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			webhookId	path		string							true	"Webhook ID"
+//	@Param			request		body		entity.UpdateShopWebhookRequest	true	"Fields to change"
+//	@Success		200			{object}	entity.ShopWebhookResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId} [put]
+func (h *ShopWebhookHandler) UpdateWebhook(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	var req entity.UpdateShopWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	webhook, err := h.usecase.UpdateWebhook(c.Request().Context(), userID, webhookID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrWebhookNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		case errors.Is(err, errmap.ErrInvalidRequest),
+			errors.Is(err, errmap.ErrWebhookURLInsecure),
+			errors.Is(err, errmap.ErrWebhookEventTypeUnsupported):
+			return response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "webhook updated", webhook)
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary		Delete a shop webhook
+//	@Description	Remove a webhook endpoint along with its delivery logs
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		200			{object}	response.ResponseSuccess
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId} [delete]
+func (h *ShopWebhookHandler) DeleteWebhook(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := h.usecase.DeleteWebhook(c.Request().Context(), userID, webhookID); err != nil {
+		if errors.Is(err, errmap.ErrWebhookNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "webhook deleted", nil)
+}
+
+// RotateWebhookSecret godoc
+//
+//	@Summary		Rotate a webhook's signing secret
+//	@Description	Replace the webhook's signing secret. The new secret is only shown once, and every delivery from now on is signed with it.
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Success		200			{object}	entity.ShopWebhookResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId}/rotate-secret [post]
+func (h *ShopWebhookHandler) RotateWebhookSecret(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	webhook, err := h.usecase.RotateWebhookSecret(c.Request().Context(), userID, webhookID)
+	if err != nil {
+		if errors.Is(err, errmap.ErrWebhookNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "webhook secret rotated", webhook)
+}
+
+// ListDeliveries godoc
+//
+//	@Summary		List webhook deliveries
+//	@Description	Get the delivery log of a webhook, newest first
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			page		query		int		false	"Page number (default: 1)"
+//	@Param			perPage		query		int		false	"Items per page (default: 20, max: 100)"
+//	@Param			status		query		string	false	"Filter by status (PENDING, SUCCEEDED, FAILED)"
+//	@Param			eventType	query		string	false	"Filter by event type, e.g. order.placed"
+//	@Success		200			{object}	entity.WebhookDeliveryListResponse
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId}/deliveries [get]
+func (h *ShopWebhookHandler) ListDeliveries(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	var req entity.WebhookDeliveryListRequest
+	if err := c.Bind(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.Error(c, http.StatusBadRequest, err.Error())
+	}
+
+	deliveries, err := h.usecase.ListDeliveries(c.Request().Context(), userID, webhookID, req)
+	if err != nil {
+		if errors.Is(err, errmap.ErrWebhookNotFound) {
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		}
+		if errors.Is(err, errmap.ErrForbidden) {
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		}
+		return response.Error(c, http.StatusInternalServerError, err.Error())
+	}
+
+	return response.Success(c, http.StatusOK, "ok", deliveries)
+}
+
+// GetDelivery godoc
+//
+//	@Summary		Get a webhook delivery
+//	@Description	Get a delivery with the log of every attempt, including the endpoint's response status and body
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			deliveryId	path		string	true	"Delivery ID"
+//	@Success		200			{object}	entity.WebhookDelivery
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId}/deliveries/{deliveryId} [get]
+func (h *ShopWebhookHandler) GetDelivery(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	delivery, err := h.usecase.GetDelivery(c.Request().Context(), userID, webhookID, deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrWebhookNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		case errors.Is(err, errmap.ErrWebhookDeliveryNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookDeliveryNotFound.Error())
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "ok", delivery)
+}
+
+// RedeliverDelivery godoc
+//
+//	@Summary		Redeliver a webhook delivery
+//	@Description	Send a delivery again with the same body and ID and a fresh set of attempts. Deliveries still pending cannot be redelivered.
+//	@Tags			Webhook
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			webhookId	path		string	true	"Webhook ID"
+//	@Param			deliveryId	path		string	true	"Delivery ID"
+//	@Success		200			{object}	entity.WebhookDelivery
+//	@Failure		400			{object}	response.ResponseError
+//	@Failure		401			{object}	response.ResponseError
+//	@Failure		403			{object}	response.ResponseError
+//	@Failure		404			{object}	response.ResponseError
+//	@Failure		409			{object}	response.ResponseError
+//	@Failure		500			{object}	response.ResponseError
+//	@Router			/api/shop/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *ShopWebhookHandler) RedeliverDelivery(c echo.Context) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
+	}
+
+	webhookID, err := uuid.Parse(c.Param("webhookId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		return response.Error(c, http.StatusBadRequest, errmap.ErrInvalidRequest.Error())
+	}
+
+	delivery, err := h.usecase.RedeliverDelivery(c.Request().Context(), userID, webhookID, deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errmap.ErrWebhookNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookNotFound.Error())
+		case errors.Is(err, errmap.ErrWebhookDeliveryNotFound):
+			return response.Error(c, http.StatusNotFound, errmap.ErrWebhookDeliveryNotFound.Error())
+		case errors.Is(err, errmap.ErrWebhookDeliveryPending):
+			return response.Error(c, http.StatusConflict, errmap.ErrWebhookDeliveryPending.Error())
+		case errors.Is(err, errmap.ErrForbidden):
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+		default:
+			return response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return response.Success(c, http.StatusOK, "delivery queued", delivery)
+}
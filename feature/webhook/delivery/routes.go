@@ -0,0 +1,28 @@
+package delivery
+
+import (
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+
+	shopRepo "ecommerce-go-api/feature/shop/repository"
+	"ecommerce-go-api/feature/webhook/repository"
+	"ecommerce-go-api/feature/webhook/usecase"
+	"ecommerce-go-api/middleware"
+)
+
+func RegisterShopWebhookHandler(group *echo.Group, db *gorm.DB) {
+	repo := repository.NewShopWebhookRepository(db)
+	uc := usecase.NewShopWebhookUsecase(repo, shopRepo.NewShopRepository(db))
+	handler := NewShopWebhookHandler(uc)
+
+	webhooks := group.Group("/shop/webhooks", middleware.JWTAuth(), middleware.ShopOwnerOnly())
+	webhooks.GET("", handler.ListWebhooks)
+	webhooks.POST("", handler.CreateWebhook)
+	webhooks.GET("/:webhookId", handler.GetWebhook)
+	webhooks.PUT("/:webhookId", handler.UpdateWebhook)
+	webhooks.DELETE("/:webhookId", handler.DeleteWebhook)
+	webhooks.POST("/:webhookId/rotate-secret", handler.RotateWebhookSecret)
+	webhooks.GET("/:webhookId/deliveries", handler.ListDeliveries)
+	webhooks.GET("/:webhookId/deliveries/:deliveryId", handler.GetDelivery)
+	webhooks.POST("/:webhookId/deliveries/:deliveryId/redeliver", handler.RedeliverDelivery)
+}
@@ -0,0 +1,124 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/timeth"
+)
+
+// enqueueHandler is the name the webhook handler is recorded under on events.
+const enqueueHandler = "webhook.enqueue"
+
+type deliveryHandler struct {
+	webhookRepo domain.ShopWebhookRepository
+	orderRepo   domain.OrderRepository
+}
+
+// RegisterHandlers queues a delivery for every shop webhook subscribed to an event. The
+// sender in internal/webhook posts them.
+func RegisterHandlers(dispatcher *outbox.Dispatcher, webhookRepo domain.ShopWebhookRepository, orderRepo domain.OrderRepository) {
+	h := &deliveryHandler{webhookRepo: webhookRepo, orderRepo: orderRepo}
+	for _, eventType := range entity.WebhookEventTypes {
+		switch eventType {
+		case entity.EventOrderPlaced, entity.EventPaymentSubmitted:
+			dispatcher.Subscribe(eventType, enqueueHandler, h.enqueueOrderEvent)
+		default:
+			dispatcher.Subscribe(eventType, enqueueHandler, h.enqueueShopEvent)
+		}
+	}
+}
+
+// enqueueOrderEvent sends an order-level event to each shop in the order, with that
+// shop's part of the order as data.
+func (h *deliveryHandler) enqueueOrderEvent(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload struct {
+		OrderID uuid.UUID `json:"orderId"`
+	}
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+
+	order, err := h.orderRepo.GetOrderByID(ctx, payload.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+
+	for _, shopOrder := range order.ShopOrders {
+		data, err := json.Marshal(entity.ShopOrderPlacedPayload{
+			ShopOrderPayload: entity.ShopOrderPayload{
+				ShopOrderID: shopOrder.ID,
+				OrderID:     order.ID,
+				ShopID:      shopOrder.ShopID,
+				UserID:      order.UserID,
+				OrderNumber: shopOrder.OrderNumber,
+			},
+			GrandTotal: shopOrder.GrandTotal,
+		})
+		if err != nil {
+			return err
+		}
+		if err := h.enqueue(ctx, event, shopOrder.ShopID, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueueShopEvent sends an event about one shop's order or refund to that shop, with
+// the event's payload as data.
+func (h *deliveryHandler) enqueueShopEvent(ctx context.Context, event *entity.OutboxEvent) error {
+	var payload struct {
+		ShopID uuid.UUID `json:"shopId"`
+	}
+	if err := outbox.Decode(event, &payload); err != nil {
+		return err
+	}
+	return h.enqueue(ctx, event, payload.ShopID, event.Payload)
+}
+
+// enqueue queues the event for the shop's subscribed webhooks. Deliveries already queued
+// by an earlier attempt are kept, so a retried event is not sent twice.
+func (h *deliveryHandler) enqueue(ctx context.Context, event *entity.OutboxEvent, shopID uuid.UUID, data json.RawMessage) error {
+	webhooks, err := h.webhookRepo.ListSubscribedWebhooks(ctx, shopID, event.EventType)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks of shop %s: %w", shopID, err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := timeth.Now()
+	deliveries := make([]*entity.WebhookDelivery, 0, len(webhooks))
+	for _, w := range webhooks {
+		id := uuid.New()
+		body, err := json.Marshal(entity.WebhookEnvelope{
+			ID:        id,
+			EventID:   event.ID,
+			Type:      event.EventType,
+			CreatedAt: event.CreatedAt,
+			Data:      data,
+		})
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, &entity.WebhookDelivery{
+			ID:            id,
+			WebhookID:     w.ID,
+			EventID:       event.ID,
+			EventType:     event.EventType,
+			Payload:       body,
+			Status:        entity.WebhookDeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+
+	return h.webhookRepo.CreateDeliveries(ctx, deliveries)
+}
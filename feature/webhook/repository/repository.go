@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type shopWebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewShopWebhookRepository(db *gorm.DB) domain.ShopWebhookRepository {
+	return &shopWebhookRepository{db: db}
+}
+
+func (r *shopWebhookRepository) CreateWebhook(ctx context.Context, webhook *entity.ShopWebhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+func (r *shopWebhookRepository) GetWebhookByID(ctx context.Context, id uuid.UUID) (*entity.ShopWebhook, error) {
+	var webhook entity.ShopWebhook
+	if err := r.db.WithContext(ctx).First(&webhook, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *shopWebhookRepository) ListWebhooksByShopID(ctx context.Context, shopID uuid.UUID) ([]*entity.ShopWebhook, error) {
+	var webhooks []*entity.ShopWebhook
+	err := r.db.WithContext(ctx).
+		Where("shop_id = ?", shopID).
+		Order("created_at").
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *shopWebhookRepository) CountWebhooksByShopID(ctx context.Context, shopID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.ShopWebhook{}).
+		Where("shop_id = ?", shopID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *shopWebhookRepository) ListSubscribedWebhooks(ctx context.Context, shopID uuid.UUID, eventType string) ([]*entity.ShopWebhook, error) {
+	subscribed, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []*entity.ShopWebhook
+	err = r.db.WithContext(ctx).
+		Where("shop_id = ? AND is_active = ?", shopID, true).
+		Where("event_types @> ?::jsonb", string(subscribed)).
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *shopWebhookRepository) UpdateWebhook(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = timeth.Now()
+	return r.db.WithContext(ctx).Model(&entity.ShopWebhook{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// DeleteWebhook removes the webhook. Its deliveries and their logs go with it.
+func (r *shopWebhookRepository) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.ShopWebhook{}, "id = ?", id).Error
+}
+
+func (r *shopWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entity.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Create(deliveries).Error
+}
+
+// ClaimDueDeliveries leases a batch of due deliveries to the claimant, oldest first.
+// Deliveries locked or leased by another instance are skipped.
+func (r *shopWebhookRepository) ClaimDueDeliveries(ctx context.Context, claim entity.JobClaim) ([]*entity.WebhookDelivery, error) {
+	now := timeth.Now()
+	due := r.db.WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Select("id").
+		Where("status = ?", entity.WebhookDeliveryStatusPending).
+		Where("next_attempt_at <= ?", now).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Order("created_at").
+		Limit(claim.Limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Raw("UPDATE webhook_deliveries SET claimed_by = ?, claimed_until = ? WHERE id IN (?) RETURNING id",
+			claim.Claimant, now.Add(claim.Lease), due).
+		Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var deliveries []*entity.WebhookDelivery
+	err = r.db.WithContext(ctx).
+		Preload("Webhook").
+		Where("id IN ?", ids).
+		Order("created_at").
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *shopWebhookRepository) FinishAttempt(ctx context.Context, delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+	delivery.ClaimedBy = nil
+	delivery.ClaimedUntil = nil
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if attempt != nil {
+			if err := tx.Create(attempt).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(delivery).
+			Select("status", "attempts", "next_attempt_at", "last_response_status", "last_error", "delivered_at", "claimed_by", "claimed_until").
+			Updates(delivery).Error
+	})
+}
+
+func (r *shopWebhookRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, req entity.WebhookDeliveryListRequest) ([]*entity.WebhookDelivery, int64, error) {
+	var deliveries []*entity.WebhookDelivery
+	var total int64
+
+	page := req.Page
+	if page == 0 {
+		page = 1
+	}
+	perPage := req.PerPage
+	if perPage == 0 {
+		perPage = 20
+	}
+	offset := (page - 1) * perPage
+
+	query := r.db.WithContext(ctx).Model(&entity.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.EventType != "" {
+		query = query.Where("event_type = ?", req.EventType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.
+		Order("created_at DESC").
+		Limit(int(perPage)).
+		Offset(int(offset)).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *shopWebhookRepository) GetDeliveryByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Preload("DeliveryAttempts", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at DESC")
+		}).
+		First(&delivery, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// RequeueDelivery sends a finished delivery again with a fresh set of attempts. Its
+// earlier attempts stay in the log.
+func (r *shopWebhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Where("id = ? AND status <> ?", id, entity.WebhookDeliveryStatusPending).
+		Updates(map[string]interface{}{
+			"status":          entity.WebhookDeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": timeth.Now(),
+			"delivered_at":    nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
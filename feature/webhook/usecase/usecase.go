@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/fieldcrypt"
+	"ecommerce-go-api/internal/webhook"
+)
+
+// maxWebhooksPerShop caps how many endpoints a shop can register.
+const maxWebhooksPerShop = 10
+
+type shopWebhookUsecase struct {
+	webhookRepo domain.ShopWebhookRepository
+	shopRepo    domain.ShopRepository
+}
+
+func NewShopWebhookUsecase(webhookRepo domain.ShopWebhookRepository, shopRepo domain.ShopRepository) domain.ShopWebhookUsecase {
+	return &shopWebhookUsecase{
+		webhookRepo: webhookRepo,
+		shopRepo:    shopRepo,
+	}
+}
+
+func mapToWebhookResponse(w *entity.ShopWebhook) *entity.ShopWebhookResponse {
+	return &entity.ShopWebhookResponse{
+		ID:          w.ID,
+		URL:         w.URL,
+		Description: w.Description,
+		EventTypes:  w.EventTypes,
+		IsActive:    w.IsActive,
+		CreatedAt:   w.CreatedAt,
+		UpdatedAt:   w.UpdatedAt,
+	}
+}
+
+// normalizeEventTypes checks that every event type can be subscribed to and drops
+// duplicates.
+func normalizeEventTypes(eventTypes []string) ([]string, error) {
+	normalized := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(entity.WebhookEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: %s", errmap.ErrWebhookEventTypeUnsupported, eventType)
+		}
+		if !slices.Contains(normalized, eventType) {
+			normalized = append(normalized, eventType)
+		}
+	}
+	return normalized, nil
+}
+
+// newEncryptedSecret returns a new signing secret and its encrypted form for storage.
+func newEncryptedSecret() (string, string, error) {
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	encrypted, err := fieldcrypt.Encrypt(secret)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encrypt webhook secret: %w", err)
+	}
+	return secret, encrypted, nil
+}
+
+// getManagedShop returns the shop of userID if the user can manage its settings, which
+// webhooks are part of.
+func (u *shopWebhookUsecase) getManagedShop(ctx context.Context, userID uuid.UUID) (*entity.Shop, error) {
+	member, err := u.shopRepo.GetShopMemberByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find shop for user: %w", err)
+	}
+	if !member.Can(entity.ShopPermissionManageShop) {
+		return nil, errmap.ErrForbidden
+	}
+	return &member.Shop, nil
+}
+
+// getShopWebhook loads a webhook and checks that it belongs to the shop managed by userID.
+func (u *shopWebhookUsecase) getShopWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) (*entity.ShopWebhook, error) {
+	shop, err := u.getManagedShop(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := u.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	if w.ShopID != shop.ID {
+		return nil, errmap.ErrWebhookNotFound
+	}
+
+	return w, nil
+}
+
+// getWebhookDelivery loads a delivery of a webhook belonging to the shop managed by userID.
+func (u *shopWebhookUsecase) getWebhookDelivery(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	if _, err := u.getShopWebhook(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	delivery, err := u.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmap.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	if delivery.WebhookID != webhookID {
+		return nil, errmap.ErrWebhookDeliveryNotFound
+	}
+
+	return delivery, nil
+}
+
+func (u *shopWebhookUsecase) ListWebhooks(ctx context.Context, userID uuid.UUID) ([]*entity.ShopWebhookResponse, error) {
+	shop, err := u.getManagedShop(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks, err := u.webhookRepo.ListWebhooksByShopID(ctx, shop.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*entity.ShopWebhookResponse, 0, len(webhooks))
+	for _, w := range webhooks {
+		items = append(items, mapToWebhookResponse(w))
+	}
+	return items, nil
+}
+
+func (u *shopWebhookUsecase) CreateWebhook(ctx context.Context, userID uuid.UUID, req entity.CreateShopWebhookRequest) (*entity.ShopWebhookResponse, error) {
+	shop, err := u.getManagedShop(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := webhook.CheckURL(req.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeEventTypes(req.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := u.webhookRepo.CountWebhooksByShopID(ctx, shop.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWebhooksPerShop {
+		return nil, errmap.ErrWebhookLimitReached
+	}
+
+	secret, encrypted, err := newEncryptedSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &entity.ShopWebhook{
+		ShopID:      shop.ID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      encrypted,
+		EventTypes:  eventTypes,
+		IsActive:    true,
+	}
+	if err := u.webhookRepo.CreateWebhook(ctx, w); err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	res := mapToWebhookResponse(w)
+	res.Secret = secret
+	return res, nil
+}
+
+func (u *shopWebhookUsecase) GetWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) (*entity.ShopWebhookResponse, error) {
+	w, err := u.getShopWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToWebhookResponse(w), nil
+}
+
+func (u *shopWebhookUsecase) UpdateWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, req entity.UpdateShopWebhookRequest) (*entity.ShopWebhookResponse, error) {
+	if _, err := u.getShopWebhook(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.URL != nil {
+		if err := webhook.CheckURL(*req.URL); err != nil {
+			return nil, err
+		}
+		updates["url"] = *req.URL
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.EventTypes != nil {
+		eventTypes, err := normalizeEventTypes(req.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+		// Map updates skip the serializer, so the jsonb value is passed as JSON text.
+		encoded, err := json.Marshal(eventTypes)
+		if err != nil {
+			return nil, err
+		}
+		updates["event_types"] = string(encoded)
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) > 0 {
+		if err := u.webhookRepo.UpdateWebhook(ctx, webhookID, updates); err != nil {
+			return nil, fmt.Errorf("failed to update webhook: %w", err)
+		}
+	}
+
+	updated, err := u.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return mapToWebhookResponse(updated), nil
+}
+
+func (u *shopWebhookUsecase) DeleteWebhook(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) error {
+	if _, err := u.getShopWebhook(ctx, userID, webhookID); err != nil {
+		return err
+	}
+	return u.webhookRepo.DeleteWebhook(ctx, webhookID)
+}
+
+// RotateWebhookSecret replaces the signing secret. Deliveries sent from now on, including
+// retries of earlier ones, are signed with the new secret.
+func (u *shopWebhookUsecase) RotateWebhookSecret(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID) (*entity.ShopWebhookResponse, error) {
+	w, err := u.getShopWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, encrypted, err := newEncryptedSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := u.webhookRepo.UpdateWebhook(ctx, w.ID, map[string]interface{}{"secret": encrypted}); err != nil {
+		return nil, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	updated, err := u.webhookRepo.GetWebhookByID(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := mapToWebhookResponse(updated)
+	res.Secret = secret
+	return res, nil
+}
+
+func (u *shopWebhookUsecase) ListDeliveries(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, req entity.WebhookDeliveryListRequest) (*entity.WebhookDeliveryListResponse, error) {
+	if _, err := u.getShopWebhook(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, total, err := u.webhookRepo.ListDeliveries(ctx, webhookID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.WebhookDeliveryListResponse{
+		Items: deliveries,
+		Total: total,
+	}, nil
+}
+
+func (u *shopWebhookUsecase) GetDelivery(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	return u.getWebhookDelivery(ctx, userID, webhookID, deliveryID)
+}
+
+// RedeliverDelivery queues a finished delivery to be sent again, with the same body and
+// ID, so the shop can recover events its endpoint missed.
+func (u *shopWebhookUsecase) RedeliverDelivery(ctx context.Context, userID uuid.UUID, webhookID uuid.UUID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	delivery, err := u.getWebhookDelivery(ctx, userID, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status == entity.WebhookDeliveryStatusPending {
+		return nil, errmap.ErrWebhookDeliveryPending
+	}
+
+	if err := u.webhookRepo.RequeueDelivery(ctx, deliveryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Requeued by a concurrent request.
+			return nil, errmap.ErrWebhookDeliveryPending
+		}
+		return nil, fmt.Errorf("failed to requeue delivery: %w", err)
+	}
+
+	return u.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+}
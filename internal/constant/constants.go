@@ -1,3 +1,9 @@
 package constant
 
+import "time"
+
 const OrderPrefix = "ORD"
+
+// RefundResponseWindow is how long the other party has to respond to a
+// buyer refund request or counter-offer before it can be escalated.
+const RefundResponseWindow = 72 * time.Hour
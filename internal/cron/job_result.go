@@ -0,0 +1,39 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+const maxErrorSamples = 10
+
+const (
+	// claimBatchSize is how many rows a job claims at a time.
+	claimBatchSize = 100
+	// claimLease is how long claimed rows stay reserved for this instance. Rows that
+	// are still eligible after it runs out, e.g. because processing failed, are picked
+	// up again by a later run.
+	claimLease = 10 * time.Minute
+)
+
+// JobResult summarises one run of a job for the run history.
+type JobResult struct {
+	Processed int
+	Succeeded int
+	Failed    int
+	// Errors keeps the first maxErrorSamples item errors.
+	Errors []string
+	// Err is set when the run could not do its work at all.
+	Err error
+}
+
+func (r *JobResult) succeed() {
+	r.Succeeded++
+}
+
+func (r *JobResult) fail(format string, args ...any) {
+	r.Failed++
+	if len(r.Errors) < maxErrorSamples {
+		r.Errors = append(r.Errors, fmt.Sprintf(format, args...))
+	}
+}
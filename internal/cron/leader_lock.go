@@ -0,0 +1,121 @@
+package cron
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// advisoryLockNamespace keeps the job locks apart from any other advisory locks taken on
+// the same database.
+const advisoryLockNamespace = 0x6A6F62
+
+const leaseCheckInterval = 10 * time.Second
+
+var errLockHeld = errors.New("lock is held by another instance")
+
+// LeaderLocker elects one instance to run each job. TryLock returns errLockHeld when
+// another instance holds the lock.
+type LeaderLocker interface {
+	TryLock(ctx context.Context, name string) (Lease, error)
+}
+
+// Lease is a held leader lock. Lost is closed when the lock can no longer be trusted to
+// be held, e.g. because the database session holding it went away.
+type Lease interface {
+	Lost() <-chan struct{}
+	Release()
+}
+
+type advisoryLocker struct {
+	db *sql.DB
+}
+
+// NewAdvisoryLocker elects leaders with Postgres session advisory locks. Each lease
+// holds its own connection, so the lock is released by Postgres if the instance dies.
+func NewAdvisoryLocker(db *sql.DB) LeaderLocker {
+	return &advisoryLocker{db: db}
+}
+
+func (l *advisoryLocker) TryLock(ctx context.Context, name string) (Lease, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1, hashtext($2))", advisoryLockNamespace, name).Scan(&acquired)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, errLockHeld
+	}
+
+	lease := &advisoryLease{
+		conn: conn,
+		name: name,
+		lost: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go lease.watch()
+
+	return lease, nil
+}
+
+type advisoryLease struct {
+	conn *sql.Conn
+	name string
+
+	lost     chan struct{}
+	lostOnce sync.Once
+	done     chan struct{}
+	release  sync.Once
+}
+
+// watch pings the lock's session. If the session is gone, so is the lock.
+func (l *advisoryLease) watch() {
+	ticker := time.NewTicker(leaseCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := l.conn.PingContext(ctx)
+			cancel()
+			if err != nil {
+				log.Printf("[CRON] Lost leader lock for %s: %v", l.name, err)
+				l.lostOnce.Do(func() { close(l.lost) })
+				return
+			}
+		}
+	}
+}
+
+func (l *advisoryLease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+func (l *advisoryLease) Release() {
+	l.release.Do(func() {
+		close(l.done)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1, hashtext($2))", advisoryLockNamespace, l.name); err != nil {
+			log.Printf("[CRON] Warning: Failed to release leader lock for %s: %v", l.name, err)
+			// Drop the connection instead of returning it to the pool still holding the lock.
+			l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		l.conn.Close()
+	})
+}
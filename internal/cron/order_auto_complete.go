@@ -4,50 +4,110 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/outbox"
 	"ecommerce-go-api/internal/timeth"
 )
 
+// OrderAutoCompleteJob completes delivered shop orders the buyer has not confirmed once
+// the auto-complete window has passed, reminding the buyer a day before. The window is
+// ORDER_AUTO_COMPLETE_DAYS unless the shop sets its own.
 type OrderAutoCompleteJob struct {
-	orderRepo domain.OrderRepository
+	orderRepo   domain.OrderRepository
+	outboxRepo  domain.OutboxRepository
+	tx          domain.Transactor
+	defaultDays int
+	claimant    string
 }
 
-func NewOrderAutoCompleteJob(orderRepo domain.OrderRepository) *OrderAutoCompleteJob {
+func NewOrderAutoCompleteJob(orderRepo domain.OrderRepository, outboxRepo domain.OutboxRepository, tx domain.Transactor, defaultDays int, claimant string) *OrderAutoCompleteJob {
 	return &OrderAutoCompleteJob{
-		orderRepo: orderRepo,
+		orderRepo:   orderRepo,
+		outboxRepo:  outboxRepo,
+		tx:          tx,
+		defaultDays: defaultDays,
+		claimant:    claimant,
 	}
 }
 
-func (j *OrderAutoCompleteJob) ProcessDeliveredOrders() {
-	ctx := context.Background()
+func (j *OrderAutoCompleteJob) claim() entity.JobClaim {
+	return entity.JobClaim{Claimant: j.claimant, Limit: claimBatchSize, Lease: claimLease}
+}
+
+func getAutoCompleteDays() int {
+	const defaultDays = 7
+
+	daysStr := os.Getenv("ORDER_AUTO_COMPLETE_DAYS")
+	if daysStr == "" {
+		return defaultDays
+	}
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days < 2 {
+		log.Printf("Warning: Invalid ORDER_AUTO_COMPLETE_DAYS '%s'. Using default %d.", daysStr, defaultDays)
+		return defaultDays
+	}
+
+	return days
+}
+
+// autoCompleteDays is the window that applies to the shop order's shop.
+func (j *OrderAutoCompleteJob) autoCompleteDays(shopOrder *entity.ShopOrder) int {
+	if shopOrder.Shop.AutoCompleteDays != nil {
+		return int(*shopOrder.Shop.AutoCompleteDays)
+	}
+	return j.defaultDays
+}
+
+func (j *OrderAutoCompleteJob) ProcessDeliveredOrders(ctx context.Context) JobResult {
 	startTime := timeth.Now()
 
-	const autoCompleteDays = 7
-	deliveredOrders, err := j.orderRepo.ListDeliveredOrdersOlderThan(ctx, autoCompleteDays)
-	if err != nil {
-		log.Printf("[CRON] Error fetching delivered orders: %v", err)
-		return
+	j.sendReminders(ctx)
+
+	var mu sync.Mutex
+	var result JobResult
+
+	for ctx.Err() == nil {
+		deliveredOrders, err := j.orderRepo.ClaimDeliveredOrdersToAutoComplete(ctx, j.defaultDays, j.claim())
+		if err != nil {
+			log.Printf("[CRON] Error claiming delivered orders: %v", err)
+			result.Err = fmt.Errorf("failed to claim delivered orders: %w", err)
+			break
+		}
+
+		if len(deliveredOrders) == 0 {
+			break
+		}
+
+		log.Printf("[CRON] Claimed %d delivered orders to auto-complete", len(deliveredOrders))
+		result.Processed += len(deliveredOrders)
+
+		j.processBatch(ctx, deliveredOrders, &mu, &result)
 	}
 
-	if len(deliveredOrders) == 0 {
+	if result.Processed == 0 && result.Err == nil {
 		log.Println("[CRON] No delivered orders to auto-complete")
-		return
+		return result
 	}
 
-	log.Printf("[CRON] Found %d delivered orders to auto-complete", len(deliveredOrders))
+	duration := time.Since(startTime)
+	log.Printf("[CRON] Auto-complete check completed in %v - Success: %d, Errors: %d, Total: %d",
+		duration, result.Succeeded, result.Failed, result.Processed)
+
+	return result
+}
 
+func (j *OrderAutoCompleteJob) processBatch(ctx context.Context, deliveredOrders []*entity.ShopOrder, mu *sync.Mutex, result *JobResult) {
 	const maxWorkers = 10
 	semaphore := make(chan struct{}, maxWorkers)
 	var wg sync.WaitGroup
 
-	var mu sync.Mutex
-	successCount := 0
-	errorCount := 0
-
 	for _, order := range deliveredOrders {
 		wg.Add(1)
 		semaphore <- struct{}{}
@@ -60,7 +120,7 @@ func (j *OrderAutoCompleteJob) ProcessDeliveredOrders() {
 				if r := recover(); r != nil {
 					log.Printf("[CRON] Panic recovered while auto-completing order %s: %v", shopOrder.ID, r)
 					mu.Lock()
-					errorCount++
+					result.fail("shop order %s: panic: %v", shopOrder.ID, r)
 					mu.Unlock()
 				}
 			}()
@@ -71,52 +131,121 @@ func (j *OrderAutoCompleteJob) ProcessDeliveredOrders() {
 			if err := j.autoCompleteOrder(processCtx, shopOrder); err != nil {
 				log.Printf("[CRON] Error auto-completing order %s: %v", shopOrder.ID, err)
 				mu.Lock()
-				errorCount++
+				result.fail("shop order %s: %v", shopOrder.ID, err)
 				mu.Unlock()
 				return
 			}
 
 			log.Printf("[CRON] Successfully auto-completed order %s (Order Number: %s, Delivered at: %s)",
-				shopOrder.ID, shopOrder.OrderNumber, shopOrder.UpdatedAt.Format(time.RFC3339))
+				shopOrder.ID, shopOrder.OrderNumber, deliveredAt(shopOrder).Format(time.RFC3339))
 			mu.Lock()
-			successCount++
+			result.succeed()
 			mu.Unlock()
 		}(order)
 	}
 
 	wg.Wait()
-
-	duration := time.Since(startTime)
-	log.Printf("[CRON] Auto-complete check completed in %v - Success: %d, Errors: %d, Total: %d",
-		duration, successCount, errorCount, len(deliveredOrders))
 }
 
 func (j *OrderAutoCompleteJob) autoCompleteOrder(ctx context.Context, shopOrder *entity.ShopOrder) error {
-	if err := j.orderRepo.UpdateShopOrderStatus(ctx, shopOrder.ID, entity.OrderStatusCompleted); err != nil {
-		return fmt.Errorf("failed to update shop order status: %w", err)
-	}
-
-	if err := j.orderRepo.UpdateShipmentStatusByShopOrderID(ctx, shopOrder.ID, entity.ShipmentStatusDelivered); err != nil {
-		log.Printf("[CRON] Warning: Failed to update shipment status for order %s: %v", shopOrder.ID, err)
-	}
+	return j.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := j.orderRepo.UpdateShopOrderStatus(ctx, shopOrder.ID, entity.OrderStatusCompleted); err != nil {
+			return fmt.Errorf("failed to update shop order status: %w", err)
+		}
 
-	go func() {
-		logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+		if err := j.orderRepo.UpdateShipmentStatusByShopOrderID(ctx, shopOrder.ID, entity.ShipmentStatusDelivered); err != nil {
+			return fmt.Errorf("failed to update shipment status: %w", err)
+		}
 
 		now := timeth.Now()
 		orderLog := &entity.OrderLog{
 			OrderID:       shopOrder.OrderID,
 			ShopOrderID:   &shopOrder.ID,
 			OrderStatusID: entity.OrderStatusCompleted,
-			Note:          fmt.Sprintf("Auto-completed: Customer did not confirm within %d days", 7),
+			Note:          fmt.Sprintf("Auto-completed: Customer did not confirm within %d days", j.autoCompleteDays(shopOrder)),
 			CreatedAt:     &now,
 		}
+		if err := j.orderRepo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
+		}
+
+		event, err := outbox.NewEvent(entity.EventShopOrderCompleted, entity.AggregateShopOrder, shopOrder.ID, entity.ShopOrderCompletedPayload{
+			ShopOrderPayload: outbox.ShopOrderPayload(shopOrder),
+			Automatic:        true,
+		})
+		if err != nil {
+			return err
+		}
+		return j.outboxRepo.Add(ctx, event)
+	})
+}
+
+// sendReminders tells buyers whose orders will be auto-completed within a day that they
+// can still confirm receipt or open a refund request. Each order is reminded once; the
+// reminder is logged on the order and its event is delivered by email and in-app.
+func (j *OrderAutoCompleteJob) sendReminders(ctx context.Context) {
+	claimed, reminded := 0, 0
+	for ctx.Err() == nil {
+		dueOrders, err := j.orderRepo.ClaimDeliveredOrdersToRemind(ctx, j.defaultDays, j.claim())
+		if err != nil {
+			log.Printf("[CRON] Error claiming orders to remind before auto-complete: %v", err)
+			break
+		}
+		if len(dueOrders) == 0 {
+			break
+		}
+		claimed += len(dueOrders)
+		reminded += j.remind(ctx, dueOrders)
+	}
+
+	if claimed > 0 {
+		log.Printf("[CRON] Sent %d of %d auto-complete reminders", reminded, claimed)
+	}
+}
+
+func (j *OrderAutoCompleteJob) remind(ctx context.Context, dueOrders []*entity.ShopOrder) int {
+	reminded := 0
+	for _, shopOrder := range dueOrders {
+		completeAt := deliveredAt(shopOrder).AddDate(0, 0, j.autoCompleteDays(shopOrder))
+		now := timeth.Now()
+		orderLog := &entity.OrderLog{
+			OrderID:       shopOrder.OrderID,
+			ShopOrderID:   &shopOrder.ID,
+			OrderStatusID: entity.OrderStatusDelivered,
+			Note: fmt.Sprintf("Reminder: order %s will be completed automatically after %s. Confirm receipt or request a refund before then if something is wrong.",
+				shopOrder.OrderNumber, completeAt.Format("2006-01-02 15:04")),
+			CreatedAt: &now,
+		}
+		err := j.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := j.orderRepo.CreateOrderLog(ctx, orderLog); err != nil {
+				return err
+			}
+			if err := j.orderRepo.MarkAutoCompleteReminded(ctx, shopOrder.ID); err != nil {
+				return err
+			}
 
-		if err := j.orderRepo.CreateOrderLog(logCtx, orderLog); err != nil {
-			log.Printf("[CRON] Warning: Failed to create order log for %s: %v", shopOrder.ID, err)
+			event, err := outbox.NewEvent(entity.EventShopOrderAutoCompleteReminder, entity.AggregateShopOrder, shopOrder.ID, entity.ShopOrderAutoCompleteReminderPayload{
+				ShopOrderPayload: outbox.ShopOrderPayload(shopOrder),
+				CompleteAt:       completeAt,
+			})
+			if err != nil {
+				return err
+			}
+			return j.outboxRepo.Add(ctx, event)
+		})
+		if err != nil {
+			log.Printf("[CRON] Error creating auto-complete reminder for %s: %v", shopOrder.ID, err)
+			continue
 		}
-	}()
+		reminded++
+	}
+	return reminded
+}
 
-	return nil
+// deliveredAt falls back to UpdatedAt for orders delivered before DeliveredAt was recorded.
+func deliveredAt(shopOrder *entity.ShopOrder) time.Time {
+	if shopOrder.DeliveredAt != nil {
+		return *shopOrder.DeliveredAt
+	}
+	return shopOrder.UpdatedAt
 }
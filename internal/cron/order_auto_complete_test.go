@@ -0,0 +1,56 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/outbox"
+)
+
+func TestRemind_PublishesReminderToTheBuyer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mock.NewMockOrderRepository(ctrl)
+	outboxRepo := mock.NewMockOutboxRepository(ctrl)
+	tx := mock.NewMockTransactor(ctrl)
+	tx.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return fn(ctx)
+	})
+	job := NewOrderAutoCompleteJob(orderRepo, outboxRepo, tx, 7, "api-1")
+
+	deliveredAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	days := uint32(5)
+	shopOrder := &entity.ShopOrder{
+		ID:          uuid.New(),
+		OrderID:     uuid.New(),
+		ShopID:      uuid.New(),
+		OrderNumber: "ORD-0001",
+		DeliveredAt: &deliveredAt,
+		Order:       entity.Order{UserID: uuid.New()},
+		Shop:        entity.Shop{AutoCompleteDays: &days},
+	}
+
+	orderRepo.EXPECT().CreateOrderLog(gomock.Any(), gomock.Any()).Return(nil)
+	orderRepo.EXPECT().MarkAutoCompleteReminded(gomock.Any(), shopOrder.ID).Return(nil)
+	outboxRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, events ...*entity.OutboxEvent) error {
+		require.Len(t, events, 1)
+		assert.Equal(t, entity.EventShopOrderAutoCompleteReminder, events[0].EventType)
+
+		var payload entity.ShopOrderAutoCompleteReminderPayload
+		require.NoError(t, outbox.Decode(events[0], &payload))
+		assert.Equal(t, shopOrder.Order.UserID, payload.UserID)
+		assert.True(t, deliveredAt.AddDate(0, 0, 5).Equal(payload.CompleteAt))
+		return nil
+	})
+
+	assert.Equal(t, 1, job.remind(context.Background(), []*entity.ShopOrder{shopOrder}))
+}
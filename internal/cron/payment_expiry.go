@@ -9,46 +9,73 @@ import (
 
 	"ecommerce-go-api/domain"
 	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/outbox"
 	"ecommerce-go-api/internal/timeth"
 )
 
 type PaymentExpiryJob struct {
-	orderRepo   domain.OrderRepository
-	productRepo domain.ProductRepository
+	orderRepo  domain.OrderRepository
+	outboxRepo domain.OutboxRepository
+	tx         domain.Transactor
+	claimant   string
 }
 
-func NewPaymentExpiryJob(orderRepo domain.OrderRepository, productRepo domain.ProductRepository) *PaymentExpiryJob {
+func NewPaymentExpiryJob(orderRepo domain.OrderRepository, outboxRepo domain.OutboxRepository, tx domain.Transactor, claimant string) *PaymentExpiryJob {
 	return &PaymentExpiryJob{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
+		orderRepo:  orderRepo,
+		outboxRepo: outboxRepo,
+		tx:         tx,
+		claimant:   claimant,
 	}
 }
 
-func (j *PaymentExpiryJob) ProcessExpiredPayments() {
-	ctx := context.Background()
+// ProcessExpiredPayments claims expired payments batch by batch until none are left or
+// ctx is cancelled.
+func (j *PaymentExpiryJob) ProcessExpiredPayments(ctx context.Context) JobResult {
 	startTime := timeth.Now()
 
-	expiredPayments, err := j.orderRepo.ListExpiredPayments(ctx)
-	if err != nil {
-		log.Printf("[CRON] Error fetching expired payments: %v", err)
-		return
+	var mu sync.Mutex
+	var result JobResult
+
+	for ctx.Err() == nil {
+		expiredPayments, err := j.orderRepo.ClaimExpiredPayments(ctx, entity.JobClaim{
+			Claimant: j.claimant,
+			Limit:    claimBatchSize,
+			Lease:    claimLease,
+		})
+		if err != nil {
+			log.Printf("[CRON] Error claiming expired payments: %v", err)
+			result.Err = fmt.Errorf("failed to claim expired payments: %w", err)
+			break
+		}
+
+		if len(expiredPayments) == 0 {
+			break
+		}
+
+		log.Printf("[CRON] Claimed %d expired payments to process", len(expiredPayments))
+		result.Processed += len(expiredPayments)
+
+		j.processBatch(ctx, expiredPayments, &mu, &result)
 	}
 
-	if len(expiredPayments) == 0 {
+	if result.Processed == 0 && result.Err == nil {
 		log.Println("[CRON] No expired payments found")
-		return
+		return result
 	}
 
-	log.Printf("[CRON] Found %d expired payments to process", len(expiredPayments))
+	duration := time.Since(startTime)
+	log.Printf("[CRON] Expired payment check completed in %v - Success: %d, Errors: %d, Total: %d",
+		duration, result.Succeeded, result.Failed, result.Processed)
+
+	return result
+}
 
+func (j *PaymentExpiryJob) processBatch(ctx context.Context, expiredPayments []*entity.Payment, mu *sync.Mutex, result *JobResult) {
 	const maxWorkers = 10
 	semaphore := make(chan struct{}, maxWorkers)
 	var wg sync.WaitGroup
 
-	var mu sync.Mutex
-	successCount := 0
-	errorCount := 0
-
 	for _, payment := range expiredPayments {
 		wg.Add(1)
 		semaphore <- struct{}{}
@@ -61,7 +88,7 @@ func (j *PaymentExpiryJob) ProcessExpiredPayments() {
 				if r := recover(); r != nil {
 					log.Printf("[CRON] Panic recovered while processing payment %s: %v", p.ID, r)
 					mu.Lock()
-					errorCount++
+					result.fail("payment %s: panic: %v", p.ID, r)
 					mu.Unlock()
 				}
 			}()
@@ -72,119 +99,98 @@ func (j *PaymentExpiryJob) ProcessExpiredPayments() {
 			if err := j.processExpiredPayment(processCtx, p); err != nil {
 				log.Printf("[CRON] Error processing expired payment %s: %v", p.ID, err)
 				mu.Lock()
-				errorCount++
+				result.fail("payment %s: %v", p.ID, err)
 				mu.Unlock()
 				return
 			}
 
 			log.Printf("[CRON] Successfully processed expired payment %s (Order: %s)", p.ID, p.OrderID)
 			mu.Lock()
-			successCount++
+			result.succeed()
 			mu.Unlock()
 		}(payment)
 	}
 
 	wg.Wait()
-
-	duration := time.Since(startTime)
-	log.Printf("[CRON] Expired payment check completed in %v - Success: %d, Errors: %d, Total: %d",
-		duration, successCount, errorCount, len(expiredPayments))
 }
 
+// processExpiredPayment expires the payment and cancels its shop orders in one
+// transaction. Stock goes back through the shop orders' cancelled events.
 func (j *PaymentExpiryJob) processExpiredPayment(ctx context.Context, payment *entity.Payment) error {
 	order, err := j.orderRepo.GetOrderByID(ctx, payment.OrderID)
 	if err != nil {
 		return fmt.Errorf("failed to get order: %w", err)
 	}
 
-	if err := j.orderRepo.UpdatePaymentStatus(ctx, payment.ID, entity.PaymentStatusExpired, nil); err != nil {
-		return fmt.Errorf("failed to update payment status: %w", err)
-	}
-
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var processingErrors []error
+	return j.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := j.orderRepo.UpdatePaymentStatus(ctx, payment.ID, entity.PaymentStatusExpired, nil); err != nil {
+			return fmt.Errorf("failed to update payment status: %w", err)
+		}
 
-	for i := range order.ShopOrders {
-		wg.Add(1)
+		now := timeth.Now()
+		reason := fmt.Sprintf("Order cancelled due to payment expiry (Transaction: %s)", payment.TransactionID)
+		events := make([]*entity.OutboxEvent, 0, len(order.ShopOrders)+1)
 
-		go func(shopOrder entity.ShopOrder) {
-			defer wg.Done()
+		for i := range order.ShopOrders {
+			shopOrder := &order.ShopOrders[i]
 
 			if err := j.orderRepo.UpdateShopOrderStatus(ctx, shopOrder.ID, entity.OrderStatusCancelled); err != nil {
-				mu.Lock()
-				processingErrors = append(processingErrors, fmt.Errorf("failed to cancel shop order %s: %w", shopOrder.ID, err))
-				mu.Unlock()
-				return
+				return fmt.Errorf("failed to cancel shop order %s: %w", shopOrder.ID, err)
 			}
 
-			j.restoreShopOrderStock(ctx, shopOrder.OrderItems)
-
-			go func(so entity.ShopOrder) {
-				logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				defer cancel()
-
-				now := timeth.Now()
-				shopOrderLog := &entity.OrderLog{
-					OrderID:       order.ID,
-					ShopOrderID:   &so.ID,
-					OrderStatusID: entity.OrderStatusCancelled,
-					Note:          fmt.Sprintf("Order cancelled due to payment expiry (Transaction: %s)", payment.TransactionID),
-					CreatedAt:     &now,
-				}
-				if err := j.orderRepo.CreateOrderLog(logCtx, shopOrderLog); err != nil {
-					log.Printf("[CRON] Warning: Failed to create shop order log for %s: %v", so.ID, err)
-				}
-			}(shopOrder)
-		}(order.ShopOrders[i])
-	}
-
-	wg.Wait()
-
-	if len(processingErrors) > 0 {
-		return fmt.Errorf("encountered %d errors while processing shop orders: %v", len(processingErrors), processingErrors[0])
-	}
+			shopOrderLog := &entity.OrderLog{
+				OrderID:       order.ID,
+				ShopOrderID:   &shopOrder.ID,
+				OrderStatusID: entity.OrderStatusCancelled,
+				Note:          reason,
+				CreatedAt:     &now,
+			}
+			if err := j.orderRepo.CreateOrderLog(ctx, shopOrderLog); err != nil {
+				return fmt.Errorf("failed to create shop order log for %s: %w", shopOrder.ID, err)
+			}
 
-	go func() {
-		logCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+			restock := make([]entity.StockItem, 0, len(shopOrder.OrderItems))
+			for _, item := range shopOrder.OrderItems {
+				restock = append(restock, entity.StockItem{ProductID: item.ProductID, Qty: item.Qty})
+			}
+			event, err := outbox.NewEvent(entity.EventShopOrderCancelled, entity.AggregateShopOrder, shopOrder.ID, entity.ShopOrderCancelledPayload{
+				ShopOrderPayload: entity.ShopOrderPayload{
+					ShopOrderID: shopOrder.ID,
+					OrderID:     order.ID,
+					ShopID:      shopOrder.ShopID,
+					UserID:      order.UserID,
+					OrderNumber: shopOrder.OrderNumber,
+				},
+				Reason:  reason,
+				Restock: restock,
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
 
-		now := timeth.Now()
 		orderLog := &entity.OrderLog{
 			OrderID:   order.ID,
 			Note:      fmt.Sprintf("Payment expired (Transaction: %s, Expired at: %s)", payment.TransactionID, payment.ExpiresAt.Format(time.RFC3339)),
 			CreatedAt: &now,
 		}
-		if err := j.orderRepo.CreateOrderLog(logCtx, orderLog); err != nil {
-			log.Printf("[CRON] Warning: Failed to create main order log for %s: %v", order.ID, err)
+		if err := j.orderRepo.CreateOrderLog(ctx, orderLog); err != nil {
+			return fmt.Errorf("failed to create order log: %w", err)
 		}
-	}()
-
-	return nil
-}
-
-func (j *PaymentExpiryJob) restoreShopOrderStock(ctx context.Context, orderItems []entity.OrderItem) {
-	if len(orderItems) == 0 {
-		return
-	}
-
-	var wg sync.WaitGroup
-	for i := range orderItems {
-		wg.Add(1)
-
-		go func(item entity.OrderItem) {
-			defer wg.Done()
-
-			restoreCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-			defer cancel()
 
-			if err := j.productRepo.RestoreProductStock(restoreCtx, item.ProductID, item.Qty); err != nil {
-				log.Printf("[CRON] Warning: Failed to restore stock for product %d (qty: %d): %v", item.ProductID, item.Qty, err)
-			} else {
-				log.Printf("[CRON] Successfully restored stock for product %d (qty: %d)", item.ProductID, item.Qty)
-			}
-		}(orderItems[i])
-	}
+		event, err := outbox.NewEvent(entity.EventPaymentExpired, entity.AggregateOrder, order.ID, entity.PaymentExpiredPayload{
+			PaymentID:     payment.ID,
+			OrderID:       order.ID,
+			UserID:        order.UserID,
+			TransactionID: payment.TransactionID,
+			ExpiresAt:     payment.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
 
-	wg.Wait()
+		return j.outboxRepo.Add(ctx, events...)
+	})
 }
@@ -1,51 +1,142 @@
 package cron
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
 	"time"
 
 	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
 
 	"github.com/go-co-op/gocron/v2"
+	"github.com/google/uuid"
 )
 
+// JobFunc is the body of a scheduled job.
+type JobFunc func(ctx context.Context) JobResult
+
+type scheduledJob struct {
+	name     string
+	schedule string
+	run      JobFunc
+	job      gocron.Job
+	paused   bool
+	running  bool
+}
+
+// Scheduler runs the background jobs, records every run in the job run history and
+// lets admins trigger, pause and resume jobs. Schedules come from JOB_*_SCHEDULE env
+// vars and accept either a Go duration ("10m") or a cron expression ("0 0 * * *").
+//
+// Every replica runs a Scheduler. Each run first takes the job's leader lock, so only
+// one instance runs a job at a time, and the jobs claim their rows with SKIP LOCKED
+// leases so the work stays safe to share. Admins pause a job through any replica, so
+// the lock holder checks the stored pause flag before a scheduled run; the flag kept in
+// memory is only a cache of it.
 type Scheduler struct {
-	scheduler            gocron.Scheduler
-	paymentExpiryJob     *PaymentExpiryJob
-	orderAutoCompleteJob *OrderAutoCompleteJob
+	scheduler  gocron.Scheduler
+	jobRepo    domain.JobRepository
+	locker     LeaderLocker
+	instanceID string
+
+	mu   sync.Mutex
+	jobs []*scheduledJob
 }
 
-func NewScheduler(orderRepo domain.OrderRepository, productRepo domain.ProductRepository) (*Scheduler, error) {
-	s, err := gocron.NewScheduler()
+func NewScheduler(orderRepo domain.OrderRepository, outboxRepo domain.OutboxRepository, tx domain.Transactor, orderUsecase domain.OrderUsecase, jobRepo domain.JobRepository, locker LeaderLocker) (*Scheduler, error) {
+	s, err := gocron.NewScheduler(gocron.WithLocation(timeth.LoadLocation()))
 	if err != nil {
 		return nil, err
 	}
 
-	paymentExpiryJob := NewPaymentExpiryJob(orderRepo, productRepo)
-	orderAutoCompleteJob := NewOrderAutoCompleteJob(orderRepo)
+	instanceID := newInstanceID()
+
+	paymentExpiryJob := NewPaymentExpiryJob(orderRepo, outboxRepo, tx, instanceID)
+	orderAutoCompleteJob := NewOrderAutoCompleteJob(orderRepo, outboxRepo, tx, getAutoCompleteDays(), instanceID)
+	shipmentTrackingJob := NewShipmentTrackingSyncJob(orderUsecase)
 
 	return &Scheduler{
-		scheduler:            s,
-		paymentExpiryJob:     paymentExpiryJob,
-		orderAutoCompleteJob: orderAutoCompleteJob,
+		scheduler:  s,
+		jobRepo:    jobRepo,
+		locker:     locker,
+		instanceID: instanceID,
+		jobs: []*scheduledJob{
+			{
+				name:     entity.JobPaymentExpiry,
+				schedule: getSchedule("JOB_PAYMENT_EXPIRY_SCHEDULE", "10m"),
+				run:      paymentExpiryJob.ProcessExpiredPayments,
+			},
+			{
+				name:     entity.JobOrderAutoComplete,
+				schedule: getSchedule("JOB_ORDER_AUTO_COMPLETE_SCHEDULE", "0 0 * * *"),
+				run:      orderAutoCompleteJob.ProcessDeliveredOrders,
+			},
+			{
+				name:     entity.JobShipmentTrackingSync,
+				schedule: getSchedule("JOB_SHIPMENT_TRACKING_SYNC_SCHEDULE", "15m"),
+				run:      shipmentTrackingJob.SyncBookedShipments,
+			},
+		},
 	}, nil
 }
 
+// newInstanceID names this replica in job runs and row claims.
+func newInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+func getSchedule(key, defaultSchedule string) string {
+	if schedule := os.Getenv(key); schedule != "" {
+		return schedule
+	}
+	return defaultSchedule
+}
+
+func jobDefinition(schedule string) gocron.JobDefinition {
+	if d, err := time.ParseDuration(schedule); err == nil && d > 0 {
+		return gocron.DurationJob(d)
+	}
+	return gocron.CronJob(schedule, false)
+}
+
 func (s *Scheduler) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	_, err := s.scheduler.NewJob(
-		gocron.DurationJob(10*time.Minute),
-		gocron.NewTask(s.paymentExpiryJob.ProcessExpiredPayments),
-	)
+	states, err := s.jobRepo.ListJobStates(ctx)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to load job states: %w", err)
+	}
+	paused := make(map[string]bool, len(states))
+	for _, state := range states {
+		paused[state.JobName] = state.Paused
 	}
 
-	_, err = s.scheduler.NewJob(
-		gocron.DailyJob(1, gocron.NewAtTimes(gocron.NewAtTime(0, 0, 0))),
-		gocron.NewTask(s.orderAutoCompleteJob.ProcessDeliveredOrders),
-	)
-	if err != nil {
-		return err
+	for _, sj := range s.jobs {
+		sj.paused = paused[sj.name]
+
+		job, err := s.scheduler.NewJob(
+			jobDefinition(sj.schedule),
+			gocron.NewTask(s.runScheduled, sj),
+			gocron.WithName(sj.name),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return fmt.Errorf("invalid schedule %q for job %s: %w", sj.schedule, sj.name, err)
+		}
+		sj.job = job
+
+		log.Printf("[CRON] Scheduled %s (%s, paused: %t, instance: %s)", sj.name, sj.schedule, sj.paused, s.instanceID)
 	}
 
 	s.scheduler.Start()
@@ -56,3 +147,246 @@ func (s *Scheduler) Start() error {
 func (s *Scheduler) Stop() error {
 	return s.scheduler.Shutdown()
 }
+
+func (s *Scheduler) runScheduled(sj *scheduledJob) {
+	if !s.begin(sj) {
+		return
+	}
+
+	lease, err := s.acquire(sj)
+	if err != nil {
+		s.end(sj)
+		if !errors.Is(err, errLockHeld) {
+			log.Printf("[CRON] Error taking leader lock for %s: %v", sj.name, err)
+		}
+		return
+	}
+
+	if s.loadPaused(sj) {
+		lease.Release()
+		s.end(sj)
+		return
+	}
+
+	run, err := s.startRun(sj, entity.JobTriggerScheduled, nil)
+	if err != nil {
+		log.Printf("[CRON] Warning: Failed to record run of %s: %v", sj.name, err)
+	}
+	s.execute(sj, run, lease)
+}
+
+// Trigger runs the job now, in the background, even while it is paused.
+func (s *Scheduler) Trigger(ctx context.Context, name string, triggeredBy *uuid.UUID) (*entity.JobRun, error) {
+	sj, ok := s.find(name)
+	if !ok {
+		return nil, errmap.ErrJobNotFound
+	}
+
+	if !s.begin(sj) {
+		return nil, errmap.ErrJobAlreadyRunning
+	}
+
+	lease, err := s.acquire(sj)
+	if err != nil {
+		s.end(sj)
+		if errors.Is(err, errLockHeld) {
+			// Another instance is running it.
+			return nil, errmap.ErrJobAlreadyRunning
+		}
+		return nil, fmt.Errorf("failed to take leader lock: %w", err)
+	}
+
+	run, err := s.startRun(sj, entity.JobTriggerManual, triggeredBy)
+	if err != nil {
+		lease.Release()
+		s.end(sj)
+		return nil, fmt.Errorf("failed to record job run: %w", err)
+	}
+
+	go s.execute(sj, run, lease)
+
+	return run, nil
+}
+
+// begin marks the job as running on this instance.
+func (s *Scheduler) begin(sj *scheduledJob) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sj.running {
+		return false
+	}
+	sj.running = true
+	return true
+}
+
+// loadPaused reads the job's stored pause flag and refreshes the cached one with it.
+// The cached flag is used when the database cannot be read.
+func (s *Scheduler) loadPaused(sj *scheduledJob) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	paused, err := s.jobRepo.IsJobPaused(ctx, sj.name)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		log.Printf("[CRON] Warning: Failed to load pause state of %s, using the cached one: %v", sj.name, err)
+		return sj.paused
+	}
+	sj.paused = paused
+	return paused
+}
+
+func (s *Scheduler) end(sj *scheduledJob) {
+	s.mu.Lock()
+	sj.running = false
+	s.mu.Unlock()
+}
+
+func (s *Scheduler) acquire(sj *scheduledJob) (Lease, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.locker.TryLock(ctx, sj.name)
+}
+
+func (s *Scheduler) startRun(sj *scheduledJob, trigger string, triggeredBy *uuid.UUID) (*entity.JobRun, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	run := &entity.JobRun{
+		JobName:     sj.name,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		InstanceID:  s.instanceID,
+		Status:      entity.JobRunStatusRunning,
+		StartedAt:   timeth.Now(),
+	}
+	if err := s.jobRepo.CreateJobRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// execute runs the job under its leader lease and stores the result on run. The job's
+// context is cancelled if the lease is lost, so it stops claiming new work. run is nil
+// when the run could not be recorded; the job still runs so a history outage does not
+// stop order processing.
+func (s *Scheduler) execute(sj *scheduledJob, run *entity.JobRun, lease Lease) {
+	defer s.end(sj)
+	defer lease.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-lease.Lost():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	result := s.safeRun(ctx, sj)
+
+	select {
+	case <-lease.Lost():
+		if result.Err == nil {
+			result.Err = errors.New("leader lock lost during the run; unfinished rows are picked up once their claims expire")
+		}
+	default:
+	}
+
+	if run == nil {
+		return
+	}
+
+	finishedAt := timeth.Now()
+	run.FinishedAt = &finishedAt
+	run.Processed = result.Processed
+	run.Succeeded = result.Succeeded
+	run.Failed = result.Failed
+	run.ErrorSamples = result.Errors
+	switch {
+	case result.Err != nil:
+		run.Status = entity.JobRunStatusFailed
+		run.Error = result.Err.Error()
+	case result.Failed > 0:
+		run.Status = entity.JobRunStatusPartial
+	default:
+		run.Status = entity.JobRunStatusSucceeded
+	}
+
+	finishCtx, finishCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer finishCancel()
+	if err := s.jobRepo.FinishJobRun(finishCtx, run); err != nil {
+		log.Printf("[CRON] Warning: Failed to record result of %s run %s: %v", sj.name, run.ID, err)
+	}
+}
+
+func (s *Scheduler) safeRun(ctx context.Context, sj *scheduledJob) (result JobResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[CRON] Panic recovered in job %s: %v", sj.name, r)
+			result.Err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return sj.run(ctx)
+}
+
+func (s *Scheduler) find(name string) (*scheduledJob, bool) {
+	for _, sj := range s.jobs {
+		if sj.name == name {
+			return sj, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Scheduler) Jobs() []entity.JobInfo {
+	jobs := make([]entity.JobInfo, 0, len(s.jobs))
+	for _, sj := range s.jobs {
+		jobs = append(jobs, s.info(sj))
+	}
+	return jobs
+}
+
+func (s *Scheduler) Job(name string) (*entity.JobInfo, bool) {
+	sj, ok := s.find(name)
+	if !ok {
+		return nil, false
+	}
+	info := s.info(sj)
+	return &info, true
+}
+
+func (s *Scheduler) info(sj *scheduledJob) entity.JobInfo {
+	s.mu.Lock()
+	info := entity.JobInfo{
+		Name:     sj.name,
+		Schedule: sj.schedule,
+		Paused:   sj.paused,
+		Running:  sj.running,
+	}
+	s.mu.Unlock()
+
+	if sj.job != nil && !info.Paused {
+		if next, err := sj.job.NextRun(); err == nil && !next.IsZero() {
+			info.NextRunAt = &next
+		}
+	}
+	return info
+}
+
+// SetPaused updates the cached pause flag of the job, so this instance reports it
+// straight away. Scheduled runs go by the stored flag, which the caller persists.
+func (s *Scheduler) SetPaused(name string, paused bool) {
+	sj, ok := s.find(name)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	sj.paused = paused
+	s.mu.Unlock()
+}
@@ -0,0 +1,171 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/errmap"
+)
+
+type fakeLocker struct {
+	lease Lease
+	err   error
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (Lease, error) {
+	return l.lease, l.err
+}
+
+type fakeLease struct {
+	lost     chan struct{}
+	released chan struct{}
+}
+
+func newFakeLease() *fakeLease {
+	return &fakeLease{lost: make(chan struct{}), released: make(chan struct{})}
+}
+
+func (l *fakeLease) Lost() <-chan struct{} { return l.lost }
+func (l *fakeLease) Release()              { close(l.released) }
+
+func TestTrigger_LockHeldByAnotherInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobRepo := mock.NewMockJobRepository(ctrl)
+	ran := false
+	s := &Scheduler{
+		jobRepo: jobRepo,
+		locker:  &fakeLocker{err: errLockHeld},
+		jobs: []*scheduledJob{{
+			name: entity.JobPaymentExpiry,
+			run: func(ctx context.Context) JobResult {
+				ran = true
+				return JobResult{}
+			},
+		}},
+	}
+
+	run, err := s.Trigger(context.Background(), entity.JobPaymentExpiry, nil)
+
+	assert.ErrorIs(t, err, errmap.ErrJobAlreadyRunning)
+	assert.Nil(t, run)
+	assert.False(t, ran)
+	info, _ := s.Job(entity.JobPaymentExpiry)
+	assert.False(t, info.Running)
+}
+
+func TestTrigger_LostLeaseCancelsRunAndFailsIt(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lease := newFakeLease()
+	jobRepo := mock.NewMockJobRepository(ctrl)
+	s := &Scheduler{
+		jobRepo:    jobRepo,
+		locker:     &fakeLocker{lease: lease},
+		instanceID: "api-1",
+		jobs: []*scheduledJob{{
+			name: entity.JobOrderAutoComplete,
+			run: func(ctx context.Context) JobResult {
+				close(lease.lost)
+				<-ctx.Done()
+				return JobResult{Processed: 3, Succeeded: 1}
+			},
+		}},
+	}
+
+	jobRepo.EXPECT().CreateJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, run *entity.JobRun) error {
+		assert.Equal(t, entity.JobTriggerManual, run.Trigger)
+		assert.Equal(t, "api-1", run.InstanceID)
+		return nil
+	})
+	finished := make(chan *entity.JobRun, 1)
+	jobRepo.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, run *entity.JobRun) error {
+		finished <- run
+		return nil
+	})
+
+	_, err := s.Trigger(context.Background(), entity.JobOrderAutoComplete, nil)
+	assert.NoError(t, err)
+
+	select {
+	case run := <-finished:
+		assert.Equal(t, entity.JobRunStatusFailed, run.Status)
+		assert.Equal(t, 3, run.Processed)
+		assert.Contains(t, run.Error, "leader lock lost")
+	case <-time.After(2 * time.Second):
+		t.Fatal("run was not finished")
+	}
+
+	select {
+	case <-lease.released:
+	case <-time.After(2 * time.Second):
+		t.Fatal("lease was not released")
+	}
+}
+
+func TestRunScheduled_SkipsJobPausedThroughAnotherInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lease := newFakeLease()
+	jobRepo := mock.NewMockJobRepository(ctrl)
+	ran := false
+	sj := &scheduledJob{
+		name: entity.JobPaymentExpiry,
+		run: func(ctx context.Context) JobResult {
+			ran = true
+			return JobResult{}
+		},
+	}
+	s := &Scheduler{jobRepo: jobRepo, locker: &fakeLocker{lease: lease}, jobs: []*scheduledJob{sj}}
+
+	jobRepo.EXPECT().IsJobPaused(gomock.Any(), entity.JobPaymentExpiry).Return(true, nil)
+	jobRepo.EXPECT().CreateJobRun(gomock.Any(), gomock.Any()).Times(0)
+
+	s.runScheduled(sj)
+
+	assert.False(t, ran)
+	info, _ := s.Job(entity.JobPaymentExpiry)
+	assert.True(t, info.Paused)
+	assert.False(t, info.Running)
+	select {
+	case <-lease.released:
+	default:
+		t.Fatal("lease was not released")
+	}
+}
+
+func TestRunScheduled_RunsJobResumedThroughAnotherInstance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jobRepo := mock.NewMockJobRepository(ctrl)
+	ran := false
+	sj := &scheduledJob{
+		name:   entity.JobPaymentExpiry,
+		paused: true,
+		run: func(ctx context.Context) JobResult {
+			ran = true
+			return JobResult{}
+		},
+	}
+	s := &Scheduler{jobRepo: jobRepo, locker: &fakeLocker{lease: newFakeLease()}, jobs: []*scheduledJob{sj}}
+
+	jobRepo.EXPECT().IsJobPaused(gomock.Any(), entity.JobPaymentExpiry).Return(false, nil)
+	jobRepo.EXPECT().CreateJobRun(gomock.Any(), gomock.Any()).Return(nil)
+	jobRepo.EXPECT().FinishJobRun(gomock.Any(), gomock.Any()).Return(nil)
+
+	s.runScheduled(sj)
+
+	assert.True(t, ran)
+	info, _ := s.Job(entity.JobPaymentExpiry)
+	assert.False(t, info.Paused)
+}
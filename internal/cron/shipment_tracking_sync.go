@@ -0,0 +1,38 @@
+package cron
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/internal/timeth"
+)
+
+type ShipmentTrackingSyncJob struct {
+	orderUsecase domain.OrderUsecase
+}
+
+func NewShipmentTrackingSyncJob(orderUsecase domain.OrderUsecase) *ShipmentTrackingSyncJob {
+	return &ShipmentTrackingSyncJob{
+		orderUsecase: orderUsecase,
+	}
+}
+
+func (j *ShipmentTrackingSyncJob) SyncBookedShipments(ctx context.Context) JobResult {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	startTime := timeth.Now()
+
+	updated, err := j.orderUsecase.SyncShipmentTracking(ctx)
+	if err != nil {
+		log.Printf("[CRON] Error syncing shipment tracking: %v", err)
+		return JobResult{Err: err}
+	}
+
+	duration := time.Since(startTime)
+	log.Printf("[CRON] Shipment tracking sync completed in %v - Updated: %d", duration, updated)
+
+	return JobResult{Processed: updated, Succeeded: updated}
+}
@@ -0,0 +1,36 @@
+package dbtx
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"ecommerce-go-api/domain"
+)
+
+type txKey struct{}
+
+// From returns the transaction carried by ctx, or db when there is none. Repositories
+// use it so their writes join a transaction started by a usecase.
+func From(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) domain.Transactor {
+	return &transactor{db: db}
+}
+
+// WithinTransaction runs fn in a transaction carried by the ctx passed to it. Nested
+// calls run in a savepoint of the outer transaction.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return From(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -1,6 +1,9 @@
 package errmap
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrEmailAlreadyExists   = errors.New("email already exists")
@@ -9,4 +12,48 @@ var (
 	ErrInvalidToken         = errors.New("invalid token")
 	ErrExpiredToken         = errors.New("token has expired")
 	ErrInvalidSigningMethod = errors.New("invalid signing method")
+
+	ErrInvalidUserToken        = errors.New("link is invalid or has expired")
+	ErrEmailAlreadyVerified    = errors.New("email is already verified")
+	ErrEmailNotVerified        = errors.New("email address is not verified")
+	ErrVerificationEmailRecent = errors.New("a verification email was sent recently, please try again later")
+	ErrIncorrectPassword       = errors.New("current password is incorrect")
+
+	ErrSessionNotFound    = errors.New("session not found")
+	ErrRefreshTokenReused = errors.New("refresh token has already been used")
+
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts, please try again later")
+
+	ErrInvalidTwoFactorToken     = errors.New("two-factor login has expired, please log in again")
+	ErrInvalidTwoFactorCode      = errors.New("invalid two-factor code")
+	ErrTwoFactorAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetUp         = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorEnforced         = errors.New("two-factor authentication is required for this account and cannot be disabled")
+	ErrTwoFactorSetupRequired    = errors.New("two-factor authentication is required for this account, please set it up first")
+	ErrTwoFactorReverifyRequired = errors.New("please confirm this action with your two-factor code")
+
+	ErrOAuthProviderNotFound = errors.New("login provider not found")
+	ErrInvalidOAuthState     = errors.New("login has expired, please try again")
+	ErrOAuthLoginFailed      = errors.New("could not sign in with the provider")
+	ErrOAuthEmailRequired    = errors.New("the provider did not share an email address")
+	ErrOAuthEmailNotVerified = errors.New("the provider has not verified this email address")
+	ErrIdentityNotFound      = errors.New("linked account not found")
+	ErrLastSignInMethod      = errors.New("set a password or link another account before unlinking this one")
 )
+
+// LoginBlockedError is returned by Login while the account or IP address has to wait
+// after its failed attempts. It wraps ErrTooManyLoginAttempts.
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+	// Locked is true when the wait is a lockout rather than a progressive delay.
+	Locked bool
+}
+
+func (e *LoginBlockedError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginBlockedError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
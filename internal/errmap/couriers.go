@@ -3,5 +3,9 @@ package errmap
 import "errors"
 
 var (
-	ErrFailedToGetCouriers = errors.New("failed to get couriers")
+	ErrFailedToGetCouriers  = errors.New("failed to get couriers")
+	ErrDuplicateTransitTime = errors.New("each origin and destination province pair can only have one transit time")
+	ErrInvalidHolidayDate   = errors.New("invalid holiday date, expected YYYY-MM-DD")
+	ErrHolidayNotFound      = errors.New("holiday not found")
+	ErrHolidayExists        = errors.New("a holiday already exists on this date")
 )
@@ -0,0 +1,8 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobAlreadyRunning = errors.New("job is already running")
+)
@@ -0,0 +1,8 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrNotificationNotFound             = errors.New("notification not found")
+	ErrNotificationEventTypeUnsupported = errors.New("unsupported notification event type")
+)
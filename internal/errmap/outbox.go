@@ -0,0 +1,8 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrOutboxEventNotFound = errors.New("outbox event not found")
+	ErrOutboxEventNotDead  = errors.New("only dead events can be retried")
+)
@@ -0,0 +1,21 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrInvalidRefundID           = errors.New("invalid refund id")
+	ErrRefundNotFound            = errors.New("refund not found")
+	ErrRefundNotPending          = errors.New("refund is not in pending status")
+	ErrRefundNotApproved         = errors.New("refund is not in approved status")
+	ErrRefundBankAccountRequired = errors.New("bank account is required before completing a bank transfer refund")
+	ErrFailedToListRefunds       = errors.New("failed to list refunds")
+	ErrRefundAlreadyOpen         = errors.New("an open refund already exists for this order")
+	ErrRefundNotEligible         = errors.New("order is not eligible for a refund request")
+	ErrRefundAmountExceedsTotal  = errors.New("refund amount exceeds what is left to refund of the order")
+	ErrRefundNotCounterOffered   = errors.New("refund has no pending counter-offer")
+	ErrCounterOfferNotLower      = errors.New("counter-offer must be lower than the requested amount")
+	ErrRefundCannotEscalate      = errors.New("refund cannot be escalated")
+	ErrRefundNotEscalated        = errors.New("refund is not escalated")
+	ErrRefundNotBuyerInitiated   = errors.New("refund was not requested by the buyer")
+	ErrRefundStatusChanged       = errors.New("refund status has changed, reload it and try again")
+)
@@ -0,0 +1,22 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrShipmentNotFound        = errors.New("shipment not found")
+	ErrCourierNotFound         = errors.New("courier not found")
+	ErrInvalidShipmentStatus   = errors.New("invalid shipment status")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrInvalidCourierID        = errors.New("invalid courier id")
+	ErrInvalidShipmentID       = errors.New("invalid shipment id")
+	ErrTrackingNoRequired      = errors.New("tracking number is required for this courier")
+	ErrCourierBookingFailed    = errors.New("failed to book courier pickup")
+	ErrCourierCannotCancel     = errors.New("courier booking can no longer be cancelled")
+	ErrCourierTrackingNotFound = errors.New("tracking number not found at courier")
+	ErrLabelUnavailable        = errors.New("shipping label is not available for cancelled orders")
+	ErrInvalidShipmentItems    = errors.New("shipment items must be unshipped items of this order")
+	ErrAllItemsShipped         = errors.New("all items in this order have already been shipped")
+	ErrOrderNotFullyShipped    = errors.New("order has items that have not been shipped")
+	ErrReattemptNotAllowed     = errors.New("a reattempt can only be scheduled after a failed delivery")
+	ErrInvalidReattemptDate    = errors.New("reattempt date must be within the next 7 days")
+)
@@ -0,0 +1,14 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrShopMemberNotFound          = errors.New("shop member not found")
+	ErrShopOwnerImmutable          = errors.New("the shop owner cannot be changed or removed")
+	ErrAlreadyShopMember           = errors.New("user is already a member of a shop")
+	ErrShopMemberLimitReached      = errors.New("shop has reached the maximum number of members")
+	ErrShopInvitationNotFound      = errors.New("shop invitation not found")
+	ErrShopInvitationInvalid       = errors.New("invitation is invalid or has expired")
+	ErrShopInvitationEmailMismatch = errors.New("invitation was sent to another email address")
+	ErrInvalidFinanceRange         = errors.New("invalid date range, expected from <= to within 366 days")
+)
@@ -0,0 +1,12 @@
+package errmap
+
+import "errors"
+
+var (
+	ErrWebhookNotFound             = errors.New("webhook not found")
+	ErrWebhookLimitReached         = errors.New("shop has reached the maximum number of webhooks")
+	ErrWebhookEventTypeUnsupported = errors.New("unsupported webhook event type")
+	ErrWebhookURLInsecure          = errors.New("webhook url must use https")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryPending      = errors.New("webhook delivery is still pending")
+)
@@ -0,0 +1,179 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"ecommerce-go-api/config"
+)
+
+// prefix marks a value as ciphertext. Values without it are treated as legacy plaintext
+// so rows written before encryption was enabled stay readable until they are re-encrypted.
+const prefix = "enc:"
+
+var (
+	ErrInvalidKeySpec    = errors.New("invalid field encryption key spec")
+	ErrUnknownKey        = errors.New("unknown field encryption key")
+	ErrMalformedCipher   = errors.New("malformed encrypted value")
+	ErrDecryptionFailure = errors.New("failed to decrypt value")
+)
+
+// KeyRing holds every AES-256-GCM key that may still be needed to decrypt stored values.
+// New values are always encrypted with the active key.
+type KeyRing struct {
+	activeID string
+	aeads    map[string]cipher.AEAD
+}
+
+// NewKeyRing parses a spec of the form "id1:base64key,id2:base64key" where every key
+// is 32 bytes, and selects activeID as the key used for encryption.
+func NewKeyRing(spec string, activeID string) (*KeyRing, error) {
+	kr := &KeyRing{activeID: activeID, aeads: make(map[string]cipher.AEAD)}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: entry %q must be id:base64key", ErrInvalidKeySpec, entry)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q is not valid base64", ErrInvalidKeySpec, id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%w: key %q must be 32 bytes, got %d", ErrInvalidKeySpec, id, len(key))
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		kr.aeads[id] = aead
+	}
+
+	if _, ok := kr.aeads[activeID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q is not in the key ring", ErrInvalidKeySpec, activeID)
+	}
+
+	return kr, nil
+}
+
+// ActiveKeyID returns the id of the key used for new encryptions.
+func (kr *KeyRing) ActiveKeyID() string {
+	return kr.activeID
+}
+
+// Encrypt encrypts plaintext with the active key. Empty strings are stored as-is.
+func (kr *KeyRing) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	aead := kr.aeads[kr.activeID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(kr.activeID))
+	return prefix + kr.activeID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt with any key in the ring.
+// Legacy plaintext values are returned unchanged.
+func (kr *KeyRing) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", ErrMalformedCipher
+	}
+
+	aead, ok := kr.aeads[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedCipher
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", ErrDecryptionFailure
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or encrypted with a key
+// other than the active one.
+func (kr *KeyRing) NeedsRotation(value string) bool {
+	if value == "" {
+		return false
+	}
+	if !IsEncrypted(value) {
+		return true
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return id != kr.activeID
+}
+
+// IsEncrypted reports whether value was produced by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// Mask hides all but the last visible characters of value, e.g. "******7890".
+func Mask(value string, visible int) string {
+	runes := []rune(value)
+	if len(runes) <= visible {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-visible) + string(runes[len(runes)-visible:])
+}
+
+var (
+	defaultRing *KeyRing
+	defaultOnce sync.Once
+)
+
+// Default returns the key ring configured by config.FIELD_ENCRYPTION_KEYS and
+// config.FIELD_ENCRYPTION_ACTIVE_KEY.
+func Default() *KeyRing {
+	defaultOnce.Do(func() {
+		kr, err := NewKeyRing(config.FIELD_ENCRYPTION_KEYS, config.FIELD_ENCRYPTION_ACTIVE_KEY)
+		if err != nil {
+			log.Fatalf("FATAL: field encryption is not configured: %v", err)
+		}
+		defaultRing = kr
+	})
+	return defaultRing
+}
+
+func Encrypt(plaintext string) (string, error) {
+	return Default().Encrypt(plaintext)
+}
+
+func Decrypt(value string) (string, error) {
+	return Default().Decrypt(value)
+}
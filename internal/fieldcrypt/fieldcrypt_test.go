@@ -0,0 +1,96 @@
+package fieldcrypt
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"ecommerce-go-api/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestKeyRing_RotateKeepsOldValuesReadable(t *testing.T) {
+	oldRing, err := NewKeyRing("k1:"+testKey('a'), "k1")
+	require.NoError(t, err)
+
+	stored, err := oldRing.Encrypt("1234567890")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, "enc:k1:"))
+	assert.NotContains(t, stored, "1234567890")
+
+	newRing, err := NewKeyRing("k1:"+testKey('a')+",k2:"+testKey('b'), "k2")
+	require.NoError(t, err)
+
+	assert.True(t, newRing.NeedsRotation(stored))
+	plaintext, err := newRing.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", plaintext)
+
+	rotated, err := newRing.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.False(t, newRing.NeedsRotation(rotated))
+
+	_, err = oldRing.Decrypt(rotated)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyRing_LegacyPlaintextAndMask(t *testing.T) {
+	kr, err := NewKeyRing("k1:"+testKey('a'), "k1")
+	require.NoError(t, err)
+
+	plaintext, err := kr.Decrypt("1234567890")
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", plaintext)
+	assert.True(t, kr.NeedsRotation("1234567890"))
+
+	assert.Equal(t, "******7890", Mask("1234567890", 4))
+	assert.Equal(t, "***", Mask("123", 4))
+
+	_, err = NewKeyRing("k1:"+testKey('a'), "k2")
+	assert.ErrorIs(t, err, ErrInvalidKeySpec)
+}
+
+func TestSerializer_EncryptsOnWriteAndDecryptsOnRead(t *testing.T) {
+	config.FIELD_ENCRYPTION_KEYS = "k1:" + testKey('a')
+	config.FIELD_ENCRYPTION_ACTIVE_KEY = "k1"
+
+	type contact struct {
+		ID    uint32
+		Phone string `gorm:"serializer:fieldcrypt"`
+	}
+	s, err := schema.Parse(&contact{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := s.LookUpField("Phone")
+	ctx := context.Background()
+
+	written := reflect.ValueOf(&contact{Phone: "0900000000"}).Elem()
+	fieldValue, _ := field.ValueOf(ctx, written)
+	stored, err := fieldValue.(driver.Valuer).Value()
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(stored.(string)))
+	assert.NotContains(t, stored, "0900000000")
+
+	// scan mimics GORM reading a column into the field.
+	scan := func(dbValue interface{}) string {
+		read := reflect.ValueOf(&contact{}).Elem()
+		scanned := field.NewValuePool.Get()
+		require.NoError(t, scanned.(sql.Scanner).Scan(dbValue))
+		require.NoError(t, field.Set(ctx, read, scanned))
+		return read.Interface().(contact).Phone
+	}
+	assert.Equal(t, "0900000000", scan(stored))
+	assert.Equal(t, "0911111111", scan("0911111111"), "legacy plaintext stays readable")
+	assert.Equal(t, "", scan(nil))
+}
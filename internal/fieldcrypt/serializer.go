@@ -0,0 +1,48 @@
+package fieldcrypt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("fieldcrypt", Serializer{})
+}
+
+// Serializer encrypts string fields tagged `serializer:fieldcrypt` with the default key
+// ring when GORM writes them, and decrypts them when it reads them, including through
+// preloads. Updates with a map bypass serializers, so encrypt those values with Encrypt.
+type Serializer struct{}
+
+// Scan implements schema.SerializerInterface.
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("fieldcrypt: unsupported value type %T for %s", dbValue, field.Name)
+	}
+
+	plaintext, err := Decrypt(value)
+	if err != nil {
+		return fmt.Errorf("fieldcrypt: failed to decrypt %s: %w", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+// Value implements schema.SerializerValuerInterface.
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("fieldcrypt: %s must be a string, got %T", field.Name, fieldValue)
+	}
+	return Encrypt(plaintext)
+}
@@ -1,7 +1,6 @@
 package jwt
 
 import (
-	"ecommerce-go-api/internal/errmap"
 	"ecommerce-go-api/internal/timeth"
 	"log"
 	"os"
@@ -11,79 +10,73 @@ import (
 	"github.com/google/uuid"
 )
 
+// Two-factor states of the account an access token was issued to.
+const (
+	TwoFactorEnabled = "enabled"
+	// TwoFactorSetupRequired means an admin requires two-factor authentication the user
+	// has not set up.
+	TwoFactorSetupRequired = "setup_required"
+)
+
 type Claims struct {
 	UserID uuid.UUID `json:"userId"`
-	Role   string    `json:"role"`
+	// Role is the account's main role. Roles lists every role it has, e.g. shop staff
+	// who also buy as users; tokens issued before it existed only carry Role.
+	Role  string   `json:"role"`
+	Roles []string `json:"roles,omitempty"`
+	// SessionID is the session the access token was issued for.
+	SessionID uuid.UUID `json:"sid"`
+	// TwoFactor is the account's two-factor state when the token was issued, empty when
+	// it has none.
+	TwoFactor string `json:"tfs,omitempty"`
+	// TwoFactorAt is when the user last entered a two-factor code, at login or by
+	// re-verifying. Sensitive actions require it to be recent.
+	TwoFactorAt *jwt.NumericDate `json:"tfa,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateAccessToken(userID uuid.UUID, role string) (string, error) {
-	secret := getJWTSecret()
+// GenerateAccessToken issues a token with the roles, the main role first.
+func GenerateAccessToken(userID uuid.UUID, roles []string, sessionID uuid.UUID, twoFactor string, twoFactorAt *time.Time) (string, error) {
 	duration := getAccessTokenDuration()
 
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      roles[0],
+		Roles:     roles,
+		SessionID: sessionID,
+		TwoFactor: twoFactor,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(timeth.Now().Add(duration)),
 			IssuedAt:  jwt.NewNumericDate(timeth.Now()),
 			NotBefore: jwt.NewNumericDate(timeth.Now()),
 		},
 	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
-}
-
-func GenerateRefreshToken(userID uuid.UUID) (string, error) {
-	secret := getJWTSecret()
-	duration := getRefreshTokenDuration()
-
-	claims := Claims{
-		UserID: userID,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(timeth.Now().Add(duration)),
-			IssuedAt:  jwt.NewNumericDate(timeth.Now()),
-			NotBefore: jwt.NewNumericDate(timeth.Now()),
-		},
+	if twoFactorAt != nil {
+		claims.TwoFactorAt = jwt.NewNumericDate(*twoFactorAt)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
-}
-
-func ValidateToken(tokenString string) (*Claims, error) {
-	secret := getJWTSecret()
-
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errmap.ErrInvalidToken
-		}
-		return []byte(secret), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
-		return nil, errmap.ErrInvalidToken
+	kr := Default()
+	if kr == nil {
+		return "", ErrNotConfigured
 	}
+	return kr.Sign(claims)
+}
 
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(timeth.Now()) {
-		return nil, errmap.ErrExpiredToken
+// AllRoles returns the roles of the token, falling back to Role for tokens without Roles.
+func (c *Claims) AllRoles() []string {
+	if len(c.Roles) == 0 {
+		return []string{c.Role}
 	}
-
-	return claims, nil
+	return c.Roles
 }
 
-func getJWTSecret() string {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Fatal("FATAL: JWT_SECRET environment variable is not set")
+// ValidateToken verifies the token against the key named by its "kid" header.
+func ValidateToken(tokenString string) (*Claims, error) {
+	kr := Default()
+	if kr == nil {
+		return nil, ErrNotConfigured
 	}
-	return secret
+	return kr.Parse(tokenString)
 }
 
 func getAccessTokenDuration() time.Duration {
@@ -107,7 +100,9 @@ func getAccessTokenDuration() time.Duration {
 	return d
 }
 
-func getRefreshTokenDuration() time.Duration {
+// RefreshTokenDuration is how long a refresh token stays valid. Sessions that are not
+// refreshed within it end.
+func RefreshTokenDuration() time.Duration {
 	const defaultRefreshDuration = 7 * 24 * time.Hour // 7 days
 
 	durationStr := os.Getenv("JWT_REFRESH_TOKEN_DURATION")
@@ -0,0 +1,251 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidKeySpec = errors.New("invalid JWT signing key spec")
+	ErrUnknownKey     = errors.New("unknown JWT signing key")
+	ErrNotConfigured  = errors.New("JWT signing is not configured")
+)
+
+// minRSABits is the smallest RSA key accepted for RS256.
+const minRSABits = 2048
+
+type key struct {
+	id     string
+	method jwt.SigningMethod
+	// signer is nil for keys that are only kept to verify tokens they signed earlier.
+	signer crypto.Signer
+	public crypto.PublicKey
+}
+
+// KeyRing holds every key tokens may still be signed with. New tokens are signed with
+// the active key and carry its id in the "kid" header; the other keys keep verifying
+// the tokens they signed until those expire.
+type KeyRing struct {
+	activeID string
+	keys     map[string]*key
+}
+
+// NewKeyRing parses a spec of the form "id1:base64pem,id2:base64pem" and selects activeID
+// as the key new tokens are signed with. Each PEM is an RSA (RS256) or Ed25519 (EdDSA)
+// private key, or a public key for a retired key that only verifies. The active key
+// must be a private key.
+func NewKeyRing(spec string, activeID string) (*KeyRing, error) {
+	kr := &KeyRing{activeID: activeID, keys: make(map[string]*key)}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("%w: entry %q must be id:base64pem", ErrInvalidKeySpec, entry)
+		}
+		if _, ok := kr.keys[id]; ok {
+			return nil, fmt.Errorf("%w: key %q is listed twice", ErrInvalidKeySpec, id)
+		}
+
+		pemBytes, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q is not valid base64", ErrInvalidKeySpec, id)
+		}
+
+		k, err := parseKey(id, pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %q: %v", ErrInvalidKeySpec, id, err)
+		}
+		kr.keys[id] = k
+	}
+
+	active, ok := kr.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("%w: active key %q is not in the key ring", ErrInvalidKeySpec, activeID)
+	}
+	if active.signer == nil {
+		return nil, fmt.Errorf("%w: active key %q is a public key and cannot sign", ErrInvalidKeySpec, activeID)
+	}
+
+	return kr, nil
+}
+
+func parseKey(id string, pemBytes []byte) (*key, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("not a PEM block")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	k := &key{id: id}
+	switch v := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.method, k.signer, k.public = jwt.SigningMethodRS256, v, &v.PublicKey
+	case *rsa.PublicKey:
+		k.method, k.public = jwt.SigningMethodRS256, v
+	case ed25519.PrivateKey:
+		k.method, k.signer, k.public = jwt.SigningMethodEdDSA, v, v.Public()
+	case ed25519.PublicKey:
+		k.method, k.public = jwt.SigningMethodEdDSA, v
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", parsed)
+	}
+
+	if rsaKey, ok := k.public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < minRSABits {
+		return nil, fmt.Errorf("RSA key must be at least %d bits, got %d", minRSABits, rsaKey.N.BitLen())
+	}
+
+	return k, nil
+}
+
+// ActiveKeyID returns the id of the key new tokens are signed with.
+func (kr *KeyRing) ActiveKeyID() string {
+	return kr.activeID
+}
+
+// Sign signs the claims with the active key.
+func (kr *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	active := kr.keys[kr.activeID]
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.id
+	return token.SignedString(active.signer)
+}
+
+// Parse verifies the token's signature with the ring's key of the same kid and returns
+// its claims.
+func (kr *KeyRing) Parse(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, kr.keyFunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithTimeFunc(timeth.Now),
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errmap.ErrExpiredToken
+		}
+		if errors.Is(err, jwt.ErrTokenSignatureInvalid) || errors.Is(err, errmap.ErrInvalidSigningMethod) {
+			return nil, errmap.ErrInvalidSigningMethod
+		}
+		return nil, errmap.ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, errmap.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// keyFunc picks the key named by the token's "kid" header and checks the token was
+// signed with that key's algorithm.
+func (kr *KeyRing) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := kr.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("%w: key %q signs %s, token uses %s", errmap.ErrInvalidSigningMethod, k.id, k.method.Alg(), token.Method.Alg())
+	}
+	return k.public, nil
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the key set served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public part of every key in the ring, sorted by id.
+func (kr *KeyRing) JWKS() JWKS {
+	ids := make([]string, 0, len(kr.keys))
+	for id := range kr.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKS{Keys: make([]JWK, 0, len(ids))}
+	for _, id := range ids {
+		k := kr.keys[id]
+		jwk := JWK{Use: "sig", Alg: k.method.Alg(), Kid: id}
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+var defaultRing atomic.Pointer[KeyRing]
+
+// Init builds the key ring from the configured signing keys and makes it the
+// one Default returns. It is called once at startup.
+func Init(spec, activeID string) error {
+	kr, err := NewKeyRing(spec, activeID)
+	if err != nil {
+		return err
+	}
+	defaultRing.Store(kr)
+	return nil
+}
+
+// Default returns the key ring set by Init, or nil if Init has not been called.
+func Default() *KeyRing {
+	return defaultRing.Load()
+}
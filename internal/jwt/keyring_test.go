@@ -0,0 +1,144 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ecommerce-go-api/internal/errmap"
+	"ecommerce-go-api/internal/timeth"
+)
+
+func pemKey(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	return base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func ed25519Key(t *testing.T) (string, ed25519.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	return pemKey(t, "PRIVATE KEY", der), pub
+}
+
+func rsaKey(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return pemKey(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv)), priv
+}
+
+func testClaims() Claims {
+	return Claims{
+		UserID: uuid.New(),
+		Role:   "USER",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(timeth.Now().Add(time.Minute)),
+		},
+	}
+}
+
+func TestKeyRing_RotateKeepsOldTokensValid(t *testing.T) {
+	oldKey, oldPublic := ed25519Key(t)
+	newKey, _ := rsaKey(t)
+
+	oldRing, err := NewKeyRing("k1:"+oldKey, "k1")
+	require.NoError(t, err)
+	claims := testClaims()
+	oldToken, err := oldRing.Sign(claims)
+	require.NoError(t, err)
+
+	// k1 is retired: only its public key is kept to verify the tokens it signed.
+	publicDER, err := x509.MarshalPKIXPublicKey(oldPublic)
+	require.NoError(t, err)
+	newRing, err := NewKeyRing("k1:"+pemKey(t, "PUBLIC KEY", publicDER)+",k2:"+newKey, "k2")
+	require.NoError(t, err)
+
+	parsed, err := newRing.Parse(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, claims.UserID, parsed.UserID)
+
+	newToken, err := newRing.Sign(testClaims())
+	require.NoError(t, err)
+	header, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", header.Header["kid"])
+	assert.Equal(t, "RS256", header.Method.Alg())
+
+	_, err = oldRing.Parse(newToken)
+	assert.ErrorIs(t, err, errmap.ErrInvalidToken)
+}
+
+func TestKeyRing_RejectsHMACSignedWithPublicKey(t *testing.T) {
+	spec, priv := rsaKey(t)
+	kr, err := NewKeyRing("k1:"+spec, "k1")
+	require.NoError(t, err)
+
+	// The classic algorithm confusion attack: HS256 keyed with the published RSA key.
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	token.Header["kid"] = "k1"
+	forged, err := token.SignedString(x509.MarshalPKCS1PublicKey(&priv.PublicKey))
+	require.NoError(t, err)
+
+	_, err = kr.Parse(forged)
+	assert.Error(t, err)
+}
+
+func TestKeyRing_ExpiredToken(t *testing.T) {
+	spec, _ := ed25519Key(t)
+	kr, err := NewKeyRing("k1:"+spec, "k1")
+	require.NoError(t, err)
+
+	claims := testClaims()
+	claims.ExpiresAt = jwt.NewNumericDate(timeth.Now().Add(-time.Minute))
+	token, err := kr.Sign(claims)
+	require.NoError(t, err)
+
+	_, err = kr.Parse(token)
+	assert.ErrorIs(t, err, errmap.ErrExpiredToken)
+}
+
+func TestKeyRing_JWKSVerifiesTokens(t *testing.T) {
+	edSpec, _ := ed25519Key(t)
+	rsaSpec, _ := rsaKey(t)
+	kr, err := NewKeyRing("ed:"+edSpec+",rs:"+rsaSpec, "ed")
+	require.NoError(t, err)
+
+	set := kr.JWKS()
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, JWK{Kty: "OKP", Use: "sig", Alg: "EdDSA", Kid: "ed", Crv: "Ed25519", X: set.Keys[0].X}, set.Keys[0])
+	assert.Equal(t, "RSA", set.Keys[1].Kty)
+	assert.Equal(t, "AQAB", set.Keys[1].E)
+
+	// A service holding only the JWKS can verify tokens.
+	x, err := base64.RawURLEncoding.DecodeString(set.Keys[0].X)
+	require.NoError(t, err)
+	token, err := kr.Sign(testClaims())
+	require.NoError(t, err)
+	_, err = jwt.Parse(token, func(*jwt.Token) (any, error) { return ed25519.PublicKey(x), nil })
+	assert.NoError(t, err)
+}
+
+func TestNewKeyRing_ActiveKeyMustBePrivate(t *testing.T) {
+	_, public := ed25519Key(t)
+	der, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+
+	_, err = NewKeyRing("k1:"+pemKey(t, "PUBLIC KEY", der), "k1")
+	assert.ErrorIs(t, err, ErrInvalidKeySpec)
+
+	_, err = NewKeyRing("", "k1")
+	assert.ErrorIs(t, err, ErrInvalidKeySpec)
+}
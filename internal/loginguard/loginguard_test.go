@@ -0,0 +1,126 @@
+package loginguard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ecommerce-go-api/entity"
+)
+
+var testPolicy = Policy{
+	FreeFailures: 3,
+	BaseDelay:    time.Second,
+	MaxDelay:     30 * time.Second,
+	LockAfter:    10,
+	LockDuration: 15 * time.Minute,
+}
+
+func TestPolicy_Wait(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		failures   int
+		lastFailed time.Duration
+		wantWait   time.Duration
+		wantLocked bool
+	}{
+		{name: "no failures", failures: 0},
+		{name: "free failures", failures: 3},
+		{name: "first delay", failures: 4, wantWait: time.Second},
+		{name: "delay doubles", failures: 6, wantWait: 4 * time.Second},
+		{name: "delay is capped", failures: 9, wantWait: 30 * time.Second},
+		{name: "delay has passed", failures: 6, lastFailed: 5 * time.Second},
+		{name: "delay partly passed", failures: 6, lastFailed: time.Second, wantWait: 3 * time.Second},
+		{name: "locked", failures: 10, lastFailed: time.Minute, wantWait: 14 * time.Minute, wantLocked: true},
+		{name: "lock expired", failures: 12, lastFailed: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			throttle := entity.LoginThrottle{Failures: tt.failures, LastFailureAt: now.Add(-tt.lastFailed)}
+
+			wait, locked := testPolicy.Wait(throttle, now)
+
+			assert.Equal(t, tt.wantWait, wait)
+			assert.Equal(t, tt.wantLocked, locked)
+		})
+	}
+}
+
+func TestMemoryLimiter_CountsUntilWindowPasses(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemoryLimiter()
+	key := AccountKey(" Somchai@Example.com ")
+	assert.Equal(t, "account:somchai@example.com", key)
+
+	for i := 0; i < 3; i++ {
+		reservation, err := limiter.Reserve(ctx, key, 50*time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, i, reservation.Previous.Failures)
+	}
+
+	throttle, err := limiter.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, throttle.Failures)
+
+	time.Sleep(60 * time.Millisecond)
+
+	throttle, err = limiter.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Zero(t, throttle.Failures)
+
+	reservation, err := limiter.Reserve(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Zero(t, reservation.Previous.Failures)
+}
+
+func TestMemoryLimiter_ReleaseTakesBackTheAttempt(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemoryLimiter()
+	key := IPKey("203.0.113.7")
+
+	failed, err := limiter.Reserve(ctx, key, time.Minute)
+	require.NoError(t, err)
+
+	first, err := limiter.Reserve(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, first.Previous.Failures)
+	assert.Equal(t, failed.ReservedAt, first.Previous.LastFailureAt)
+
+	// A concurrent attempt sees the first one while it is being checked.
+	second, err := limiter.Reserve(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, second.Previous.Failures)
+
+	require.NoError(t, limiter.Release(ctx, second, time.Minute))
+	require.NoError(t, limiter.Release(ctx, first, time.Minute))
+
+	throttle, err := limiter.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, throttle.Failures)
+	assert.True(t, throttle.LastFailureAt.Equal(failed.ReservedAt))
+}
+
+func TestMemoryLimiter_Reset(t *testing.T) {
+	ctx := context.Background()
+	limiter := NewMemoryLimiter()
+
+	_, err := limiter.Reserve(ctx, IPKey("203.0.113.7"), time.Minute)
+	require.NoError(t, err)
+	_, err = limiter.Reserve(ctx, IPKey("198.51.100.1"), time.Minute)
+	require.NoError(t, err)
+
+	require.NoError(t, limiter.Reset(ctx, IPKey("203.0.113.7")))
+
+	throttle, err := limiter.Get(ctx, IPKey("203.0.113.7"))
+	require.NoError(t, err)
+	assert.Zero(t, throttle.Failures)
+	throttle, err = limiter.Get(ctx, IPKey("198.51.100.1"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, throttle.Failures)
+}
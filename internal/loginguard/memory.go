@@ -0,0 +1,102 @@
+package loginguard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+// sweepInterval is how often expired counts are deleted.
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	throttle  entity.LoginThrottle
+	expiresAt time.Time
+}
+
+type memoryLimiter struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter counts failures in this process only. Use it when a single instance
+// serves logins; with several instances each keeps its own counts.
+func NewMemoryLimiter() domain.LoginLimiter {
+	return &memoryLimiter{entries: make(map[string]*memoryEntry)}
+}
+
+func (l *memoryLimiter) Get(ctx context.Context, key string) (entity.LoginThrottle, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok || !timeth.Now().Before(entry.expiresAt) {
+		return entity.LoginThrottle{}, nil
+	}
+	return entry.throttle, nil
+}
+
+func (l *memoryLimiter) Reserve(ctx context.Context, key string, window time.Duration) (entity.LoginReservation, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := timeth.Now()
+	l.sweep(now)
+
+	entry, ok := l.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		entry = &memoryEntry{}
+		l.entries[key] = entry
+	}
+	reservation := entity.LoginReservation{Key: key, Previous: entry.throttle, ReservedAt: now}
+	entry.throttle.Failures++
+	entry.throttle.LastFailureAt = now
+	entry.expiresAt = now.Add(window)
+
+	return reservation, nil
+}
+
+func (l *memoryLimiter) Release(ctx context.Context, reservation entity.LoginReservation, window time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[reservation.Key]
+	if !ok || entry.throttle.Failures == 0 {
+		return nil
+	}
+	entry.throttle.Failures--
+	if entry.throttle.LastFailureAt.Equal(reservation.ReservedAt) {
+		entry.throttle.LastFailureAt = reservation.Previous.LastFailureAt
+		entry.expiresAt = reservation.Previous.LastFailureAt.Add(window)
+	}
+	if entry.throttle.Failures == 0 {
+		delete(l.entries, reservation.Key)
+	}
+	return nil
+}
+
+func (l *memoryLimiter) Reset(ctx context.Context, key string) error {
+	l.mu.Lock()
+	delete(l.entries, key)
+	l.mu.Unlock()
+	return nil
+}
+
+// sweep deletes expired counts, so keys that stop failing do not pile up.
+func (l *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, entry := range l.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(l.entries, key)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Package loginguard throttles failed logins per account and per IP address. Each
+// failure past a free allowance adds a growing delay before the next attempt, and
+// enough failures lock the key for a while.
+package loginguard
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"ecommerce-go-api/entity"
+
+	"github.com/google/uuid"
+)
+
+// maxDelayShift caps the doubling of BaseDelay so the shift cannot overflow.
+const maxDelayShift = 20
+
+// Policy is how failed logins of one kind of key are throttled.
+type Policy struct {
+	// FreeFailures is how many failures are allowed before delays start.
+	FreeFailures int
+	// BaseDelay is the wait after the first failure past FreeFailures. It doubles with
+	// every further failure, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockAfter is the failure count that locks the key.
+	LockAfter int
+	// LockDuration is how long a locked key waits. Failures are also forgotten once
+	// LockDuration has passed since the last one.
+	LockDuration time.Duration
+}
+
+// Wait returns how long the key has to wait before its next attempt, and whether the
+// wait is a lockout. It returns zero when the attempt is allowed.
+func (p Policy) Wait(throttle entity.LoginThrottle, now time.Time) (time.Duration, bool) {
+	expiresAt := throttle.LastFailureAt.Add(p.LockDuration)
+	if throttle.Failures == 0 || !now.Before(expiresAt) {
+		return 0, false
+	}
+	if throttle.Failures >= p.LockAfter {
+		return expiresAt.Sub(now), true
+	}
+	if throttle.Failures <= p.FreeFailures {
+		return 0, false
+	}
+
+	shift := min(throttle.Failures-p.FreeFailures-1, maxDelayShift)
+	delay := min(p.BaseDelay<<shift, p.MaxDelay)
+	if wait := throttle.LastFailureAt.Add(delay).Sub(now); wait > 0 {
+		return wait, false
+	}
+	return 0, false
+}
+
+// AccountKey is the limiter key of the account with the email, whether or not an
+// account has it, so unknown emails are throttled the same way.
+func AccountKey(email string) string {
+	return "account:" + NormalizeEmail(email)
+}
+
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
+
+// TwoFactorKey counts wrong two-factor codes of the user, apart from their password
+// failures.
+func TwoFactorKey(userID uuid.UUID) string {
+	return "2fa:" + userID.String()
+}
+
+// NormalizeEmail is the form emails are counted and audited in.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// AccountPolicyFromEnv locks an account after LOGIN_LOCKOUT_THRESHOLD failures (default
+// 10) for LOGIN_LOCKOUT_DURATION (default 15m). Delays start after the third failure.
+func AccountPolicyFromEnv() Policy {
+	return Policy{
+		FreeFailures: 3,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+		LockAfter:    getThreshold("LOGIN_LOCKOUT_THRESHOLD", 10),
+		LockDuration: getLockoutDuration(),
+	}
+}
+
+// IPPolicyFromEnv locks an IP address after LOGIN_IP_LOCKOUT_THRESHOLD failures (default
+// 100) across all accounts. It allows more failures than an account since many users
+// may share an address.
+func IPPolicyFromEnv() Policy {
+	return Policy{
+		FreeFailures: 20,
+		BaseDelay:    time.Second,
+		MaxDelay:     30 * time.Second,
+		LockAfter:    getThreshold("LOGIN_IP_LOCKOUT_THRESHOLD", 100),
+		LockDuration: getLockoutDuration(),
+	}
+}
+
+func getThreshold(key string, defaultThreshold int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultThreshold
+	}
+
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < 1 {
+		log.Printf("Warning: Invalid %s '%s'. Using default %d.", key, value, defaultThreshold)
+		return defaultThreshold
+	}
+
+	return threshold
+}
+
+func getLockoutDuration() time.Duration {
+	const defaultDuration = 15 * time.Minute
+
+	value := os.Getenv("LOGIN_LOCKOUT_DURATION")
+	if value == "" {
+		return defaultDuration
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Warning: Invalid LOGIN_LOCKOUT_DURATION '%s'. Using default %s.", value, defaultDuration)
+		return defaultDuration
+	}
+
+	return duration
+}
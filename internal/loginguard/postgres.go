@@ -0,0 +1,118 @@
+package loginguard
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+
+	"gorm.io/gorm"
+)
+
+type postgresLimiter struct {
+	db *gorm.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewPostgresLimiter keeps the counts in the login_throttles table, so every instance
+// sees the same failures.
+func NewPostgresLimiter(db *gorm.DB) domain.LoginLimiter {
+	return &postgresLimiter{db: db}
+}
+
+// NewLimiterFromEnv returns the limiter chosen by LOGIN_LIMITER_STORE: "postgres"
+// (default) or "memory".
+func NewLimiterFromEnv(db *gorm.DB) domain.LoginLimiter {
+	switch store := os.Getenv("LOGIN_LIMITER_STORE"); store {
+	case "", "postgres":
+		return NewPostgresLimiter(db)
+	case "memory":
+		return NewMemoryLimiter()
+	default:
+		log.Printf("Warning: Invalid LOGIN_LIMITER_STORE '%s'. Using default postgres.", store)
+		return NewPostgresLimiter(db)
+	}
+}
+
+func (l *postgresLimiter) Get(ctx context.Context, key string) (entity.LoginThrottle, error) {
+	var throttle entity.LoginThrottle
+	err := l.db.WithContext(ctx).
+		Raw("SELECT failures, last_failure_at FROM login_throttles WHERE key = ? AND expires_at > ?", key, timeth.Now()).
+		Scan(&throttle).Error
+	return throttle, err
+}
+
+// Reserve counts the attempt in a single upsert, so concurrent attempts of the same key
+// are all counted and each sees the ones before it. The time is kept to microseconds,
+// as stored, so Release can recognize the reservation.
+func (l *postgresLimiter) Reserve(ctx context.Context, key string, window time.Duration) (entity.LoginReservation, error) {
+	now := timeth.Now().Truncate(time.Microsecond)
+	l.sweep(ctx, now)
+
+	var previous entity.LoginThrottle
+	err := l.db.WithContext(ctx).Raw(`
+		WITH previous AS (
+			SELECT last_failure_at FROM login_throttles
+			WHERE key = @key AND expires_at > @now
+			FOR UPDATE
+		), reserved AS (
+			INSERT INTO login_throttles (key, failures, last_failure_at, expires_at)
+			VALUES (@key, 1, @now, @expiresAt)
+			ON CONFLICT (key) DO UPDATE SET
+				failures = CASE WHEN login_throttles.expires_at > @now THEN login_throttles.failures + 1 ELSE 1 END,
+				last_failure_at = EXCLUDED.last_failure_at,
+				expires_at = EXCLUDED.expires_at
+			RETURNING failures
+		)
+		SELECT reserved.failures - 1 AS failures, COALESCE(previous.last_failure_at, @now) AS last_failure_at
+		FROM reserved LEFT JOIN previous ON true`,
+		map[string]interface{}{"key": key, "now": now, "expiresAt": now.Add(window)},
+	).Scan(&previous).Error
+	if err != nil {
+		return entity.LoginReservation{}, err
+	}
+	return entity.LoginReservation{Key: key, Previous: previous, ReservedAt: now}, nil
+}
+
+func (l *postgresLimiter) Release(ctx context.Context, reservation entity.LoginReservation, window time.Duration) error {
+	return l.db.WithContext(ctx).Exec(`
+		UPDATE login_throttles SET
+			failures = GREATEST(failures - 1, 0),
+			last_failure_at = CASE WHEN last_failure_at = @reservedAt THEN @previousAt ELSE last_failure_at END,
+			expires_at = CASE WHEN last_failure_at = @reservedAt THEN @previousExpiresAt ELSE expires_at END
+		WHERE key = @key`,
+		map[string]interface{}{
+			"key":               reservation.Key,
+			"reservedAt":        reservation.ReservedAt,
+			"previousAt":        reservation.Previous.LastFailureAt,
+			"previousExpiresAt": reservation.Previous.LastFailureAt.Add(window),
+		},
+	).Error
+}
+
+func (l *postgresLimiter) Reset(ctx context.Context, key string) error {
+	return l.db.WithContext(ctx).Exec("DELETE FROM login_throttles WHERE key = ?", key).Error
+}
+
+// sweep deletes expired counts at most once per sweepInterval. A failed sweep is only
+// logged; the rows are deleted on a later one.
+func (l *postgresLimiter) sweep(ctx context.Context, now time.Time) {
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) < sweepInterval {
+		l.mu.Unlock()
+		return
+	}
+	l.lastSweep = now
+	l.mu.Unlock()
+
+	if err := l.db.WithContext(ctx).Exec("DELETE FROM login_throttles WHERE expires_at <= ?", now).Error; err != nil {
+		log.Printf("[AUTH] Warning: Failed to delete expired login throttles: %v", err)
+	}
+}
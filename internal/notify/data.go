@@ -0,0 +1,94 @@
+package notify
+
+import "time"
+
+// The data the email templates are rendered with.
+
+type OrderItemSummary struct {
+	Name     string
+	Qty      uint32
+	Subtotal float64
+}
+
+type ShopOrderSummary struct {
+	OrderNumber string
+	ShopName    string
+	Items       []OrderItemSummary
+	Shipping    float64
+	GrandTotal  float64
+}
+
+type OrderPlacedData struct {
+	Name       string
+	Orders     []ShopOrderSummary
+	GrandTotal float64
+}
+
+type PaymentExpiredData struct {
+	Name          string
+	OrderNumbers  []string
+	TransactionID string
+}
+
+type ParcelSummary struct {
+	Courier    string
+	TrackingNo string
+}
+
+type ShopOrderShippedData struct {
+	Name                string
+	OrderNumber         string
+	ShopName            string
+	Parcels             []ParcelSummary
+	EstimatedDeliveryTo *time.Time
+}
+
+// AutoCompleteReminderData is used by the shop_order_auto_complete_reminder email.
+type AutoCompleteReminderData struct {
+	Name        string
+	OrderNumber string
+	ShopName    string
+	CompleteAt  time.Time
+}
+
+// RefundData is used by every refund email. Reason is only set for refund requests,
+// which go to the shop.
+type RefundData struct {
+	Name        string
+	OrderNumber string
+	ShopName    string
+	Amount      float64
+	ReasonCode  string
+	Reason      string
+}
+
+// InboxData is what in-app notifications are rendered with. OrderNumbers lists every
+// shop order of a checkout; OrderNumber is the one the notification is about. DueAt is
+// only set for auto-complete reminders.
+type InboxData struct {
+	OrderNumber  string
+	OrderNumbers []string
+	ShopName     string
+	Amount       float64
+	Reason       string
+	DueAt        time.Time
+}
+
+// AccountData is used by the account emails. URL carries the single-use token of
+// verify_email and password_reset emails; ChangedAt is only set for password_changed.
+type AccountData struct {
+	Name           string
+	URL            string
+	ExpiresInHours int
+	ChangedAt      time.Time
+}
+
+// ShopInvitationData is used by the shop_invitation email. URL carries the invitation
+// token; Role is the shop member role offered.
+type ShopInvitationData struct {
+	InviterName   string
+	ShopName      string
+	Role          string
+	URL           string
+	ExpiresInDays int
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+
+	"ecommerce-go-api/entity"
+)
+
+// inboxTemplates holds, per language, templates/<language>/inbox.txt, which defines
+// "<template>.title" and "<template>.body" for every in-app notification.
+var (
+	inboxTemplates     map[string]*texttemplate.Template
+	inboxTemplatesErr  error
+	inboxTemplatesOnce sync.Once
+)
+
+func loadInboxTemplates() (map[string]*texttemplate.Template, error) {
+	inboxTemplatesOnce.Do(func() {
+		inboxTemplates = make(map[string]*texttemplate.Template)
+		for _, lang := range languages {
+			tmpl, err := texttemplate.New("inbox.txt").Funcs(funcs).ParseFS(templateFS, "templates/"+lang+"/inbox.txt")
+			if err != nil {
+				inboxTemplatesErr = err
+				return
+			}
+			inboxTemplates[lang] = tmpl
+		}
+	})
+	return inboxTemplates, inboxTemplatesErr
+}
+
+// RenderInbox renders the title and body of an in-app notification in language,
+// falling back to Thai for languages without templates.
+func RenderInbox(name, language string, data InboxData) (title, body string, err error) {
+	all, err := loadInboxTemplates()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load inbox templates: %w", err)
+	}
+
+	tmpl, ok := all[language]
+	if !ok {
+		tmpl = all[entity.LanguageThai]
+	}
+	if tmpl.Lookup(name+".title") == nil {
+		return "", "", fmt.Errorf("unknown inbox template %q", name)
+	}
+
+	var t, b bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&t, name+".title", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&b, name+".body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(t.String()), strings.TrimSpace(b.String()), nil
+}
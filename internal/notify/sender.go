@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	claimBatchSize      = 50
+	claimLease          = 5 * time.Minute
+	maxWorkers          = 5
+	sendTimeout         = 30 * time.Second
+)
+
+// maxAttempts is how many times an email is tried before it is marked failed.
+const maxAttempts = 6
+
+const (
+	baseRetryDelay = time.Minute
+	maxRetryDelay  = time.Hour
+)
+
+// Sender sends queued emails through the notifier. Every instance runs one; emails are
+// claimed with SKIP LOCKED leases, so each is sent by one instance at a time.
+type Sender struct {
+	repo     domain.NotificationRepository
+	notifier domain.Notifier
+	claimant string
+	interval time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewSender(repo domain.NotificationRepository, notifier domain.Notifier) *Sender {
+	return &Sender{
+		repo:     repo,
+		notifier: notifier,
+		claimant: newClaimant(),
+		interval: getPollInterval(),
+		stop:     make(chan struct{}),
+	}
+}
+
+func newClaimant() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+func getPollInterval() time.Duration {
+	interval := os.Getenv("EMAIL_POLL_INTERVAL")
+	if interval == "" {
+		return defaultPollInterval
+	}
+
+	d, err := time.ParseDuration(interval)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: Invalid EMAIL_POLL_INTERVAL '%s'. Using default %s", interval, defaultPollInterval)
+		return defaultPollInterval
+	}
+	return d
+}
+
+func (s *Sender) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-s.stop
+			cancel()
+		}()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		log.Printf("[EMAIL] Sender started (every %s, instance: %s)", s.interval, s.claimant)
+		for {
+			s.drain(ctx)
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the emails being sent, if any, and stops polling.
+func (s *Sender) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// drain sends due emails batch by batch until none are left.
+func (s *Sender) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		emails, err := s.repo.ClaimDueEmails(ctx, entity.JobClaim{
+			Claimant: s.claimant,
+			Limit:    claimBatchSize,
+			Lease:    claimLease,
+		})
+		if err != nil {
+			log.Printf("[EMAIL] Error claiming emails: %v", err)
+			return
+		}
+		if len(emails) == 0 {
+			return
+		}
+
+		semaphore := make(chan struct{}, maxWorkers)
+		var wg sync.WaitGroup
+		for _, email := range emails {
+			wg.Add(1)
+			semaphore <- struct{}{}
+
+			go func(m *entity.EmailMessage) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[EMAIL] Panic recovered while sending email %s: %v", m.ID, r)
+					}
+				}()
+
+				s.send(ctx, m)
+			}(email)
+		}
+		wg.Wait()
+	}
+}
+
+func (s *Sender) send(ctx context.Context, message *entity.EmailMessage) {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := s.notifier.Send(sendCtx, entity.Email{
+		To:      message.Recipient,
+		Subject: message.Subject,
+		HTML:    message.HTMLBody,
+		Text:    message.TextBody,
+	})
+	cancel()
+
+	message.Attempts++
+	if err == nil {
+		now := timeth.Now()
+		message.Status = entity.EmailStatusSent
+		message.SentAt = &now
+		message.LastError = ""
+	} else {
+		message.LastError = err.Error()
+		if message.Attempts >= maxAttempts {
+			message.Status = entity.EmailStatusFailed
+			log.Printf("[EMAIL] Email %s (%s) failed after %d attempts: %v", message.ID, message.Template, message.Attempts, err)
+		} else {
+			message.NextAttemptAt = timeth.Now().Add(retryDelay(message.Attempts))
+		}
+	}
+
+	finishCtx, cancelFinish := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFinish()
+	if err := s.repo.FinishEmail(finishCtx, message); err != nil {
+		log.Printf("[EMAIL] Warning: Failed to record attempt of email %s: %v", message.ID, err)
+	}
+}
+
+// retryDelay doubles the wait after every failed attempt, up to maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
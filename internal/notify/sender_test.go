@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+)
+
+func newTestEmail() *entity.EmailMessage {
+	return &entity.EmailMessage{
+		ID:        uuid.New(),
+		Recipient: "buyer@example.com",
+		Template:  entity.EmailTemplateOrderPlaced,
+		Subject:   "Order ORD-0001 placed",
+		HTMLBody:  "<p>Thanks</p>",
+		TextBody:  "Thanks\n",
+		Status:    entity.EmailStatusPending,
+	}
+}
+
+func TestSend_MarksSent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockNotificationRepository(ctrl)
+	notifier := mock.NewMockNotifier(ctrl)
+	s := &Sender{repo: repo, notifier: notifier}
+	email := newTestEmail()
+
+	notifier.EXPECT().Send(gomock.Any(), entity.Email{
+		To:      "buyer@example.com",
+		Subject: "Order ORD-0001 placed",
+		HTML:    "<p>Thanks</p>",
+		Text:    "Thanks\n",
+	}).Return(nil)
+	repo.EXPECT().FinishEmail(gomock.Any(), email).DoAndReturn(func(ctx context.Context, m *entity.EmailMessage) error {
+		assert.Equal(t, entity.EmailStatusSent, m.Status)
+		assert.Equal(t, 1, m.Attempts)
+		assert.NotNil(t, m.SentAt)
+		return nil
+	})
+
+	s.send(context.Background(), email)
+}
+
+func TestSend_RetriesThenFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mock.NewMockNotificationRepository(ctrl)
+	notifier := mock.NewMockNotifier(ctrl)
+	s := &Sender{repo: repo, notifier: notifier}
+	email := newTestEmail()
+
+	notifier.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("421 try again later")).Times(2)
+	repo.EXPECT().FinishEmail(gomock.Any(), email).DoAndReturn(func(ctx context.Context, m *entity.EmailMessage) error {
+		assert.Equal(t, entity.EmailStatusPending, m.Status)
+		assert.Equal(t, "421 try again later", m.LastError)
+		assert.True(t, m.NextAttemptAt.After(time.Now()))
+		return nil
+	})
+	s.send(context.Background(), email)
+
+	email.Attempts = maxAttempts - 1
+	repo.EXPECT().FinishEmail(gomock.Any(), email).DoAndReturn(func(ctx context.Context, m *entity.EmailMessage) error {
+		assert.Equal(t, entity.EmailStatusFailed, m.Status)
+		assert.Equal(t, maxAttempts, m.Attempts)
+		assert.Nil(t, m.SentAt)
+		return nil
+	})
+	s.send(context.Background(), email)
+}
+
+func TestRetryDelay_DoublesUpToCap(t *testing.T) {
+	assert.Equal(t, time.Minute, retryDelay(1))
+	assert.Equal(t, 2*time.Minute, retryDelay(2))
+	assert.Equal(t, 16*time.Minute, retryDelay(5))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
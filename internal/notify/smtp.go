@@ -0,0 +1,206 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+const defaultSMTPPort = "587"
+
+// SMTPConfig configures the SMTP notifier. Username and Password are optional, e.g. for
+// a local SMTP catcher such as Mailpit.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type smtpNotifier struct {
+	cfg  SMTPConfig
+	from *mail.Address
+}
+
+// NewSMTPNotifier sends emails through an SMTP server, upgrading the connection with
+// STARTTLS when the server offers it.
+func NewSMTPNotifier(cfg SMTPConfig) (domain.Notifier, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+	if cfg.Port == "" {
+		cfg.Port = defaultSMTPPort
+	}
+	return &smtpNotifier{cfg: cfg, from: from}, nil
+}
+
+// NewNotifierFromEnv returns the SMTP notifier configured by the SMTP_* env vars. When
+// SMTP_HOST is not set, emails are only logged.
+func NewNotifierFromEnv() domain.Notifier {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("Warning: SMTP_HOST is not set. Emails will be logged instead of sent.")
+		return &logNotifier{}
+	}
+
+	notifier, err := NewSMTPNotifier(SMTPConfig{
+		Host:     host,
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure SMTP: %v", err)
+	}
+	return notifier
+}
+
+func (n *smtpNotifier) Send(ctx context.Context, email entity.Email) error {
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address %q: %w", email.To, err)
+	}
+
+	msg, err := buildMessage(n.from, to, email)
+	if err != nil {
+		return err
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(n.cfg.Host, n.cfg.Port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if n.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMessage writes a multipart/alternative message with a plain text and an HTML
+// part, both base64 encoded so Thai text survives any relay.
+func buildMessage(from, to *mail.Address, email entity.Email) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", email.Text},
+		{"text/html; charset=UTF-8", email.HTML},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := pw.Write([]byte(wrapBase64(part.content))); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", to.String())
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", email.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", timeth.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domainOf(from.Address))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n", mw.Boundary())
+	fmt.Fprintf(&msg, "\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// wrapBase64 encodes s in lines of 76 characters, as MIME requires.
+func wrapBase64(s string) string {
+	encoded := base64.StdEncoding.EncodeToString([]byte(s))
+	var b bytes.Buffer
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76])
+		b.WriteString("\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
+
+// logNotifier is used when no SMTP server is configured.
+type logNotifier struct{}
+
+func (n *logNotifier) Send(ctx context.Context, email entity.Email) error {
+	log.Printf("[EMAIL] SMTP is not configured; not sending %q to %s", email.Subject, email.To)
+	return nil
+}
@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Each template has, per language, a .html file defining "body", rendered inside that
+// language's layout.html, and a .txt file defining "subject" and "text".
+var templateNames = []string{
+	entity.EmailTemplateOrderPlaced,
+	entity.EmailTemplatePaymentExpired,
+	entity.EmailTemplateShopOrderShipped,
+	entity.EmailTemplateShopOrderAutoCompleteReminder,
+	entity.EmailTemplateRefundRequested,
+	entity.EmailTemplateRefundApproved,
+	entity.EmailTemplateRefundCompleted,
+	entity.EmailTemplateVerifyEmail,
+	entity.EmailTemplatePasswordReset,
+	entity.EmailTemplatePasswordChanged,
+	entity.EmailTemplateShopInvitation,
+}
+
+var languages = []string{entity.LanguageThai, entity.LanguageEnglish}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var (
+	templates     map[string]emailTemplate
+	templatesErr  error
+	templatesOnce sync.Once
+)
+
+var funcs = map[string]any{
+	"baht": formatBaht,
+	"join": strings.Join,
+	"date": func(t time.Time) string {
+		return t.In(timeth.LoadLocation()).Format("02/01/2006")
+	},
+}
+
+func loadTemplates() (map[string]emailTemplate, error) {
+	templatesOnce.Do(func() {
+		templates = make(map[string]emailTemplate)
+		for _, lang := range languages {
+			layout, err := htmltemplate.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/"+lang+"/layout.html")
+			if err != nil {
+				templatesErr = err
+				return
+			}
+			for _, name := range templateNames {
+				html, err := htmltemplate.Must(layout.Clone()).ParseFS(templateFS, fmt.Sprintf("templates/%s/%s.html", lang, name))
+				if err != nil {
+					templatesErr = err
+					return
+				}
+				text, err := texttemplate.New(name+".txt").Funcs(funcs).ParseFS(templateFS, fmt.Sprintf("templates/%s/%s.txt", lang, name))
+				if err != nil {
+					templatesErr = err
+					return
+				}
+				templates[lang+"/"+name] = emailTemplate{html: html, text: text}
+			}
+		}
+	})
+	return templates, templatesErr
+}
+
+// Render renders the named email template in language, falling back to Thai for
+// languages without templates.
+func Render(name, language, to string, data any) (*entity.Email, error) {
+	all, err := loadTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email templates: %w", err)
+	}
+
+	tmpl, ok := all[language+"/"+name]
+	if !ok {
+		tmpl, ok = all[entity.LanguageThai+"/"+name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "layout.html", data); err != nil {
+		return nil, err
+	}
+
+	return &entity.Email{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
+
+// formatBaht formats an amount as "฿1,234.50".
+func formatBaht(amount float64) string {
+	s := fmt.Sprintf("%.2f", amount)
+	whole, fraction, _ := strings.Cut(s, ".")
+
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+
+	if negative {
+		return "-฿" + b.String() + "." + fraction
+	}
+	return "฿" + b.String() + "." + fraction
+}
@@ -0,0 +1,122 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ecommerce-go-api/entity"
+)
+
+func TestRender_AllTemplatesInBothLanguages(t *testing.T) {
+	delivery := time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)
+	data := map[string]any{
+		entity.EmailTemplateOrderPlaced: OrderPlacedData{
+			Name: "Somchai",
+			Orders: []ShopOrderSummary{{
+				OrderNumber: "ORD-0001",
+				ShopName:    "Bangkok Gadgets",
+				Items:       []OrderItemSummary{{Name: "USB-C Cable", Qty: 2, Subtotal: 398}},
+				Shipping:    40,
+				GrandTotal:  438,
+			}},
+			GrandTotal: 438,
+		},
+		entity.EmailTemplatePaymentExpired: PaymentExpiredData{Name: "Somchai", OrderNumbers: []string{"ORD-0001"}, TransactionID: "TXN-1"},
+		entity.EmailTemplateShopOrderShipped: ShopOrderShippedData{
+			Name:                "Somchai",
+			OrderNumber:         "ORD-0001",
+			ShopName:            "Bangkok Gadgets",
+			Parcels:             []ParcelSummary{{Courier: "Kerry Express", TrackingNo: "KEX123"}},
+			EstimatedDeliveryTo: &delivery,
+		},
+		entity.EmailTemplateShopOrderAutoCompleteReminder: AutoCompleteReminderData{Name: "Somchai", OrderNumber: "ORD-0001", ShopName: "Bangkok Gadgets", CompleteAt: delivery},
+		entity.EmailTemplateRefundRequested:               RefundData{Name: "Somsri", OrderNumber: "ORD-0001", ShopName: "Bangkok Gadgets", Amount: 1234.5, ReasonCode: "DAMAGED", Reason: "Box was crushed"},
+		entity.EmailTemplateRefundApproved:                RefundData{Name: "Somchai", OrderNumber: "ORD-0001", ShopName: "Bangkok Gadgets", Amount: 1234.5},
+		entity.EmailTemplateRefundCompleted:               RefundData{Name: "Somchai", OrderNumber: "ORD-0001", ShopName: "Bangkok Gadgets", Amount: 1234.5},
+		entity.EmailTemplateVerifyEmail:                   AccountData{Name: "Somchai", URL: "https://shop.example.com/verify-email?token=verify-123", ExpiresInHours: 24},
+		entity.EmailTemplatePasswordReset:                 AccountData{Name: "Somchai", URL: "https://shop.example.com/reset-password?token=reset-123", ExpiresInHours: 1},
+		entity.EmailTemplatePasswordChanged:               AccountData{Name: "Somchai", ChangedAt: delivery},
+		entity.EmailTemplateShopInvitation:                ShopInvitationData{InviterName: "Somsri", ShopName: "Bangkok Gadgets", Role: "PACKER", URL: "https://shop.example.com/shop-invitations/accept?token=invite-123", ExpiresInDays: 7},
+	}
+	// The account emails are not about an order; check them for their link or date.
+	want := map[string]string{
+		entity.EmailTemplateVerifyEmail:     "token=verify-123",
+		entity.EmailTemplatePasswordReset:   "token=reset-123",
+		entity.EmailTemplatePasswordChanged: "05/03/2026",
+		entity.EmailTemplateShopInvitation:  "token=invite-123",
+	}
+
+	for _, name := range templateNames {
+		marker, ok := want[name]
+		if !ok {
+			marker = "ORD-0001"
+		}
+		for _, lang := range languages {
+			email, err := Render(name, lang, "buyer@example.com", data[name])
+			require.NoError(t, err, "%s/%s", lang, name)
+
+			assert.Equal(t, "buyer@example.com", email.To)
+			assert.NotEmpty(t, email.Subject, "%s/%s", lang, name)
+			assert.NotContains(t, email.Subject, "\n", "%s/%s", lang, name)
+			assert.Contains(t, email.HTML, marker, "%s/%s", lang, name)
+			assert.Contains(t, email.Text, marker, "%s/%s", lang, name)
+		}
+	}
+}
+
+func TestRender_EscapesHTMLAndFallsBackToThai(t *testing.T) {
+	data := RefundData{Name: "<script>alert(1)</script>", OrderNumber: "ORD-0001", Amount: 10}
+
+	email, err := Render(entity.EmailTemplateRefundApproved, "fr", "buyer@example.com", data)
+	require.NoError(t, err)
+
+	thai, err := Render(entity.EmailTemplateRefundApproved, entity.LanguageThai, "buyer@example.com", data)
+	require.NoError(t, err)
+	assert.Equal(t, thai.Subject, email.Subject)
+	assert.NotContains(t, email.HTML, "<script>")
+
+	_, err = Render("missing", entity.LanguageEnglish, "buyer@example.com", data)
+	assert.Error(t, err)
+}
+
+func TestRender_FormatsBaht(t *testing.T) {
+	assert.Equal(t, "฿0.00", formatBaht(0))
+	assert.Equal(t, "฿999.00", formatBaht(999))
+	assert.Equal(t, "฿1,234.50", formatBaht(1234.5))
+	assert.Equal(t, "฿1,000,000.00", formatBaht(1000000))
+	assert.Equal(t, "-฿12,000.25", formatBaht(-12000.25))
+}
+
+func TestRenderInbox_AllTemplatesInBothLanguages(t *testing.T) {
+	names := []string{
+		entity.InboxTemplateOrderPlaced,
+		entity.InboxTemplateNewOrder,
+		entity.InboxTemplatePaymentReceived,
+		entity.InboxTemplatePaymentExpired,
+		entity.InboxTemplateShopOrderShipped,
+		entity.InboxTemplateShopOrderDelivered,
+		entity.InboxTemplateShopOrderCompleted,
+		entity.InboxTemplateShopOrderCancelled,
+		entity.InboxTemplateShopOrderAutoCompleteReminder,
+		entity.InboxTemplateRefundRequested,
+		entity.InboxTemplateRefundApproved,
+		entity.InboxTemplateRefundCompleted,
+	}
+	data := InboxData{OrderNumber: "ORD-0001", OrderNumbers: []string{"ORD-0001"}, ShopName: "Bangkok Gadgets", Amount: 438, DueAt: time.Date(2026, 3, 5, 10, 0, 0, 0, time.UTC)}
+
+	for _, name := range names {
+		for _, lang := range languages {
+			title, body, err := RenderInbox(name, lang, data)
+			require.NoError(t, err, "%s/%s", lang, name)
+
+			assert.NotEmpty(t, title, "%s/%s", lang, name)
+			assert.Contains(t, body, "ORD-0001", "%s/%s", lang, name)
+		}
+	}
+
+	_, _, err := RenderInbox("missing", entity.LanguageEnglish, data)
+	assert.Error(t, err)
+}
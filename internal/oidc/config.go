@@ -0,0 +1,138 @@
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	appconfig "ecommerce-go-api/config"
+	"ecommerce-go-api/domain"
+)
+
+// presets are the endpoints of the providers known by name. Any of them can be
+// overridden, e.g. to point at a local mock provider.
+var presets = map[string]Config{
+	"google": {
+		AuthURL:     "https://accounts.google.com/o/oauth2/v2/auth",
+		TokenURL:    "https://oauth2.googleapis.com/token",
+		UserInfoURL: "https://openidconnect.googleapis.com/v1/userinfo",
+		JWKSURL:     "https://www.googleapis.com/oauth2/v3/certs",
+		Issuers:     []string{"https://accounts.google.com", "accounts.google.com"},
+		Scopes:      []string{"openid", "email", "profile"},
+	},
+	// LINE only shares emails its users have verified, and does not say so in the token.
+	"line": {
+		AuthURL:     "https://access.line.me/oauth2/v2.1/authorize",
+		TokenURL:    "https://api.line.me/oauth2/v2.1/token",
+		UserInfoURL: "https://api.line.me/oauth2/v2.1/userinfo",
+		JWKSURL:     "https://api.line.me/oauth2/v2.1/certs",
+		Issuers:     []string{"https://access.line.me"},
+		Scopes:      []string{"openid", "profile", "email"},
+		TrustEmail:  true,
+	},
+	// Facebook Login is plain OAuth2: the user is read from the Graph API, which only
+	// returns confirmed emails.
+	"facebook": {
+		AuthURL:     "https://www.facebook.com/v19.0/dialog/oauth",
+		TokenURL:    "https://graph.facebook.com/v19.0/oauth/access_token",
+		UserInfoURL: "https://graph.facebook.com/v19.0/me?fields=id,email,first_name,last_name,name",
+		Scopes:      []string{"email", "public_profile"},
+		TrustEmail:  true,
+	},
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9-]{1,30}$`)
+
+// ProvidersFromEnv returns the providers listed in OAUTH_PROVIDERS, e.g. "google,line".
+// Each is configured by OAUTH_<NAME>_* variables; see ConfigFromEnv. Providers that are
+// not fully configured are skipped with a warning.
+func ProvidersFromEnv() map[string]domain.OAuthProvider {
+	providers := make(map[string]domain.OAuthProvider)
+	for _, name := range strings.Split(os.Getenv("OAUTH_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		config, err := ConfigFromEnv(name)
+		if err != nil {
+			log.Printf("Warning: Invalid OAuth provider '%s': %v. Skipping it.", name, err)
+			continue
+		}
+		providers[name] = NewClient(config)
+	}
+	return providers
+}
+
+// ConfigFromEnv reads OAUTH_<NAME>_CLIENT_ID and OAUTH_<NAME>_CLIENT_SECRET, and the
+// optional overrides of the provider's preset: _AUTH_URL, _TOKEN_URL, _USERINFO_URL,
+// _JWKS_URL, _ISSUER (comma-separated), _SCOPES (space-separated), _TRUST_EMAIL and
+// _REDIRECT_URL. Providers without a preset must set the endpoints and default to the
+// "openid email profile" scopes. The redirect URL defaults to
+// APP_URL/oauth/callback/<name>.
+func ConfigFromEnv(name string) (Config, error) {
+	if !providerNamePattern.MatchString(name) {
+		return Config{}, errors.New("name must be lowercase letters, digits or dashes")
+	}
+
+	config := presets[name]
+	config.Name = name
+	prefix := "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+	env := func(key string) string {
+		return strings.TrimSpace(os.Getenv(prefix + key))
+	}
+
+	config.ClientID = env("CLIENT_ID")
+	config.ClientSecret = env("CLIENT_SECRET")
+	if v := env("AUTH_URL"); v != "" {
+		config.AuthURL = v
+	}
+	if v := env("TOKEN_URL"); v != "" {
+		config.TokenURL = v
+	}
+	if v := env("USERINFO_URL"); v != "" {
+		config.UserInfoURL = v
+	}
+	if v := env("JWKS_URL"); v != "" {
+		config.JWKSURL = v
+	}
+	if v := env("ISSUER"); v != "" {
+		config.Issuers = nil
+		for _, issuer := range strings.Split(v, ",") {
+			if issuer = strings.TrimSpace(issuer); issuer != "" {
+				config.Issuers = append(config.Issuers, issuer)
+			}
+		}
+	}
+	if v := env("SCOPES"); v != "" {
+		config.Scopes = strings.Fields(v)
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if v := env("TRUST_EMAIL"); v != "" {
+		trust, err := strconv.ParseBool(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%sTRUST_EMAIL must be true or false", prefix)
+		}
+		config.TrustEmail = trust
+	}
+	config.RedirectURL = env("REDIRECT_URL")
+	if config.RedirectURL == "" {
+		config.RedirectURL = appconfig.AppURL() + "/oauth/callback/" + name
+	}
+
+	switch {
+	case config.ClientID == "":
+		return Config{}, fmt.Errorf("%sCLIENT_ID is not set", prefix)
+	case config.AuthURL == "" || config.TokenURL == "":
+		return Config{}, fmt.Errorf("%sAUTH_URL and %sTOKEN_URL must be set", prefix, prefix)
+	case config.UserInfoURL == "" && config.JWKSURL == "" && config.ClientSecret == "":
+		return Config{}, fmt.Errorf("%sUSERINFO_URL or %sJWKS_URL must be set", prefix, prefix)
+	}
+	return config, nil
+}
@@ -0,0 +1,348 @@
+// Package oidc signs users in with OAuth2 and OpenID Connect providers using the
+// authorization code flow with PKCE. Providers that return an ID token are read from it
+// after checking its signature, issuer, audience and nonce; the others, like Facebook,
+// are read from their userinfo endpoint.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrTokenExchange  = errors.New("provider rejected the authorization code")
+	ErrInvalidIDToken = errors.New("invalid ID token")
+	ErrUnknownKey     = errors.New("ID token is signed with an unknown key")
+	ErrNoSubject      = errors.New("provider did not return the account ID")
+)
+
+const (
+	httpTimeout = 10 * time.Second
+	// keysTTL is how long the provider's signing keys are cached. An unknown key ID
+	// fetches them again, at most once per keysRefetchInterval.
+	keysTTL             = time.Hour
+	keysRefetchInterval = time.Minute
+	// clockSkew is allowed between the provider's clock and ours.
+	clockSkew = time.Minute
+)
+
+// Config is one identity provider.
+type Config struct {
+	Name         string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is where the provider sends the user back with the code. It must be
+	// registered with the provider.
+	RedirectURL string
+	AuthURL     string
+	TokenURL    string
+	// UserInfoURL is read with the access token when the provider returns no ID token.
+	UserInfoURL string
+	// JWKSURL serves the keys ID tokens are signed with. ID tokens signed with HS256 are
+	// checked with ClientSecret instead.
+	JWKSURL string
+	// Issuers are the accepted "iss" of ID tokens; any is accepted when empty.
+	Issuers []string
+	Scopes  []string
+	// TrustEmail treats emails as verified when the provider does not say, for providers
+	// that only share verified emails.
+	TrustEmail bool
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (c *Client) AuthCodeURL(state, nonce, codeChallenge string) string {
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", c.config.ClientID)
+	query.Set("redirect_uri", c.config.RedirectURL)
+	query.Set("scope", strings.Join(c.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(c.config.AuthURL, "?") {
+		separator = "&"
+	}
+	return c.config.AuthURL + separator + query.Encode()
+}
+
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*entity.OAuthIdentity, error) {
+	token, err := c.exchangeCode(ctx, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var subject string
+	var info profile
+	if token.IDToken != "" {
+		subject, info, err = c.verifyIDToken(ctx, token.IDToken, nonce)
+	} else {
+		subject, info, err = c.userInfo(ctx, token.AccessToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return c.identity(subject, info)
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	IDToken     string `json:"id_token"`
+}
+
+func (c *Client) exchangeCode(ctx context.Context, code, codeVerifier string) (*tokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.config.RedirectURL)
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	if err := c.do(req, &token); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenExchange, err)
+	}
+	if token.AccessToken == "" && token.IDToken == "" {
+		return nil, fmt.Errorf("%w: no token in the response", ErrTokenExchange)
+	}
+	return &token, nil
+}
+
+// userInfoResponse is a userinfo response. Facebook sends the account ID as "id".
+type userInfoResponse struct {
+	Subject string `json:"sub"`
+	ID      string `json:"id"`
+	profile
+}
+
+func (c *Client) userInfo(ctx context.Context, accessToken string) (string, profile, error) {
+	if c.config.UserInfoURL == "" {
+		return "", profile{}, fmt.Errorf("provider %s returned no ID token and has no userinfo endpoint", c.config.Name)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.UserInfoURL, nil)
+	if err != nil {
+		return "", profile{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info userInfoResponse
+	if err := c.do(req, &info); err != nil {
+		return "", profile{}, fmt.Errorf("failed to get userinfo: %w", err)
+	}
+	return firstNonEmpty(info.Subject, info.ID), info.profile, nil
+}
+
+// idTokenClaims are the claims read from ID tokens. The account ID is the subject.
+type idTokenClaims struct {
+	profile
+	Nonce string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+func (c *Client) verifyIDToken(ctx context.Context, idToken, nonce string) (string, profile, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+			if c.config.ClientSecret == "" {
+				return nil, ErrUnknownKey
+			}
+			return []byte(c.config.ClientSecret), nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return c.publicKey(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256", "HS256"}),
+		jwt.WithAudience(c.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+		jwt.WithTimeFunc(timeth.Now),
+	)
+	if err != nil {
+		return "", profile{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if len(c.config.Issuers) > 0 && !slices.Contains(c.config.Issuers, claims.Issuer) {
+		return "", profile{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidIDToken, claims.Issuer)
+	}
+	if claims.Nonce != nonce {
+		return "", profile{}, fmt.Errorf("%w: nonce does not match", ErrInvalidIDToken)
+	}
+
+	return claims.Subject, claims.profile, nil
+}
+
+// publicKey returns the provider's key with the ID, fetching the keys again if it is
+// not cached.
+func (c *Client) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := timeth.Now()
+	if key, ok := c.keys[kid]; ok && now.Sub(c.keysFetchedAt) < keysTTL {
+		return key, nil
+	}
+	if c.keys != nil && now.Sub(c.keysFetchedAt) < keysRefetchInterval {
+		return nil, ErrUnknownKey
+	}
+
+	keys, err := c.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.keys = keys
+	c.keysFetchedAt = now
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (c *Client) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	if c.config.JWKSURL == "" {
+		return nil, ErrUnknownKey
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := c.do(req, &set); err != nil {
+		return nil, fmt.Errorf("failed to get signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped; tokens signed with them are rejected.
+		if key, err := k.publicKey(); err == nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// do sends the request and decodes its JSON response.
+func (c *Client) do(req *http.Request, v interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, truncate(string(body), 200))
+	}
+	return json.Unmarshal(body, v)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,247 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProvider is a local identity provider. It accepts the code "good-code" with the
+// code verifier "verifier", and answers with idTokenClaims signed by its key, or with
+// userInfo when idTokenClaims is nil. signWith replaces the key the token is signed
+// with, but not the one its JWKS serves.
+type mockProvider struct {
+	server        *httptest.Server
+	key           *rsa.PrivateKey
+	signWith      *rsa.PrivateKey
+	idTokenClaims jwt.MapClaims
+	userInfo      map[string]interface{}
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p := &mockProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "good-code" || r.FormValue("code_verifier") != "verifier" || r.FormValue("client_id") != "client-1" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		resp := map[string]string{"access_token": "access-1", "token_type": "Bearer"}
+		if p.idTokenClaims != nil {
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, p.idTokenClaims)
+			token.Header["kid"] = "k1"
+			key := p.key
+			if p.signWith != nil {
+				key = p.signWith
+			}
+			signed, err := token.SignedString(key)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			resp["id_token"] = signed
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(p.userInfo)
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *mockProvider) config() Config {
+	return Config{
+		Name:        "mock",
+		ClientID:    "client-1",
+		RedirectURL: "https://shop.example.com/oauth/callback/mock",
+		AuthURL:     p.server.URL + "/authorize",
+		TokenURL:    p.server.URL + "/token",
+		UserInfoURL: p.server.URL + "/userinfo",
+		JWKSURL:     p.server.URL + "/jwks",
+		Issuers:     []string{p.server.URL},
+		Scopes:      []string{"openid", "email", "profile"},
+	}
+}
+
+func (p *mockProvider) claims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            p.server.URL,
+		"aud":            "client-1",
+		"sub":            "mock-user-1",
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "Somchai@Example.com",
+		"email_verified": true,
+		"given_name":     "Somchai",
+		"family_name":    "Jaidee",
+		"picture":        "https://example.com/somchai.jpg",
+	}
+}
+
+func TestClient_AuthCodeURLCarriesStateNonceAndChallenge(t *testing.T) {
+	p := newMockProvider(t)
+	client := NewClient(p.config())
+
+	u, err := url.Parse(client.AuthCodeURL("state-1", "nonce-1", "challenge-1"))
+	require.NoError(t, err)
+
+	query := u.Query()
+	assert.Equal(t, "/authorize", u.Path)
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "client-1", query.Get("client_id"))
+	assert.Equal(t, "https://shop.example.com/oauth/callback/mock", query.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "challenge-1", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestClient_ExchangeVerifiesIDToken(t *testing.T) {
+	p := newMockProvider(t)
+	p.idTokenClaims = p.claims("nonce-1")
+	client := NewClient(p.config())
+
+	identity, err := client.Exchange(context.Background(), "good-code", "verifier", "nonce-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "mock-user-1", identity.Subject)
+	assert.Equal(t, "somchai@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Somchai", identity.FirstName)
+	assert.Equal(t, "Jaidee", identity.LastName)
+	assert.Equal(t, "https://example.com/somchai.jpg", identity.ImageURL)
+}
+
+func TestClient_ExchangeRejectsInvalidIDTokens(t *testing.T) {
+	p := newMockProvider(t)
+	client := NewClient(p.config())
+
+	tests := map[string]func(claims jwt.MapClaims){
+		"wrong nonce":    func(claims jwt.MapClaims) { claims["nonce"] = "replayed" },
+		"wrong audience": func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+		"wrong issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+	}
+	for name, tamper := range tests {
+		t.Run(name, func(t *testing.T) {
+			p.idTokenClaims = p.claims("nonce-1")
+			tamper(p.idTokenClaims)
+
+			_, err := client.Exchange(context.Background(), "good-code", "verifier", "nonce-1")
+
+			assert.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestClient_ExchangeRejectsTokenSignedWithAnotherKey(t *testing.T) {
+	p := newMockProvider(t)
+	p.idTokenClaims = p.claims("nonce-1")
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	p.signWith = other
+	client := NewClient(p.config())
+
+	_, err = client.Exchange(context.Background(), "good-code", "verifier", "nonce-1")
+
+	assert.ErrorIs(t, err, ErrInvalidIDToken)
+}
+
+func TestClient_ExchangeRejectsWrongCodeVerifier(t *testing.T) {
+	p := newMockProvider(t)
+	p.idTokenClaims = p.claims("nonce-1")
+	client := NewClient(p.config())
+
+	_, err := client.Exchange(context.Background(), "good-code", "stolen-code-without-verifier", "nonce-1")
+
+	assert.ErrorIs(t, err, ErrTokenExchange)
+}
+
+func TestClient_ExchangeReadsUserInfoWithoutIDToken(t *testing.T) {
+	p := newMockProvider(t)
+	p.userInfo = map[string]interface{}{
+		"id":         "fb-1",
+		"email":      "somchai@example.com",
+		"first_name": "Somchai",
+		"last_name":  "Jaidee",
+		"picture":    map[string]interface{}{"data": map[string]string{"url": "https://example.com/p.jpg"}},
+	}
+	config := p.config()
+	config.TrustEmail = true
+	client := NewClient(config)
+
+	identity, err := client.Exchange(context.Background(), "good-code", "verifier", "nonce-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "fb-1", identity.Subject)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Somchai", identity.FirstName)
+	assert.Empty(t, identity.ImageURL)
+}
+
+func TestClient_UnverifiedEmailIsNotTrusted(t *testing.T) {
+	p := newMockProvider(t)
+	p.userInfo = map[string]interface{}{"sub": "mock-user-1", "email": "somchai@example.com", "email_verified": "false", "name": "Somchai Jaidee"}
+	config := p.config()
+	config.TrustEmail = true
+	client := NewClient(config)
+
+	identity, err := client.Exchange(context.Background(), "good-code", "verifier", "nonce-1")
+
+	require.NoError(t, err)
+	assert.False(t, identity.EmailVerified)
+	assert.Equal(t, "Somchai", identity.FirstName)
+	assert.Equal(t, "Jaidee", identity.LastName)
+}
+
+func TestConfigFromEnv_OverridesPresetEndpoints(t *testing.T) {
+	t.Setenv("APP_URL", "https://shop.example.com/")
+	t.Setenv("OAUTH_GOOGLE_CLIENT_ID", "client-1")
+	t.Setenv("OAUTH_GOOGLE_CLIENT_SECRET", "secret-1")
+	t.Setenv("OAUTH_GOOGLE_TOKEN_URL", "http://localhost:9000/token")
+	t.Setenv("OAUTH_GOOGLE_ISSUER", "http://localhost:9000")
+
+	config, err := ConfigFromEnv("google")
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://accounts.google.com/o/oauth2/v2/auth", config.AuthURL)
+	assert.Equal(t, "http://localhost:9000/token", config.TokenURL)
+	assert.Equal(t, []string{"http://localhost:9000"}, config.Issuers)
+	assert.Equal(t, "https://shop.example.com/oauth/callback/google", config.RedirectURL)
+
+	_, err = ConfigFromEnv("mock")
+	assert.Error(t, err, "providers without a preset need their endpoints")
+}
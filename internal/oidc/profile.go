@@ -0,0 +1,75 @@
+package oidc
+
+import (
+	"encoding/json"
+	"strings"
+
+	"ecommerce-go-api/entity"
+)
+
+// profile holds the standard OIDC claims about the user, and the fields Facebook's Graph
+// API uses for them. The account ID is read apart, since ID tokens and userinfo
+// responses carry it differently.
+type profile struct {
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+	GivenName     string       `json:"given_name"`
+	FamilyName    string       `json:"family_name"`
+	FirstName     string       `json:"first_name"`
+	LastName      string       `json:"last_name"`
+	// Picture is a URL, or an object in Graph API responses, which is ignored.
+	Picture json.RawMessage `json:"picture"`
+}
+
+// flexibleBool reads booleans some providers send as strings, e.g. "true".
+type flexibleBool struct {
+	set   bool
+	value bool
+}
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "true":
+		*b = flexibleBool{set: true, value: true}
+	case "false":
+		*b = flexibleBool{set: true, value: false}
+	}
+	return nil
+}
+
+func (c *Client) identity(subject string, info profile) (*entity.OAuthIdentity, error) {
+	if subject == "" {
+		return nil, ErrNoSubject
+	}
+
+	identity := &entity.OAuthIdentity{
+		Subject:       subject,
+		Email:         strings.ToLower(strings.TrimSpace(info.Email)),
+		EmailVerified: info.EmailVerified.value || (!info.EmailVerified.set && c.config.TrustEmail),
+		FirstName:     firstNonEmpty(info.GivenName, info.FirstName),
+		LastName:      firstNonEmpty(info.FamilyName, info.LastName),
+	}
+	if identity.Email == "" {
+		identity.EmailVerified = false
+	}
+	if identity.FirstName == "" && identity.LastName == "" {
+		identity.FirstName, identity.LastName, _ = strings.Cut(strings.TrimSpace(info.Name), " ")
+	}
+
+	var picture string
+	if json.Unmarshal(info.Picture, &picture) == nil && strings.HasPrefix(picture, "https://") {
+		identity.ImageURL = picture
+	}
+
+	return identity, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
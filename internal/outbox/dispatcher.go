@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"ecommerce-go-api/entity"
+)
+
+// maxAttempts is how many times an event is tried before it is dead-lettered.
+const maxAttempts = 8
+
+const (
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = time.Hour
+)
+
+// Handler reacts to an event. Handlers are retried until they succeed, so they must be
+// safe to run more than once for the same event.
+type Handler func(ctx context.Context, event *entity.OutboxEvent) error
+
+type subscription struct {
+	name   string
+	handle Handler
+}
+
+// Dispatcher delivers events to the in-process handlers subscribed to their type.
+type Dispatcher struct {
+	handlers map[string][]subscription
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string][]subscription)}
+}
+
+// Subscribe registers handler for eventType under name. The name is what marks the
+// handler as done on the event, so it must stay stable across releases.
+func (d *Dispatcher) Subscribe(eventType, name string, handler Handler) {
+	d.handlers[eventType] = append(d.handlers[eventType], subscription{name: name, handle: handler})
+}
+
+// Dispatch runs the event's handlers that have not succeeded yet and adds the ones that
+// succeed to event.CompletedHandlers. A failing handler does not stop the others.
+func (d *Dispatcher) Dispatch(ctx context.Context, event *entity.OutboxEvent) error {
+	var errs []error
+	for _, sub := range d.handlers[event.EventType] {
+		if slices.Contains(event.CompletedHandlers, sub.name) {
+			continue
+		}
+		if err := safeHandle(ctx, sub, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.name, err))
+			continue
+		}
+		event.CompletedHandlers = append(event.CompletedHandlers, sub.name)
+	}
+	return errors.Join(errs...)
+}
+
+func safeHandle(ctx context.Context, sub subscription, event *entity.OutboxEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return sub.handle(ctx, event)
+}
+
+// retryDelay doubles the wait after every failed attempt, up to maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
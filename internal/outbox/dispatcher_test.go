@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"ecommerce-go-api/entity"
+)
+
+func TestDispatch_RetryRunsOnlyFailedHandlers(t *testing.T) {
+	d := NewDispatcher()
+
+	logCalls, notifyCalls := 0, 0
+	notifyErr := errors.New("smtp down")
+	d.Subscribe(entity.EventShopOrderCancelled, "order.log", func(ctx context.Context, event *entity.OutboxEvent) error {
+		logCalls++
+		return nil
+	})
+	d.Subscribe(entity.EventShopOrderCancelled, "notify", func(ctx context.Context, event *entity.OutboxEvent) error {
+		notifyCalls++
+		return notifyErr
+	})
+
+	event := &entity.OutboxEvent{EventType: entity.EventShopOrderCancelled}
+
+	err := d.Dispatch(context.Background(), event)
+	assert.ErrorIs(t, err, notifyErr)
+	assert.Equal(t, []string{"order.log"}, event.CompletedHandlers)
+
+	notifyErr = nil
+	err = d.Dispatch(context.Background(), event)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, logCalls)
+	assert.Equal(t, 2, notifyCalls)
+	assert.Equal(t, []string{"order.log", "notify"}, event.CompletedHandlers)
+}
+
+func TestDispatch_RecoversHandlerPanic(t *testing.T) {
+	d := NewDispatcher()
+	d.Subscribe(entity.EventOrderPlaced, "broken", func(ctx context.Context, event *entity.OutboxEvent) error {
+		panic("nil map")
+	})
+
+	err := d.Dispatch(context.Background(), &entity.OutboxEvent{EventType: entity.EventOrderPlaced})
+
+	assert.ErrorContains(t, err, "panic: nil map")
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, retryDelay(1))
+	assert.Equal(t, time.Minute, retryDelay(2))
+	assert.Equal(t, 4*time.Minute, retryDelay(4))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+// NewEvent builds a pending event ready to be added to the outbox.
+func NewEvent(eventType, aggregateType string, aggregateID uuid.UUID, payload any) (*entity.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	now := timeth.Now()
+	return &entity.OutboxEvent{
+		EventType:         eventType,
+		AggregateType:     aggregateType,
+		AggregateID:       aggregateID,
+		Payload:           data,
+		Status:            entity.OutboxStatusPending,
+		NextAttemptAt:     now,
+		CompletedHandlers: []string{},
+		CreatedAt:         now,
+	}, nil
+}
+
+// Decode reads the event's payload into v.
+func Decode(event *entity.OutboxEvent, v any) error {
+	if err := json.Unmarshal(event.Payload, v); err != nil {
+		return fmt.Errorf("failed to decode %s event %s: %w", event.EventType, event.ID, err)
+	}
+	return nil
+}
+
+// ShopOrderPayload identifies a shop order in an event. so.Order must be loaded.
+func ShopOrderPayload(so *entity.ShopOrder) entity.ShopOrderPayload {
+	return entity.ShopOrderPayload{
+		ShopOrderID: so.ID,
+		OrderID:     so.OrderID,
+		ShopID:      so.ShopID,
+		UserID:      so.Order.UserID,
+		OrderNumber: so.OrderNumber,
+	}
+}
@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/timeth"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	claimBatchSize      = 100
+	claimLease          = 5 * time.Minute
+)
+
+// Relay polls the outbox for due events and hands them to the dispatcher. Every
+// instance runs one; events are claimed with SKIP LOCKED leases, so each event is
+// delivered by one instance at a time.
+type Relay struct {
+	repo       domain.OutboxRepository
+	dispatcher *Dispatcher
+	claimant   string
+	interval   time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewRelay(repo domain.OutboxRepository, dispatcher *Dispatcher) *Relay {
+	return &Relay{
+		repo:       repo,
+		dispatcher: dispatcher,
+		claimant:   newClaimant(),
+		interval:   getPollInterval(),
+		stop:       make(chan struct{}),
+	}
+}
+
+func newClaimant() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+func getPollInterval() time.Duration {
+	interval := os.Getenv("OUTBOX_POLL_INTERVAL")
+	if interval == "" {
+		return defaultPollInterval
+	}
+
+	d, err := time.ParseDuration(interval)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: Invalid OUTBOX_POLL_INTERVAL '%s'. Using default %s", interval, defaultPollInterval)
+		return defaultPollInterval
+	}
+	return d
+}
+
+func (r *Relay) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-r.stop
+			cancel()
+		}()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		log.Printf("[OUTBOX] Relay started (every %s, instance: %s)", r.interval, r.claimant)
+		for {
+			r.drain(ctx)
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the event being delivered, if any, and stops polling.
+func (r *Relay) Stop() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+// drain delivers due events batch by batch until none are left.
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := r.repo.ClaimPendingEvents(ctx, entity.JobClaim{
+			Claimant: r.claimant,
+			Limit:    claimBatchSize,
+			Lease:    claimLease,
+		})
+		if err != nil {
+			log.Printf("[OUTBOX] Error claiming events: %v", err)
+			return
+		}
+		if len(events) == 0 {
+			return
+		}
+
+		for _, event := range events {
+			if ctx.Err() != nil {
+				// Unfinished events are picked up again once their claims expire.
+				return
+			}
+			r.deliver(ctx, event)
+		}
+	}
+}
+
+func (r *Relay) deliver(ctx context.Context, event *entity.OutboxEvent) {
+	handleCtx, cancelHandle := context.WithTimeout(ctx, 30*time.Second)
+	err := r.dispatcher.Dispatch(handleCtx, event)
+	cancelHandle()
+
+	event.Attempts++
+	if err == nil {
+		now := timeth.Now()
+		event.Status = entity.OutboxStatusProcessed
+		event.ProcessedAt = &now
+		event.LastError = ""
+	} else {
+		event.LastError = err.Error()
+		if event.Attempts >= maxAttempts {
+			event.Status = entity.OutboxStatusDead
+			log.Printf("[OUTBOX] Event %s (%s) dead after %d attempts: %v", event.ID, event.EventType, event.Attempts, err)
+		} else {
+			event.NextAttemptAt = timeth.Now().Add(retryDelay(event.Attempts))
+			log.Printf("[OUTBOX] Event %s (%s) failed, attempt %d of %d: %v", event.ID, event.EventType, event.Attempts, maxAttempts, err)
+		}
+	}
+
+	finishCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.repo.FinishDelivery(finishCtx, event); err != nil {
+		log.Printf("[OUTBOX] Warning: Failed to record delivery of event %s: %v", event.ID, err)
+	}
+}
@@ -0,0 +1,251 @@
+package realtime
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+)
+
+// channel is the Postgres notification channel every instance listens on.
+const channel = "realtime_events"
+
+// maxPayloadSize is the largest notification Postgres accepts.
+const maxPayloadSize = 8000
+
+// subscriberBuffer is how many events a subscriber can fall behind before it is dropped.
+const subscriberBuffer = 32
+
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+func UserTopic(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+func ShopTopic(shopID uuid.UUID) string {
+	return "shop:" + shopID.String()
+}
+
+type notification struct {
+	Topic string                `json:"topic"`
+	Event *entity.RealtimeEvent `json:"event"`
+}
+
+// Broker fans events out to subscribers across replicas. Publish sends a Postgres
+// NOTIFY; every instance LISTENs on its own connection and hands the events to its local
+// subscribers. Events published while an instance is reconnecting are lost, so clients
+// should reload what they show when their stream reconnects.
+type Broker struct {
+	db *sql.DB
+
+	mu          sync.RWMutex
+	subscribers map[string]map[*subscription]struct{}
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func NewBroker(db *sql.DB) *Broker {
+	return &Broker{
+		db:          db,
+		subscribers: make(map[string]map[*subscription]struct{}),
+		stop:        make(chan struct{}),
+	}
+}
+
+func (b *Broker) Start() {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-b.stop
+			cancel()
+		}()
+
+		delay := minReconnectDelay
+		for {
+			connected, err := b.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			if connected {
+				delay = minReconnectDelay
+			}
+			log.Printf("[REALTIME] Listener disconnected, reconnecting in %s: %v", delay, err)
+
+			select {
+			case <-b.stop:
+				return
+			case <-time.After(delay):
+			}
+			delay = min(delay*2, maxReconnectDelay)
+		}
+	}()
+}
+
+// Stop stops listening and closes every subscription, which ends the open streams.
+func (b *Broker) Stop() {
+	b.stopOnce.Do(func() {
+		close(b.stop)
+		b.wg.Wait()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for topic, subs := range b.subscribers {
+			for sub := range subs {
+				close(sub.events)
+			}
+			delete(b.subscribers, topic)
+		}
+	})
+}
+
+// listen holds a connection out of the pool for LISTEN until it fails or ctx is
+// cancelled. It reports whether the LISTEN went through.
+func (b *Broker) listen(ctx context.Context) (bool, error) {
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	connected := false
+	var listenErr error
+	conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			listenErr = fmt.Errorf("unexpected driver connection %T", driverConn)
+			return driver.ErrBadConn
+		}
+		pgConn := c.Conn()
+
+		if _, err := pgConn.Exec(ctx, "LISTEN "+channel); err != nil {
+			listenErr = err
+			return driver.ErrBadConn
+		}
+		connected = true
+		log.Printf("[REALTIME] Listening for events on %s", channel)
+
+		for {
+			n, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				listenErr = err
+				// Never hand a connection that is still listening back to the pool.
+				return driver.ErrBadConn
+			}
+			b.dispatch(n.Payload)
+		}
+	})
+	return connected, listenErr
+}
+
+func (b *Broker) dispatch(payload string) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil || n.Event == nil {
+		log.Printf("[REALTIME] Ignoring malformed notification: %v", err)
+		return
+	}
+
+	var slow []*subscription
+	b.mu.RLock()
+	for sub := range b.subscribers[n.Topic] {
+		select {
+		case sub.events <- n.Event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range slow {
+		log.Printf("[REALTIME] Dropping subscriber of %s: it fell %d events behind", n.Topic, subscriberBuffer)
+		sub.Close()
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, topic string, event *entity.RealtimeEvent) error {
+	payload, err := json.Marshal(notification{Topic: topic, Event: event})
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxPayloadSize {
+		return fmt.Errorf("realtime event %s is %d bytes, over the %d byte limit", event.Type, len(payload), maxPayloadSize)
+	}
+
+	_, err = b.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", channel, string(payload))
+	return err
+}
+
+func (b *Broker) Subscribe(topic string) domain.RealtimeSubscription {
+	sub := &subscription{
+		broker: b,
+		topic:  topic,
+		events: make(chan *entity.RealtimeEvent, subscriberBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	select {
+	case <-b.stop:
+		// Stopping: hand out a subscription that is already over.
+		close(sub.events)
+		return sub
+	default:
+	}
+
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[*subscription]struct{})
+	}
+	b.subscribers[topic][sub] = struct{}{}
+	return sub
+}
+
+func (b *Broker) unsubscribe(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.subscribers[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(b.subscribers, sub.topic)
+	}
+	close(sub.events)
+}
+
+type subscription struct {
+	broker *Broker
+	topic  string
+	events chan *entity.RealtimeEvent
+}
+
+func (s *subscription) Events() <-chan *entity.RealtimeEvent {
+	return s.events
+}
+
+func (s *subscription) Close() {
+	s.broker.unsubscribe(s)
+}
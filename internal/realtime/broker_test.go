@@ -0,0 +1,75 @@
+package realtime
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ecommerce-go-api/entity"
+)
+
+func notify(t *testing.T, b *Broker, topic string, event *entity.RealtimeEvent) {
+	payload, err := json.Marshal(notification{Topic: topic, Event: event})
+	require.NoError(t, err)
+	b.dispatch(string(payload))
+}
+
+func TestBroker_DispatchesToTopicSubscribersOnly(t *testing.T) {
+	b := NewBroker(nil)
+	shopID := uuid.New()
+
+	shop := b.Subscribe(ShopTopic(shopID))
+	other := b.Subscribe(ShopTopic(uuid.New()))
+	defer shop.Close()
+	defer other.Close()
+
+	event := &entity.RealtimeEvent{ID: uuid.New(), Type: entity.EventOrderPlaced, Data: json.RawMessage(`{"orderNumber":"ORD-0001"}`)}
+	notify(t, b, ShopTopic(shopID), event)
+
+	select {
+	case got := <-shop.Events():
+		assert.Equal(t, event.ID, got.ID)
+		assert.JSONEq(t, `{"orderNumber":"ORD-0001"}`, string(got.Data))
+	default:
+		t.Fatal("subscriber did not get the event")
+	}
+	assert.Empty(t, other.Events())
+}
+
+func TestBroker_DropsSubscriberThatFallsBehind(t *testing.T) {
+	b := NewBroker(nil)
+	topic := UserTopic(uuid.New())
+	sub := b.Subscribe(topic)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		notify(t, b, topic, &entity.RealtimeEvent{ID: uuid.New(), Type: entity.EventShopOrderShipped})
+	}
+
+	received := 0
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	assert.Empty(t, b.subscribers)
+
+	// Closing a dropped subscription is harmless.
+	sub.Close()
+}
+
+func TestBroker_StopEndsSubscriptions(t *testing.T) {
+	b := NewBroker(nil)
+	sub := b.Subscribe(UserTopic(uuid.New()))
+
+	b.Stop()
+
+	_, open := <-sub.Events()
+	assert.False(t, open)
+
+	late := b.Subscribe(UserTopic(uuid.New()))
+	_, open = <-late.Events()
+	assert.False(t, open)
+	sub.Close()
+}
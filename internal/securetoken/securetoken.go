@@ -0,0 +1,27 @@
+// Package securetoken generates the random tokens sent to users, such as email links,
+// invitations and refresh tokens, and hashes them for storage. Only the hash is stored,
+// so a leaked database does not reveal working tokens.
+package securetoken
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Generate returns 32 random bytes, base64url encoded without padding.
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash is the hex SHA-256 of the token, the form tokens are stored and looked up in.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package shiplabel
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/boombuler/barcode/code128"
+	"github.com/jung-kurt/gofpdf"
+
+	"ecommerce-go-api/entity"
+)
+
+const (
+	// Labels are A6 (100x150mm), the size used by thermal label printers.
+	pageWidth  = 100.0
+	pageHeight = 150.0
+	margin     = 5.0
+	fontFamily = "label"
+)
+
+var (
+	fontBytes []byte
+	fontOnce  sync.Once
+)
+
+// loadFont reads the TTF font from LABEL_FONT_PATH. The PDF core fonts cannot render
+// Thai, so without a font (e.g. Sarabun or Noto Sans Thai) non-ASCII text is replaced.
+func loadFont() []byte {
+	fontOnce.Do(func() {
+		path := os.Getenv("LABEL_FONT_PATH")
+		if path == "" {
+			log.Printf("Warning: LABEL_FONT_PATH is not set. Shipping labels will not render Thai text.")
+			return
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Warning: Failed to read LABEL_FONT_PATH '%s': %v. Shipping labels will not render Thai text.", path, err)
+			return
+		}
+		fontBytes = b
+	})
+	return fontBytes
+}
+
+type document struct {
+	pdf     *gofpdf.Fpdf
+	unicode bool
+}
+
+// Render writes a PDF with a label page and a packing slip for each shop order, in order.
+func Render(w io.Writer, labels []entity.ShippingLabel) error {
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: pageWidth, Ht: pageHeight},
+	})
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(true, margin)
+
+	doc := &document{pdf: pdf}
+	if font := loadFont(); font != nil {
+		pdf.AddUTF8FontFromBytes(fontFamily, "", font)
+		pdf.AddUTF8FontFromBytes(fontFamily, "B", font)
+		doc.unicode = true
+	}
+
+	for _, l := range labels {
+		if err := doc.label(l); err != nil {
+			return err
+		}
+		doc.packingSlip(l)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to render shipping labels: %w", err)
+	}
+	return pdf.Output(w)
+}
+
+func (d *document) setFont(style string, size float64) {
+	if d.unicode {
+		d.pdf.SetFont(fontFamily, style, size)
+		return
+	}
+	d.pdf.SetFont("Helvetica", style, size)
+}
+
+// text prepares a string for the current font. Core fonts only cover Latin-1, so
+// anything else becomes '?' rather than corrupting the output.
+func (d *document) text(s string) string {
+	if d.unicode {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e {
+			return '?'
+		}
+		return r
+	}, s)
+}
+
+func (d *document) label(l entity.ShippingLabel) error {
+	pdf := d.pdf
+	pdf.AddPage()
+	contentWidth := pageWidth - 2*margin
+
+	d.setFont("B", 14)
+	pdf.CellFormat(contentWidth*0.65, 8, d.text(l.CourierName), "", 0, "L", false, 0, "")
+	d.setFont("B", 16)
+	pdf.CellFormat(contentWidth*0.35, 8, d.text(l.SortCode), "", 1, "R", false, 0, "")
+	pdf.Line(margin, pdf.GetY()+1, pageWidth-margin, pdf.GetY()+1)
+	pdf.Ln(4)
+
+	if err := d.barcode(l.TrackingNo, margin, pdf.GetY(), contentWidth, 20); err != nil {
+		return err
+	}
+	pdf.SetY(pdf.GetY() + 21)
+	d.setFont("B", 11)
+	pdf.CellFormat(contentWidth, 6, d.text(l.TrackingNo), "", 1, "C", false, 0, "")
+	pdf.Line(margin, pdf.GetY()+1, pageWidth-margin, pdf.GetY()+1)
+	pdf.Ln(3)
+
+	d.setFont("B", 9)
+	pdf.CellFormat(contentWidth, 5, "FROM", "", 1, "L", false, 0, "")
+	d.setFont("", 9)
+	d.party(l.Sender, 4.5)
+	pdf.Line(margin, pdf.GetY()+1, pageWidth-margin, pdf.GetY()+1)
+	pdf.Ln(3)
+
+	d.setFont("B", 10)
+	pdf.CellFormat(contentWidth, 6, "TO", "", 1, "L", false, 0, "")
+	d.setFont("B", 12)
+	d.party(l.Recipient, 6)
+	pdf.Line(margin, pdf.GetY()+1, pageWidth-margin, pdf.GetY()+1)
+	pdf.Ln(3)
+
+	d.setFont("", 9)
+	pdf.CellFormat(contentWidth, 5, d.text(fmt.Sprintf("Order: %s", l.OrderNumber)), "", 1, "L", false, 0, "")
+	if l.ParcelNo > 0 {
+		pdf.CellFormat(contentWidth, 5, fmt.Sprintf("Parcel: %d", l.ParcelNo), "", 1, "L", false, 0, "")
+	}
+	pdf.CellFormat(contentWidth, 5, fmt.Sprintf("Items: %d", itemCount(l.Items)), "", 1, "L", false, 0, "")
+
+	return nil
+}
+
+func (d *document) party(p entity.CourierParty, lineHeight float64) {
+	contentWidth := pageWidth - 2*margin
+	d.pdf.MultiCell(contentWidth, lineHeight, d.text(p.Name), "", "L", false)
+	if p.Phone != "" {
+		d.pdf.MultiCell(contentWidth, lineHeight, d.text(p.Phone), "", "L", false)
+	}
+	address := strings.TrimSpace(strings.Join([]string{p.Address, p.Province, p.Zipcode}, " "))
+	if address != "" {
+		d.pdf.MultiCell(contentWidth, lineHeight, d.text(address), "", "L", false)
+	}
+}
+
+// barcode draws value as a Code 128 barcode scaled to fill the given box.
+func (d *document) barcode(value string, x, y, w, h float64) error {
+	code, err := code128.Encode(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode tracking number %q as barcode: %w", value, err)
+	}
+
+	modules := code.Bounds().Dx()
+	moduleWidth := w / float64(modules)
+	d.pdf.SetFillColor(0, 0, 0)
+	for i := 0; i < modules; i++ {
+		if code.At(code.Bounds().Min.X+i, code.Bounds().Min.Y) == color.Black {
+			d.pdf.Rect(x+float64(i)*moduleWidth, y, moduleWidth, h, "F")
+		}
+	}
+	return nil
+}
+
+func (d *document) packingSlip(l entity.ShippingLabel) {
+	pdf := d.pdf
+	pdf.AddPage()
+	contentWidth := pageWidth - 2*margin
+	qtyWidth, priceWidth := 10.0, 20.0
+	nameWidth := contentWidth - qtyWidth - 2*priceWidth
+
+	d.setFont("B", 14)
+	pdf.CellFormat(contentWidth, 8, "PACKING SLIP", "", 1, "L", false, 0, "")
+	d.setFont("", 9)
+	pdf.CellFormat(contentWidth, 5, d.text(fmt.Sprintf("Order: %s", l.OrderNumber)), "", 1, "L", false, 0, "")
+	if l.ParcelNo > 0 {
+		pdf.CellFormat(contentWidth, 5, fmt.Sprintf("Parcel %d - items in this parcel only", l.ParcelNo), "", 1, "L", false, 0, "")
+	}
+	pdf.CellFormat(contentWidth, 5, fmt.Sprintf("Date: %s", l.OrderedAt.Format("02/01/2006")), "", 1, "L", false, 0, "")
+	pdf.CellFormat(contentWidth, 5, d.text(fmt.Sprintf("Ship to: %s", l.Recipient.Name)), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	d.setFont("B", 8)
+	pdf.CellFormat(nameWidth, 6, "Item", "B", 0, "L", false, 0, "")
+	pdf.CellFormat(qtyWidth, 6, "Qty", "B", 0, "R", false, 0, "")
+	pdf.CellFormat(priceWidth, 6, "Price", "B", 0, "R", false, 0, "")
+	pdf.CellFormat(priceWidth, 6, "Total", "B", 1, "R", false, 0, "")
+
+	d.setFont("", 8)
+	for _, item := range l.Items {
+		lines := pdf.SplitText(d.text(item.Name), nameWidth)
+		if len(lines) == 0 {
+			lines = []string{""}
+		}
+		for i, line := range lines {
+			pdf.CellFormat(nameWidth, 5, line, "", 0, "L", false, 0, "")
+			if i == 0 {
+				pdf.CellFormat(qtyWidth, 5, fmt.Sprintf("%d", item.Qty), "", 0, "R", false, 0, "")
+				pdf.CellFormat(priceWidth, 5, fmt.Sprintf("%.2f", item.UnitPrice), "", 0, "R", false, 0, "")
+				pdf.CellFormat(priceWidth, 5, fmt.Sprintf("%.2f", item.Subtotal), "", 1, "R", false, 0, "")
+			} else {
+				pdf.Ln(5)
+			}
+		}
+	}
+
+	pdf.Line(margin, pdf.GetY()+1, pageWidth-margin, pdf.GetY()+1)
+	pdf.Ln(2)
+	totals := []struct {
+		label string
+		value float64
+	}{
+		{"Subtotal", l.Subtotal},
+		{"Shipping", l.Shipping},
+		{"Total", l.GrandTotal},
+	}
+	for _, t := range totals {
+		pdf.CellFormat(contentWidth-priceWidth, 5, t.label, "", 0, "R", false, 0, "")
+		pdf.CellFormat(priceWidth, 5, fmt.Sprintf("%.2f", t.value), "", 1, "R", false, 0, "")
+	}
+}
+
+func itemCount(items []entity.ShippingLabelItem) uint32 {
+	var count uint32
+	for _, item := range items {
+		count += item.Qty
+	}
+	return count
+}
@@ -0,0 +1,34 @@
+package shiplabel
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"ecommerce-go-api/entity"
+)
+
+func TestRender_LabelAndPackingSlipPerOrder(t *testing.T) {
+	label := entity.ShippingLabel{
+		OrderNumber: "ORD-0001",
+		CourierName: "Kerry Express",
+		TrackingNo:  "SIM17600000001234",
+		Sender:      entity.CourierParty{Name: "Shop", Address: "1 Silom Road"},
+		Recipient:   entity.CourierParty{Name: "สมชาย ใจดี", Phone: "0812345678", Province: "กรุงเทพมหานคร", Zipcode: "10500"},
+		Items:       []entity.ShippingLabelItem{{Name: "T-Shirt", Qty: 2, UnitPrice: 199, Subtotal: 398}},
+		Subtotal:    398,
+		Shipping:    50,
+		GrandTotal:  448,
+		OrderedAt:   time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, []entity.ShippingLabel{label, label}))
+
+	out := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-")))
+	assert.Equal(t, 4, bytes.Count(out, []byte("/Type /Page\n")))
+}
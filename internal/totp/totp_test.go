@@ -0,0 +1,68 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, "12345678901234567890".
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; these are their last 6 digits.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, want := range vectors {
+		code, err := Code(rfcSecret, Step(time.Unix(unix, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, want, code, "time %d", unix)
+	}
+}
+
+func TestValidate_AcceptsOneStepOfDrift(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, err := Code(rfcSecret, step+offset)
+		require.NoError(t, err)
+
+		matched, ok := Validate(rfcSecret, code, now)
+		assert.True(t, ok, "offset %d", offset)
+		assert.Equal(t, step+offset, matched)
+	}
+
+	code, err := Code(rfcSecret, step+2)
+	require.NoError(t, err)
+	_, ok := Validate(rfcSecret, code, now)
+	assert.False(t, ok)
+
+	_, ok = Validate(rfcSecret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret_ProvisioningURI(t *testing.T) {
+	secret, err := GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	uri, err := url.Parse(ProvisioningURI("E-commerce", "somchai@example.com", secret))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/E-commerce:somchai@example.com", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "E-commerce", uri.Query().Get("issuer"))
+}
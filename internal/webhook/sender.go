@@ -0,0 +1,287 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ecommerce-go-api/domain"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/fieldcrypt"
+	"ecommerce-go-api/internal/timeth"
+)
+
+const (
+	defaultPollInterval = 5 * time.Second
+	claimBatchSize      = 50
+	claimLease          = 5 * time.Minute
+	maxWorkers          = 10
+	requestTimeout      = 10 * time.Second
+	// maxResponseBody is how much of a response body is kept in the delivery log.
+	maxResponseBody = 2048
+)
+
+// maxAttempts is how many times a delivery is tried before it is marked failed. With the
+// backoff below the last attempt is made about a day after the first.
+const maxAttempts = 10
+
+const (
+	baseRetryDelay = time.Minute
+	maxRetryDelay  = 6 * time.Hour
+)
+
+// Sender posts queued deliveries to the shops' webhooks. Every instance runs one;
+// deliveries are claimed with SKIP LOCKED leases, so each is sent by one instance at a
+// time.
+type Sender struct {
+	repo     domain.ShopWebhookRepository
+	client   *http.Client
+	claimant string
+	interval time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewSender(repo domain.ShopWebhookRepository) *Sender {
+	return &Sender{
+		repo:     repo,
+		client:   newHTTPClient(!isProduction()),
+		claimant: newClaimant(),
+		interval: getPollInterval(),
+		stop:     make(chan struct{}),
+	}
+}
+
+func newClaimant() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+func getPollInterval() time.Duration {
+	interval := os.Getenv("WEBHOOK_POLL_INTERVAL")
+	if interval == "" {
+		return defaultPollInterval
+	}
+
+	d, err := time.ParseDuration(interval)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: Invalid WEBHOOK_POLL_INTERVAL '%s'. Using default %s", interval, defaultPollInterval)
+		return defaultPollInterval
+	}
+	return d
+}
+
+// newHTTPClient does not follow redirects, and in production refuses to connect to
+// loopback, private and link-local addresses, so a webhook cannot be pointed at our
+// own network.
+func newHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+				return fmt.Errorf("address %s is not allowed", host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   requestTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (s *Sender) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-s.stop
+			cancel()
+		}()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		log.Printf("[WEBHOOK] Sender started (every %s, instance: %s)", s.interval, s.claimant)
+		for {
+			s.drain(ctx)
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the deliveries being sent, if any, and stops polling.
+func (s *Sender) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+// drain sends due deliveries batch by batch until none are left.
+func (s *Sender) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDueDeliveries(ctx, entity.JobClaim{
+			Claimant: s.claimant,
+			Limit:    claimBatchSize,
+			Lease:    claimLease,
+		})
+		if err != nil {
+			log.Printf("[WEBHOOK] Error claiming deliveries: %v", err)
+			return
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+
+		semaphore := make(chan struct{}, maxWorkers)
+		var wg sync.WaitGroup
+		for _, delivery := range deliveries {
+			wg.Add(1)
+			semaphore <- struct{}{}
+
+			go func(d *entity.WebhookDelivery) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+
+				defer func() {
+					if r := recover(); r != nil {
+						log.Printf("[WEBHOOK] Panic recovered while sending delivery %s: %v", d.ID, r)
+					}
+				}()
+
+				s.deliver(ctx, d)
+			}(delivery)
+		}
+		wg.Wait()
+	}
+}
+
+func (s *Sender) deliver(ctx context.Context, delivery *entity.WebhookDelivery) {
+	if delivery.Webhook == nil || !delivery.Webhook.IsActive {
+		// The shop turned the webhook off after the delivery was queued.
+		delivery.Status = entity.WebhookDeliveryStatusFailed
+		delivery.LastError = "webhook is inactive"
+		s.finish(delivery, nil)
+		return
+	}
+
+	attempt := s.send(ctx, delivery)
+	delivery.Attempts++
+	delivery.LastResponseStatus = attempt.ResponseStatus
+
+	if attempt.Error == "" {
+		now := timeth.Now()
+		delivery.Status = entity.WebhookDeliveryStatusSucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = attempt.Error
+		if delivery.Attempts >= maxAttempts {
+			delivery.Status = entity.WebhookDeliveryStatusFailed
+			log.Printf("[WEBHOOK] Delivery %s (%s) failed after %d attempts: %s", delivery.ID, delivery.EventType, delivery.Attempts, attempt.Error)
+		} else {
+			delivery.NextAttemptAt = timeth.Now().Add(retryDelay(delivery.Attempts))
+		}
+	}
+
+	s.finish(delivery, attempt)
+}
+
+func (s *Sender) finish(delivery *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.repo.FinishAttempt(ctx, delivery, attempt); err != nil {
+		log.Printf("[WEBHOOK] Warning: Failed to record attempt of delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send posts the delivery once and logs the outcome. Any status outside 2xx is a
+// failure.
+func (s *Sender) send(ctx context.Context, delivery *entity.WebhookDelivery) *entity.WebhookDeliveryAttempt {
+	startedAt := timeth.Now()
+	attempt := &entity.WebhookDeliveryAttempt{
+		DeliveryID: delivery.ID,
+		CreatedAt:  startedAt,
+	}
+	defer func() {
+		attempt.DurationMs = time.Since(startedAt).Milliseconds()
+	}()
+
+	secret, err := fieldcrypt.Decrypt(delivery.Webhook.Secret)
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to decrypt webhook secret: %v", err)
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ecommerce-go-api-webhooks/1.0")
+	req.Header.Set(HeaderID, delivery.ID.String())
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(startedAt.Unix(), 10))
+	req.Header.Set(HeaderSignature, Sign(secret, startedAt, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	attempt.ResponseStatus = &status
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	attempt.ResponseBody = string(body)
+
+	if status < 200 || status > 299 {
+		attempt.Error = fmt.Sprintf("endpoint responded with status %d", status)
+	}
+	return attempt
+}
+
+// retryDelay doubles the wait after every failed attempt, up to maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"ecommerce-go-api/config"
+	"ecommerce-go-api/domain/mock"
+	"ecommerce-go-api/entity"
+	"ecommerce-go-api/internal/fieldcrypt"
+)
+
+const testSecret = "whsec_test"
+
+func newTestDelivery(t *testing.T, url string) *entity.WebhookDelivery {
+	config.FIELD_ENCRYPTION_KEYS = "k1:" + base64.StdEncoding.EncodeToString(make([]byte, 32))
+	config.FIELD_ENCRYPTION_ACTIVE_KEY = "k1"
+
+	secret, err := fieldcrypt.Encrypt(testSecret)
+	assert.NoError(t, err)
+
+	return &entity.WebhookDelivery{
+		ID:        uuid.New(),
+		EventType: entity.EventOrderPlaced,
+		Payload:   []byte(`{"type":"order.placed"}`),
+		Status:    entity.WebhookDeliveryStatusPending,
+		Webhook:   &entity.ShopWebhook{URL: url, Secret: secret, IsActive: true},
+	}
+}
+
+func TestDeliver_SignsBodyAndMarksSucceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		unix, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		assert.Equal(t, Sign(testSecret, time.Unix(unix, 0), body), r.Header.Get(HeaderSignature))
+		assert.Equal(t, entity.EventOrderPlaced, r.Header.Get(HeaderEvent))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := mock.NewMockShopWebhookRepository(ctrl)
+	s := &Sender{repo: repo, client: newHTTPClient(true)}
+	delivery := newTestDelivery(t, server.URL)
+
+	repo.EXPECT().FinishAttempt(gomock.Any(), delivery, gomock.Any()).DoAndReturn(func(ctx context.Context, d *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+		assert.Equal(t, entity.WebhookDeliveryStatusSucceeded, d.Status)
+		assert.Equal(t, 1, d.Attempts)
+		assert.NotNil(t, d.DeliveredAt)
+		assert.Equal(t, http.StatusNoContent, *attempt.ResponseStatus)
+		assert.Empty(t, attempt.Error)
+		return nil
+	})
+
+	s.deliver(context.Background(), delivery)
+}
+
+func TestDeliver_ServerErrorSchedulesRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("erp is down"))
+	}))
+	defer server.Close()
+
+	repo := mock.NewMockShopWebhookRepository(ctrl)
+	s := &Sender{repo: repo, client: newHTTPClient(true)}
+	delivery := newTestDelivery(t, server.URL)
+	delivery.Attempts = 2
+
+	repo.EXPECT().FinishAttempt(gomock.Any(), delivery, gomock.Any()).DoAndReturn(func(ctx context.Context, d *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+		assert.Equal(t, entity.WebhookDeliveryStatusPending, d.Status)
+		assert.Equal(t, 3, d.Attempts)
+		assert.WithinDuration(t, time.Now().Add(4*time.Minute), d.NextAttemptAt, 5*time.Second)
+		assert.Equal(t, "erp is down", attempt.ResponseBody)
+		assert.Contains(t, d.LastError, "status 500")
+		return nil
+	})
+
+	s.deliver(context.Background(), delivery)
+}
+
+func TestDeliver_BlocksPrivateAddressesInProduction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback address")
+	}))
+	defer server.Close()
+
+	repo := mock.NewMockShopWebhookRepository(ctrl)
+	s := &Sender{repo: repo, client: newHTTPClient(false)}
+	delivery := newTestDelivery(t, server.URL)
+
+	repo.EXPECT().FinishAttempt(gomock.Any(), delivery, gomock.Any()).DoAndReturn(func(ctx context.Context, d *entity.WebhookDelivery, attempt *entity.WebhookDeliveryAttempt) error {
+		assert.Nil(t, attempt.ResponseStatus)
+		assert.Contains(t, attempt.Error, "is not allowed")
+		return nil
+	})
+
+	s.deliver(context.Background(), delivery)
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"ecommerce-go-api/internal/errmap"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+)
+
+// NewSecret returns a random signing secret for a webhook.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Sign returns the X-Webhook-Signature value for body sent at timestamp:
+// "sha256=" + hex(HMAC-SHA256(secret, "<unix timestamp>.<body>")). Signing the timestamp
+// lets receivers reject replayed requests.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckURL rejects webhook URLs the sender would not post to. Outside production plain
+// http is allowed, so shops can test against local endpoints.
+func CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%w: invalid url", errmap.ErrInvalidRequest)
+	}
+
+	switch u.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if !isProduction() {
+			return nil
+		}
+	}
+	return errmap.ErrWebhookURLInsecure
+}
+
+func isProduction() bool {
+	return os.Getenv("APP_ENV") == "production"
+}
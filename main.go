@@ -19,16 +19,43 @@ import (
 	authDelivery "ecommerce-go-api/feature/auth/delivery"
 	cartDelivery "ecommerce-go-api/feature/cart/delivery"
 	courierDelivery "ecommerce-go-api/feature/courier/delivery"
+	jobDelivery "ecommerce-go-api/feature/job/delivery"
 	locationDelivery "ecommerce-go-api/feature/location/delivery"
+	notificationDelivery "ecommerce-go-api/feature/notification/delivery"
 	orderDelivery "ecommerce-go-api/feature/order/delivery"
+	outboxDelivery "ecommerce-go-api/feature/outbox/delivery"
 	productDelivery "ecommerce-go-api/feature/product/delivery"
+	realtimeDelivery "ecommerce-go-api/feature/realtime/delivery"
 	refundDelivery "ecommerce-go-api/feature/refund/delivery"
 	shopDelivery "ecommerce-go-api/feature/shop/delivery"
+	shopMemberDelivery "ecommerce-go-api/feature/shopmember/delivery"
 	userDelivery "ecommerce-go-api/feature/user/delivery"
-
+	webhookDelivery "ecommerce-go-api/feature/webhook/delivery"
+
+	"ecommerce-go-api/feature/courier/estimate"
+	"ecommerce-go-api/feature/courier/provider"
+	courierRepo "ecommerce-go-api/feature/courier/repository"
+	jobRepo "ecommerce-go-api/feature/job/repository"
+	notificationEvents "ecommerce-go-api/feature/notification/events"
+	notificationRepo "ecommerce-go-api/feature/notification/repository"
 	orderRepo "ecommerce-go-api/feature/order/repository"
+	orderUsecase "ecommerce-go-api/feature/order/usecase"
+	outboxRepo "ecommerce-go-api/feature/outbox/repository"
+	productEvents "ecommerce-go-api/feature/product/events"
 	productRepo "ecommerce-go-api/feature/product/repository"
+	realtimeEvents "ecommerce-go-api/feature/realtime/events"
+	shopRepo "ecommerce-go-api/feature/shop/repository"
+	userRepo "ecommerce-go-api/feature/user/repository"
+	webhookEvents "ecommerce-go-api/feature/webhook/events"
+	webhookRepo "ecommerce-go-api/feature/webhook/repository"
 	"ecommerce-go-api/internal/cron"
+	"ecommerce-go-api/internal/dbtx"
+	"ecommerce-go-api/internal/fieldcrypt"
+	"ecommerce-go-api/internal/jwt"
+	"ecommerce-go-api/internal/notify"
+	"ecommerce-go-api/internal/outbox"
+	"ecommerce-go-api/internal/realtime"
+	"ecommerce-go-api/internal/webhook"
 
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
@@ -44,6 +71,11 @@ import (
 func init() {
 	config.InitialENV()
 	config.ConnectDatabase()
+	// Fail at startup rather than on the first login or refund read if the keys are invalid.
+	if err := jwt.Init(config.JWT_SIGNING_KEYS, config.JWT_ACTIVE_KEY); err != nil {
+		log.Fatalf("FATAL: JWT signing is not configured: %v", err)
+	}
+	fieldcrypt.Default()
 }
 
 func main() {
@@ -68,6 +100,8 @@ func main() {
 		e.GET("/swagger/*", echoSwagger.WrapHandler)
 	}
 
+	authDelivery.RegisterWellKnownRoutes(e)
+
 	e.GET("/health", func(c echo.Context) error {
 		ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
 		defer cancel()
@@ -91,7 +125,15 @@ func main() {
 
 	oRepo := orderRepo.NewOrderRepository(db)
 	pRepo := productRepo.NewProductRepository(db)
-	scheduler, err := cron.NewScheduler(oRepo, pRepo)
+	obRepo := outboxRepo.NewOutboxRepository(db)
+	uRepo := userRepo.NewUserRepository(db)
+	transactor := dbtx.NewTransactor(db)
+	oUsecase := orderUsecase.NewOrderUsecase(oRepo, shopRepo.NewShopRepository(db), pRepo, uRepo, provider.Default(), estimate.NewEstimator(courierRepo.NewCourierRepository(db)), obRepo, transactor, config.REQUIRE_EMAIL_VERIFICATION)
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Failed to get database handle: %v", err)
+	}
+	scheduler, err := cron.NewScheduler(oRepo, obRepo, transactor, oUsecase, jobRepo.NewJobRepository(db), cron.NewAdvisoryLocker(sqlDB))
 	if err != nil {
 		log.Fatalf("Failed to create scheduler: %v", err)
 	}
@@ -100,6 +142,31 @@ func main() {
 	}
 	defer scheduler.Stop()
 
+	whRepo := webhookRepo.NewShopWebhookRepository(db)
+	nRepo := notificationRepo.NewNotificationRepository(db)
+
+	// Stopped when the server starts shutting down, so open streams do not hold it up.
+	broker := realtime.NewBroker(sqlDB)
+	broker.Start()
+	e.Server.RegisterOnShutdown(broker.Stop)
+
+	dispatcher := outbox.NewDispatcher()
+	productEvents.RegisterHandlers(dispatcher, pRepo, transactor)
+	webhookEvents.RegisterHandlers(dispatcher, whRepo, oRepo)
+	notificationEvents.RegisterHandlers(dispatcher, nRepo, oRepo, uRepo)
+	realtimeEvents.RegisterHandlers(dispatcher, broker, oRepo)
+	relay := outbox.NewRelay(obRepo, dispatcher)
+	relay.Start()
+	defer relay.Stop()
+
+	webhookSender := webhook.NewSender(whRepo)
+	webhookSender.Start()
+	defer webhookSender.Stop()
+
+	emailSender := notify.NewSender(nRepo, notify.NewNotifierFromEnv())
+	emailSender.Start()
+	defer emailSender.Stop()
+
 	api := e.Group("/api")
 	{
 		authDelivery.RegisterAuthHandler(api, db)
@@ -111,6 +178,12 @@ func main() {
 		orderDelivery.RegisterOrderHandler(api, db)
 		courierDelivery.RegisterCourierHandler(api, db)
 		refundDelivery.RegisterRefundHandler(api, db)
+		jobDelivery.RegisterJobHandler(api, db, scheduler)
+		outboxDelivery.RegisterOutboxHandler(api, db)
+		webhookDelivery.RegisterShopWebhookHandler(api, db)
+		shopMemberDelivery.RegisterShopMemberHandler(api, db)
+		notificationDelivery.RegisterNotificationHandler(api, db)
+		realtimeDelivery.RegisterRealtimeHandler(api, db, broker)
 	}
 
 	utils.ServeGracefulShutdown(e)
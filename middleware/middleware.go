@@ -4,10 +4,12 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"ecommerce-go-api/internal/errmap"
 	"ecommerce-go-api/internal/jwt"
 	"ecommerce-go-api/internal/response"
+	"ecommerce-go-api/internal/timeth"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -45,12 +47,19 @@ func JWTAuth() echo.MiddlewareFunc {
 
 			c.Set("userId", claims.UserID)
 			c.Set("role", claims.Role)
+			c.Set("roles", claims.AllRoles())
+			c.Set("sessionId", claims.SessionID)
+			c.Set("twoFactor", claims.TwoFactor)
+			if claims.TwoFactorAt != nil {
+				c.Set("twoFactorAt", claims.TwoFactorAt.Time)
+			}
 
 			return next(c)
 		}
 	}
 }
 
+// RoleAuth admits tokens that carry any of the allowed roles.
 func RoleAuth(allowedRoles ...string) echo.MiddlewareFunc {
 	roleSet := make(map[string]struct{}, len(allowedRoles))
 	for _, r := range allowedRoles {
@@ -59,21 +68,18 @@ func RoleAuth(allowedRoles ...string) echo.MiddlewareFunc {
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			roleKey := c.Get("role")
-			if roleKey == nil {
-				return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
-			}
-
-			role, ok := roleKey.(string)
-			if !ok || role == "" {
+			roles, ok := c.Get("roles").([]string)
+			if !ok || len(roles) == 0 {
 				return response.Error(c, http.StatusUnauthorized, errmap.ErrUnauthorized.Error())
 			}
 
-			if _, exists := roleSet[role]; !exists {
-				return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
+			for _, role := range roles {
+				if _, exists := roleSet[role]; exists {
+					return next(c)
+				}
 			}
 
-			return next(c)
+			return response.Error(c, http.StatusForbidden, errmap.ErrForbidden.Error())
 		}
 	}
 }
@@ -97,6 +103,40 @@ func GetUserID(c echo.Context) (uuid.UUID, error) {
 	}
 }
 
+// GetSessionID returns the session the access token was issued for, or uuid.Nil for
+// tokens issued before sessions existed.
+func GetSessionID(c echo.Context) uuid.UUID {
+	sessionID, _ := c.Get("sessionId").(uuid.UUID)
+	return sessionID
+}
+
+// TwoFactorReverifyWindow is how long after entering a two-factor code the user may
+// perform sensitive actions without entering another one.
+const TwoFactorReverifyWindow = 10 * time.Minute
+
+// RequireTwoFactor guards sensitive actions. Users with two-factor authentication must
+// have entered a code within TwoFactorReverifyWindow, and users an admin requires it of
+// must set it up first. Users without it pass. Use it after JWTAuth.
+func RequireTwoFactor() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch twoFactor, _ := c.Get("twoFactor").(string); twoFactor {
+			case jwt.TwoFactorSetupRequired:
+				return response.Error(c, http.StatusForbidden, errmap.ErrTwoFactorSetupRequired.Error())
+			case jwt.TwoFactorEnabled:
+				verifiedAt, _ := c.Get("twoFactorAt").(time.Time)
+				if verifiedAt.IsZero() || timeth.Now().Sub(verifiedAt) > TwoFactorReverifyWindow {
+					return response.Error(c, http.StatusForbidden, errmap.ErrTwoFactorReverifyRequired.Error())
+				}
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// ShopOwnerOnly admits shop accounts: shop owners and the staff they invited. What each
+// member may do in the shop is checked against their member role.
 func ShopOwnerOnly() echo.MiddlewareFunc {
 	return RoleAuth("SHOP")
 }
@@ -108,3 +148,7 @@ func UserOnly() echo.MiddlewareFunc {
 func ShopOrUser() echo.MiddlewareFunc {
 	return RoleAuth("SHOP", "USER")
 }
+
+func AdminOnly() echo.MiddlewareFunc {
+	return RoleAuth("ADMIN")
+}
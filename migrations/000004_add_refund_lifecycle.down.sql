@@ -0,0 +1,20 @@
+-- ===================================
+-- Rollback: Remove Refund Lifecycle
+-- Version: 000004
+-- ===================================
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_order_logs_refund_id;
+DROP INDEX IF EXISTS idx_refunds_payment_id;
+
+ALTER TABLE order_logs
+    DROP COLUMN IF EXISTS refund_status_id,
+    DROP COLUMN IF EXISTS refund_id;
+
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS rejected_at,
+    DROP COLUMN IF EXISTS approved_at,
+    DROP COLUMN IF EXISTS reject_reason;
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- ===================================
+-- Migration: Add Refund Lifecycle
+-- Version: 000004
+-- Description: Track refund approval/rejection and link refund events to the order timeline
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE refunds
+    ADD COLUMN IF NOT EXISTS reject_reason TEXT,
+    ADD COLUMN IF NOT EXISTS approved_at TIMESTAMPTZ(6),
+    ADD COLUMN IF NOT EXISTS rejected_at TIMESTAMPTZ(6);
+
+ALTER TABLE order_logs
+    ADD COLUMN IF NOT EXISTS refund_id UUID REFERENCES refunds(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS refund_status_id INTEGER REFERENCES refund_status(id);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_payment_id ON refunds(payment_id);
+CREATE INDEX IF NOT EXISTS idx_order_logs_refund_id ON order_logs(refund_id);
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- ===================================
+-- Rollback: Remove Buyer Refund Requests
+-- Version: 000005
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS refund_evidences CASCADE;
+
+ALTER TABLE refunds DROP CONSTRAINT IF EXISTS refunds_counter_amount_positive;
+
+ALTER TABLE refunds
+    DROP COLUMN IF EXISTS resolution_note,
+    DROP COLUMN IF EXISTS escalated_by,
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS response_due_at,
+    DROP COLUMN IF EXISTS counter_note,
+    DROP COLUMN IF EXISTS counter_amount,
+    DROP COLUMN IF EXISTS requested_by,
+    DROP COLUMN IF EXISTS reason_code,
+    DROP COLUMN IF EXISTS initiator;
+
+DELETE FROM refund_status WHERE id IN (5, 6);
+
+COMMIT;
@@ -0,0 +1,42 @@
+-- ===================================
+-- Migration: Add Buyer Refund Requests
+-- Version: 000005
+-- Description: Buyer-initiated refunds with evidence, counter-offers and admin mediation
+-- ===================================
+
+BEGIN;
+
+INSERT INTO refund_status (id, code, name) VALUES
+  (5, 'COUNTER_OFFERED', 'ร้านค้าเสนอยอดคืนเงินใหม่'),
+  (6, 'ESCALATED', 'รอผู้ดูแลระบบพิจารณา')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE refunds
+    ADD COLUMN IF NOT EXISTS initiator VARCHAR(20) NOT NULL DEFAULT 'SHOP',
+    ADD COLUMN IF NOT EXISTS reason_code VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS requested_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS counter_amount NUMERIC(10, 2),
+    ADD COLUMN IF NOT EXISTS counter_note TEXT,
+    ADD COLUMN IF NOT EXISTS response_due_at TIMESTAMPTZ(6),
+    ADD COLUMN IF NOT EXISTS escalated_at TIMESTAMPTZ(6),
+    ADD COLUMN IF NOT EXISTS escalated_by UUID REFERENCES users(id),
+    ADD COLUMN IF NOT EXISTS resolution_note TEXT;
+
+ALTER TABLE refunds
+    ADD CONSTRAINT refunds_counter_amount_positive CHECK (counter_amount IS NULL OR counter_amount >= 0);
+
+-- Refund Evidences
+CREATE TABLE IF NOT EXISTS refund_evidences (
+    id SERIAL PRIMARY KEY,
+    refund_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    note TEXT,
+    uploaded_by UUID,
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (refund_id) REFERENCES refunds(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refund_evidences_refund_id ON refund_evidences(refund_id);
+
+COMMIT;
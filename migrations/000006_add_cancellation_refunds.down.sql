@@ -0,0 +1,14 @@
+-- ===================================
+-- Rollback: Remove Cancellation Refunds
+-- Version: 000006
+-- ===================================
+
+BEGIN;
+
+UPDATE payments SET payment_status_id = 6 WHERE payment_status_id = 8;
+UPDATE refunds SET refund_method_id = 1 WHERE refund_method_id = 3;
+
+DELETE FROM payment_status WHERE id = 8;
+DELETE FROM refund_methods WHERE id = 3;
+
+COMMIT;
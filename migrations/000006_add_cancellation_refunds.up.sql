@@ -0,0 +1,17 @@
+-- ===================================
+-- Migration: Add Cancellation Refunds
+-- Version: 000006
+-- Description: Partially refunded payment status and PromptPay refund method for automatic refunds on cancellation
+-- ===================================
+
+BEGIN;
+
+INSERT INTO payment_status (id, code, name) VALUES
+  (8, 'PARTIALLY_REFUNDED', 'คืนเงินบางส่วน')
+ON CONFLICT (id) DO NOTHING;
+
+INSERT INTO refund_methods (id, code, name) VALUES
+  (3, 'PROMPTPAY', 'คืนเงินผ่านพร้อมเพย์')
+ON CONFLICT (id) DO NOTHING;
+
+COMMIT;
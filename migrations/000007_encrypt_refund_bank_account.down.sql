@@ -0,0 +1,13 @@
+-- ===================================
+-- Rollback: Encrypt Refund Bank Account
+-- Version: 000007
+-- Note: encrypted values do not fit VARCHAR(100) with long key ids; decrypt before rolling back.
+-- ===================================
+
+BEGIN;
+
+COMMENT ON COLUMN refunds.bank_account IS NULL;
+
+ALTER TABLE refunds ALTER COLUMN bank_account TYPE VARCHAR(100);
+
+COMMIT;
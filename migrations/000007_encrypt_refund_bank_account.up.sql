@@ -0,0 +1,13 @@
+-- ===================================
+-- Migration: Encrypt Refund Bank Account
+-- Version: 000007
+-- Description: Widen refunds.bank_account to hold AES-GCM ciphertext (run `make reencrypt` to encrypt existing rows)
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE refunds ALTER COLUMN bank_account TYPE TEXT;
+
+COMMENT ON COLUMN refunds.bank_account IS 'Encrypted: enc:<key id>:<base64 nonce+ciphertext>';
+
+COMMIT;
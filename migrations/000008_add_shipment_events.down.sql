@@ -0,0 +1,17 @@
+-- ===================================
+-- Rollback: Remove Shipment Events
+-- Version: 000008
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS shipment_events CASCADE;
+
+ALTER TABLE couriers DROP COLUMN IF EXISTS webhook_secret;
+
+UPDATE shipments SET shipment_status_id = 1 WHERE shipment_status_id IN (4, 5);
+UPDATE shipments SET shipment_status_id = 3 WHERE shipment_status_id = 6;
+
+DELETE FROM shipment_status WHERE id IN (4, 5, 6);
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- ===================================
+-- Migration: Add Shipment Events
+-- Version: 000008
+-- Description: Shipment tracking history fed by courier webhooks
+-- ===================================
+
+BEGIN;
+
+INSERT INTO shipment_status (id, code, name) VALUES
+  (4, 'PICKED_UP', 'เข้ารับพัสดุแล้ว'),
+  (5, 'OUT_FOR_DELIVERY', 'กำลังนำจ่าย'),
+  (6, 'RETURNED_TO_SENDER', 'ตีกลับถึงผู้ส่ง')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE couriers
+    ADD COLUMN IF NOT EXISTS webhook_secret TEXT;
+
+-- Shipment Events
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id SERIAL PRIMARY KEY,
+    shipment_id UUID NOT NULL,
+    shipment_status_id INTEGER NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    external_event_id VARCHAR(100),
+    description TEXT,
+    location VARCHAR(255),
+    occurred_at TIMESTAMPTZ(6) NOT NULL,
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (shipment_id) REFERENCES shipments(id) ON DELETE CASCADE,
+    FOREIGN KEY (shipment_status_id) REFERENCES shipment_status(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_events_shipment_id ON shipment_events(shipment_id, occurred_at);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_shipment_events_external_event_id
+    ON shipment_events(shipment_id, external_event_id)
+    WHERE external_event_id IS NOT NULL;
+
+-- Backfill history for shipments created before events existed
+INSERT INTO shipment_events (shipment_id, shipment_status_id, source, description, occurred_at)
+SELECT id, 1, 'SHOP', 'Shipment created', COALESCE(shipped_at, created_at)
+FROM shipments;
+
+INSERT INTO shipment_events (shipment_id, shipment_status_id, source, description, occurred_at)
+SELECT id, 2, 'SHOP', 'Marked as delivered', COALESCE(delivered_at, updated_at)
+FROM shipments
+WHERE shipment_status_id = 2;
+
+COMMIT;
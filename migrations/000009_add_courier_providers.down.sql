@@ -0,0 +1,14 @@
+-- ===================================
+-- Rollback: Remove Courier Providers
+-- Version: 000009
+-- ===================================
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_shipments_provider_sync;
+
+ALTER TABLE shipments DROP COLUMN IF EXISTS provider_code;
+
+ALTER TABLE couriers DROP COLUMN IF EXISTS provider_code;
+
+COMMIT;
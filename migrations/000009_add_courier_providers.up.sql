@@ -0,0 +1,24 @@
+-- ===================================
+-- Migration: Add Courier Providers
+-- Version: 000009
+-- Description: Courier booking integrations and shipments booked through them
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE couriers
+    ADD COLUMN IF NOT EXISTS provider_code VARCHAR(50);
+
+ALTER TABLE shipments
+    ADD COLUMN IF NOT EXISTS provider_code VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_shipments_provider_sync
+    ON shipments(updated_at)
+    WHERE provider_code IS NOT NULL;
+
+-- No real courier API is integrated yet, so the seeded couriers book through the simulator.
+UPDATE couriers SET provider_code = 'SIMULATOR'
+WHERE provider_code IS NULL
+  AND name IN ('Kerry Express', 'Flash Express', 'Thailand Post', 'J&T Express', 'SCG Express');
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- ===================================
+-- Rollback: Remove Shipment Items
+-- Version: 000010
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS shipment_items CASCADE;
+
+COMMIT;
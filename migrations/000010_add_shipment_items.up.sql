@@ -0,0 +1,29 @@
+-- ===================================
+-- Migration: Add Shipment Items
+-- Version: 000010
+-- Description: Multiple parcels per shop order, each covering specific order items
+-- ===================================
+
+BEGIN;
+
+-- Shipment Items
+CREATE TABLE IF NOT EXISTS shipment_items (
+    id SERIAL PRIMARY KEY,
+    shipment_id UUID NOT NULL,
+    order_item_id INTEGER NOT NULL,
+    qty INTEGER NOT NULL CHECK (qty > 0),
+    FOREIGN KEY (shipment_id) REFERENCES shipments(id) ON DELETE CASCADE,
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id) ON DELETE CASCADE,
+    UNIQUE (shipment_id, order_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_items_order_item_id ON shipment_items(order_item_id);
+
+-- Existing shipments were the only parcel of their shop order, so they carry every item.
+INSERT INTO shipment_items (shipment_id, order_item_id, qty)
+SELECT s.id, oi.id, oi.qty
+FROM shipments s
+JOIN order_items oi ON oi.shop_order_id = s.shop_order_id
+ON CONFLICT (shipment_id, order_item_id) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- ===================================
+-- Rollback: Remove Delivery Reattempts
+-- Version: 000011
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS delivery_reattempts CASCADE;
+
+ALTER TABLE shipments DROP COLUMN IF EXISTS failed_attempts;
+
+COMMIT;
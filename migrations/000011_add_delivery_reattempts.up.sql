@@ -0,0 +1,35 @@
+-- ===================================
+-- Migration: Add Delivery Reattempts
+-- Version: 000011
+-- Description: Failed delivery attempts per parcel and buyer-scheduled redeliveries
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE shipments ADD COLUMN IF NOT EXISTS failed_attempts INTEGER NOT NULL DEFAULT 0;
+
+-- Delivery Reattempts
+CREATE TABLE IF NOT EXISTS delivery_reattempts (
+    id SERIAL PRIMARY KEY,
+    shipment_id UUID NOT NULL,
+    requested_by UUID NOT NULL,
+    scheduled_date DATE NOT NULL,
+    address_id INTEGER,
+    shipping_name TEXT,
+    shipping_phone VARCHAR(15),
+    shipping_line1 TEXT,
+    shipping_line2 TEXT,
+    shipping_sub_district TEXT,
+    shipping_district TEXT,
+    shipping_province TEXT,
+    shipping_zipcode VARCHAR(10),
+    note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (shipment_id) REFERENCES shipments(id) ON DELETE CASCADE,
+    FOREIGN KEY (requested_by) REFERENCES users(id),
+    FOREIGN KEY (address_id) REFERENCES addresses(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_reattempts_shipment_id ON delivery_reattempts(shipment_id);
+
+COMMIT;
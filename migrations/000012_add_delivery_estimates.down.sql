@@ -0,0 +1,19 @@
+-- ===================================
+-- Rollback: Remove Delivery Estimates
+-- Version: 000012
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS holidays CASCADE;
+DROP TABLE IF EXISTS courier_transit_times CASCADE;
+
+ALTER TABLE shop_orders
+    DROP COLUMN IF EXISTS estimated_delivery_to,
+    DROP COLUMN IF EXISTS estimated_delivery_from;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS handling_days,
+    DROP COLUMN IF EXISTS province_id;
+
+COMMIT;
@@ -0,0 +1,81 @@
+-- ===================================
+-- Migration: Add Delivery Estimates
+-- Version: 000012
+-- Description: Shop origin province and handling time, courier transit times, holiday calendar and estimated delivery windows on shop orders
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE shops
+    ADD COLUMN IF NOT EXISTS province_id INTEGER REFERENCES provinces(id),
+    ADD COLUMN IF NOT EXISTS handling_days INTEGER NOT NULL DEFAULT 1 CHECK (handling_days >= 0);
+
+ALTER TABLE shop_orders
+    ADD COLUMN IF NOT EXISTS estimated_delivery_from DATE,
+    ADD COLUMN IF NOT EXISTS estimated_delivery_to DATE;
+
+-- Courier Transit Times
+-- A NULL province matches any province. Routes without a rule use the built-in defaults.
+CREATE TABLE IF NOT EXISTS courier_transit_times (
+    id SERIAL PRIMARY KEY,
+    courier_id INTEGER NOT NULL,
+    origin_province_id INTEGER,
+    destination_province_id INTEGER,
+    min_days INTEGER NOT NULL CHECK (min_days >= 0),
+    max_days INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (courier_id) REFERENCES couriers(id) ON DELETE CASCADE,
+    FOREIGN KEY (origin_province_id) REFERENCES provinces(id) ON DELETE CASCADE,
+    FOREIGN KEY (destination_province_id) REFERENCES provinces(id) ON DELETE CASCADE,
+    CONSTRAINT courier_transit_times_days_range CHECK (max_days >= min_days)
+);
+
+CREATE INDEX IF NOT EXISTS idx_courier_transit_times_courier_id ON courier_transit_times(courier_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_courier_transit_times_route
+    ON courier_transit_times(courier_id, COALESCE(origin_province_id, 0), COALESCE(destination_province_id, 0));
+
+-- Holidays
+CREATE TABLE IF NOT EXISTS holidays (
+    date DATE PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Thai public holidays with fixed dates. Lunar holidays (Makha Bucha, Visakha Bucha,
+-- Asarnha Bucha, Buddhist Lent) and substitution days change every year and are added
+-- through /api/admin/holidays once the government announces them.
+INSERT INTO holidays (date, name) VALUES
+    ('2026-01-01', 'New Year''s Day'),
+    ('2026-04-06', 'Chakri Memorial Day'),
+    ('2026-04-13', 'Songkran Festival'),
+    ('2026-04-14', 'Songkran Festival'),
+    ('2026-04-15', 'Songkran Festival'),
+    ('2026-05-01', 'National Labour Day'),
+    ('2026-05-04', 'Coronation Day'),
+    ('2026-06-03', 'H.M. Queen Suthida''s Birthday'),
+    ('2026-07-28', 'H.M. King Maha Vajiralongkorn''s Birthday'),
+    ('2026-08-12', 'H.M. Queen Sirikit The Queen Mother''s Birthday / Mother''s Day'),
+    ('2026-10-13', 'King Bhumibol Adulyadej Memorial Day'),
+    ('2026-10-23', 'King Chulalongkorn Memorial Day'),
+    ('2026-12-05', 'King Bhumibol Adulyadej''s Birthday / Father''s Day'),
+    ('2026-12-10', 'Constitution Day'),
+    ('2026-12-31', 'New Year''s Eve'),
+    ('2027-01-01', 'New Year''s Day'),
+    ('2027-04-06', 'Chakri Memorial Day'),
+    ('2027-04-13', 'Songkran Festival'),
+    ('2027-04-14', 'Songkran Festival'),
+    ('2027-04-15', 'Songkran Festival'),
+    ('2027-05-01', 'National Labour Day'),
+    ('2027-05-04', 'Coronation Day'),
+    ('2027-06-03', 'H.M. Queen Suthida''s Birthday'),
+    ('2027-07-28', 'H.M. King Maha Vajiralongkorn''s Birthday'),
+    ('2027-08-12', 'H.M. Queen Sirikit The Queen Mother''s Birthday / Mother''s Day'),
+    ('2027-10-13', 'King Bhumibol Adulyadej Memorial Day'),
+    ('2027-10-23', 'King Chulalongkorn Memorial Day'),
+    ('2027-12-05', 'King Bhumibol Adulyadej''s Birthday / Father''s Day'),
+    ('2027-12-10', 'Constitution Day'),
+    ('2027-12-31', 'New Year''s Eve')
+ON CONFLICT (date) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- ===================================
+-- Rollback: Remove Order Auto-Complete Settings
+-- Version: 000013
+-- ===================================
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_shop_orders_status_delivered_at;
+
+ALTER TABLE shop_orders
+    DROP COLUMN IF EXISTS auto_complete_reminded_at,
+    DROP COLUMN IF EXISTS delivered_at;
+
+ALTER TABLE shops
+    DROP COLUMN IF EXISTS auto_complete_days;
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- ===================================
+-- Migration: Add Order Auto-Complete Settings
+-- Version: 000013
+-- Description: Per-shop auto-complete window, delivered time and pre-completion reminder tracking on shop orders
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE shops
+    ADD COLUMN IF NOT EXISTS auto_complete_days INTEGER CHECK (auto_complete_days BETWEEN 2 AND 30);
+
+ALTER TABLE shop_orders
+    ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMPTZ(6),
+    ADD COLUMN IF NOT EXISTS auto_complete_reminded_at TIMESTAMPTZ(6);
+
+-- Orders delivered before this migration start their window from the last status change.
+UPDATE shop_orders
+SET delivered_at = updated_at
+WHERE order_status_id = 4
+  AND delivered_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_shop_orders_status_delivered_at ON shop_orders(order_status_id, delivered_at);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- ===================================
+-- Rollback: Remove Job Runs
+-- Version: 000014
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS job_states CASCADE;
+DROP TABLE IF EXISTS job_runs CASCADE;
+
+COMMIT;
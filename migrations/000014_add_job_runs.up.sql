@@ -0,0 +1,36 @@
+-- ===================================
+-- Migration: Add Job Runs
+-- Version: 000014
+-- Description: Run history of scheduled jobs and persisted pause state for admin job controls
+-- ===================================
+
+BEGIN;
+
+-- Job Runs
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('SCHEDULED', 'MANUAL')),
+    triggered_by UUID REFERENCES users(id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('RUNNING', 'SUCCEEDED', 'PARTIAL', 'FAILED')),
+    started_at TIMESTAMPTZ(6) NOT NULL,
+    finished_at TIMESTAMPTZ(6),
+    processed INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    error_samples JSONB,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job_name_started_at ON job_runs(job_name, started_at DESC);
+CREATE INDEX IF NOT EXISTS idx_job_runs_started_at ON job_runs(started_at DESC);
+
+-- Job States
+CREATE TABLE IF NOT EXISTS job_states (
+    job_name VARCHAR(100) PRIMARY KEY,
+    paused BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+COMMIT;
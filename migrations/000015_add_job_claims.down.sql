@@ -0,0 +1,21 @@
+-- ===================================
+-- Rollback: Remove Job Claims
+-- Version: 000015
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE job_runs
+    DROP COLUMN IF EXISTS instance_id;
+
+DROP INDEX IF EXISTS idx_payments_pending_expires_at;
+
+ALTER TABLE shop_orders
+    DROP COLUMN IF EXISTS claimed_until,
+    DROP COLUMN IF EXISTS claimed_by;
+
+ALTER TABLE payments
+    DROP COLUMN IF EXISTS claimed_until,
+    DROP COLUMN IF EXISTS claimed_by;
+
+COMMIT;
@@ -0,0 +1,24 @@
+-- ===================================
+-- Migration: Add Job Claims
+-- Version: 000015
+-- Description: Row leases for multi-instance job processing and the instance that ran each job
+-- ===================================
+
+BEGIN;
+
+-- A scheduler instance leases the rows it is about to process. Other instances skip
+-- rows that are locked or still leased.
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS claimed_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ(6);
+
+ALTER TABLE shop_orders
+    ADD COLUMN IF NOT EXISTS claimed_by VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMPTZ(6);
+
+CREATE INDEX IF NOT EXISTS idx_payments_pending_expires_at ON payments(expires_at) WHERE payment_status_id = 1;
+
+ALTER TABLE job_runs
+    ADD COLUMN IF NOT EXISTS instance_id VARCHAR(100) NOT NULL DEFAULT '';
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- ===================================
+-- Rollback: Remove Outbox Events
+-- Version: 000016
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS outbox_events CASCADE;
+
+COMMIT;
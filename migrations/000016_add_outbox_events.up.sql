@@ -0,0 +1,31 @@
+-- ===================================
+-- Migration: Add Outbox Events
+-- Version: 000016
+-- Description: Transactional outbox of domain events, delivered to in-process handlers with retries and dead-lettering
+-- ===================================
+
+BEGIN;
+
+-- Outbox Events
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'PROCESSED', 'DEAD')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    completed_handlers JSONB NOT NULL DEFAULT '[]',
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMPTZ(6)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_outbox_events_status_created_at ON outbox_events(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_aggregate ON outbox_events(aggregate_type, aggregate_id);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- ===================================
+-- Rollback: Remove Shop Webhooks
+-- Version: 000017
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS webhook_delivery_attempts CASCADE;
+DROP TABLE IF EXISTS webhook_deliveries CASCADE;
+DROP TABLE IF EXISTS shop_webhooks CASCADE;
+
+COMMIT;
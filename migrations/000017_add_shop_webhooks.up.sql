@@ -0,0 +1,59 @@
+-- ===================================
+-- Migration: Add Shop Webhooks
+-- Version: 000017
+-- Description: Shop webhook endpoints with subscribed event types, their signed deliveries and a log of every delivery attempt
+-- ===================================
+
+BEGIN;
+
+-- Shop Webhooks
+CREATE TABLE IF NOT EXISTS shop_webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    url VARCHAR(500) NOT NULL,
+    description VARCHAR(255),
+    secret TEXT NOT NULL,
+    event_types JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_webhooks_shop_id ON shop_webhooks(shop_id);
+
+-- Webhook Deliveries
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES shop_webhooks(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    last_response_status INTEGER,
+    last_error TEXT,
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMPTZ(6),
+    CONSTRAINT uq_webhook_deliveries_webhook_event UNIQUE (webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_created_at ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- Webhook Delivery Attempts
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id UUID NOT NULL REFERENCES webhook_deliveries(id) ON DELETE CASCADE,
+    response_status INTEGER,
+    response_body TEXT,
+    error TEXT,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts(delivery_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- ===================================
+-- Rollback: Remove Email Notifications
+-- Version: 000018
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS email_messages CASCADE;
+
+ALTER TABLE users DROP COLUMN IF EXISTS language;
+
+COMMIT;
@@ -0,0 +1,38 @@
+-- ===================================
+-- Migration: Add Email Notifications
+-- Version: 000018
+-- Description: Users' preferred language and the queue of rendered emails waiting to be sent
+-- ===================================
+
+BEGIN;
+
+-- Users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(5) NOT NULL DEFAULT 'th'
+    CONSTRAINT chk_users_language CHECK (language IN ('th', 'en'));
+
+-- Email Messages
+CREATE TABLE IF NOT EXISTS email_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    recipient VARCHAR(255) NOT NULL,
+    template VARCHAR(100) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    html_body TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    dedupe_key VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    claimed_by VARCHAR(100),
+    claimed_until TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMPTZ(6),
+    CONSTRAINT uq_email_messages_dedupe_key UNIQUE (dedupe_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_messages_pending ON email_messages(next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_email_messages_user_id ON email_messages(user_id);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- ===================================
+-- Rollback: Remove Notification Inbox
+-- Version: 000019
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS notification_preferences CASCADE;
+DROP TABLE IF EXISTS notifications CASCADE;
+
+COMMIT;
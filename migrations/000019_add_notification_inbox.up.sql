@@ -0,0 +1,36 @@
+-- ===================================
+-- Migration: Add Notification Inbox
+-- Version: 000019
+-- Description: In-app notifications per user and each user's channel preferences per event
+-- ===================================
+
+BEGIN;
+
+-- Notifications
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    dedupe_key VARCHAR(255) NOT NULL,
+    read_at TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_notifications_dedupe_key UNIQUE (dedupe_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Notification Preferences
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL,
+    email BOOLEAN NOT NULL DEFAULT TRUE,
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, event_type)
+);
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- ===================================
+-- Rollback: Remove User Tokens
+-- Version: 000020
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS user_tokens CASCADE;
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+
+COMMIT;
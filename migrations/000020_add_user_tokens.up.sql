@@ -0,0 +1,30 @@
+-- ===================================
+-- Migration: Add User Tokens
+-- Version: 000020
+-- Description: Email verification and password change timestamps on users, and the single-use tokens sent to verify an email or reset a password
+-- ===================================
+
+BEGIN;
+
+-- Users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ(6);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ(6);
+
+-- Accounts created before verification existed keep working when it is required
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- User Tokens
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL CHECK (purpose IN ('EMAIL_VERIFICATION', 'PASSWORD_RESET')),
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ(6) NOT NULL,
+    used_at TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_user_tokens_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_purpose ON user_tokens(user_id, purpose, created_at DESC);
+
+COMMIT;
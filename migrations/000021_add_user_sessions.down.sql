@@ -0,0 +1,11 @@
+-- ===================================
+-- Rollback: Remove User Sessions
+-- Version: 000021
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS refresh_tokens CASCADE;
+DROP TABLE IF EXISTS user_sessions CASCADE;
+
+COMMIT;
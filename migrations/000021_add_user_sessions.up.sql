@@ -0,0 +1,37 @@
+-- ===================================
+-- Migration: Add User Sessions
+-- Version: 000021
+-- Description: Signed-in sessions and their rotating refresh tokens, stored as hashes
+-- ===================================
+
+BEGIN;
+
+-- User Sessions
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent VARCHAR(500) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ(6) NOT NULL,
+    revoked_at TIMESTAMPTZ(6),
+    revoked_reason VARCHAR(30) CHECK (revoked_reason IN ('LOGOUT', 'LOGOUT_ALL', 'TOKEN_REUSE', 'PASSWORD_CHANGE'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_active ON user_sessions(user_id, last_used_at DESC) WHERE revoked_at IS NULL;
+
+-- Refresh Tokens
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES user_sessions(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ(6) NOT NULL,
+    used_at TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_refresh_tokens_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+
+COMMIT;
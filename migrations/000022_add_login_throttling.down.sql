@@ -0,0 +1,11 @@
+-- ===================================
+-- Rollback: Remove Login Throttling
+-- Version: 000022
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS failed_logins CASCADE;
+DROP TABLE IF EXISTS login_throttles CASCADE;
+
+COMMIT;
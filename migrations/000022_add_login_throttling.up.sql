@@ -0,0 +1,34 @@
+-- ===================================
+-- Migration: Add Login Throttling
+-- Version: 000022
+-- Description: Failed login counters per account and IP address, and the failed login audit trail
+-- ===================================
+
+BEGIN;
+
+-- Login Throttles (used when LOGIN_LIMITER_STORE=postgres)
+CREATE TABLE IF NOT EXISTS login_throttles (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure_at TIMESTAMPTZ(6) NOT NULL,
+    expires_at TIMESTAMPTZ(6) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_throttles_expires_at ON login_throttles(expires_at);
+
+-- Failed Logins
+CREATE TABLE IF NOT EXISTS failed_logins (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(500) NOT NULL DEFAULT '',
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('UNKNOWN_EMAIL', 'WRONG_PASSWORD', 'THROTTLED', 'LOCKED')),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_logins_created_at ON failed_logins(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_failed_logins_email ON failed_logins(email, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_failed_logins_ip_address ON failed_logins(ip_address, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,30 @@
+-- ===================================
+-- Rollback: Remove Two-Factor Authentication
+-- Version: 000023
+-- ===================================
+
+BEGIN;
+
+DELETE FROM failed_logins WHERE reason = 'WRONG_TWO_FACTOR_CODE';
+ALTER TABLE failed_logins DROP CONSTRAINT IF EXISTS failed_logins_reason_check;
+ALTER TABLE failed_logins ADD CONSTRAINT failed_logins_reason_check
+    CHECK (reason IN ('UNKNOWN_EMAIL', 'WRONG_PASSWORD', 'THROTTLED', 'LOCKED'));
+
+UPDATE user_sessions SET revoked_reason = 'LOGOUT_ALL' WHERE revoked_reason = 'TWO_FACTOR_RESET';
+ALTER TABLE user_sessions DROP CONSTRAINT IF EXISTS user_sessions_revoked_reason_check;
+ALTER TABLE user_sessions ADD CONSTRAINT user_sessions_revoked_reason_check
+    CHECK (revoked_reason IN ('LOGOUT', 'LOGOUT_ALL', 'TOKEN_REUSE', 'PASSWORD_CHANGE'));
+
+DELETE FROM user_tokens WHERE purpose = 'TWO_FACTOR_LOGIN';
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+    CHECK (purpose IN ('EMAIL_VERIFICATION', 'PASSWORD_RESET'));
+
+DROP TABLE IF EXISTS two_factor_recovery_codes CASCADE;
+
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_required;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_enabled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS two_factor_secret;
+
+COMMIT;
@@ -0,0 +1,41 @@
+-- ===================================
+-- Migration: Add Two-Factor Authentication
+-- Version: 000023
+-- Description: TOTP secrets and enforcement on users, hashed recovery codes, and the token that carries a login between its password and 2FA steps
+-- ===================================
+
+BEGIN;
+
+-- Users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled_at TIMESTAMPTZ(6);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_last_step BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Two-Factor Recovery Codes
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_two_factor_recovery_codes_user_id ON two_factor_recovery_codes(user_id);
+
+-- User Tokens
+ALTER TABLE user_tokens DROP CONSTRAINT IF EXISTS user_tokens_purpose_check;
+ALTER TABLE user_tokens ADD CONSTRAINT user_tokens_purpose_check
+    CHECK (purpose IN ('EMAIL_VERIFICATION', 'PASSWORD_RESET', 'TWO_FACTOR_LOGIN'));
+
+-- User Sessions
+ALTER TABLE user_sessions DROP CONSTRAINT IF EXISTS user_sessions_revoked_reason_check;
+ALTER TABLE user_sessions ADD CONSTRAINT user_sessions_revoked_reason_check
+    CHECK (revoked_reason IN ('LOGOUT', 'LOGOUT_ALL', 'TOKEN_REUSE', 'PASSWORD_CHANGE', 'TWO_FACTOR_RESET'));
+
+-- Failed Logins
+ALTER TABLE failed_logins DROP CONSTRAINT IF EXISTS failed_logins_reason_check;
+ALTER TABLE failed_logins ADD CONSTRAINT failed_logins_reason_check
+    CHECK (reason IN ('UNKNOWN_EMAIL', 'WRONG_PASSWORD', 'WRONG_TWO_FACTOR_CODE', 'THROTTLED', 'LOCKED'));
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- ===================================
+-- Rollback: Remove Social Login
+-- Version: 000024
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS oauth_states;
+DROP TABLE IF EXISTS user_identities;
+
+COMMIT;
@@ -0,0 +1,35 @@
+-- ===================================
+-- Migration: Add Social Login
+-- Version: 000024
+-- Description: External OAuth2/OIDC identities linked to users, and the single-use state of logins in progress at a provider
+-- ===================================
+
+BEGIN;
+
+-- User Identities
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(30) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- OAuth States
+CREATE TABLE IF NOT EXISTS oauth_states (
+    state_hash VARCHAR(64) PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at TIMESTAMPTZ(6) NOT NULL,
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_states_expires_at ON oauth_states(expires_at);
+
+COMMIT;
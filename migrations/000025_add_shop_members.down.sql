@@ -0,0 +1,11 @@
+-- ===================================
+-- Rollback: Remove Shop Members
+-- Version: 000025
+-- ===================================
+
+BEGIN;
+
+DROP TABLE IF EXISTS shop_invitations;
+DROP TABLE IF EXISTS shop_members;
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- ===================================
+-- Migration: Add Shop Members
+-- Version: 000025
+-- Description: Shop staff with roles, and email invitations to join a shop. Existing shop owners become the OWNER member of their shop
+-- ===================================
+
+BEGIN;
+
+-- Shop Members
+CREATE TABLE IF NOT EXISTS shop_members (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('OWNER', 'MANAGER', 'PACKER', 'SUPPORT')),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_members_shop_id ON shop_members(shop_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_shop_members_owner ON shop_members(shop_id) WHERE role = 'OWNER';
+
+INSERT INTO shop_members (shop_id, user_id, role, created_at, updated_at)
+SELECT id, user_id, 'OWNER', created_at, created_at
+FROM shops
+WHERE deleted_at IS NULL
+ON CONFLICT (user_id) DO NOTHING;
+
+-- Shop Invitations
+CREATE TABLE IF NOT EXISTS shop_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    shop_id UUID NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('MANAGER', 'PACKER', 'SUPPORT')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ(6) NOT NULL,
+    accepted_at TIMESTAMPTZ(6),
+    created_at TIMESTAMPTZ(6) NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_shop_invitations_shop_id ON shop_invitations(shop_id);
+
+COMMIT;
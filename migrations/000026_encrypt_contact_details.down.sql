@@ -0,0 +1,15 @@
+-- ===================================
+-- Rollback: Encrypt Contact Details
+-- Version: 000026
+-- Note: encrypted values do not fit the original VARCHAR sizes; decrypt before rolling back.
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE delivery_reattempts ALTER COLUMN shipping_phone TYPE VARCHAR(15);
+ALTER TABLE orders ALTER COLUMN shipping_phone TYPE VARCHAR(15);
+ALTER TABLE orders ALTER COLUMN shipping_name TYPE VARCHAR(255);
+ALTER TABLE addresses ALTER COLUMN phone_number TYPE VARCHAR(15);
+ALTER TABLE users ALTER COLUMN phone_number TYPE VARCHAR(15);
+
+COMMIT;
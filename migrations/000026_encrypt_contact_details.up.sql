@@ -0,0 +1,15 @@
+-- ===================================
+-- Migration: Encrypt Contact Details
+-- Version: 000026
+-- Description: Widen phone numbers and shipping names to hold AES-GCM ciphertext (run `make reencrypt` to encrypt existing rows)
+-- ===================================
+
+BEGIN;
+
+ALTER TABLE users ALTER COLUMN phone_number TYPE TEXT;
+ALTER TABLE addresses ALTER COLUMN phone_number TYPE TEXT;
+ALTER TABLE orders ALTER COLUMN shipping_name TYPE TEXT;
+ALTER TABLE orders ALTER COLUMN shipping_phone TYPE TEXT;
+ALTER TABLE delivery_reattempts ALTER COLUMN shipping_phone TYPE TEXT;
+
+COMMIT;
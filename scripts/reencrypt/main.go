@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/joho/godotenv"
+
+	"ecommerce-go-api/internal/fieldcrypt"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+	colorBlue   = "\033[34m"
+)
+
+// target is an encrypted column. Add new entries here when more fields are encrypted.
+type target struct {
+	Table  string
+	Column string
+}
+
+var targets = []target{
+	{Table: "refunds", Column: "bank_account"},
+	{Table: "shop_webhooks", Column: "secret"},
+	{Table: "users", Column: "two_factor_secret"},
+	{Table: "users", Column: "phone_number"},
+	{Table: "addresses", Column: "name"},
+	{Table: "addresses", Column: "line1"},
+	{Table: "addresses", Column: "line2"},
+	{Table: "addresses", Column: "phone_number"},
+	{Table: "orders", Column: "shipping_name"},
+	{Table: "orders", Column: "shipping_phone"},
+	{Table: "orders", Column: "shipping_line1"},
+	{Table: "orders", Column: "shipping_line2"},
+	{Table: "delivery_reattempts", Column: "shipping_name"},
+	{Table: "delivery_reattempts", Column: "shipping_phone"},
+	{Table: "delivery_reattempts", Column: "shipping_line1"},
+	{Table: "delivery_reattempts", Column: "shipping_line2"},
+}
+
+// Re-encrypts every encrypted column with the active key from FIELD_ENCRYPTION_ACTIVE_KEY.
+// Legacy plaintext values are encrypted, and values under retired keys are rotated.
+// Once it reports nothing left to rotate, old keys can be removed from FIELD_ENCRYPTION_KEYS.
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Only report how many values need re-encryption")
+	batchSize := flag.Int("batch", 500, "Rows to process per batch")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found")
+	}
+
+	keyRing, err := fieldcrypt.NewKeyRing(os.Getenv("FIELD_ENCRYPTION_KEYS"), os.Getenv("FIELD_ENCRYPTION_ACTIVE_KEY"))
+	if err != nil {
+		log.Fatalf("%sError loading key ring: %v%s\n", colorRed, err, colorReset)
+	}
+
+	dbURL := fmt.Sprintf(
+		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_DB"),
+	)
+
+	db, err := sql.Open("pgx", dbURL)
+	if err != nil {
+		log.Fatalf("%sError connecting to database: %v%s\n", colorRed, err, colorReset)
+	}
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("%sError pinging database: %v%s\n", colorRed, err, colorReset)
+	}
+
+	fmt.Printf("%s→ Active key: %s%s\n", colorBlue, keyRing.ActiveKeyID(), colorReset)
+
+	for _, t := range targets {
+		rotated, err := reencryptColumn(context.Background(), db, keyRing, t, *batchSize, *dryRun)
+		if err != nil {
+			log.Fatalf("%sError re-encrypting %s.%s: %v%s\n", colorRed, t.Table, t.Column, err, colorReset)
+		}
+
+		if *dryRun {
+			fmt.Printf("%s%s.%s: %d value(s) need re-encryption%s\n", colorYellow, t.Table, t.Column, rotated, colorReset)
+		} else {
+			fmt.Printf("%s✓ %s.%s: %d value(s) re-encrypted%s\n", colorGreen, t.Table, t.Column, rotated, colorReset)
+		}
+	}
+}
+
+func reencryptColumn(ctx context.Context, db *sql.DB, keyRing *fieldcrypt.KeyRing, t target, batchSize int, dryRun bool) (int, error) {
+	selectQuery := fmt.Sprintf(
+		`SELECT id, %[2]s FROM %[1]s WHERE %[2]s IS NOT NULL AND %[2]s <> '' AND id::text > $1 ORDER BY id::text LIMIT $2`,
+		t.Table, t.Column,
+	)
+	updateQuery := fmt.Sprintf(`UPDATE %[1]s SET %[2]s = $1 WHERE id::text = $2 AND %[2]s = $3`, t.Table, t.Column)
+
+	rotated := 0
+	lastID := ""
+
+	for {
+		rows, err := db.QueryContext(ctx, selectQuery, lastID, batchSize)
+		if err != nil {
+			return rotated, err
+		}
+
+		type row struct {
+			ID    string
+			Value string
+		}
+		var batch []row
+		for rows.Next() {
+			var r row
+			if err := rows.Scan(&r.ID, &r.Value); err != nil {
+				rows.Close()
+				return rotated, err
+			}
+			batch = append(batch, r)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return rotated, err
+		}
+
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+
+		for _, r := range batch {
+			lastID = r.ID
+
+			if !keyRing.NeedsRotation(r.Value) {
+				continue
+			}
+
+			rotated++
+			if dryRun {
+				continue
+			}
+
+			plaintext, err := keyRing.Decrypt(r.Value)
+			if err != nil {
+				return rotated, fmt.Errorf("id=%s: %w", r.ID, err)
+			}
+
+			ciphertext, err := keyRing.Encrypt(plaintext)
+			if err != nil {
+				return rotated, fmt.Errorf("id=%s: %w", r.ID, err)
+			}
+
+			// Compare-and-set on the old value so a concurrent update is never overwritten.
+			if _, err := db.ExecContext(ctx, updateQuery, ciphertext, r.ID, r.Value); err != nil {
+				return rotated, fmt.Errorf("id=%s: %w", r.ID, err)
+			}
+		}
+	}
+}